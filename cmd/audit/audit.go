@@ -0,0 +1,112 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/ydcloud-dy/opshub/cmd/root"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	dataPkg "github.com/ydcloud-dy/opshub/internal/data"
+	auditserver "github.com/ydcloud-dy/opshub/internal/server/audit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	verifyChain string
+	verifyJSON  bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "audit",
+	Short: "审计管理",
+	Long:  `管理 OpsHub 审计记录`,
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "校验审计链完整性",
+	Long:  `逐条校验审计记录的哈希链和签名检查点，报告缺失、篡改和未入链的记录，发现问题时以状态码 1 退出`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := conf.Load(root.GetConfigFile())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "加载配置失败: %v\n", err)
+			os.Exit(2)
+		}
+		data, err := dataPkg.NewData(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "初始化数据层失败: %v\n", err)
+			os.Exit(2)
+		}
+		db := data.DB().Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+		uc := auditserver.NewAuditChainUseCase(db, cfg.AuditSigningKey())
+		reports, err := uc.Verify(context.Background(), verifyChain)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "校验失败: %v\n", err)
+			os.Exit(2)
+		}
+
+		valid := true
+		for _, r := range reports {
+			valid = valid && r.Valid
+		}
+
+		if verifyJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(map[string]interface{}{"valid": valid, "reports": reports})
+		} else {
+			fmt.Println("========================================")
+			fmt.Println("           审计链完整性校验")
+			fmt.Println("========================================")
+			for _, r := range reports {
+				status := "✓ 通过"
+				if !r.Valid {
+					status = "✗ 异常"
+				}
+				fmt.Printf("%-22s %-24s 记录: %-8d 检查点: %-4d %s\n", r.Chain, r.Table, r.Entries, r.Checkpoints, status)
+				for _, issue := range r.Issues {
+					fmt.Printf("    [%s] 序号=%d 记录ID=%d %s\n", issue.Type, issue.Seq, issue.RecordID, issue.Detail)
+				}
+				if r.Truncated {
+					fmt.Println("    问题过多，仅显示部分")
+				}
+			}
+			fmt.Println("========================================")
+		}
+
+		if !valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	root.Cmd.AddCommand(Cmd)
+	Cmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVar(&verifyChain, "chain", "", "只校验指定的审计链，如 operation_log、login_log")
+	verifyCmd.Flags().BoolVar(&verifyJSON, "json", false, "以 JSON 格式输出校验结果")
+}
@@ -38,6 +38,7 @@ import (
 	rbacmodel "github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	rbacservice "github.com/ydcloud-dy/opshub/internal/service/rbac"
 	auditmodel "github.com/ydcloud-dy/opshub/internal/biz/audit"
+	assetmodel "github.com/ydcloud-dy/opshub/internal/biz/asset"
 	"github.com/ydcloud-dy/opshub/plugins/kubernetes/data/models"
 	k8smodel "github.com/ydcloud-dy/opshub/plugins/kubernetes/model"
 	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
@@ -154,7 +155,7 @@ func runServer() (*conf.Config, error) {
 	}
 
 	// 初始化HTTP服务器
-	httpServer := server.NewHTTPServer(cfg, svc, data.DB())
+	httpServer := server.NewHTTPServer(cfg, svc, data.DB(), redis.Get())
 	globalHTTPServer = httpServer // 保存到全局变量
 
 	// 启动服务器
@@ -183,6 +184,15 @@ func autoMigrate(db *gorm.DB) error {
 		&rbacmodel.SysPosition{},
 		&rbacmodel.SysUserPosition{},
 		&rbacmodel.SysRoleAssetPermission{},
+		&rbacmodel.SysAssetGrant{},
+		&rbacmodel.SysAccessRequest{},
+		&rbacmodel.SysAccessRequestEvent{},
+		&rbacmodel.SysAccessApprover{},
+		&rbacmodel.SysUserIdentity{},
+		&rbacmodel.SysUserMFA{},
+		&rbacmodel.SysUserRecoveryCode{},
+		&rbacmodel.SysAPIToken{},
+		&rbacmodel.SysUserPasswordHistory{},
 		// Kubernetes 集群相关表
 		&models.Cluster{},
 		&k8smodel.UserKubeConfig{},
@@ -191,10 +201,31 @@ func autoMigrate(db *gorm.DB) error {
 		&auditmodel.SysOperationLog{},
 		&auditmodel.SysLoginLog{},
 		&auditmodel.SysDataLog{},
+		&auditmodel.SysAuditChain{},
+		&auditmodel.SysAuditChainEntry{},
+		&auditmodel.SysAuditCheckpoint{},
+		&auditmodel.SysAuditRetentionPolicy{},
+		&auditmodel.SysAuditArchive{},
+		&auditmodel.SysAuditArchiveRecord{},
+		&auditmodel.SysSecurityEvent{},
+		// 资产相关表
+		&assetmodel.TerminalSession{},
+		&assetmodel.TerminalSessionPolicy{},
+		&assetmodel.PortForwardSession{},
+		&assetmodel.DatabaseInstance{},
+		&assetmodel.DatabaseQueryLog{},
 	); err != nil {
 		return err
 	}
 
+	// 主机表由 init.sql 创建，这里补充创建人列，用于按部门划分数据权限
+	if db.Migrator().HasTable(&assetmodel.Host{}) && !db.Migrator().HasColumn(&assetmodel.Host{}, "CreatedBy") {
+		if err := db.Migrator().AddColumn(&assetmodel.Host{}, "CreatedBy"); err != nil {
+			appLogger.Warn("添加主机创建人列失败", zap.Error(err))
+		}
+	}
+	backfillHostCreator(db)
+
 	// 为用户表创建虚拟列和唯一索引
 	// 问题：MySQL 唯一索引中多个 NULL 值被认为是不同的，无法正确约束
 	// 解决：使用虚拟列 is_deleted (0=未删除, 1=已删除) 来创建唯一索引
@@ -229,6 +260,39 @@ func autoMigrate(db *gorm.DB) error {
 	return nil
 }
 
+// backfillHostCreator 没有创建人的主机（升级前创建或由后台同步写入）归属到超级管理员，
+// 避免按部门或本人划分数据权限后这些主机对所有非管理员角色都不可见
+func backfillHostCreator(db *gorm.DB) {
+	if !db.Migrator().HasColumn(&assetmodel.Host{}, "CreatedBy") {
+		return
+	}
+	var adminID uint
+	if err := db.Table("sys_user AS u").
+		Select("u.id").
+		Joins("JOIN sys_user_role AS ur ON ur.user_id = u.id").
+		Joins("JOIN sys_role AS r ON r.id = ur.role_id").
+		Where("r.code = ? AND u.deleted_at IS NULL", rbacmodel.RoleCodeAdmin).
+		Order("u.id ASC").
+		Limit(1).
+		Scan(&adminID).Error; err != nil {
+		appLogger.Warn("查询超级管理员失败，跳过主机创建人回填", zap.Error(err))
+		return
+	}
+	if adminID == 0 {
+		return
+	}
+	result := db.Model(&assetmodel.Host{}).
+		Where("created_by = 0 OR created_by IS NULL").
+		Update("created_by", adminID)
+	if result.Error != nil {
+		appLogger.Warn("回填主机创建人失败", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		appLogger.Info("已回填主机创建人", zap.Uint("createdBy", adminID), zap.Int64("hosts", result.RowsAffected))
+	}
+}
+
 // initDefaultData 初始化默认数据
 func initDefaultData(db *gorm.DB) error {
 	// 检查是否已有管理员用户
@@ -0,0 +1,149 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 数据库查询状态
+const (
+	DatabaseQueryStatusSuccess = "success" // 执行成功
+	DatabaseQueryStatusFailed  = "failed"  // 执行失败
+	DatabaseQueryStatusBlocked = "blocked" // 被规则拦截
+)
+
+// DatabaseInstance 数据库资产模型
+type DatabaseInstance struct {
+	gorm.Model
+	Name         string      `gorm:"type:varchar(100);not null;comment:实例名称" json:"name"`
+	Type         string      `gorm:"type:varchar(20);not null;comment:数据库类型 mysql/postgresql/redis" json:"type"`
+	GroupID      uint        `gorm:"column:group_id;index;comment:分组ID" json:"groupId"`
+	Host         string      `gorm:"type:varchar(255);not null;comment:连接地址" json:"host"`
+	Port         int         `gorm:"type:int;not null;comment:端口" json:"port"`
+	CredentialID uint        `gorm:"column:credential_id;comment:凭证ID" json:"credentialId"`
+	Credential   *Credential `gorm:"-" json:"credential,omitempty"`
+	DatabaseName string      `gorm:"type:varchar(100);comment:默认库名(Redis为DB序号)" json:"databaseName"`
+	MaxRows      int         `gorm:"type:int;default:500;comment:查询最大返回行数" json:"maxRows"`
+	Tags         string      `gorm:"type:varchar(500);comment:标签(逗号分隔)" json:"tags"`
+	Description  string      `gorm:"type:varchar(500);comment:备注" json:"description"`
+	Status       int         `gorm:"type:tinyint;default:-1;comment:状态 1:在线 0:离线 -1:未知" json:"status"`
+	LastSeen     *time.Time  `gorm:"column:last_seen;comment:最后连接时间" json:"lastSeen,omitempty"`
+}
+
+// TableName 表名
+func (DatabaseInstance) TableName() string {
+	return "asset_databases"
+}
+
+// DatabaseRequest 数据库资产请求
+type DatabaseRequest struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name" binding:"required,min=2,max=100"`
+	Type         string `json:"type" binding:"required,oneof=mysql postgresql redis"`
+	GroupID      uint   `json:"groupId"`
+	Host         string `json:"host" binding:"required"`
+	Port         int    `json:"port" binding:"required,min=1,max=65535"`
+	CredentialID uint   `json:"credentialId"`
+	DatabaseName string `json:"databaseName"`
+	MaxRows      int    `json:"maxRows" binding:"omitempty,min=1,max=10000"`
+	Tags         string `json:"tags"`
+	Description  string `json:"description"`
+}
+
+// DatabaseInfoVO 数据库资产信息VO
+type DatabaseInfoVO struct {
+	ID           uint          `json:"id"`
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	TypeText     string        `json:"typeText"`
+	GroupID      uint          `json:"groupId"`
+	GroupName    string        `json:"groupName"`
+	Host         string        `json:"host"`
+	Port         int           `json:"port"`
+	CredentialID uint          `json:"credentialId"`
+	Credential   *CredentialVO `json:"credential,omitempty"`
+	DatabaseName string        `json:"databaseName"`
+	MaxRows      int           `json:"maxRows"`
+	Tags         []string      `json:"tags"`
+	Description  string        `json:"description"`
+	Status       int           `json:"status"`
+	StatusText   string        `json:"statusText"`
+	LastSeen     string        `json:"lastSeen,omitempty"`
+	CreateTime   string        `json:"createTime"`
+	UpdateTime   string        `json:"updateTime"`
+}
+
+// DatabaseQueryLog 数据库控制台语句审计记录
+type DatabaseQueryLog struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `gorm:"index" json:"createdAt"`
+	DatabaseID    uint      `gorm:"not null;index;comment:数据库资产ID" json:"databaseId"`
+	InstanceName  string    `gorm:"type:varchar(100);comment:实例名称" json:"instanceName"`
+	DatabaseType  string    `gorm:"type:varchar(20);comment:数据库类型" json:"databaseType"`
+	DatabaseName  string    `gorm:"type:varchar(100);comment:执行时的库名" json:"databaseName"`
+	UserID        uint      `gorm:"not null;index;comment:操作用户ID" json:"userId"`
+	Username      string    `gorm:"type:varchar(100);comment:用户名" json:"username"`
+	ClientIP      string    `gorm:"type:varchar(50);comment:客户端IP" json:"clientIp"`
+	Statement     string    `gorm:"type:text;comment:执行语句" json:"statement"`
+	StatementType string    `gorm:"type:varchar(30);comment:语句类型" json:"statementType"`
+	ReadOnly      bool      `gorm:"comment:是否只读语句" json:"readOnly"`
+	Status        string    `gorm:"type:varchar(20);index;comment:状态 success/failed/blocked" json:"status"`
+	Duration      int64     `gorm:"comment:执行耗时(毫秒)" json:"duration"`
+	RowCount      int64     `gorm:"comment:返回或影响行数" json:"rowCount"`
+	Truncated     bool      `gorm:"comment:结果是否被截断" json:"truncated"`
+	ErrorMsg      string    `gorm:"type:text;comment:错误或拦截原因" json:"errorMsg"`
+}
+
+// TableName 表名
+func (DatabaseQueryLog) TableName() string {
+	return "asset_database_query_logs"
+}
+
+// DatabaseQueryRequest 控制台执行请求
+type DatabaseQueryRequest struct {
+	Database  string `json:"database"` // 为空时使用实例默认库
+	Statement string `json:"statement" binding:"required"`
+	Limit     int    `json:"limit" binding:"omitempty,min=1"`
+}
+
+// DatabaseQueryResult 控制台执行结果
+type DatabaseQueryResult struct {
+	LogID         uint            `json:"logId"`
+	StatementType string          `json:"statementType"`
+	ReadOnly      bool            `json:"readOnly"`
+	Columns       []string        `json:"columns"`
+	Rows          [][]interface{} `json:"rows"`
+	RowsAffected  int64           `json:"rowsAffected"`
+	Truncated     bool            `json:"truncated"`
+	Duration      int64           `json:"duration"` // 毫秒
+}
+
+// DatabaseQueryLogListRequest 语句审计列表请求
+type DatabaseQueryLogListRequest struct {
+	Page       int    `form:"page"`
+	PageSize   int    `form:"pageSize"`
+	DatabaseID uint   `form:"databaseId"`
+	Username   string `form:"username"`
+	Status     string `form:"status"`
+	Keyword    string `form:"keyword"`
+}
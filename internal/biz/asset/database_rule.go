@@ -0,0 +1,149 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ydcloud-dy/opshub/pkg/dbclient"
+)
+
+// DatabaseRule 危险语句拦截规则
+type DatabaseRule struct {
+	Name    string
+	Types   []string       // 适用的数据库类型
+	Pattern *regexp.Regexp // 匹配规范化后的语句（大写、去注释、去字符串字面量）
+	Message string
+}
+
+// StatementCheck 语句检查结果
+type StatementCheck struct {
+	Type     string // 语句类型（首个关键字）
+	ReadOnly bool   // 是否只读
+	Blocked  bool   // 是否被拦截
+	Reason   string // 拦截原因
+}
+
+var sqlTypes = []string{dbclient.TypeMySQL, dbclient.TypePostgreSQL}
+
+// defaultDatabaseRules 内置危险语句规则
+var defaultDatabaseRules = []DatabaseRule{
+	{Name: "drop-database", Types: sqlTypes, Pattern: regexp.MustCompile(`^DROP\s+(DATABASE|SCHEMA)\b`), Message: "禁止删除数据库"},
+	{Name: "drop-table", Types: sqlTypes, Pattern: regexp.MustCompile(`^DROP\s+TABLE\b`), Message: "禁止删除数据表"},
+	{Name: "truncate", Types: sqlTypes, Pattern: regexp.MustCompile(`^TRUNCATE\b`), Message: "禁止清空数据表"},
+	{Name: "delete-without-where", Types: sqlTypes, Pattern: regexp.MustCompile(`^DELETE\b`), Message: "DELETE 语句必须包含 WHERE 条件"},
+	{Name: "update-without-where", Types: sqlTypes, Pattern: regexp.MustCompile(`^UPDATE\b`), Message: "UPDATE 语句必须包含 WHERE 条件"},
+	{Name: "account", Types: sqlTypes, Pattern: regexp.MustCompile(`^(GRANT|REVOKE)\b|^(CREATE|ALTER|DROP|RENAME)\s+(USER|ROLE)\b|^SET\s+PASSWORD\b`), Message: "禁止在控制台管理账号和授权"},
+	{Name: "server-admin", Types: sqlTypes, Pattern: regexp.MustCompile(`^(SHUTDOWN|KILL)\b|^SET\s+(GLOBAL|PERSIST)\b|^ALTER\s+SYSTEM\b`), Message: "禁止执行服务器管理语句"},
+	{Name: "file-access", Types: sqlTypes, Pattern: regexp.MustCompile(`\bINTO\s+(OUTFILE|DUMPFILE)\b|^LOAD\s+DATA\b|\bLOAD_FILE\s*\(|^COPY\b|\bPG_READ_(BINARY_)?FILE\s*\(`), Message: "禁止读写数据库服务器文件"},
+	{Name: "redis-flush", Types: []string{dbclient.TypeRedis}, Pattern: regexp.MustCompile(`^(FLUSHALL|FLUSHDB|SWAPDB)\b`), Message: "禁止清空Redis数据"},
+	{Name: "redis-admin", Types: []string{dbclient.TypeRedis}, Pattern: regexp.MustCompile(`^(SHUTDOWN|CONFIG|DEBUG|MODULE|ACL|CLUSTER|REPLICAOF|SLAVEOF|MIGRATE|SYNC|PSYNC|SAVE|BGSAVE|BGREWRITEAOF|CLIENT|SCRIPT|FUNCTION|FAILOVER)\b`), Message: "禁止执行Redis管理命令"},
+	{Name: "redis-blocking", Types: []string{dbclient.TypeRedis}, Pattern: regexp.MustCompile(`^(KEYS|MONITOR|SUBSCRIBE|PSUBSCRIBE|SSUBSCRIBE|BLPOP|BRPOP|BLMOVE|BRPOPLPUSH|BZPOPMIN|BZPOPMAX|BLMPOP|BZMPOP|WAIT|XREAD|XREADGROUP)\b`), Message: "禁止执行阻塞或全量遍历命令，请使用 SCAN"},
+}
+
+// sqlReadKeywords 只读SQL语句的首关键字
+var sqlReadKeywords = map[string]bool{
+	"SELECT": true, "SHOW": true, "DESC": true, "DESCRIBE": true, "EXPLAIN": true, "WITH": true, "VALUES": true, "TABLE": true,
+}
+
+// sqlWriteInRead 只读语句中出现即视为写入的关键字
+var sqlWriteInRead = regexp.MustCompile(`\b(INSERT|UPDATE|DELETE|MERGE)\b|\bINTO\b|\bFOR\s+(UPDATE|SHARE)\b`)
+
+// redisReadCommands Redis只读命令
+var redisReadCommands = map[string]bool{
+	"GET": true, "MGET": true, "GETRANGE": true, "STRLEN": true, "EXISTS": true, "TYPE": true, "TTL": true, "PTTL": true,
+	"HGET": true, "HMGET": true, "HGETALL": true, "HKEYS": true, "HVALS": true, "HLEN": true, "HEXISTS": true, "HSTRLEN": true, "HSCAN": true,
+	"LRANGE": true, "LLEN": true, "LINDEX": true, "LPOS": true,
+	"SMEMBERS": true, "SCARD": true, "SISMEMBER": true, "SMISMEMBER": true, "SRANDMEMBER": true, "SSCAN": true, "SINTER": true, "SUNION": true, "SDIFF": true,
+	"ZRANGE": true, "ZRANGEBYSCORE": true, "ZREVRANGE": true, "ZREVRANGEBYSCORE": true, "ZCARD": true, "ZCOUNT": true, "ZSCORE": true, "ZMSCORE": true, "ZRANK": true, "ZREVRANK": true, "ZSCAN": true,
+	"XRANGE": true, "XREVRANGE": true, "XLEN": true, "XINFO": true, "PFCOUNT": true, "GETBIT": true, "BITCOUNT": true, "BITPOS": true,
+	"SCAN": true, "DBSIZE": true, "INFO": true, "PING": true, "ECHO": true, "TIME": true, "OBJECT": true, "MEMORY": true, "RANDOMKEY": true, "SLOWLOG": true,
+}
+
+var (
+	sqlLineComment  = regexp.MustCompile(`(--|#)[^\n]*`)
+	sqlBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	sqlLiteral      = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.)*"|` + "`[^`]*`")
+	sqlSpaces       = regexp.MustCompile(`\s+`)
+	sqlWhere        = regexp.MustCompile(`\bWHERE\b`)
+)
+
+// normalizeSQL 去除注释和字符串字面量并转为大写，仅用于规则匹配
+func normalizeSQL(statement string) string {
+	s := sqlBlockComment.ReplaceAllString(statement, " ")
+	s = sqlLiteral.ReplaceAllString(s, "''")
+	s = sqlLineComment.ReplaceAllString(s, " ")
+	s = sqlSpaces.ReplaceAllString(s, " ")
+	s = strings.TrimSpace(strings.ToUpper(s))
+	return strings.TrimSpace(strings.TrimRight(s, "; "))
+}
+
+// CheckStatement 检查语句类型并匹配危险语句规则
+func CheckStatement(dbType, statement string) *StatementCheck {
+	check := &StatementCheck{}
+
+	var normalized string
+	if dbType == dbclient.TypeRedis {
+		args, err := dbclient.SplitCommand(statement)
+		if err != nil || len(args) == 0 {
+			check.Blocked, check.Reason = true, "命令格式错误"
+			return check
+		}
+		normalized = strings.ToUpper(strings.Join(args, " "))
+		check.Type = strings.ToUpper(args[0])
+		check.ReadOnly = redisReadCommands[check.Type]
+	} else {
+		normalized = normalizeSQL(statement)
+		if normalized == "" {
+			check.Blocked, check.Reason = true, "语句不能为空"
+			return check
+		}
+		check.Type = strings.SplitN(normalized, " ", 2)[0]
+		if strings.Contains(normalized, ";") {
+			check.Blocked, check.Reason = true, "不允许一次执行多条语句"
+			return check
+		}
+		check.ReadOnly = sqlReadKeywords[check.Type] && !sqlWriteInRead.MatchString(normalized)
+	}
+
+	for _, rule := range defaultDatabaseRules {
+		if !ruleApplies(rule, dbType) || !rule.Pattern.MatchString(normalized) {
+			continue
+		}
+		// UPDATE/DELETE 仅在缺少 WHERE 时拦截
+		if (rule.Name == "update-without-where" || rule.Name == "delete-without-where") && sqlWhere.MatchString(normalized) {
+			continue
+		}
+		check.Blocked, check.Reason = true, rule.Message
+		return check
+	}
+
+	return check
+}
+
+func ruleApplies(rule DatabaseRule, dbType string) bool {
+	for _, t := range rule.Types {
+		if t == dbType {
+			return true
+		}
+	}
+	return false
+}
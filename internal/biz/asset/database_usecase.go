@@ -0,0 +1,341 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/pkg/dbclient"
+)
+
+const (
+	defaultDatabaseMaxRows = 500              // 默认最大返回行数
+	databaseQueryTimeout   = 60 * time.Second // 单条语句执行超时
+)
+
+// ErrStatementBlocked 语句被拦截
+var ErrStatementBlocked = errors.New("语句已被拦截")
+
+// DatabaseUseCase 数据库资产用例
+type DatabaseUseCase struct {
+	repo           DatabaseRepo
+	credentialRepo CredentialRepo
+	groupRepo      AssetGroupRepo
+}
+
+// NewDatabaseUseCase 创建数据库资产用例
+func NewDatabaseUseCase(repo DatabaseRepo, credentialRepo CredentialRepo, groupRepo AssetGroupRepo) *DatabaseUseCase {
+	return &DatabaseUseCase{
+		repo:           repo,
+		credentialRepo: credentialRepo,
+		groupRepo:      groupRepo,
+	}
+}
+
+// Create 创建数据库资产
+func (uc *DatabaseUseCase) Create(ctx context.Context, req *DatabaseRequest) (*DatabaseInstance, error) {
+	instance := &DatabaseInstance{Status: -1}
+	uc.applyRequest(instance, req)
+	if err := uc.repo.Create(ctx, instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// Update 更新数据库资产
+func (uc *DatabaseUseCase) Update(ctx context.Context, req *DatabaseRequest) error {
+	instance, err := uc.repo.GetByID(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("数据库不存在")
+	}
+	uc.applyRequest(instance, req)
+	return uc.repo.Update(ctx, instance)
+}
+
+func (uc *DatabaseUseCase) applyRequest(instance *DatabaseInstance, req *DatabaseRequest) {
+	instance.Name = req.Name
+	instance.Type = req.Type
+	instance.GroupID = req.GroupID
+	instance.Host = req.Host
+	instance.Port = req.Port
+	instance.CredentialID = req.CredentialID
+	instance.DatabaseName = req.DatabaseName
+	instance.MaxRows = req.MaxRows
+	if instance.MaxRows <= 0 {
+		instance.MaxRows = defaultDatabaseMaxRows
+	}
+	instance.Tags = req.Tags
+	instance.Description = req.Description
+}
+
+// Delete 删除数据库资产
+func (uc *DatabaseUseCase) Delete(ctx context.Context, id uint) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+// GetByID 获取数据库资产详情
+func (uc *DatabaseUseCase) GetByID(ctx context.Context, id uint) (*DatabaseInfoVO, error) {
+	instance, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toInfoVO(ctx, instance), nil
+}
+
+// List 分页查询数据库资产
+func (uc *DatabaseUseCase) List(ctx context.Context, page, pageSize int, keyword, dbType string, groupID *uint, accessibleIDs []uint) ([]*DatabaseInfoVO, int64, error) {
+	var groupIDs []uint
+	if groupID != nil && *groupID > 0 {
+		groupIDs = append(groupIDs, *groupID)
+		descendantIDs, err := uc.groupRepo.GetDescendantIDs(ctx, *groupID)
+		if err == nil {
+			groupIDs = append(groupIDs, descendantIDs...)
+		}
+	}
+
+	instances, total, err := uc.repo.List(ctx, page, pageSize, keyword, dbType, groupIDs, accessibleIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	vos := make([]*DatabaseInfoVO, 0, len(instances))
+	for _, instance := range instances {
+		vos = append(vos, uc.toInfoVO(ctx, instance))
+	}
+	return vos, total, nil
+}
+
+func (uc *DatabaseUseCase) toInfoVO(ctx context.Context, instance *DatabaseInstance) *DatabaseInfoVO {
+	statusText := "未知"
+	if instance.Status == 1 {
+		statusText = "在线"
+	} else if instance.Status == 0 {
+		statusText = "离线"
+	}
+
+	typeText := instance.Type
+	switch instance.Type {
+	case dbclient.TypeMySQL:
+		typeText = "MySQL"
+	case dbclient.TypePostgreSQL:
+		typeText = "PostgreSQL"
+	case dbclient.TypeRedis:
+		typeText = "Redis"
+	}
+
+	var tags []string
+	if instance.Tags != "" {
+		tags = strings.Split(instance.Tags, ",")
+	}
+
+	var lastSeen string
+	if instance.LastSeen != nil {
+		lastSeen = instance.LastSeen.Format("2006-01-02 15:04:05")
+	}
+
+	vo := &DatabaseInfoVO{
+		ID:           instance.ID,
+		Name:         instance.Name,
+		Type:         instance.Type,
+		TypeText:     typeText,
+		GroupID:      instance.GroupID,
+		Host:         instance.Host,
+		Port:         instance.Port,
+		CredentialID: instance.CredentialID,
+		DatabaseName: instance.DatabaseName,
+		MaxRows:      instance.MaxRows,
+		Tags:         tags,
+		Description:  instance.Description,
+		Status:       instance.Status,
+		StatusText:   statusText,
+		LastSeen:     lastSeen,
+		CreateTime:   instance.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdateTime:   instance.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if instance.GroupID > 0 {
+		if group, err := uc.groupRepo.GetByID(ctx, instance.GroupID); err == nil && group != nil {
+			vo.GroupName = group.Name
+		}
+	}
+	if instance.CredentialID > 0 {
+		if credential, err := uc.credentialRepo.GetByID(ctx, instance.CredentialID); err == nil && credential != nil {
+			vo.Credential = &CredentialVO{
+				ID:       credential.ID,
+				Name:     credential.Name,
+				Type:     credential.Type,
+				Username: credential.Username,
+			}
+		}
+	}
+
+	return vo
+}
+
+// openClient 使用解密后的凭证创建数据库客户端
+func (uc *DatabaseUseCase) openClient(ctx context.Context, instance *DatabaseInstance, database string) (dbclient.Client, error) {
+	cfg := dbclient.Config{
+		Type:     instance.Type,
+		Host:     instance.Host,
+		Port:     instance.Port,
+		Database: database,
+	}
+
+	if instance.CredentialID > 0 {
+		credential, err := uc.credentialRepo.GetByIDDecrypted(ctx, instance.CredentialID)
+		if err != nil {
+			return nil, fmt.Errorf("获取凭证失败: %w", err)
+		}
+		cfg.Username = credential.Username
+		cfg.Password = credential.Password
+	}
+
+	return dbclient.NewClient(cfg)
+}
+
+// TestConnection 测试数据库连接并更新状态
+func (uc *DatabaseUseCase) TestConnection(ctx context.Context, id uint) error {
+	instance, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("数据库不存在")
+	}
+
+	client, err := uc.openClient(ctx, instance, instance.DatabaseName)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx); err != nil {
+		uc.repo.UpdateStatus(ctx, id, 0)
+		return fmt.Errorf("连接测试失败: %w", err)
+	}
+
+	uc.repo.UpdateStatus(ctx, id, 1)
+	return nil
+}
+
+// Execute 在控制台执行语句并记录审计日志
+// canWrite 为 false 时只允许执行只读语句
+func (uc *DatabaseUseCase) Execute(ctx context.Context, id, userID uint, username, clientIP string, canWrite bool, req *DatabaseQueryRequest) (*DatabaseQueryResult, error) {
+	instance, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("数据库不存在")
+	}
+
+	database := req.Database
+	if database == "" {
+		database = instance.DatabaseName
+	}
+
+	check := CheckStatement(instance.Type, req.Statement)
+	log := &DatabaseQueryLog{
+		DatabaseID:    instance.ID,
+		InstanceName:  instance.Name,
+		DatabaseType:  instance.Type,
+		DatabaseName:  database,
+		UserID:        userID,
+		Username:      username,
+		ClientIP:      clientIP,
+		Statement:     req.Statement,
+		StatementType: check.Type,
+		ReadOnly:      check.ReadOnly,
+	}
+
+	if !check.Blocked && !check.ReadOnly && !canWrite {
+		check.Blocked, check.Reason = true, "当前角色对该数据库只有只读权限"
+	}
+	if check.Blocked {
+		log.Status = DatabaseQueryStatusBlocked
+		log.ErrorMsg = check.Reason
+		uc.repo.CreateQueryLog(ctx, log)
+		return nil, fmt.Errorf("%w: %s", ErrStatementBlocked, check.Reason)
+	}
+
+	// 返回行数不超过实例配置的上限
+	maxRows := instance.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultDatabaseMaxRows
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxRows {
+		limit = maxRows
+	}
+
+	start := time.Now()
+	result, err := uc.run(ctx, instance, database, check.ReadOnly, req.Statement, limit)
+	log.Duration = time.Since(start).Milliseconds()
+
+	if err != nil {
+		log.Status = DatabaseQueryStatusFailed
+		log.ErrorMsg = err.Error()
+		uc.repo.CreateQueryLog(ctx, log)
+		return nil, err
+	}
+
+	log.Status = DatabaseQueryStatusSuccess
+	log.RowCount = result.RowsAffected
+	log.Truncated = result.Truncated
+	uc.repo.CreateQueryLog(ctx, log)
+
+	return &DatabaseQueryResult{
+		LogID:         log.ID,
+		StatementType: check.Type,
+		ReadOnly:      check.ReadOnly,
+		Columns:       result.Columns,
+		Rows:          result.Rows,
+		RowsAffected:  result.RowsAffected,
+		Truncated:     result.Truncated,
+		Duration:      log.Duration,
+	}, nil
+}
+
+func (uc *DatabaseUseCase) run(ctx context.Context, instance *DatabaseInstance, database string, readOnly bool, statement string, limit int) (*dbclient.Result, error) {
+	client, err := uc.openClient(ctx, instance, database)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	execCtx, cancel := context.WithTimeout(ctx, databaseQueryTimeout)
+	defer cancel()
+
+	if readOnly {
+		return client.Query(execCtx, statement, limit)
+	}
+	return client.Exec(execCtx, statement)
+}
+
+// ListQueryLogs 分页查询语句审计记录
+func (uc *DatabaseUseCase) ListQueryLogs(ctx context.Context, req *DatabaseQueryLogListRequest) ([]*DatabaseQueryLog, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+	return uc.repo.ListQueryLogs(ctx, req)
+}
@@ -59,6 +59,7 @@ type Host struct {
 	DiskUsage        float64       `gorm:"type:float;comment:磁盘使用率" json:"diskUsage"`
 	Uptime           string        `gorm:"type:varchar(100);comment:运行时间" json:"uptime"`
 	Hostname         string        `gorm:"type:varchar(100);comment:主机名" json:"hostname"`
+	CreatedBy        uint          `gorm:"column:created_by;default:0;index;comment:创建人ID" json:"createdBy"`
 }
 
 // HostRequest 主机请求
@@ -1667,3 +1667,27 @@ func (uc *HostUseCase) DeleteFile(ctx context.Context, hostID uint, remotePath s
 
 	return nil
 }
+
+// OpenSSHClient 创建到主机的SSH客户端，由调用方负责关闭
+func (uc *HostUseCase) OpenSSHClient(ctx context.Context, hostID uint) (*Host, *sshclient.Client, error) {
+	host, err := uc.hostRepo.GetByID(ctx, hostID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取主机信息失败: %w", err)
+	}
+
+	if host.CredentialID == 0 {
+		return nil, nil, fmt.Errorf("主机未配置凭证")
+	}
+
+	credential, err := uc.credentialRepo.GetByIDDecrypted(ctx, host.CredentialID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取凭证失败: %w", err)
+	}
+
+	sshClient, err := uc.createSSHClient(host, credential)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建SSH连接失败: %w", err)
+	}
+
+	return host, sshClient, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 端口转发模式
+const (
+	PortForwardModeWebSocket = "websocket" // WebSocket隧道（配合命令行工具使用）
+	PortForwardModeListener  = "listener"  // 平台侧临时监听端口
+)
+
+// 端口转发状态
+const (
+	PortForwardStatusActive  = "active"  // 转发中
+	PortForwardStatusClosed  = "closed"  // 已关闭
+	PortForwardStatusExpired = "expired" // 已过期
+	PortForwardStatusFailed  = "failed"  // 失败
+)
+
+// PortForwardSession 端口转发会话模型
+// 通过SSH direct-tcpip 通道将请求转发到主机可访问的 targetHost:targetPort
+type PortForwardSession struct {
+	ID            uint           `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	HostID        uint           `gorm:"not null;index;comment:主机ID" json:"hostId"`
+	HostName      string         `gorm:"type:varchar(100);comment:主机名称" json:"hostName"`
+	HostIP        string         `gorm:"type:varchar(50);comment:主机IP" json:"hostIp"`
+	UserID        uint           `gorm:"not null;index;comment:操作用户ID" json:"userId"`
+	Username      string         `gorm:"type:varchar(100);comment:用户名" json:"username"`
+	Mode          string         `gorm:"type:varchar(20);comment:转发模式 websocket/listener" json:"mode"`
+	TargetHost    string         `gorm:"type:varchar(255);comment:目标地址(相对主机)" json:"targetHost"`
+	TargetPort    int            `gorm:"comment:目标端口" json:"targetPort"`
+	ListenAddr    string         `gorm:"type:varchar(100);comment:平台监听地址" json:"listenAddr"`
+	ClientIP      string         `gorm:"type:varchar(50);comment:发起方IP" json:"clientIp"`
+	BytesSent     int64          `gorm:"default:0;comment:客户端发往目标的字节数" json:"bytesSent"`
+	BytesReceived int64          `gorm:"default:0;comment:目标返回客户端的字节数" json:"bytesReceived"`
+	ConnCount     int            `gorm:"default:0;comment:转发连接数" json:"connCount"`
+	Status        string         `gorm:"type:varchar(20);default:'active';index;comment:状态 active/closed/expired/failed" json:"status"`
+	Reason        string         `gorm:"type:varchar(500);comment:关闭原因" json:"reason"`
+	ExpiresAt     time.Time      `gorm:"comment:过期时间" json:"expiresAt"`
+	ClosedAt      *time.Time     `gorm:"comment:关闭时间" json:"closedAt"`
+	Duration      int            `gorm:"comment:持续时长(秒)" json:"duration"`
+}
+
+// TableName 表名
+func (PortForwardSession) TableName() string {
+	return "ssh_port_forwards"
+}
+
+// PortForwardRequest 创建端口转发请求
+type PortForwardRequest struct {
+	TargetHost string `json:"targetHost" form:"targetHost"`                                    // 目标地址，默认127.0.0.1
+	TargetPort int    `json:"targetPort" form:"targetPort" binding:"required,min=1,max=65535"` // 目标端口
+	Duration   int    `json:"duration" form:"duration" binding:"omitempty,min=1,max=480"`      // 有效期(分钟)，默认60
+}
+
+// Target 目标地址 host:port
+func (s *PortForwardSession) Target() string {
+	return net.JoinHostPort(s.TargetHost, strconv.Itoa(s.TargetPort))
+}
@@ -63,3 +63,14 @@ type CloudAccountRepo interface {
 	List(ctx context.Context, page, pageSize int) ([]*CloudAccount, int64, error)
 	GetAll(ctx context.Context) ([]*CloudAccount, error)
 }
+
+type DatabaseRepo interface {
+	Create(ctx context.Context, instance *DatabaseInstance) error
+	Update(ctx context.Context, instance *DatabaseInstance) error
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*DatabaseInstance, error)
+	List(ctx context.Context, page, pageSize int, keyword, dbType string, groupIDs []uint, accessibleIDs []uint) ([]*DatabaseInstance, int64, error)
+	UpdateStatus(ctx context.Context, id uint, status int) error
+	CreateQueryLog(ctx context.Context, log *DatabaseQueryLog) error
+	ListQueryLogs(ctx context.Context, req *DatabaseQueryLogListRequest) ([]*DatabaseQueryLog, int64, error)
+}
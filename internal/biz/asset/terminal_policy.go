@@ -0,0 +1,250 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 会话策略作用范围
+const (
+	SessionPolicyScopeRole  = "role"  // 按角色
+	SessionPolicyScopeGroup = "group" // 按资产分组
+)
+
+// 终端会话结束原因
+const (
+	SessionCloseClient        = "client_closed"    // 用户断开连接
+	SessionCloseShellExit     = "shell_exited"     // 远端 shell 退出
+	SessionCloseIdleTimeout   = "idle_timeout"     // 空闲超时
+	SessionCloseMaxDuration   = "max_duration"     // 超过最长会话时长
+	SessionCloseOutsideWindow = "outside_window"   // 不在允许的时间段
+	SessionCloseConcurrent    = "concurrent_limit" // 超过并发会话数
+)
+
+// TerminalSessionPolicy 终端会话策略
+// 同一会话命中多条策略时取最严格的限制
+type TerminalSessionPolicy struct {
+	ID              uint           `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	Name            string         `gorm:"type:varchar(100);not null;comment:策略名称" json:"name"`
+	ScopeType       string         `gorm:"type:varchar(20);not null;index:idx_session_policy_scope;comment:作用范围 role/group" json:"scopeType"`
+	ScopeID         uint           `gorm:"not null;index:idx_session_policy_scope;comment:角色ID或资产分组ID" json:"scopeId"`
+	IdleTimeout     int            `gorm:"type:int;default:0;comment:空闲超时(秒)，0表示不限制" json:"idleTimeout"`
+	IdleWarning     int            `gorm:"type:int;default:60;comment:空闲断开前提前警告(秒)" json:"idleWarning"`
+	MaxDuration     int            `gorm:"type:int;default:0;comment:最长会话时长(秒)，0表示不限制" json:"maxDuration"`
+	MaxConcurrent   int            `gorm:"type:int;default:0;comment:每个用户最大并发会话数，0表示不限制" json:"maxConcurrent"`
+	AllowedWindows  string         `gorm:"type:varchar(255);comment:允许的时间段，如 09:00-18:00,20:00-22:00" json:"allowedWindows"`
+	AllowedWeekdays string         `gorm:"type:varchar(20);comment:允许的星期，1-7 表示周一到周日，如 1,2,3,4,5" json:"allowedWeekdays"`
+	Enabled         bool           `gorm:"type:tinyint(1);default:1;comment:是否启用" json:"enabled"`
+	Description     string         `gorm:"type:varchar(500);comment:描述" json:"description"`
+}
+
+// TableName 表名
+func (TerminalSessionPolicy) TableName() string {
+	return "ssh_session_policies"
+}
+
+// TerminalSessionPolicyRequest 会话策略请求
+type TerminalSessionPolicyRequest struct {
+	Name            string `json:"name" binding:"required,max=100"`
+	ScopeType       string `json:"scopeType" binding:"required,oneof=role group"`
+	ScopeID         uint   `json:"scopeId" binding:"required"`
+	IdleTimeout     int    `json:"idleTimeout" binding:"min=0"`
+	IdleWarning     int    `json:"idleWarning" binding:"min=0"`
+	MaxDuration     int    `json:"maxDuration" binding:"min=0"`
+	MaxConcurrent   int    `json:"maxConcurrent" binding:"min=0"`
+	AllowedWindows  string `json:"allowedWindows"`
+	AllowedWeekdays string `json:"allowedWeekdays"`
+	Enabled         *bool  `json:"enabled"`
+	Description     string `json:"description"`
+}
+
+// ToModel 转换为模型并校验时间段配置
+func (req *TerminalSessionPolicyRequest) ToModel() (*TerminalSessionPolicy, error) {
+	policy := &TerminalSessionPolicy{
+		Name:            req.Name,
+		ScopeType:       req.ScopeType,
+		ScopeID:         req.ScopeID,
+		IdleTimeout:     req.IdleTimeout,
+		IdleWarning:     req.IdleWarning,
+		MaxDuration:     req.MaxDuration,
+		MaxConcurrent:   req.MaxConcurrent,
+		AllowedWindows:  strings.TrimSpace(req.AllowedWindows),
+		AllowedWeekdays: strings.TrimSpace(req.AllowedWeekdays),
+		Enabled:         true,
+		Description:     req.Description,
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if _, err := parseTimeWindows(policy.AllowedWindows); err != nil {
+		return nil, err
+	}
+	if _, err := parseWeekdays(policy.AllowedWeekdays); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// AllowedAt 判断指定时刻是否在策略允许的时间段内
+func (p *TerminalSessionPolicy) AllowedAt(t time.Time) bool {
+	weekdays, err := parseWeekdays(p.AllowedWeekdays)
+	if err != nil {
+		return false
+	}
+	windows, err := parseTimeWindows(p.AllowedWindows)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	weekday := isoWeekday(t.Weekday())
+	if len(windows) == 0 {
+		return len(weekdays) == 0 || weekdays[weekday]
+	}
+	for _, w := range windows {
+		if w.start <= w.end {
+			if minute >= w.start && minute < w.end && (len(weekdays) == 0 || weekdays[weekday]) {
+				return true
+			}
+			continue
+		}
+		// 跨零点的时间段，零点之后的部分属于前一天的时间段
+		if minute >= w.start && (len(weekdays) == 0 || weekdays[weekday]) {
+			return true
+		}
+		if minute < w.end && (len(weekdays) == 0 || weekdays[isoWeekday(t.AddDate(0, 0, -1).Weekday())]) {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveSessionPolicy 合并后的会话策略
+type EffectiveSessionPolicy struct {
+	IdleTimeout   time.Duration
+	IdleWarning   time.Duration
+	MaxDuration   time.Duration
+	MaxConcurrent int
+	Policies      []*TerminalSessionPolicy
+}
+
+// MergeSessionPolicies 合并多条策略，每项限制取最严格的值
+func MergeSessionPolicies(policies []*TerminalSessionPolicy) *EffectiveSessionPolicy {
+	eff := &EffectiveSessionPolicy{Policies: policies}
+	for _, p := range policies {
+		if p.IdleTimeout > 0 && (eff.IdleTimeout == 0 || time.Duration(p.IdleTimeout)*time.Second < eff.IdleTimeout) {
+			eff.IdleTimeout = time.Duration(p.IdleTimeout) * time.Second
+			eff.IdleWarning = time.Duration(p.IdleWarning) * time.Second
+		}
+		if p.MaxDuration > 0 && (eff.MaxDuration == 0 || time.Duration(p.MaxDuration)*time.Second < eff.MaxDuration) {
+			eff.MaxDuration = time.Duration(p.MaxDuration) * time.Second
+		}
+		if p.MaxConcurrent > 0 && (eff.MaxConcurrent == 0 || p.MaxConcurrent < eff.MaxConcurrent) {
+			eff.MaxConcurrent = p.MaxConcurrent
+		}
+	}
+	if eff.IdleWarning >= eff.IdleTimeout {
+		eff.IdleWarning = eff.IdleTimeout / 2
+	}
+	return eff
+}
+
+// AllowedAt 判断指定时刻是否满足所有策略的时间段限制
+func (e *EffectiveSessionPolicy) AllowedAt(t time.Time) bool {
+	for _, p := range e.Policies {
+		if !p.AllowedAt(t) {
+			return false
+		}
+	}
+	return true
+}
+
+type timeWindow struct {
+	start, end int // 当天的分钟数
+}
+
+// parseTimeWindows 解析 HH:MM-HH:MM 格式的时间段列表
+func parseTimeWindows(s string) ([]timeWindow, error) {
+	var windows []timeWindow
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("无效的时间段: %s", part)
+		}
+		start, err := parseClock(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("时间段起止时间不能相同: %s", part)
+		}
+		windows = append(windows, timeWindow{start: start, end: end})
+	}
+	return windows, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("无效的时间: %s", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseWeekdays 解析星期列表，1-7 表示周一到周日
+func parseWeekdays(s string) (map[int]bool, error) {
+	weekdays := make(map[int]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		day, err := strconv.Atoi(part)
+		if err != nil || day < 1 || day > 7 {
+			return nil, fmt.Errorf("无效的星期: %s", part)
+		}
+		weekdays[day] = true
+	}
+	return weekdays, nil
+}
+
+func isoWeekday(d time.Weekday) int {
+	if d == time.Sunday {
+		return 7
+	}
+	return int(d)
+}
@@ -39,7 +39,8 @@ type TerminalSession struct {
 	RecordingPath string         `gorm:"type:varchar(500);comment:录制文件路径" json:"recordingPath"`
 	Duration      int            `gorm:"type:int;comment:会话时长(秒)" json:"duration"`
 	FileSize      int64          `gorm:"type:bigint;comment:文件大小(字节)" json:"fileSize"`
-	Status        string         `gorm:"type:varchar(20);default:'recording';comment:会话状态 recording/completed/failed" json:"status"`
+	Status        string         `gorm:"type:varchar(20);default:'recording';comment:会话状态 recording/completed/failed/rejected" json:"status"`
+	CloseReason   string         `gorm:"type:varchar(50);comment:会话结束原因" json:"closeReason"`
 }
 
 // TableName 表名
@@ -61,6 +62,8 @@ type TerminalSessionInfo struct {
 	FileSizeText  string    `json:"fileSizeText"`  // 格式化的文件大小，如 "1.5 MB"
 	Status        string    `json:"status"`
 	StatusText    string    `json:"statusText"`
+	CloseReason   string    `json:"closeReason"`
+	CloseReasonText string    `json:"closeReasonText"`
 	CreatedAt     time.Time `json:"createdAt"`
 	CreatedAtText string    `json:"createdAtText"` // 格式化的创建时间
 }
@@ -0,0 +1,424 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 审计链名称，每条链对应一张审计表
+const (
+	ChainOperationLog       = "operation_log"
+	ChainLoginLog           = "login_log"
+	ChainDataLog            = "data_log"
+	ChainTerminalSession    = "terminal_session"
+	ChainK8sTerminalSession = "k8s_terminal_session"
+)
+
+// 校验发现的问题类型
+const (
+	ChainIssueGap        = "gap"         // 链序号缺失，链记录被删除
+	ChainIssueBrokenLink = "broken_link" // 前序哈希与上一条不一致
+	ChainIssueHash       = "hash"        // 链记录自身哈希不正确
+	ChainIssueModified   = "modified"    // 审计记录内容与入链时不一致
+	ChainIssueDeleted    = "deleted"     // 审计记录已被删除或软删除
+	ChainIssueUnchained  = "unchained"   // 链启用后写入但未入链的审计记录
+	ChainIssueHead       = "head"        // 链头与最后一条链记录不一致
+	ChainIssueCheckpoint = "checkpoint"  // 检查点签名无效或与链记录不一致
+	ChainIssuePrune      = "prune"       // 链头的归档截断位置没有对应的签名归档
+)
+
+// chainIssueLimit 单条链最多报告的问题数
+const chainIssueLimit = 1000
+
+// chainVerifyBatch 校验时每批读取的链记录数
+const chainVerifyBatch = 500
+
+// AuditChain 审计链配置，ExcludeFields 为允许事后更新、不参与摘要的列
+// AddedFields 为审计表启用哈希链后新增的列，值为空时不参与摘要，新增列前写入的记录仍能通过校验
+type AuditChain struct {
+	Name          string
+	Table         string
+	ExcludeFields []string
+	AddedFields   []string
+}
+
+// SysAuditChain 审计链头，记录最后一条链记录的序号和哈希，追加时加行锁保证串行
+// 保留策略归档后，序号不超过 PrunedSeq 的链记录连同审计记录一起清理，校验从 PrunedHash 接续
+type SysAuditChain struct {
+	Name       string    `gorm:"primaryKey;type:varchar(50);comment:链名称" json:"name"`
+	Table      string    `gorm:"column:table_name;type:varchar(100);comment:审计表名" json:"tableName"`
+	LastSeq    uint64    `gorm:"comment:最后序号" json:"lastSeq"`
+	LastHash   string    `gorm:"type:varchar(64);comment:最后哈希" json:"lastHash"`
+	PrunedSeq  uint64    `gorm:"comment:已归档清理到的序号" json:"prunedSeq"`
+	PrunedHash string    `gorm:"type:varchar(64);comment:已归档清理的最后一条哈希" json:"prunedHash"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// SysAuditChainEntry 审计链记录，每条审计记录对应一条，哈希包含前一条的哈希
+type SysAuditChainEntry struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Chain     string    `gorm:"type:varchar(50);uniqueIndex:uk_chain_seq;index:idx_chain_created,priority:1;comment:链名称" json:"chain"`
+	Seq       uint64    `gorm:"uniqueIndex:uk_chain_seq;comment:链内序号" json:"seq"`
+	RecordID  uint      `gorm:"index;comment:审计记录ID" json:"recordId"`
+	Digest    string    `gorm:"type:varchar(64);comment:审计记录内容摘要" json:"digest"`
+	PrevHash  string    `gorm:"type:varchar(64);comment:前一条哈希" json:"prevHash"`
+	Hash      string    `gorm:"type:varchar(64);comment:本条哈希" json:"hash"`
+	CreatedAt time.Time `gorm:"index:idx_chain_created,priority:2" json:"createdAt"`
+}
+
+// SysAuditCheckpoint 审计链检查点，用签名密钥对某一时刻的链头签名，防止整条链被重算
+type SysAuditCheckpoint struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Chain     string    `gorm:"type:varchar(50);index;comment:链名称" json:"chain"`
+	Seq       uint64    `gorm:"comment:链头序号" json:"seq"`
+	Hash      string    `gorm:"type:varchar(64);comment:链头哈希" json:"hash"`
+	Signature string    `gorm:"type:varchar(64);comment:HMAC-SHA256签名" json:"signature"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (SysAuditChain) TableName() string {
+	return "sys_audit_chain"
+}
+
+func (SysAuditChainEntry) TableName() string {
+	return "sys_audit_chain_entry"
+}
+
+func (SysAuditCheckpoint) TableName() string {
+	return "sys_audit_checkpoint"
+}
+
+// ChainRecord 审计表中的一行，Row 以列名为键
+type ChainRecord struct {
+	Row     map[string]interface{}
+	Deleted bool
+}
+
+// ChainIssue 校验发现的问题
+type ChainIssue struct {
+	Type     string `json:"type"`
+	Seq      uint64 `json:"seq,omitempty"`
+	RecordID uint   `json:"recordId,omitempty"`
+	Detail   string `json:"detail"`
+}
+
+// ChainReport 单条审计链的校验结果
+type ChainReport struct {
+	Chain       string       `json:"chain"`
+	Table       string       `json:"tableName"`
+	Entries     int64        `json:"entries"`
+	LastSeq     uint64       `json:"lastSeq"`
+	Checkpoints int          `json:"checkpoints"`
+	Valid       bool         `json:"valid"`
+	Truncated   bool         `json:"truncated"` // 问题过多，只保留前一部分
+	Issues      []ChainIssue `json:"issues"`
+	VerifiedAt  time.Time    `json:"verifiedAt"`
+}
+
+func (r *ChainReport) addIssue(issue ChainIssue) {
+	r.Valid = false
+	if len(r.Issues) >= chainIssueLimit {
+		r.Truncated = true
+		return
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+// AuditChainRepo 审计链仓储接口
+type AuditChainRepo interface {
+	GetHead(ctx context.Context, chain string) (*SysAuditChain, error)
+	ListEntries(ctx context.Context, chain string, afterSeq uint64, limit int) ([]*SysAuditChainEntry, error)
+	LoadRecords(ctx context.Context, table string, ids []uint) (map[uint]ChainRecord, error)
+	ListUnchained(ctx context.Context, chain AuditChain, since time.Time, limit int) ([]uint, int64, error)
+	ListCheckpoints(ctx context.Context, chain string) ([]*SysAuditCheckpoint, error)
+	CreateCheckpoint(ctx context.Context, checkpoint *SysAuditCheckpoint) error
+	// GetPruneArchive 返回链上截断位置最大的归档
+	GetPruneArchive(ctx context.Context, chain string) (*SysAuditArchive, error)
+}
+
+// RecordDigest 计算审计记录内容摘要，忽略更新时间、删除时间和配置的可变列
+// 时间统一转为 UTC，保证写入和校验时从数据库读出的同一行得到相同结果
+func RecordDigest(row map[string]interface{}, chain AuditChain) string {
+	normalized := NormalizeRow(row)
+	delete(normalized, "updated_at")
+	delete(normalized, "deleted_at")
+	for _, f := range chain.ExcludeFields {
+		delete(normalized, f)
+	}
+	for _, f := range chain.AddedFields {
+		if v, ok := normalized[f]; ok && (v == nil || v == "") {
+			delete(normalized, f)
+		}
+	}
+	// encoding/json 按键排序输出 map，结果稳定
+	data, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeRow 将从数据库读出的行转换为可稳定序列化的值：字节转字符串，时间转 UTC
+// 归档文件使用同一格式，可以用归档中的行重新计算摘要
+func NormalizeRow(row map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		switch val := v.(type) {
+		case []byte:
+			normalized[k] = string(val)
+		case time.Time:
+			normalized[k] = val.UTC().Format(time.RFC3339Nano)
+		case *time.Time:
+			if val != nil {
+				normalized[k] = val.UTC().Format(time.RFC3339Nano)
+			} else {
+				normalized[k] = nil
+			}
+		default:
+			normalized[k] = val
+		}
+	}
+	return normalized
+}
+
+// ChainHash 计算链记录哈希
+func ChainHash(chain string, seq uint64, recordID uint, digest, prevHash string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%s|%s", chain, seq, recordID, digest, prevHash)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignCheckpoint 计算检查点签名
+func SignCheckpoint(key []byte, chain string, seq uint64, hash string, createdAt time.Time) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s|%d|%s|%d", chain, seq, hash, createdAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditChainUseCase 审计链用例：生成检查点、校验链完整性
+type AuditChainUseCase struct {
+	repo       AuditChainRepo
+	chains     []AuditChain
+	signingKey []byte
+}
+
+func NewAuditChainUseCase(repo AuditChainRepo, chains []AuditChain, signingKey string) *AuditChainUseCase {
+	return &AuditChainUseCase{
+		repo:       repo,
+		chains:     chains,
+		signingKey: []byte(signingKey),
+	}
+}
+
+// Chains 返回启用的审计链
+func (uc *AuditChainUseCase) Chains() []AuditChain {
+	return uc.chains
+}
+
+// Checkpoint 为自上次检查点以来有新记录的链生成签名检查点
+func (uc *AuditChainUseCase) Checkpoint(ctx context.Context) (int, error) {
+	if len(uc.signingKey) == 0 {
+		return 0, fmt.Errorf("未配置审计签名密钥")
+	}
+	created := 0
+	for _, chain := range uc.chains {
+		head, err := uc.repo.GetHead(ctx, chain.Name)
+		if err != nil {
+			return created, err
+		}
+		if head == nil || head.LastSeq == 0 {
+			continue
+		}
+		checkpoints, err := uc.repo.ListCheckpoints(ctx, chain.Name)
+		if err != nil {
+			return created, err
+		}
+		if n := len(checkpoints); n > 0 && checkpoints[n-1].Seq >= head.LastSeq {
+			continue
+		}
+		// 精确到秒，数据库读出后签名内容不变
+		now := time.Now().Truncate(time.Second)
+		checkpoint := &SysAuditCheckpoint{
+			Chain:     chain.Name,
+			Seq:       head.LastSeq,
+			Hash:      head.LastHash,
+			Signature: SignCheckpoint(uc.signingKey, chain.Name, head.LastSeq, head.LastHash, now),
+			CreatedAt: now,
+		}
+		if err := uc.repo.CreateCheckpoint(ctx, checkpoint); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// Verify 校验审计链，name 为空时校验全部链
+func (uc *AuditChainUseCase) Verify(ctx context.Context, name string) ([]*ChainReport, error) {
+	var reports []*ChainReport
+	for _, chain := range uc.chains {
+		if name != "" && chain.Name != name {
+			continue
+		}
+		report, err := uc.verifyChain(ctx, chain)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if name != "" && len(reports) == 0 {
+		return nil, fmt.Errorf("审计链不存在: %s", name)
+	}
+	return reports, nil
+}
+
+func (uc *AuditChainUseCase) verifyChain(ctx context.Context, chain AuditChain) (*ChainReport, error) {
+	report := &ChainReport{Chain: chain.Name, Table: chain.Table, Valid: true, Issues: []ChainIssue{}, VerifiedAt: time.Now()}
+
+	head, err := uc.repo.GetHead(ctx, chain.Name)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		report.addIssue(ChainIssue{Type: ChainIssueHead, Detail: "链头不存在"})
+		return report, nil
+	}
+
+	checkpoints, err := uc.repo.ListCheckpoints(ctx, chain.Name)
+	if err != nil {
+		return nil, err
+	}
+	report.Checkpoints = len(checkpoints)
+	// 只保留检查点对应序号的哈希，避免长链占用过多内存
+	hashes := make(map[uint64]string, len(checkpoints))
+	wanted := make(map[uint64]bool, len(checkpoints))
+	for _, cp := range checkpoints {
+		wanted[cp.Seq] = true
+	}
+
+	// 已归档清理的前缀由签名归档担保，校验从截断位置接续
+	if head.PrunedSeq > 0 {
+		archive, err := uc.repo.GetPruneArchive(ctx, chain.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case archive == nil:
+			report.addIssue(ChainIssue{Type: ChainIssuePrune, Seq: head.PrunedSeq, Detail: "链头记录已归档清理，但找不到对应的归档"})
+		case len(uc.signingKey) > 0 && !hmac.Equal([]byte(SignArchive(uc.signingKey, archive)), []byte(archive.Signature)):
+			report.addIssue(ChainIssue{Type: ChainIssuePrune, Seq: archive.ToSeq, Detail: "归档签名无效"})
+		case archive.ToSeq != head.PrunedSeq || archive.LastHash != head.PrunedHash:
+			report.addIssue(ChainIssue{Type: ChainIssuePrune, Seq: head.PrunedSeq, Detail: fmt.Sprintf("链头截断位置与归档记录的序号 %d 不一致", archive.ToSeq)})
+		}
+	}
+
+	// 逐批检查序号连续、前序哈希衔接、链记录哈希和审计记录内容
+	expected := head.PrunedSeq + 1
+	prevHash := head.PrunedHash
+	for {
+		entries, err := uc.repo.ListEntries(ctx, chain.Name, expected-1, chainVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		ids := make([]uint, 0, len(entries))
+		for _, e := range entries {
+			ids = append(ids, e.RecordID)
+		}
+		records, err := uc.repo.LoadRecords(ctx, chain.Table, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			report.Entries++
+			if e.Seq != expected {
+				report.addIssue(ChainIssue{Type: ChainIssueGap, Seq: expected, Detail: fmt.Sprintf("缺少序号 %d-%d 的链记录", expected, e.Seq-1)})
+			} else if e.PrevHash != prevHash {
+				report.addIssue(ChainIssue{Type: ChainIssueBrokenLink, Seq: e.Seq, RecordID: e.RecordID, Detail: "前序哈希与上一条链记录不一致"})
+			}
+			if ChainHash(chain.Name, e.Seq, e.RecordID, e.Digest, e.PrevHash) != e.Hash {
+				report.addIssue(ChainIssue{Type: ChainIssueHash, Seq: e.Seq, RecordID: e.RecordID, Detail: "链记录哈希不正确"})
+			}
+			record, ok := records[e.RecordID]
+			switch {
+			case !ok:
+				report.addIssue(ChainIssue{Type: ChainIssueDeleted, Seq: e.Seq, RecordID: e.RecordID, Detail: "审计记录已被删除"})
+			case RecordDigest(record.Row, chain) != e.Digest:
+				report.addIssue(ChainIssue{Type: ChainIssueModified, Seq: e.Seq, RecordID: e.RecordID, Detail: "审计记录内容已被修改"})
+			case record.Deleted:
+				report.addIssue(ChainIssue{Type: ChainIssueDeleted, Seq: e.Seq, RecordID: e.RecordID, Detail: "审计记录已被软删除"})
+			}
+			if wanted[e.Seq] {
+				hashes[e.Seq] = e.Hash
+			}
+			prevHash = e.Hash
+			report.LastSeq = e.Seq
+			expected = e.Seq + 1
+		}
+	}
+
+	if report.Entries == 0 {
+		report.LastSeq = head.PrunedSeq
+	}
+	if head.LastSeq != report.LastSeq || head.LastHash != prevHash {
+		report.addIssue(ChainIssue{Type: ChainIssueHead, Seq: head.LastSeq, Detail: fmt.Sprintf("链头序号 %d 与最后一条链记录序号 %d 不一致，链尾可能被截断", head.LastSeq, report.LastSeq)})
+	}
+
+	for _, cp := range checkpoints {
+		if cp.Seq <= head.PrunedSeq {
+			continue
+		}
+		if len(uc.signingKey) == 0 {
+			report.addIssue(ChainIssue{Type: ChainIssueCheckpoint, Seq: cp.Seq, Detail: "未配置审计签名密钥，无法校验检查点"})
+			break
+		}
+		if !hmac.Equal([]byte(SignCheckpoint(uc.signingKey, cp.Chain, cp.Seq, cp.Hash, cp.CreatedAt)), []byte(cp.Signature)) {
+			report.addIssue(ChainIssue{Type: ChainIssueCheckpoint, Seq: cp.Seq, Detail: "检查点签名无效"})
+			continue
+		}
+		if hash, ok := hashes[cp.Seq]; !ok {
+			report.addIssue(ChainIssue{Type: ChainIssueCheckpoint, Seq: cp.Seq, Detail: "检查点对应的链记录不存在"})
+		} else if hash != cp.Hash {
+			report.addIssue(ChainIssue{Type: ChainIssueCheckpoint, Seq: cp.Seq, Detail: "链记录哈希与已签名检查点不一致，链可能被重算"})
+		}
+	}
+
+	// 链启用后写入却没有链记录的审计记录，可能是绕过应用直接插入
+	ids, total, err := uc.repo.ListUnchained(ctx, chain, head.CreatedAt, 100)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		report.addIssue(ChainIssue{Type: ChainIssueUnchained, RecordID: id, Detail: "审计记录未入链"})
+	}
+	if total > int64(len(ids)) {
+		report.addIssue(ChainIssue{Type: ChainIssueUnchained, Detail: fmt.Sprintf("共有 %d 条审计记录未入链", total)})
+	}
+
+	return report, nil
+}
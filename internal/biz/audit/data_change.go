@@ -0,0 +1,150 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// 数据变更类型，与 SysDataLog.Action 一致
+const (
+	DataActionCreate = "create"
+	DataActionUpdate = "update"
+	DataActionDelete = "delete"
+)
+
+// dataMaskValue 敏感字段在数据日志中的替代值
+const dataMaskValue = "******"
+
+// Actor 发起数据变更的用户和客户端，由认证中间件放入请求上下文
+type Actor struct {
+	UserID    uint
+	Username  string
+	RealName  string
+	IP        string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor 在上下文中记录当前操作人
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext 获取上下文中的操作人，后台任务等没有操作人时返回 false
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// DataChangeTable 需要记录数据变更的表
+type DataChangeTable struct {
+	// Table 数据库表名
+	Table string
+	// MaskFields 敏感列，日志中只记录是否变更，不记录内容
+	MaskFields []string
+	// IgnoreFields 不参与比较的列，如更新时间、采集的监控指标，只有这些列变化时不记录
+	IgnoreFields []string
+}
+
+// BuildDataLog 根据变更前后的行快照生成数据日志，快照以列名为键
+// 更新时没有实际变化的列返回 nil，不需要记录
+func BuildDataLog(ctx context.Context, table DataChangeTable, action string, recordID uint, oldRow, newRow map[string]interface{}) (*SysDataLog, error) {
+	ignored := make(map[string]bool, len(table.IgnoreFields))
+	for _, f := range table.IgnoreFields {
+		ignored[f] = true
+	}
+
+	var diff []string
+	if action == DataActionUpdate {
+		for column, newValue := range newRow {
+			if ignored[column] {
+				continue
+			}
+			if !sameDataValue(oldRow[column], newValue) {
+				diff = append(diff, column)
+			}
+		}
+		if len(diff) == 0 {
+			return nil, nil
+		}
+		sort.Strings(diff)
+	}
+
+	log := &SysDataLog{
+		Table:      table.Table,
+		RecordID:   recordID,
+		Action:     action,
+		DiffFields: strings.Join(diff, ","),
+	}
+	var err error
+	if log.OldData, err = maskedDataJSON(oldRow, table.MaskFields); err != nil {
+		return nil, err
+	}
+	if log.NewData, err = maskedDataJSON(newRow, table.MaskFields); err != nil {
+		return nil, err
+	}
+	if actor, ok := ActorFromContext(ctx); ok {
+		log.UserID = actor.UserID
+		log.Username = actor.Username
+		log.RealName = actor.RealName
+		log.IP = actor.IP
+		log.UserAgent = actor.UserAgent
+	}
+	return log, nil
+}
+
+// sameDataValue 按 JSON 序列化结果比较，避免不同数值类型和时间指针带来的误判
+func sameDataValue(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
+
+// maskedDataJSON 序列化行快照，敏感列有值时替换为掩码
+func maskedDataJSON(row map[string]interface{}, maskFields []string) (string, error) {
+	if row == nil {
+		return "", nil
+	}
+	masked := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		masked[k] = v
+	}
+	for _, f := range maskFields {
+		if v, ok := masked[f]; ok && v != nil && v != "" {
+			masked[f] = dataMaskValue
+		}
+	}
+	data, err := json.Marshal(masked)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 转发到外部系统的审计事件类型
+const (
+	EventTypeOperation       = "operation"
+	EventTypeLogin           = "login"
+	EventTypeDataChange      = "data_change"
+	EventTypeTerminalCommand = "terminal_command"
+	EventTypeK8sExec         = "k8s_exec"
+	EventTypeSecurity        = "security"
+)
+
+// 审计事件结果
+const (
+	EventOutcomeSuccess = "success"
+	EventOutcomeFailure = "failure"
+)
+
+// AuditEvent 转发到 syslog、SIEM 等外部系统的审计事件，各类审计记录统一为同一结构
+type AuditEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Time      time.Time              `json:"time"`
+	UserID    uint                   `json:"userId,omitempty"`
+	Username  string                 `json:"username,omitempty"`
+	IP        string                 `json:"ip,omitempty"`
+	UserAgent string                 `json:"userAgent,omitempty"`
+	Action    string                 `json:"action"`
+	Target    string                 `json:"target,omitempty"`
+	Outcome   string                 `json:"outcome"`
+	Detail    map[string]interface{} `json:"detail,omitempty"`
+}
+
+// EventPublisher 审计事件发布者，Publish 不能阻塞调用方
+type EventPublisher interface {
+	Publish(event *AuditEvent)
+}
+
+var (
+	publisherMu sync.RWMutex
+	publisher   EventPublisher
+)
+
+// SetEventPublisher 设置全局审计事件发布者，未设置时事件直接丢弃
+func SetEventPublisher(p EventPublisher) {
+	publisherMu.Lock()
+	defer publisherMu.Unlock()
+	publisher = p
+}
+
+// PublishEvent 发布审计事件，终端、插件等不经过审计表的事件源直接调用
+func PublishEvent(event *AuditEvent) {
+	publisherMu.RLock()
+	p := publisher
+	publisherMu.RUnlock()
+	if p == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = NewEventID()
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Outcome == "" {
+		event.Outcome = EventOutcomeSuccess
+	}
+	p.Publish(event)
+}
+
+// NewEventID 生成随机事件ID
+func NewEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// CommandBuffer 从终端输入流中还原用户输入的命令行，回车时返回整行
+// 只处理退格、清行和转义序列，补全和历史命令在远端展开，无法还原
+type CommandBuffer struct {
+	line   []rune
+	escape int // 0 非转义，1 收到 ESC，2 处于 CSI 序列中
+}
+
+// maxCommandLength 单条命令最多保留的字符数
+const maxCommandLength = 4096
+
+// Feed 写入一段输入，返回其中完成的命令行
+func (b *CommandBuffer) Feed(data []byte) []string {
+	var lines []string
+	for _, r := range string(data) {
+		switch b.escape {
+		case 1:
+			if r == '[' || r == 'O' {
+				b.escape = 2
+			} else {
+				b.escape = 0
+			}
+			continue
+		case 2:
+			// CSI 序列以 0x40-0x7e 结束
+			if r >= 0x40 && r <= 0x7e {
+				b.escape = 0
+			}
+			continue
+		}
+		switch r {
+		case '\r', '\n':
+			if line := string(b.line); strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+			b.line = b.line[:0]
+		case 0x7f, 0x08:
+			if len(b.line) > 0 {
+				b.line = b.line[:len(b.line)-1]
+			}
+		case 0x03, 0x15:
+			// Ctrl+C 放弃当前输入，Ctrl+U 清空当前行
+			b.line = b.line[:0]
+		case 0x1b:
+			b.escape = 1
+		default:
+			if r >= 0x20 && len(b.line) < maxCommandLength {
+				b.line = append(b.line, r)
+			}
+		}
+	}
+	return lines
+}
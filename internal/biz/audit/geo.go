@@ -0,0 +1,86 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"math"
+	"net"
+	"strings"
+)
+
+// LocationPrivate 内网和本机地址的登录地点
+const LocationPrivate = "内网"
+
+// GeoLocation IP 地理位置
+type GeoLocation struct {
+	CountryCode    string  `json:"countryCode"`
+	Country        string  `json:"country"`
+	Region         string  `json:"region"`
+	City           string  `json:"city"`
+	Latitude       float64 `json:"latitude"`
+	Longitude      float64 `json:"longitude"`
+	HasCoordinates bool    `json:"hasCoordinates"`
+}
+
+// String 返回 国家 省份 城市 形式的地点，相同的名称只保留一个
+func (g *GeoLocation) String() string {
+	if g == nil {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	for _, name := range []string{g.Country, g.Region, g.City} {
+		if name != "" && (len(parts) == 0 || parts[len(parts)-1] != name) {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CountryKey 用于比较国家的标识，优先使用 ISO 代码
+func (g *GeoLocation) CountryKey() string {
+	if g == nil {
+		return ""
+	}
+	if g.CountryCode != "" {
+		return g.CountryCode
+	}
+	return g.Country
+}
+
+// GeoLocator 离线 IP 地理位置库
+type GeoLocator interface {
+	// Lookup 查询 IP 所在地，库中没有时返回 nil
+	Lookup(ip net.IP) (*GeoLocation, error)
+}
+
+// IsPrivateIP 是否为内网、本机或链路本地地址，这类地址不做地理位置解析
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+}
+
+// DistanceKm 按球面距离计算两个地点相距的公里数
+func DistanceKm(a, b *GeoLocation) float64 {
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
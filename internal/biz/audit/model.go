@@ -51,6 +51,11 @@ type SysOperationLog struct {
 	ErrorMsg string `gorm:"type:text;comment:错误信息" json:"errorMsg"`            // 错误信息
 	CostTime int64  `gorm:"type:bigint;comment:耗时(毫秒)" json:"costTime"`        // 请求耗时
 
+	// 资源信息，来自接口登记的审计元数据
+	Route        string `gorm:"type:varchar(200);comment:路由模式" json:"route"`                                  // /api/v1/users/:id
+	ResourceType string `gorm:"type:varchar(50);index:idx_resource,priority:1;comment:资源类型" json:"resourceType"` // user, host, k8s_deployment等
+	ResourceID   string `gorm:"type:varchar(200);index:idx_resource,priority:2;comment:资源ID" json:"resourceId"`
+
 	// 环境信息
 	IP        string `gorm:"type:varchar(50);comment:IP地址" json:"ip"`
 	UserAgent string `gorm:"type:varchar(500);comment:用户代理" json:"userAgent"`
@@ -75,7 +80,7 @@ type SysLoginLog struct {
 	LogoutTime  *time.Time `gorm:"comment:登出时间" json:"logoutTime,omitempty"`
 
 	// 环境信息
-	IP        string `gorm:"type:varchar(50);comment:IP地址" json:"ip"`
+	IP        string `gorm:"type:varchar(50);index:idx_ip;comment:IP地址" json:"ip"`
 	Location  string `gorm:"type:varchar(100);comment:登录地点" json:"location"`
 	UserAgent string `gorm:"type:varchar(500);comment:用户代理" json:"userAgent"`
 
@@ -96,7 +101,7 @@ type SysDataLog struct {
 	RealName string `gorm:"type:varchar(50);comment:真实姓名" json:"realName"`
 
 	// 数据信息
-	TableName  string `gorm:"column:table_name;type:varchar(50);comment:表名" json:"tableName"`   // sys_user, sys_role等
+	Table      string `gorm:"column:table_name;type:varchar(50);comment:表名" json:"tableName"`   // sys_user, sys_role等
 	RecordID   uint   `gorm:"index;comment:记录ID" json:"recordId"`             // 数据记录的主键ID
 	Action     string `gorm:"type:varchar(20);comment:操作类型" json:"action"`   // create, update, delete
 	OldData    string `gorm:"type:longtext;comment:原始数据" json:"oldData"`     // JSON格式的原始数据
@@ -108,8 +113,8 @@ type SysDataLog struct {
 	UserAgent string `gorm:"type:varchar(500);comment:用户代理" json:"userAgent"`
 }
 
-// Table 指定表名
-func (SysDataLog) Table() string {
+// TableName 指定表名
+func (SysDataLog) TableName() string {
 	return "sys_data_log"
 }
 
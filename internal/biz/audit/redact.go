@@ -0,0 +1,340 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue 脱敏后的占位值
+const RedactedValue = "******"
+
+// maxRedactDepth JSON 嵌套及字符串内嵌 JSON 的最大处理深度
+const maxRedactDepth = 32
+
+// builtinSensitiveFields 内置敏感字段，比较时忽略大小写、下划线和连字符
+var builtinSensitiveFields = []string{
+	"password", "passwd", "pwd", "passphrase",
+	"secret", "secretkey", "secretaccesskey", "accesskeysecret", "clientsecret",
+	"token", "accesstoken", "refreshtoken", "apitoken", "apikey", "key",
+	"privatekey", "kubeconfig", "credential", "credentials",
+}
+
+// builtinSensitiveSuffixes 以这些后缀结尾的字段同样视为敏感，如 smtpPassword、dingtalkSecret
+var builtinSensitiveSuffixes = []string{
+	"password", "passwd", "secret", "token", "apikey", "secretkey", "privatekey", "kubeconfig", "passphrase",
+}
+
+// builtinRedactionPatterns 对字符串值、查询参数和错误信息生效的内置规则
+var builtinRedactionPatterns = []RedactionPattern{
+	// PEM 私钥，如证书导入、SSH 凭证
+	{Pattern: `-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?(-----END [A-Z0-9 ]*PRIVATE KEY-----|$)`, Replacement: "[REDACTED PRIVATE KEY]"},
+	// kubeconfig 中的客户端证书私钥、令牌和密码
+	{Pattern: `(?m)((?:client-key-data|client-certificate-data|token|password|id-token|refresh-token)\s*:\s*)\S+`, Replacement: "${1}" + RedactedValue},
+	// Authorization 头
+	{Pattern: `(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`, Replacement: "${1} " + RedactedValue},
+	// URL 中的用户密码，如数据库连接串
+	{Pattern: `(://[^:/@\s]+:)[^@/\s]+@`, Replacement: "${1}" + RedactedValue + "@"},
+	// 文本中的 key=value 形式
+	{Pattern: `(?i)\b((?:password|passwd|pwd|secret|token|api_?key|secret_?key|access_?key_?secret)\s*[=:]\s*)[^&\s,;"']+`, Replacement: "${1}" + RedactedValue},
+}
+
+// RedactionPattern 正则脱敏规则，匹配内容替换为 Replacement，为空时替换为 RedactedValue
+type RedactionPattern struct {
+	Pattern     string
+	Replacement string
+}
+
+// RedactionRules 在内置规则之外追加的脱敏规则
+type RedactionRules struct {
+	Fields     []string           // 敏感字段名
+	Patterns   []RedactionPattern // 正则规则
+	SkipRoutes []string           // 不脱敏的路由，格式 "METHOD /path" 或 "/path"，路径为路由模板如 /api/v1/hosts/:id
+}
+
+type compiledPattern struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Redactor 操作日志脱敏引擎，按字段名和正则规则替换请求参数、错误信息中的敏感内容
+type Redactor struct {
+	fields     map[string]bool
+	patterns   []compiledPattern
+	skipRoutes map[string]bool
+}
+
+// NewRedactor 创建脱敏引擎，内置规则始终生效
+func NewRedactor(rules RedactionRules) (*Redactor, error) {
+	r := &Redactor{
+		fields:     make(map[string]bool),
+		skipRoutes: make(map[string]bool),
+	}
+	for _, f := range builtinSensitiveFields {
+		r.fields[normalizeFieldName(f)] = true
+	}
+	for _, f := range rules.Fields {
+		if name := normalizeFieldName(f); name != "" {
+			r.fields[name] = true
+		}
+	}
+	for _, p := range append(append([]RedactionPattern{}, builtinRedactionPatterns...), rules.Patterns...) {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("脱敏规则 %q 无效: %w", p.Pattern, err)
+		}
+		replacement := p.Replacement
+		if replacement == "" {
+			replacement = RedactedValue
+		}
+		r.patterns = append(r.patterns, compiledPattern{re: re, replacement: replacement})
+	}
+	for _, route := range rules.SkipRoutes {
+		if route = strings.TrimSpace(route); route != "" {
+			r.skipRoutes[route] = true
+		}
+	}
+	return r, nil
+}
+
+// NewDefaultRedactor 创建只包含内置规则的脱敏引擎
+func NewDefaultRedactor() *Redactor {
+	r, err := NewRedactor(RedactionRules{})
+	if err != nil {
+		// 内置规则均可编译
+		panic(err)
+	}
+	return r
+}
+
+// SkipRoute 判断路由是否配置为不脱敏
+func (r *Redactor) SkipRoute(method, route string) bool {
+	if route == "" {
+		return false
+	}
+	return r.skipRoutes[route] || r.skipRoutes[method+" "+route]
+}
+
+// IsSensitiveField 判断字段名是否敏感
+func (r *Redactor) IsSensitiveField(name string) bool {
+	name = normalizeFieldName(name)
+	if name == "" {
+		return false
+	}
+	if r.fields[name] {
+		return true
+	}
+	for _, suffix := range builtinSensitiveSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactText 对任意文本应用正则规则，用于错误信息和无法解析的请求体
+func (r *Redactor) RedactText(s string) string {
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// RedactQuery 脱敏 URL 查询参数中的敏感字段
+func (r *Redactor) RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return r.RedactText(rawQuery)
+	}
+	r.redactValues(values)
+	// 占位值不转义，便于阅读
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(RedactedValue), RedactedValue)
+}
+
+// RedactBody 按 Content-Type 脱敏请求体，返回适合存入操作日志的字符串
+// multipart 请求只记录表单字段和文件元信息，不记录文件内容
+func (r *Redactor) RedactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "multipart/form-data":
+		if s, ok := r.redactMultipart(body, params["boundary"]); ok {
+			return s
+		}
+		return "[multipart body omitted]"
+	case mediaType == "application/x-www-form-urlencoded":
+		return r.RedactQuery(string(body))
+	}
+
+	if s, ok := r.RedactJSON(body); ok {
+		return s
+	}
+	return r.RedactText(string(body))
+}
+
+// RedactJSON 脱敏 JSON 文本，不是合法 JSON 时返回 false
+func (r *Redactor) RedactJSON(data []byte) (string, bool) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+	if dec.More() {
+		return "", false
+	}
+	out, err := json.Marshal(r.redactValue(v, 0))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func (r *Redactor) redactValue(v interface{}, depth int) interface{} {
+	if depth > maxRedactDepth {
+		return RedactedValue
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if r.IsSensitiveField(k) {
+				if item != nil && item != "" {
+					val[k] = RedactedValue
+				}
+				continue
+			}
+			val[k] = r.redactValue(item, depth+1)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = r.redactValue(item, depth+1)
+		}
+		return val
+	case string:
+		return r.redactString(val, depth)
+	default:
+		return val
+	}
+}
+
+// redactString 字符串内嵌的 JSON（如 DNS 服务商配置、告警通道配置）按字段脱敏，其余应用正则规则
+func (r *Redactor) redactString(s string, depth int) string {
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) > 1 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var nested interface{}
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		if err := dec.Decode(&nested); err == nil && !dec.More() {
+			if out, err := json.Marshal(r.redactValue(nested, depth+1)); err == nil {
+				return string(out)
+			}
+		}
+	}
+	return r.RedactText(s)
+}
+
+func (r *Redactor) redactValues(values url.Values) {
+	for k, vs := range values {
+		for i := range vs {
+			if r.IsSensitiveField(k) {
+				vs[i] = RedactedValue
+			} else {
+				vs[i] = r.RedactText(vs[i])
+			}
+		}
+	}
+}
+
+// multipartFile multipart 请求中文件的元信息
+type multipartFile struct {
+	Field    string `json:"field"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+func (r *Redactor) redactMultipart(body []byte, boundary string) (string, bool) {
+	if boundary == "" {
+		return "", false
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	fields := url.Values{}
+	var files []multipartFile
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false
+		}
+		if part.FileName() != "" {
+			size, _ := io.Copy(io.Discard, part)
+			files = append(files, multipartFile{Field: part.FormName(), Filename: part.FileName(), Size: size})
+			continue
+		}
+		value, err := io.ReadAll(io.LimitReader(part, 1<<20))
+		if err != nil {
+			return "", false
+		}
+		fields.Add(part.FormName(), string(value))
+	}
+	r.redactValues(fields)
+
+	result := map[string]interface{}{}
+	if len(fields) > 0 {
+		form := make(map[string]interface{}, len(fields))
+		for k, vs := range fields {
+			if len(vs) == 1 {
+				form[k] = vs[0]
+			} else {
+				form[k] = vs
+			}
+		}
+		result["form"] = form
+	}
+	if len(files) > 0 {
+		result["files"] = files
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+// normalizeFieldName 统一字段名：小写并去掉下划线、连字符，使 access_key_secret 与 accessKeySecret 等价
+func normalizeFieldName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "", ".", "").Replace(name)
+}
@@ -21,15 +21,27 @@ package audit
 
 import (
 	"context"
+	"time"
 )
 
+// OperationLogFilter 操作日志查询条件，列表和导出共用
+type OperationLogFilter struct {
+	Username     string
+	Module       string
+	Action       string
+	Status       string // 状态码或 2xx/3xx/4xx/5xx
+	ResourceType string
+	ResourceID   string
+	StartTime    string // 2006-01-02
+	EndTime      string // 2006-01-02，包含当天
+}
+
 // OperationLogRepo 操作日志仓储接口
 type OperationLogRepo interface {
 	Create(ctx context.Context, log *SysOperationLog) error
 	GetByID(ctx context.Context, id uint) (*SysOperationLog, error)
-	List(ctx context.Context, page, pageSize int, username, module, action, status, startTime, endTime string) ([]*SysOperationLog, int64, error)
-	Delete(ctx context.Context, id uint) error
-	DeleteBatch(ctx context.Context, ids []uint) error
+	List(ctx context.Context, page, pageSize int, filter OperationLogFilter) ([]*SysOperationLog, int64, error)
+	ListAll(ctx context.Context, filter OperationLogFilter, limit int) ([]*SysOperationLog, error)
 }
 
 // LoginLogRepo 登录日志仓储接口
@@ -37,9 +49,14 @@ type LoginLogRepo interface {
 	Create(ctx context.Context, log *SysLoginLog) error
 	GetByID(ctx context.Context, id uint) (*SysLoginLog, error)
 	List(ctx context.Context, page, pageSize int, username, loginType, loginStatus, startTime, endTime string) ([]*SysLoginLog, int64, error)
+	ListAll(ctx context.Context, username, loginType, loginStatus, startTime, endTime string, limit int) ([]*SysLoginLog, error)
 	UpdateLogout(ctx context.Context, userID uint, logoutTime *SysLoginLog) error
-	Delete(ctx context.Context, id uint) error
-	DeleteBatch(ctx context.Context, ids []uint) error
+	// CountFailedSince 统计 since 之后身份认证失败的次数，username 和 ip 为空时不作为条件
+	CountFailedSince(ctx context.Context, username, ip string, since time.Time) (int64, error)
+	// ListLoginIPs 返回用户在 since 之后登录成功使用过的 IP，不含 excludeID 对应的记录
+	ListLoginIPs(ctx context.Context, username string, since time.Time, excludeID uint) ([]string, error)
+	// LastSuccessBefore 返回用户在 beforeID 之前最近一次登录成功的记录，没有时返回 nil
+	LastSuccessBefore(ctx context.Context, username string, beforeID uint) (*SysLoginLog, error)
 }
 
 // DataLogRepo 数据日志仓储接口
@@ -47,6 +64,5 @@ type DataLogRepo interface {
 	Create(ctx context.Context, log *SysDataLog) error
 	GetByID(ctx context.Context, id uint) (*SysDataLog, error)
 	List(ctx context.Context, page, pageSize int, username, tableName, action, startTime, endTime string) ([]*SysDataLog, int64, error)
-	Delete(ctx context.Context, id uint) error
-	DeleteBatch(ctx context.Context, ids []uint) error
+	ListAll(ctx context.Context, username, tableName, action, startTime, endTime string, limit int) ([]*SysDataLog, error)
 }
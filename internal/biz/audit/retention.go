@@ -0,0 +1,608 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 可配置保留策略的日志类型，与审计链名称一致
+const (
+	LogTypeOperation = ChainOperationLog
+	LogTypeLogin     = ChainLoginLog
+	LogTypeData      = ChainDataLog
+)
+
+// 保留策略执行状态
+const (
+	RetentionStatusRunning = "running"
+	RetentionStatusSuccess = "success"
+	RetentionStatusFailed  = "failed"
+)
+
+const (
+	// retentionBatch 每个归档文件包含的最大记录数
+	retentionBatch = 5000
+	// retentionRunTimeout 执行标记超过该时间视为上次执行异常中断，允许重新执行
+	retentionRunTimeout = 6 * time.Hour
+	// importBatch 导入归档时每批写入的记录数
+	importBatch = 500
+)
+
+// DefaultRetentionPolicies 首次启动时创建的默认策略，默认不启用
+func DefaultRetentionPolicies() []*SysAuditRetentionPolicy {
+	return []*SysAuditRetentionPolicy{
+		{LogType: LogTypeOperation, RetentionDays: 180},
+		{LogType: LogTypeLogin, RetentionDays: 365},
+		{LogType: LogTypeData, RetentionDays: 365},
+	}
+}
+
+// SysAuditRetentionPolicy 审计日志保留策略，每种日志类型一条
+type SysAuditRetentionPolicy struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	LogType       string     `gorm:"type:varchar(50);uniqueIndex;not null;comment:日志类型" json:"logType"`
+	RetentionDays int        `gorm:"not null;default:180;comment:保留天数" json:"retentionDays"`
+	Enabled       bool       `gorm:"default:false;comment:是否启用" json:"enabled"`
+	LastRunAt     *time.Time `gorm:"comment:最后执行时间" json:"lastRunAt"`
+	LastStatus    string     `gorm:"type:varchar(20);comment:最后执行状态" json:"lastStatus"`
+	LastMessage   string     `gorm:"type:varchar(500);comment:最后执行信息" json:"lastMessage"`
+	LastArchived  int64      `gorm:"comment:最后一次归档记录数" json:"lastArchived"`
+	RunningSince  *time.Time `gorm:"comment:执行开始时间" json:"-"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// SysAuditArchive 审计日志归档文件，记录在清理审计记录的同一事务中写入
+// 链上归档的 FromSeq-ToSeq 为覆盖的链序号，未入链的历史记录归档时均为 0
+type SysAuditArchive struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	LogType       string     `gorm:"type:varchar(50);index;comment:日志类型" json:"logType"`
+	Chain         string     `gorm:"type:varchar(50);index;comment:审计链名称" json:"chain"`
+	Storage       string     `gorm:"type:varchar(20);comment:存储类型" json:"storage"`
+	ObjectKey     string     `gorm:"type:varchar(500);comment:归档文件路径" json:"objectKey"`
+	RecordCount   int64      `gorm:"comment:记录数" json:"recordCount"`
+	MissingCount  int64      `gorm:"comment:链记录对应审计记录缺失数" json:"missingCount"`
+	TamperedCount int64      `gorm:"comment:摘要不一致记录数" json:"tamperedCount"`
+	FromSeq       uint64     `gorm:"comment:起始链序号" json:"fromSeq"`
+	ToSeq         uint64     `gorm:"comment:结束链序号" json:"toSeq"`
+	LastHash      string     `gorm:"type:varchar(64);comment:结束链记录哈希" json:"lastHash"`
+	StartTime     *time.Time `gorm:"comment:最早记录时间" json:"startTime"`
+	EndTime       *time.Time `gorm:"comment:最晚记录时间" json:"endTime"`
+	Cutoff        time.Time  `gorm:"comment:保留截止时间" json:"cutoff"`
+	FileSize      int64      `gorm:"comment:文件大小" json:"fileSize"`
+	SHA256        string     `gorm:"column:sha256;type:varchar(64);comment:文件SHA256" json:"sha256"`
+	Signature     string     `gorm:"type:varchar(64);comment:HMAC-SHA256签名" json:"signature"`
+	ImportedAt    *time.Time `gorm:"comment:导入调查时间" json:"importedAt"`
+	ImportedCount int64      `gorm:"comment:已导入记录数" json:"importedCount"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// SysAuditArchiveRecord 为调查导入的归档记录，与在线审计表隔离，不入链、可随时卸载
+type SysAuditArchiveRecord struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ArchiveID  uint       `gorm:"index;comment:归档ID" json:"archiveId"`
+	RecordID   uint       `gorm:"comment:原审计记录ID" json:"recordId"`
+	Username   string     `gorm:"type:varchar(50);index;comment:用户名" json:"username"`
+	RecordTime *time.Time `gorm:"index;comment:记录时间" json:"recordTime"`
+	Data       string     `gorm:"type:longtext;comment:记录内容" json:"data"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (SysAuditRetentionPolicy) TableName() string {
+	return "sys_audit_retention_policy"
+}
+
+func (SysAuditArchive) TableName() string {
+	return "sys_audit_archive"
+}
+
+func (SysAuditArchiveRecord) TableName() string {
+	return "sys_audit_archive_record"
+}
+
+// ArchiveLine 归档文件（gzip 压缩的 JSON Lines）中的一行
+// 链上记录保留序号和哈希，可以脱离数据库重新计算摘要和哈希链
+type ArchiveLine struct {
+	Seq      uint64                 `json:"seq,omitempty"`
+	RecordID uint                   `json:"recordId"`
+	Digest   string                 `json:"digest,omitempty"`
+	PrevHash string                 `json:"prevHash,omitempty"`
+	Hash     string                 `json:"hash,omitempty"`
+	Missing  bool                   `json:"missing,omitempty"`
+	Tampered bool                   `json:"tampered,omitempty"`
+	Record   map[string]interface{} `json:"record,omitempty"`
+}
+
+// ArchiveStorage 归档文件存储
+type ArchiveStorage interface {
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// AuditRetentionRepo 保留策略和归档仓储接口
+type AuditRetentionRepo interface {
+	EnsurePolicies(ctx context.Context, policies []*SysAuditRetentionPolicy) error
+	ListPolicies(ctx context.Context) ([]*SysAuditRetentionPolicy, error)
+	GetPolicy(ctx context.Context, logType string) (*SysAuditRetentionPolicy, error)
+	UpdatePolicy(ctx context.Context, logType string, retentionDays int, enabled bool) error
+	// AcquireRun 标记策略开始执行，已有未超时的执行时返回 false
+	AcquireRun(ctx context.Context, logType string, staleBefore time.Time) (bool, error)
+	FinishRun(ctx context.Context, logType, status, message string, archived int64) error
+	// FirstSeqSince 返回链上创建时间不早于 since 的最小序号，没有时返回 0
+	FirstSeqSince(ctx context.Context, chain string, since time.Time) (uint64, error)
+	// ListLegacy 返回创建时间早于 before 且未入链的审计记录ID
+	ListLegacy(ctx context.Context, chain AuditChain, before time.Time, limit int) ([]uint, error)
+	// PurgeArchived 在同一事务中删除已归档的审计记录和链记录、推进链头截断位置并保存归档记录
+	PurgeArchived(ctx context.Context, chain AuditChain, archive *SysAuditArchive, recordIDs []uint) error
+	ListArchives(ctx context.Context, page, pageSize int, logType string) ([]*SysAuditArchive, int64, error)
+	GetArchive(ctx context.Context, id uint) (*SysAuditArchive, error)
+	ImportArchiveRecords(ctx context.Context, records []*SysAuditArchiveRecord) error
+	MarkArchiveImported(ctx context.Context, archiveID uint, importedAt *time.Time, count int64) error
+	DeleteArchiveRecords(ctx context.Context, archiveID uint) error
+	ListArchiveRecords(ctx context.Context, archiveID uint, page, pageSize int, username, keyword string) ([]*SysAuditArchiveRecord, int64, error)
+}
+
+// SignArchive 计算归档签名，覆盖文件摘要和链上截断位置
+func SignArchive(key []byte, a *SysAuditArchive) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s|%s|%s|%d|%d|%d|%s|%s|%d", a.LogType, a.Chain, a.ObjectKey, a.RecordCount, a.FromSeq, a.ToSeq, a.LastHash, a.SHA256, a.CreatedAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditRetentionUseCase 审计日志保留用例：按策略归档并清理过期记录，管理归档文件
+type AuditRetentionUseCase struct {
+	repo       AuditRetentionRepo
+	chainRepo  AuditChainRepo
+	chains     map[string]AuditChain
+	storage    ArchiveStorage
+	signingKey []byte
+}
+
+func NewAuditRetentionUseCase(repo AuditRetentionRepo, chainRepo AuditChainRepo, chains []AuditChain, storage ArchiveStorage, signingKey string) *AuditRetentionUseCase {
+	uc := &AuditRetentionUseCase{
+		repo:       repo,
+		chainRepo:  chainRepo,
+		chains:     make(map[string]AuditChain),
+		storage:    storage,
+		signingKey: []byte(signingKey),
+	}
+	for _, p := range DefaultRetentionPolicies() {
+		for _, chain := range chains {
+			if chain.Name == p.LogType {
+				uc.chains[p.LogType] = chain
+			}
+		}
+	}
+	return uc
+}
+
+// EnsureDefaultPolicies 创建缺失的默认策略，已有策略不修改
+func (uc *AuditRetentionUseCase) EnsureDefaultPolicies(ctx context.Context) error {
+	return uc.repo.EnsurePolicies(ctx, DefaultRetentionPolicies())
+}
+
+func (uc *AuditRetentionUseCase) ListPolicies(ctx context.Context) ([]*SysAuditRetentionPolicy, error) {
+	return uc.repo.ListPolicies(ctx)
+}
+
+// UpdatePolicy 修改保留天数和启用状态
+func (uc *AuditRetentionUseCase) UpdatePolicy(ctx context.Context, logType string, retentionDays int, enabled bool) error {
+	if _, ok := uc.chains[logType]; !ok {
+		return fmt.Errorf("不支持的日志类型: %s", logType)
+	}
+	if retentionDays < 1 {
+		return fmt.Errorf("保留天数至少为 1 天")
+	}
+	return uc.repo.UpdatePolicy(ctx, logType, retentionDays, enabled)
+}
+
+// Run 立即执行一次保留策略，在后台完成，结果记录在策略的最后执行状态中
+func (uc *AuditRetentionUseCase) Run(ctx context.Context, logType string) error {
+	policy, err := uc.acquire(ctx, logType)
+	if err != nil {
+		return err
+	}
+	go uc.execute(context.Background(), policy)
+	return nil
+}
+
+// RunAll 依次执行所有启用的保留策略，由定时任务调用
+func (uc *AuditRetentionUseCase) RunAll(ctx context.Context) {
+	policies, err := uc.repo.ListPolicies(ctx)
+	if err != nil {
+		appLogger.Error("查询审计日志保留策略失败", zap.Error(err))
+		return
+	}
+	for _, p := range policies {
+		if !p.Enabled {
+			continue
+		}
+		policy, err := uc.acquire(ctx, p.LogType)
+		if err != nil {
+			appLogger.Warn("跳过审计日志保留策略", zap.String("logType", p.LogType), zap.Error(err))
+			continue
+		}
+		uc.execute(ctx, policy)
+	}
+}
+
+func (uc *AuditRetentionUseCase) acquire(ctx context.Context, logType string) (*SysAuditRetentionPolicy, error) {
+	if _, ok := uc.chains[logType]; !ok {
+		return nil, fmt.Errorf("不支持的日志类型: %s", logType)
+	}
+	policy, err := uc.repo.GetPolicy(ctx, logType)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("保留策略不存在: %s", logType)
+	}
+	ok, err := uc.repo.AcquireRun(ctx, logType, time.Now().Add(-retentionRunTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("保留策略正在执行中")
+	}
+	return policy, nil
+}
+
+func (uc *AuditRetentionUseCase) execute(ctx context.Context, policy *SysAuditRetentionPolicy) {
+	chain := uc.chains[policy.LogType]
+	cutoff := time.Now().AddDate(0, 0, -policy.RetentionDays)
+
+	legacy, err := uc.archiveLegacy(ctx, chain, cutoff)
+	archived := legacy
+	if err == nil {
+		var chained int64
+		chained, err = uc.archiveChained(ctx, chain, cutoff)
+		archived += chained
+	}
+
+	status, message := RetentionStatusSuccess, fmt.Sprintf("已归档并清理 %d 条 %s 之前的记录", archived, cutoff.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		status, message = RetentionStatusFailed, err.Error()
+		appLogger.Error("执行审计日志保留策略失败", zap.String("logType", policy.LogType), zap.Int64("archived", archived), zap.Error(err))
+	} else if archived > 0 {
+		appLogger.Info("审计日志保留策略执行完成", zap.String("logType", policy.LogType), zap.Int64("archived", archived))
+	}
+	if err := uc.repo.FinishRun(ctx, policy.LogType, status, message, archived); err != nil {
+		appLogger.Error("更新审计日志保留策略状态失败", zap.String("logType", policy.LogType), zap.Error(err))
+	}
+}
+
+// archiveLegacy 归档启用审计链之前写入、未入链的过期记录
+// 审计链启用后写入却未入链的记录可能是被绕过应用插入的，保留在线上供完整性校验发现
+func (uc *AuditRetentionUseCase) archiveLegacy(ctx context.Context, chain AuditChain, cutoff time.Time) (int64, error) {
+	head, err := uc.chainRepo.GetHead(ctx, chain.Name)
+	if err != nil {
+		return 0, err
+	}
+	before := cutoff
+	if head != nil && head.CreatedAt.Before(before) {
+		before = head.CreatedAt
+	}
+
+	var archived int64
+	for {
+		ids, err := uc.repo.ListLegacy(ctx, chain, before, retentionBatch)
+		if err != nil {
+			return archived, err
+		}
+		if len(ids) == 0 {
+			return archived, nil
+		}
+		records, err := uc.chainRepo.LoadRecords(ctx, chain.Table, ids)
+		if err != nil {
+			return archived, err
+		}
+		lines := make([]ArchiveLine, 0, len(ids))
+		for _, id := range ids {
+			if record, ok := records[id]; ok {
+				lines = append(lines, ArchiveLine{RecordID: id, Record: NormalizeRow(record.Row)})
+			}
+		}
+		archive := &SysAuditArchive{LogType: chain.Name, Chain: chain.Name, Cutoff: cutoff}
+		if err := uc.store(ctx, chain, archive, lines, ids); err != nil {
+			return archived, err
+		}
+		archived += archive.RecordCount
+	}
+}
+
+// archiveChained 按链序号顺序归档过期记录，清理后链头记录截断位置，校验从截断处接续
+func (uc *AuditRetentionUseCase) archiveChained(ctx context.Context, chain AuditChain, cutoff time.Time) (int64, error) {
+	head, err := uc.chainRepo.GetHead(ctx, chain.Name)
+	if err != nil || head == nil {
+		return 0, err
+	}
+	// 截止时间之后的第一条链记录之前的部分全部过期
+	upTo := head.LastSeq
+	first, err := uc.repo.FirstSeqSince(ctx, chain.Name, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if first > 0 {
+		upTo = first - 1
+	}
+
+	var archived int64
+	afterSeq := head.PrunedSeq
+	for afterSeq < upTo {
+		limit := retentionBatch
+		if remain := upTo - afterSeq; remain < uint64(limit) {
+			limit = int(remain)
+		}
+		entries, err := uc.chainRepo.ListEntries(ctx, chain.Name, afterSeq, limit)
+		if err != nil {
+			return archived, err
+		}
+		if len(entries) == 0 {
+			return archived, nil
+		}
+		ids := make([]uint, 0, len(entries))
+		for _, e := range entries {
+			ids = append(ids, e.RecordID)
+		}
+		records, err := uc.chainRepo.LoadRecords(ctx, chain.Table, ids)
+		if err != nil {
+			return archived, err
+		}
+
+		archive := &SysAuditArchive{LogType: chain.Name, Chain: chain.Name, Cutoff: cutoff, FromSeq: afterSeq + 1}
+		lines := make([]ArchiveLine, 0, len(entries))
+		for _, e := range entries {
+			line := ArchiveLine{Seq: e.Seq, RecordID: e.RecordID, Digest: e.Digest, PrevHash: e.PrevHash, Hash: e.Hash}
+			if record, ok := records[e.RecordID]; !ok {
+				line.Missing = true
+				archive.MissingCount++
+			} else {
+				line.Record = NormalizeRow(record.Row)
+				if RecordDigest(record.Row, chain) != e.Digest {
+					line.Tampered = true
+					archive.TamperedCount++
+				}
+			}
+			lines = append(lines, line)
+			archive.ToSeq, archive.LastHash = e.Seq, e.Hash
+		}
+		if err := uc.store(ctx, chain, archive, lines, ids); err != nil {
+			return archived, err
+		}
+		archived += archive.RecordCount
+		afterSeq = archive.ToSeq
+	}
+	return archived, nil
+}
+
+// store 写出归档文件并上传，成功后签名归档记录并清理对应的审计记录
+func (uc *AuditRetentionUseCase) store(ctx context.Context, chain AuditChain, archive *SysAuditArchive, lines []ArchiveLine, recordIDs []uint) error {
+	tmp, err := os.CreateTemp("", "audit-archive-*.jsonl.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, hasher)}
+	gz := gzip.NewWriter(counter)
+	enc := json.NewEncoder(gz)
+	for i := range lines {
+		if err := enc.Encode(&lines[i]); err != nil {
+			return err
+		}
+		if lines[i].Missing {
+			continue
+		}
+		archive.RecordCount++
+		if t, ok := recordTime(lines[i].Record); ok {
+			if archive.StartTime == nil || t.Before(*archive.StartTime) {
+				archive.StartTime = &t
+			}
+			if archive.EndTime == nil || t.After(*archive.EndTime) {
+				archive.EndTime = &t
+			}
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// 精确到秒，数据库读出后签名内容不变
+	now := time.Now().Truncate(time.Second)
+	archive.Storage = uc.storage.Name()
+	archive.ObjectKey = fmt.Sprintf("%s/%s/%s-%d-%d-%s.jsonl.gz", archive.LogType, now.Format("2006/01"), archive.LogType, archive.FromSeq, archive.ToSeq, now.Format("20060102150405"))
+	archive.FileSize = counter.n
+	archive.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	archive.CreatedAt = now
+	archive.Signature = SignArchive(uc.signingKey, archive)
+
+	if err := uc.storage.Put(ctx, archive.ObjectKey, tmp, archive.FileSize); err != nil {
+		return fmt.Errorf("上传归档文件失败: %w", err)
+	}
+	return uc.repo.PurgeArchived(ctx, chain, archive, recordIDs)
+}
+
+func (uc *AuditRetentionUseCase) ListArchives(ctx context.Context, page, pageSize int, logType string) ([]*SysAuditArchive, int64, error) {
+	return uc.repo.ListArchives(ctx, page, pageSize, logType)
+}
+
+// OpenArchive 打开归档文件用于下载
+func (uc *AuditRetentionUseCase) OpenArchive(ctx context.Context, id uint) (*SysAuditArchive, io.ReadCloser, error) {
+	archive, err := uc.repo.GetArchive(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if archive.Storage != uc.storage.Name() {
+		return nil, nil, fmt.Errorf("归档文件保存在 %s 存储，当前配置为 %s", archive.Storage, uc.storage.Name())
+	}
+	r, err := uc.storage.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, r, nil
+}
+
+// ImportArchive 校验归档文件摘要后将记录导入调查表，已导入的先卸载
+func (uc *AuditRetentionUseCase) ImportArchive(ctx context.Context, id uint) (int64, error) {
+	archive, err := uc.repo.GetArchive(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if len(uc.signingKey) > 0 && !hmac.Equal([]byte(SignArchive(uc.signingKey, archive)), []byte(archive.Signature)) {
+		return 0, fmt.Errorf("归档签名无效，归档记录可能被篡改")
+	}
+	if err := uc.checkArchiveFile(ctx, archive); err != nil {
+		return 0, err
+	}
+	if err := uc.UnloadArchive(ctx, id); err != nil {
+		return 0, err
+	}
+
+	_, r, err := uc.OpenArchive(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	var imported int64
+	batch := make([]*SysAuditArchiveRecord, 0, importBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := uc.repo.ImportArchiveRecords(ctx, batch); err != nil {
+			return err
+		}
+		imported += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var line ArchiveLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return imported, fmt.Errorf("解析归档文件失败: %w", err)
+		}
+		if line.Record == nil {
+			continue
+		}
+		data, _ := json.Marshal(line.Record)
+		record := &SysAuditArchiveRecord{ArchiveID: id, RecordID: line.RecordID, Data: string(data)}
+		if username, ok := line.Record["username"].(string); ok {
+			record.Username = username
+		}
+		if t, ok := recordTime(line.Record); ok {
+			record.RecordTime = &t
+		}
+		batch = append(batch, record)
+		if len(batch) >= importBatch {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, err
+	}
+	if err := flush(); err != nil {
+		return imported, err
+	}
+	now := time.Now()
+	return imported, uc.repo.MarkArchiveImported(ctx, id, &now, imported)
+}
+
+// checkArchiveFile 重新计算归档文件的 SHA256，与归档记录比对
+func (uc *AuditRetentionUseCase) checkArchiveFile(ctx context.Context, archive *SysAuditArchive) error {
+	r, err := uc.storage.Get(ctx, archive.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != archive.SHA256 {
+		return fmt.Errorf("归档文件摘要不一致，文件可能被篡改")
+	}
+	return nil
+}
+
+// UnloadArchive 删除为调查导入的记录
+func (uc *AuditRetentionUseCase) UnloadArchive(ctx context.Context, id uint) error {
+	if err := uc.repo.DeleteArchiveRecords(ctx, id); err != nil {
+		return err
+	}
+	return uc.repo.MarkArchiveImported(ctx, id, nil, 0)
+}
+
+func (uc *AuditRetentionUseCase) ListArchiveRecords(ctx context.Context, id uint, page, pageSize int, username, keyword string) ([]*SysAuditArchiveRecord, int64, error) {
+	return uc.repo.ListArchiveRecords(ctx, id, page, pageSize, username, keyword)
+}
+
+// recordTime 读取归档行中的创建时间
+func recordTime(record map[string]interface{}) (time.Time, bool) {
+	s, ok := record["created_at"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.Local(), true
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
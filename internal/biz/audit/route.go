@@ -0,0 +1,182 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 常用操作类型，模块和操作用于操作日志筛选
+const (
+	ActionQuery   = "查询"
+	ActionCreate  = "创建"
+	ActionUpdate  = "更新"
+	ActionDelete  = "删除"
+	ActionLogin   = "登录"
+	ActionLogout  = "登出"
+	ActionExecute = "执行"
+	ActionImport  = "导入"
+	ActionExport  = "导出"
+	ActionGrant   = "授权"
+	ActionRevoke  = "撤销"
+	ActionApprove = "审批"
+	ActionTest    = "测试"
+	ActionSync    = "同步"
+	ActionDeploy  = "部署"
+	ActionUpload  = "上传"
+	ActionConnect = "连接"
+)
+
+// RouteAudit 接口的审计元数据，路由挂载时登记，操作日志据此记录“谁对哪个资源做了什么”
+// Description 和 ResourceID 可以引用请求中的值：
+//   - path.<参数>   路由参数，如 path.id
+//   - query.<参数>  查询参数
+//   - body.<字段>   JSON 请求体字段，支持 a.b 和数组下标 a.0.b
+//   - resp.<字段>   JSON 响应字段，如创建接口返回的 resp.data.id
+//
+// Description 中以 {表达式} 引用；ResourceID 直接写表达式，多个用 | 分隔，取第一个非空值
+type RouteAudit struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"` // gin 路由模式，例如 /api/v1/users/:id
+	Module       string `json:"module"`
+	Action       string `json:"action"`
+	Description  string `json:"description"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+}
+
+// templateExpr 描述模板中的 {表达式}
+var templateExpr = regexp.MustCompile(`\{((?:path|query|body|resp)\.[A-Za-z0-9_.\-]+)\}`)
+
+// NeedsResponse 元数据是否引用响应字段，需要时由中间件缓存响应体
+func (r RouteAudit) NeedsResponse() bool {
+	return strings.Contains(r.Description, "{resp.") || strings.Contains(r.ResourceID, "resp.")
+}
+
+// RouteValues 渲染审计元数据时的取值来源
+type RouteValues struct {
+	Path  func(name string) string
+	Query func(name string) string
+	Body  interface{} // 已脱敏的请求体
+	Resp  interface{}
+}
+
+// Lookup 按表达式取值，取不到时返回空
+func (v RouteValues) Lookup(expr string) string {
+	source, key, ok := strings.Cut(strings.TrimSpace(expr), ".")
+	if !ok || key == "" {
+		return ""
+	}
+	switch source {
+	case "path":
+		if v.Path != nil {
+			return v.Path(key)
+		}
+	case "query":
+		if v.Query != nil {
+			return v.Query(key)
+		}
+	case "body":
+		return lookupJSONPath(v.Body, key)
+	case "resp":
+		return lookupJSONPath(v.Resp, key)
+	}
+	return ""
+}
+
+// Render 生成操作描述和资源ID，描述中取不到的值留空
+func (r RouteAudit) Render(values RouteValues) (description, resourceID string) {
+	description = templateExpr.ReplaceAllStringFunc(r.Description, func(m string) string {
+		return values.Lookup(m[1 : len(m)-1])
+	})
+	description = strings.Join(strings.Fields(description), " ")
+	for _, expr := range strings.Split(r.ResourceID, "|") {
+		if id := values.Lookup(expr); id != "" {
+			resourceID = id
+			break
+		}
+	}
+	return description, resourceID
+}
+
+// lookupJSONPath 按 a.b.0.c 形式在解析后的 JSON 中取值，数组和对象输出为 JSON
+func lookupJSONPath(v interface{}, path string) string {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v = node[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return ""
+			}
+			v = node[i]
+		default:
+			return ""
+		}
+	}
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+// RouteAuditRegistry 接口到审计元数据的映射表
+type RouteAuditRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]RouteAudit
+}
+
+// NewRouteAuditRegistry 创建审计元数据映射表
+func NewRouteAuditRegistry() *RouteAuditRegistry {
+	return &RouteAuditRegistry{routes: make(map[string]RouteAudit)}
+}
+
+// Register 登记接口审计元数据，同一接口重复登记时以后登记的为准
+func (r *RouteAuditRegistry) Register(routes ...RouteAudit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, route := range routes {
+		r.routes[route.Method+" "+route.Path] = route
+	}
+}
+
+// Lookup 查找接口的审计元数据
+func (r *RouteAuditRegistry) Lookup(method, fullPath string) (RouteAudit, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	route, ok := r.routes[method+" "+fullPath]
+	return route, ok
+}
@@ -0,0 +1,617 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 登录安全事件类型
+const (
+	SecurityEventBruteForceUser   = "brute_force_user"
+	SecurityEventBruteForceIP     = "brute_force_ip"
+	SecurityEventNewIP            = "new_ip"
+	SecurityEventNewCountry       = "new_country"
+	SecurityEventImpossibleTravel = "impossible_travel"
+	SecurityEventOffHours         = "off_hours"
+)
+
+// 安全事件等级
+const (
+	SecurityLevelInfo     = "info"
+	SecurityLevelWarning  = "warning"
+	SecurityLevelCritical = "critical"
+)
+
+// 安全事件处理状态
+const (
+	SecurityStatusOpen     = "open"
+	SecurityStatusResolved = "resolved"
+	SecurityStatusIgnored  = "ignored"
+)
+
+// SecurityEventNames 安全事件类型名称
+var SecurityEventNames = map[string]string{
+	SecurityEventBruteForceUser:   "用户暴力破解",
+	SecurityEventBruteForceIP:     "IP暴力破解",
+	SecurityEventNewIP:            "新IP登录",
+	SecurityEventNewCountry:       "新国家/地区登录",
+	SecurityEventImpossibleTravel: "不可能旅行",
+	SecurityEventOffHours:         "非工作时间登录",
+}
+
+// securityLevelRank 等级高低，用于判断是否发送告警
+var securityLevelRank = map[string]int{
+	SecurityLevelInfo:     1,
+	SecurityLevelWarning:  2,
+	SecurityLevelCritical: 3,
+}
+
+// NonAuthLoginTypes 登录日志中不属于身份认证的记录类型，不参与异常检测
+var NonAuthLoginTypes = []string{"logout", "mfa_disable", "mfa_reset"}
+
+const (
+	defaultBruteForceWindow        = 10 * time.Minute
+	defaultBruteForceUserThreshold = 5
+	defaultBruteForceIPThreshold   = 20
+	defaultLoginHistoryWindow      = 90 * 24 * time.Hour
+	// minTravelDistanceKm 两地距离小于该值时不判断不可能旅行，避免 IP 库定位误差造成误报
+	minTravelDistanceKm = 500
+	// loginAnomalyQueueSize 待检测登录记录的队列长度
+	loginAnomalyQueueSize = 1024
+)
+
+// SysSecurityEvent 登录异常检测发现的安全事件
+type SysSecurityEvent struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType   string     `gorm:"type:varchar(30);index;comment:事件类型" json:"eventType"`
+	Level       string     `gorm:"type:varchar(20);index;comment:等级" json:"level"`
+	UserID      uint       `gorm:"index;comment:用户ID" json:"userId"`
+	Username    string     `gorm:"type:varchar(50);index;comment:用户名" json:"username"`
+	IP          string     `gorm:"type:varchar(50);index;comment:IP地址" json:"ip"`
+	Location    string     `gorm:"type:varchar(100);comment:登录地点" json:"location"`
+	LoginLogID  uint       `gorm:"index;comment:触发的登录日志ID" json:"loginLogId"`
+	Title       string     `gorm:"type:varchar(200);comment:事件摘要" json:"title"`
+	Detail      string     `gorm:"type:text;comment:检测依据" json:"detail"`
+	Status      string     `gorm:"type:varchar(20);index;default:open;comment:处理状态" json:"status"`
+	Notified    bool       `gorm:"default:false;comment:是否已发送告警" json:"notified"`
+	NotifyError string     `gorm:"type:varchar(500);comment:告警发送失败原因" json:"notifyError,omitempty"`
+	HandledBy   uint       `gorm:"comment:处理人ID" json:"handledBy"`
+	HandlerName string     `gorm:"type:varchar(50);comment:处理人" json:"handlerName"`
+	HandledAt   *time.Time `gorm:"comment:处理时间" json:"handledAt"`
+	Remark      string     `gorm:"type:varchar(500);comment:处理备注" json:"remark"`
+	CreatedAt   time.Time  `gorm:"index" json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (SysSecurityEvent) TableName() string {
+	return "sys_security_event"
+}
+
+// SecurityEventFilter 安全事件查询条件
+type SecurityEventFilter struct {
+	EventType string
+	Level     string
+	Status    string
+	Username  string
+	IP        string
+	StartTime string
+	EndTime   string
+}
+
+// SecurityEventStats 安全事件统计
+type SecurityEventStats struct {
+	Open         int64            `json:"open"`
+	OpenCritical int64            `json:"openCritical"`
+	Recent       int64            `json:"recent"` // 最近 7 天
+	RecentByType map[string]int64 `json:"recentByType"`
+}
+
+// SecurityEventRepo 安全事件仓储接口
+type SecurityEventRepo interface {
+	Create(ctx context.Context, event *SysSecurityEvent) error
+	GetByID(ctx context.Context, id uint) (*SysSecurityEvent, error)
+	List(ctx context.Context, page, pageSize int, filter SecurityEventFilter) ([]*SysSecurityEvent, int64, error)
+	// ExistsSince 是否已有 since 之后的同类事件，username 和 ip 为空时不作为条件
+	ExistsSince(ctx context.Context, eventType, username, ip string, since time.Time) (bool, error)
+	UpdateNotify(ctx context.Context, id uint, notified bool, notifyError string) error
+	Handle(ctx context.Context, id uint, status string, handledBy uint, handlerName, remark string, handledAt time.Time) error
+	Stats(ctx context.Context, since time.Time) (*SecurityEventStats, error)
+}
+
+// SecurityNotifier 安全事件告警发送器，复用监控中心的告警通道
+type SecurityNotifier interface {
+	Notify(ctx context.Context, title, content string, userIDs []uint) error
+}
+
+// WorkHours 工作时间段，End 早于 Start 时表示跨零点
+type WorkHours struct {
+	Start    int // 距零点的分钟数
+	End      int
+	Weekdays map[time.Weekday]bool
+	Location *time.Location
+}
+
+// NewWorkHours 解析工作时间，start/end 为 HH:MM，weekdays 取 1-7 表示周一到周日，为空时为周一到周五
+func NewWorkHours(start, end string, weekdays []int, timezone string) (*WorkHours, error) {
+	w := &WorkHours{Weekdays: make(map[time.Weekday]bool), Location: time.Local}
+	var err error
+	if w.Start, err = parseClock(start); err != nil {
+		return nil, err
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return nil, err
+	}
+	if w.Start == w.End {
+		return nil, fmt.Errorf("工作时间开始和结束不能相同")
+	}
+	if len(weekdays) == 0 {
+		weekdays = []int{1, 2, 3, 4, 5}
+	}
+	for _, d := range weekdays {
+		if d < 1 || d > 7 {
+			return nil, fmt.Errorf("无效的工作日: %d", d)
+		}
+		w.Weekdays[time.Weekday(d%7)] = true
+	}
+	if timezone != "" {
+		if w.Location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("无效的时区 %s: %w", timezone, err)
+		}
+	}
+	return w, nil
+}
+
+// Contains 时间是否在工作时间内，跨零点的时段按开始那天的工作日判断
+func (w *WorkHours) Contains(t time.Time) bool {
+	t = t.In(w.Location)
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return w.Weekdays[t.Weekday()] && minute >= w.Start && minute < w.End
+	}
+	if minute >= w.Start {
+		return w.Weekdays[t.Weekday()]
+	}
+	return minute < w.End && w.Weekdays[t.AddDate(0, 0, -1).Weekday()]
+}
+
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if !ok || err1 != nil || err2 != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("无效的时间 %q，应为 HH:MM", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// LoginAnomalyRules 登录异常检测规则，阈值为 0 时使用默认值
+type LoginAnomalyRules struct {
+	BruteForceWindow        time.Duration
+	BruteForceUserThreshold int
+	BruteForceIPThreshold   int
+	NewIP                   bool
+	NewCountry              bool
+	HistoryWindow           time.Duration // 判断新 IP、新国家时回看的登录历史
+	TravelSpeedKmh          float64       // 两次登录间移动速度超过该值视为不可能旅行，0 表示不检测
+	WorkHours               *WorkHours    // 为 nil 时不检测非工作时间登录
+	NotifyLevel             string        // 发送告警的最低等级，默认 warning
+	NotifyUserIDs           []uint        // 额外接收告警的系统用户
+}
+
+// LoginAnomalyDetector 登录异常检测：登录日志写入后异步检测，发现的事件保存并通过告警通道发送
+type LoginAnomalyDetector struct {
+	logs     LoginLogRepo
+	events   SecurityEventRepo
+	locator  GeoLocator
+	notifier SecurityNotifier
+	rules    LoginAnomalyRules
+	queue    chan *SysLoginLog
+}
+
+// NewLoginAnomalyDetector 创建登录异常检测器，locator 和 notifier 可以为 nil
+func NewLoginAnomalyDetector(logs LoginLogRepo, events SecurityEventRepo, locator GeoLocator, notifier SecurityNotifier, rules LoginAnomalyRules) *LoginAnomalyDetector {
+	if rules.BruteForceWindow <= 0 {
+		rules.BruteForceWindow = defaultBruteForceWindow
+	}
+	if rules.BruteForceUserThreshold == 0 {
+		rules.BruteForceUserThreshold = defaultBruteForceUserThreshold
+	}
+	if rules.BruteForceIPThreshold == 0 {
+		rules.BruteForceIPThreshold = defaultBruteForceIPThreshold
+	}
+	if rules.HistoryWindow <= 0 {
+		rules.HistoryWindow = defaultLoginHistoryWindow
+	}
+	if _, ok := securityLevelRank[rules.NotifyLevel]; !ok {
+		rules.NotifyLevel = SecurityLevelWarning
+	}
+	return &LoginAnomalyDetector{
+		logs:     logs,
+		events:   events,
+		locator:  locator,
+		notifier: notifier,
+		rules:    rules,
+		queue:    make(chan *SysLoginLog, loginAnomalyQueueSize),
+	}
+}
+
+// Locate 解析 IP 所在地，返回位置和登录日志中展示的地点
+// 内网地址地点为“内网”，没有 IP 库或库中没有记录时均为空
+func (d *LoginAnomalyDetector) Locate(ip string) (*GeoLocation, string) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return nil, ""
+	}
+	if IsPrivateIP(parsed) {
+		return nil, LocationPrivate
+	}
+	if d.locator == nil {
+		return nil, ""
+	}
+	loc, err := d.locator.Lookup(parsed)
+	if err != nil {
+		appLogger.Debug("解析IP地理位置失败", zap.String("ip", ip), zap.Error(err))
+		return nil, ""
+	}
+	return loc, loc.String()
+}
+
+// Submit 提交登录日志等待检测，不阻塞调用方，队列满时丢弃
+func (d *LoginAnomalyDetector) Submit(log *SysLoginLog) {
+	if isNonAuthLogin(log.LoginType) {
+		return
+	}
+	select {
+	case d.queue <- log:
+	default:
+		appLogger.Warn("登录异常检测队列已满，跳过检测", zap.Uint("loginLogId", log.ID), zap.String("username", log.Username))
+	}
+}
+
+// Run 处理检测队列，ctx 结束时返回
+func (d *LoginAnomalyDetector) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case log := <-d.queue:
+			events, err := d.Detect(ctx, log)
+			if err != nil {
+				appLogger.Error("登录异常检测失败", zap.Uint("loginLogId", log.ID), zap.Error(err))
+			}
+			for _, event := range events {
+				d.raise(ctx, event)
+			}
+		}
+	}
+}
+
+// Detect 按规则检测一条登录日志，返回发现的安全事件，事件尚未保存
+func (d *LoginAnomalyDetector) Detect(ctx context.Context, log *SysLoginLog) ([]*SysSecurityEvent, error) {
+	switch {
+	case isNonAuthLogin(log.LoginType):
+		return nil, nil
+	case log.LoginStatus == "failed":
+		return d.detectBruteForce(ctx, log)
+	case log.LoginStatus != "success" || log.Username == "":
+		return nil, nil
+	}
+
+	var events []*SysSecurityEvent
+	var errs []error
+	loginTime := loginTimeOf(log)
+	current, _ := d.Locate(log.IP)
+
+	if d.rules.NewIP || d.rules.NewCountry {
+		found, err := d.detectNewSource(ctx, log, current)
+		events = append(events, found...)
+		errs = append(errs, err)
+	}
+	if d.rules.TravelSpeedKmh > 0 && current != nil && current.HasCoordinates {
+		event, err := d.detectImpossibleTravel(ctx, log, current)
+		if event != nil {
+			events = append(events, event)
+		}
+		errs = append(errs, err)
+	}
+	if d.rules.WorkHours != nil && !d.rules.WorkHours.Contains(loginTime) {
+		events = append(events, newSecurityEvent(log, SecurityEventOffHours, SecurityLevelWarning,
+			fmt.Sprintf("用户 %s 在非工作时间 %s 登录", log.Username, loginTime.In(d.rules.WorkHours.Location).Format("2006-01-02 15:04")),
+			map[string]interface{}{"loginTime": loginTime, "weekday": loginTime.In(d.rules.WorkHours.Location).Weekday().String()}))
+	}
+	return events, errors.Join(errs...)
+}
+
+// detectBruteForce 统计窗口内同一用户、同一 IP 的失败次数，达到阈值时每个窗口只产生一次事件
+func (d *LoginAnomalyDetector) detectBruteForce(ctx context.Context, log *SysLoginLog) ([]*SysSecurityEvent, error) {
+	since := loginTimeOf(log).Add(-d.rules.BruteForceWindow)
+	window := humanDuration(d.rules.BruteForceWindow)
+	checks := []struct {
+		eventType string
+		level     string
+		username  string
+		ip        string
+		threshold int
+		title     string
+	}{
+		{SecurityEventBruteForceUser, SecurityLevelWarning, log.Username, "", d.rules.BruteForceUserThreshold, "用户 " + log.Username},
+		{SecurityEventBruteForceIP, SecurityLevelCritical, "", log.IP, d.rules.BruteForceIPThreshold, "IP " + log.IP},
+	}
+
+	var events []*SysSecurityEvent
+	var errs []error
+	for _, check := range checks {
+		if check.threshold < 0 || check.username == "" && check.ip == "" {
+			continue
+		}
+		failures, err := d.logs.CountFailedSince(ctx, check.username, check.ip, since)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if failures < int64(check.threshold) {
+			continue
+		}
+		exists, err := d.events.ExistsSince(ctx, check.eventType, check.username, check.ip, since)
+		if err != nil || exists {
+			errs = append(errs, err)
+			continue
+		}
+		events = append(events, newSecurityEvent(log, check.eventType, check.level,
+			fmt.Sprintf("%s 在 %s 内登录失败 %d 次", check.title, window, failures),
+			map[string]interface{}{"failures": failures, "window": window, "threshold": check.threshold, "lastReason": log.FailReason}))
+	}
+	return events, errors.Join(errs...)
+}
+
+// detectNewSource 与回看期内登录成功使用过的 IP 比较，用户没有历史登录时不判断
+func (d *LoginAnomalyDetector) detectNewSource(ctx context.Context, log *SysLoginLog, current *GeoLocation) ([]*SysSecurityEvent, error) {
+	knownIPs, err := d.logs.ListLoginIPs(ctx, log.Username, loginTimeOf(log).Add(-d.rules.HistoryWindow), log.ID)
+	if err != nil || len(knownIPs) == 0 {
+		return nil, err
+	}
+	for _, ip := range knownIPs {
+		if ip == log.IP {
+			return nil, nil
+		}
+	}
+
+	var events []*SysSecurityEvent
+	if d.rules.NewIP {
+		events = append(events, newSecurityEvent(log, SecurityEventNewIP, SecurityLevelInfo,
+			fmt.Sprintf("用户 %s 从未使用过的 IP %s 登录", log.Username, log.IP),
+			map[string]interface{}{"knownIps": len(knownIPs), "historyDays": int(d.rules.HistoryWindow.Hours() / 24)}))
+	}
+	if d.rules.NewCountry && current != nil && current.CountryKey() != "" {
+		countries := make(map[string]string)
+		for _, ip := range knownIPs {
+			if loc, _ := d.Locate(ip); loc != nil && loc.CountryKey() != "" {
+				countries[loc.CountryKey()] = loc.Country
+			}
+		}
+		if _, seen := countries[current.CountryKey()]; !seen && len(countries) > 0 {
+			names := make([]string, 0, len(countries))
+			for _, name := range countries {
+				names = append(names, name)
+			}
+			events = append(events, newSecurityEvent(log, SecurityEventNewCountry, SecurityLevelWarning,
+				fmt.Sprintf("用户 %s 首次从 %s 登录", log.Username, current.Country),
+				map[string]interface{}{"country": current.Country, "knownCountries": names}))
+		}
+	}
+	return events, nil
+}
+
+// detectImpossibleTravel 与上一次成功登录的地点比较，按距离和时间差计算移动速度
+func (d *LoginAnomalyDetector) detectImpossibleTravel(ctx context.Context, log *SysLoginLog, current *GeoLocation) (*SysSecurityEvent, error) {
+	prev, err := d.logs.LastSuccessBefore(ctx, log.Username, log.ID)
+	if err != nil || prev == nil || prev.IP == log.IP {
+		return nil, err
+	}
+	prevLoc, _ := d.Locate(prev.IP)
+	if prevLoc == nil || !prevLoc.HasCoordinates {
+		return nil, nil
+	}
+	distance := DistanceKm(prevLoc, current)
+	if distance < minTravelDistanceKm {
+		return nil, nil
+	}
+	elapsed := loginTimeOf(log).Sub(loginTimeOf(prev))
+	if elapsed < time.Minute {
+		elapsed = time.Minute
+	}
+	speed := distance / elapsed.Hours()
+	if speed <= d.rules.TravelSpeedKmh {
+		return nil, nil
+	}
+	return newSecurityEvent(log, SecurityEventImpossibleTravel, SecurityLevelCritical,
+		fmt.Sprintf("用户 %s 在 %s 内从 %s 到 %s 登录，相距约 %.0f 公里", log.Username, humanDuration(elapsed), prevLoc.String(), current.String(), distance),
+		map[string]interface{}{
+			"previousLoginLogId": prev.ID,
+			"previousIp":         prev.IP,
+			"previousLocation":   prevLoc.String(),
+			"previousLoginTime":  loginTimeOf(prev),
+			"distanceKm":         int(distance),
+			"speedKmh":           int(speed),
+		}), nil
+}
+
+// raise 保存事件，转发到审计事件目标，达到告警等级时发送告警
+func (d *LoginAnomalyDetector) raise(ctx context.Context, event *SysSecurityEvent) {
+	if err := d.events.Create(ctx, event); err != nil {
+		appLogger.Error("保存安全事件失败", zap.String("eventType", event.EventType), zap.String("username", event.Username), zap.Error(err))
+		return
+	}
+	appLogger.Warn("发现登录安全事件",
+		zap.Uint("id", event.ID),
+		zap.String("eventType", event.EventType),
+		zap.String("username", event.Username),
+		zap.String("ip", event.IP),
+		zap.String("title", event.Title),
+	)
+
+	var detail map[string]interface{}
+	_ = json.Unmarshal([]byte(event.Detail), &detail)
+	PublishEvent(&AuditEvent{
+		Type:     EventTypeSecurity,
+		Time:     event.CreatedAt,
+		UserID:   event.UserID,
+		Username: event.Username,
+		IP:       event.IP,
+		Action:   event.EventType,
+		Target:   fmt.Sprintf("login_log/%d", event.LoginLogID),
+		Outcome:  EventOutcomeFailure,
+		Detail:   map[string]interface{}{"level": event.Level, "title": event.Title, "location": event.Location, "evidence": detail},
+	})
+
+	if d.notifier == nil || securityLevelRank[event.Level] < securityLevelRank[d.rules.NotifyLevel] {
+		return
+	}
+	userIDs := append([]uint{}, d.rules.NotifyUserIDs...)
+	if event.UserID != 0 {
+		userIDs = append(userIDs, event.UserID)
+	}
+	notifyErr := d.notifier.Notify(ctx, "登录安全告警: "+SecurityEventNames[event.EventType], formatSecurityNotice(event), userIDs)
+	message := ""
+	if notifyErr != nil {
+		message = notifyErr.Error()
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		appLogger.Error("发送安全事件告警失败", zap.Uint("id", event.ID), zap.Error(notifyErr))
+	}
+	if err := d.events.UpdateNotify(ctx, event.ID, notifyErr == nil, message); err != nil {
+		appLogger.Error("更新安全事件告警状态失败", zap.Uint("id", event.ID), zap.Error(err))
+	}
+}
+
+// formatSecurityNotice 生成告警正文
+func formatSecurityNotice(event *SysSecurityEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", event.Title)
+	fmt.Fprintf(&b, "事件类型: %s\n", SecurityEventNames[event.EventType])
+	fmt.Fprintf(&b, "等级: %s\n", event.Level)
+	if event.Username != "" {
+		fmt.Fprintf(&b, "用户: %s\n", event.Username)
+	}
+	if event.Location != "" {
+		fmt.Fprintf(&b, "IP: %s (%s)\n", event.IP, event.Location)
+	} else {
+		fmt.Fprintf(&b, "IP: %s\n", event.IP)
+	}
+	fmt.Fprintf(&b, "如非本人操作，请及时修改密码并在 操作审计-安全事件 中处理")
+	return b.String()
+}
+
+func newSecurityEvent(log *SysLoginLog, eventType, level, title string, detail map[string]interface{}) *SysSecurityEvent {
+	data, _ := json.Marshal(detail)
+	if len(title) > 200 {
+		title = title[:200]
+	}
+	return &SysSecurityEvent{
+		EventType:  eventType,
+		Level:      level,
+		UserID:     log.UserID,
+		Username:   log.Username,
+		IP:         log.IP,
+		Location:   log.Location,
+		LoginLogID: log.ID,
+		Title:      title,
+		Detail:     string(data),
+		Status:     SecurityStatusOpen,
+	}
+}
+
+func isNonAuthLogin(loginType string) bool {
+	for _, t := range NonAuthLoginTypes {
+		if t == loginType {
+			return true
+		}
+	}
+	return false
+}
+
+func loginTimeOf(log *SysLoginLog) time.Time {
+	if !log.LoginTime.IsZero() {
+		return log.LoginTime
+	}
+	if !log.CreatedAt.IsZero() {
+		return log.CreatedAt
+	}
+	return time.Now()
+}
+
+// humanDuration 以分钟、小时或天展示时长
+func humanDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d分钟", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return strconv.FormatFloat(d.Hours(), 'f', 1, 64) + "小时"
+	default:
+		return fmt.Sprintf("%d天", int(d.Hours()/24))
+	}
+}
+
+// SecurityEventUseCase 安全事件查询和处理
+type SecurityEventUseCase struct {
+	repo SecurityEventRepo
+}
+
+func NewSecurityEventUseCase(repo SecurityEventRepo) *SecurityEventUseCase {
+	return &SecurityEventUseCase{repo: repo}
+}
+
+func (uc *SecurityEventUseCase) List(ctx context.Context, page, pageSize int, filter SecurityEventFilter) ([]*SysSecurityEvent, int64, error) {
+	return uc.repo.List(ctx, page, pageSize, filter)
+}
+
+func (uc *SecurityEventUseCase) GetByID(ctx context.Context, id uint) (*SysSecurityEvent, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+// Stats 统计未处理事件和最近 7 天的事件分布
+func (uc *SecurityEventUseCase) Stats(ctx context.Context) (*SecurityEventStats, error) {
+	return uc.repo.Stats(ctx, time.Now().AddDate(0, 0, -7))
+}
+
+// Handle 处理安全事件，标记为已处理、已忽略或重新打开
+func (uc *SecurityEventUseCase) Handle(ctx context.Context, id uint, status string, handledBy uint, handlerName, remark string) error {
+	switch status {
+	case SecurityStatusOpen, SecurityStatusResolved, SecurityStatusIgnored:
+	default:
+		return fmt.Errorf("无效的处理状态: %s", status)
+	}
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("安全事件不存在")
+	}
+	return uc.repo.Handle(ctx, id, status, handledBy, handlerName, remark, time.Now())
+}
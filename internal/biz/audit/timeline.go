@@ -0,0 +1,265 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 内置的时间线来源
+const (
+	TimelineSourceOperation  = "operation"
+	TimelineSourceLogin      = "login"
+	TimelineSourceSecurity   = "security"
+	TimelineSourceSSHSession = "ssh_session"
+)
+
+// 时间线详情类型，前端据此决定如何展示 DetailURL 的内容
+const (
+	TimelineDetailJSON      = "json"      // 标准响应，展示 data
+	TimelineDetailRecording = "recording" // asciinema 录制文件，使用播放器回放
+)
+
+// 时间线条数限制
+const (
+	DefaultTimelineLimit = 1000
+	MaxTimelineLimit     = 5000
+)
+
+// TimelineQuery 活动时间线查询条件，用户和资源至少指定一个
+// 用户优先按 UserID 匹配，未指定时按 Username 匹配
+// 资源由各来源自行解释，不认识的资源类型返回空
+type TimelineQuery struct {
+	UserID       uint
+	Username     string
+	ResourceType string
+	ResourceID   string
+	StartTime    time.Time
+	EndTime      time.Time // 不含
+	Ascending    bool      // 按时间正序，默认倒序
+	Limit        int       // 每个来源最多返回的条数
+}
+
+// HasUser 是否按用户查询
+func (q TimelineQuery) HasUser() bool {
+	return q.UserID > 0 || q.Username != ""
+}
+
+// HasResource 是否按资源查询
+func (q TimelineQuery) HasResource() bool {
+	return q.ResourceType != "" && q.ResourceID != ""
+}
+
+// TimelineScope 按时间范围、用户、排序和条数查询来源表，用于 db.Scopes
+// usernameColumn 为空时通过 sys_user 将用户名换算为 userIDColumn 匹配
+func TimelineScope(query TimelineQuery, timeColumn, userIDColumn, usernameColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(timeColumn+" >= ? AND "+timeColumn+" < ?", query.StartTime, query.EndTime)
+		switch {
+		case query.UserID > 0:
+			db = db.Where(userIDColumn+" = ?", query.UserID)
+		case query.Username != "" && usernameColumn != "":
+			db = db.Where(usernameColumn+" = ?", query.Username)
+		case query.Username != "":
+			db = db.Where(userIDColumn+" IN (SELECT id FROM sys_user WHERE username = ?)", query.Username)
+		}
+		if query.Ascending {
+			return db.Order(timeColumn + " ASC").Order("id ASC").Limit(query.Limit)
+		}
+		return db.Order(timeColumn + " DESC").Order("id DESC").Limit(query.Limit)
+	}
+}
+
+// TimelineEntry 归一化后的时间线条目
+type TimelineEntry struct {
+	Source       string     `json:"source"`
+	SourceName   string     `json:"sourceName"`
+	RecordID     uint       `json:"recordId"`
+	Time         time.Time  `json:"time"`
+	EndTime      *time.Time `json:"endTime,omitempty"` // 会话类记录的结束时间
+	UserID       uint       `json:"userId"`
+	Username     string     `json:"username"`
+	Action       string     `json:"action"`
+	Title        string     `json:"title"`
+	ResourceType string     `json:"resourceType"`
+	ResourceID   string     `json:"resourceId"`
+	Status       string     `json:"status"`
+	IP           string     `json:"ip"`
+	DetailKind   string     `json:"detailKind"` // json/recording，为空表示没有详情
+	DetailURL    string     `json:"detailUrl"`  // 详情接口，如录制回放、任务输出
+	Link         string     `json:"link"`       // 前端对应的页面
+}
+
+// TimelineSource 时间线数据来源，内置来源和插件来源都实现该接口
+type TimelineSource interface {
+	// Name 来源标识，如 operation、ssh_session
+	Name() string
+	// Label 来源名称，用于展示和导出
+	Label() string
+	// Timeline 按条件查询记录，按 query.Ascending 排序，最多返回 query.Limit 条
+	Timeline(ctx context.Context, query TimelineQuery) ([]*TimelineEntry, error)
+}
+
+// TimelineSourceError 单个来源查询失败，不影响其他来源
+type TimelineSourceError struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+// TimelineResult 合并后的时间线
+type TimelineResult struct {
+	Entries   []*TimelineEntry      `json:"entries"`
+	Truncated bool                  `json:"truncated"` // 超过条数限制，只返回了部分记录
+	Errors    []TimelineSourceError `json:"errors"`
+}
+
+// TimelineUseCase 活动时间线用例，合并操作日志、登录日志、终端会话和插件提供的记录
+type TimelineUseCase struct {
+	mu      sync.RWMutex
+	sources []TimelineSource
+}
+
+func NewTimelineUseCase(sources ...TimelineSource) *TimelineUseCase {
+	uc := &TimelineUseCase{}
+	uc.Register(sources...)
+	return uc
+}
+
+// Register 登记时间线来源，同名来源后登记的覆盖先登记的
+func (uc *TimelineUseCase) Register(sources ...TimelineSource) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for _, source := range sources {
+		replaced := false
+		for i, existing := range uc.sources {
+			if existing.Name() == source.Name() {
+				uc.sources[i] = source
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			uc.sources = append(uc.sources, source)
+		}
+	}
+}
+
+// TimelineSourceInfo 时间线来源信息
+type TimelineSourceInfo struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// Sources 返回已登记的来源
+func (uc *TimelineUseCase) Sources() []TimelineSourceInfo {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	infos := make([]TimelineSourceInfo, 0, len(uc.sources))
+	for _, source := range uc.sources {
+		infos = append(infos, TimelineSourceInfo{Name: source.Name(), Label: source.Label()})
+	}
+	return infos
+}
+
+// Query 并发查询各来源并按时间合并，sources 为空时查询全部来源
+func (uc *TimelineUseCase) Query(ctx context.Context, query TimelineQuery, sources []string) (*TimelineResult, error) {
+	if !query.HasUser() && !query.HasResource() {
+		return nil, fmt.Errorf("请指定用户或资源")
+	}
+	if query.StartTime.IsZero() || query.EndTime.IsZero() || !query.EndTime.After(query.StartTime) {
+		return nil, fmt.Errorf("时间范围无效")
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultTimelineLimit
+	}
+	if query.Limit > MaxTimelineLimit {
+		query.Limit = MaxTimelineLimit
+	}
+	limit := query.Limit
+	// 多取一条用于判断是否被截断
+	query.Limit++
+
+	selected := uc.selectSources(sources)
+	results := make([][]*TimelineEntry, len(selected))
+	errs := make([]error, len(selected))
+	var wg sync.WaitGroup
+	for i, source := range selected {
+		wg.Add(1)
+		go func(i int, source TimelineSource) {
+			defer wg.Done()
+			results[i], errs[i] = source.Timeline(ctx, query)
+		}(i, source)
+	}
+	wg.Wait()
+
+	result := &TimelineResult{Entries: make([]*TimelineEntry, 0), Errors: make([]TimelineSourceError, 0)}
+	for i, source := range selected {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, TimelineSourceError{Source: source.Name(), Message: errs[i].Error()})
+			continue
+		}
+		if len(results[i]) > limit {
+			result.Truncated = true
+		}
+		for _, entry := range results[i] {
+			entry.Source = source.Name()
+			entry.SourceName = source.Label()
+		}
+		result.Entries = append(result.Entries, results[i]...)
+	}
+
+	sort.SliceStable(result.Entries, func(a, b int) bool {
+		if query.Ascending {
+			return result.Entries[a].Time.Before(result.Entries[b].Time)
+		}
+		return result.Entries[a].Time.After(result.Entries[b].Time)
+	})
+	if len(result.Entries) > limit {
+		result.Entries = result.Entries[:limit]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// selectSources 按标识筛选来源，未登记的标识忽略
+func (uc *TimelineUseCase) selectSources(names []string) []TimelineSource {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if len(names) == 0 {
+		return append([]TimelineSource(nil), uc.sources...)
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	selected := make([]TimelineSource, 0, len(names))
+	for _, source := range uc.sources {
+		if wanted[source.Name()] {
+			selected = append(selected, source)
+		}
+	}
+	return selected
+}
@@ -21,8 +21,12 @@ package audit
 
 import (
 	"context"
+	"fmt"
 )
 
+// MaxExportRows 单次导出的最大记录数
+const MaxExportRows = 100000
+
 // OperationLogUseCase 操作日志用例
 type OperationLogUseCase struct {
 	repo OperationLogRepo
@@ -42,16 +46,20 @@ func (uc *OperationLogUseCase) GetByID(ctx context.Context, id uint) (*SysOperat
 	return uc.repo.GetByID(ctx, id)
 }
 
-func (uc *OperationLogUseCase) List(ctx context.Context, page, pageSize int, username, module, action, status, startTime, endTime string) ([]*SysOperationLog, int64, error) {
-	return uc.repo.List(ctx, page, pageSize, username, module, action, status, startTime, endTime)
-}
-
-func (uc *OperationLogUseCase) Delete(ctx context.Context, id uint) error {
-	return uc.repo.Delete(ctx, id)
+func (uc *OperationLogUseCase) List(ctx context.Context, page, pageSize int, filter OperationLogFilter) ([]*SysOperationLog, int64, error) {
+	return uc.repo.List(ctx, page, pageSize, filter)
 }
 
-func (uc *OperationLogUseCase) DeleteBatch(ctx context.Context, ids []uint) error {
-	return uc.repo.DeleteBatch(ctx, ids)
+// Export 按条件查询全部记录用于导出，超过上限时提示缩小范围
+func (uc *OperationLogUseCase) Export(ctx context.Context, filter OperationLogFilter) ([]*SysOperationLog, error) {
+	logs, err := uc.repo.ListAll(ctx, filter, MaxExportRows+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > MaxExportRows {
+		return nil, fmt.Errorf("导出记录超过 %d 条，请缩小查询范围", MaxExportRows)
+	}
+	return logs, nil
 }
 
 // LoginLogUseCase 登录日志用例
@@ -77,16 +85,20 @@ func (uc *LoginLogUseCase) List(ctx context.Context, page, pageSize int, usernam
 	return uc.repo.List(ctx, page, pageSize, username, loginType, loginStatus, startTime, endTime)
 }
 
-func (uc *LoginLogUseCase) UpdateLogout(ctx context.Context, userID uint, logoutTime *SysLoginLog) error {
-	return uc.repo.UpdateLogout(ctx, userID, logoutTime)
-}
-
-func (uc *LoginLogUseCase) Delete(ctx context.Context, id uint) error {
-	return uc.repo.Delete(ctx, id)
+// Export 按条件查询全部记录用于导出，超过上限时提示缩小范围
+func (uc *LoginLogUseCase) Export(ctx context.Context, username, loginType, loginStatus, startTime, endTime string) ([]*SysLoginLog, error) {
+	logs, err := uc.repo.ListAll(ctx, username, loginType, loginStatus, startTime, endTime, MaxExportRows+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > MaxExportRows {
+		return nil, fmt.Errorf("导出记录超过 %d 条，请缩小查询范围", MaxExportRows)
+	}
+	return logs, nil
 }
 
-func (uc *LoginLogUseCase) DeleteBatch(ctx context.Context, ids []uint) error {
-	return uc.repo.DeleteBatch(ctx, ids)
+func (uc *LoginLogUseCase) UpdateLogout(ctx context.Context, userID uint, logoutTime *SysLoginLog) error {
+	return uc.repo.UpdateLogout(ctx, userID, logoutTime)
 }
 
 // DataLogUseCase 数据日志用例
@@ -112,10 +124,14 @@ func (uc *DataLogUseCase) List(ctx context.Context, page, pageSize int, username
 	return uc.repo.List(ctx, page, pageSize, username, tableName, action, startTime, endTime)
 }
 
-func (uc *DataLogUseCase) Delete(ctx context.Context, id uint) error {
-	return uc.repo.Delete(ctx, id)
-}
-
-func (uc *DataLogUseCase) DeleteBatch(ctx context.Context, ids []uint) error {
-	return uc.repo.DeleteBatch(ctx, ids)
+// Export 按条件查询全部记录用于导出，超过上限时提示缩小范围
+func (uc *DataLogUseCase) Export(ctx context.Context, username, tableName, action, startTime, endTime string) ([]*SysDataLog, error) {
+	logs, err := uc.repo.ListAll(ctx, username, tableName, action, startTime, endTime, MaxExportRows+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > MaxExportRows {
+		return nil, fmt.Errorf("导出记录超过 %d 条，请缩小查询范围", MaxExportRows)
+	}
+	return logs, nil
 }
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+)
+
+// 访问申请的资源类型
+const (
+	AccessResourceHost    = "host"    // 主机（终端/文件权限）
+	AccessResourceCluster = "cluster" // Kubernetes 集群角色
+)
+
+// 访问申请状态
+const (
+	AccessStatusPending   = "pending"   // 待审批
+	AccessStatusApproved  = "approved"  // 已批准（授权生效中）
+	AccessStatusRejected  = "rejected"  // 已驳回
+	AccessStatusCancelled = "cancelled" // 申请人已撤回
+	AccessStatusExpired   = "expired"   // 授权已到期
+	AccessStatusRevoked   = "revoked"   // 授权被提前收回
+)
+
+// 访问申请事件
+const (
+	AccessEventSubmit  = "submit"
+	AccessEventApprove = "approve"
+	AccessEventReject  = "reject"
+	AccessEventCancel  = "cancel"
+	AccessEventRevoke  = "revoke"
+	AccessEventExpire  = "expire"
+	AccessEventNotify  = "notify"
+)
+
+// 审批人配置的作用范围
+const (
+	ApproverScopeAssetGroup = "asset_group" // 按资产分组
+	ApproverScopeDepartment = "department"  // 按申请人所在部门（含上级部门）
+)
+
+// 访问申请可申请的主机权限及最长时长
+const (
+	AccessRequestablePermissions = PermissionView | PermissionTerminal | PermissionFile
+	MaxAccessDurationMinutes     = 7 * 24 * 60
+)
+
+// SysAccessRequest 访问申请
+type SysAccessRequest struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	UserID          uint       `gorm:"not null;index;comment:申请人ID" json:"userId"`
+	Username        string     `gorm:"type:varchar(50);comment:申请人用户名" json:"username"`
+	ResourceType    string     `gorm:"type:varchar(20);not null;index;comment:资源类型 host/cluster" json:"resourceType"`
+	AssetGroupID    uint       `gorm:"index;comment:资产分组ID" json:"assetGroupId"`
+	HostIDs         UintArray  `gorm:"type:json;comment:主机ID列表" json:"hostIds"`
+	Permissions     uint       `gorm:"type:int unsigned;default:0;comment:申请的主机权限位掩码" json:"permissions"`
+	ClusterID       uint       `gorm:"index;comment:集群ID" json:"clusterId"`
+	RoleName        string     `gorm:"type:varchar(255);comment:集群角色名称" json:"roleName"`
+	RoleNamespace   string     `gorm:"type:varchar(255);comment:角色命名空间" json:"roleNamespace"`
+	RoleType        string     `gorm:"type:varchar(50);comment:ClusterRole/Role" json:"roleType"`
+	Reason          string     `gorm:"type:varchar(500);not null;comment:申请原因" json:"reason"`
+	DurationMinutes int        `gorm:"not null;comment:申请时长（分钟）" json:"durationMinutes"`
+	Status          string     `gorm:"type:varchar(20);not null;index;comment:状态" json:"status"`
+	ApproverIDs     UintArray  `gorm:"type:json;comment:可审批人ID列表" json:"approverIds"`
+	ReviewerID      uint       `gorm:"comment:审批人ID" json:"reviewerId"`
+	ReviewerName    string     `gorm:"type:varchar(50);comment:审批人用户名" json:"reviewerName"`
+	ReviewComment   string     `gorm:"type:varchar(500);comment:审批意见" json:"reviewComment"`
+	ReviewedAt      *time.Time `json:"reviewedAt"`
+	GrantID         uint       `gorm:"comment:审批通过后创建的资产授权ID" json:"grantId"`
+	StartAt         *time.Time `json:"startAt"`
+	ExpiresAt       *time.Time `gorm:"index" json:"expiresAt"`
+}
+
+// TableName 指定表名
+func (SysAccessRequest) TableName() string {
+	return "sys_access_request"
+}
+
+// SysAccessRequestEvent 访问申请流转记录
+type SysAccessRequestEvent struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `json:"createdAt"`
+	RequestID    uint      `gorm:"not null;index;comment:访问申请ID" json:"requestId"`
+	Action       string    `gorm:"type:varchar(20);not null;comment:动作" json:"action"`
+	OperatorID   uint      `gorm:"comment:操作人ID，0表示系统" json:"operatorId"`
+	OperatorName string    `gorm:"type:varchar(50);comment:操作人" json:"operatorName"`
+	Comment      string    `gorm:"type:varchar(1000);comment:说明" json:"comment"`
+}
+
+// TableName 指定表名
+func (SysAccessRequestEvent) TableName() string {
+	return "sys_access_request_event"
+}
+
+// SysAccessApprover 访问申请审批人配置
+type SysAccessApprover struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ScopeType string    `gorm:"type:varchar(20);not null;uniqueIndex:uk_access_approver;comment:范围类型 asset_group/department" json:"scopeType"`
+	ScopeID   uint      `gorm:"not null;uniqueIndex:uk_access_approver;comment:资产分组ID或部门ID" json:"scopeId"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uk_access_approver;comment:审批人ID" json:"userId"`
+	Username  string    `gorm:"->;-:migration" json:"username"`
+	RealName  string    `gorm:"->;-:migration" json:"realName"`
+}
+
+// TableName 指定表名
+func (SysAccessApprover) TableName() string {
+	return "sys_access_approver"
+}
+
+// AccessRequestCreateReq 提交访问申请请求
+type AccessRequestCreateReq struct {
+	ResourceType    string `json:"resourceType" binding:"required,oneof=host cluster"`
+	AssetGroupID    uint   `json:"assetGroupId"`
+	HostIDs         []uint `json:"hostIds"`
+	Permissions     uint   `json:"permissions"`
+	ClusterID       uint   `json:"clusterId"`
+	RoleName        string `json:"roleName"`
+	RoleNamespace   string `json:"roleNamespace"`
+	RoleType        string `json:"roleType" binding:"omitempty,oneof=ClusterRole Role"`
+	Reason          string `json:"reason" binding:"required,max=500"`
+	DurationMinutes int    `json:"durationMinutes" binding:"required,min=1"`
+}
+
+// AccessRequestReviewReq 审批/收回请求
+type AccessRequestReviewReq struct {
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// AccessRequestListReq 访问申请列表请求
+type AccessRequestListReq struct {
+	Page         int    `form:"page"`
+	PageSize     int    `form:"pageSize"`
+	Scope        string `form:"scope"` // mine: 我的申请; todo: 待我审批; all: 全部（管理员）
+	Status       string `form:"status"`
+	ResourceType string `form:"resourceType"`
+}
+
+// AccessApproverReq 设置审批人请求
+type AccessApproverReq struct {
+	ScopeType string `json:"scopeType" binding:"required,oneof=asset_group department"`
+	ScopeID   uint   `json:"scopeId" binding:"required"`
+	UserIDs   []uint `json:"userIds"`
+}
+
+// AccessRequestDetailVO 访问申请详情（含流转记录）
+type AccessRequestDetailVO struct {
+	SysAccessRequest
+	PermissionNames []string                 `json:"permissionNames"`
+	Events          []*SysAccessRequestEvent `json:"events"`
+}
+
+// AccessNotifier 访问申请通知（通过告警通道发送）
+type AccessNotifier interface {
+	Notify(ctx context.Context, title, content string, userIDs []uint) error
+}
+
+// ClusterRoleBinder Kubernetes 集群角色绑定
+type ClusterRoleBinder interface {
+	BindClusterRole(ctx context.Context, clusterID, userID uint, roleName, roleNamespace, roleType string, boundBy uint) error
+	UnbindClusterRole(ctx context.Context, clusterID, userID uint, roleName, roleNamespace string) error
+}
@@ -0,0 +1,442 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrAccessRequestReviewed 申请已被其他审批人处理
+var ErrAccessRequestReviewed = errors.New("该申请已处理")
+
+// AccessRequestUseCase 访问申请用例
+type AccessRequestUseCase struct {
+	repo      AccessRequestRepo
+	grantRepo AssetPermissionRepo
+	binder    ClusterRoleBinder
+	notifier  AccessNotifier
+}
+
+// NewAccessRequestUseCase 创建访问申请用例
+func NewAccessRequestUseCase(repo AccessRequestRepo, grantRepo AssetPermissionRepo) *AccessRequestUseCase {
+	return &AccessRequestUseCase{
+		repo:      repo,
+		grantRepo: grantRepo,
+	}
+}
+
+// SetClusterRoleBinder 设置集群角色绑定器
+func (uc *AccessRequestUseCase) SetClusterRoleBinder(binder ClusterRoleBinder) {
+	uc.binder = binder
+}
+
+// SetNotifier 设置通知器
+func (uc *AccessRequestUseCase) SetNotifier(notifier AccessNotifier) {
+	uc.notifier = notifier
+}
+
+// Submit 提交访问申请
+func (uc *AccessRequestUseCase) Submit(ctx context.Context, userID uint, username string, req *AccessRequestCreateReq) (*SysAccessRequest, error) {
+	if req.DurationMinutes > MaxAccessDurationMinutes {
+		return nil, fmt.Errorf("申请时长不能超过 %d 天", MaxAccessDurationMinutes/60/24)
+	}
+
+	ar := &SysAccessRequest{
+		UserID:          userID,
+		Username:        username,
+		ResourceType:    req.ResourceType,
+		Reason:          strings.TrimSpace(req.Reason),
+		DurationMinutes: req.DurationMinutes,
+		Status:          AccessStatusPending,
+	}
+
+	switch req.ResourceType {
+	case AccessResourceHost:
+		if req.AssetGroupID == 0 {
+			return nil, errors.New("请选择资产分组")
+		}
+		if req.Permissions&^AccessRequestablePermissions != 0 {
+			return nil, errors.New("只能申请查看、终端和文件管理权限")
+		}
+		if req.Permissions&(PermissionTerminal|PermissionFile) == 0 {
+			return nil, errors.New("请至少选择终端或文件管理权限")
+		}
+		ar.AssetGroupID = req.AssetGroupID
+		ar.HostIDs = req.HostIDs
+		ar.Permissions = req.Permissions | PermissionView
+	case AccessResourceCluster:
+		if req.ClusterID == 0 || req.RoleName == "" {
+			return nil, errors.New("请选择集群和角色")
+		}
+		ar.ClusterID = req.ClusterID
+		ar.RoleName = req.RoleName
+		ar.RoleType = req.RoleType
+		if ar.RoleType == "" {
+			ar.RoleType = "ClusterRole"
+		}
+		if ar.RoleType == "Role" {
+			if req.RoleNamespace == "" {
+				return nil, errors.New("Role 类型必须指定命名空间")
+			}
+			ar.RoleNamespace = req.RoleNamespace
+		}
+	}
+
+	approvers, err := uc.repo.ResolveApprovers(ctx, ar.AssetGroupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 申请人不能审批自己的申请
+	for _, id := range approvers {
+		if id != userID {
+			ar.ApproverIDs = append(ar.ApproverIDs, id)
+		}
+	}
+	if len(ar.ApproverIDs) == 0 {
+		return nil, errors.New("未找到可用的审批人，请联系管理员配置")
+	}
+
+	if err := uc.repo.Create(ctx, ar); err != nil {
+		return nil, err
+	}
+	uc.addEvent(ctx, ar.ID, AccessEventSubmit, userID, username, ar.Reason)
+	uc.notify(ctx, ar, "待审批的访问申请", fmt.Sprintf("%s 申请%s，时长 %s，原因: %s", username, describeAccess(ar), formatDuration(ar.DurationMinutes), ar.Reason), ar.ApproverIDs)
+
+	return ar, nil
+}
+
+// Approve 批准访问申请并创建临时授权
+func (uc *AccessRequestUseCase) Approve(ctx context.Context, id, reviewerID uint, reviewerName, comment string) error {
+	ar, err := uc.reviewable(ctx, id, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(ar.DurationMinutes) * time.Minute)
+
+	var grant *SysAssetGrant
+	var bind func(ctx context.Context) error
+	switch ar.ResourceType {
+	case AccessResourceHost:
+		grant = &SysAssetGrant{
+			SubjectType:  GrantSubjectUser,
+			SubjectID:    ar.UserID,
+			AssetGroupID: ar.AssetGroupID,
+			HostIDs:      ar.HostIDs,
+			Permissions:  ar.Permissions,
+			Effect:       GrantEffectAllow,
+			StartAt:      &now,
+			ExpiresAt:    &expiresAt,
+			Reason:       fmt.Sprintf("访问申请 #%d: %s", ar.ID, ar.Reason),
+			CreatedBy:    reviewerID,
+		}
+	case AccessResourceCluster:
+		if uc.binder == nil {
+			return errors.New("Kubernetes 插件未启用，无法授予集群角色")
+		}
+		bind = func(ctx context.Context) error {
+			if err := uc.binder.BindClusterRole(ctx, ar.ClusterID, ar.UserID, ar.RoleName, ar.RoleNamespace, ar.RoleType, reviewerID); err != nil {
+				return fmt.Errorf("绑定集群角色失败: %w", err)
+			}
+			return nil
+		}
+	}
+
+	ar.Status = AccessStatusApproved
+	ar.ReviewerID = reviewerID
+	ar.ReviewerName = reviewerName
+	ar.ReviewComment = comment
+	ar.ReviewedAt = &now
+	ar.StartAt = &now
+	ar.ExpiresAt = &expiresAt
+	// 授权和状态在同一事务中写入，并发审批时只有一个能成功
+	if err := uc.repo.Review(ctx, ar, grant, bind); err != nil {
+		return err
+	}
+
+	uc.addEvent(ctx, ar.ID, AccessEventApprove, reviewerID, reviewerName, comment)
+	uc.notify(ctx, ar, "访问申请已批准", fmt.Sprintf("%s 批准了你的申请: %s，有效期至 %s", reviewerName, describeAccess(ar), expiresAt.Format("2006-01-02 15:04:05")), []uint{ar.UserID})
+	return nil
+}
+
+// Reject 驳回访问申请
+func (uc *AccessRequestUseCase) Reject(ctx context.Context, id, reviewerID uint, reviewerName, comment string) error {
+	ar, err := uc.reviewable(ctx, id, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ar.Status = AccessStatusRejected
+	ar.ReviewerID = reviewerID
+	ar.ReviewerName = reviewerName
+	ar.ReviewComment = comment
+	ar.ReviewedAt = &now
+	if err := uc.repo.Review(ctx, ar, nil, nil); err != nil {
+		return err
+	}
+
+	uc.addEvent(ctx, ar.ID, AccessEventReject, reviewerID, reviewerName, comment)
+	uc.notify(ctx, ar, "访问申请被驳回", fmt.Sprintf("%s 驳回了你的申请: %s，意见: %s", reviewerName, describeAccess(ar), comment), []uint{ar.UserID})
+	return nil
+}
+
+// Cancel 申请人撤回待审批的申请
+func (uc *AccessRequestUseCase) Cancel(ctx context.Context, id, userID uint, username string) error {
+	ar, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return errors.New("申请不存在")
+	}
+	if ar.UserID != userID {
+		return errors.New("只能撤回自己的申请")
+	}
+	if ar.Status != AccessStatusPending {
+		return errors.New("只能撤回待审批的申请")
+	}
+
+	ar.Status = AccessStatusCancelled
+	if err := uc.repo.Update(ctx, ar); err != nil {
+		return err
+	}
+	uc.addEvent(ctx, ar.ID, AccessEventCancel, userID, username, "")
+	return nil
+}
+
+// Revoke 提前收回已批准的授权
+func (uc *AccessRequestUseCase) Revoke(ctx context.Context, id, operatorID uint, operatorName, comment string) error {
+	ar, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return errors.New("申请不存在")
+	}
+	if ar.Status != AccessStatusApproved {
+		return errors.New("只能收回生效中的授权")
+	}
+	if ok, err := uc.canReview(ctx, ar, operatorID); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("无权收回该授权")
+	}
+
+	if err := uc.release(ctx, ar); err != nil {
+		return err
+	}
+	ar.Status = AccessStatusRevoked
+	if err := uc.repo.Update(ctx, ar); err != nil {
+		return err
+	}
+
+	uc.addEvent(ctx, ar.ID, AccessEventRevoke, operatorID, operatorName, comment)
+	uc.notify(ctx, ar, "访问授权已收回", fmt.Sprintf("%s 收回了你的授权: %s", operatorName, describeAccess(ar)), []uint{ar.UserID})
+	return nil
+}
+
+// ExpireDue 处理已到期的授权：解除集群角色绑定并标记为已到期
+func (uc *AccessRequestUseCase) ExpireDue(ctx context.Context) (int64, error) {
+	due, err := uc.repo.ListDue(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	var expired int64
+	for _, ar := range due {
+		if err := uc.release(ctx, ar); err != nil {
+			appLogger.Error("回收到期访问授权失败", zap.Uint("requestId", ar.ID), zap.Error(err))
+			continue
+		}
+		ar.Status = AccessStatusExpired
+		if err := uc.repo.Update(ctx, ar); err != nil {
+			return expired, err
+		}
+		uc.addEvent(ctx, ar.ID, AccessEventExpire, 0, "system", "")
+		expired++
+	}
+	return expired, nil
+}
+
+// GetDetail 获取申请详情，仅申请人、审批人和管理员可见
+func (uc *AccessRequestUseCase) GetDetail(ctx context.Context, id, userID uint) (*AccessRequestDetailVO, error) {
+	ar, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("申请不存在")
+	}
+	if ar.UserID != userID {
+		if ok, err := uc.canReview(ctx, ar, userID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, errors.New("无权查看该申请")
+		}
+	}
+
+	events, err := uc.repo.ListEvents(ctx, ar.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessRequestDetailVO{
+		SysAccessRequest: *ar,
+		PermissionNames:  GetAllPermissionNames(ar.Permissions),
+		Events:           events,
+	}, nil
+}
+
+// List 分页查询访问申请
+func (uc *AccessRequestUseCase) List(ctx context.Context, req *AccessRequestListReq, userID uint) ([]*SysAccessRequest, int64, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.Scope == "all" {
+		isAdmin, err := uc.repo.IsAdmin(ctx, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !isAdmin {
+			req.Scope = "mine"
+		}
+	}
+	return uc.repo.List(ctx, req, userID)
+}
+
+// SetApprovers 设置资产分组或部门的审批人，仅管理员可操作
+func (uc *AccessRequestUseCase) SetApprovers(ctx context.Context, operatorID uint, req *AccessApproverReq) error {
+	isAdmin, err := uc.repo.IsAdmin(ctx, operatorID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return errors.New("只有管理员可以配置审批人")
+	}
+	return uc.repo.SetApprovers(ctx, req.ScopeType, req.ScopeID, req.UserIDs)
+}
+
+// ListApprovers 获取资产分组或部门的审批人
+func (uc *AccessRequestUseCase) ListApprovers(ctx context.Context, scopeType string, scopeID uint) ([]*SysAccessApprover, error) {
+	return uc.repo.ListApprovers(ctx, scopeType, scopeID)
+}
+
+// reviewable 获取可由指定用户审批的待审批申请
+func (uc *AccessRequestUseCase) reviewable(ctx context.Context, id, reviewerID uint) (*SysAccessRequest, error) {
+	ar, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("申请不存在")
+	}
+	if ar.Status != AccessStatusPending {
+		return nil, ErrAccessRequestReviewed
+	}
+	if ar.UserID == reviewerID {
+		return nil, errors.New("不能审批自己的申请")
+	}
+	ok, err := uc.canReview(ctx, ar, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("无权审批该申请")
+	}
+	return ar, nil
+}
+
+// canReview 判断用户是否为申请的审批人或管理员
+func (uc *AccessRequestUseCase) canReview(ctx context.Context, ar *SysAccessRequest, userID uint) (bool, error) {
+	for _, id := range ar.ApproverIDs {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return uc.repo.IsAdmin(ctx, userID)
+}
+
+// release 回收申请对应的授权
+func (uc *AccessRequestUseCase) release(ctx context.Context, ar *SysAccessRequest) error {
+	switch ar.ResourceType {
+	case AccessResourceHost:
+		if ar.GrantID > 0 {
+			return uc.grantRepo.DeleteGrant(ctx, ar.GrantID)
+		}
+	case AccessResourceCluster:
+		if uc.binder == nil {
+			return errors.New("Kubernetes 插件未启用，无法解除集群角色")
+		}
+		return uc.binder.UnbindClusterRole(ctx, ar.ClusterID, ar.UserID, ar.RoleName, ar.RoleNamespace)
+	}
+	return nil
+}
+
+func (uc *AccessRequestUseCase) addEvent(ctx context.Context, requestID uint, action string, operatorID uint, operatorName, comment string) {
+	event := &SysAccessRequestEvent{
+		RequestID:    requestID,
+		Action:       action,
+		OperatorID:   operatorID,
+		OperatorName: operatorName,
+		Comment:      comment,
+	}
+	if err := uc.repo.AddEvent(ctx, event); err != nil {
+		appLogger.Error("记录访问申请事件失败", zap.Uint("requestId", requestID), zap.String("action", action), zap.Error(err))
+	}
+}
+
+// notify 异步发送通知，并把发送结果记入流转记录
+func (uc *AccessRequestUseCase) notify(ctx context.Context, ar *SysAccessRequest, title, content string, userIDs []uint) {
+	if uc.notifier == nil || len(userIDs) == 0 {
+		return
+	}
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		comment := title
+		if err := uc.notifier.Notify(ctx, title, content, userIDs); err != nil {
+			comment = fmt.Sprintf("%s（发送失败: %v）", title, err)
+		}
+		uc.addEvent(ctx, ar.ID, AccessEventNotify, 0, "system", comment)
+	}()
+}
+
+// describeAccess 描述申请的资源和权限
+func describeAccess(ar *SysAccessRequest) string {
+	if ar.ResourceType == AccessResourceCluster {
+		if ar.RoleNamespace != "" {
+			return fmt.Sprintf("集群 #%d 的 %s %s/%s", ar.ClusterID, ar.RoleType, ar.RoleNamespace, ar.RoleName)
+		}
+		return fmt.Sprintf("集群 #%d 的 %s %s", ar.ClusterID, ar.RoleType, ar.RoleName)
+	}
+	target := "全部主机"
+	if len(ar.HostIDs) > 0 {
+		target = fmt.Sprintf("%d 台主机", len(ar.HostIDs))
+	}
+	return fmt.Sprintf("资产分组 #%d %s的%s权限", ar.AssetGroupID, target, strings.Join(GetAllPermissionNames(ar.Permissions), "、"))
+}
+
+func formatDuration(minutes int) string {
+	if minutes%(24*60) == 0 {
+		return fmt.Sprintf("%d 天", minutes/24/60)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%d 小时", minutes/60)
+	}
+	return fmt.Sprintf("%d 分钟", minutes)
+}
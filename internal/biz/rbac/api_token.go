@@ -0,0 +1,329 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// UserSourceService 服务账号，只能通过 API 令牌访问，不能登录
+const UserSourceService = "service"
+
+const (
+	// APITokenPrefix API 令牌前缀，用于和 JWT 区分
+	APITokenPrefix = "oph_"
+	// apiTokenTouchInterval 最近使用时间的最小更新间隔，避免每个请求都写库
+	apiTokenTouchInterval = time.Minute
+)
+
+var (
+	// ErrAPITokenInvalid 令牌无效、已吊销或已过期
+	ErrAPITokenInvalid = errors.New("令牌无效或已过期")
+	// ErrAPITokenScope 令牌作用域不允许访问该接口
+	ErrAPITokenScope = errors.New("令牌无权访问该接口")
+)
+
+// APITokenScope 令牌作用域，各字段同时满足才算匹配，为空表示不限制
+type APITokenScope struct {
+	Plugin  string   `json:"plugin,omitempty"`  // 插件名称，限制在 /api/v1/plugins/<plugin>/ 下
+	Methods []string `json:"methods,omitempty"` // HTTP 方法
+	Paths   []string `json:"paths,omitempty"`   // 路径模式，* 匹配一段，结尾的 /** 匹配任意子路径
+}
+
+// APITokenScopes 令牌作用域列表，满足任意一个即可
+type APITokenScopes []APITokenScope
+
+// Value 实现 driver.Valuer 接口，用于数据库存储
+func (s APITokenScopes) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan 实现 sql.Scanner 接口，用于从数据库读取
+func (s *APITokenScopes) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// Allows 判断作用域是否允许访问
+func (s APITokenScopes) Allows(method, requestPath string) bool {
+	for _, scope := range s {
+		if scope.allows(method, requestPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s APITokenScope) allows(method, requestPath string) bool {
+	if s.Plugin != "" && !strings.HasPrefix(requestPath, "/api/v1/plugins/"+s.Plugin+"/") {
+		return false
+	}
+	if len(s.Methods) > 0 {
+		matched := false
+		for _, m := range s.Methods {
+			if strings.EqualFold(m, method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(s.Paths) > 0 {
+		for _, pattern := range s.Paths {
+			if matchPathPattern(pattern, requestPath) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchPathPattern 匹配路径模式，* 匹配一段，结尾的 /** 匹配该前缀及其下的任意路径
+func matchPathPattern(pattern, requestPath string) bool {
+	prefix, ok := strings.CutSuffix(pattern, "/**")
+	if !ok {
+		matched, _ := path.Match(pattern, requestPath)
+		return matched
+	}
+	segments := strings.Split(requestPath, "/")
+	n := strings.Count(prefix, "/") + 1
+	if len(segments) < n {
+		return false
+	}
+	matched, _ := path.Match(prefix, strings.Join(segments[:n], "/"))
+	return matched
+}
+
+// SysAPIToken API 令牌，只保存摘要
+type SysAPIToken struct {
+	gorm.Model
+	Name        string         `gorm:"type:varchar(100);not null;comment:令牌名称" json:"name"`
+	UserID      uint           `gorm:"not null;index;comment:令牌身份（用户或服务账号）" json:"userId"`
+	CreatedBy   uint           `gorm:"comment:创建人ID" json:"createdBy"`
+	Prefix      string         `gorm:"type:varchar(20);comment:令牌前缀，用于识别" json:"prefix"`
+	TokenHash   string         `gorm:"type:varchar(64);not null;uniqueIndex:uk_token_hash;comment:令牌SHA-256摘要" json:"-"`
+	Scopes      APITokenScopes `gorm:"type:json;comment:作用域" json:"scopes"`
+	ExpiresAt   *time.Time     `gorm:"index;comment:过期时间" json:"expiresAt"`
+	LastUsedAt  *time.Time     `gorm:"comment:最近使用时间" json:"lastUsedAt"`
+	LastUsedIP  string         `gorm:"type:varchar(64);comment:最近使用IP" json:"lastUsedIp"`
+	Description string         `gorm:"type:varchar(255);comment:描述" json:"description"`
+}
+
+// TableName 表名
+func (SysAPIToken) TableName() string {
+	return "sys_api_token"
+}
+
+// APITokenReq 创建令牌请求
+type APITokenReq struct {
+	Name        string          `json:"name" binding:"required,max=100"`
+	Scopes      []APITokenScope `json:"scopes" binding:"required,min=1"`
+	ExpiresAt   *time.Time      `json:"expiresAt" binding:"required"`
+	Description string          `json:"description"`
+}
+
+// APITokenCreatedVO 创建令牌结果，明文令牌只返回一次
+type APITokenCreatedVO struct {
+	Token string       `json:"token"`
+	Info  *SysAPIToken `json:"info"`
+}
+
+// ServiceAccountReq 创建服务账号请求
+type ServiceAccountReq struct {
+	Username string `json:"username" binding:"required,max=50"`
+	RealName string `json:"realName"`
+	RoleIDs  []uint `json:"roleIds"`
+}
+
+// APITokenUseCase API 令牌和服务账号
+type APITokenUseCase struct {
+	repo         APITokenRepo
+	userRepo     UserRepo
+	identityRepo ExternalIdentityRepo
+}
+
+// NewAPITokenUseCase 创建 API 令牌用例
+func NewAPITokenUseCase(repo APITokenRepo, userRepo UserRepo, identityRepo ExternalIdentityRepo) *APITokenUseCase {
+	return &APITokenUseCase{
+		repo:         repo,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+	}
+}
+
+// Create 为用户或服务账号创建令牌
+func (uc *APITokenUseCase) Create(ctx context.Context, userID, createdBy uint, req *APITokenReq) (*APITokenCreatedVO, error) {
+	if req.ExpiresAt == nil || !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("过期时间必须晚于当前时间")
+	}
+	for _, scope := range req.Scopes {
+		if scope.Plugin == "" && len(scope.Paths) == 0 {
+			return nil, errors.New("每个作用域至少需要指定插件或路径")
+		}
+		for _, pattern := range scope.Paths {
+			if _, err := path.Match(pattern, "/"); err != nil || !strings.HasPrefix(pattern, "/") {
+				return nil, fmt.Errorf("无效的路径模式: %s", pattern)
+			}
+		}
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	raw := APITokenPrefix + hex.EncodeToString(buf)
+
+	token := &SysAPIToken{
+		Name:        req.Name,
+		UserID:      userID,
+		CreatedBy:   createdBy,
+		Prefix:      raw[:len(APITokenPrefix)+8],
+		TokenHash:   HashAPIToken(raw),
+		Scopes:      req.Scopes,
+		ExpiresAt:   req.ExpiresAt,
+		Description: req.Description,
+	}
+	if err := uc.repo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+	return &APITokenCreatedVO{Token: raw, Info: token}, nil
+}
+
+// ListByUser 获取用户或服务账号的令牌
+func (uc *APITokenUseCase) ListByUser(ctx context.Context, userID uint) ([]*SysAPIToken, error) {
+	return uc.repo.ListByUser(ctx, userID)
+}
+
+// Revoke 吊销令牌，只能吊销属于指定身份的令牌
+func (uc *APITokenUseCase) Revoke(ctx context.Context, userID, tokenID uint) error {
+	token, err := uc.repo.GetByID(ctx, tokenID)
+	if err != nil || token.UserID != userID {
+		return errors.New("令牌不存在")
+	}
+	return uc.repo.Delete(ctx, tokenID)
+}
+
+// Authenticate 校验令牌和作用域，返回令牌和对应的用户
+func (uc *APITokenUseCase) Authenticate(ctx context.Context, raw, method, requestPath, clientIP string) (*SysAPIToken, *SysUser, error) {
+	token, err := uc.repo.GetByHash(ctx, HashAPIToken(raw))
+	if err != nil {
+		return nil, nil, ErrAPITokenInvalid
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		return nil, nil, ErrAPITokenInvalid
+	}
+	user, err := uc.userRepo.GetByID(ctx, token.UserID)
+	if err != nil || user.Status != 1 {
+		return nil, nil, ErrAPITokenInvalid
+	}
+	if !token.Scopes.Allows(method, requestPath) {
+		return nil, nil, ErrAPITokenScope
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenTouchInterval || token.LastUsedIP != clientIP {
+		_ = uc.repo.Touch(ctx, token.ID, now, clientIP)
+	}
+	return token, user, nil
+}
+
+// ListServiceAccounts 获取服务账号列表
+func (uc *APITokenUseCase) ListServiceAccounts(ctx context.Context) ([]*SysUser, error) {
+	return uc.identityRepo.ListBySource(ctx, UserSourceService)
+}
+
+// GetServiceAccount 获取服务账号
+func (uc *APITokenUseCase) GetServiceAccount(ctx context.Context, id uint) (*SysUser, error) {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil || user.Source != UserSourceService {
+		return nil, errors.New("服务账号不存在")
+	}
+	return user, nil
+}
+
+// CreateServiceAccount 创建服务账号，密码随机生成且不会返回，账号无法交互式登录
+func (uc *APITokenUseCase) CreateServiceAccount(ctx context.Context, req *ServiceAccountReq) (*SysUser, error) {
+	if _, err := uc.userRepo.GetByUsername(ctx, req.Username); err == nil {
+		return nil, errors.New("用户名已存在")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &SysUser{
+		Username: req.Username,
+		Password: string(hashed),
+		RealName: req.RealName,
+		Status:   1,
+		Source:   UserSourceService,
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if len(req.RoleIDs) > 0 {
+		if err := uc.userRepo.AssignRoles(ctx, user.ID, req.RoleIDs); err != nil {
+			return nil, err
+		}
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// DeleteServiceAccount 删除服务账号及其全部令牌
+func (uc *APITokenUseCase) DeleteServiceAccount(ctx context.Context, id uint) error {
+	if _, err := uc.GetServiceAccount(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteByUser(ctx, id); err != nil {
+		return err
+	}
+	return uc.userRepo.Delete(ctx, id)
+}
+
+// HashAPIToken 计算令牌摘要
+func HashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 授权主体类型
+const (
+	GrantSubjectUser       = "user"       // 用户
+	GrantSubjectDepartment = "department" // 部门（含下级部门成员）
+	GrantSubjectRole       = "role"       // 角色
+)
+
+// 授权效果
+const (
+	GrantEffectAllow = "allow" // 允许
+	GrantEffectDeny  = "deny"  // 显式拒绝，优先于允许
+)
+
+// 权限来源类型
+const (
+	PermissionSourceRole  = "role-permission" // 角色资产权限（sys_role_asset_permission）
+	PermissionSourceGrant = "grant"           // 资产授权（sys_asset_grant）
+)
+
+// SysAssetGrant 资产授权模型
+// 可直接授权给用户、部门或角色，支持生效/过期时间和显式拒绝
+type SysAssetGrant struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	SubjectType  string         `gorm:"type:varchar(20);not null;index:idx_grant_subject;comment:授权主体类型 user/department/role" json:"subjectType"`
+	SubjectID    uint           `gorm:"not null;index:idx_grant_subject;comment:授权主体ID" json:"subjectId"`
+	AssetGroupID uint           `gorm:"not null;index;comment:资产分组ID" json:"assetGroupId"`
+	HostIDs      UintArray      `gorm:"type:json" json:"hostIds"` // 主机ID列表（为空表示整个分组）
+	Permissions  uint           `gorm:"type:int unsigned;default:1;comment:操作权限位掩码" json:"permissions"`
+	Effect       string         `gorm:"type:varchar(10);not null;default:'allow';comment:授权效果 allow/deny" json:"effect"`
+	StartAt      *time.Time     `gorm:"comment:生效时间" json:"startAt"`
+	ExpiresAt    *time.Time     `gorm:"index;comment:过期时间" json:"expiresAt"`
+	Reason       string         `gorm:"type:varchar(500);comment:授权原因" json:"reason"`
+	CreatedBy    uint           `gorm:"comment:创建人ID" json:"createdBy"`
+}
+
+// TableName 指定表名
+func (SysAssetGrant) TableName() string {
+	return "sys_asset_grant"
+}
+
+// AssetGrantReq 创建/更新资产授权请求
+type AssetGrantReq struct {
+	SubjectType  string     `json:"subjectType" binding:"required,oneof=user department role"`
+	SubjectID    uint       `json:"subjectId" binding:"required"`
+	AssetGroupID uint       `json:"assetGroupId" binding:"required"`
+	HostIDs      []uint     `json:"hostIds"`
+	Permissions  uint       `json:"permissions"`
+	Effect       string     `json:"effect" binding:"omitempty,oneof=allow deny"`
+	StartAt      *time.Time `json:"startAt"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+	Reason       string     `json:"reason"`
+}
+
+// AssetGrantListReq 资产授权列表请求
+type AssetGrantListReq struct {
+	Page         int    `form:"page"`
+	PageSize     int    `form:"pageSize"`
+	SubjectType  string `form:"subjectType"`
+	SubjectID    uint   `form:"subjectId"`
+	AssetGroupID uint   `form:"assetGroupId"`
+	Effect       string `form:"effect"`
+	Active       *bool  `form:"active"` // 仅查询当前生效的授权
+}
+
+// AssetGrantInfo 资产授权信息（用于前端展示）
+type AssetGrantInfo struct {
+	SysAssetGrant
+	SubjectName     string   `json:"subjectName"`
+	AssetGroupName  string   `json:"assetGroupName"`
+	PermissionNames []string `json:"permissionNames"`
+	Active          bool     `json:"active"`
+}
+
+// AssetPermissionSource 权限来源，用于计算和解释用户的有效权限
+type AssetPermissionSource struct {
+	Kind            string     `json:"kind"` // role-permission / grant
+	ID              uint       `json:"id"`
+	SubjectType     string     `json:"subjectType"`
+	SubjectID       uint       `json:"subjectId"`
+	SubjectName     string     `json:"subjectName"`
+	Effect          string     `json:"effect"`
+	AssetGroupID    uint       `json:"assetGroupId"`
+	HostIDs         UintArray  `json:"hostIds"`
+	Permissions     uint       `json:"permissions"`
+	PermissionNames []string   `json:"permissionNames"`
+	StartAt         *time.Time `json:"startAt"`
+	ExpiresAt       *time.Time `json:"expiresAt"`
+	Active          bool       `json:"active"`
+	InactiveReason  string     `json:"inactiveReason,omitempty"`
+}
+
+// IsActiveAt 判断时间窗口在指定时刻是否生效
+func IsActiveAt(startAt, expiresAt *time.Time, now time.Time) bool {
+	if startAt != nil && now.Before(*startAt) {
+		return false
+	}
+	if expiresAt != nil && !now.Before(*expiresAt) {
+		return false
+	}
+	return true
+}
+
+// Matches 判断来源是否作用于指定分组下的资产
+// assetID 为 0 时只匹配整组授权（用于数据库等不按主机细分的资产）
+func (s *AssetPermissionSource) Matches(groupID, assetID uint) bool {
+	if s.AssetGroupID != groupID {
+		return false
+	}
+	if len(s.HostIDs) == 0 {
+		return true
+	}
+	if assetID == 0 {
+		return false
+	}
+	for _, id := range s.HostIDs {
+		if id == assetID {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateAssetPermissions 根据权限来源计算有效权限：允许位的并集去掉拒绝位的并集
+func EvaluateAssetPermissions(sources []*AssetPermissionSource, groupID, assetID uint) (allow, deny, effective uint) {
+	for _, s := range sources {
+		if !s.Active || !s.Matches(groupID, assetID) {
+			continue
+		}
+		if s.Effect == GrantEffectDeny {
+			deny |= s.Permissions
+		} else {
+			allow |= s.Permissions
+		}
+	}
+	return allow, deny, allow &^ deny
+}
+
+// AssetPermissionExplainVO 有效权限解释
+type AssetPermissionExplainVO struct {
+	UserID         uint                     `json:"userId"`
+	Username       string                   `json:"username"`
+	HostID         uint                     `json:"hostId"`
+	HostName       string                   `json:"hostName"`
+	AssetGroupID   uint                     `json:"assetGroupId"`
+	IsAdmin        bool                     `json:"isAdmin"`
+	Allowed        uint                     `json:"allowed"`
+	Denied         uint                     `json:"denied"`
+	Effective      uint                     `json:"effective"`
+	EffectiveNames []string                 `json:"effectiveNames"`
+	Sources        []*AssetPermissionSource `json:"sources"` // 作用于该主机的全部来源（含未生效的）
+	Reasons        []string                 `json:"reasons"` // 可读的判定说明
+}
@@ -29,13 +29,15 @@ import (
 
 // Permission constants - using bitmask
 const (
-	PermissionView     = 1 << 0  // 1 (查看)
-	PermissionEdit     = 1 << 1  // 2 (编辑)
-	PermissionDelete   = 1 << 2  // 4 (删除)
-	PermissionTerminal = 1 << 3  // 8 (终端)
-	PermissionFile     = 1 << 4  // 16 (文件管理)
-	PermissionCollect  = 1 << 5  // 32 (采集信息)
-	PermissionAll      = 0x3F    // 63 (所有权限)
+	PermissionView        = 1 << 0 // 1 (查看)
+	PermissionEdit        = 1 << 1 // 2 (编辑)
+	PermissionDelete      = 1 << 2 // 4 (删除)
+	PermissionTerminal    = 1 << 3 // 8 (终端)
+	PermissionFile        = 1 << 4 // 16 (文件管理)
+	PermissionCollect     = 1 << 5 // 32 (采集信息)
+	PermissionPortForward = 1 << 6 // 64 (端口转发)
+	PermissionDBWrite     = 1 << 7 // 128 (数据库写入)
+	PermissionAll         = 0xFF   // 255 (所有权限)
 )
 
 // UintArray 用于处理JSON格式的uint数组
@@ -62,10 +64,12 @@ type SysRoleAssetPermission struct {
 	CreatedAt    time.Time      `json:"createdAt"`
 	UpdatedAt    time.Time      `json:"updatedAt"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
-	RoleID       uint           `gorm:"not null;index:idx_role_asset" json:"roleId"`        // 角色ID
+	RoleID       uint           `gorm:"not null;index:idx_role_asset" json:"roleId"`       // 角色ID
 	AssetGroupID uint           `gorm:"not null;index:idx_role_asset" json:"assetGroupId"` // 资产分组ID
 	HostIDs      UintArray      `gorm:"type:json" json:"hostIds"`                          // 主机ID列表（为空表示整个分组）
-	Permissions  uint           `gorm:"type:int unsigned;default:1;comment:操作权限位掩码：1=查看,2=编辑,4=删除,8=终端,16=文件,32=采集;index" json:"permissions"`
+	Permissions  uint           `gorm:"type:int unsigned;default:1;comment:操作权限位掩码：1=查看,2=编辑,4=删除,8=终端,16=文件,32=采集,64=端口转发,128=数据库写入;index" json:"permissions"`
+	StartAt      *time.Time     `gorm:"comment:生效时间" json:"startAt"`         // 为空表示立即生效
+	ExpiresAt    *time.Time     `gorm:"index;comment:过期时间" json:"expiresAt"` // 为空表示永久有效
 }
 
 // TableName 指定表名
@@ -75,17 +79,19 @@ func (SysRoleAssetPermission) TableName() string {
 
 // AssetPermissionInfo 资产权限信息（用于前端展示）
 type AssetPermissionInfo struct {
-	ID             uint      `json:"id"`
-	RoleID         uint      `json:"roleId"`
-	RoleName       string    `json:"roleName"`
-	RoleCode       string    `json:"roleCode"`
-	AssetGroupID   uint      `json:"assetGroupId"`
-	AssetGroupName string    `json:"assetGroupName"`
-	HostIDs        []uint    `json:"hostIds"`        // 主机ID列表（为空表示整个分组）
-	HostNames      []string  `json:"hostNames,omitempty"` // 主机名称列表
-	IsAllHosts     bool      `json:"isAllHosts"`    // 是否授权所有主机
-	Permissions    uint      `json:"permissions"`
-	CreatedAt      time.Time `json:"createdAt"`
+	ID             uint       `json:"id"`
+	RoleID         uint       `json:"roleId"`
+	RoleName       string     `json:"roleName"`
+	RoleCode       string     `json:"roleCode"`
+	AssetGroupID   uint       `json:"assetGroupId"`
+	AssetGroupName string     `json:"assetGroupName"`
+	HostIDs        []uint     `json:"hostIds"`             // 主机ID列表（为空表示整个分组）
+	HostNames      []string   `json:"hostNames,omitempty"` // 主机名称列表
+	IsAllHosts     bool       `json:"isAllHosts"`          // 是否授权所有主机
+	Permissions    uint       `json:"permissions"`
+	StartAt        *time.Time `json:"startAt"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
 }
 
 // AssetPermissionCreateReq 创建资产权限请求
@@ -103,22 +109,26 @@ type AssetPermissionUpdateReq struct {
 
 // AssetPermissionCreateReqWithPermissions 创建资产权限请求（支持操作权限）
 type AssetPermissionCreateReqWithPermissions struct {
-	RoleID      uint   `json:"roleId" binding:"required"`
-	AssetGroupID uint   `json:"assetGroupId" binding:"required"`
-	HostIDs     []uint `json:"hostIds"` // 空数组表示整个分组，非空表示指定主机
-	Permissions uint   `json:"permissions"`
+	RoleID       uint       `json:"roleId" binding:"required"`
+	AssetGroupID uint       `json:"assetGroupId" binding:"required"`
+	HostIDs      []uint     `json:"hostIds"` // 空数组表示整个分组，非空表示指定主机
+	Permissions  uint       `json:"permissions"`
+	StartAt      *time.Time `json:"startAt"`   // 生效时间，为空表示立即生效
+	ExpiresAt    *time.Time `json:"expiresAt"` // 过期时间，为空表示永久有效
 }
 
 // AssetPermissionDetailVO 资产权限详情（用于编辑）
 type AssetPermissionDetailVO struct {
-	ID            uint      `json:"id"`
-	RoleID        uint      `json:"roleId"`
-	RoleName      string    `json:"roleName"`
-	AssetGroupID  uint      `json:"assetGroupId"`
-	AssetGroupName string   `json:"assetGroupName"`
-	HostIDs       []uint    `json:"hostIds"`       // 指定的主机ID列表（为空表示全部）
-	Permissions   uint      `json:"permissions"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID             uint       `json:"id"`
+	RoleID         uint       `json:"roleId"`
+	RoleName       string     `json:"roleName"`
+	AssetGroupID   uint       `json:"assetGroupId"`
+	AssetGroupName string     `json:"assetGroupName"`
+	HostIDs        []uint     `json:"hostIds"` // 指定的主机ID列表（为空表示全部）
+	Permissions    uint       `json:"permissions"`
+	StartAt        *time.Time `json:"startAt"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
 }
 
 // HasPermission 检查是否具有指定权限
@@ -151,6 +161,10 @@ func GetPermissionName(perm uint) string {
 		return "文件管理"
 	case PermissionCollect:
 		return "采集信息"
+	case PermissionPortForward:
+		return "端口转发"
+	case PermissionDBWrite:
+		return "数据库写入"
 	default:
 		return "未知"
 	}
@@ -177,6 +191,11 @@ func GetAllPermissionNames(permissions uint) []string {
 	if (permissions & PermissionCollect) > 0 {
 		names = append(names, "采集信息")
 	}
+	if (permissions & PermissionPortForward) > 0 {
+		names = append(names, "端口转发")
+	}
+	if (permissions & PermissionDBWrite) > 0 {
+		names = append(names, "数据库写入")
+	}
 	return names
 }
-
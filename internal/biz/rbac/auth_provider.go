@@ -0,0 +1,110 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 用户来源
+const (
+	UserSourceLocal = "local" // 本地账号
+	UserSourceLDAP  = "ldap"  // LDAP/AD 目录账号
+)
+
+var (
+	// ErrAuthProviderSkip 当前认证提供者不处理该用户，交给认证链中的下一个提供者
+	ErrAuthProviderSkip = errors.New("认证提供者不处理该用户")
+	// ErrInvalidCredentials 用户名或密码错误
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+)
+
+// AuthProvider 认证提供者
+type AuthProvider interface {
+	// Name 提供者名称，同时作为用户来源标识
+	Name() string
+	// Authenticate 校验用户名和密码，成功时返回本地用户；不处理该用户时返回 ErrAuthProviderSkip
+	Authenticate(ctx context.Context, username, password string) (*SysUser, error)
+}
+
+// AuthChain 认证链，按顺序尝试各个认证提供者
+type AuthChain struct {
+	providers []AuthProvider
+}
+
+// NewAuthChain 创建认证链
+func NewAuthChain(providers ...AuthProvider) *AuthChain {
+	return &AuthChain{providers: providers}
+}
+
+// Use 追加认证提供者
+func (c *AuthChain) Use(provider AuthProvider) {
+	c.providers = append(c.providers, provider)
+}
+
+// Authenticate 依次调用认证提供者，第一个给出结论（成功或失败）的提供者决定结果
+func (c *AuthChain) Authenticate(ctx context.Context, username, password string) (*SysUser, string, error) {
+	for _, provider := range c.providers {
+		user, err := provider.Authenticate(ctx, username, password)
+		if errors.Is(err, ErrAuthProviderSkip) {
+			continue
+		}
+		if err != nil {
+			return nil, provider.Name(), err
+		}
+		return user, provider.Name(), nil
+	}
+	return nil, "", ErrInvalidCredentials
+}
+
+// LocalAuthProvider 本地密码认证
+type LocalAuthProvider struct {
+	userRepo UserRepo
+}
+
+// NewLocalAuthProvider 创建本地密码认证提供者
+func NewLocalAuthProvider(userRepo UserRepo) *LocalAuthProvider {
+	return &LocalAuthProvider{userRepo: userRepo}
+}
+
+// Name 提供者名称
+func (p *LocalAuthProvider) Name() string {
+	return UserSourceLocal
+}
+
+// Authenticate 校验本地密码，外部目录用户和不存在的用户交给后续提供者
+func (p *LocalAuthProvider) Authenticate(ctx context.Context, username, password string) (*SysUser, error) {
+	user, err := p.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrAuthProviderSkip
+	}
+	// SCIM 下发的账号在身份提供者同步了密码时可以用本地密码登录
+	if user.Source != "" && user.Source != UserSourceLocal && user.Source != UserSourceSCIM {
+		return nil, ErrAuthProviderSkip
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// 角色数据范围
+const (
+	DataScopeAll             = 1 // 全部数据
+	DataScopeDeptAndChildren = 2 // 本部门及下级部门
+	DataScopeDept            = 3 // 本部门
+	DataScopeSelf            = 4 // 仅本人
+)
+
+// DataScope 用户的数据可见范围，多个角色取并集
+type DataScope struct {
+	All     bool   // 不限制
+	UserID  uint   // 当前用户，本人的数据始终可见
+	DeptIDs []uint // 可见部门，部门内用户产生的数据可见
+}
+
+// Apply 返回按数据范围过滤的 GORM Scope，userColumn 为记录所属用户的列
+func (s *DataScope) Apply(userColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if s == nil || s.All {
+			return db
+		}
+		if len(s.DeptIDs) == 0 {
+			return db.Where(userColumn+" = ?", s.UserID)
+		}
+		return db.Where(
+			"("+userColumn+" = ? OR "+userColumn+" IN (SELECT id FROM sys_user WHERE department_id IN ? AND deleted_at IS NULL))",
+			s.UserID, s.DeptIDs,
+		)
+	}
+}
+
+type dataScopeKey struct{}
+
+// dataScopeResolver 按需解析数据范围，同一请求只查询一次
+type dataScopeResolver struct {
+	userID  uint
+	once    sync.Once
+	resolve func() (*DataScope, error)
+	scope   *DataScope
+	err     error
+}
+
+// WithDataScope 在上下文中登记当前用户和数据范围解析函数，解析函数首次使用时才会执行
+func WithDataScope(ctx context.Context, userID uint, resolve func() (*DataScope, error)) context.Context {
+	return context.WithValue(ctx, dataScopeKey{}, &dataScopeResolver{userID: userID, resolve: resolve})
+}
+
+// DataOwnerFromContext 获取上下文中的当前用户，用于记录新数据的归属，未登记时返回 0
+func DataOwnerFromContext(ctx context.Context) uint {
+	if resolver, ok := ctx.Value(dataScopeKey{}).(*dataScopeResolver); ok {
+		return resolver.userID
+	}
+	return 0
+}
+
+// DataScopeFromContext 获取上下文中的数据范围，未登记时返回 nil 表示不限制
+func DataScopeFromContext(ctx context.Context) (*DataScope, error) {
+	resolver, ok := ctx.Value(dataScopeKey{}).(*dataScopeResolver)
+	if !ok {
+		return nil, nil
+	}
+	resolver.once.Do(func() {
+		resolver.scope, resolver.err = resolver.resolve()
+	})
+	return resolver.scope, resolver.err
+}
+
+// ScopeByDataPermission 按上下文中的数据范围过滤列表查询，用于 db.Scopes
+// 例如 query.Scopes(rbac.ScopeByDataPermission(ctx, "user_id"))
+func ScopeByDataPermission(ctx context.Context, userColumn string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope, err := DataScopeFromContext(ctx)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		return scope.Apply(userColumn)(db)
+	}
+}
+
+// DataScopeUseCase 数据权限用例
+type DataScopeUseCase struct {
+	roleRepo RoleRepo
+	userRepo UserRepo
+	deptRepo DepartmentRepo
+}
+
+// NewDataScopeUseCase 创建数据权限用例
+func NewDataScopeUseCase(roleRepo RoleRepo, userRepo UserRepo, deptRepo DepartmentRepo) *DataScopeUseCase {
+	return &DataScopeUseCase{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		deptRepo: deptRepo,
+	}
+}
+
+// Resolve 根据用户的启用角色计算数据范围
+// admin 角色或任一角色为全部数据时不限制；没有角色的用户只能看到本人数据
+func (uc *DataScopeUseCase) Resolve(ctx context.Context, userID uint) (*DataScope, error) {
+	roles, err := uc.roleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := &DataScope{UserID: userID}
+	var withDept, withChildren bool
+	for _, role := range roles {
+		if role.Status != 1 {
+			continue
+		}
+		if role.Code == RoleCodeAdmin {
+			scope.All = true
+			return scope, nil
+		}
+		switch role.DataScope {
+		case DataScopeDeptAndChildren:
+			withChildren = true
+		case DataScopeDept:
+			withDept = true
+		case DataScopeSelf:
+		default:
+			scope.All = true
+			return scope, nil
+		}
+	}
+	if !withDept && !withChildren {
+		return scope, nil
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DepartmentID == 0 {
+		return scope, nil
+	}
+	scope.DeptIDs = []uint{user.DepartmentID}
+	if withChildren {
+		depts, err := uc.deptRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		scope.DeptIDs = descendantDepartments(depts, user.DepartmentID)
+	}
+	return scope, nil
+}
+
+// descendantDepartments 返回部门及其全部下级部门ID
+func descendantDepartments(depts []*SysDepartment, rootID uint) []uint {
+	children := make(map[uint][]uint)
+	for _, dept := range depts {
+		children[dept.ParentID] = append(children[dept.ParentID], dept.ID)
+	}
+	ids := []uint{rootID}
+	seen := map[uint]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
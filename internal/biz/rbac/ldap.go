@@ -0,0 +1,311 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrDirectoryUserNotFound 目录中不存在该用户
+	ErrDirectoryUserNotFound = errors.New("目录中不存在该用户")
+	// ErrDirectoryInvalidCredentials 目录认证失败
+	ErrDirectoryInvalidCredentials = errors.New("目录认证失败")
+)
+
+// DirectoryEntry 目录中的用户条目
+type DirectoryEntry struct {
+	DN       string
+	Username string
+	RealName string
+	Email    string
+	Phone    string
+	Groups   []string // 分组名称和完整DN
+}
+
+// Directory LDAP/AD 目录
+type Directory interface {
+	// Authenticate 以用户身份绑定目录，成功后返回用户条目
+	Authenticate(ctx context.Context, username, password string) (*DirectoryEntry, error)
+	// ListUsers 列出目录中的全部用户
+	ListUsers(ctx context.Context) ([]*DirectoryEntry, error)
+}
+
+// DirectoryGroupMapping 目录分组到角色/部门的映射
+type DirectoryGroupMapping struct {
+	Group          string
+	RoleCodes      []string
+	DepartmentCode string
+}
+
+// LDAPSyncOptions 目录用户同步选项
+type LDAPSyncOptions struct {
+	DefaultRoleCodes []string
+	GroupMappings    []DirectoryGroupMapping
+	DisableMissing   bool
+}
+
+// LDAPSyncResult 目录同步结果
+type LDAPSyncResult struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Total      int       `json:"total"`
+	Updated    int       `json:"updated"`
+	Disabled   int       `json:"disabled"`
+	Failed     int       `json:"failed"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// LDAPUseCase LDAP/AD 认证与同步
+type LDAPUseCase struct {
+	directory    Directory
+	userRepo     UserRepo
+	identityRepo ExternalIdentityRepo
+	opts         LDAPSyncOptions
+
+	sessionUseCase *SessionUseCase
+
+	mu       sync.Mutex
+	lastSync *LDAPSyncResult
+}
+
+// NewLDAPUseCase 创建 LDAP 用例
+func NewLDAPUseCase(directory Directory, userRepo UserRepo, identityRepo ExternalIdentityRepo, opts LDAPSyncOptions) *LDAPUseCase {
+	return &LDAPUseCase{
+		directory:    directory,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		opts:         opts,
+	}
+}
+
+// SetSessionUseCase 设置会话用例，同步禁用用户时注销其全部会话
+func (uc *LDAPUseCase) SetSessionUseCase(sessionUseCase *SessionUseCase) {
+	uc.sessionUseCase = sessionUseCase
+}
+
+// Name 提供者名称
+func (uc *LDAPUseCase) Name() string {
+	return UserSourceLDAP
+}
+
+// Authenticate 以用户身份绑定目录，首次登录时自动创建本地用户，每次登录同步用户资料、角色和部门
+func (uc *LDAPUseCase) Authenticate(ctx context.Context, username, password string) (*SysUser, error) {
+	// 空密码在多数目录上会被当作匿名绑定而成功，必须拒绝
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	entry, err := uc.directory.Authenticate(ctx, username, password)
+	if errors.Is(err, ErrDirectoryUserNotFound) {
+		return nil, ErrAuthProviderSkip
+	}
+	if errors.Is(err, ErrDirectoryInvalidCredentials) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("LDAP认证失败: %w", err)
+	}
+
+	user, err := uc.userRepo.GetByUsername(ctx, entry.Username)
+	if err != nil {
+		user, err = uc.provision(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+	} else if user.Source != UserSourceLDAP {
+		return nil, errors.New("用户名已被本地账号使用，请联系管理员")
+	}
+
+	if err := uc.syncUser(ctx, user, entry); err != nil {
+		return nil, err
+	}
+	return uc.userRepo.GetByID(ctx, user.ID)
+}
+
+// provision 首次登录时创建本地用户，本地密码设为随机值，不能用于登录
+func (uc *LDAPUseCase) provision(ctx context.Context, entry *DirectoryEntry) (*SysUser, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &SysUser{
+		Username:   entry.Username,
+		Password:   string(hashed),
+		RealName:   entry.RealName,
+		Email:      entry.Email,
+		Phone:      entry.Phone,
+		Status:     1,
+		Source:     UserSourceLDAP,
+		ExternalID: entry.DN,
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("创建LDAP用户失败: %w", err)
+	}
+	return user, nil
+}
+
+// syncUser 将目录中的资料、分组映射的角色和部门同步到本地用户
+func (uc *LDAPUseCase) syncUser(ctx context.Context, user *SysUser, entry *DirectoryEntry) error {
+	roleCodes, deptCode := resolveGroupMappings(entry.Groups, uc.opts.GroupMappings, uc.opts.DefaultRoleCodes)
+
+	var deptID uint
+	if deptCode != "" {
+		id, err := uc.identityRepo.GetDepartmentIDByCode(ctx, deptCode)
+		if err != nil {
+			return fmt.Errorf("LDAP分组映射的部门 %s 不存在", deptCode)
+		}
+		deptID = id
+	}
+
+	profile := &SysUser{
+		RealName:     entry.RealName,
+		Email:        entry.Email,
+		Phone:        entry.Phone,
+		DepartmentID: deptID,
+		ExternalID:   entry.DN,
+	}
+	if deptID == 0 {
+		// 未配置部门映射时保留管理员手动设置的部门
+		profile.DepartmentID = user.DepartmentID
+	}
+	if err := uc.identityRepo.SyncProfile(ctx, user.ID, profile); err != nil {
+		return err
+	}
+
+	// 未配置任何映射时角色由管理员手动维护
+	if len(uc.opts.GroupMappings) == 0 && len(uc.opts.DefaultRoleCodes) == 0 {
+		return nil
+	}
+	roleIDs, err := uc.identityRepo.GetRoleIDsByCodes(ctx, roleCodes)
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.AssignRoles(ctx, user.ID, roleIDs)
+}
+
+// resolveGroupMappings 根据用户所在分组计算角色编码和部门编码，部门取第一个匹配的映射
+// 未匹配任何分组时使用默认角色
+func resolveGroupMappings(groups []string, mappings []DirectoryGroupMapping, defaultRoleCodes []string) ([]string, string) {
+	seen := make(map[string]bool)
+	var roleCodes []string
+	var deptCode string
+	for _, m := range mappings {
+		if !inGroups(groups, m.Group) {
+			continue
+		}
+		for _, code := range m.RoleCodes {
+			if !seen[code] {
+				seen[code] = true
+				roleCodes = append(roleCodes, code)
+			}
+		}
+		if deptCode == "" {
+			deptCode = m.DepartmentCode
+		}
+	}
+	if len(roleCodes) == 0 {
+		roleCodes = append(roleCodes, defaultRoleCodes...)
+	}
+	return roleCodes, deptCode
+}
+
+// inGroups 判断分组列表中是否包含指定分组（名称或DN，不区分大小写）
+func inGroups(groups []string, group string) bool {
+	for _, g := range groups {
+		if strings.EqualFold(g, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sync 同步目录用户：更新已存在的 LDAP 用户资料和分组映射，禁用目录中已不存在的用户
+// 目录中新增的用户在首次登录时创建
+func (uc *LDAPUseCase) Sync(ctx context.Context) (*LDAPSyncResult, error) {
+	result := &LDAPSyncResult{StartedAt: time.Now()}
+	defer func() {
+		result.FinishedAt = time.Now()
+		uc.mu.Lock()
+		uc.lastSync = result
+		uc.mu.Unlock()
+	}()
+
+	entries, err := uc.directory.ListUsers(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result, fmt.Errorf("读取LDAP用户失败: %w", err)
+	}
+	users, err := uc.identityRepo.ListBySource(ctx, UserSourceLDAP)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	byUsername := make(map[string]*DirectoryEntry, len(entries))
+	for _, entry := range entries {
+		byUsername[strings.ToLower(entry.Username)] = entry
+	}
+
+	result.Total = len(users)
+	for _, user := range users {
+		entry, ok := byUsername[strings.ToLower(user.Username)]
+		if !ok {
+			if uc.opts.DisableMissing && user.Status == 1 {
+				if err := uc.identityRepo.SetStatus(ctx, user.ID, 0); err != nil {
+					result.Failed++
+					continue
+				}
+				if uc.sessionUseCase != nil {
+					_, _ = uc.sessionUseCase.RevokeAll(ctx, user.ID, "")
+				}
+				result.Disabled++
+			}
+			continue
+		}
+		if err := uc.syncUser(ctx, user, entry); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Updated++
+	}
+	return result, nil
+}
+
+// LastSync 获取最近一次同步结果
+func (uc *LDAPUseCase) LastSync() *LDAPSyncResult {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.lastSync
+}
@@ -0,0 +1,391 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// mfaPeriod TOTP 时间步长
+	mfaPeriod = 30
+	// mfaSkew 允许前后各偏差一个时间步
+	mfaSkew = 1
+	// mfaMaxFailures 连续失败多少次后锁定
+	mfaMaxFailures = 5
+	// mfaLockDuration 锁定时长
+	mfaLockDuration = 5 * time.Minute
+	// RecoveryCodeCount 每次生成的恢复码数量
+	RecoveryCodeCount = 10
+)
+
+// 双因素验证方式
+const (
+	MFAMethodTOTP     = "totp"
+	MFAMethodRecovery = "recovery"
+)
+
+var (
+	// ErrMFANotEnabled 未启用双因素认证
+	ErrMFANotEnabled = errors.New("未启用双因素认证")
+	// ErrMFAInvalidCode 验证码错误
+	ErrMFAInvalidCode = errors.New("验证码错误")
+	// ErrMFALocked 验证失败次数过多
+	ErrMFALocked = errors.New("验证失败次数过多，请稍后再试")
+)
+
+// SysUserMFA 用户双因素认证（TOTP）
+type SysUserMFA struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	UserID       uint       `gorm:"not null;uniqueIndex;comment:用户ID" json:"userId"`
+	Secret       string     `gorm:"type:varchar(255);not null;comment:TOTP密钥(加密)" json:"-"`
+	Enabled      bool       `gorm:"type:tinyint(1);default:0;comment:是否已启用" json:"enabled"`
+	ConfirmedAt  *time.Time `gorm:"comment:绑定确认时间" json:"confirmedAt,omitempty"`
+	LastUsedStep int64      `gorm:"default:0;comment:最近一次使用的时间步，防止验证码重放" json:"-"`
+	FailedCount  int        `gorm:"default:0;comment:连续验证失败次数" json:"-"`
+	LockedUntil  *time.Time `gorm:"comment:锁定截止时间" json:"-"`
+}
+
+// TableName 表名
+func (SysUserMFA) TableName() string {
+	return "sys_user_mfa"
+}
+
+// SysUserRecoveryCode 双因素认证恢复码，只保存摘要
+type SysUserRecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UserID    uint       `gorm:"not null;index;comment:用户ID" json:"userId"`
+	CodeHash  string     `gorm:"type:varchar(64);not null;comment:恢复码SHA-256摘要" json:"-"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"usedAt,omitempty"`
+}
+
+// TableName 表名
+func (SysUserRecoveryCode) TableName() string {
+	return "sys_user_recovery_code"
+}
+
+// MFAStatusVO 双因素认证状态
+type MFAStatusVO struct {
+	Enabled           bool       `json:"enabled"`
+	Required          bool       `json:"required"`
+	ConfirmedAt       *time.Time `json:"confirmedAt,omitempty"`
+	RecoveryCodesLeft int64      `json:"recoveryCodesLeft"`
+}
+
+// MFAEnrollVO 绑定认证器所需信息
+type MFAEnrollVO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`    // otpauth:// 配置地址
+	QRCode string `json:"qrCode"` // data:image/png;base64 二维码
+}
+
+// MFACodeReq 验证码请求
+type MFACodeReq struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAUseCase 双因素认证
+type MFAUseCase struct {
+	repo               MFARepo
+	permissionRepo     AssetPermissionRepo
+	issuer             string
+	requireForTerminal bool
+}
+
+// NewMFAUseCase 创建双因素认证用例
+func NewMFAUseCase(repo MFARepo, permissionRepo AssetPermissionRepo, issuer string, requireForTerminal bool) *MFAUseCase {
+	if issuer == "" {
+		issuer = "OpsHub"
+	}
+	return &MFAUseCase{
+		repo:               repo,
+		permissionRepo:     permissionRepo,
+		issuer:             issuer,
+		requireForTerminal: requireForTerminal,
+	}
+}
+
+// IsRequired 用户是否必须启用双因素认证：所属角色要求，或配置要求拥有终端权限的用户启用
+func (uc *MFAUseCase) IsRequired(ctx context.Context, userID uint) (bool, error) {
+	required, err := uc.repo.RoleRequiresMFA(ctx, userID)
+	if err != nil || required {
+		return required, err
+	}
+	if !uc.requireForTerminal {
+		return false, nil
+	}
+	return uc.permissionRepo.HasAnyPermission(ctx, userID, PermissionTerminal)
+}
+
+// IsEnabled 用户是否已启用双因素认证
+func (uc *MFAUseCase) IsEnabled(ctx context.Context, userID uint) (bool, error) {
+	mfa, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, nil
+	}
+	return mfa.Enabled, nil
+}
+
+// Status 获取双因素认证状态
+func (uc *MFAUseCase) Status(ctx context.Context, userID uint) (*MFAStatusVO, error) {
+	required, err := uc.IsRequired(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	vo := &MFAStatusVO{Required: required}
+	if mfa, err := uc.repo.GetByUserID(ctx, userID); err == nil && mfa.Enabled {
+		vo.Enabled = true
+		vo.ConfirmedAt = mfa.ConfirmedAt
+		if vo.RecoveryCodesLeft, err = uc.repo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return vo, nil
+}
+
+// BeginEnroll 生成新的 TOTP 密钥，确认前不生效
+func (uc *MFAUseCase) BeginEnroll(ctx context.Context, userID uint, username string) (*MFAEnrollVO, error) {
+	if existing, err := uc.repo.GetByUserID(ctx, userID); err == nil && existing.Enabled {
+		return nil, errors.New("已启用双因素认证，如需更换认证器请先关闭")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      uc.issuer,
+		AccountName: username,
+		Period:      mfaPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, &SysUserMFA{UserID: userID, Secret: key.Secret()}); err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(200, 200)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollVO{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ConfirmEnroll 校验认证器生成的验证码，启用双因素认证并生成恢复码
+func (uc *MFAUseCase) ConfirmEnroll(ctx context.Context, userID uint, code string) ([]string, error) {
+	mfa, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("请先生成认证器密钥")
+	}
+	if mfa.Enabled {
+		return nil, errors.New("已启用双因素认证")
+	}
+	if err := uc.checkTOTP(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mfa.Enabled = true
+	mfa.ConfirmedAt = &now
+	if err := uc.repo.Save(ctx, mfa); err != nil {
+		return nil, err
+	}
+	return uc.newRecoveryCodes(ctx, userID)
+}
+
+// Verify 校验 TOTP 验证码或恢复码，返回使用的验证方式
+func (uc *MFAUseCase) Verify(ctx context.Context, userID uint, code string) (string, error) {
+	mfa, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil || !mfa.Enabled {
+		return "", ErrMFANotEnabled
+	}
+	if mfa.LockedUntil != nil && time.Now().Before(*mfa.LockedUntil) {
+		return "", ErrMFALocked
+	}
+
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return MFAMethodTOTP, uc.checkTOTP(ctx, mfa, code)
+	}
+
+	used, err := uc.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return "", err
+	}
+	if !used {
+		uc.recordFailure(ctx, mfa)
+		return MFAMethodRecovery, ErrMFAInvalidCode
+	}
+	_ = uc.repo.ResetFailures(ctx, userID)
+	return MFAMethodRecovery, nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部失效
+func (uc *MFAUseCase) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	if _, err := uc.verifyTOTPOnly(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	return uc.newRecoveryCodes(ctx, userID)
+}
+
+// Disable 用户关闭双因素认证，策略要求启用时不允许关闭
+func (uc *MFAUseCase) Disable(ctx context.Context, userID uint, code string) error {
+	required, err := uc.IsRequired(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.New("当前账号必须启用双因素认证，不能关闭")
+	}
+	if _, err := uc.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	return uc.repo.Delete(ctx, userID)
+}
+
+// Reset 管理员重置用户的双因素认证（如认证器丢失），用户下次登录时重新绑定
+func (uc *MFAUseCase) Reset(ctx context.Context, userID uint) error {
+	return uc.repo.Delete(ctx, userID)
+}
+
+// SetRoleRequired 设置角色是否要求双因素认证
+func (uc *MFAUseCase) SetRoleRequired(ctx context.Context, roleID uint, required bool) error {
+	return uc.repo.SetRoleRequireMFA(ctx, roleID, required)
+}
+
+// verifyTOTPOnly 只接受认证器验证码
+func (uc *MFAUseCase) verifyTOTPOnly(ctx context.Context, userID uint, code string) (*SysUserMFA, error) {
+	mfa, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil || !mfa.Enabled {
+		return nil, ErrMFANotEnabled
+	}
+	if mfa.LockedUntil != nil && time.Now().Before(*mfa.LockedUntil) {
+		return nil, ErrMFALocked
+	}
+	return mfa, uc.checkTOTP(ctx, mfa, strings.TrimSpace(code))
+}
+
+// checkTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (uc *MFAUseCase) checkTOTP(ctx context.Context, mfa *SysUserMFA, code string) error {
+	now := time.Now()
+	opts := totp.ValidateOpts{Period: mfaPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	current := now.Unix() / mfaPeriod
+
+	for offset := -mfaSkew; offset <= mfaSkew; offset++ {
+		step := current + int64(offset)
+		expected, err := totp.GenerateCodeCustom(mfa.Secret, time.Unix(step*mfaPeriod, 0), opts)
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) != 1 {
+			continue
+		}
+		ok, err := uc.repo.MarkStepUsed(ctx, mfa.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("验证码已使用，请等待下一个验证码")
+		}
+		mfa.LastUsedStep = step
+		mfa.FailedCount = 0
+		mfa.LockedUntil = nil
+		return nil
+	}
+
+	uc.recordFailure(ctx, mfa)
+	return ErrMFAInvalidCode
+}
+
+// recordFailure 记录验证失败，连续失败达到上限时锁定一段时间
+func (uc *MFAUseCase) recordFailure(ctx context.Context, mfa *SysUserMFA) {
+	var lockUntil *time.Time
+	if mfa.FailedCount+1 >= mfaMaxFailures {
+		t := time.Now().Add(mfaLockDuration)
+		lockUntil = &t
+	}
+	_ = uc.repo.RecordFailure(ctx, mfa.UserID, lockUntil)
+}
+
+// newRecoveryCodes 生成恢复码，只返回一次明文
+func (uc *MFAUseCase) newRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(buf)
+		code := fmt.Sprintf("%s-%s", raw[:5], raw[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := uc.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 计算恢复码摘要，忽略大小写、空格和连字符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// isTOTPCode 判断是否为 6 位数字验证码
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
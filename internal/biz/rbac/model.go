@@ -41,6 +41,13 @@ type SysUser struct {
 	Positions   []SysPosition `gorm:"many2many:sys_user_position;joinForeignKey:UserID;joinReferences:PositionID" json:"positions,omitempty"`
 	Bio         string         `gorm:"type:text;comment:个人简介" json:"bio"`
 	LastLoginAt *time.Time     `gorm:"comment:最后登录时间" json:"lastLoginAt,omitempty"`
+	Source      string         `gorm:"type:varchar(20);default:'local';comment:用户来源 local/ldap/oidc/service" json:"source"`
+	ExternalID  string         `gorm:"type:varchar(255);index;comment:外部目录中的用户标识" json:"externalId,omitempty"`
+	PasswordChangedAt  *time.Time `gorm:"comment:密码修改时间" json:"passwordChangedAt,omitempty"`
+	MustChangePassword bool       `gorm:"type:tinyint(1);default:0;comment:下次登录必须修改密码" json:"mustChangePassword"`
+	LoginFailures      int        `gorm:"default:0;comment:登录失败次数" json:"-"`
+	LoginFailedAt      *time.Time `gorm:"comment:本轮首次登录失败时间" json:"-"`
+	LockedUntil        *time.Time `gorm:"comment:锁定截止时间" json:"lockedUntil,omitempty"`
 }
 
 // SysRole 角色表
@@ -51,6 +58,8 @@ type SysRole struct {
 	Description string       `gorm:"type:varchar(200);comment:角色描述" json:"description"`
 	Sort        int          `gorm:"type:int;default:0;comment:排序" json:"sort"`
 	Status      int          `gorm:"type:tinyint;default:1;comment:状态 1:启用 0:禁用" json:"status"`
+	RequireMFA  bool         `gorm:"type:tinyint(1);default:0;comment:是否要求双因素认证" json:"requireMfa"`
+	DataScope   int          `gorm:"type:tinyint;default:1;comment:数据范围 1:全部 2:本部门及下级 3:本部门 4:仅本人" json:"dataScope" binding:"omitempty,oneof=1 2 3 4"`
 	Users       []SysUser    `gorm:"many2many:sys_user_role;joinForeignKey:RoleID;joinReferences:UserID" json:"-"`
 	Menus       []SysMenu    `gorm:"many2many:sys_role_menu;joinForeignKey:RoleID;joinReferences:MenuID" json:"menus,omitempty"`
 }
@@ -0,0 +1,308 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserSourceOIDC OIDC 单点登录创建的账号
+const UserSourceOIDC = "oidc"
+
+// 关联已有本地账号的方式
+const (
+	OIDCLinkByUsername = "username"
+	OIDCLinkByEmail    = "email"
+)
+
+// SysUserIdentity 用户与外部身份提供者的绑定关系
+type SysUserIdentity struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	UserID      uint       `gorm:"not null;index;comment:用户ID" json:"userId"`
+	Provider    string     `gorm:"type:varchar(50);not null;uniqueIndex:uk_identity_provider_subject;comment:身份提供者" json:"provider"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_identity_provider_subject;comment:提供者中的用户标识(sub)" json:"subject"`
+	Email       string     `gorm:"type:varchar(100);comment:提供者返回的邮箱" json:"email"`
+	LastLoginAt *time.Time `gorm:"comment:最后登录时间" json:"lastLoginAt,omitempty"`
+}
+
+// TableName 表名
+func (SysUserIdentity) TableName() string {
+	return "sys_user_identity"
+}
+
+// OIDCIdentity 从 ID Token 和 UserInfo 中解析出的用户身份
+type OIDCIdentity struct {
+	Provider string
+	Subject  string
+	Username string
+	Email    string
+	// EmailVerified 身份提供者是否声明邮箱已验证（email_verified）
+	EmailVerified bool
+	RealName      string
+	Phone         string
+	Groups        []string
+}
+
+// OIDCProviderInfo 登录页展示的提供者信息
+type OIDCProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// OIDCConnector OIDC 协议客户端（发现、授权码交换、ID Token 校验）
+type OIDCConnector interface {
+	// AuthCodeURL 生成授权地址，使用 PKCE(S256) 和 nonce
+	AuthCodeURL(ctx context.Context, provider, state, nonce, verifier string) (string, error)
+	// Exchange 用授权码换取令牌并校验 ID Token，返回用户身份
+	Exchange(ctx context.Context, provider, code, nonce, verifier string) (*OIDCIdentity, error)
+}
+
+// OIDCProviderOptions 提供者的账号映射选项
+type OIDCProviderOptions struct {
+	Name             string
+	DisplayName      string
+	AutoCreate       bool
+	LinkBy           string
+	DefaultRoleCodes []string
+	RoleMappings     []DirectoryGroupMapping
+	// PostLoginRedirect 登录完成后跳转的前端地址
+	PostLoginRedirect string
+}
+
+// OIDCUseCase OIDC 单点登录
+type OIDCUseCase struct {
+	connector    OIDCConnector
+	userRepo     UserRepo
+	identityRepo ExternalIdentityRepo
+	providers    map[string]OIDCProviderOptions
+	order        []string
+}
+
+// NewOIDCUseCase 创建 OIDC 用例
+func NewOIDCUseCase(connector OIDCConnector, userRepo UserRepo, identityRepo ExternalIdentityRepo, providers []OIDCProviderOptions) *OIDCUseCase {
+	uc := &OIDCUseCase{
+		connector:    connector,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		providers:    make(map[string]OIDCProviderOptions, len(providers)),
+	}
+	for _, p := range providers {
+		uc.providers[p.Name] = p
+		uc.order = append(uc.order, p.Name)
+	}
+	return uc
+}
+
+// Providers 获取已配置的提供者
+func (uc *OIDCUseCase) Providers() []OIDCProviderInfo {
+	list := make([]OIDCProviderInfo, 0, len(uc.order))
+	for _, name := range uc.order {
+		p := uc.providers[name]
+		displayName := p.DisplayName
+		if displayName == "" {
+			displayName = p.Name
+		}
+		list = append(list, OIDCProviderInfo{Name: p.Name, DisplayName: displayName})
+	}
+	return list
+}
+
+// HasProvider 是否配置了指定提供者
+func (uc *OIDCUseCase) HasProvider(name string) bool {
+	_, ok := uc.providers[name]
+	return ok
+}
+
+// PostLoginRedirect 获取登录完成后跳转的前端地址
+func (uc *OIDCUseCase) PostLoginRedirect(name string) string {
+	if p, ok := uc.providers[name]; ok && p.PostLoginRedirect != "" {
+		return p.PostLoginRedirect
+	}
+	return "/login"
+}
+
+// AuthCodeURL 生成跳转到身份提供者的授权地址
+func (uc *OIDCUseCase) AuthCodeURL(ctx context.Context, provider, state, nonce, verifier string) (string, error) {
+	if !uc.HasProvider(provider) {
+		return "", fmt.Errorf("未配置的身份提供者: %s", provider)
+	}
+	return uc.connector.AuthCodeURL(ctx, provider, state, nonce, verifier)
+}
+
+// Login 完成授权码交换，将外部身份映射为本地用户
+// 查找顺序：已绑定的身份 -> 按 link_by 关联已有本地账号 -> 自动创建账号
+func (uc *OIDCUseCase) Login(ctx context.Context, provider, code, nonce, verifier string) (*SysUser, error) {
+	opts, ok := uc.providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("未配置的身份提供者: %s", provider)
+	}
+
+	identity, err := uc.connector.Exchange(ctx, provider, code, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Username == "" {
+		return nil, errors.New("身份提供者未返回用户名")
+	}
+
+	user, err := uc.resolveUser(ctx, opts, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只同步由 OIDC 创建的账号，关联的本地账号资料和角色仍由管理员维护
+	if user.Source == UserSourceOIDC {
+		if err := uc.syncUser(ctx, opts, user, identity); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.identityRepo.TouchIdentity(ctx, provider, identity.Subject, identity.Email); err != nil {
+		return nil, err
+	}
+	return uc.userRepo.GetByID(ctx, user.ID)
+}
+
+// resolveUser 查找或创建与外部身份对应的本地用户
+func (uc *OIDCUseCase) resolveUser(ctx context.Context, opts OIDCProviderOptions, identity *OIDCIdentity) (*SysUser, error) {
+	if userID, err := uc.identityRepo.GetIdentityUserID(ctx, identity.Provider, identity.Subject); err == nil {
+		return uc.userRepo.GetByID(ctx, userID)
+	}
+
+	var user *SysUser
+	switch opts.LinkBy {
+	case OIDCLinkByUsername:
+		if u, err := uc.userRepo.GetByUsername(ctx, identity.Username); err == nil {
+			user = u
+		}
+	case OIDCLinkByEmail:
+		// 未验证的邮箱可以由任何人在身份提供者处填写，不能用于关联本地账号
+		if identity.Email != "" && identity.EmailVerified {
+			if u, err := uc.identityRepo.GetUserByEmail(ctx, identity.Email); err == nil {
+				user = u
+			}
+		}
+	}
+	if user != nil {
+		// 管理员账号被关联后即可通过外部身份登录，只能由管理员手动绑定
+		linked, err := uc.userRepo.GetByID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range linked.Roles {
+			if role.Code == RoleCodeAdmin {
+				return nil, errors.New("管理员账号不能通过单点登录自动关联，请联系管理员")
+			}
+		}
+	}
+
+	if user == nil {
+		if !opts.AutoCreate {
+			return nil, errors.New("未找到关联的本地账号，请联系管理员")
+		}
+		if _, err := uc.userRepo.GetByUsername(ctx, identity.Username); err == nil {
+			return nil, errors.New("用户名已被本地账号使用，请联系管理员")
+		}
+		created, err := uc.provision(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+		user = created
+	}
+
+	binding := &SysUserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	if err := uc.identityRepo.CreateIdentity(ctx, binding); err != nil {
+		return nil, fmt.Errorf("绑定外部身份失败: %w", err)
+	}
+	return user, nil
+}
+
+// provision 创建 OIDC 用户，本地密码设为随机值，不能用于登录
+func (uc *OIDCUseCase) provision(ctx context.Context, identity *OIDCIdentity) (*SysUser, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &SysUser{
+		Username:   identity.Username,
+		Password:   string(hashed),
+		RealName:   identity.RealName,
+		Email:      identity.Email,
+		Phone:      identity.Phone,
+		Status:     1,
+		Source:     UserSourceOIDC,
+		ExternalID: identity.Provider + ":" + identity.Subject,
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("创建OIDC用户失败: %w", err)
+	}
+	return user, nil
+}
+
+// syncUser 同步声明中的资料和角色映射
+func (uc *OIDCUseCase) syncUser(ctx context.Context, opts OIDCProviderOptions, user *SysUser, identity *OIDCIdentity) error {
+	roleCodes, deptCode := resolveGroupMappings(identity.Groups, opts.RoleMappings, opts.DefaultRoleCodes)
+
+	profile := &SysUser{
+		RealName:     strings.TrimSpace(identity.RealName),
+		Email:        identity.Email,
+		Phone:        identity.Phone,
+		DepartmentID: user.DepartmentID,
+		ExternalID:   identity.Provider + ":" + identity.Subject,
+	}
+	if deptCode != "" {
+		deptID, err := uc.identityRepo.GetDepartmentIDByCode(ctx, deptCode)
+		if err != nil {
+			return fmt.Errorf("角色映射的部门 %s 不存在", deptCode)
+		}
+		profile.DepartmentID = deptID
+	}
+	if err := uc.identityRepo.SyncProfile(ctx, user.ID, profile); err != nil {
+		return err
+	}
+
+	if len(opts.RoleMappings) == 0 && len(opts.DefaultRoleCodes) == 0 {
+		return nil
+	}
+	roleIDs, err := uc.identityRepo.GetRoleIDsByCodes(ctx, roleCodes)
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.AssignRoles(ctx, user.ID, roleIDs)
+}
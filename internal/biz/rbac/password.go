@@ -0,0 +1,318 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 需要修改密码的原因
+const (
+	PasswordChangeReasonInitial = "initial" // 管理员创建或重置后首次登录
+	PasswordChangeReasonExpired = "expired" // 密码已过期
+)
+
+var (
+	// ErrAccountLocked 登录失败次数过多，账号已锁定
+	ErrAccountLocked = errors.New("账号已锁定")
+	// ErrPasswordReused 新密码与最近使用过的密码相同
+	ErrPasswordReused = errors.New("不能使用最近使用过的密码")
+)
+
+// commonPasswords 内置的常见弱密码，比较时忽略大小写
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "111111", "000000", "888888", "666666",
+	"123123", "654321", "abc123", "abc12345", "a123456", "a12345678", "qwerty", "qwerty123",
+	"qwe123", "1qaz2wsx", "1q2w3e4r", "1q2w3e4r5t", "zaq12wsx", "asdf1234", "password",
+	"password1", "password123", "passw0rd", "p@ssw0rd", "p@ssword", "admin", "admin123",
+	"admin@123", "admin888", "root", "root123", "root@123", "iloveyou", "welcome", "welcome1",
+	"changeme", "letmein", "test123", "opshub", "opshub123", "opshub@123",
+}
+
+// SysUserPasswordHistory 用户历史密码，用于禁止重复使用
+type SysUserPasswordHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"userId"`
+	Password  string    `gorm:"type:varchar(255);not null;comment:密码哈希" json:"-"`
+}
+
+// TableName 指定表名
+func (SysUserPasswordHistory) TableName() string {
+	return "sys_user_password_history"
+}
+
+// PasswordPolicy 密码策略，数值为 0 表示不限制
+type PasswordPolicy struct {
+	MinLength      int      `json:"minLength"`
+	RequireUpper   bool     `json:"requireUpper"`
+	RequireLower   bool     `json:"requireLower"`
+	RequireDigit   bool     `json:"requireDigit"`
+	RequireSymbol  bool     `json:"requireSymbol"`
+	Blocklist      []string `json:"-"`
+	HistoryCount   int      `json:"historyCount"`   // 不能与最近 N 个密码相同
+	MaxAgeDays     int      `json:"maxAgeDays"`     // 密码有效期（天）
+	WarnDays       int      `json:"warnDays"`       // 过期前多少天开始提醒
+	ForceChange    bool     `json:"forceChange"`    // 管理员创建或重置密码后首次登录必须修改
+	MaxFailures    int      `json:"maxFailures"`    // 窗口期内连续失败多少次后锁定
+	FailureWindow  int      `json:"failureWindow"`  // 失败计数窗口（分钟）
+	LockoutMinutes int      `json:"lockoutMinutes"` // 锁定时长（分钟）
+}
+
+// PasswordUseCase 密码策略、过期和登录失败锁定
+type PasswordUseCase struct {
+	repo     PasswordRepo
+	userRepo UserRepo
+	policy   PasswordPolicy
+	blocked  map[string]struct{}
+}
+
+// NewPasswordUseCase 创建密码策略用例
+func NewPasswordUseCase(repo PasswordRepo, userRepo UserRepo, policy PasswordPolicy) *PasswordUseCase {
+	blocked := make(map[string]struct{}, len(commonPasswords)+len(policy.Blocklist))
+	for _, list := range [][]string{commonPasswords, policy.Blocklist} {
+		for _, p := range list {
+			if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+				blocked[p] = struct{}{}
+			}
+		}
+	}
+	if policy.FailureWindow <= 0 {
+		policy.FailureWindow = 15
+	}
+	if policy.LockoutMinutes <= 0 {
+		policy.LockoutMinutes = 30
+	}
+	return &PasswordUseCase{repo: repo, userRepo: userRepo, policy: policy, blocked: blocked}
+}
+
+// Policy 当前密码策略
+func (uc *PasswordUseCase) Policy() PasswordPolicy {
+	return uc.policy
+}
+
+// Validate 校验密码复杂度和弱密码黑名单
+func (uc *PasswordUseCase) Validate(username, password string) error {
+	p := uc.policy
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("密码长度不能少于%d位", p.MinLength)
+	}
+	if len(password) > 72 {
+		return errors.New("密码长度不能超过72个字符")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "大写字母")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "小写字母")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "数字")
+	}
+	if p.RequireSymbol && !symbol {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("密码必须包含%s", strings.Join(missing, "、"))
+	}
+
+	lowered := strings.ToLower(password)
+	if _, ok := uc.blocked[lowered]; ok {
+		return errors.New("密码过于常见，请使用更复杂的密码")
+	}
+	if username != "" && lowered == strings.ToLower(username) {
+		return errors.New("密码不能与用户名相同")
+	}
+	return nil
+}
+
+// SetPassword 校验策略和历史密码后设置新密码，mustChange 表示下次登录必须修改
+func (uc *PasswordUseCase) SetPassword(ctx context.Context, user *SysUser, password string, mustChange bool) error {
+	if err := uc.Validate(user.Username, password); err != nil {
+		return err
+	}
+	if err := uc.checkReuse(ctx, user, password); err != nil {
+		return err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := uc.repo.SetPassword(ctx, user.ID, string(hashed), now, mustChange); err != nil {
+		return err
+	}
+	if err := uc.remember(ctx, user.ID, string(hashed)); err != nil {
+		return err
+	}
+
+	user.Password = string(hashed)
+	user.PasswordChangedAt = &now
+	user.MustChangePassword = mustChange
+	return nil
+}
+
+// remember 记录新设置的密码，用于之后的重复使用检查
+func (uc *PasswordUseCase) remember(ctx context.Context, userID uint, hash string) error {
+	if uc.policy.HistoryCount <= 0 {
+		return nil
+	}
+	return uc.repo.AddHistory(ctx, userID, hash, uc.policy.HistoryCount)
+}
+
+// checkReuse 新密码不能与当前密码及最近 N 个历史密码相同
+func (uc *PasswordUseCase) checkReuse(ctx context.Context, user *SysUser, password string) error {
+	if uc.policy.HistoryCount <= 0 {
+		return nil
+	}
+	hashes, err := uc.repo.ListHistory(ctx, user.ID, uc.policy.HistoryCount)
+	if err != nil {
+		return err
+	}
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// ForceChangeOnReset 管理员创建或重置密码后是否要求用户修改
+func (uc *PasswordUseCase) ForceChangeOnReset() bool {
+	return uc.policy.ForceChange
+}
+
+// ChangeReason 本地账号需要修改密码时返回原因，否则返回空字符串
+func (uc *PasswordUseCase) ChangeReason(user *SysUser) string {
+	if user.Source != "" && user.Source != UserSourceLocal {
+		return ""
+	}
+	if user.MustChangePassword {
+		return PasswordChangeReasonInitial
+	}
+	if expiresAt := uc.ExpiresAt(user); expiresAt != nil && !time.Now().Before(*expiresAt) {
+		return PasswordChangeReasonExpired
+	}
+	return ""
+}
+
+// ExpiresAt 密码过期时间，未设置有效期或没有修改记录时返回 nil
+func (uc *PasswordUseCase) ExpiresAt(user *SysUser) *time.Time {
+	if uc.policy.MaxAgeDays <= 0 || user.PasswordChangedAt == nil {
+		return nil
+	}
+	if user.Source != "" && user.Source != UserSourceLocal {
+		return nil
+	}
+	expiresAt := user.PasswordChangedAt.AddDate(0, 0, uc.policy.MaxAgeDays)
+	return &expiresAt
+}
+
+// ExpiryWarning 密码即将过期时返回过期时间，用于登录后提醒
+func (uc *PasswordUseCase) ExpiryWarning(user *SysUser) *time.Time {
+	expiresAt := uc.ExpiresAt(user)
+	if expiresAt == nil || uc.policy.WarnDays <= 0 {
+		return nil
+	}
+	if time.Until(*expiresAt) > time.Duration(uc.policy.WarnDays)*24*time.Hour {
+		return nil
+	}
+	return expiresAt
+}
+
+// CheckLocked 检查账号是否因登录失败被锁定
+func (uc *PasswordUseCase) CheckLocked(user *SysUser) error {
+	if user.LockedUntil == nil {
+		return nil
+	}
+	remaining := time.Until(*user.LockedUntil)
+	if remaining <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w，请%d分钟后重试或联系管理员解锁", ErrAccountLocked, int(remaining.Minutes())+1)
+}
+
+// RecordFailure 记录一次登录失败，达到阈值时锁定账号并返回 ErrAccountLocked
+func (uc *PasswordUseCase) RecordFailure(ctx context.Context, user *SysUser) error {
+	if uc.policy.MaxFailures <= 0 {
+		return nil
+	}
+	now := time.Now()
+	window := time.Duration(uc.policy.FailureWindow) * time.Minute
+	count, err := uc.repo.RecordLoginFailure(ctx, user.ID, now.Add(-window), now)
+	if err != nil || count < uc.policy.MaxFailures {
+		return err
+	}
+
+	until := now.Add(time.Duration(uc.policy.LockoutMinutes) * time.Minute)
+	if err := uc.repo.Lock(ctx, user.ID, until); err != nil {
+		return err
+	}
+	user.LockedUntil = &until
+	return fmt.Errorf("%w，请%d分钟后重试或联系管理员解锁", ErrAccountLocked, uc.policy.LockoutMinutes)
+}
+
+// ResetFailures 登录成功后清除失败计数
+func (uc *PasswordUseCase) ResetFailures(ctx context.Context, user *SysUser) error {
+	if user.LoginFailures == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	if err := uc.repo.Unlock(ctx, user.ID); err != nil {
+		return err
+	}
+	user.LoginFailures = 0
+	user.LoginFailedAt = nil
+	user.LockedUntil = nil
+	return nil
+}
+
+// Unlock 管理员解锁账号
+func (uc *PasswordUseCase) Unlock(ctx context.Context, userID uint) error {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return errors.New("用户不存在")
+	}
+	return uc.repo.Unlock(ctx, userID)
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RoleCodeAdmin 超级管理员角色编码，拥有全部权限
+const RoleCodeAdmin = "admin"
+
+// permissionCacheTTL 用户权限缓存时间，角色或菜单变更时会主动失效
+const permissionCacheTTL = 30 * time.Second
+
+// ErrPermissionDenied 缺少接口对应的按钮权限
+var ErrPermissionDenied = errors.New("权限不足")
+
+// ErrPermissionUnregistered 接口既未登记权限也不在登录即可访问的列表中
+var ErrPermissionUnregistered = errors.New("接口未登记权限")
+
+// PermissionRoute 受权限控制的接口
+type PermissionRoute struct {
+	Method string `json:"method"`
+	Path   string `json:"path"` // gin 路由模式，例如 /api/v1/users/:id
+}
+
+// PermissionDefinition 权限定义，对应菜单下的一个按钮
+type PermissionDefinition struct {
+	Code     string            `json:"code"`     // 按钮编码，同时作为 sys_menu.code
+	Name     string            `json:"name"`     // 按钮名称
+	MenuCode string            `json:"menuCode"` // 所属菜单编码
+	Routes   []PermissionRoute `json:"routes"`
+}
+
+// PermissionHolder 持有权限的角色
+type PermissionHolder struct {
+	RoleID   uint   `json:"roleId"`
+	RoleName string `json:"roleName"`
+	RoleCode string `json:"roleCode"`
+}
+
+// PermissionInfo 权限及其持有角色
+type PermissionInfo struct {
+	PermissionDefinition
+	MenuID uint               `json:"menuId"`
+	Roles  []PermissionHolder `json:"roles"`
+}
+
+// PermissionRegistry 接口到按钮权限的映射表
+type PermissionRegistry struct {
+	mu      sync.RWMutex
+	defs    map[string]*PermissionDefinition
+	order   []string
+	byRoute map[string]string
+	allowed map[string]struct{}
+}
+
+// NewPermissionRegistry 创建权限映射表
+func NewPermissionRegistry() *PermissionRegistry {
+	return &PermissionRegistry{
+		defs:    make(map[string]*PermissionDefinition),
+		byRoute: make(map[string]string),
+		allowed: make(map[string]struct{}),
+	}
+}
+
+// Register 注册权限定义，同一编码多次注册时合并接口，同一接口只能对应一个权限
+func (r *PermissionRegistry) Register(defs ...PermissionDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, def := range defs {
+		existing, ok := r.defs[def.Code]
+		if !ok {
+			d := def
+			d.Routes = nil
+			existing = &d
+			r.defs[def.Code] = existing
+			r.order = append(r.order, def.Code)
+		}
+		for _, route := range def.Routes {
+			key := routeKey(route.Method, route.Path)
+			if _, taken := r.byRoute[key]; taken {
+				continue
+			}
+			r.byRoute[key] = def.Code
+			existing.Routes = append(existing.Routes, route)
+		}
+	}
+}
+
+// Allow 登记只需登录即可访问的接口，例如个人中心和下拉选项
+func (r *PermissionRegistry) Allow(routes ...PermissionRoute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, route := range routes {
+		r.allowed[routeKey(route.Method, route.Path)] = struct{}{}
+	}
+}
+
+// Lookup 查找接口需要的权限编码，未注册的接口返回空
+func (r *PermissionRegistry) Lookup(method, fullPath string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byRoute[routeKey(method, fullPath)]
+}
+
+// IsAllowed 接口是否只需登录即可访问
+func (r *PermissionRegistry) IsAllowed(method, fullPath string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.allowed[routeKey(method, fullPath)]
+	return ok
+}
+
+// Validate 校验登记的接口都能对应到实际路由，防止路径写错后权限静默失效
+func (r *PermissionRegistry) Validate(routes []PermissionRoute) error {
+	existing := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		existing[routeKey(route.Method, route.Path)] = struct{}{}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var missing []string
+	for key := range r.byRoute {
+		if _, ok := existing[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	for key := range r.allowed {
+		if _, ok := existing[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("以下登记的接口没有对应的路由: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Definitions 返回全部权限定义，按注册顺序排列
+func (r *PermissionRegistry) Definitions() []PermissionDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]PermissionDefinition, 0, len(r.order))
+	for _, code := range r.order {
+		def := *r.defs[code]
+		def.Routes = append([]PermissionRoute(nil), def.Routes...)
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+type permissionCacheEntry struct {
+	admin   bool
+	codes   map[string]struct{}
+	expires time.Time
+}
+
+// PermissionUseCase 按钮权限校验
+type PermissionUseCase struct {
+	repo     PermissionRepo
+	roleRepo RoleRepo
+	registry *PermissionRegistry
+
+	mu    sync.Mutex
+	cache map[uint]*permissionCacheEntry
+}
+
+// NewPermissionUseCase 创建按钮权限用例
+func NewPermissionUseCase(repo PermissionRepo, roleRepo RoleRepo, registry *PermissionRegistry) *PermissionUseCase {
+	return &PermissionUseCase{
+		repo:     repo,
+		roleRepo: roleRepo,
+		registry: registry,
+		cache:    make(map[uint]*permissionCacheEntry),
+	}
+}
+
+// Registry 返回权限映射表
+func (uc *PermissionUseCase) Registry() *PermissionRegistry {
+	return uc.registry
+}
+
+// Sync 将已注册的权限同步为菜单下的按钮，返回新建的按钮数量
+func (uc *PermissionUseCase) Sync(ctx context.Context) (int, error) {
+	created, err := uc.repo.SyncButtons(ctx, uc.registry.Definitions())
+	if err != nil {
+		return 0, err
+	}
+	uc.Invalidate()
+	return created, nil
+}
+
+// Check 校验用户是否可以访问接口，未登记权限的接口拒绝访问，登录即可访问的接口除外
+func (uc *PermissionUseCase) Check(ctx context.Context, userID uint, method, fullPath string) (string, error) {
+	code := uc.registry.Lookup(method, fullPath)
+	if code == "" {
+		if !uc.registry.IsAllowed(method, fullPath) {
+			return "", ErrPermissionUnregistered
+		}
+		return "", nil
+	}
+	entry, err := uc.load(ctx, userID)
+	if err != nil {
+		return code, err
+	}
+	if entry.admin {
+		return code, nil
+	}
+	if _, ok := entry.codes[code]; !ok {
+		return code, ErrPermissionDenied
+	}
+	return code, nil
+}
+
+// GetUserCodes 获取用户拥有的按钮权限编码，管理员返回全部已注册权限
+func (uc *PermissionUseCase) GetUserCodes(ctx context.Context, userID uint) ([]string, error) {
+	entry, err := uc.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(entry.codes))
+	if entry.admin {
+		for _, def := range uc.registry.Definitions() {
+			codes = append(codes, def.Code)
+		}
+		return codes, nil
+	}
+	for code := range entry.codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+// List 列出全部权限及持有角色
+func (uc *PermissionUseCase) List(ctx context.Context) ([]PermissionInfo, error) {
+	defs := uc.registry.Definitions()
+	codes := make([]string, 0, len(defs))
+	for _, def := range defs {
+		codes = append(codes, def.Code)
+	}
+	menuIDs, holders, err := uc.repo.ListHolders(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]PermissionInfo, 0, len(defs))
+	for _, def := range defs {
+		roles := holders[def.Code]
+		if roles == nil {
+			roles = []PermissionHolder{}
+		}
+		list = append(list, PermissionInfo{
+			PermissionDefinition: def,
+			MenuID:               menuIDs[def.Code],
+			Roles:                roles,
+		})
+	}
+	return list, nil
+}
+
+// Invalidate 清空权限缓存，角色授权或菜单变更后调用
+func (uc *PermissionUseCase) Invalidate() {
+	uc.mu.Lock()
+	uc.cache = make(map[uint]*permissionCacheEntry)
+	uc.mu.Unlock()
+}
+
+func (uc *PermissionUseCase) load(ctx context.Context, userID uint) (*permissionCacheEntry, error) {
+	now := time.Now()
+	uc.mu.Lock()
+	entry, ok := uc.cache[userID]
+	uc.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry, nil
+	}
+
+	roles, err := uc.roleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entry = &permissionCacheEntry{codes: make(map[string]struct{}), expires: now.Add(permissionCacheTTL)}
+	for _, role := range roles {
+		if role.Code == RoleCodeAdmin {
+			entry.admin = true
+			break
+		}
+	}
+	if !entry.admin {
+		codes, err := uc.repo.GetCodesByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range codes {
+			entry.codes[code] = struct{}{}
+		}
+	}
+
+	uc.mu.Lock()
+	uc.cache[userID] = entry
+	uc.mu.Unlock()
+	return entry, nil
+}
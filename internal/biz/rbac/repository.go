@@ -19,7 +19,10 @@
 
 package rbac
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type UserRepo interface {
 	Create(ctx context.Context, user *SysUser) error
@@ -28,11 +31,102 @@ type UserRepo interface {
 	GetByID(ctx context.Context, id uint) (*SysUser, error)
 	GetByUsername(ctx context.Context, username string) (*SysUser, error)
 	List(ctx context.Context, page, pageSize int, keyword string, departmentID uint) ([]*SysUser, int64, error)
+	// 不分页获取全部符合条件的用户，用于导出
+	ListAll(ctx context.Context, keyword string, departmentID uint) ([]*SysUser, error)
 	AssignRoles(ctx context.Context, userID uint, roleIDs []uint) error
 	AssignPositions(ctx context.Context, userID uint, positionIDs []uint) error
 	UpdateLastLogin(ctx context.Context, userID uint) error
 }
 
+// ExternalIdentityRepo 外部目录用户的本地映射
+type ExternalIdentityRepo interface {
+	ListBySource(ctx context.Context, source string) ([]*SysUser, error)
+	SyncProfile(ctx context.Context, userID uint, profile *SysUser) error
+	SetStatus(ctx context.Context, userID uint, status int) error
+	GetRoleIDsByCodes(ctx context.Context, codes []string) ([]uint, error)
+	GetDepartmentIDByCode(ctx context.Context, code string) (uint, error)
+	GetUserByEmail(ctx context.Context, email string) (*SysUser, error)
+	GetIdentityUserID(ctx context.Context, provider, subject string) (uint, error)
+	CreateIdentity(ctx context.Context, identity *SysUserIdentity) error
+	TouchIdentity(ctx context.Context, provider, subject, email string) error
+}
+
+// SessionStore 登录会话存储
+type SessionStore interface {
+	Save(ctx context.Context, session *Session, ttl time.Duration) error
+	// Get 会话不存在时返回 ErrSessionNotFound
+	Get(ctx context.Context, id string) (*Session, error)
+	Touch(ctx context.Context, id, ip string, at time.Time) error
+	// Rotate 刷新令牌摘要等于 oldHash 时原子替换为 newHash，并延长有效期
+	Rotate(ctx context.Context, id, oldHash, newHash, ip string, at time.Time, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, userID uint, id string) error
+	ListByUser(ctx context.Context, userID uint) ([]*Session, error)
+}
+
+// APITokenRepo API 令牌仓储
+type APITokenRepo interface {
+	Create(ctx context.Context, token *SysAPIToken) error
+	GetByID(ctx context.Context, id uint) (*SysAPIToken, error)
+	GetByHash(ctx context.Context, hash string) (*SysAPIToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]*SysAPIToken, error)
+	Delete(ctx context.Context, id uint) error
+	DeleteByUser(ctx context.Context, userID uint) error
+	Touch(ctx context.Context, id uint, usedAt time.Time, ip string) error
+}
+
+// MFARepo 双因素认证仓储，密钥在仓储层加密存储
+type MFARepo interface {
+	GetByUserID(ctx context.Context, userID uint) (*SysUserMFA, error)
+	Save(ctx context.Context, mfa *SysUserMFA) error
+	Delete(ctx context.Context, userID uint) error
+	MarkStepUsed(ctx context.Context, userID uint, step int64) (bool, error)
+	RecordFailure(ctx context.Context, userID uint, lockUntil *time.Time) error
+	ResetFailures(ctx context.Context, userID uint) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+	RoleRequiresMFA(ctx context.Context, userID uint) (bool, error)
+	SetRoleRequireMFA(ctx context.Context, roleID uint, required bool) error
+}
+
+// PasswordRepo 密码历史和登录失败锁定仓储
+type PasswordRepo interface {
+	SetPassword(ctx context.Context, userID uint, hash string, changedAt time.Time, mustChange bool) error
+	AddHistory(ctx context.Context, userID uint, hash string, keep int) error
+	ListHistory(ctx context.Context, userID uint, limit int) ([]string, error)
+	RecordLoginFailure(ctx context.Context, userID uint, windowStart, now time.Time) (int, error)
+	Lock(ctx context.Context, userID uint, until time.Time) error
+	Unlock(ctx context.Context, userID uint) error
+}
+
+// PermissionRepo 按钮权限仓储
+type PermissionRepo interface {
+	// 获取用户通过角色拥有的已启用按钮编码
+	GetCodesByUserID(ctx context.Context, userID uint) ([]string, error)
+	// 确保每个权限都有对应的按钮菜单，新建的按钮授予已拥有父菜单的角色
+	SyncButtons(ctx context.Context, defs []PermissionDefinition) (int, error)
+	// 获取按钮菜单ID和持有角色
+	ListHolders(ctx context.Context, codes []string) (map[string]uint, map[string][]PermissionHolder, error)
+}
+
+// SCIMRepo SCIM 下发使用的用户、角色成员和部门仓储
+type SCIMRepo interface {
+	// 按条件分页获取除服务账号外的用户，预加载部门和角色，同时返回符合条件的总数
+	ListUsers(ctx context.Context, page *SCIMPage) ([]*SysUser, int64, error)
+	// 保存 SCIM 管理的用户属性
+	SaveUser(ctx context.Context, user *SysUser) error
+	// 获取全部角色及其成员
+	ListRoles(ctx context.Context) ([]*SysRole, error)
+	// 按条件分页获取角色及其成员，同时返回符合条件的总数
+	PageRoles(ctx context.Context, page *SCIMPage) ([]*SysRole, int64, error)
+	GetRole(ctx context.Context, id uint) (*SysRole, error)
+	AddRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error
+	RemoveRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error
+	SetRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error
+	// 按名称或编码查找部门，不存在时创建顶级部门
+	FindOrCreateDepartment(ctx context.Context, name string) (uint, error)
+}
+
 type RoleRepo interface {
 	Create(ctx context.Context, role *SysRole) error
 	Update(ctx context.Context, role *SysRole) error
@@ -79,7 +173,7 @@ type AssetPermissionRepo interface {
 	// 创建资产权限（批量）
 	CreateBatch(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint) error
 	// 创建资产权限（支持操作权限）
-	CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error
+	CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error
 	// 删除指定角色对指定资产分组的所有权限
 	DeleteByRoleAndGroup(ctx context.Context, roleID, assetGroupID uint) error
 	// 删除单个权限
@@ -87,7 +181,7 @@ type AssetPermissionRepo interface {
 	// 根据ID获取权限详情（用于编辑）
 	GetDetailByID(ctx context.Context, id uint) (*AssetPermissionDetailVO, error)
 	// 更新权限配置（支持修改角色、分组、主机、权限）
-	UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error
+	UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error
 	// 获取角色的所有资产权限
 	GetByRoleID(ctx context.Context, roleID uint) ([]*AssetPermissionInfo, error)
 	// 获取资产分组的所有权限配置
@@ -102,4 +196,46 @@ type AssetPermissionRepo interface {
 	GetUserHostPermissions(ctx context.Context, userID, hostID uint) (uint, error)
 	// 获取用户有权限访问的所有主机ID列表
 	GetUserAccessibleHostIDs(ctx context.Context, userID uint) ([]uint, error)
+	// 检查用户是否有对指定数据库的特定操作权限
+	CheckDatabaseOperationPermission(ctx context.Context, userID, databaseID uint, operation uint) (bool, error)
+	// 获取用户对指定数据库的所有操作权限
+	GetUserDatabasePermissions(ctx context.Context, userID, databaseID uint) (uint, error)
+	// 获取用户有权限访问的所有数据库ID列表
+	GetUserAccessibleDatabaseIDs(ctx context.Context, userID uint) ([]uint, error)
+	// 创建资产授权（用户/部门/角色，支持时效和显式拒绝）
+	CreateGrant(ctx context.Context, grant *SysAssetGrant) error
+	// 更新资产授权
+	UpdateGrant(ctx context.Context, grant *SysAssetGrant) error
+	// 删除资产授权
+	DeleteGrant(ctx context.Context, id uint) error
+	// 根据ID获取资产授权
+	GetGrantByID(ctx context.Context, id uint) (*AssetGrantInfo, error)
+	// 分页查询资产授权
+	ListGrants(ctx context.Context, req *AssetGrantListReq) ([]*AssetGrantInfo, int64, error)
+	// 解释用户对指定主机的有效权限及来源
+	ExplainHostPermissions(ctx context.Context, userID, hostID uint) (*AssetPermissionExplainVO, error)
+	// 清理已过期的权限和授权，返回清理条数
+	SweepExpired(ctx context.Context) (int64, error)
+	// 检查用户是否在任意资产上拥有指定操作权限
+	HasAnyPermission(ctx context.Context, userID uint, operation uint) (bool, error)
+}
+
+// AccessRequestRepo 访问申请仓储
+type AccessRequestRepo interface {
+	Create(ctx context.Context, req *SysAccessRequest) error
+	Update(ctx context.Context, req *SysAccessRequest) error
+	GetByID(ctx context.Context, id uint) (*SysAccessRequest, error)
+	List(ctx context.Context, req *AccessRequestListReq, userID uint) ([]*SysAccessRequest, int64, error)
+	// 获取已到期但仍处于批准状态的申请
+	ListDue(ctx context.Context, now time.Time) ([]*SysAccessRequest, error)
+	// 在同一事务中保存待审批申请的审批结果并创建授权，bind 在提交前执行，任一步失败整体回滚
+	// 申请已不是待审批状态时返回 ErrAccessRequestReviewed
+	Review(ctx context.Context, req *SysAccessRequest, grant *SysAssetGrant, bind func(ctx context.Context) error) error
+	AddEvent(ctx context.Context, event *SysAccessRequestEvent) error
+	ListEvents(ctx context.Context, requestID uint) ([]*SysAccessRequestEvent, error)
+	// 根据资产分组和申请人部门解析审批人，未配置时回退到管理员
+	ResolveApprovers(ctx context.Context, assetGroupID, userID uint) ([]uint, error)
+	SetApprovers(ctx context.Context, scopeType string, scopeID uint, userIDs []uint) error
+	ListApprovers(ctx context.Context, scopeType string, scopeID uint) ([]*SysAccessApprover, error)
+	IsAdmin(ctx context.Context, userID uint) (bool, error)
 }
@@ -0,0 +1,1151 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// SCIM 2.0 协议使用的 schema 标识
+const (
+	SCIMSchemaUser           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaEnterpriseUser = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SCIMSchemaGroup          = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaListResponse   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError          = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// UserSourceSCIM 由身份提供者通过 SCIM 下发的账号
+const UserSourceSCIM = "scim"
+
+// SCIMBasePath SCIM 接口的路由前缀，用于生成资源的 meta.location
+const SCIMBasePath = "/scim/v2"
+
+const (
+	scimDefaultCount = 100
+	scimMaxCount     = 1000
+)
+
+// scimUserColumns 可以在数据库中过滤的用户属性
+var scimUserColumns = map[string]string{
+	"id":                 "id",
+	"username":           "username",
+	"externalid":         "external_id",
+	"displayname":        "real_name",
+	"name.formatted":     "real_name",
+	"emails.value":       "email",
+	"phonenumbers.value": "phone",
+}
+
+// scimGroupColumns 可以在数据库中过滤的用户组属性
+var scimGroupColumns = map[string]string{
+	"id":          "id",
+	"displayname": "name",
+}
+
+// SCIMError SCIM 协议错误，按 RFC 7644 3.12 的格式返回给身份提供者
+type SCIMError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *SCIMError) Error() string {
+	return e.Detail
+}
+
+func scimErrorf(status int, scimType, format string, args ...interface{}) *SCIMError {
+	return &SCIMError{Status: status, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+// SCIMName 用户姓名
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMMultiValue 邮箱、电话等多值属性
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMMember 用户组成员或用户所属的组
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMEnterpriseUser 企业用户扩展，部门映射到系统部门
+type SCIMEnterpriseUser struct {
+	Department string `json:"department,omitempty"`
+}
+
+// SCIMMeta 资源元数据
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// SCIMUser SCIM 用户资源，对应 SysUser
+type SCIMUser struct {
+	Schemas      []string            `json:"schemas"`
+	ID           string              `json:"id,omitempty"`
+	ExternalID   string              `json:"externalId,omitempty"`
+	UserName     string              `json:"userName"`
+	Name         *SCIMName           `json:"name,omitempty"`
+	DisplayName  string              `json:"displayName,omitempty"`
+	Password     string              `json:"password,omitempty"`
+	Active       *bool               `json:"active,omitempty"`
+	Emails       []SCIMMultiValue    `json:"emails,omitempty"`
+	PhoneNumbers []SCIMMultiValue    `json:"phoneNumbers,omitempty"`
+	Groups       []SCIMMember        `json:"groups,omitempty"`
+	Enterprise   *SCIMEnterpriseUser `json:"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User,omitempty"`
+	Meta         *SCIMMeta           `json:"meta,omitempty"`
+}
+
+// SCIMGroup SCIM 用户组资源，对应 SysRole
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+	Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+// SCIMListQuery 列表查询参数，startIndex 从 1 开始
+type SCIMListQuery struct {
+	Filter             string
+	StartIndex         int
+	Count              int
+	Attributes         []string
+	ExcludedAttributes []string
+}
+
+// SCIMListResponse 列表响应
+type SCIMListResponse struct {
+	Schemas      []string                 `json:"schemas"`
+	TotalResults int                      `json:"totalResults"`
+	StartIndex   int                      `json:"startIndex"`
+	ItemsPerPage int                      `json:"itemsPerPage"`
+	Resources    []map[string]interface{} `json:"Resources"`
+}
+
+// SCIMPage 在数据库中执行的过滤和分页条件，Limit 小于 0 表示不分页
+type SCIMPage struct {
+	Where  *SCIMWhere
+	Offset int
+	Limit  int
+}
+
+// SCIMPatchRequest PATCH 请求体
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+// SCIMPatchOperation 单个 PATCH 操作
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// SCIMUseCase SCIM 用户和用户组的下发逻辑
+type SCIMUseCase struct {
+	repo              SCIMRepo
+	userRepo          UserRepo
+	roleRepo          RoleRepo
+	identityRepo      ExternalIdentityRepo
+	userUseCase       *UserUseCase
+	sessionUseCase    *SessionUseCase
+	permissionUseCase *PermissionUseCase
+	defaultRoles      []string
+}
+
+func NewSCIMUseCase(repo SCIMRepo, userRepo UserRepo, roleRepo RoleRepo, identityRepo ExternalIdentityRepo, userUseCase *UserUseCase, defaultRoles []string) *SCIMUseCase {
+	return &SCIMUseCase{
+		repo:         repo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		identityRepo: identityRepo,
+		userUseCase:  userUseCase,
+		defaultRoles: defaultRoles,
+	}
+}
+
+// SetSessionUseCase 设置会话管理，禁用或删除用户时吊销其登录会话
+func (uc *SCIMUseCase) SetSessionUseCase(sessionUseCase *SessionUseCase) {
+	uc.sessionUseCase = sessionUseCase
+}
+
+// SetPermissionUseCase 设置权限校验，组成员变化后清空权限缓存
+func (uc *SCIMUseCase) SetPermissionUseCase(permissionUseCase *PermissionUseCase) {
+	uc.permissionUseCase = permissionUseCase
+}
+
+// ListUsers 按过滤条件分页查询用户
+func (uc *SCIMUseCase) ListUsers(ctx context.Context, q *SCIMListQuery) (*SCIMListResponse, error) {
+	filter, err := ParseSCIMFilter(q.Filter)
+	if err != nil {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidFilter", "%s", err.Error())
+	}
+	page, pushed := newSCIMPage(filter, scimUserColumns, q)
+	users, total, err := uc.repo.ListUsers(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		resource, err := scimResourceMap(toSCIMUser(user))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	if pushed {
+		return scimListResponse(resources, int(total), q), nil
+	}
+	return paginateSCIM(resources, filter, q), nil
+}
+
+// GetUser 查询单个用户
+func (uc *SCIMUseCase) GetUser(ctx context.Context, id string) (*SCIMUser, error) {
+	user, err := uc.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// CreateUser 创建用户，未提供密码时设置随机密码，只能通过身份提供者登录
+func (uc *SCIMUseCase) CreateUser(ctx context.Context, in *SCIMUser) (*SCIMUser, error) {
+	username := strings.TrimSpace(in.UserName)
+	if username == "" {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "userName 不能为空")
+	}
+	if _, err := uc.userRepo.GetByUsername(ctx, username); err == nil {
+		return nil, scimErrorf(http.StatusConflict, "uniqueness", "用户名 %s 已存在", username)
+	}
+
+	user := &SysUser{Username: username, Source: UserSourceSCIM}
+	if err := uc.applyUser(ctx, user, in, scimRealName(in)); err != nil {
+		return nil, err
+	}
+
+	if in.Password != "" {
+		user.Password = in.Password
+		if err := uc.userUseCase.Create(ctx, user); err != nil {
+			return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+		}
+	} else {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = string(hashed)
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	// Create 会因 gorm 默认值把禁用状态写成启用，这里再按请求保存一次
+	if err := uc.repo.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// 超级管理员角色不会作为默认角色分配给 SCIM 下发的账号
+	defaultRoles := make([]string, 0, len(uc.defaultRoles))
+	for _, code := range uc.defaultRoles {
+		if code != RoleCodeAdmin {
+			defaultRoles = append(defaultRoles, code)
+		}
+	}
+	if len(defaultRoles) > 0 {
+		roleIDs, err := uc.identityRepo.GetRoleIDsByCodes(ctx, defaultRoles)
+		if err != nil {
+			return nil, err
+		}
+		if len(roleIDs) > 0 {
+			if err := uc.userRepo.AssignRoles(ctx, user.ID, roleIDs); err != nil {
+				return nil, err
+			}
+			uc.invalidatePermissions()
+		}
+	}
+	return uc.GetUser(ctx, strconv.FormatUint(uint64(user.ID), 10))
+}
+
+// ReplaceUser 用请求中的属性整体替换用户（PUT）
+func (uc *SCIMUseCase) ReplaceUser(ctx context.Context, id string, in *SCIMUser) (*SCIMUser, error) {
+	user, err := uc.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.saveUser(ctx, user, in, scimRealName(in))
+}
+
+// PatchUser 按 PATCH 操作修改用户
+func (uc *SCIMUseCase) PatchUser(ctx context.Context, id string, req *SCIMPatchRequest) (*SCIMUser, error) {
+	user, err := uc.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	original := toSCIMUser(user)
+	resource, err := scimResourceMap(original)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range req.Operations {
+		if err := applySCIMPatch(resource, op); err != nil {
+			return nil, err
+		}
+	}
+
+	// 部分身份提供者把 active 作为字符串 "True"/"False" 下发
+	if active, ok := resource["active"].(string); ok {
+		resource["active"] = strings.EqualFold(active, "true")
+	}
+
+	patched := &SCIMUser{}
+	raw, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, patched); err != nil {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "PATCH 结果不是合法的用户: %s", err.Error())
+	}
+	return uc.saveUser(ctx, user, patched, patchedRealName(original, patched, user.RealName))
+}
+
+// DeleteUser 删除用户并吊销其会话
+func (uc *SCIMUseCase) DeleteUser(ctx context.Context, id string) error {
+	user, err := uc.getManagedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	uc.revokeSessions(ctx, user.ID)
+	uc.invalidatePermissions()
+	return nil
+}
+
+// ListGroups 按过滤条件分页查询用户组
+func (uc *SCIMUseCase) ListGroups(ctx context.Context, q *SCIMListQuery) (*SCIMListResponse, error) {
+	filter, err := ParseSCIMFilter(q.Filter)
+	if err != nil {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidFilter", "%s", err.Error())
+	}
+	page, pushed := newSCIMPage(filter, scimGroupColumns, q)
+	roles, total, err := uc.repo.PageRoles(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]map[string]interface{}, 0, len(roles))
+	for _, role := range roles {
+		resource, err := scimResourceMap(toSCIMGroup(role))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, resource)
+	}
+	if pushed {
+		return scimListResponse(resources, int(total), q), nil
+	}
+	return paginateSCIM(resources, filter, q), nil
+}
+
+// GetGroup 查询单个用户组
+func (uc *SCIMUseCase) GetGroup(ctx context.Context, id string) (*SCIMGroup, error) {
+	roleID, err := parseSCIMID(id, "Group")
+	if err != nil {
+		return nil, err
+	}
+	role, err := uc.repo.GetRole(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scimErrorf(http.StatusNotFound, "", "Group %s 不存在", id)
+		}
+		return nil, err
+	}
+	return toSCIMGroup(role), nil
+}
+
+// CreateGroup 创建用户组，对应新建一个角色，角色编码由名称生成
+// 新角色的数据范围为仅本人，需要更大范围时由管理员在系统内调整
+func (uc *SCIMUseCase) CreateGroup(ctx context.Context, in *SCIMGroup) (*SCIMGroup, error) {
+	name := strings.TrimSpace(in.DisplayName)
+	if name == "" {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "displayName 不能为空")
+	}
+	roles, err := uc.repo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		if role.Name == name {
+			return nil, scimErrorf(http.StatusConflict, "uniqueness", "用户组 %s 已存在", name)
+		}
+		codes[role.Code] = true
+	}
+	memberIDs, err := scimMemberIDs(in.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &SysRole{
+		Name:        name,
+		Code:        scimRoleCode(name, codes),
+		Description: "由 SCIM 创建",
+		Status:      1,
+		DataScope:   DataScopeSelf,
+	}
+	if err := uc.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SetRoleMembers(ctx, role.ID, memberIDs); err != nil {
+		return nil, err
+	}
+	uc.invalidatePermissions()
+	return uc.GetGroup(ctx, strconv.FormatUint(uint64(role.ID), 10))
+}
+
+// ReplaceGroup 整体替换用户组名称和成员（PUT）
+func (uc *SCIMUseCase) ReplaceGroup(ctx context.Context, id string, in *SCIMGroup) (*SCIMGroup, error) {
+	group, err := uc.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	roleID, _ := parseSCIMID(group.ID, "Group")
+	if err := uc.checkMembersMutable(ctx, roleID); err != nil {
+		return nil, err
+	}
+	if err := uc.renameGroup(ctx, roleID, in.DisplayName); err != nil {
+		return nil, err
+	}
+	memberIDs, err := scimMemberIDs(in.Members)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SetRoleMembers(ctx, roleID, memberIDs); err != nil {
+		return nil, err
+	}
+	uc.invalidatePermissions()
+	return uc.GetGroup(ctx, id)
+}
+
+// PatchGroup 按 PATCH 操作修改用户组，支持成员的增删改和重命名
+func (uc *SCIMUseCase) PatchGroup(ctx context.Context, id string, req *SCIMPatchRequest) (*SCIMGroup, error) {
+	group, err := uc.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	roleID, _ := parseSCIMID(group.ID, "Group")
+
+	for _, op := range req.Operations {
+		kind := strings.ToLower(op.Op)
+		path, memberFilter := splitSCIMValuePath(op.Path)
+		switch {
+		case path == "":
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "未指定 path 时 value 必须是对象")
+			}
+			for key, value := range attrs {
+				if err := uc.patchGroupAttr(ctx, roleID, kind, strings.ToLower(key), "", value); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if err := uc.patchGroupAttr(ctx, roleID, kind, strings.ToLower(path), memberFilter, op.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	uc.invalidatePermissions()
+	return uc.GetGroup(ctx, id)
+}
+
+// DeleteGroup 删除用户组，超级管理员角色不允许删除
+func (uc *SCIMUseCase) DeleteGroup(ctx context.Context, id string) error {
+	roleID, err := parseSCIMID(id, "Group")
+	if err != nil {
+		return err
+	}
+	role, err := uc.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return scimErrorf(http.StatusNotFound, "", "Group %s 不存在", id)
+	}
+	if role.Code == RoleCodeAdmin {
+		return scimErrorf(http.StatusBadRequest, "mutability", "超级管理员角色不允许删除")
+	}
+	if err := uc.roleRepo.Delete(ctx, roleID); err != nil {
+		return err
+	}
+	uc.invalidatePermissions()
+	return nil
+}
+
+func (uc *SCIMUseCase) patchGroupAttr(ctx context.Context, roleID uint, op, path, memberFilter string, value json.RawMessage) error {
+	switch path {
+	case "displayname":
+		if op == "remove" {
+			return scimErrorf(http.StatusBadRequest, "mutability", "displayName 不能删除")
+		}
+		var name string
+		if err := json.Unmarshal(value, &name); err != nil {
+			return scimErrorf(http.StatusBadRequest, "invalidValue", "displayName 必须是字符串")
+		}
+		return uc.renameGroup(ctx, roleID, name)
+	case "members":
+		if err := uc.checkMembersMutable(ctx, roleID); err != nil {
+			return err
+		}
+		var ids []uint
+		if memberFilter != "" {
+			filter, err := ParseSCIMFilter(memberFilter)
+			if err != nil {
+				return scimErrorf(http.StatusBadRequest, "invalidPath", "%s", err.Error())
+			}
+			role, err := uc.repo.GetRole(ctx, roleID)
+			if err != nil {
+				return err
+			}
+			for _, member := range toSCIMGroup(role).Members {
+				if filter.Match(map[string]interface{}{"value": member.Value, "display": member.Display}) {
+					id, _ := strconv.ParseUint(member.Value, 10, 64)
+					ids = append(ids, uint(id))
+				}
+			}
+		} else if len(value) > 0 && string(value) != "null" {
+			var members []SCIMMember
+			if err := json.Unmarshal(value, &members); err != nil {
+				return scimErrorf(http.StatusBadRequest, "invalidValue", "members 必须是数组")
+			}
+			parsed, err := scimMemberIDs(members)
+			if err != nil {
+				return err
+			}
+			ids = parsed
+		}
+
+		switch op {
+		case "add":
+			return uc.repo.AddRoleMembers(ctx, roleID, ids)
+		case "replace":
+			return uc.repo.SetRoleMembers(ctx, roleID, ids)
+		case "remove":
+			if memberFilter == "" && len(ids) == 0 {
+				return uc.repo.SetRoleMembers(ctx, roleID, nil)
+			}
+			return uc.repo.RemoveRoleMembers(ctx, roleID, ids)
+		}
+		return scimErrorf(http.StatusBadRequest, "invalidSyntax", "不支持的操作 %s", op)
+	}
+	// 角色没有对应的属性（如 externalId），忽略
+	return nil
+}
+
+// checkMembersMutable 超级管理员角色的成员只能在系统内调整，不接受身份提供者修改
+func (uc *SCIMUseCase) checkMembersMutable(ctx context.Context, roleID uint) error {
+	role, err := uc.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if role.Code == RoleCodeAdmin {
+		return scimErrorf(http.StatusForbidden, "", "超级管理员角色的成员不允许通过 SCIM 修改")
+	}
+	return nil
+}
+
+func (uc *SCIMUseCase) renameGroup(ctx context.Context, roleID uint, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return scimErrorf(http.StatusBadRequest, "invalidValue", "displayName 不能为空")
+	}
+	role, err := uc.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if role.Name == name {
+		return nil
+	}
+	roles, err := uc.repo.ListRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range roles {
+		if other.ID != roleID && other.Name == name {
+			return scimErrorf(http.StatusConflict, "uniqueness", "用户组 %s 已存在", name)
+		}
+	}
+	role.Name = name
+	return uc.roleRepo.Update(ctx, role)
+}
+
+func (uc *SCIMUseCase) getUser(ctx context.Context, id string) (*SysUser, error) {
+	userID, err := parseSCIMID(id, "User")
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil || user.Source == UserSourceService {
+		return nil, scimErrorf(http.StatusNotFound, "", "User %s 不存在", id)
+	}
+	return user, nil
+}
+
+// getManagedUser 查询允许 SCIM 修改的用户，本地、LDAP、OIDC 等来源的账号只读
+func (uc *SCIMUseCase) getManagedUser(ctx context.Context, id string) (*SysUser, error) {
+	user, err := uc.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Source != UserSourceSCIM {
+		return nil, scimErrorf(http.StatusForbidden, "", "User %s 不是由 SCIM 下发的账号，不能通过 SCIM 修改或删除", id)
+	}
+	return user, nil
+}
+
+// saveUser 把 SCIM 属性写回用户，停用时吊销会话
+func (uc *SCIMUseCase) saveUser(ctx context.Context, user *SysUser, in *SCIMUser, realName string) (*SCIMUser, error) {
+	username := strings.TrimSpace(in.UserName)
+	if username == "" {
+		return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "userName 不能为空")
+	}
+	if username != user.Username {
+		if existing, err := uc.userRepo.GetByUsername(ctx, username); err == nil && existing.ID != user.ID {
+			return nil, scimErrorf(http.StatusConflict, "uniqueness", "用户名 %s 已存在", username)
+		}
+		user.Username = username
+	}
+
+	wasActive := user.Status == 1
+	if err := uc.applyUser(ctx, user, in, realName); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.SaveUser(ctx, user); err != nil {
+		return nil, err
+	}
+	if in.Password != "" {
+		if err := uc.setPassword(ctx, user, in.Password); err != nil {
+			return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "%s", err.Error())
+		}
+	}
+	if wasActive && user.Status != 1 {
+		uc.revokeSessions(ctx, user.ID)
+	}
+	return uc.GetUser(ctx, strconv.FormatUint(uint64(user.ID), 10))
+}
+
+// applyUser 把 SCIM 属性映射到用户字段，部门不存在时自动创建
+func (uc *SCIMUseCase) applyUser(ctx context.Context, user *SysUser, in *SCIMUser, realName string) error {
+	user.RealName = realName
+	user.ExternalID = in.ExternalID
+	user.Email = primarySCIMValue(in.Emails)
+	user.Phone = primarySCIMValue(in.PhoneNumbers)
+	user.Status = 1
+	if in.Active != nil && !*in.Active {
+		user.Status = 0
+	}
+
+	user.DepartmentID = 0
+	if in.Enterprise != nil && strings.TrimSpace(in.Enterprise.Department) != "" {
+		deptID, err := uc.repo.FindOrCreateDepartment(ctx, strings.TrimSpace(in.Enterprise.Department))
+		if err != nil {
+			return err
+		}
+		user.DepartmentID = deptID
+	}
+	return nil
+}
+
+// setPassword 身份提供者同步的密码按密码策略校验，不要求用户下次登录修改
+func (uc *SCIMUseCase) setPassword(ctx context.Context, user *SysUser, password string) error {
+	if uc.userUseCase.passwordUseCase != nil {
+		return uc.userUseCase.passwordUseCase.SetPassword(ctx, user, password, false)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+	return uc.userRepo.Update(ctx, user)
+}
+
+func (uc *SCIMUseCase) revokeSessions(ctx context.Context, userID uint) {
+	if uc.sessionUseCase != nil {
+		_, _ = uc.sessionUseCase.RevokeAll(ctx, userID, "")
+	}
+}
+
+func (uc *SCIMUseCase) invalidatePermissions() {
+	if uc.permissionUseCase != nil {
+		uc.permissionUseCase.Invalidate()
+	}
+}
+
+func toSCIMUser(user *SysUser) *SCIMUser {
+	id := strconv.FormatUint(uint64(user.ID), 10)
+	active := user.Status == 1
+	out := &SCIMUser{
+		Schemas:     []string{SCIMSchemaUser, SCIMSchemaEnterpriseUser},
+		ID:          id,
+		ExternalID:  user.ExternalID,
+		UserName:    user.Username,
+		DisplayName: user.RealName,
+		Active:      &active,
+		Groups:      []SCIMMember{},
+		Meta: &SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     SCIMBasePath + "/Users/" + id,
+		},
+	}
+	if user.RealName != "" {
+		out.Name = &SCIMName{Formatted: user.RealName}
+	}
+	if user.Email != "" {
+		out.Emails = []SCIMMultiValue{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	if user.Phone != "" {
+		out.PhoneNumbers = []SCIMMultiValue{{Value: user.Phone, Type: "work", Primary: true}}
+	}
+	for _, role := range user.Roles {
+		roleID := strconv.FormatUint(uint64(role.ID), 10)
+		out.Groups = append(out.Groups, SCIMMember{Value: roleID, Display: role.Name, Ref: SCIMBasePath + "/Groups/" + roleID})
+	}
+	if user.Department != nil {
+		out.Enterprise = &SCIMEnterpriseUser{Department: user.Department.Name}
+	}
+	return out
+}
+
+func toSCIMGroup(role *SysRole) *SCIMGroup {
+	id := strconv.FormatUint(uint64(role.ID), 10)
+	out := &SCIMGroup{
+		Schemas:     []string{SCIMSchemaGroup},
+		ID:          id,
+		DisplayName: role.Name,
+		Members:     []SCIMMember{},
+		Meta: &SCIMMeta{
+			ResourceType: "Group",
+			Created:      role.CreatedAt,
+			LastModified: role.UpdatedAt,
+			Location:     SCIMBasePath + "/Groups/" + id,
+		},
+	}
+	for _, user := range role.Users {
+		userID := strconv.FormatUint(uint64(user.ID), 10)
+		out.Members = append(out.Members, SCIMMember{Value: userID, Display: user.Username, Ref: SCIMBasePath + "/Users/" + userID})
+	}
+	return out
+}
+
+// scimRealName 依次取 displayName、name.formatted 和姓名拼接作为真实姓名
+func scimRealName(in *SCIMUser) string {
+	if name := strings.TrimSpace(in.DisplayName); name != "" {
+		return name
+	}
+	if in.Name == nil {
+		return ""
+	}
+	if name := strings.TrimSpace(in.Name.Formatted); name != "" {
+		return name
+	}
+	return strings.TrimSpace(in.Name.GivenName + " " + in.Name.FamilyName)
+}
+
+// patchedRealName PATCH 时只有被修改的姓名属性才更新真实姓名
+func patchedRealName(original, patched *SCIMUser, current string) string {
+	if patched.DisplayName != original.DisplayName {
+		return strings.TrimSpace(patched.DisplayName)
+	}
+	var before, after SCIMName
+	if original.Name != nil {
+		before = *original.Name
+	}
+	if patched.Name != nil {
+		after = *patched.Name
+	}
+	if after.Formatted != before.Formatted {
+		return strings.TrimSpace(after.Formatted)
+	}
+	if after.GivenName != before.GivenName || after.FamilyName != before.FamilyName {
+		return strings.TrimSpace(after.GivenName + " " + after.FamilyName)
+	}
+	return current
+}
+
+// primarySCIMValue 取 primary 标记的值，没有时取 work 类型，再退回第一个
+func primarySCIMValue(values []SCIMMultiValue) string {
+	for _, v := range values {
+		if v.Primary {
+			return strings.TrimSpace(v.Value)
+		}
+	}
+	for _, v := range values {
+		if strings.EqualFold(v.Type, "work") {
+			return strings.TrimSpace(v.Value)
+		}
+	}
+	if len(values) > 0 {
+		return strings.TrimSpace(values[0].Value)
+	}
+	return ""
+}
+
+func parseSCIMID(id, resourceType string) (uint, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || n == 0 {
+		return 0, scimErrorf(http.StatusNotFound, "", "%s %s 不存在", resourceType, id)
+	}
+	return uint(n), nil
+}
+
+func scimMemberIDs(members []SCIMMember) ([]uint, error) {
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member.Value, 10, 64)
+		if err != nil || id == 0 {
+			return nil, scimErrorf(http.StatusBadRequest, "invalidValue", "无效的成员 %s", member.Value)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+var scimRoleCodeInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// scimRoleCode 由组名生成唯一的角色编码，非 ASCII 名称退回固定前缀加序号
+func scimRoleCode(name string, existing map[string]bool) string {
+	slug := strings.Trim(scimRoleCodeInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	base := "scim"
+	if slug != "" {
+		base = "scim-" + slug
+	}
+	code := base
+	for i := 2; existing[code]; i++ {
+		code = fmt.Sprintf("%s-%d", base, i)
+	}
+	return code
+}
+
+// scimResourceMap 把资源转成通用 map，用于过滤、属性裁剪和 PATCH
+func scimResourceMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	resource := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// paginateSCIM 过滤并分页，总数为过滤后的数量
+func paginateSCIM(resources []map[string]interface{}, filter SCIMFilter, q *SCIMListQuery) *SCIMListResponse {
+	matched := make([]map[string]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		if filter == nil || filter.Match(resource) {
+			matched = append(matched, resource)
+		}
+	}
+
+	startIndex, count := scimPageBounds(q)
+	page := []map[string]interface{}{}
+	if start := startIndex - 1; start < len(matched) {
+		end := start + count
+		if end > len(matched) {
+			end = len(matched)
+		}
+		page = matched[start:end]
+	}
+	return scimListResponse(page, len(matched), q)
+}
+
+// newSCIMPage 过滤表达式能转换为 SQL 时在数据库中过滤和分页，否则取出全部数据交给 paginateSCIM
+func newSCIMPage(filter SCIMFilter, columns map[string]string, q *SCIMListQuery) (*SCIMPage, bool) {
+	where, ok := SCIMFilterSQL(filter, columns)
+	if !ok {
+		return &SCIMPage{Limit: -1}, false
+	}
+	startIndex, count := scimPageBounds(q)
+	return &SCIMPage{Where: where, Offset: startIndex - 1, Limit: count}, true
+}
+
+// scimPageBounds 规范化 startIndex 和 count
+func scimPageBounds(q *SCIMListQuery) (startIndex, count int) {
+	startIndex = q.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count = q.Count
+	if count <= 0 {
+		count = scimDefaultCount
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+	return startIndex, count
+}
+
+// scimListResponse 组装当前页的列表响应
+func scimListResponse(page []map[string]interface{}, total int, q *SCIMListQuery) *SCIMListResponse {
+	startIndex, _ := scimPageBounds(q)
+	for _, resource := range page {
+		projectSCIMAttributes(resource, q.Attributes, q.ExcludedAttributes)
+	}
+	return &SCIMListResponse{
+		Schemas:      []string{SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// projectSCIMAttributes 按 attributes/excludedAttributes 裁剪顶层属性，id 和 schemas 总是返回
+func projectSCIMAttributes(resource map[string]interface{}, attributes, excluded []string) {
+	always := map[string]bool{"id": true, "schemas": true}
+	if len(attributes) > 0 {
+		keep := map[string]bool{}
+		for _, attr := range attributes {
+			keep[strings.ToLower(strings.SplitN(strings.TrimSpace(attr), ".", 2)[0])] = true
+		}
+		for key := range resource {
+			if !always[key] && !keep[strings.ToLower(key)] {
+				delete(resource, key)
+			}
+		}
+		return
+	}
+	for _, attr := range excluded {
+		attr = strings.ToLower(strings.TrimSpace(attr))
+		for key := range resource {
+			if !always[key] && strings.ToLower(key) == attr {
+				delete(resource, key)
+			}
+		}
+	}
+}
+
+// splitSCIMValuePath 拆分 emails[type eq "work"].value 形式的路径
+// 返回去掉过滤条件后的路径（emails.value）和方括号内的过滤表达式
+func splitSCIMValuePath(path string) (string, string) {
+	path = strings.TrimSpace(path)
+	open := strings.Index(path, "[")
+	closing := strings.LastIndex(path, "]")
+	if open < 0 || closing < open {
+		return path, ""
+	}
+	return path[:open] + path[closing+1:], path[open+1 : closing]
+}
+
+// applySCIMPatch 在资源 map 上执行一个 PATCH 操作
+func applySCIMPatch(resource map[string]interface{}, op SCIMPatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	if kind != "add" && kind != "replace" && kind != "remove" {
+		return scimErrorf(http.StatusBadRequest, "invalidSyntax", "不支持的操作 %s", op.Op)
+	}
+	var value interface{}
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return scimErrorf(http.StatusBadRequest, "invalidValue", "无效的 value")
+		}
+	}
+
+	if strings.TrimSpace(op.Path) == "" {
+		attrs, ok := value.(map[string]interface{})
+		if !ok || kind == "remove" {
+			return scimErrorf(http.StatusBadRequest, "noTarget", "未指定 path 时必须提供对象类型的 value")
+		}
+		for key, v := range attrs {
+			if err := patchSCIMPath(resource, kind, key, "", v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	path, filter := splitSCIMValuePath(op.Path)
+	return patchSCIMPath(resource, kind, path, filter, value)
+}
+
+// patchSCIMPath 修改路径对应的属性，路径可以带 schema URN 前缀或 a.b 子属性
+func patchSCIMPath(resource map[string]interface{}, kind, path, filterExpr string, value interface{}) error {
+	container := resource
+	if idx := strings.LastIndex(path, ":"); idx >= 0 {
+		urn := path[:idx]
+		path = path[idx+1:]
+		if !strings.EqualFold(urn, SCIMSchemaUser) {
+			key := scimMapKey(resource, urn)
+			ext, _ := resource[key].(map[string]interface{})
+			if ext == nil {
+				ext = map[string]interface{}{}
+				resource[key] = ext
+			}
+			container = ext
+		}
+	}
+
+	parts := strings.SplitN(path, ".", 2)
+	key := scimMapKey(container, parts[0])
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+
+	if filterExpr != "" {
+		return patchSCIMMultiValue(container, key, kind, filterExpr, sub, value)
+	}
+	if sub == "" {
+		if kind == "remove" {
+			delete(container, key)
+			return nil
+		}
+		// 多值属性的 add 追加元素，其余情况直接覆盖
+		if existing, ok := container[key].([]interface{}); ok && kind == "add" {
+			if values, ok := value.([]interface{}); ok {
+				container[key] = append(existing, values...)
+				return nil
+			}
+		}
+		// 不带 path 的嵌套对象（如 name）按子属性合并
+		if existing, ok := container[key].(map[string]interface{}); ok {
+			if values, ok := value.(map[string]interface{}); ok {
+				for k, v := range values {
+					existing[scimMapKey(existing, k)] = v
+				}
+				return nil
+			}
+		}
+		container[key] = value
+		return nil
+	}
+
+	nested, _ := container[key].(map[string]interface{})
+	if nested == nil {
+		if kind == "remove" {
+			return nil
+		}
+		nested = map[string]interface{}{}
+		container[key] = nested
+	}
+	subKey := scimMapKey(nested, sub)
+	if kind == "remove" {
+		delete(nested, subKey)
+	} else {
+		nested[subKey] = value
+	}
+	return nil
+}
+
+// patchSCIMMultiValue 修改多值属性中匹配过滤条件的元素，add/replace 找不到时追加新元素
+func patchSCIMMultiValue(container map[string]interface{}, key, kind, filterExpr, sub string, value interface{}) error {
+	filter, err := ParseSCIMFilter(filterExpr)
+	if err != nil || filter == nil {
+		return scimErrorf(http.StatusBadRequest, "invalidPath", "无效的路径过滤条件 %s", filterExpr)
+	}
+	items, _ := container[key].([]interface{})
+	kept := make([]interface{}, 0, len(items))
+	matched := false
+	for _, item := range items {
+		element, ok := item.(map[string]interface{})
+		if !ok || !filter.Match(element) {
+			kept = append(kept, item)
+			continue
+		}
+		matched = true
+		switch {
+		case kind == "remove" && sub == "":
+			continue
+		case kind == "remove":
+			delete(element, scimMapKey(element, sub))
+		case sub == "":
+			if values, ok := value.(map[string]interface{}); ok {
+				element = values
+			}
+		default:
+			element[scimMapKey(element, sub)] = value
+		}
+		kept = append(kept, element)
+	}
+
+	if !matched && kind != "remove" {
+		element := map[string]interface{}{}
+		if sub == "" {
+			if values, ok := value.(map[string]interface{}); ok {
+				element = values
+			}
+		} else {
+			element[sub] = value
+		}
+		// 过滤条件是 type eq "work" 时新元素带上类型
+		if m := scimTypeFilter.FindStringSubmatch(filterExpr); m != nil {
+			element["type"] = m[1]
+		}
+		kept = append(kept, element)
+	}
+	container[key] = kept
+	return nil
+}
+
+var scimTypeFilter = regexp.MustCompile(`(?i)^\s*type\s+eq\s+"([^"]*)"\s*$`)
+
+// scimMapKey 大小写不敏感地查找已有的键，不存在时返回原名称
+func scimMapKey(m map[string]interface{}, name string) string {
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
@@ -0,0 +1,422 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SCIMFilter 解析后的 SCIM 过滤表达式（RFC 7644 3.4.2.2）
+// 支持 eq/ne/co/sw/ew/gt/ge/lt/le/pr 比较、and/or/not 逻辑运算和括号，不支持 [] 值过滤
+type SCIMFilter interface {
+	Match(resource map[string]interface{}) bool
+}
+
+// ParseSCIMFilter 解析过滤表达式，空表达式返回 nil 表示不过滤
+func ParseSCIMFilter(expr string) (SCIMFilter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenizeSCIMFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &scimFilterParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("过滤表达式存在多余内容: %s", p.tokens[p.pos].text)
+	}
+	return filter, nil
+}
+
+type scimToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeSCIMFilter(expr string) ([]scimToken, error) {
+	var tokens []scimToken
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		switch {
+		case ch == ' ' || ch == '\t':
+			i++
+		case ch == '(' || ch == ')':
+			tokens = append(tokens, scimToken{text: string(ch)})
+			i++
+		case ch == '[' || ch == ']':
+			return nil, fmt.Errorf("不支持的过滤表达式: %s", expr)
+		case ch == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				}
+				sb.WriteByte(expr[j])
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("过滤表达式中的字符串未结束")
+			}
+			tokens = append(tokens, scimToken{text: sb.String(), quoted: true})
+			i = j + 1
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t()\"", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, scimToken{text: expr[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type scimFilterParser struct {
+	tokens []scimToken
+	pos    int
+}
+
+func (p *scimFilterParser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *scimFilterParser) parseOr() (SCIMFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = scimLogical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (SCIMFilter, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = scimLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseFactor() (SCIMFilter, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("过滤表达式不完整")
+	}
+	if p.peekKeyword("not") {
+		p.pos++
+		inner, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return scimNot{inner: inner}, nil
+	}
+	if p.peekKeyword("(") {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekKeyword(")") {
+			return nil, fmt.Errorf("过滤表达式缺少右括号")
+		}
+		p.pos++
+		return inner, nil
+	}
+
+	attr := p.tokens[p.pos]
+	if attr.quoted || p.pos+1 >= len(p.tokens) {
+		return nil, fmt.Errorf("过滤表达式不完整")
+	}
+	op := strings.ToLower(p.tokens[p.pos+1].text)
+	p.pos += 2
+	if op == "pr" {
+		return scimComparison{path: attr.text, op: op}, nil
+	}
+	switch op {
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("不支持的比较运算符: %s", op)
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("过滤表达式缺少比较值")
+	}
+	value, err := parseSCIMFilterValue(p.tokens[p.pos])
+	if err != nil {
+		return nil, err
+	}
+	p.pos++
+	return scimComparison{path: attr.text, op: op, value: value}, nil
+}
+
+func parseSCIMFilterValue(token scimToken) (interface{}, error) {
+	if token.quoted {
+		return token.text, nil
+	}
+	switch strings.ToLower(token.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if f, err := strconv.ParseFloat(token.text, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("无效的比较值: %s", token.text)
+}
+
+type scimLogical struct {
+	or          bool
+	left, right SCIMFilter
+}
+
+func (f scimLogical) Match(resource map[string]interface{}) bool {
+	if f.or {
+		return f.left.Match(resource) || f.right.Match(resource)
+	}
+	return f.left.Match(resource) && f.right.Match(resource)
+}
+
+type scimNot struct {
+	inner SCIMFilter
+}
+
+func (f scimNot) Match(resource map[string]interface{}) bool {
+	return !f.inner.Match(resource)
+}
+
+type scimComparison struct {
+	path  string
+	op    string
+	value interface{}
+}
+
+func (f scimComparison) Match(resource map[string]interface{}) bool {
+	values := lookupSCIMAttribute(resource, f.path)
+	if f.op == "pr" {
+		for _, v := range values {
+			if v != nil && v != "" {
+				return true
+			}
+		}
+		return false
+	}
+	if f.op == "ne" {
+		for _, v := range values {
+			if compareSCIMValue(v, "eq", f.value) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		if compareSCIMValue(v, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareSCIMValue(actual interface{}, op string, expected interface{}) bool {
+	switch want := expected.(type) {
+	case nil:
+		return op == "eq" && actual == nil
+	case bool:
+		got, ok := actual.(bool)
+		return ok && op == "eq" && got == want
+	case float64:
+		got, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return got == want
+		case "gt":
+			return got > want
+		case "ge":
+			return got >= want
+		case "lt":
+			return got < want
+		case "le":
+			return got <= want
+		}
+		return false
+	case string:
+		got, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		got, want = strings.ToLower(got), strings.ToLower(want)
+		switch op {
+		case "eq":
+			return got == want
+		case "co":
+			return strings.Contains(got, want)
+		case "sw":
+			return strings.HasPrefix(got, want)
+		case "ew":
+			return strings.HasSuffix(got, want)
+		case "gt":
+			return got > want
+		case "ge":
+			return got >= want
+		case "lt":
+			return got < want
+		case "le":
+			return got <= want
+		}
+	}
+	return false
+}
+
+// lookupSCIMAttribute 按属性路径取值，属性名不区分大小写，多值属性展开为全部元素的取值
+// 路径可以带扩展 schema 前缀，例如 urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department
+func lookupSCIMAttribute(resource map[string]interface{}, path string) []interface{} {
+	current := []interface{}{resource}
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		idx := strings.LastIndex(path, ":")
+		current = collectSCIMField(current, path[:idx])
+		path = path[idx+1:]
+	}
+	for _, name := range strings.Split(path, ".") {
+		current = collectSCIMField(current, name)
+	}
+	return current
+}
+
+func collectSCIMField(values []interface{}, name string) []interface{} {
+	var result []interface{}
+	for _, v := range values {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, field := range obj {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			if list, ok := field.([]interface{}); ok {
+				result = append(result, list...)
+			} else {
+				result = append(result, field)
+			}
+		}
+	}
+	return result
+}
+
+// SCIMWhere 由过滤表达式转换得到的 SQL 条件
+type SCIMWhere struct {
+	SQL  string
+	Args []interface{}
+}
+
+// SCIMFilterSQL 把过滤表达式转换为 SQL 条件，columns 为属性路径（小写）到列名的映射
+// 只转换字符串比较；出现未映射的属性或无法转换的比较时返回 false，由调用方在内存中过滤
+// 列使用大小写不敏感的排序规则，与 SCIM 属性默认不区分大小写一致
+func SCIMFilterSQL(filter SCIMFilter, columns map[string]string) (*SCIMWhere, bool) {
+	switch f := filter.(type) {
+	case nil:
+		return nil, true
+	case scimLogical:
+		left, ok := SCIMFilterSQL(f.left, columns)
+		if !ok {
+			return nil, false
+		}
+		right, ok := SCIMFilterSQL(f.right, columns)
+		if !ok {
+			return nil, false
+		}
+		op := "AND"
+		if f.or {
+			op = "OR"
+		}
+		return &SCIMWhere{
+			SQL:  "(" + left.SQL + " " + op + " " + right.SQL + ")",
+			Args: append(left.Args, right.Args...),
+		}, true
+	case scimNot:
+		inner, ok := SCIMFilterSQL(f.inner, columns)
+		if !ok {
+			return nil, false
+		}
+		return &SCIMWhere{SQL: "NOT " + inner.SQL, Args: inner.Args}, true
+	case scimComparison:
+		column, ok := columns[strings.ToLower(f.path)]
+		if !ok {
+			return nil, false
+		}
+		if f.op == "pr" {
+			return &SCIMWhere{SQL: "(" + column + " IS NOT NULL AND " + column + " <> '')"}, true
+		}
+		value, ok := f.value.(string)
+		if !ok {
+			return nil, false
+		}
+		switch f.op {
+		case "eq":
+			return &SCIMWhere{SQL: column + " = ?", Args: []interface{}{value}}, true
+		case "ne":
+			return &SCIMWhere{SQL: "(" + column + " <> ? OR " + column + " IS NULL)", Args: []interface{}{value}}, true
+		case "co":
+			return &SCIMWhere{SQL: column + " LIKE ?", Args: []interface{}{"%" + escapeSCIMLike(value) + "%"}}, true
+		case "sw":
+			return &SCIMWhere{SQL: column + " LIKE ?", Args: []interface{}{escapeSCIMLike(value) + "%"}}, true
+		case "ew":
+			return &SCIMWhere{SQL: column + " LIKE ?", Args: []interface{}{"%" + escapeSCIMLike(value)}}, true
+		case "gt":
+			return &SCIMWhere{SQL: column + " > ?", Args: []interface{}{value}}, true
+		case "ge":
+			return &SCIMWhere{SQL: column + " >= ?", Args: []interface{}{value}}, true
+		case "lt":
+			return &SCIMWhere{SQL: column + " < ?", Args: []interface{}{value}}, true
+		case "le":
+			return &SCIMWhere{SQL: column + " <= ?", Args: []interface{}{value}}, true
+		}
+	}
+	return nil, false
+}
+
+// escapeSCIMLike 转义 LIKE 中的通配符
+func escapeSCIMLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
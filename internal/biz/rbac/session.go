@@ -0,0 +1,264 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAccessTokenTTL 访问令牌默认有效期
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// DefaultRefreshTokenTTL 刷新令牌默认有效期
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// sessionTouchInterval 最近活动时间的最小更新间隔
+	sessionTouchInterval = time.Minute
+	// refreshReuseGrace 刷新令牌轮换后旧令牌的宽限期，多个标签页同时刷新时不视为盗用
+	refreshReuseGrace = 30 * time.Second
+)
+
+var (
+	// ErrSessionNotFound 会话不存在或已过期
+	ErrSessionNotFound = errors.New("会话不存在或已过期")
+	// ErrSessionRevoked 会话已失效，需要重新登录
+	ErrSessionRevoked = errors.New("登录已过期，请重新登录")
+	// ErrRefreshTokenRotated 刷新令牌刚被其他请求轮换，应使用最新的刷新令牌重试
+	ErrRefreshTokenRotated = errors.New("刷新令牌已更新，请使用最新的令牌")
+)
+
+// Session 登录会话
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       uint      `json:"userId"`
+	Username     string    `json:"username"`
+	LoginType    string    `json:"loginType"`
+	IP           string    `json:"ip"`
+	UserAgent    string    `json:"userAgent"`
+	Device       string    `json:"device"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastActiveAt time.Time `json:"lastActiveAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	RefreshHash  string    `json:"-"`
+	PrevHash     string    `json:"-"`
+	RotatedAt    time.Time `json:"-"`
+	Current      bool      `json:"current"`
+}
+
+// SessionUseCase 登录会话管理
+type SessionUseCase struct {
+	store      SessionStore
+	userRepo   UserRepo
+	refreshTTL time.Duration
+}
+
+// NewSessionUseCase 创建会话用例，refreshTTL 为 0 时使用默认值
+func NewSessionUseCase(store SessionStore, userRepo UserRepo, refreshTTL time.Duration) *SessionUseCase {
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTokenTTL
+	}
+	return &SessionUseCase{
+		store:      store,
+		userRepo:   userRepo,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Create 创建会话，返回会话和刷新令牌（明文只返回一次）
+func (uc *SessionUseCase) Create(ctx context.Context, user *SysUser, loginType, ip, userAgent string) (*Session, string, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:           id,
+		UserID:       user.ID,
+		Username:     user.Username,
+		LoginType:    loginType,
+		IP:           ip,
+		UserAgent:    userAgent,
+		Device:       DescribeUserAgent(userAgent),
+		CreatedAt:    now,
+		LastActiveAt: now,
+		ExpiresAt:    now.Add(uc.refreshTTL),
+		RefreshHash:  hashSecret(secret),
+	}
+	if err := uc.store.Save(ctx, session, uc.refreshTTL); err != nil {
+		return nil, "", err
+	}
+	return session, id + "." + secret, nil
+}
+
+// Refresh 校验并轮换刷新令牌；已轮换的旧令牌再次出现（宽限期外）视为泄露，整个会话作废
+func (uc *SessionUseCase) Refresh(ctx context.Context, refreshToken, ip string) (*Session, string, error) {
+	id, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || id == "" || secret == "" {
+		return nil, "", ErrSessionRevoked
+	}
+	session, err := uc.store.Get(ctx, id)
+	if err != nil {
+		return nil, "", ErrSessionRevoked
+	}
+
+	now := time.Now()
+	presented := hashSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(session.RefreshHash)) != 1 {
+		if session.PrevHash != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(session.PrevHash)) == 1 &&
+			now.Sub(session.RotatedAt) < refreshReuseGrace {
+			return nil, "", ErrRefreshTokenRotated
+		}
+		_ = uc.store.Delete(ctx, session.UserID, session.ID)
+		return nil, "", ErrSessionRevoked
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, session.UserID)
+	if err != nil || user.Status != 1 {
+		_ = uc.store.Delete(ctx, session.UserID, session.ID)
+		return nil, "", ErrSessionRevoked
+	}
+
+	newSecret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	rotated, err := uc.store.Rotate(ctx, session.ID, session.RefreshHash, hashSecret(newSecret), ip, now, uc.refreshTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	if !rotated {
+		return nil, "", ErrRefreshTokenRotated
+	}
+
+	session.IP = ip
+	session.LastActiveAt = now
+	session.ExpiresAt = now.Add(uc.refreshTTL)
+	session.Username = user.Username
+	return session, session.ID + "." + newSecret, nil
+}
+
+// Validate 校验访问令牌所属的会话及用户状态仍然有效，并记录最近活动
+func (uc *SessionUseCase) Validate(ctx context.Context, sessionID, ip string) (*Session, error) {
+	session, err := uc.store.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	user, err := uc.userRepo.GetByID(ctx, session.UserID)
+	if err != nil || user.Status != 1 {
+		_ = uc.store.Delete(ctx, session.UserID, session.ID)
+		return nil, ErrSessionRevoked
+	}
+	now := time.Now()
+	if now.Sub(session.LastActiveAt) >= sessionTouchInterval || session.IP != ip {
+		_ = uc.store.Touch(ctx, sessionID, ip, now)
+	}
+	return session, nil
+}
+
+// List 获取用户的活动会话，按最近活动时间倒序
+func (uc *SessionUseCase) List(ctx context.Context, userID uint) ([]*Session, error) {
+	return uc.store.ListByUser(ctx, userID)
+}
+
+// Revoke 注销用户的指定会话
+func (uc *SessionUseCase) Revoke(ctx context.Context, userID uint, sessionID string) error {
+	session, err := uc.store.Get(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return uc.store.Delete(ctx, userID, sessionID)
+}
+
+// RevokeAll 注销用户的全部会话，exceptID 非空时保留该会话，返回注销数量
+func (uc *SessionUseCase) RevokeAll(ctx context.Context, userID uint, exceptID string) (int, error) {
+	sessions, err := uc.store.ListByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, s := range sessions {
+		if s.ID == exceptID {
+			continue
+		}
+		if err := uc.store.Delete(ctx, userID, s.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// DescribeUserAgent 从 User-Agent 中提取浏览器和操作系统
+func DescribeUserAgent(ua string) string {
+	if ua == "" {
+		return "未知设备"
+	}
+	browser := "其他"
+	for _, b := range []struct{ key, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Chrome/", "Chrome"}, {"Firefox/", "Firefox"},
+		{"Safari/", "Safari"}, {"curl/", "curl"}, {"python-requests", "Python"}, {"Go-http-client", "Go"},
+	} {
+		if strings.Contains(ua, b.key) {
+			browser = b.name
+			break
+		}
+	}
+	platform := ""
+	for _, o := range []struct{ key, name string }{
+		{"Windows", "Windows"}, {"Android", "Android"}, {"iPhone", "iOS"}, {"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"}, {"Linux", "Linux"},
+	} {
+		if strings.Contains(ua, o.key) {
+			platform = o.name
+			break
+		}
+	}
+	if platform == "" {
+		return browser
+	}
+	return browser + " / " + platform
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
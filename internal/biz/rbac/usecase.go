@@ -22,11 +22,14 @@ package rbac
 import (
 	"context"
 	"errors"
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUseCase struct {
-	userRepo UserRepo
+	userRepo        UserRepo
+	passwordUseCase *PasswordUseCase
 }
 
 func NewUserUseCase(userRepo UserRepo) *UserUseCase {
@@ -35,14 +38,33 @@ func NewUserUseCase(userRepo UserRepo) *UserUseCase {
 	}
 }
 
+// SetPasswordUseCase 设置密码策略，设置后创建用户和修改密码都按策略校验
+func (uc *UserUseCase) SetPasswordUseCase(passwordUseCase *PasswordUseCase) {
+	uc.passwordUseCase = passwordUseCase
+}
+
 func (uc *UserUseCase) Create(ctx context.Context, user *SysUser) error {
+	if uc.passwordUseCase != nil {
+		if err := uc.passwordUseCase.Validate(user.Username, user.Password); err != nil {
+			return err
+		}
+	}
+
 	// 加密密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
-	return uc.userRepo.Create(ctx, user)
+	now := time.Now()
+	user.PasswordChangedAt = &now
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+	if uc.passwordUseCase != nil {
+		return uc.passwordUseCase.remember(ctx, user.ID, user.Password)
+	}
+	return nil
 }
 
 func (uc *UserUseCase) Update(ctx context.Context, user *SysUser) error {
@@ -92,11 +114,17 @@ func (uc *UserUseCase) UpdatePassword(ctx context.Context, userID uint, oldPassw
 	if err != nil {
 		return errors.New("用户不存在")
 	}
+	if user.Source != "" && user.Source != UserSourceLocal {
+		return errors.New("外部账号请在身份提供者中修改密码")
+	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword))
 	if err != nil {
 		return errors.New("原密码错误")
 	}
+	if uc.passwordUseCase != nil {
+		return uc.passwordUseCase.SetPassword(ctx, user, newPassword, false)
+	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -112,6 +140,16 @@ func (uc *UserUseCase) ResetPassword(ctx context.Context, userID uint, newPasswo
 	if err != nil {
 		return errors.New("用户不存在")
 	}
+	if user.Source != "" && user.Source != UserSourceLocal {
+		return errors.New("外部账号请在身份提供者中重置密码")
+	}
+	if uc.passwordUseCase != nil {
+		// 重置后按策略要求用户下次登录修改密码，并解除登录失败锁定
+		if err := uc.passwordUseCase.SetPassword(ctx, user, newPassword, uc.passwordUseCase.ForceChangeOnReset()); err != nil {
+			return err
+		}
+		return uc.passwordUseCase.Unlock(ctx, userID)
+	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -360,8 +398,11 @@ func (uc *AssetPermissionUseCase) GetDetailByID(ctx context.Context, id uint) (*
 }
 
 // UpdateAssetPermission 更新权限配置（支持修改角色、分组、主机、权限）
-func (uc *AssetPermissionUseCase) UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error {
-	return uc.assetPermissionRepo.UpdateAssetPermission(ctx, id, roleID, assetGroupID, hostIDs, permissions)
+func (uc *AssetPermissionUseCase) UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error {
+	if err := validateGrantWindow(startAt, expiresAt); err != nil {
+		return err
+	}
+	return uc.assetPermissionRepo.UpdateAssetPermission(ctx, id, roleID, assetGroupID, hostIDs, permissions, startAt, expiresAt)
 }
 
 // GetByRoleID 获取角色的所有资产权限
@@ -390,8 +431,11 @@ func (uc *AssetPermissionUseCase) GetUserAccessibleHostIDs(ctx context.Context,
 }
 
 // CreateBatchWithPermissions 批量创建资产权限（支持指定操作权限）
-func (uc *AssetPermissionUseCase) CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error {
-	return uc.assetPermissionRepo.CreateBatchWithPermissions(ctx, roleID, assetGroupID, hostIDs, permissions)
+func (uc *AssetPermissionUseCase) CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error {
+	if err := validateGrantWindow(startAt, expiresAt); err != nil {
+		return err
+	}
+	return uc.assetPermissionRepo.CreateBatchWithPermissions(ctx, roleID, assetGroupID, hostIDs, permissions, startAt, expiresAt)
 }
 
 // CheckHostOperationPermission 检查用户是否有对指定主机的特定操作权限
@@ -403,3 +447,104 @@ func (uc *AssetPermissionUseCase) CheckHostOperationPermission(ctx context.Conte
 func (uc *AssetPermissionUseCase) GetUserHostPermissions(ctx context.Context, userID, hostID uint) (uint, error) {
 	return uc.assetPermissionRepo.GetUserHostPermissions(ctx, userID, hostID)
 }
+
+// GetUserDatabasePermissions 获取用户对指定数据库的所有操作权限
+func (uc *AssetPermissionUseCase) GetUserDatabasePermissions(ctx context.Context, userID, databaseID uint) (uint, error) {
+	return uc.assetPermissionRepo.GetUserDatabasePermissions(ctx, userID, databaseID)
+}
+
+// GetUserAccessibleDatabaseIDs 获取用户有权限访问的所有数据库ID列表
+func (uc *AssetPermissionUseCase) GetUserAccessibleDatabaseIDs(ctx context.Context, userID uint) ([]uint, error) {
+	return uc.assetPermissionRepo.GetUserAccessibleDatabaseIDs(ctx, userID)
+}
+
+// CreateGrant 创建资产授权
+func (uc *AssetPermissionUseCase) CreateGrant(ctx context.Context, req *AssetGrantReq, createdBy uint) (*SysAssetGrant, error) {
+	grant := &SysAssetGrant{CreatedBy: createdBy}
+	if err := applyGrantReq(grant, req); err != nil {
+		return nil, err
+	}
+	if err := uc.assetPermissionRepo.CreateGrant(ctx, grant); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// UpdateGrant 更新资产授权
+func (uc *AssetPermissionUseCase) UpdateGrant(ctx context.Context, id uint, req *AssetGrantReq) error {
+	existing, err := uc.assetPermissionRepo.GetGrantByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	grant := existing.SysAssetGrant
+	if err := applyGrantReq(&grant, req); err != nil {
+		return err
+	}
+	return uc.assetPermissionRepo.UpdateGrant(ctx, &grant)
+}
+
+// DeleteGrant 删除资产授权
+func (uc *AssetPermissionUseCase) DeleteGrant(ctx context.Context, id uint) error {
+	return uc.assetPermissionRepo.DeleteGrant(ctx, id)
+}
+
+// GetGrantByID 根据ID获取资产授权
+func (uc *AssetPermissionUseCase) GetGrantByID(ctx context.Context, id uint) (*AssetGrantInfo, error) {
+	return uc.assetPermissionRepo.GetGrantByID(ctx, id)
+}
+
+// ListGrants 分页查询资产授权
+func (uc *AssetPermissionUseCase) ListGrants(ctx context.Context, req *AssetGrantListReq) ([]*AssetGrantInfo, int64, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	return uc.assetPermissionRepo.ListGrants(ctx, req)
+}
+
+// ExplainHostPermissions 解释用户对指定主机的有效权限及来源
+func (uc *AssetPermissionUseCase) ExplainHostPermissions(ctx context.Context, userID, hostID uint) (*AssetPermissionExplainVO, error) {
+	return uc.assetPermissionRepo.ExplainHostPermissions(ctx, userID, hostID)
+}
+
+// SweepExpired 清理已过期的权限和授权
+func (uc *AssetPermissionUseCase) SweepExpired(ctx context.Context) (int64, error) {
+	return uc.assetPermissionRepo.SweepExpired(ctx)
+}
+
+// applyGrantReq 校验请求并写入授权模型
+func applyGrantReq(grant *SysAssetGrant, req *AssetGrantReq) error {
+	if err := validateGrantWindow(req.StartAt, req.ExpiresAt); err != nil {
+		return err
+	}
+	if req.Permissions == 0 {
+		return errors.New("请至少选择一项操作权限")
+	}
+	effect := req.Effect
+	if effect == "" {
+		effect = GrantEffectAllow
+	}
+	grant.SubjectType = req.SubjectType
+	grant.SubjectID = req.SubjectID
+	grant.AssetGroupID = req.AssetGroupID
+	grant.HostIDs = req.HostIDs
+	grant.Permissions = req.Permissions
+	grant.Effect = effect
+	grant.StartAt = req.StartAt
+	grant.ExpiresAt = req.ExpiresAt
+	grant.Reason = req.Reason
+	return nil
+}
+
+// validateGrantWindow 校验授权的生效时间窗口
+func validateGrantWindow(startAt, expiresAt *time.Time) error {
+	if startAt != nil && expiresAt != nil && !expiresAt.After(*startAt) {
+		return errors.New("过期时间必须晚于生效时间")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("过期时间必须晚于当前时间")
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/mail"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// UserImportColumns 用户导入文件的列顺序，带 * 的为必填
+var UserImportColumns = []string{"用户名*", "真实姓名", "邮箱", "手机号", "部门编码", "角色编码", "岗位编码", "初始密码"}
+
+// userImportMaxRows 单次导入的最大行数
+const userImportMaxRows = 5000
+
+// UserImportRow 导入文件中的一行用户数据
+type UserImportRow struct {
+	Row            int      `json:"row"`
+	Username       string   `json:"username"`
+	RealName       string   `json:"realName"`
+	Email          string   `json:"email"`
+	Phone          string   `json:"phone"`
+	DepartmentCode string   `json:"departmentCode"`
+	RoleCodes      []string `json:"roleCodes"`
+	PositionCodes  []string `json:"positionCodes"`
+	Password       string   `json:"-"`
+}
+
+// UserImportOptions 导入选项
+type UserImportOptions struct {
+	// DryRun 只校验不写入
+	DryRun bool
+	// GeneratePassword 未填写初始密码时自动生成，生成的密码首次登录必须修改
+	GeneratePassword bool
+}
+
+// UserImportError 某一行的校验或创建错误
+type UserImportError struct {
+	Row      int    `json:"row"`
+	Username string `json:"username"`
+	Message  string `json:"message"`
+}
+
+// UserImportPassword 自动生成的初始密码，只在导入结果中返回一次
+type UserImportPassword struct {
+	Row      int    `json:"row"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// UserImportResult 导入结果
+type UserImportResult struct {
+	DryRun       bool                 `json:"dryRun"`
+	Total        int                  `json:"total"`
+	SuccessCount int                  `json:"successCount"`
+	FailedCount  int                  `json:"failedCount"`
+	Errors       []UserImportError    `json:"errors"`
+	Passwords    []UserImportPassword `json:"passwords,omitempty"`
+}
+
+// UserImportUseCase 批量导入导出用户
+type UserImportUseCase struct {
+	userRepo     UserRepo
+	roleRepo     RoleRepo
+	deptRepo     DepartmentRepo
+	positionRepo PositionRepo
+	userUseCase  *UserUseCase
+}
+
+func NewUserImportUseCase(userRepo UserRepo, roleRepo RoleRepo, deptRepo DepartmentRepo, positionRepo PositionRepo, userUseCase *UserUseCase) *UserImportUseCase {
+	return &UserImportUseCase{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		deptRepo:     deptRepo,
+		positionRepo: positionRepo,
+		userUseCase:  userUseCase,
+	}
+}
+
+// userImportPlan 校验通过的一行及其解析出的关联ID
+type userImportPlan struct {
+	row          *UserImportRow
+	departmentID uint
+	roleIDs      []uint
+	positionIDs  []uint
+	generated    bool
+}
+
+// Import 校验并导入用户，每行独立处理，失败的行不影响其他行
+func (uc *UserImportUseCase) Import(ctx context.Context, rows []*UserImportRow, opts UserImportOptions) (*UserImportResult, error) {
+	if len(rows) > userImportMaxRows {
+		return nil, fmt.Errorf("单次最多导入%d个用户", userImportMaxRows)
+	}
+
+	depts, err := uc.deptRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deptIDs := make(map[string]uint, len(depts))
+	for _, dept := range depts {
+		deptIDs[dept.Code] = dept.ID
+	}
+	roles, err := uc.roleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make(map[string]uint, len(roles))
+	for _, role := range roles {
+		roleIDs[role.Code] = role.ID
+	}
+	positions, err := uc.positionRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positionIDs := make(map[string]uint, len(positions))
+	for _, position := range positions {
+		positionIDs[position.PostCode] = position.ID
+	}
+
+	result := &UserImportResult{DryRun: opts.DryRun, Total: len(rows), Errors: []UserImportError{}}
+	fail := func(row *UserImportRow, msg string) {
+		result.FailedCount++
+		result.Errors = append(result.Errors, UserImportError{Row: row.Row, Username: row.Username, Message: msg})
+	}
+
+	seen := make(map[string]int, len(rows))
+	plans := make([]*userImportPlan, 0, len(rows))
+	for _, row := range rows {
+		plan, err := uc.validateRow(ctx, row, opts, deptIDs, roleIDs, positionIDs)
+		if err == nil {
+			if first, ok := seen[strings.ToLower(row.Username)]; ok {
+				err = fmt.Errorf("用户名与第%d行重复", first)
+			}
+		}
+		if err != nil {
+			fail(row, err.Error())
+			continue
+		}
+		seen[strings.ToLower(row.Username)] = row.Row
+		plans = append(plans, plan)
+	}
+
+	if opts.DryRun {
+		result.SuccessCount = len(plans)
+		return result, nil
+	}
+
+	forceChange := uc.userUseCase.passwordUseCase != nil && uc.userUseCase.passwordUseCase.ForceChangeOnReset()
+	for _, plan := range plans {
+		row := plan.row
+		user := &SysUser{
+			Username:     row.Username,
+			Password:     row.Password,
+			RealName:     row.RealName,
+			Email:        row.Email,
+			Phone:        row.Phone,
+			Status:       1,
+			DepartmentID: plan.departmentID,
+			Source:       UserSourceLocal,
+			// 自动生成的密码必须在首次登录时修改，管理员填写的密码按策略处理
+			MustChangePassword: plan.generated || forceChange,
+		}
+		if err := uc.userUseCase.Create(ctx, user); err != nil {
+			fail(row, "创建失败: "+err.Error())
+			continue
+		}
+		if len(plan.roleIDs) > 0 {
+			if err := uc.userRepo.AssignRoles(ctx, user.ID, plan.roleIDs); err != nil {
+				fail(row, "用户已创建，分配角色失败: "+err.Error())
+				continue
+			}
+		}
+		if len(plan.positionIDs) > 0 {
+			if err := uc.userRepo.AssignPositions(ctx, user.ID, plan.positionIDs); err != nil {
+				fail(row, "用户已创建，分配岗位失败: "+err.Error())
+				continue
+			}
+		}
+		result.SuccessCount++
+		if plan.generated {
+			result.Passwords = append(result.Passwords, UserImportPassword{Row: row.Row, Username: row.Username, Password: row.Password})
+		}
+	}
+	return result, nil
+}
+
+// Export 导出用户及其部门、角色和岗位，受数据权限限制
+func (uc *UserImportUseCase) Export(ctx context.Context, keyword string, departmentID uint) ([]*SysUser, error) {
+	return uc.userRepo.ListAll(ctx, keyword, departmentID)
+}
+
+// validateRow 校验一行数据并解析部门、角色和岗位编码
+func (uc *UserImportUseCase) validateRow(ctx context.Context, row *UserImportRow, opts UserImportOptions, deptIDs, roleIDs, positionIDs map[string]uint) (*userImportPlan, error) {
+	if row.Username == "" {
+		return nil, errors.New("用户名不能为空")
+	}
+	if len([]rune(row.Username)) > 50 {
+		return nil, errors.New("用户名不能超过50个字符")
+	}
+	if len([]rune(row.RealName)) > 50 {
+		return nil, errors.New("真实姓名不能超过50个字符")
+	}
+	if row.Email != "" {
+		if _, err := mail.ParseAddress(row.Email); err != nil {
+			return nil, fmt.Errorf("邮箱格式不正确: %s", row.Email)
+		}
+	}
+	if len(row.Phone) > 20 {
+		return nil, errors.New("手机号不能超过20个字符")
+	}
+	if _, err := uc.userRepo.GetByUsername(ctx, row.Username); err == nil {
+		return nil, fmt.Errorf("用户名 %s 已存在", row.Username)
+	}
+
+	plan := &userImportPlan{row: row}
+	if row.DepartmentCode != "" {
+		id, ok := deptIDs[row.DepartmentCode]
+		if !ok {
+			return nil, fmt.Errorf("部门编码 %s 不存在", row.DepartmentCode)
+		}
+		plan.departmentID = id
+	}
+	for _, code := range row.RoleCodes {
+		id, ok := roleIDs[code]
+		if !ok {
+			return nil, fmt.Errorf("角色编码 %s 不存在", code)
+		}
+		plan.roleIDs = append(plan.roleIDs, id)
+	}
+	for _, code := range row.PositionCodes {
+		id, ok := positionIDs[code]
+		if !ok {
+			return nil, fmt.Errorf("岗位编码 %s 不存在", code)
+		}
+		plan.positionIDs = append(plan.positionIDs, id)
+	}
+
+	if row.Password == "" {
+		if !opts.GeneratePassword {
+			return nil, errors.New("初始密码不能为空，或选择自动生成密码")
+		}
+		password, err := uc.generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		row.Password = password
+		plan.generated = true
+	}
+	if uc.userUseCase.passwordUseCase != nil {
+		if err := uc.userUseCase.passwordUseCase.Validate(row.Username, row.Password); err != nil {
+			return nil, err
+		}
+	} else if len(row.Password) < 6 {
+		return nil, errors.New("密码长度不能少于6位")
+	}
+	return plan, nil
+}
+
+// 生成初始密码使用的字符集，去掉了容易混淆的字符
+const (
+	passwordUpper  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordLower  = "abcdefghijkmnpqrstuvwxyz"
+	passwordDigit  = "23456789"
+	passwordSymbol = "!@#$%^&*-_=+"
+)
+
+// generatePassword 生成满足密码策略的随机初始密码，四类字符各至少一个
+func (uc *UserImportUseCase) generatePassword() (string, error) {
+	length := 14
+	if uc.userUseCase.passwordUseCase != nil {
+		if minLength := uc.userUseCase.passwordUseCase.Policy().MinLength; minLength > length {
+			length = minLength
+		}
+	}
+
+	sets := []string{passwordUpper, passwordLower, passwordDigit, passwordSymbol}
+	all := strings.Join(sets, "")
+	chars := make([]byte, 0, length)
+	for _, set := range sets {
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		chars = append(chars, c)
+	}
+	for len(chars) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		chars = append(chars, c)
+	}
+	// 打乱顺序，避免固定位置的字符类型
+	for i := len(chars) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		chars[i], chars[j.Int64()] = chars[j.Int64()], chars[i]
+	}
+	return string(chars), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
+
+// ParseUserImportFile 按扩展名解析 .xlsx 或 .csv 导入文件，第一行为标题行
+func ParseUserImportFile(filename string, data []byte) ([]*UserImportRow, error) {
+	var records [][]string
+	// lines 记录每条数据在文件中的行号，CSV 会跳过空行，不能直接用下标
+	var lines []int
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("读取Excel文件失败: %w", err)
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("Excel文件中没有工作表")
+		}
+		records, err = f.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("读取Excel数据失败: %w", err)
+		}
+		for i := range records {
+			lines = append(lines, i+1)
+		}
+	case ".csv":
+		// 兼容 Excel 另存为 CSV 时带的 UTF-8 BOM
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("读取CSV文件失败: %w", err)
+			}
+			line, _ := reader.FieldPos(0)
+			records = append(records, record)
+			lines = append(lines, line)
+		}
+	default:
+		return nil, errors.New("仅支持 .xlsx 和 .csv 文件")
+	}
+
+	rows := make([]*UserImportRow, 0, len(records))
+	for i, record := range records {
+		if i == 0 {
+			// 跳过标题行
+			continue
+		}
+		cell := func(idx int) string {
+			if idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		// 跳过空行
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, &UserImportRow{
+			Row:            lines[i],
+			Username:       cell(0),
+			RealName:       cell(1),
+			Email:          cell(2),
+			Phone:          cell(3),
+			DepartmentCode: cell(4),
+			RoleCodes:      splitImportCodes(cell(5)),
+			PositionCodes:  splitImportCodes(cell(6)),
+			Password:       cell(7),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("导入文件中没有数据")
+	}
+	return rows, nil
+}
+
+// splitImportCodes 拆分逗号、中文逗号或分号分隔的编码列表
+func splitImportCodes(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '，' || r == ';' || r == '；'
+	})
+	codes := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" && !seen[f] {
+			seen[f] = true
+			codes = append(codes, f)
+		}
+	}
+	return codes
+}
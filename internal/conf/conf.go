@@ -32,6 +32,107 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	Log      LogConfig      `mapstructure:"log"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+	Audit    AuditConfig    `mapstructure:"audit"`
+}
+
+// AuditConfig 审计配置
+type AuditConfig struct {
+	SigningKey         string               `mapstructure:"signing_key"`         // 审计链检查点签名密钥，留空时使用 server.jwt_secret
+	CheckpointInterval int                  `mapstructure:"checkpoint_interval"` // 生成签名检查点的间隔（分钟），默认 60
+	RetentionInterval  int                  `mapstructure:"retention_interval"`  // 执行保留策略的间隔（分钟），默认 1440
+	Archive            AuditArchiveConfig   `mapstructure:"archive"`
+	QueueDir           string               `mapstructure:"queue_dir"` // 审计事件转发失败时的本地重试队列目录，默认 ./data/audit-queue
+	Sinks              []AuditSinkConfig    `mapstructure:"sinks"`     // 审计事件转发目标
+	Redaction          AuditRedactionConfig `mapstructure:"redaction"`
+	Security           AuditSecurityConfig  `mapstructure:"security"`
+}
+
+// AuditSecurityConfig 登录异常检测配置
+type AuditSecurityConfig struct {
+	Enabled                 bool                 `mapstructure:"enabled"`
+	GeoIPDB                 string               `mapstructure:"geoip_db"`                   // 离线 IP 库路径，MaxMind DB 格式（如 GeoLite2-City.mmdb），为空时不解析地理位置
+	GeoIPLanguage           string               `mapstructure:"geoip_language"`             // 地名语言，默认 zh-CN，缺失时使用英文
+	BruteForceWindow        int                  `mapstructure:"brute_force_window"`         // 暴力破解统计窗口（分钟），默认 10
+	BruteForceUserThreshold int                  `mapstructure:"brute_force_user_threshold"` // 窗口内单个用户失败次数阈值，默认 5，-1 关闭
+	BruteForceIPThreshold   int                  `mapstructure:"brute_force_ip_threshold"`   // 窗口内单个 IP 失败次数阈值，默认 20，-1 关闭
+	NewIP                   bool                 `mapstructure:"new_ip"`                     // 检测用户从未使用过的 IP 登录
+	NewCountry              bool                 `mapstructure:"new_country"`                // 检测用户从未出现过的国家/地区登录，需要 IP 库
+	HistoryDays             int                  `mapstructure:"history_days"`               // 判断新 IP、新国家时回看的天数，默认 90
+	TravelSpeed             int                  `mapstructure:"travel_speed"`               // 不可能旅行的速度阈值（公里/小时），0 关闭，需要带经纬度的 IP 库
+	WorkHours               AuditWorkHoursConfig `mapstructure:"work_hours"`
+	NotifyLevel             string               `mapstructure:"notify_level"`    // 发送告警的最低等级 info/warning/critical，默认 warning
+	NotifyUserIDs           []uint               `mapstructure:"notify_user_ids"` // 额外接收告警的系统用户ID，需在监控中心告警接收人中关联
+}
+
+// AuditWorkHoursConfig 工作时间，用于检测非工作时间登录
+type AuditWorkHoursConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Start    string `mapstructure:"start"`    // HH:MM
+	End      string `mapstructure:"end"`      // HH:MM，早于 start 时表示跨零点
+	Weekdays []int  `mapstructure:"weekdays"` // 1-7 表示周一到周日，默认 1-5
+	Timezone string `mapstructure:"timezone"` // 如 Asia/Shanghai，默认服务器时区
+}
+
+// AuditRedactionConfig 操作日志脱敏配置，在内置规则之外追加
+type AuditRedactionConfig struct {
+	Fields     []string                `mapstructure:"fields"`      // 额外的敏感字段名，忽略大小写、下划线和连字符
+	Patterns   []AuditRedactionPattern `mapstructure:"patterns"`    // 额外的正则规则
+	SkipRoutes []string                `mapstructure:"skip_routes"` // 不脱敏的路由，如 "POST /api/v1/hosts/:id"
+}
+
+// AuditRedactionPattern 正则脱敏规则
+type AuditRedactionPattern struct {
+	Pattern     string `mapstructure:"pattern"`
+	Replacement string `mapstructure:"replacement"` // 为空时替换为 ******
+}
+
+// AuditSinkConfig 审计事件转发目标配置
+type AuditSinkConfig struct {
+	Name          string            `mapstructure:"name"` // 名称，同时作为重试队列子目录
+	Type          string            `mapstructure:"type"` // syslog, webhook, file
+	Enabled       bool              `mapstructure:"enabled"`
+	Events        []string          `mapstructure:"events"`         // 转发的事件类型，为空时转发全部
+	BatchSize     int               `mapstructure:"batch_size"`     // 每批事件数，默认 100
+	FlushInterval int               `mapstructure:"flush_interval"` // 批次最长等待时间（秒），默认 5
+	MaxQueueSize  int               `mapstructure:"max_queue_size"` // 重试队列最大容量（MB），默认 512，超出后丢弃最早的事件
+	Network       string            `mapstructure:"network"`        // syslog: udp, tcp, tls
+	Address       string            `mapstructure:"address"`        // syslog: host:port
+	Facility      int               `mapstructure:"facility"`       // syslog: 设施代码，默认 13 (log audit)
+	AppName       string            `mapstructure:"app_name"`       // syslog: APP-NAME，默认 opshub
+	CAFile        string            `mapstructure:"ca_file"`        // syslog tls: 服务端 CA 证书
+	Insecure      bool              `mapstructure:"insecure"`       // syslog tls: 跳过证书校验
+	URL           string            `mapstructure:"url"`            // webhook: 接收地址
+	Headers       map[string]string `mapstructure:"headers"`        // webhook: 附加请求头，如认证令牌
+	Secret        string            `mapstructure:"secret"`         // webhook: 请求体 HMAC-SHA256 签名密钥
+	Timeout       int               `mapstructure:"timeout"`        // webhook/syslog: 超时（秒），默认 10
+	Path          string            `mapstructure:"path"`           // file: JSONL 文件路径
+}
+
+// AuditArchiveConfig 审计日志归档存储配置
+type AuditArchiveConfig struct {
+	Storage  string        `mapstructure:"storage"`   // local, s3
+	LocalDir string        `mapstructure:"local_dir"` // 本地存储目录，默认 ./data/audit-archives
+	S3       AuditS3Config `mapstructure:"s3"`
+}
+
+// AuditS3Config S3 兼容对象存储配置
+type AuditS3Config struct {
+	Endpoint  string `mapstructure:"endpoint"` // 如 https://s3.amazonaws.com、https://minio.example.com:9000
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	Prefix    string `mapstructure:"prefix"` // 对象键前缀
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"` // 使用路径风格访问，MinIO 等需要开启
+}
+
+// AuditSigningKey 返回审计链检查点签名密钥
+func (c *Config) AuditSigningKey() string {
+	if c.Audit.SigningKey != "" {
+		return c.Audit.SigningKey
+	}
+	return c.Server.JWTSecret
 }
 
 // ServerConfig 服务器配置
@@ -42,6 +143,10 @@ type ServerConfig struct {
 	ReadTimeout  int  `mapstructure:"read_timeout"`  // 毫秒
 	WriteTimeout int  `mapstructure:"write_timeout"` // 毫秒
 	JWTSecret  string `mapstructure:"jwt_secret"`    // JWT密钥
+	// PortForwardBind 端口转发临时监听端口绑定的地址，默认 127.0.0.1；
+	// 回环地址下监听模式只能在平台服务器本机使用，远程客户端直接连接时必须配置为对外网卡的地址，
+	// 经反向代理访问时代理需透传 X-Forwarded-For，平台据此只放行发起方IP
+	PortForwardBind string `mapstructure:"port_forward_bind"`
 }
 
 // DatabaseConfig 数据库配置
@@ -78,6 +183,105 @@ type LogConfig struct {
 	Console    bool   `mapstructure:"console"`
 }
 
+// AuthConfig 认证配置
+type AuthConfig struct {
+	LDAP     LDAPConfig           `mapstructure:"ldap"`
+	OIDC     []OIDCProviderConfig `mapstructure:"oidc"`
+	MFA      MFAConfig            `mapstructure:"mfa"`
+	Session  SessionConfig        `mapstructure:"session"`
+	Password PasswordConfig       `mapstructure:"password"`
+	SCIM     SCIMConfig           `mapstructure:"scim"`
+}
+
+// SCIMConfig SCIM 2.0 用户供给配置
+type SCIMConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Token        string   `mapstructure:"token"`         // HR/IdP 调用时使用的 Bearer 令牌
+	DefaultRoles []string `mapstructure:"default_roles"` // 新建用户默认授予的角色编码
+}
+
+// PasswordConfig 本地账号密码策略，数值为 0 表示不限制
+type PasswordConfig struct {
+	MinLength      int      `mapstructure:"min_length"`
+	RequireUpper   bool     `mapstructure:"require_upper"`
+	RequireLower   bool     `mapstructure:"require_lower"`
+	RequireDigit   bool     `mapstructure:"require_digit"`
+	RequireSymbol  bool     `mapstructure:"require_symbol"`
+	Blocklist      []string `mapstructure:"blocklist"`       // 内置常见弱密码之外的黑名单
+	HistoryCount   int      `mapstructure:"history_count"`   // 不能与最近 N 个密码相同
+	MaxAgeDays     int      `mapstructure:"max_age_days"`    // 密码有效期（天），过期后登录时必须修改
+	WarnDays       int      `mapstructure:"warn_days"`       // 过期前多少天开始提醒
+	ForceChange    bool     `mapstructure:"force_change"`    // 管理员创建或重置密码后首次登录必须修改
+	MaxFailures    int      `mapstructure:"max_failures"`    // 窗口期内登录失败多少次后锁定
+	FailureWindow  int      `mapstructure:"failure_window"`  // 失败计数窗口（分钟），默认 15
+	LockoutMinutes int      `mapstructure:"lockout_minutes"` // 锁定时长（分钟），默认 30
+}
+
+// SessionConfig 登录会话配置
+type SessionConfig struct {
+	AccessTokenTTL  int `mapstructure:"access_token_ttl"`  // 访问令牌有效期（分钟），默认 15
+	RefreshTokenTTL int `mapstructure:"refresh_token_ttl"` // 刷新令牌有效期（小时），默认 168
+}
+
+// MFAConfig 双因素认证配置
+type MFAConfig struct {
+	Issuer             string `mapstructure:"issuer"`               // 认证器 App 中显示的发行方
+	RequireForTerminal bool   `mapstructure:"require_for_terminal"` // 拥有主机终端权限的用户必须启用
+}
+
+// LDAPConfig LDAP/AD 认证配置
+type LDAPConfig struct {
+	Enabled            bool           `mapstructure:"enabled"`
+	URL                string         `mapstructure:"url"` // ldap://host:389 或 ldaps://host:636
+	StartTLS           bool           `mapstructure:"start_tls"`
+	InsecureSkipVerify bool           `mapstructure:"insecure_skip_verify"`
+	BindDN             string         `mapstructure:"bind_dn"` // 用于搜索用户的服务账号
+	BindPassword       string         `mapstructure:"bind_password"`
+	BaseDN             string         `mapstructure:"base_dn"`          // 用户搜索根
+	UserFilter         string         `mapstructure:"user_filter"`      // 如 (uid=%s)，AD 使用 (sAMAccountName=%s)
+	UserListFilter     string         `mapstructure:"user_list_filter"` // 同步时列出全部用户，如 (objectClass=inetOrgPerson)
+	UsernameAttr       string         `mapstructure:"username_attr"`
+	RealNameAttr       string         `mapstructure:"real_name_attr"`
+	EmailAttr          string         `mapstructure:"email_attr"`
+	PhoneAttr          string         `mapstructure:"phone_attr"`
+	GroupBaseDN        string         `mapstructure:"group_base_dn"`
+	GroupFilter        string         `mapstructure:"group_filter"` // %s 替换为用户DN，如 (member=%s)
+	GroupNameAttr      string         `mapstructure:"group_name_attr"`
+	DefaultRoles       []string       `mapstructure:"default_roles"` // 未匹配任何分组时授予的角色编码
+	GroupMappings      []GroupMapping `mapstructure:"group_mappings"`
+	SyncInterval       int            `mapstructure:"sync_interval"`   // 分钟，0 表示不定期同步
+	DisableMissing     bool           `mapstructure:"disable_missing"` // 同步时禁用目录中已不存在的用户
+	Timeout            int            `mapstructure:"timeout"`         // 秒
+}
+
+// GroupMapping 外部分组到角色/部门的映射
+type GroupMapping struct {
+	Group      string   `mapstructure:"group"`      // 分组名称或完整DN
+	Roles      []string `mapstructure:"roles"`      // 角色编码
+	Department string   `mapstructure:"department"` // 部门编码
+}
+
+// OIDCProviderConfig OIDC 单点登录提供者配置
+type OIDCProviderConfig struct {
+	Name              string         `mapstructure:"name"`         // 提供者标识，用于回调地址
+	DisplayName       string         `mapstructure:"display_name"` // 登录页按钮名称
+	Issuer            string         `mapstructure:"issuer"`       // 通过 {issuer}/.well-known/openid-configuration 发现端点
+	ClientID          string         `mapstructure:"client_id"`
+	ClientSecret      string         `mapstructure:"client_secret"`
+	RedirectURL       string         `mapstructure:"redirect_url"` // 如 https://opshub.example.com/api/v1/public/oidc/{name}/callback
+	Scopes            []string       `mapstructure:"scopes"`
+	UsernameClaim     string         `mapstructure:"username_claim"`
+	EmailClaim        string         `mapstructure:"email_claim"`
+	RealNameClaim     string         `mapstructure:"real_name_claim"`
+	PhoneClaim        string         `mapstructure:"phone_claim"`
+	GroupsClaim       string         `mapstructure:"groups_claim"`        // 用于角色映射的声明，值为字符串或字符串数组
+	DefaultRoles      []string       `mapstructure:"default_roles"`       // 未匹配任何映射时授予的角色编码
+	RoleMappings      []GroupMapping `mapstructure:"role_mappings"`       // 声明值 -> 角色编码/部门编码
+	AutoCreate        bool           `mapstructure:"auto_create"`         // 首次登录自动创建用户
+	LinkBy            string         `mapstructure:"link_by"`             // 关联已有本地账号的方式 username/email，留空不关联
+	PostLoginRedirect string         `mapstructure:"post_login_redirect"` // 登录完成后跳转的前端地址，默认 /login
+}
+
 var globalConfig *Config
 
 // Load 加载配置
@@ -0,0 +1,143 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/asset"
+	"gorm.io/gorm"
+)
+
+type databaseRepo struct {
+	db *gorm.DB
+}
+
+// NewDatabaseRepo 创建数据库资产仓库
+func NewDatabaseRepo(db *gorm.DB) asset.DatabaseRepo {
+	return &databaseRepo{db: db}
+}
+
+// Create 创建数据库资产
+func (r *databaseRepo) Create(ctx context.Context, instance *asset.DatabaseInstance) error {
+	return r.db.WithContext(ctx).Create(instance).Error
+}
+
+// Update 更新数据库资产
+func (r *databaseRepo) Update(ctx context.Context, instance *asset.DatabaseInstance) error {
+	return r.db.WithContext(ctx).Save(instance).Error
+}
+
+// Delete 删除数据库资产
+func (r *databaseRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&asset.DatabaseInstance{}, id).Error
+}
+
+// GetByID 根据ID获取数据库资产
+func (r *databaseRepo) GetByID(ctx context.Context, id uint) (*asset.DatabaseInstance, error) {
+	var instance asset.DatabaseInstance
+	if err := r.db.WithContext(ctx).First(&instance, id).Error; err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// List 列表查询
+// accessibleIDs 为nil表示不进行权限筛选，为空切片表示没有任何权限
+func (r *databaseRepo) List(ctx context.Context, page, pageSize int, keyword, dbType string, groupIDs []uint, accessibleIDs []uint) ([]*asset.DatabaseInstance, int64, error) {
+	var instances []*asset.DatabaseInstance
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&asset.DatabaseInstance{})
+
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR host LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if dbType != "" {
+		query = query.Where("type = ?", dbType)
+	}
+	if len(groupIDs) > 0 {
+		query = query.Where("group_id IN ?", groupIDs)
+	}
+	if accessibleIDs != nil {
+		if len(accessibleIDs) == 0 {
+			return []*asset.DatabaseInstance{}, 0, nil
+		}
+		query = query.Where("id IN ?", accessibleIDs)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&instances).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return instances, total, nil
+}
+
+// UpdateStatus 更新连接状态
+func (r *databaseRepo) UpdateStatus(ctx context.Context, id uint, status int) error {
+	updates := map[string]interface{}{"status": status}
+	if status == 1 {
+		updates["last_seen"] = time.Now()
+	}
+	return r.db.WithContext(ctx).Model(&asset.DatabaseInstance{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// CreateQueryLog 保存语句审计记录
+func (r *databaseRepo) CreateQueryLog(ctx context.Context, log *asset.DatabaseQueryLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// ListQueryLogs 分页查询语句审计记录
+func (r *databaseRepo) ListQueryLogs(ctx context.Context, req *asset.DatabaseQueryLogListRequest) ([]*asset.DatabaseQueryLog, int64, error) {
+	var logs []*asset.DatabaseQueryLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&asset.DatabaseQueryLog{})
+
+	if req.DatabaseID > 0 {
+		query = query.Where("database_id = ?", req.DatabaseID)
+	}
+	if req.Username != "" {
+		query = query.Where("username LIKE ?", "%"+req.Username+"%")
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Keyword != "" {
+		query = query.Where("statement LIKE ? OR instance_name LIKE ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/ydcloud-dy/opshub/internal/biz/asset"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"gorm.io/gorm"
 )
 
@@ -44,6 +45,7 @@ func NewHostRepo(db *gorm.DB) asset.HostRepo {
 
 // Create 创建主机
 func (r *hostRepo) Create(ctx context.Context, host *asset.Host) error {
+	setHostOwner(ctx, host)
 	return r.db.WithContext(ctx).Create(host).Error
 }
 
@@ -66,6 +68,7 @@ func (r *hostRepo) CreateOrUpdate(ctx context.Context, host *asset.Host) error {
 			existing.Tags = host.Tags
 			existing.Description = host.Description
 			existing.Status = host.Status
+			existing.CreatedBy = rbac.DataOwnerFromContext(ctx)
 			existing.DeletedAt.Time = *new(time.Time) // 清除删除时间
 			existing.DeletedAt.Valid = false
 			return r.db.WithContext(ctx).Unscoped().Save(&existing).Error
@@ -75,9 +78,17 @@ func (r *hostRepo) CreateOrUpdate(ctx context.Context, host *asset.Host) error {
 	}
 
 	// 没找到记录，创建新的
+	setHostOwner(ctx, host)
 	return r.db.WithContext(ctx).Create(host).Error
 }
 
+// setHostOwner 记录主机创建人，用于按部门划分数据权限
+func setHostOwner(ctx context.Context, host *asset.Host) {
+	if host.CreatedBy == 0 {
+		host.CreatedBy = rbac.DataOwnerFromContext(ctx)
+	}
+}
+
 // Update 更新主机
 func (r *hostRepo) Update(ctx context.Context, host *asset.Host) error {
 	return r.db.WithContext(ctx).Save(host).Error
@@ -125,6 +136,9 @@ func (r *hostRepo) List(ctx context.Context, page, pageSize int, keyword string,
 		query = query.Where("id IN ?", accessibleHostIDs)
 	}
 
+	// 数据权限：只返回可见部门内用户创建的主机
+	query = query.Scopes(rbac.ScopeByDataPermission(ctx, "created_by"))
+
 	err := query.Order("id DESC").Count(&total).Error
 	if err != nil {
 		return nil, 0, err
@@ -0,0 +1,224 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+)
+
+// 归档存储类型
+const (
+	ArchiveStorageLocal = "local"
+	ArchiveStorageS3    = "s3"
+)
+
+const (
+	defaultArchiveDir = "./data/audit-archives"
+	// emptyPayloadHash 空请求体的 SHA256
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// NewArchiveStorage 根据配置创建归档存储
+func NewArchiveStorage(cfg conf.AuditArchiveConfig) (audit.ArchiveStorage, error) {
+	switch cfg.Storage {
+	case "", ArchiveStorageLocal:
+		dir := cfg.LocalDir
+		if dir == "" {
+			dir = defaultArchiveDir
+		}
+		return &localArchiveStorage{dir: dir}, nil
+	case ArchiveStorageS3:
+		if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+			return nil, fmt.Errorf("S3 归档存储需要配置 endpoint 和 bucket")
+		}
+		region := cfg.S3.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		return &s3ArchiveStorage{
+			cfg:    cfg.S3,
+			region: region,
+			signer: v4.NewSigner(),
+			client: &http.Client{Timeout: 10 * time.Minute},
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的归档存储类型: %s", cfg.Storage)
+	}
+}
+
+// localArchiveStorage 本地磁盘存储
+type localArchiveStorage struct {
+	dir string
+}
+
+func (s *localArchiveStorage) Name() string {
+	return ArchiveStorageLocal
+}
+
+func (s *localArchiveStorage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免留下不完整的归档
+	tmp := p + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (s *localArchiveStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func (s *localArchiveStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("无效的归档路径: %s", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+// s3ArchiveStorage S3 兼容对象存储，只用到 PutObject 和 GetObject，直接以 SigV4 签名请求
+type s3ArchiveStorage struct {
+	cfg    conf.AuditS3Config
+	region string
+	signer *v4.Signer
+	client *http.Client
+}
+
+func (s *s3ArchiveStorage) Name() string {
+	return ArchiveStorageS3
+}
+
+func (s *s3ArchiveStorage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	req, err := s.request(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+	resp, err := s.do(req, "UNSIGNED-PAYLOAD")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3ArchiveStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *s3ArchiveStorage) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 S3 地址: %w", err)
+	}
+	objectKey := strings.Trim(path.Join(s.cfg.Prefix, key), "/")
+	if s.cfg.PathStyle {
+		endpoint.Path = "/" + s.cfg.Bucket + "/" + objectKey
+	} else {
+		endpoint.Host = s.cfg.Bucket + "." + endpoint.Host
+		endpoint.Path = "/" + objectKey
+	}
+	return http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+}
+
+func (s *s3ArchiveStorage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := aws.Credentials{AccessKeyID: s.cfg.AccessKey, SecretAccessKey: s.cfg.SecretKey}
+	if err := s.signer.SignHTTP(req.Context(), creds, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("S3 请求失败: %s %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// unavailableArchiveStorage 存储配置有误时使用，读写都返回配置错误，执行结果中可以看到原因
+type unavailableArchiveStorage struct {
+	name string
+	err  error
+}
+
+// NewUnavailableArchiveStorage 创建始终返回 err 的归档存储
+func NewUnavailableArchiveStorage(name string, err error) audit.ArchiveStorage {
+	return &unavailableArchiveStorage{name: name, err: err}
+}
+
+func (s *unavailableArchiveStorage) Name() string {
+	return s.name
+}
+
+func (s *unavailableArchiveStorage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	return s.err
+}
+
+func (s *unavailableArchiveStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, s.err
+}
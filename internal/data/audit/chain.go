@@ -0,0 +1,209 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type auditChainRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditChainRepo(db *gorm.DB) audit.AuditChainRepo {
+	return &auditChainRepo{db: db}
+}
+
+func (r *auditChainRepo) GetHead(ctx context.Context, chain string) (*audit.SysAuditChain, error) {
+	var head audit.SysAuditChain
+	err := r.db.WithContext(ctx).Where("name = ?", chain).First(&head).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &head, err
+}
+
+func (r *auditChainRepo) ListEntries(ctx context.Context, chain string, afterSeq uint64, limit int) ([]*audit.SysAuditChainEntry, error) {
+	var entries []*audit.SysAuditChainEntry
+	err := r.db.WithContext(ctx).
+		Where("chain = ? AND seq > ?", chain, afterSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditChainRepo) LoadRecords(ctx context.Context, table string, ids []uint) (map[uint]audit.ChainRecord, error) {
+	return loadChainRecords(r.db.WithContext(ctx), table, ids)
+}
+
+func (r *auditChainRepo) ListUnchained(ctx context.Context, chain audit.AuditChain, since time.Time, limit int) ([]uint, int64, error) {
+	// 插件未启用时表可能不存在
+	if !r.db.Migrator().HasTable(chain.Table) {
+		return nil, 0, nil
+	}
+	query := r.db.WithContext(ctx).Table(chain.Table+" AS t").
+		Where("t.created_at >= ?", since).
+		Where("NOT EXISTS (SELECT 1 FROM sys_audit_chain_entry e WHERE e.chain = ? AND e.record_id = t.id)", chain.Name)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var ids []uint
+	if total > 0 {
+		if err := query.Order("t.id ASC").Limit(limit).Pluck("t.id", &ids).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	return ids, total, nil
+}
+
+func (r *auditChainRepo) ListCheckpoints(ctx context.Context, chain string) ([]*audit.SysAuditCheckpoint, error) {
+	var checkpoints []*audit.SysAuditCheckpoint
+	err := r.db.WithContext(ctx).Where("chain = ?", chain).Order("seq ASC, id ASC").Find(&checkpoints).Error
+	return checkpoints, err
+}
+
+func (r *auditChainRepo) CreateCheckpoint(ctx context.Context, checkpoint *audit.SysAuditCheckpoint) error {
+	return r.db.WithContext(ctx).Create(checkpoint).Error
+}
+
+func (r *auditChainRepo) GetPruneArchive(ctx context.Context, chain string) (*audit.SysAuditArchive, error) {
+	var archive audit.SysAuditArchive
+	err := r.db.WithContext(ctx).Where("chain = ? AND to_seq > 0", chain).Order("to_seq DESC").First(&archive).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &archive, err
+}
+
+// loadChainRecords 按表名查询审计记录，不经过模型和软删除条件，写入和校验使用同一方式读取
+func loadChainRecords(db *gorm.DB, table string, ids []uint) (map[uint]audit.ChainRecord, error) {
+	records := make(map[uint]audit.ChainRecord, len(ids))
+	if len(ids) == 0 || !db.Migrator().HasTable(table) {
+		return records, nil
+	}
+	var rows []map[string]interface{}
+	if err := db.Table(table).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		deleted := false
+		if v, ok := row["deleted_at"]; ok && v != nil {
+			deleted = true
+		}
+		records[toUint(row["id"])] = audit.ChainRecord{Row: row, Deleted: deleted}
+	}
+	return records, nil
+}
+
+type auditChainRecorder struct {
+	chains map[string]audit.AuditChain
+}
+
+// RegisterAuditChainCallbacks 注册 GORM 回调，审计表每新增一行就在审计链上追加一条记录
+// 链头加行锁串行追加，与审计记录的写入在同一事务中，追加失败时审计记录一并回滚，不会留下未入链的记录
+func RegisterAuditChainCallbacks(db *gorm.DB, chains []audit.AuditChain) error {
+	r := &auditChainRecorder{chains: make(map[string]audit.AuditChain, len(chains))}
+	for _, chain := range chains {
+		r.chains[chain.Table] = chain
+		head := &audit.SysAuditChain{Name: chain.Name, Table: chain.Table}
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(head).Error; err != nil {
+			return err
+		}
+	}
+	return db.Callback().Create().After("gorm:create").Register("audit:chain_append", r.afterCreate)
+}
+
+func (r *auditChainRecorder) afterCreate(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || db.Statement.RowsAffected == 0 || stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return
+	}
+	table := stmt.Table
+	if table == "" {
+		table = stmt.Schema.Table
+	}
+	chain, ok := r.chains[table]
+	if !ok {
+		return
+	}
+
+	var ids []uint
+	forEachModel(stmt.ReflectValue, func(v reflect.Value) {
+		if id, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, v); !zero {
+			ids = append(ids, toUint(id))
+		}
+	})
+	if len(ids) == 0 {
+		return
+	}
+	if err := appendAuditChain(db.Session(&gorm.Session{NewDB: true, SkipHooks: true}), chain, ids); err != nil {
+		appLogger.Error("追加审计链失败，审计记录写入已回滚", zap.String("chain", chain.Name), zap.Uints("recordIds", ids), zap.Error(err))
+		_ = db.AddError(fmt.Errorf("追加审计链失败: %w", err))
+	}
+}
+
+// appendAuditChain 锁定链头，按顺序为审计记录生成链记录并更新链头
+func appendAuditChain(db *gorm.DB, chain audit.AuditChain, ids []uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var head audit.SysAuditChain
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", chain.Name).First(&head).Error; err != nil {
+			return err
+		}
+		records, err := loadChainRecords(tx, chain.Table, ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			record, ok := records[id]
+			if !ok {
+				continue
+			}
+			seq := head.LastSeq + 1
+			digest := audit.RecordDigest(record.Row, chain)
+			entry := &audit.SysAuditChainEntry{
+				Chain:    chain.Name,
+				Seq:      seq,
+				RecordID: id,
+				Digest:   digest,
+				PrevHash: head.LastHash,
+				Hash:     audit.ChainHash(chain.Name, seq, id, digest, head.LastHash),
+			}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+			head.LastSeq, head.LastHash = seq, entry.Hash
+		}
+		return tx.Model(&audit.SysAuditChain{}).Where("name = ?", chain.Name).
+			Updates(map[string]interface{}{"last_seq": head.LastSeq, "last_hash": head.LastHash}).Error
+	})
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// dataChangeOldRowsKey 更新、删除前的行快照在语句实例中的键
+	dataChangeOldRowsKey = "audit:data_change_old_rows"
+	// dataChangeMaxRows 单条语句最多记录的行数，批量更新超过时不记录，避免拖慢业务
+	dataChangeMaxRows = 500
+)
+
+// dataChangeRow 一行数据的主键和以列名为键的快照
+type dataChangeRow struct {
+	id   uint
+	data map[string]interface{}
+}
+
+type dataChangeRecorder struct {
+	tables map[string]audit.DataChangeTable
+}
+
+// RegisterDataChangeCallbacks 注册 GORM 回调，为指定的表在新增、更新和删除时写入数据日志
+// 更新和删除前后各查询一次受影响的行，日志与业务写入在同一事务中
+func RegisterDataChangeCallbacks(db *gorm.DB, tables []audit.DataChangeTable) error {
+	r := &dataChangeRecorder{tables: make(map[string]audit.DataChangeTable, len(tables))}
+	for _, t := range tables {
+		r.tables[t.Table] = t
+	}
+
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Register("audit:data_change_create", r.afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:data_change_before_update", r.beforeChange); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("audit:data_change_update", r.afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:data_change_before_delete", r.beforeChange); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Register("audit:data_change_delete", r.afterDelete)
+}
+
+// tracked 返回语句对应表的记录配置
+func (r *dataChangeRecorder) tracked(db *gorm.DB) (audit.DataChangeTable, bool) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return audit.DataChangeTable{}, false
+	}
+	table := stmt.Table
+	if table == "" {
+		table = stmt.Schema.Table
+	}
+	t, ok := r.tables[table]
+	return t, ok
+}
+
+func (r *dataChangeRecorder) afterCreate(db *gorm.DB) {
+	table, ok := r.tracked(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	ctx := db.Statement.Context
+	var rows []dataChangeRow
+	forEachModel(db.Statement.ReflectValue, func(v reflect.Value) {
+		rows = append(rows, snapshotRow(ctx, db.Statement.Schema, v))
+	})
+	for _, row := range rows {
+		r.save(db, table, audit.DataActionCreate, row.id, nil, row.data)
+	}
+}
+
+// beforeChange 更新、删除前按相同条件查询并保存受影响行的快照
+func (r *dataChangeRecorder) beforeChange(db *gorm.DB) {
+	if _, ok := r.tracked(db); !ok {
+		return
+	}
+	exprs := changeConditions(db)
+	if len(exprs) == 0 {
+		return
+	}
+	rows, err := loadRows(db, exprs)
+	if err != nil {
+		appLogger.Warn("读取数据变更前快照失败", zap.String("table", db.Statement.Table), zap.Error(err))
+		return
+	}
+	if len(rows) == 0 || len(rows) > dataChangeMaxRows {
+		return
+	}
+	db.InstanceSet(dataChangeOldRowsKey, rows)
+}
+
+func (r *dataChangeRecorder) afterUpdate(db *gorm.DB) {
+	table, ok := r.tracked(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	oldRows := instanceRows(db)
+	if len(oldRows) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(oldRows))
+	for _, row := range oldRows {
+		ids = append(ids, row.id)
+	}
+	pk := db.Statement.Schema.PrioritizedPrimaryField.DBName
+	newRows, err := loadRows(db, []clause.Expression{clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk}, Values: ids}})
+	if err != nil {
+		appLogger.Warn("读取数据变更后快照失败", zap.String("table", table.Table), zap.Error(err))
+		return
+	}
+	byID := make(map[uint]map[string]interface{}, len(newRows))
+	for _, row := range newRows {
+		byID[row.id] = row.data
+	}
+	for _, old := range oldRows {
+		if data, ok := byID[old.id]; ok {
+			r.save(db, table, audit.DataActionUpdate, old.id, old.data, data)
+		}
+	}
+}
+
+func (r *dataChangeRecorder) afterDelete(db *gorm.DB) {
+	table, ok := r.tracked(db)
+	if !ok || db.Statement.RowsAffected == 0 {
+		return
+	}
+	for _, old := range instanceRows(db) {
+		r.save(db, table, audit.DataActionDelete, old.id, old.data, nil)
+	}
+}
+
+// save 在当前连接（事务中即为同一事务）写入数据日志，失败只记录告警，不影响业务
+func (r *dataChangeRecorder) save(db *gorm.DB, table audit.DataChangeTable, action string, id uint, oldRow, newRow map[string]interface{}) {
+	log, err := audit.BuildDataLog(db.Statement.Context, table, action, id, oldRow, newRow)
+	if err == nil && log != nil {
+		err = db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(log).Error
+	}
+	if err != nil {
+		appLogger.Warn("保存数据日志失败", zap.String("table", table.Table), zap.Uint("recordId", id), zap.Error(err))
+	}
+}
+
+// changeConditions 还原语句的 WHERE 条件，按模型主键更新或删除时补上主键条件
+// 没有任何条件（全表操作）时返回空，不记录
+func changeConditions(db *gorm.DB) []clause.Expression {
+	stmt := db.Statement
+	var exprs []clause.Expression
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			exprs = append(exprs, where.Exprs...)
+		}
+	}
+
+	pk := stmt.Schema.PrioritizedPrimaryField
+	var ids []interface{}
+	forEachModel(stmt.ReflectValue, func(v reflect.Value) {
+		if id, zero := pk.ValueOf(stmt.Context, v); !zero {
+			ids = append(ids, id)
+		}
+	})
+	if len(ids) > 0 {
+		exprs = append(exprs, clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids})
+	}
+	return exprs
+}
+
+// loadRows 在同一连接上按条件查询行快照，软删除的行不会被查到
+func loadRows(db *gorm.DB, exprs []clause.Expression) ([]dataChangeRow, error) {
+	stmt := db.Statement
+	models := reflect.New(reflect.SliceOf(reflect.PointerTo(stmt.Schema.ModelType)))
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(stmt.Schema.ModelType).Interface())
+	if stmt.Table != "" {
+		tx = tx.Table(stmt.Table)
+	}
+	if stmt.Unscoped {
+		tx = tx.Unscoped()
+	}
+	if err := tx.Clauses(clause.Where{Exprs: exprs}).Limit(dataChangeMaxRows + 1).Find(models.Interface()).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]dataChangeRow, 0, models.Elem().Len())
+	forEachModel(models.Elem(), func(v reflect.Value) {
+		rows = append(rows, snapshotRow(stmt.Context, stmt.Schema, v))
+	})
+	return rows, nil
+}
+
+func instanceRows(db *gorm.DB) []dataChangeRow {
+	v, ok := db.InstanceGet(dataChangeOldRowsKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := v.([]dataChangeRow)
+	return rows
+}
+
+// forEachModel 遍历单个模型或模型切片
+func forEachModel(v reflect.Value, fn func(reflect.Value)) {
+	v = reflect.Indirect(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		fn(v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if elem := reflect.Indirect(v.Index(i)); elem.Kind() == reflect.Struct {
+				fn(elem)
+			}
+		}
+	}
+}
+
+// snapshotRow 以列名为键生成行快照
+func snapshotRow(ctx context.Context, sch *schema.Schema, v reflect.Value) dataChangeRow {
+	row := dataChangeRow{data: make(map[string]interface{}, len(sch.DBNames))}
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		value, _ := field.ValueOf(ctx, v)
+		row.data[field.DBName] = value
+	}
+	if id, zero := sch.PrioritizedPrimaryField.ValueOf(ctx, v); !zero {
+		row.id = toUint(id)
+	}
+	return row
+}
+
+func toUint(v interface{}) uint {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := rv.Int(); n > 0 {
+			return uint(n)
+		}
+	}
+	return 0
+}
@@ -49,6 +49,32 @@ func (r *dataLogRepo) List(ctx context.Context, page, pageSize int, username, ta
 	var logs []*audit.SysDataLog
 	var total int64
 
+	query := r.filter(ctx, username, tableName, action, startTime, endTime)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Offset((page-1)*pageSize).Limit(pageSize).
+		Order("created_at DESC").
+		Find(&logs).Error
+
+	return logs, total, err
+}
+
+// ListAll 按条件查询数据日志，最多返回 limit 条，用于导出
+func (r *dataLogRepo) ListAll(ctx context.Context, username, tableName, action, startTime, endTime string, limit int) ([]*audit.SysDataLog, error) {
+	var logs []*audit.SysDataLog
+	err := r.filter(ctx, username, tableName, action, startTime, endTime).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// filter 列表和导出共用的查询条件
+func (r *dataLogRepo) filter(ctx context.Context, username, tableName, action, startTime, endTime string) *gorm.DB {
 	query := r.db.WithContext(ctx).Model(&audit.SysDataLog{})
 
 	if username != "" {
@@ -74,25 +100,5 @@ func (r *dataLogRepo) List(ctx context.Context, page, pageSize int, username, ta
 		}
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	err = query.Offset((page-1)*pageSize).Limit(pageSize).
-		Order("created_at DESC").
-		Find(&logs).Error
-
-	return logs, total, err
-}
-
-func (r *dataLogRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&audit.SysDataLog{}, id).Error
-}
-
-func (r *dataLogRepo) DeleteBatch(ctx context.Context, ids []uint) error {
-	if len(ids) == 0 {
-		return nil
-	}
-	return r.db.WithContext(ctx).Delete(&audit.SysDataLog{}, ids).Error
+	return query
 }
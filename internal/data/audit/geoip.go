@@ -0,0 +1,108 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+)
+
+// GeoIPDatabase 读取 MaxMind DB 格式（.mmdb）的离线 IP 库，如 GeoLite2-City、DB-IP City Lite
+// 文件只读映射到内存，可并发查询
+type GeoIPDatabase struct {
+	reader   *maxminddb.Reader
+	language string
+}
+
+// geoIPNames 多语言地名
+type geoIPNames struct {
+	Names map[string]string `maxminddb:"names"`
+}
+
+// geoIPRecord City 库中用到的字段
+type geoIPRecord struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []geoIPNames `maxminddb:"subdivisions"`
+	City         geoIPNames   `maxminddb:"city"`
+	Location     struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// OpenGeoIPDatabase 打开离线 IP 库，language 为地名语言（如 zh-CN），缺失时回退到英文
+func OpenGeoIPDatabase(path, language string) (*GeoIPDatabase, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取IP库失败: %w", err)
+	}
+	return &GeoIPDatabase{reader: reader, language: language}, nil
+}
+
+// Close 关闭 IP 库
+func (db *GeoIPDatabase) Close() error {
+	return db.reader.Close()
+}
+
+// Lookup 查询 IP 所在地，库中没有记录时返回 nil
+func (db *GeoIPDatabase) Lookup(ip net.IP) (*audit.GeoLocation, error) {
+	// IPv4 库中没有 IPv6 地址的记录
+	if ip.To4() == nil && db.reader.Metadata.IPVersion == 4 {
+		return nil, nil
+	}
+	offset, err := db.reader.LookupOffset(ip)
+	if err != nil || offset == maxminddb.NotFound {
+		return nil, err
+	}
+	var record geoIPRecord
+	if err := db.reader.Decode(offset, &record); err != nil {
+		return nil, err
+	}
+
+	loc := &audit.GeoLocation{
+		CountryCode: record.Country.ISOCode,
+		Country:     db.name(record.Country.Names),
+		City:        db.name(record.City.Names),
+	}
+	if len(record.Subdivisions) > 0 {
+		loc.Region = db.name(record.Subdivisions[0].Names)
+	}
+	if record.Location.Latitude != nil && record.Location.Longitude != nil {
+		loc.Latitude, loc.Longitude, loc.HasCoordinates = *record.Location.Latitude, *record.Location.Longitude, true
+	}
+	if loc.Country == "" && loc.City == "" && !loc.HasCoordinates {
+		return nil, nil
+	}
+	return loc, nil
+}
+
+// name 按配置语言取地名，缺失时使用英文
+func (db *GeoIPDatabase) name(names map[string]string) string {
+	if name := names[db.language]; name != "" {
+		return name
+	}
+	return names["en"]
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"reflect"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"gorm.io/gorm"
+)
+
+// RegisterLoginAnomalyCallbacks 注册 GORM 回调：登录日志写入前按 IP 填充登录地点，写入后提交异常检测
+func RegisterLoginAnomalyCallbacks(db *gorm.DB, detector *audit.LoginAnomalyDetector) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("audit:login_locate", func(db *gorm.DB) {
+		forEachLoginLog(db, func(log *audit.SysLoginLog) {
+			if log.Location == "" {
+				_, log.Location = detector.Locate(log.IP)
+			}
+		})
+	}); err != nil {
+		return err
+	}
+	return db.Callback().Create().After("gorm:create").Register("audit:login_anomaly", func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.RowsAffected == 0 {
+			return
+		}
+		forEachLoginLog(db, func(log *audit.SysLoginLog) {
+			submitted := *log
+			detector.Submit(&submitted)
+		})
+	})
+}
+
+// forEachLoginLog 遍历本次写入的登录日志
+func forEachLoginLog(db *gorm.DB, fn func(log *audit.SysLoginLog)) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.Table != "sys_login_log" {
+		return
+	}
+	forEachModel(db.Statement.ReflectValue, func(v reflect.Value) {
+		if !v.CanAddr() {
+			return
+		}
+		if log, ok := v.Addr().Interface().(*audit.SysLoginLog); ok {
+			fn(log)
+		}
+	})
+}
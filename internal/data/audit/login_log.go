@@ -49,6 +49,32 @@ func (r *loginLogRepo) List(ctx context.Context, page, pageSize int, username, l
 	var logs []*audit.SysLoginLog
 	var total int64
 
+	query := r.filter(ctx, username, loginType, loginStatus, startTime, endTime)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Offset((page-1)*pageSize).Limit(pageSize).
+		Order("login_time DESC").
+		Find(&logs).Error
+
+	return logs, total, err
+}
+
+// ListAll 按条件查询登录日志，最多返回 limit 条，用于导出
+func (r *loginLogRepo) ListAll(ctx context.Context, username, loginType, loginStatus, startTime, endTime string, limit int) ([]*audit.SysLoginLog, error) {
+	var logs []*audit.SysLoginLog
+	err := r.filter(ctx, username, loginType, loginStatus, startTime, endTime).
+		Order("login_time DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// filter 列表和导出共用的查询条件
+func (r *loginLogRepo) filter(ctx context.Context, username, loginType, loginStatus, startTime, endTime string) *gorm.DB {
 	query := r.db.WithContext(ctx).Model(&audit.SysLoginLog{})
 
 	if username != "" {
@@ -74,16 +100,7 @@ func (r *loginLogRepo) List(ctx context.Context, page, pageSize int, username, l
 		}
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	err = query.Offset((page-1)*pageSize).Limit(pageSize).
-		Order("login_time DESC").
-		Find(&logs).Error
-
-	return logs, total, err
+	return query
 }
 
 func (r *loginLogRepo) UpdateLogout(ctx context.Context, userID uint, logoutTime *audit.SysLoginLog) error {
@@ -94,13 +111,45 @@ func (r *loginLogRepo) UpdateLogout(ctx context.Context, userID uint, logoutTime
 		Updates(logoutTime).Error
 }
 
-func (r *loginLogRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&audit.SysLoginLog{}, id).Error
+// CountFailedSince 统计 since 之后身份认证失败的次数
+func (r *loginLogRepo) CountFailedSince(ctx context.Context, username, ip string, since time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&audit.SysLoginLog{}).
+		Where("login_status = ? AND login_time >= ? AND login_type NOT IN ?", "failed", since, audit.NonAuthLoginTypes)
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
 }
 
-func (r *loginLogRepo) DeleteBatch(ctx context.Context, ids []uint) error {
-	if len(ids) == 0 {
-		return nil
+// ListLoginIPs 返回用户在 since 之后登录成功使用过的 IP，按最近使用排序，最多 200 个
+func (r *loginLogRepo) ListLoginIPs(ctx context.Context, username string, since time.Time, excludeID uint) ([]string, error) {
+	var ips []string
+	err := r.db.WithContext(ctx).Model(&audit.SysLoginLog{}).
+		Where("username = ? AND login_status = ? AND login_time >= ? AND id <> ? AND ip <> ''", username, "success", since, excludeID).
+		Where("login_type NOT IN ?", audit.NonAuthLoginTypes).
+		Group("ip").
+		Order("MAX(login_time) DESC").
+		Limit(200).
+		Pluck("ip", &ips).Error
+	return ips, err
+}
+
+// LastSuccessBefore 返回用户在 beforeID 之前最近一次登录成功的记录
+func (r *loginLogRepo) LastSuccessBefore(ctx context.Context, username string, beforeID uint) (*audit.SysLoginLog, error) {
+	var logs []*audit.SysLoginLog
+	err := r.db.WithContext(ctx).
+		Where("username = ? AND login_status = ? AND id < ?", username, "success", beforeID).
+		Where("login_type NOT IN ?", audit.NonAuthLoginTypes).
+		Order("id DESC").
+		Limit(1).
+		Find(&logs).Error
+	if err != nil || len(logs) == 0 {
+		return nil, err
 	}
-	return r.db.WithContext(ctx).Delete(&audit.SysLoginLog{}, ids).Error
+	return logs[0], nil
 }
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"gorm.io/gorm"
 )
 
@@ -45,24 +46,56 @@ func (r *operationLogRepo) GetByID(ctx context.Context, id uint) (*audit.SysOper
 	return &log, err
 }
 
-func (r *operationLogRepo) List(ctx context.Context, page, pageSize int, username, module, action, status, startTime, endTime string) ([]*audit.SysOperationLog, int64, error) {
+func (r *operationLogRepo) List(ctx context.Context, page, pageSize int, filter audit.OperationLogFilter) ([]*audit.SysOperationLog, int64, error) {
 	var logs []*audit.SysOperationLog
 	var total int64
 
+	query := r.filter(ctx, filter)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Offset((page-1)*pageSize).Limit(pageSize).
+		Order("created_at DESC").
+		Find(&logs).Error
+
+	return logs, total, err
+}
+
+// ListAll 按条件查询操作日志，最多返回 limit 条，用于导出
+func (r *operationLogRepo) ListAll(ctx context.Context, filter audit.OperationLogFilter, limit int) ([]*audit.SysOperationLog, error) {
+	var logs []*audit.SysOperationLog
+	err := r.filter(ctx, filter).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// filter 列表和导出共用的查询条件
+func (r *operationLogRepo) filter(ctx context.Context, filter audit.OperationLogFilter) *gorm.DB {
 	query := r.db.WithContext(ctx).Model(&audit.SysOperationLog{})
 
-	if username != "" {
-		query = query.Where("username LIKE ?", "%"+username+"%")
+	if filter.Username != "" {
+		query = query.Where("username LIKE ?", "%"+filter.Username+"%")
+	}
+	if filter.Module != "" {
+		query = query.Where("module = ?", filter.Module)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
 	}
-	if module != "" {
-		query = query.Where("module = ?", module)
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
 	}
-	if action != "" {
-		query = query.Where("action = ?", action)
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
 	}
-	if status != "" {
+	if filter.Status != "" {
 		// 处理状态码范围查询
-		switch status {
+		switch filter.Status {
 		case "2xx":
 			query = query.Where("status >= 200 AND status < 300")
 		case "3xx":
@@ -72,17 +105,17 @@ func (r *operationLogRepo) List(ctx context.Context, page, pageSize int, usernam
 		case "5xx":
 			query = query.Where("status >= 500 AND status < 600")
 		default:
-			query = query.Where("status = ?", status)
+			query = query.Where("status = ?", filter.Status)
 		}
 	}
-	if startTime != "" {
-		t, err := time.Parse("2006-01-02", startTime)
+	if filter.StartTime != "" {
+		t, err := time.Parse("2006-01-02", filter.StartTime)
 		if err == nil {
 			query = query.Where("created_at >= ?", t)
 		}
 	}
-	if endTime != "" {
-		t, err := time.Parse("2006-01-02", endTime)
+	if filter.EndTime != "" {
+		t, err := time.Parse("2006-01-02", filter.EndTime)
 		if err == nil {
 			// 加一天，包含当天
 			t = t.AddDate(0, 0, 1)
@@ -90,25 +123,8 @@ func (r *operationLogRepo) List(ctx context.Context, page, pageSize int, usernam
 		}
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
+	// 数据权限：只返回可见部门内用户的操作日志
+	query = query.Scopes(rbac.ScopeByDataPermission(ctx, "user_id"))
 
-	err = query.Offset((page-1)*pageSize).Limit(pageSize).
-		Order("created_at DESC").
-		Find(&logs).Error
-
-	return logs, total, err
-}
-
-func (r *operationLogRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&audit.SysOperationLog{}, id).Error
-}
-
-func (r *operationLogRepo) DeleteBatch(ctx context.Context, ids []uint) error {
-	if len(ids) == 0 {
-		return nil
-	}
-	return r.db.WithContext(ctx).Delete(&audit.SysOperationLog{}, ids).Error
+	return query
 }
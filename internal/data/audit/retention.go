@@ -0,0 +1,198 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purgeBatch 清理审计记录时每条 DELETE 语句包含的ID数
+const purgeBatch = 1000
+
+type auditRetentionRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRetentionRepo(db *gorm.DB) audit.AuditRetentionRepo {
+	return &auditRetentionRepo{db: db}
+}
+
+func (r *auditRetentionRepo) EnsurePolicies(ctx context.Context, policies []*audit.SysAuditRetentionPolicy) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(policies).Error
+}
+
+func (r *auditRetentionRepo) ListPolicies(ctx context.Context) ([]*audit.SysAuditRetentionPolicy, error) {
+	var policies []*audit.SysAuditRetentionPolicy
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *auditRetentionRepo) GetPolicy(ctx context.Context, logType string) (*audit.SysAuditRetentionPolicy, error) {
+	var policy audit.SysAuditRetentionPolicy
+	err := r.db.WithContext(ctx).Where("log_type = ?", logType).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &policy, err
+}
+
+func (r *auditRetentionRepo) UpdatePolicy(ctx context.Context, logType string, retentionDays int, enabled bool) error {
+	return r.db.WithContext(ctx).Model(&audit.SysAuditRetentionPolicy{}).
+		Where("log_type = ?", logType).
+		Updates(map[string]interface{}{"retention_days": retentionDays, "enabled": enabled}).Error
+}
+
+func (r *auditRetentionRepo) AcquireRun(ctx context.Context, logType string, staleBefore time.Time) (bool, error) {
+	// 条件更新保证多实例部署时同一策略只有一个在执行
+	result := r.db.WithContext(ctx).Model(&audit.SysAuditRetentionPolicy{}).
+		Where("log_type = ? AND (running_since IS NULL OR running_since < ?)", logType, staleBefore).
+		Updates(map[string]interface{}{"running_since": time.Now(), "last_status": audit.RetentionStatusRunning})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *auditRetentionRepo) FinishRun(ctx context.Context, logType, status, message string, archived int64) error {
+	if len([]rune(message)) > 500 {
+		message = string([]rune(message)[:500])
+	}
+	return r.db.WithContext(ctx).Model(&audit.SysAuditRetentionPolicy{}).
+		Where("log_type = ?", logType).
+		Updates(map[string]interface{}{
+			"running_since": nil,
+			"last_run_at":   time.Now(),
+			"last_status":   status,
+			"last_message":  message,
+			"last_archived": archived,
+		}).Error
+}
+
+func (r *auditRetentionRepo) FirstSeqSince(ctx context.Context, chain string, since time.Time) (uint64, error) {
+	var seq *uint64
+	err := r.db.WithContext(ctx).Model(&audit.SysAuditChainEntry{}).
+		Where("chain = ? AND created_at >= ?", chain, since).
+		Select("MIN(seq)").Scan(&seq).Error
+	if err != nil || seq == nil {
+		return 0, err
+	}
+	return *seq, nil
+}
+
+func (r *auditRetentionRepo) ListLegacy(ctx context.Context, chain audit.AuditChain, before time.Time, limit int) ([]uint, error) {
+	if !r.db.Migrator().HasTable(chain.Table) {
+		return nil, nil
+	}
+	var ids []uint
+	err := r.db.WithContext(ctx).Table(chain.Table+" AS t").
+		Where("t.created_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM sys_audit_chain_entry e WHERE e.chain = ? AND e.record_id = t.id)", chain.Name).
+		Order("t.id ASC").Limit(limit).Pluck("t.id", &ids).Error
+	return ids, err
+}
+
+func (r *auditRetentionRepo) PurgeArchived(ctx context.Context, chain audit.AuditChain, archive *audit.SysAuditArchive, recordIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if archive.ToSeq > 0 {
+			// 锁定链头，与追加链记录串行，并确认归档与上次截断位置衔接
+			var head audit.SysAuditChain
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", chain.Name).First(&head).Error; err != nil {
+				return err
+			}
+			if head.PrunedSeq+1 != archive.FromSeq {
+				return fmt.Errorf("审计链截断位置已变化，期望 %d，实际 %d", archive.FromSeq-1, head.PrunedSeq)
+			}
+			if err := tx.Where("chain = ? AND seq <= ?", chain.Name, archive.ToSeq).Delete(&audit.SysAuditChainEntry{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&audit.SysAuditChain{}).Where("name = ?", chain.Name).
+				Updates(map[string]interface{}{"pruned_seq": archive.ToSeq, "pruned_hash": archive.LastHash}).Error; err != nil {
+				return err
+			}
+		}
+		// 按表名物理删除，软删除的记录一并清理
+		for start := 0; start < len(recordIDs); start += purgeBatch {
+			end := start + purgeBatch
+			if end > len(recordIDs) {
+				end = len(recordIDs)
+			}
+			if err := tx.Exec("DELETE FROM "+chain.Table+" WHERE id IN ?", recordIDs[start:end]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(archive).Error
+	})
+}
+
+func (r *auditRetentionRepo) ListArchives(ctx context.Context, page, pageSize int, logType string) ([]*audit.SysAuditArchive, int64, error) {
+	var archives []*audit.SysAuditArchive
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&audit.SysAuditArchive{})
+	if logType != "" {
+		query = query.Where("log_type = ?", logType)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Offset((page - 1) * pageSize).Limit(pageSize).Order("id DESC").Find(&archives).Error
+	return archives, total, err
+}
+
+func (r *auditRetentionRepo) GetArchive(ctx context.Context, id uint) (*audit.SysAuditArchive, error) {
+	var archive audit.SysAuditArchive
+	err := r.db.WithContext(ctx).First(&archive, id).Error
+	return &archive, err
+}
+
+func (r *auditRetentionRepo) ImportArchiveRecords(ctx context.Context, records []*audit.SysAuditArchiveRecord) error {
+	return r.db.WithContext(ctx).Create(records).Error
+}
+
+func (r *auditRetentionRepo) MarkArchiveImported(ctx context.Context, archiveID uint, importedAt *time.Time, count int64) error {
+	return r.db.WithContext(ctx).Model(&audit.SysAuditArchive{}).Where("id = ?", archiveID).
+		Updates(map[string]interface{}{"imported_at": importedAt, "imported_count": count}).Error
+}
+
+func (r *auditRetentionRepo) DeleteArchiveRecords(ctx context.Context, archiveID uint) error {
+	return r.db.WithContext(ctx).Where("archive_id = ?", archiveID).Delete(&audit.SysAuditArchiveRecord{}).Error
+}
+
+func (r *auditRetentionRepo) ListArchiveRecords(ctx context.Context, archiveID uint, page, pageSize int, username, keyword string) ([]*audit.SysAuditArchiveRecord, int64, error) {
+	var records []*audit.SysAuditArchiveRecord
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&audit.SysAuditArchiveRecord{}).Where("archive_id = ?", archiveID)
+	if username != "" {
+		query = query.Where("username LIKE ?", "%"+username+"%")
+	}
+	if keyword != "" {
+		query = query.Where("data LIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Offset((page - 1) * pageSize).Limit(pageSize).Order("record_time ASC, id ASC").Find(&records).Error
+	return records, total, err
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"gorm.io/gorm"
+)
+
+type securityEventRepo struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepo(db *gorm.DB) audit.SecurityEventRepo {
+	return &securityEventRepo{db: db}
+}
+
+func (r *securityEventRepo) Create(ctx context.Context, event *audit.SysSecurityEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *securityEventRepo) GetByID(ctx context.Context, id uint) (*audit.SysSecurityEvent, error) {
+	var event audit.SysSecurityEvent
+	err := r.db.WithContext(ctx).First(&event, id).Error
+	return &event, err
+}
+
+func (r *securityEventRepo) List(ctx context.Context, page, pageSize int, filter audit.SecurityEventFilter) ([]*audit.SysSecurityEvent, int64, error) {
+	var events []*audit.SysSecurityEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&audit.SysSecurityEvent{})
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Level != "" {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Username != "" {
+		query = query.Where("username LIKE ?", "%"+filter.Username+"%")
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.StartTime != "" {
+		if t, err := time.Parse("2006-01-02", filter.StartTime); err == nil {
+			query = query.Where("created_at >= ?", t)
+		}
+	}
+	if filter.EndTime != "" {
+		if t, err := time.Parse("2006-01-02", filter.EndTime); err == nil {
+			query = query.Where("created_at < ?", t.AddDate(0, 0, 1))
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Offset((page - 1) * pageSize).Limit(pageSize).Order("id DESC").Find(&events).Error
+	return events, total, err
+}
+
+func (r *securityEventRepo) ExistsSince(ctx context.Context, eventType, username, ip string, since time.Time) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&audit.SysSecurityEvent{}).
+		Where("event_type = ? AND created_at >= ?", eventType, since)
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *securityEventRepo) UpdateNotify(ctx context.Context, id uint, notified bool, notifyError string) error {
+	return r.db.WithContext(ctx).Model(&audit.SysSecurityEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"notified": notified, "notify_error": notifyError}).Error
+}
+
+func (r *securityEventRepo) Handle(ctx context.Context, id uint, status string, handledBy uint, handlerName, remark string, handledAt time.Time) error {
+	updates := map[string]interface{}{
+		"status":       status,
+		"handled_by":   handledBy,
+		"handler_name": handlerName,
+		"handled_at":   handledAt,
+		"remark":       remark,
+	}
+	if status == audit.SecurityStatusOpen {
+		updates["handled_at"] = nil
+	}
+	return r.db.WithContext(ctx).Model(&audit.SysSecurityEvent{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *securityEventRepo) Stats(ctx context.Context, since time.Time) (*audit.SecurityEventStats, error) {
+	stats := &audit.SecurityEventStats{RecentByType: make(map[string]int64)}
+	db := r.db.WithContext(ctx).Model(&audit.SysSecurityEvent{})
+
+	if err := db.Session(&gorm.Session{}).Where("status = ?", audit.SecurityStatusOpen).Count(&stats.Open).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Session(&gorm.Session{}).Where("status = ? AND level = ?", audit.SecurityStatusOpen, audit.SecurityLevelCritical).Count(&stats.OpenCritical).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		EventType string
+		Count     int64
+	}
+	if err := db.Session(&gorm.Session{}).Select("event_type, COUNT(*) AS count").
+		Where("created_at >= ?", since).Group("event_type").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		stats.RecentByType[row.EventType] = row.Count
+		stats.Recent += row.Count
+	}
+	return stats, nil
+}
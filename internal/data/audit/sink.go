@@ -0,0 +1,419 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 审计事件转发目标类型
+const (
+	SinkTypeSyslog  = "syslog"
+	SinkTypeWebhook = "webhook"
+	SinkTypeFile    = "file"
+)
+
+const (
+	defaultSinkQueueDir      = "./data/audit-queue"
+	defaultSinkBatchSize     = 100
+	defaultSinkFlushInterval = 5 * time.Second
+	defaultSinkTimeout       = 10 * time.Second
+	defaultSinkMaxQueueSize  = 512
+	// sinkChannelSize 每个转发目标的内存缓冲，写满后直接写入重试队列
+	sinkChannelSize = 10000
+	// sinkMaxBackoff 连续失败时重试间隔的上限
+	sinkMaxBackoff = 5 * time.Minute
+)
+
+// AuditSink 审计事件转发目标
+type AuditSink interface {
+	Send(ctx context.Context, events []*audit.AuditEvent) error
+	Close() error
+}
+
+// AuditSinkPipeline 将审计事件扇出到所有转发目标，每个目标独立批量发送
+// 发送失败的批次写入本地重试队列，目标恢复后先按顺序补发队列中的事件
+type AuditSinkPipeline struct {
+	workers []*sinkWorker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewAuditSinkPipeline 根据配置创建转发目标并启动发送协程，未启用任何目标时返回 nil
+func NewAuditSinkPipeline(queueDir string, configs []conf.AuditSinkConfig) (*AuditSinkPipeline, error) {
+	if queueDir == "" {
+		queueDir = defaultSinkQueueDir
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &AuditSinkPipeline{cancel: cancel}
+	names := make(map[string]bool)
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		if cfg.Name == "" || strings.ContainsAny(cfg.Name, `/\.`) {
+			p.Close()
+			return nil, fmt.Errorf("审计转发目标名称无效: %q", cfg.Name)
+		}
+		if names[cfg.Name] {
+			p.Close()
+			return nil, fmt.Errorf("审计转发目标名称重复: %s", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		sink, err := newAuditSink(cfg)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("审计转发目标 %s: %w", cfg.Name, err)
+		}
+		queue, err := newSinkQueue(queueDir, cfg.Name, sinkMaxQueueBytes(cfg))
+		if err != nil {
+			sink.Close()
+			p.Close()
+			return nil, fmt.Errorf("审计转发目标 %s: %w", cfg.Name, err)
+		}
+		w := newSinkWorker(cfg, sink, queue)
+		p.workers = append(p.workers, w)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			w.run(ctx)
+		}()
+	}
+	if len(p.workers) == 0 {
+		cancel()
+		return nil, nil
+	}
+	return p, nil
+}
+
+func newAuditSink(cfg conf.AuditSinkConfig) (AuditSink, error) {
+	switch cfg.Type {
+	case SinkTypeSyslog:
+		return newSyslogSink(cfg)
+	case SinkTypeWebhook:
+		return newWebhookSink(cfg)
+	case SinkTypeFile:
+		return newFileSink(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的类型: %s", cfg.Type)
+	}
+}
+
+// Publish 将事件交给各转发目标，不阻塞调用方
+func (p *AuditSinkPipeline) Publish(event *audit.AuditEvent) {
+	for _, w := range p.workers {
+		w.enqueue(event)
+	}
+}
+
+// Close 停止发送，内存中未发送的事件写入重试队列，下次启动后补发
+func (p *AuditSinkPipeline) Close() {
+	p.cancel()
+	p.wg.Wait()
+	for _, w := range p.workers {
+		w.sink.Close()
+	}
+}
+
+func sinkMaxQueueBytes(cfg conf.AuditSinkConfig) int64 {
+	size := cfg.MaxQueueSize
+	if size <= 0 {
+		size = defaultSinkMaxQueueSize
+	}
+	return int64(size) * 1024 * 1024
+}
+
+type sinkWorker struct {
+	name          string
+	sink          AuditSink
+	queue         *sinkQueue
+	events        map[string]bool
+	batchSize     int
+	flushInterval time.Duration
+	timeout       time.Duration
+	ch            chan *audit.AuditEvent
+
+	// 连续失败时按指数退避，期间新批次直接进入重试队列
+	failures  int
+	nextRetry time.Time
+}
+
+func newSinkWorker(cfg conf.AuditSinkConfig, sink AuditSink, queue *sinkQueue) *sinkWorker {
+	w := &sinkWorker{
+		name:          cfg.Name,
+		sink:          sink,
+		queue:         queue,
+		batchSize:     cfg.BatchSize,
+		flushInterval: time.Duration(cfg.FlushInterval) * time.Second,
+		timeout:       time.Duration(cfg.Timeout) * time.Second,
+		ch:            make(chan *audit.AuditEvent, sinkChannelSize),
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = defaultSinkBatchSize
+	}
+	if w.flushInterval <= 0 {
+		w.flushInterval = defaultSinkFlushInterval
+	}
+	if w.timeout <= 0 {
+		w.timeout = defaultSinkTimeout
+	}
+	if len(cfg.Events) > 0 {
+		w.events = make(map[string]bool, len(cfg.Events))
+		for _, t := range cfg.Events {
+			w.events[t] = true
+		}
+	}
+	return w
+}
+
+func (w *sinkWorker) enqueue(event *audit.AuditEvent) {
+	if w.events != nil && !w.events[event.Type] {
+		return
+	}
+	select {
+	case w.ch <- event:
+	default:
+		// 发送跟不上写入速度，直接落盘，避免丢失
+		if err := w.queue.Append([]*audit.AuditEvent{event}); err != nil {
+			appLogger.Error("审计事件写入重试队列失败", zap.String("sink", w.name), zap.Error(err))
+		}
+	}
+}
+
+func (w *sinkWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*audit.AuditEvent, 0, w.batchSize)
+	for {
+		select {
+		case event := <-w.ch:
+			batch = append(batch, event)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(ctx, batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-w.ch:
+					batch = append(batch, event)
+					continue
+				default:
+				}
+				break
+			}
+			if len(batch) > 0 {
+				if err := w.queue.Append(batch); err != nil {
+					appLogger.Error("审计事件写入重试队列失败", zap.String("sink", w.name), zap.Int("count", len(batch)), zap.Error(err))
+				}
+			}
+			return
+		}
+	}
+}
+
+// flush 先补发重试队列，队列清空后再发送当前批次，保证事件顺序
+func (w *sinkWorker) flush(ctx context.Context, batch []*audit.AuditEvent) []*audit.AuditEvent {
+	if w.drainQueue(ctx) && len(batch) > 0 {
+		if err := w.send(ctx, batch); err == nil {
+			return batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err := w.queue.Append(batch); err != nil {
+			appLogger.Error("审计事件写入重试队列失败", zap.String("sink", w.name), zap.Int("count", len(batch)), zap.Error(err))
+		}
+	}
+	return batch[:0]
+}
+
+// drainQueue 按写入顺序补发重试队列，全部发送成功时返回 true
+func (w *sinkWorker) drainQueue(ctx context.Context) bool {
+	if time.Now().Before(w.nextRetry) {
+		return false
+	}
+	for {
+		file, events, err := w.queue.Oldest()
+		if err != nil {
+			appLogger.Error("读取审计事件重试队列失败", zap.String("sink", w.name), zap.Error(err))
+			return false
+		}
+		if file == "" {
+			return true
+		}
+		if len(events) > 0 {
+			if err := w.send(ctx, events); err != nil {
+				return false
+			}
+		}
+		w.queue.Remove(file)
+	}
+}
+
+func (w *sinkWorker) send(ctx context.Context, events []*audit.AuditEvent) error {
+	sendCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	err := w.sink.Send(sendCtx, events)
+	if err != nil {
+		w.failures++
+		backoff := time.Duration(1<<min(w.failures, 10)) * time.Second
+		if backoff > sinkMaxBackoff {
+			backoff = sinkMaxBackoff
+		}
+		w.nextRetry = time.Now().Add(backoff)
+		// 只在开始失败和之后每 10 次记录一次，避免目标长时间不可用时刷屏
+		if w.failures == 1 || w.failures%10 == 0 {
+			appLogger.Warn("审计事件转发失败，已写入重试队列", zap.String("sink", w.name), zap.Int("failures", w.failures), zap.Error(err))
+		}
+		return err
+	}
+	if w.failures > 0 {
+		appLogger.Info("审计事件转发已恢复", zap.String("sink", w.name), zap.Int("failures", w.failures))
+	}
+	w.failures = 0
+	w.nextRetry = time.Time{}
+	return nil
+}
+
+// RegisterAuditSinkCallbacks 注册 GORM 回调，操作日志、登录日志和数据日志写入后发布审计事件
+func RegisterAuditSinkCallbacks(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").Register("audit:sink_publish", publishCreatedAuditEvents)
+}
+
+func publishCreatedAuditEvents(db *gorm.DB) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 || db.Statement.Schema == nil {
+		return
+	}
+	switch db.Statement.Schema.Table {
+	case "sys_operation_log", "sys_login_log", "sys_data_log":
+	default:
+		return
+	}
+	forEachModel(db.Statement.ReflectValue, func(v reflect.Value) {
+		var event *audit.AuditEvent
+		switch m := v.Interface().(type) {
+		case audit.SysOperationLog:
+			event = operationLogEvent(&m)
+		case audit.SysLoginLog:
+			event = loginLogEvent(&m)
+		case audit.SysDataLog:
+			event = dataLogEvent(&m)
+		}
+		if event != nil {
+			audit.PublishEvent(event)
+		}
+	})
+}
+
+func operationLogEvent(log *audit.SysOperationLog) *audit.AuditEvent {
+	outcome := audit.EventOutcomeSuccess
+	if log.Status >= 400 {
+		outcome = audit.EventOutcomeFailure
+	}
+	target := log.Method + " " + log.Path
+	if log.ResourceType != "" {
+		target = log.ResourceType + "/" + log.ResourceID
+	}
+	return &audit.AuditEvent{
+		ID:        audit.EventTypeOperation + "-" + strconv.FormatUint(uint64(log.ID), 10),
+		Type:      audit.EventTypeOperation,
+		Time:      log.CreatedAt,
+		UserID:    log.UserID,
+		Username:  log.Username,
+		IP:        log.IP,
+		UserAgent: log.UserAgent,
+		Action:    log.Action,
+		Target:    target,
+		Outcome:   outcome,
+		Detail: map[string]interface{}{
+			"module":       log.Module,
+			"description":  log.Description,
+			"method":       log.Method,
+			"path":         log.Path,
+			"resourceType": log.ResourceType,
+			"resourceId":   log.ResourceID,
+			"params":       log.Params,
+			"status":       log.Status,
+			"errorMsg":     log.ErrorMsg,
+			"costTime":     log.CostTime,
+		},
+	}
+}
+
+func loginLogEvent(log *audit.SysLoginLog) *audit.AuditEvent {
+	outcome := audit.EventOutcomeSuccess
+	if log.LoginStatus != "success" {
+		outcome = audit.EventOutcomeFailure
+	}
+	return &audit.AuditEvent{
+		ID:        audit.EventTypeLogin + "-" + strconv.FormatUint(uint64(log.ID), 10),
+		Type:      audit.EventTypeLogin,
+		Time:      log.LoginTime,
+		UserID:    log.UserID,
+		Username:  log.Username,
+		IP:        log.IP,
+		UserAgent: log.UserAgent,
+		Action:    "login",
+		Target:    log.LoginType,
+		Outcome:   outcome,
+		Detail: map[string]interface{}{
+			"loginType":  log.LoginType,
+			"location":   log.Location,
+			"failReason": log.FailReason,
+		},
+	}
+}
+
+func dataLogEvent(log *audit.SysDataLog) *audit.AuditEvent {
+	return &audit.AuditEvent{
+		ID:        audit.EventTypeDataChange + "-" + strconv.FormatUint(uint64(log.ID), 10),
+		Type:      audit.EventTypeDataChange,
+		Time:      log.CreatedAt,
+		UserID:    log.UserID,
+		Username:  log.Username,
+		IP:        log.IP,
+		UserAgent: log.UserAgent,
+		Action:    log.Action,
+		Target:    log.Table + "/" + strconv.FormatUint(uint64(log.RecordID), 10),
+		Outcome:   audit.EventOutcomeSuccess,
+		Detail: map[string]interface{}{
+			"tableName":  log.Table,
+			"recordId":   log.RecordID,
+			"diffFields": log.DiffFields,
+			"oldData":    log.OldData,
+			"newData":    log.NewData,
+		},
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// sinkQueue 转发目标的本地重试队列，每个批次一个 JSONL 文件，文件名按写入时间排序
+type sinkQueue struct {
+	dir      string
+	maxBytes int64
+	seq      atomic.Uint64
+	mu       sync.Mutex
+}
+
+func newSinkQueue(baseDir, name string, maxBytes int64) (*sinkQueue, error) {
+	dir := filepath.Join(baseDir, name)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("创建重试队列目录失败: %w", err)
+	}
+	// 清理上次异常退出时残留的临时文件
+	if tmps, err := filepath.Glob(filepath.Join(dir, "*.tmp")); err == nil {
+		for _, tmp := range tmps {
+			os.Remove(tmp)
+		}
+	}
+	return &sinkQueue{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// Append 将一个批次写入队列，先写临时文件再重命名，避免读到写了一半的批次
+func (q *sinkQueue) Append(events []*audit.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name := fmt.Sprintf("%020d-%06d.jsonl", time.Now().UnixNano(), q.seq.Add(1)%1000000)
+	path := filepath.Join(q.dir, name)
+	f, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			f.Close()
+			os.Remove(path + ".tmp")
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(path + ".tmp")
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	q.enforceLimit()
+	return nil
+}
+
+// Oldest 返回最早的批次，队列为空时 file 为空
+func (q *sinkQueue) Oldest() (string, []*audit.AuditEvent, error) {
+	q.mu.Lock()
+	files, err := q.files()
+	q.mu.Unlock()
+	if err != nil || len(files) == 0 {
+		return "", nil, err
+	}
+	path := filepath.Join(q.dir, files[0])
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 刚好被容量限制清理，由调用方继续读取下一个
+			return path, nil, nil
+		}
+		return "", nil, err
+	}
+	defer f.Close()
+
+	var events []*audit.AuditEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event audit.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			appLogger.Warn("跳过无法解析的审计重试事件", zap.String("file", path), zap.Error(err))
+			continue
+		}
+		events = append(events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		appLogger.Warn("审计重试批次读取不完整", zap.String("file", path), zap.Error(err))
+	}
+	return path, events, nil
+}
+
+// Remove 删除已发送的批次
+func (q *sinkQueue) Remove(path string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		appLogger.Error("删除审计重试批次失败", zap.String("file", path), zap.Error(err))
+	}
+}
+
+// enforceLimit 队列超过容量上限时从最早的批次开始丢弃
+func (q *sinkQueue) enforceLimit() {
+	files, err := q.files()
+	if err != nil {
+		return
+	}
+	sizes := make([]int64, len(files))
+	var total int64
+	for i, name := range files {
+		if info, err := os.Stat(filepath.Join(q.dir, name)); err == nil {
+			sizes[i] = info.Size()
+			total += info.Size()
+		}
+	}
+	dropped := 0
+	// 至少保留最新的批次
+	for i := 0; total > q.maxBytes && i < len(files)-1; i++ {
+		if err := os.Remove(filepath.Join(q.dir, files[i])); err != nil {
+			continue
+		}
+		total -= sizes[i]
+		dropped++
+	}
+	if dropped > 0 {
+		appLogger.Warn("审计重试队列超过容量上限，已丢弃最早的批次",
+			zap.String("dir", q.dir), zap.Int("files", dropped), zap.Int64("maxBytes", q.maxBytes))
+	}
+}
+
+func (q *sinkQueue) files() ([]string, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+)
+
+const (
+	// syslogFacilityLogAudit RFC 5424 中的 log audit 设施
+	syslogFacilityLogAudit = 13
+	syslogSeverityWarning  = 4
+	syslogSeverityInfo     = 6
+	// syslogEnterpriseID 结构化数据 SD-ID 使用的私有企业号
+	syslogEnterpriseID = "opshub@32473"
+	// syslogMaxUDPMessage UDP 单条消息上限，超出时截断事件详情
+	syslogMaxUDPMessage = 8192
+)
+
+// syslogSink 以 RFC 5424 格式发送审计事件，TCP/TLS 使用 octet-counting 分帧 (RFC 6587)
+type syslogSink struct {
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	timeout  time.Duration
+	tls      *tls.Config
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogSink(cfg conf.AuditSinkConfig) (*syslogSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("syslog 地址不能为空")
+	}
+	s := &syslogSink{
+		network:  strings.ToLower(cfg.Network),
+		address:  cfg.Address,
+		facility: cfg.Facility,
+		appName:  cfg.AppName,
+		timeout:  time.Duration(cfg.Timeout) * time.Second,
+	}
+	if s.network == "" {
+		s.network = "udp"
+	}
+	if s.facility <= 0 || s.facility > 23 {
+		s.facility = syslogFacilityLogAudit
+	}
+	if s.appName == "" {
+		s.appName = "opshub"
+	}
+	if s.timeout <= 0 {
+		s.timeout = defaultSinkTimeout
+	}
+	s.hostname, _ = os.Hostname()
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	switch s.network {
+	case "udp", "tcp":
+	case "tls":
+		host, _, err := net.SplitHostPort(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("syslog 地址格式错误: %w", err)
+		}
+		s.tls = &tls.Config{ServerName: host, InsecureSkipVerify: cfg.Insecure, MinVersion: tls.VersionTLS12}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA 证书格式错误: %s", cfg.CAFile)
+			}
+			s.tls.RootCAs = pool
+		}
+	default:
+		return nil, fmt.Errorf("不支持的 syslog 协议: %s", cfg.Network)
+	}
+	return s, nil
+}
+
+// Send 逐条发送，连接出错时关闭连接，由下次发送重新建立
+func (s *syslogSink) Send(ctx context.Context, events []*audit.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.timeout)
+	}
+	s.conn.SetWriteDeadline(deadline)
+
+	var buf bytes.Buffer
+	for _, event := range events {
+		msg, err := s.format(event)
+		if err != nil {
+			continue
+		}
+		if s.network == "udp" {
+			if _, err := s.conn.Write(msg); err != nil {
+				s.closeConn()
+				return fmt.Errorf("发送 syslog 消息失败: %w", err)
+			}
+			continue
+		}
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.Write(msg)
+	}
+	if buf.Len() > 0 {
+		if _, err := s.conn.Write(buf.Bytes()); err != nil {
+			s.closeConn()
+			return fmt.Errorf("发送 syslog 消息失败: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", s.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接 syslog 服务器失败: %w", err)
+	}
+	return conn, nil
+}
+
+// format 生成 RFC 5424 消息：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *syslogSink) format(event *audit.AuditEvent) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	severity := syslogSeverityInfo
+	if event.Outcome == audit.EventOutcomeFailure {
+		severity = syslogSeverityWarning
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %d %s ",
+		s.facility*8+severity,
+		event.Time.UTC().Format(time.RFC3339Nano),
+		syslogHeaderValue(s.hostname, 255),
+		syslogHeaderValue(s.appName, 48),
+		os.Getpid(),
+		syslogHeaderValue(event.Type, 32),
+	)
+	b.WriteString("[" + syslogEnterpriseID)
+	writeSyslogParam(&b, "id", event.ID)
+	writeSyslogParam(&b, "user", event.Username)
+	writeSyslogParam(&b, "ip", event.IP)
+	writeSyslogParam(&b, "action", event.Action)
+	writeSyslogParam(&b, "outcome", event.Outcome)
+	b.WriteString("] ")
+	if s.network == "udp" && b.Len()+len(body) > syslogMaxUDPMessage {
+		// 数据报放不下完整事件时去掉详情，保留关键字段
+		trimmed := *event
+		trimmed.Detail = map[string]interface{}{"truncated": true}
+		if body, err = json.Marshal(&trimmed); err != nil {
+			return nil, err
+		}
+	}
+	b.Write(body)
+	return b.Bytes(), nil
+}
+
+// syslogHeaderValue 头部字段只允许可打印 ASCII，空值用 NILVALUE 表示
+func syslogHeaderValue(v string, maxLen int) string {
+	out := make([]byte, 0, len(v))
+	for i := 0; i < len(v) && len(out) < maxLen; i++ {
+		if v[i] > 32 && v[i] < 127 {
+			out = append(out, v[i])
+		}
+	}
+	if len(out) == 0 {
+		return "-"
+	}
+	return string(out)
+}
+
+// writeSyslogParam 写入结构化数据参数，按 RFC 5424 转义 " \ ]
+func writeSyslogParam(b *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	b.WriteString(" " + name + `="`)
+	for _, r := range value {
+		switch r {
+		case '"', '\\', ']':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+}
+
+func (s *syslogSink) closeConn() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close 关闭连接
+func (s *syslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConn()
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+// NewTimelineSources 创建内置的时间线来源
+func NewTimelineSources(db *gorm.DB) []audit.TimelineSource {
+	return []audit.TimelineSource{
+		&operationTimeline{db: db},
+		&loginTimeline{db: db},
+		&securityTimeline{db: db},
+		&sshSessionTimeline{db: db},
+	}
+}
+
+// operationTimeline 操作日志，资源按操作日志记录的资源类型和资源ID匹配
+type operationTimeline struct {
+	db *gorm.DB
+}
+
+func (s *operationTimeline) Name() string  { return audit.TimelineSourceOperation }
+func (s *operationTimeline) Label() string { return "操作日志" }
+
+func (s *operationTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	db := s.db.WithContext(ctx).Model(&audit.SysOperationLog{})
+	if query.HasResource() {
+		db = db.Where("resource_type = ? AND resource_id = ?", query.ResourceType, query.ResourceID)
+	}
+	var logs []*audit.SysOperationLog
+	err := db.Scopes(
+		audit.TimelineScope(query, "created_at", "user_id", "username"),
+		rbac.ScopeByDataPermission(ctx, "user_id"),
+	).Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.TimelineEntry, 0, len(logs))
+	for _, log := range logs {
+		title := log.Description
+		if title == "" {
+			title = log.Method + " " + log.Path
+		}
+		if log.Module != "" {
+			title = log.Module + ": " + title
+		}
+		status := "success"
+		if log.Status >= 400 {
+			status = "failed"
+		}
+		entries = append(entries, &audit.TimelineEntry{
+			RecordID:     log.ID,
+			Time:         log.CreatedAt,
+			UserID:       log.UserID,
+			Username:     log.Username,
+			Action:       log.Action,
+			Title:        title,
+			ResourceType: log.ResourceType,
+			ResourceID:   log.ResourceID,
+			Status:       status,
+			IP:           log.IP,
+			DetailKind:   audit.TimelineDetailJSON,
+			DetailURL:    fmt.Sprintf("/api/v1/audit/operation-logs/%d", log.ID),
+			Link:         "/audit/operation-logs",
+		})
+	}
+	return entries, nil
+}
+
+// loginTimeline 登录日志，只按用户查询
+type loginTimeline struct {
+	db *gorm.DB
+}
+
+func (s *loginTimeline) Name() string  { return audit.TimelineSourceLogin }
+func (s *loginTimeline) Label() string { return "登录日志" }
+
+func (s *loginTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	if !query.HasUser() {
+		return nil, nil
+	}
+	var logs []*audit.SysLoginLog
+	err := s.db.WithContext(ctx).Model(&audit.SysLoginLog{}).Scopes(
+		audit.TimelineScope(query, "login_time", "user_id", "username"),
+		rbac.ScopeByDataPermission(ctx, "user_id"),
+	).Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.TimelineEntry, 0, len(logs))
+	for _, log := range logs {
+		action := audit.ActionLogin
+		switch {
+		case log.LoginType == "logout":
+			action = audit.ActionLogout
+		case strings.HasPrefix(log.LoginType, "mfa_"):
+			action = "MFA"
+		}
+		title := strings.ToUpper(log.LoginType)
+		if log.LoginStatus == "success" {
+			title += " 成功"
+		} else {
+			title += " 失败"
+		}
+		if log.FailReason != "" {
+			title += ": " + log.FailReason
+		}
+		if log.Location != "" {
+			title += " · " + log.Location
+		}
+		entries = append(entries, &audit.TimelineEntry{
+			RecordID:   log.ID,
+			Time:       log.LoginTime,
+			EndTime:    log.LogoutTime,
+			UserID:     log.UserID,
+			Username:   log.Username,
+			Action:     action,
+			Title:      title,
+			Status:     log.LoginStatus,
+			IP:         log.IP,
+			DetailKind: audit.TimelineDetailJSON,
+			DetailURL:  fmt.Sprintf("/api/v1/audit/login-logs/%d", log.ID),
+			Link:       "/audit/login-logs",
+		})
+	}
+	return entries, nil
+}
+
+// securityTimeline 登录安全事件，资源类型 security_event
+type securityTimeline struct {
+	db *gorm.DB
+}
+
+func (s *securityTimeline) Name() string  { return audit.TimelineSourceSecurity }
+func (s *securityTimeline) Label() string { return "安全事件" }
+
+func (s *securityTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	db := s.db.WithContext(ctx).Model(&audit.SysSecurityEvent{})
+	if query.HasResource() {
+		if query.ResourceType != "security_event" {
+			return nil, nil
+		}
+		db = db.Where("id = ?", query.ResourceID)
+	}
+	var events []*audit.SysSecurityEvent
+	err := db.Scopes(
+		audit.TimelineScope(query, "created_at", "user_id", "username"),
+		rbac.ScopeByDataPermission(ctx, "user_id"),
+	).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.TimelineEntry, 0, len(events))
+	for _, event := range events {
+		entries = append(entries, &audit.TimelineEntry{
+			RecordID:     event.ID,
+			Time:         event.CreatedAt,
+			UserID:       event.UserID,
+			Username:     event.Username,
+			Action:       audit.SecurityEventNames[event.EventType],
+			Title:        event.Title,
+			ResourceType: "security_event",
+			ResourceID:   strconv.FormatUint(uint64(event.ID), 10),
+			Status:       event.Level,
+			IP:           event.IP,
+			DetailKind:   audit.TimelineDetailJSON,
+			DetailURL:    fmt.Sprintf("/api/v1/audit/security-events/%d", event.ID),
+			Link:         "/audit/security-events",
+		})
+	}
+	return entries, nil
+}
+
+// sshSessionTimeline SSH 终端会话，资源类型 host 按主机匹配，terminal_session 按会话匹配
+type sshSessionTimeline struct {
+	db *gorm.DB
+}
+
+func (s *sshSessionTimeline) Name() string  { return audit.TimelineSourceSSHSession }
+func (s *sshSessionTimeline) Label() string { return "SSH 会话" }
+
+func (s *sshSessionTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	db := s.db.WithContext(ctx).Model(&assetbiz.TerminalSession{})
+	if query.HasResource() {
+		switch query.ResourceType {
+		case "host":
+			db = db.Where("host_id = ?", query.ResourceID)
+		case "terminal_session":
+			db = db.Where("id = ?", query.ResourceID)
+		default:
+			return nil, nil
+		}
+	}
+	var sessions []*assetbiz.TerminalSession
+	err := db.Scopes(
+		audit.TimelineScope(query, "created_at", "user_id", "username"),
+		rbac.ScopeByDataPermission(ctx, "user_id"),
+	).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.TimelineEntry, 0, len(sessions))
+	for _, session := range sessions {
+		entry := &audit.TimelineEntry{
+			RecordID:     session.ID,
+			Time:         session.CreatedAt,
+			UserID:       session.UserID,
+			Username:     session.Username,
+			Action:       audit.ActionConnect,
+			Title:        fmt.Sprintf("SSH 连接 %s (%s)", session.HostName, session.HostIP),
+			ResourceType: "host",
+			ResourceID:   strconv.FormatUint(uint64(session.HostID), 10),
+			Status:       session.Status,
+			Link:         "/asset/terminal-audit",
+		}
+		if session.Duration > 0 {
+			end := session.CreatedAt.Add(time.Duration(session.Duration) * time.Second)
+			entry.EndTime = &end
+		}
+		if session.RecordingPath != "" {
+			entry.DetailKind = audit.TimelineDetailRecording
+			entry.DetailURL = fmt.Sprintf("/api/v1/terminal-sessions/%d/play", session.ID)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+type accessRequestRepo struct {
+	db *gorm.DB
+}
+
+// NewAccessRequestRepo 创建访问申请仓储
+func NewAccessRequestRepo(db *gorm.DB) rbac.AccessRequestRepo {
+	return &accessRequestRepo{db: db}
+}
+
+// Create 创建访问申请
+func (r *accessRequestRepo) Create(ctx context.Context, req *rbac.SysAccessRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+// Update 更新访问申请
+func (r *accessRequestRepo) Update(ctx context.Context, req *rbac.SysAccessRequest) error {
+	return r.db.WithContext(ctx).Save(req).Error
+}
+
+// Review 保存审批结果，只有仍处于待审批状态的申请会被更新
+func (r *accessRequestRepo) Review(ctx context.Context, req *rbac.SysAccessRequest, grant *rbac.SysAssetGrant, bind func(ctx context.Context) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if grant != nil {
+			if err := tx.Create(grant).Error; err != nil {
+				return fmt.Errorf("创建临时授权失败: %w", err)
+			}
+			req.GrantID = grant.ID
+		}
+		// 条件更新会锁住该行，并发的另一次审批等待提交后匹配不到待审批状态
+		result := tx.Model(req).
+			Where("status = ?", rbac.AccessStatusPending).
+			Select("status", "reviewer_id", "reviewer_name", "review_comment", "reviewed_at", "grant_id", "start_at", "expires_at").
+			Updates(req)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return rbac.ErrAccessRequestReviewed
+		}
+		if bind != nil {
+			return bind(ctx)
+		}
+		return nil
+	})
+}
+
+// GetByID 根据ID获取访问申请
+func (r *accessRequestRepo) GetByID(ctx context.Context, id uint) (*rbac.SysAccessRequest, error) {
+	var req rbac.SysAccessRequest
+	if err := r.db.WithContext(ctx).First(&req, id).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// List 分页查询访问申请
+func (r *accessRequestRepo) List(ctx context.Context, req *rbac.AccessRequestListReq, userID uint) ([]*rbac.SysAccessRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&rbac.SysAccessRequest{})
+	switch req.Scope {
+	case "all":
+	case "todo":
+		query = query.Where("status = ? AND JSON_CONTAINS(approver_ids, ?)", rbac.AccessStatusPending, fmt.Sprintf("%d", userID))
+	default:
+		query = query.Where("user_id = ?", userID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.ResourceType != "" {
+		query = query.Where("resource_type = ?", req.ResourceType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var list []*rbac.SysAccessRequest
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&list).Error
+	return list, total, err
+}
+
+// ListDue 获取已到期但仍处于批准状态的申请
+func (r *accessRequestRepo) ListDue(ctx context.Context, now time.Time) ([]*rbac.SysAccessRequest, error) {
+	var list []*rbac.SysAccessRequest
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", rbac.AccessStatusApproved, now).
+		Find(&list).Error
+	return list, err
+}
+
+// AddEvent 记录访问申请流转事件
+func (r *accessRequestRepo) AddEvent(ctx context.Context, event *rbac.SysAccessRequestEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// ListEvents 获取访问申请的流转记录
+func (r *accessRequestRepo) ListEvents(ctx context.Context, requestID uint) ([]*rbac.SysAccessRequestEvent, error) {
+	var events []*rbac.SysAccessRequestEvent
+	err := r.db.WithContext(ctx).
+		Where("request_id = ?", requestID).
+		Order("id ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// ResolveApprovers 解析审批人：资产分组审批人 + 申请人部门（含上级部门）审批人，均未配置时回退到管理员
+func (r *accessRequestRepo) ResolveApprovers(ctx context.Context, assetGroupID, userID uint) ([]uint, error) {
+	var approverIDs []uint
+
+	if assetGroupID > 0 {
+		var ids []uint
+		if err := r.db.WithContext(ctx).
+			Model(&rbac.SysAccessApprover{}).
+			Where("scope_type = ? AND scope_id = ?", rbac.ApproverScopeAssetGroup, assetGroupID).
+			Pluck("user_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		approverIDs = append(approverIDs, ids...)
+	}
+
+	deptIDs, err := departmentChain(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(deptIDs) > 0 {
+		var ids []uint
+		if err := r.db.WithContext(ctx).
+			Model(&rbac.SysAccessApprover{}).
+			Where("scope_type = ? AND scope_id IN ?", rbac.ApproverScopeDepartment, deptIDs).
+			Pluck("user_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		approverIDs = append(approverIDs, ids...)
+	}
+
+	if len(approverIDs) == 0 {
+		if err := r.db.WithContext(ctx).
+			Table("sys_user_role AS ur").
+			Joins("JOIN sys_role AS r ON ur.role_id = r.id").
+			Where("r.code = ?", "admin").
+			Pluck("ur.user_id", &approverIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[uint]bool)
+	result := make([]uint, 0, len(approverIDs))
+	for _, id := range approverIDs {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// SetApprovers 设置审批人（覆盖原有配置）
+func (r *accessRequestRepo) SetApprovers(ctx context.Context, scopeType string, scopeID uint, userIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scope_type = ? AND scope_id = ?", scopeType, scopeID).
+			Delete(&rbac.SysAccessApprover{}).Error; err != nil {
+			return err
+		}
+		seen := make(map[uint]bool)
+		for _, userID := range userIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			approver := &rbac.SysAccessApprover{ScopeType: scopeType, ScopeID: scopeID, UserID: userID}
+			if err := tx.Create(approver).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListApprovers 获取审批人配置
+func (r *accessRequestRepo) ListApprovers(ctx context.Context, scopeType string, scopeID uint) ([]*rbac.SysAccessApprover, error) {
+	var approvers []*rbac.SysAccessApprover
+	err := r.db.WithContext(ctx).
+		Table("sys_access_approver AS a").
+		Select("a.*, u.username, u.real_name").
+		Joins("LEFT JOIN sys_user AS u ON u.id = a.user_id").
+		Where("a.scope_type = ? AND a.scope_id = ?", scopeType, scopeID).
+		Order("a.id ASC").
+		Scan(&approvers).Error
+	return approvers, err
+}
+
+// IsAdmin 检查用户是否拥有管理员角色
+func (r *accessRequestRepo) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	return isAdminUser(ctx, r.db, userID)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+type apiTokenRepo struct {
+	db *gorm.DB
+}
+
+// NewAPITokenRepo 创建 API 令牌仓储
+func NewAPITokenRepo(db *gorm.DB) rbac.APITokenRepo {
+	return &apiTokenRepo{db: db}
+}
+
+// Create 创建令牌
+func (r *apiTokenRepo) Create(ctx context.Context, token *rbac.SysAPIToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByID 根据ID获取令牌
+func (r *apiTokenRepo) GetByID(ctx context.Context, id uint) (*rbac.SysAPIToken, error) {
+	var token rbac.SysAPIToken
+	if err := r.db.WithContext(ctx).First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetByHash 根据摘要获取令牌
+func (r *apiTokenRepo) GetByHash(ctx context.Context, hash string) (*rbac.SysAPIToken, error) {
+	var token rbac.SysAPIToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser 获取用户或服务账号的令牌
+func (r *apiTokenRepo) ListByUser(ctx context.Context, userID uint) ([]*rbac.SysAPIToken, error) {
+	var tokens []*rbac.SysAPIToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Delete 吊销令牌
+func (r *apiTokenRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&rbac.SysAPIToken{}, id).Error
+}
+
+// DeleteByUser 吊销用户或服务账号的全部令牌
+func (r *apiTokenRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&rbac.SysAPIToken{}).Error
+}
+
+// Touch 记录最近使用时间和IP
+func (r *apiTokenRepo) Touch(ctx context.Context, id uint, usedAt time.Time, ip string) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysAPIToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,485 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+// roleSourceRow 角色资产权限来源查询行
+type roleSourceRow struct {
+	ID           uint
+	RoleID       uint
+	RoleName     string
+	AssetGroupID uint
+	HostIDs      rbac.UintArray
+	Permissions  uint
+	StartAt      *time.Time
+	ExpiresAt    *time.Time
+}
+
+// departmentChain 获取用户所在部门及其全部上级部门ID
+func departmentChain(ctx context.Context, db *gorm.DB, userID uint) ([]uint, error) {
+	var deptID uint
+	if err := db.WithContext(ctx).
+		Table("sys_user").
+		Select("department_id").
+		Where("id = ?", userID).
+		Scan(&deptID).Error; err != nil {
+		return nil, err
+	}
+
+	var chain []uint
+	visited := make(map[uint]bool)
+	for deptID != 0 && !visited[deptID] {
+		visited[deptID] = true
+		chain = append(chain, deptID)
+		var parentID uint
+		if err := db.WithContext(ctx).
+			Table("sys_department").
+			Select("parent_id").
+			Where("id = ? AND deleted_at IS NULL", deptID).
+			Scan(&parentID).Error; err != nil {
+			return nil, err
+		}
+		deptID = parentID
+	}
+	return chain, nil
+}
+
+// loadUserSources 加载作用于用户的全部权限来源（含未生效的）
+// groupIDs 非空时只加载这些资产分组上的来源
+func (r *assetPermissionRepo) loadUserSources(ctx context.Context, userID uint, groupIDs ...uint) ([]*rbac.AssetPermissionSource, error) {
+	now := time.Now()
+	var sources []*rbac.AssetPermissionSource
+
+	// 角色资产权限
+	var roleRows []roleSourceRow
+	roleQuery := r.db.WithContext(ctx).
+		Table("sys_role_asset_permission AS p").
+		Select("p.id, p.role_id, r.name AS role_name, p.asset_group_id, p.host_ids, p.permissions, p.start_at, p.expires_at").
+		Joins("JOIN sys_user_role AS ur ON p.role_id = ur.role_id").
+		Joins("JOIN sys_role AS r ON r.id = p.role_id").
+		Where("ur.user_id = ? AND p.deleted_at IS NULL", userID)
+	if len(groupIDs) > 0 {
+		roleQuery = roleQuery.Where("p.asset_group_id IN ?", groupIDs)
+	}
+	if err := roleQuery.Scan(&roleRows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range roleRows {
+		sources = append(sources, newPermissionSource(&rbac.AssetPermissionSource{
+			Kind:         rbac.PermissionSourceRole,
+			ID:           row.ID,
+			SubjectType:  rbac.GrantSubjectRole,
+			SubjectID:    row.RoleID,
+			SubjectName:  row.RoleName,
+			Effect:       rbac.GrantEffectAllow,
+			AssetGroupID: row.AssetGroupID,
+			HostIDs:      row.HostIDs,
+			Permissions:  row.Permissions,
+			StartAt:      row.StartAt,
+			ExpiresAt:    row.ExpiresAt,
+		}, now))
+	}
+
+	// 资产授权：用户本人、所在部门（含上级部门）、所属角色
+	deptIDs, err := departmentChain(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	var roleIDs []uint
+	if err := r.db.WithContext(ctx).
+		Table("sys_user_role").
+		Where("user_id = ?", userID).
+		Pluck("role_id", &roleIDs).Error; err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"(subject_type = ? AND subject_id = ?)"}
+	args := []interface{}{rbac.GrantSubjectUser, userID}
+	if len(deptIDs) > 0 {
+		conditions = append(conditions, "(subject_type = ? AND subject_id IN ?)")
+		args = append(args, rbac.GrantSubjectDepartment, deptIDs)
+	}
+	if len(roleIDs) > 0 {
+		conditions = append(conditions, "(subject_type = ? AND subject_id IN ?)")
+		args = append(args, rbac.GrantSubjectRole, roleIDs)
+	}
+
+	var grants []*rbac.SysAssetGrant
+	grantQuery := r.db.WithContext(ctx).Where(strings.Join(conditions, " OR "), args...)
+	if len(groupIDs) > 0 {
+		grantQuery = grantQuery.Where("asset_group_id IN ?", groupIDs)
+	}
+	if err := grantQuery.Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	names := r.subjectNames(ctx, grants)
+	for _, g := range grants {
+		sources = append(sources, newPermissionSource(&rbac.AssetPermissionSource{
+			Kind:         rbac.PermissionSourceGrant,
+			ID:           g.ID,
+			SubjectType:  g.SubjectType,
+			SubjectID:    g.SubjectID,
+			SubjectName:  names[subjectKey(g.SubjectType, g.SubjectID)],
+			Effect:       g.Effect,
+			AssetGroupID: g.AssetGroupID,
+			HostIDs:      g.HostIDs,
+			Permissions:  g.Permissions,
+			StartAt:      g.StartAt,
+			ExpiresAt:    g.ExpiresAt,
+		}, now))
+	}
+
+	return sources, nil
+}
+
+// newPermissionSource 补全来源的权限名称和生效状态
+func newPermissionSource(s *rbac.AssetPermissionSource, now time.Time) *rbac.AssetPermissionSource {
+	if s.Effect == "" {
+		s.Effect = rbac.GrantEffectAllow
+	}
+	s.PermissionNames = rbac.GetAllPermissionNames(s.Permissions)
+	s.Active = rbac.IsActiveAt(s.StartAt, s.ExpiresAt, now)
+	if !s.Active {
+		if s.StartAt != nil && now.Before(*s.StartAt) {
+			s.InactiveReason = "尚未生效"
+		} else {
+			s.InactiveReason = "已过期"
+		}
+	}
+	return s
+}
+
+func subjectKey(subjectType string, subjectID uint) string {
+	return fmt.Sprintf("%s:%d", subjectType, subjectID)
+}
+
+// subjectNames 批量查询授权主体名称
+func (r *assetPermissionRepo) subjectNames(ctx context.Context, grants []*rbac.SysAssetGrant) map[string]string {
+	ids := map[string][]uint{}
+	for _, g := range grants {
+		ids[g.SubjectType] = append(ids[g.SubjectType], g.SubjectID)
+	}
+
+	type nameRow struct {
+		ID   uint
+		Name string
+	}
+	names := make(map[string]string)
+	lookup := func(subjectType, table, column string) {
+		if len(ids[subjectType]) == 0 {
+			return
+		}
+		var rows []nameRow
+		r.db.WithContext(ctx).
+			Table(table).
+			Select("id, "+column+" AS name").
+			Where("id IN ?", ids[subjectType]).
+			Scan(&rows)
+		for _, row := range rows {
+			names[subjectKey(subjectType, row.ID)] = row.Name
+		}
+	}
+	lookup(rbac.GrantSubjectUser, "sys_user", "username")
+	lookup(rbac.GrantSubjectDepartment, "sys_department", "name")
+	lookup(rbac.GrantSubjectRole, "sys_role", "name")
+	return names
+}
+
+// accessibleAssetIDs 根据权限来源计算用户可访问的资产ID
+// perHost 为 true 时按主机粒度匹配，否则只认整组授权（如数据库）
+func (r *assetPermissionRepo) accessibleAssetIDs(ctx context.Context, userID uint, table string, perHost bool) ([]uint, error) {
+	sources, err := r.loadUserSources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupSet := make(map[uint]bool)
+	var groupIDs []uint
+	for _, s := range sources {
+		if s.Active && s.Effect != rbac.GrantEffectDeny && !groupSet[s.AssetGroupID] {
+			groupSet[s.AssetGroupID] = true
+			groupIDs = append(groupIDs, s.AssetGroupID)
+		}
+	}
+	if len(groupIDs) == 0 {
+		return []uint{}, nil
+	}
+
+	type assetRow struct {
+		ID      uint
+		GroupID uint
+	}
+	var assets []assetRow
+	if err := r.db.WithContext(ctx).
+		Table(table).
+		Select("id, group_id").
+		Where("group_id IN ? AND deleted_at IS NULL", groupIDs).
+		Scan(&assets).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(assets))
+	for _, a := range assets {
+		assetID := uint(0)
+		if perHost {
+			assetID = a.ID
+		}
+		if _, _, effective := rbac.EvaluateAssetPermissions(sources, a.GroupID, assetID); effective > 0 {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids, nil
+}
+
+// HasAnyPermission 检查用户是否在任意资产上拥有指定操作权限（只看生效中的允许来源）
+func (r *assetPermissionRepo) HasAnyPermission(ctx context.Context, userID uint, operation uint) (bool, error) {
+	isAdmin, err := r.isAdmin(ctx, userID)
+	if err != nil || isAdmin {
+		return isAdmin, err
+	}
+
+	sources, err := r.loadUserSources(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sources {
+		if s.Active && s.Effect != rbac.GrantEffectDeny && s.Permissions&operation != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateGrant 创建资产授权
+func (r *assetPermissionRepo) CreateGrant(ctx context.Context, grant *rbac.SysAssetGrant) error {
+	return r.db.WithContext(ctx).Create(grant).Error
+}
+
+// UpdateGrant 更新资产授权
+func (r *assetPermissionRepo) UpdateGrant(ctx context.Context, grant *rbac.SysAssetGrant) error {
+	return r.db.WithContext(ctx).Select("*").Omit("created_at", "created_by").Save(grant).Error
+}
+
+// DeleteGrant 删除资产授权
+func (r *assetPermissionRepo) DeleteGrant(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&rbac.SysAssetGrant{}, id).Error
+}
+
+// GetGrantByID 根据ID获取资产授权
+func (r *assetPermissionRepo) GetGrantByID(ctx context.Context, id uint) (*rbac.AssetGrantInfo, error) {
+	var grant rbac.SysAssetGrant
+	if err := r.db.WithContext(ctx).First(&grant, id).Error; err != nil {
+		return nil, err
+	}
+	infos := r.toGrantInfos(ctx, []*rbac.SysAssetGrant{&grant})
+	return infos[0], nil
+}
+
+// ListGrants 分页查询资产授权
+func (r *assetPermissionRepo) ListGrants(ctx context.Context, req *rbac.AssetGrantListReq) ([]*rbac.AssetGrantInfo, int64, error) {
+	query := r.db.WithContext(ctx).Model(&rbac.SysAssetGrant{})
+	if req.SubjectType != "" {
+		query = query.Where("subject_type = ?", req.SubjectType)
+	}
+	if req.SubjectID > 0 {
+		query = query.Where("subject_id = ?", req.SubjectID)
+	}
+	if req.AssetGroupID > 0 {
+		query = query.Where("asset_group_id = ?", req.AssetGroupID)
+	}
+	if req.Effect != "" {
+		query = query.Where("effect = ?", req.Effect)
+	}
+	if req.Active != nil {
+		now := time.Now()
+		if *req.Active {
+			query = query.Where("(start_at IS NULL OR start_at <= ?) AND (expires_at IS NULL OR expires_at > ?)", now, now)
+		} else {
+			query = query.Where("(start_at > ? OR expires_at <= ?)", now, now)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var grants []*rbac.SysAssetGrant
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&grants).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return r.toGrantInfos(ctx, grants), total, nil
+}
+
+// toGrantInfos 组装资产授权展示信息
+func (r *assetPermissionRepo) toGrantInfos(ctx context.Context, grants []*rbac.SysAssetGrant) []*rbac.AssetGrantInfo {
+	names := r.subjectNames(ctx, grants)
+
+	var groupIDs []uint
+	for _, g := range grants {
+		groupIDs = append(groupIDs, g.AssetGroupID)
+	}
+	groupNames := make(map[uint]string)
+	if len(groupIDs) > 0 {
+		var rows []struct {
+			ID   uint
+			Name string
+		}
+		r.db.WithContext(ctx).Table("asset_group").Select("id, name").Where("id IN ?", groupIDs).Scan(&rows)
+		for _, row := range rows {
+			groupNames[row.ID] = row.Name
+		}
+	}
+
+	now := time.Now()
+	infos := make([]*rbac.AssetGrantInfo, 0, len(grants))
+	for _, g := range grants {
+		infos = append(infos, &rbac.AssetGrantInfo{
+			SysAssetGrant:   *g,
+			SubjectName:     names[subjectKey(g.SubjectType, g.SubjectID)],
+			AssetGroupName:  groupNames[g.AssetGroupID],
+			PermissionNames: rbac.GetAllPermissionNames(g.Permissions),
+			Active:          rbac.IsActiveAt(g.StartAt, g.ExpiresAt, now),
+		})
+	}
+	return infos
+}
+
+// ExplainHostPermissions 解释用户对指定主机的有效权限及其来源
+func (r *assetPermissionRepo) ExplainHostPermissions(ctx context.Context, userID, hostID uint) (*rbac.AssetPermissionExplainVO, error) {
+	var user rbac.SysUser
+	if err := r.db.WithContext(ctx).Select("id, username").First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("用户不存在")
+	}
+
+	var host struct {
+		ID      uint
+		Name    string
+		GroupID uint
+	}
+	if err := r.db.WithContext(ctx).
+		Table("hosts").
+		Select("id, name, group_id").
+		Where("id = ? AND deleted_at IS NULL", hostID).
+		Scan(&host).Error; err != nil {
+		return nil, err
+	}
+	if host.ID == 0 {
+		return nil, fmt.Errorf("主机不存在")
+	}
+
+	vo := &rbac.AssetPermissionExplainVO{
+		UserID:       user.ID,
+		Username:     user.Username,
+		HostID:       host.ID,
+		HostName:     host.Name,
+		AssetGroupID: host.GroupID,
+		Sources:      []*rbac.AssetPermissionSource{},
+		Reasons:      []string{},
+	}
+
+	isAdmin, err := r.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		vo.IsAdmin = true
+		vo.Allowed = rbac.PermissionAll
+		vo.Effective = rbac.PermissionAll
+		vo.EffectiveNames = rbac.GetAllPermissionNames(rbac.PermissionAll)
+		vo.Reasons = append(vo.Reasons, "用户拥有管理员角色，忽略资产授权，拥有全部权限")
+		return vo, nil
+	}
+
+	sources, err := r.loadUserSources(ctx, userID, host.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sources {
+		if s.Matches(host.GroupID, host.ID) {
+			vo.Sources = append(vo.Sources, s)
+		}
+	}
+
+	vo.Allowed, vo.Denied, vo.Effective = rbac.EvaluateAssetPermissions(vo.Sources, host.GroupID, host.ID)
+	vo.EffectiveNames = rbac.GetAllPermissionNames(vo.Effective)
+
+	for _, s := range vo.Sources {
+		action := "允许"
+		if s.Effect == rbac.GrantEffectDeny {
+			action = "拒绝"
+		}
+		reason := fmt.Sprintf("%s[%s] %s: %s", subjectLabel(s.SubjectType), s.SubjectName, action, strings.Join(s.PermissionNames, ","))
+		if !s.Active {
+			reason += fmt.Sprintf("（%s，不计入）", s.InactiveReason)
+		}
+		vo.Reasons = append(vo.Reasons, reason)
+	}
+	if vo.Denied&vo.Allowed > 0 {
+		vo.Reasons = append(vo.Reasons, fmt.Sprintf("显式拒绝覆盖了允许的权限: %s", strings.Join(rbac.GetAllPermissionNames(vo.Denied&vo.Allowed), ",")))
+	}
+	if vo.Effective == 0 {
+		vo.Reasons = append(vo.Reasons, "没有生效的允许授权，无法访问该主机")
+	}
+
+	return vo, nil
+}
+
+func subjectLabel(subjectType string) string {
+	switch subjectType {
+	case rbac.GrantSubjectUser:
+		return "用户"
+	case rbac.GrantSubjectDepartment:
+		return "部门"
+	default:
+		return "角色"
+	}
+}
+
+// SweepExpired 清理已过期的角色资产权限和资产授权
+func (r *assetPermissionRepo) SweepExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Delete(&rbac.SysRoleAssetPermission{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	swept := result.RowsAffected
+
+	result = r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Delete(&rbac.SysAssetGrant{})
+	if result.Error != nil {
+		return swept, result.Error
+	}
+	return swept + result.RowsAffected, nil
+}
@@ -21,6 +21,7 @@ package rbac
 
 import (
 	"context"
+	"time"
 
 	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"gorm.io/gorm"
@@ -46,14 +47,14 @@ func (r *assetPermissionRepo) CreateBatch(ctx context.Context, roleID, assetGrou
 	permission := &rbac.SysRoleAssetPermission{
 		RoleID:       roleID,
 		AssetGroupID: assetGroupID,
-		HostIDs:      hostIDs, // 直接存储主机ID数组
-		Permissions: rbac.PermissionView, // 默认权限
+		HostIDs:      hostIDs,             // 直接存储主机ID数组
+		Permissions:  rbac.PermissionView, // 默认权限
 	}
 	return r.db.WithContext(ctx).Create(permission).Error
 }
 
 // CreateBatchWithPermissions 批量创建资产权限（支持指定操作权限）
-func (r *assetPermissionRepo) CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error {
+func (r *assetPermissionRepo) CreateBatchWithPermissions(ctx context.Context, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error {
 	// 先硬删除该角色对该资产分组的所有现有权限（包括已软删除的）
 	if err := r.db.WithContext(ctx).
 		Where("role_id = ? AND asset_group_id = ?", roleID, assetGroupID).
@@ -71,7 +72,9 @@ func (r *assetPermissionRepo) CreateBatchWithPermissions(ctx context.Context, ro
 		RoleID:       roleID,
 		AssetGroupID: assetGroupID,
 		HostIDs:      hostIDs, // 直接存储主机ID数组
-		Permissions: permissions,
+		Permissions:  permissions,
+		StartAt:      startAt,
+		ExpiresAt:    expiresAt,
 	}
 
 	return r.db.WithContext(ctx).Create(permission).Error
@@ -138,19 +141,21 @@ func (r *assetPermissionRepo) GetDetailByID(ctx context.Context, id uint) (*rbac
 	hostIDs := []uint(permission.HostIDs)
 
 	return &rbac.AssetPermissionDetailVO{
-		ID:            permission.ID,
-		RoleID:        permission.RoleID,
-		RoleName:      role.Name,
-		AssetGroupID:  permission.AssetGroupID,
+		ID:             permission.ID,
+		RoleID:         permission.RoleID,
+		RoleName:       role.Name,
+		AssetGroupID:   permission.AssetGroupID,
 		AssetGroupName: group.Name,
-		HostIDs:       hostIDs,
-		Permissions:   permission.Permissions,
-		CreatedAt:     permission.CreatedAt,
+		HostIDs:        hostIDs,
+		Permissions:    permission.Permissions,
+		StartAt:        permission.StartAt,
+		ExpiresAt:      permission.ExpiresAt,
+		CreatedAt:      permission.CreatedAt,
 	}, nil
 }
 
 // UpdateAssetPermission 更新权限配置（支持修改角色、分组、主机、权限）
-func (r *assetPermissionRepo) UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint) error {
+func (r *assetPermissionRepo) UpdateAssetPermission(ctx context.Context, id uint, roleID, assetGroupID uint, hostIDs []uint, permissions uint, startAt, expiresAt *time.Time) error {
 	// 首先硬删除该权限的旧记录（包括软删除的）
 	if err := r.db.WithContext(ctx).Model(&rbac.SysRoleAssetPermission{}).
 		Where("id = ?", id).
@@ -168,7 +173,9 @@ func (r *assetPermissionRepo) UpdateAssetPermission(ctx context.Context, id uint
 		RoleID:       roleID,
 		AssetGroupID: assetGroupID,
 		HostIDs:      hostIDs, // 直接存储主机ID数组
-		Permissions: permissions,
+		Permissions:  permissions,
+		StartAt:      startAt,
+		ExpiresAt:    expiresAt,
 	}
 
 	return r.db.WithContext(ctx).Create(permission).Error
@@ -189,6 +196,8 @@ func (r *assetPermissionRepo) GetByRoleID(ctx context.Context, roleID uint) ([]*
 			g.name AS asset_group_name,
 			p.host_ids,
 			p.permissions,
+			p.start_at,
+			p.expires_at,
 			p.created_at
 		`).
 		Joins("LEFT JOIN sys_role AS r ON p.role_id = r.id").
@@ -215,6 +224,8 @@ func (r *assetPermissionRepo) GetByAssetGroupID(ctx context.Context, assetGroupI
 			g.name AS asset_group_name,
 			p.host_ids,
 			p.permissions,
+			p.start_at,
+			p.expires_at,
 			p.created_at
 		`).
 		Joins("LEFT JOIN sys_role AS r ON p.role_id = r.id").
@@ -242,6 +253,8 @@ func (r *assetPermissionRepo) List(ctx context.Context, page, pageSize int, role
 			g.name AS asset_group_name,
 			p.host_ids,
 			p.permissions,
+			p.start_at,
+			p.expires_at,
 			p.created_at
 		`).
 		Joins("LEFT JOIN sys_role AS r ON p.role_id = r.id").
@@ -272,69 +285,22 @@ func (r *assetPermissionRepo) List(ctx context.Context, page, pageSize int, role
 
 // CheckHostPermission 检查用户是否有访问指定主机的权限
 func (r *assetPermissionRepo) CheckHostPermission(ctx context.Context, userID, hostID uint) (bool, error) {
-	// 首先检查用户是否是管理员
-	var adminCount int64
-	err := r.db.WithContext(ctx).
-		Table("sys_user_role AS ur").
-		Joins("JOIN sys_role AS r ON ur.role_id = r.id").
-		Where("ur.user_id = ? AND r.code = ?", userID, "admin").
-		Count(&adminCount).Error
-
-	if err != nil {
-		return false, err
-	}
-
-	// 管理员拥有所有权限
-	if adminCount > 0 {
-		return true, nil
-	}
-
-	// 获取主机所属的资产分组ID
-	var groupID uint
-	err = r.db.WithContext(ctx).
-		Table("hosts").
-		Select("group_id").
-		Where("id = ?", hostID).
-		Scan(&groupID).Error
-
+	permissions, err := r.GetUserHostPermissions(ctx, userID, hostID)
 	if err != nil {
 		return false, err
 	}
-
-	// 检查用户的角色是否有权限访问该主机
-	// 1. 检查是否有整个分组的权限（host_ids 为空或 NULL）
-	// 2. 检查是否有特定主机的权限（host_ids 包含该主机ID）
-	var permCount int64
-	err = r.db.WithContext(ctx).
-		Table("sys_role_asset_permission AS p").
-		Joins("JOIN sys_user_role AS ur ON p.role_id = ur.role_id").
-		Where("ur.user_id = ? AND p.asset_group_id = ? AND p.deleted_at IS NULL", userID, groupID).
-		Where("JSON_LENGTH(COALESCE(p.host_ids, JSON_ARRAY())) = 0 OR JSON_CONTAINS(p.host_ids, CAST(? AS JSON))", hostID).
-		Count(&permCount).Error
-
-	if err != nil {
-		return false, err
-	}
-
-	return permCount > 0, nil
+	return permissions > 0, nil
 }
 
 // GetUserAccessibleHostIDs 获取用户有权限访问的所有主机ID列表
 func (r *assetPermissionRepo) GetUserAccessibleHostIDs(ctx context.Context, userID uint) ([]uint, error) {
-	// 首先检查用户是否是管理员
-	var adminCount int64
-	err := r.db.WithContext(ctx).
-		Table("sys_user_role AS ur").
-		Joins("JOIN sys_role AS r ON ur.role_id = r.id").
-		Where("ur.user_id = ? AND r.code = ?", userID, "admin").
-		Count(&adminCount).Error
-
+	isAdmin, err := r.isAdmin(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 管理员可以访问所有主机
-	if adminCount > 0 {
+	if isAdmin {
 		var allHostIDs []uint
 		err = r.db.WithContext(ctx).
 			Table("hosts").
@@ -343,46 +309,29 @@ func (r *assetPermissionRepo) GetUserAccessibleHostIDs(ctx context.Context, user
 		return allHostIDs, err
 	}
 
-	// 获取用户有权限的主机ID列表
-	// 1. 如果 host_ids 为空，表示有整个分组的权限
-	// 2. 如果 host_ids 包含主机ID，表示有特定主机的权限
-	var hostIDs []uint
-
-	// 通过原生SQL查询，处理 JSON 字段
-	err = r.db.WithContext(ctx).Raw(`
-		SELECT DISTINCT h.id
-		FROM hosts AS h
-		JOIN sys_role_asset_permission AS p ON p.asset_group_id = h.group_id
-		JOIN sys_user_role AS ur ON p.role_id = ur.role_id
-		WHERE ur.user_id = ?
-		AND h.deleted_at IS NULL
-		AND p.deleted_at IS NULL
-		AND (
-			JSON_LENGTH(COALESCE(p.host_ids, JSON_ARRAY())) = 0
-			OR JSON_CONTAINS(p.host_ids, CAST(h.id AS JSON))
-		)
-	`, userID).Scan(&hostIDs).Error
-
-	return hostIDs, err
+	return r.accessibleAssetIDs(ctx, userID, "hosts", true)
 }
 
 // CheckHostOperationPermission 检查用户是否有对指定主机的特定操作权限
 func (r *assetPermissionRepo) CheckHostOperationPermission(ctx context.Context, userID, hostID uint, operation uint) (bool, error) {
-	// 首先检查用户是否是管理员
-	var adminCount int64
-	err := r.db.WithContext(ctx).
-		Table("sys_user_role AS ur").
-		Joins("JOIN sys_role AS r ON ur.role_id = r.id").
-		Where("ur.user_id = ? AND r.code = ?", userID, "admin").
-		Count(&adminCount).Error
-
+	permissions, err := r.GetUserHostPermissions(ctx, userID, hostID)
 	if err != nil {
 		return false, err
 	}
+	return (permissions & operation) > 0, nil
+}
+
+// GetUserHostPermissions 获取用户对指定主机的所有操作权限
+// 有效权限 = 生效中的允许授权（角色、用户、部门）之并集 - 生效中的拒绝授权之并集
+func (r *assetPermissionRepo) GetUserHostPermissions(ctx context.Context, userID, hostID uint) (uint, error) {
+	isAdmin, err := r.isAdmin(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
 
 	// 管理员拥有所有权限
-	if adminCount > 0 {
-		return true, nil
+	if isAdmin {
+		return rbac.PermissionAll, nil
 	}
 
 	// 获取主机所属的资产分组ID
@@ -392,68 +341,89 @@ func (r *assetPermissionRepo) CheckHostOperationPermission(ctx context.Context,
 		Select("group_id").
 		Where("id = ?", hostID).
 		Scan(&groupID).Error
-
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	// 检查用户的角色是否有该操作权限
-	// host_ids 为空表示有整个分组的权限，host_ids 包含该主机表示有特定主机的权限
-	var permCount int64
-	err = r.db.WithContext(ctx).
-		Table("sys_role_asset_permission AS p").
-		Joins("JOIN sys_user_role AS ur ON p.role_id = ur.role_id").
-		Where("ur.user_id = ? AND p.asset_group_id = ? AND p.deleted_at IS NULL", userID, groupID).
-		Where("(JSON_LENGTH(COALESCE(p.host_ids, JSON_ARRAY())) = 0 OR JSON_CONTAINS(p.host_ids, CAST(? AS JSON))) AND (p.permissions & ?) > 0", hostID, operation).
-		Count(&permCount).Error
-
+	sources, err := r.loadUserSources(ctx, userID, groupID)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 
-	return permCount > 0, nil
+	_, _, effective := rbac.EvaluateAssetPermissions(sources, groupID, hostID)
+	return effective, nil
 }
 
-// GetUserHostPermissions 获取用户对指定主机的所有操作权限
-func (r *assetPermissionRepo) GetUserHostPermissions(ctx context.Context, userID, hostID uint) (uint, error) {
-	// 首先检查用户是否是管理员
+// isAdmin 检查用户是否拥有管理员角色
+func (r *assetPermissionRepo) isAdmin(ctx context.Context, userID uint) (bool, error) {
+	return isAdminUser(ctx, r.db, userID)
+}
+
+// isAdminUser 检查用户是否拥有管理员角色
+func isAdminUser(ctx context.Context, db *gorm.DB, userID uint) (bool, error) {
 	var adminCount int64
-	err := r.db.WithContext(ctx).
+	err := db.WithContext(ctx).
 		Table("sys_user_role AS ur").
 		Joins("JOIN sys_role AS r ON ur.role_id = r.id").
 		Where("ur.user_id = ? AND r.code = ?", userID, "admin").
 		Count(&adminCount).Error
+	return adminCount > 0, err
+}
 
+// GetUserDatabasePermissions 获取用户对指定数据库的所有操作权限
+// 数据库资产按所属资产分组授权，仅整组授权（host_ids 为空）的配置对数据库生效
+func (r *assetPermissionRepo) GetUserDatabasePermissions(ctx context.Context, userID, databaseID uint) (uint, error) {
+	isAdmin, err := r.isAdmin(ctx, userID)
 	if err != nil {
 		return 0, err
 	}
-
-	// 管理员拥有所有权限
-	if adminCount > 0 {
+	if isAdmin {
 		return rbac.PermissionAll, nil
 	}
 
-	// 获取主机所属的资产分组ID
 	var groupID uint
 	err = r.db.WithContext(ctx).
-		Table("hosts").
+		Table("asset_databases").
 		Select("group_id").
-		Where("id = ?", hostID).
+		Where("id = ? AND deleted_at IS NULL", databaseID).
 		Scan(&groupID).Error
+	if err != nil || groupID == 0 {
+		return 0, err
+	}
 
+	sources, err := r.loadUserSources(ctx, userID, groupID)
 	if err != nil {
 		return 0, err
 	}
 
-	// 查询用户对该主机的所有权限（通过OR操作组合权限）
-	var permissions uint
-	err = r.db.WithContext(ctx).Raw(`
-		SELECT COALESCE(BIT_OR(p.permissions), 0) as permissions
-		FROM sys_role_asset_permission AS p
-		JOIN sys_user_role AS ur ON p.role_id = ur.role_id
-		WHERE ur.user_id = ? AND p.asset_group_id = ? AND p.deleted_at IS NULL
-		AND (JSON_LENGTH(COALESCE(p.host_ids, JSON_ARRAY())) = 0 OR JSON_CONTAINS(p.host_ids, CAST(? AS JSON)))
-	`, userID, groupID, hostID).Scan(&permissions).Error
+	_, _, effective := rbac.EvaluateAssetPermissions(sources, groupID, 0)
+	return effective, nil
+}
 
-	return permissions, err
+// CheckDatabaseOperationPermission 检查用户是否有对指定数据库的特定操作权限
+func (r *assetPermissionRepo) CheckDatabaseOperationPermission(ctx context.Context, userID, databaseID uint, operation uint) (bool, error) {
+	permissions, err := r.GetUserDatabasePermissions(ctx, userID, databaseID)
+	if err != nil {
+		return false, err
+	}
+	return (permissions & operation) > 0, nil
+}
+
+// GetUserAccessibleDatabaseIDs 获取用户有权限访问的所有数据库ID列表
+func (r *assetPermissionRepo) GetUserAccessibleDatabaseIDs(ctx context.Context, userID uint) ([]uint, error) {
+	isAdmin, err := r.isAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAdmin {
+		var databaseIDs []uint
+		err = r.db.WithContext(ctx).
+			Table("asset_databases").
+			Where("deleted_at IS NULL").
+			Pluck("id", &databaseIDs).Error
+		return databaseIDs, err
+	}
+
+	return r.accessibleAssetIDs(ctx, userID, "asset_databases", false)
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+type externalIdentityRepo struct {
+	db *gorm.DB
+}
+
+// NewExternalIdentityRepo 创建外部目录用户映射仓储
+func NewExternalIdentityRepo(db *gorm.DB) rbac.ExternalIdentityRepo {
+	return &externalIdentityRepo{db: db}
+}
+
+// ListBySource 获取指定来源的全部用户
+func (r *externalIdentityRepo) ListBySource(ctx context.Context, source string) ([]*rbac.SysUser, error) {
+	var users []*rbac.SysUser
+	err := r.db.WithContext(ctx).Where("source = ?", source).Find(&users).Error
+	return users, err
+}
+
+// SyncProfile 同步目录中的用户资料，空值也会写入以清除目录中已删除的属性
+func (r *externalIdentityRepo) SyncProfile(ctx context.Context, userID uint, profile *rbac.SysUser) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Select("real_name", "email", "phone", "department_id", "external_id").
+		Updates(profile).Error
+}
+
+// SetStatus 设置用户状态
+func (r *externalIdentityRepo) SetStatus(ctx context.Context, userID uint, status int) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Update("status", status).Error
+}
+
+// GetRoleIDsByCodes 根据角色编码获取角色ID，不存在的编码会被忽略
+func (r *externalIdentityRepo) GetRoleIDsByCodes(ctx context.Context, codes []string) ([]uint, error) {
+	var ids []uint
+	if len(codes) == 0 {
+		return ids, nil
+	}
+	err := r.db.WithContext(ctx).Model(&rbac.SysRole{}).
+		Where("code IN ?", codes).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetDepartmentIDByCode 根据部门编码获取部门ID
+func (r *externalIdentityRepo) GetDepartmentIDByCode(ctx context.Context, code string) (uint, error) {
+	var dept rbac.SysDepartment
+	if err := r.db.WithContext(ctx).Select("id").Where("code = ?", code).First(&dept).Error; err != nil {
+		return 0, err
+	}
+	return dept.ID, nil
+}
+
+// GetUserByEmail 根据邮箱获取用户，存在多个同邮箱用户时视为无法关联
+func (r *externalIdentityRepo) GetUserByEmail(ctx context.Context, email string) (*rbac.SysUser, error) {
+	var users []*rbac.SysUser
+	if err := r.db.WithContext(ctx).Where("email = ?", email).Limit(2).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	if len(users) != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return users[0], nil
+}
+
+// GetIdentityUserID 获取外部身份绑定的用户ID
+func (r *externalIdentityRepo) GetIdentityUserID(ctx context.Context, provider, subject string) (uint, error) {
+	var identity rbac.SysUserIdentity
+	err := r.db.WithContext(ctx).
+		Joins("JOIN sys_user ON sys_user.id = sys_user_identity.user_id AND sys_user.deleted_at IS NULL").
+		Where("sys_user_identity.provider = ? AND sys_user_identity.subject = ?", provider, subject).
+		First(&identity).Error
+	if err != nil {
+		return 0, err
+	}
+	return identity.UserID, nil
+}
+
+// CreateIdentity 绑定外部身份，已删除用户留下的绑定会被替换
+func (r *externalIdentityRepo) CreateIdentity(ctx context.Context, identity *rbac.SysUserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("provider = ? AND subject = ?", identity.Provider, identity.Subject).
+			Delete(&rbac.SysUserIdentity{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(identity).Error
+	})
+}
+
+// TouchIdentity 更新外部身份的最后登录时间和邮箱
+func (r *externalIdentityRepo) TouchIdentity(ctx context.Context, provider, subject, email string) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUserIdentity{}).
+		Where("provider = ? AND subject = ?", provider, subject).
+		Updates(map[string]interface{}{
+			"email":         email,
+			"last_login_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+)
+
+// ldapPageSize 列出目录用户时的分页大小
+const ldapPageSize = 500
+
+type ldapDirectory struct {
+	cfg conf.LDAPConfig
+}
+
+// NewLDAPDirectory 创建 LDAP/AD 目录客户端
+func NewLDAPDirectory(cfg conf.LDAPConfig) rbac.Directory {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(uid=%s)"
+	}
+	if cfg.UserListFilter == "" {
+		cfg.UserListFilter = strings.ReplaceAll(cfg.UserFilter, "%s", "*")
+	}
+	if cfg.UsernameAttr == "" {
+		cfg.UsernameAttr = "uid"
+	}
+	if cfg.RealNameAttr == "" {
+		cfg.RealNameAttr = "cn"
+	}
+	if cfg.EmailAttr == "" {
+		cfg.EmailAttr = "mail"
+	}
+	if cfg.PhoneAttr == "" {
+		cfg.PhoneAttr = "telephoneNumber"
+	}
+	if cfg.GroupFilter == "" {
+		cfg.GroupFilter = "(member=%s)"
+	}
+	if cfg.GroupNameAttr == "" {
+		cfg.GroupNameAttr = "cn"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10
+	}
+	return &ldapDirectory{cfg: cfg}
+}
+
+// dial 建立连接并以服务账号绑定
+func (d *ldapDirectory) dial() (*ldap.Conn, error) {
+	timeout := time.Duration(d.cfg.Timeout) * time.Second
+	tlsConfig := &tls.Config{InsecureSkipVerify: d.cfg.InsecureSkipVerify}
+
+	conn, err := ldap.DialURL(d.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("服务账号绑定失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Authenticate 以服务账号搜索用户DN，再以用户身份绑定校验密码
+func (d *ldapDirectory) Authenticate(ctx context.Context, username, password string) (*rbac.DirectoryEntry, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := strings.ReplaceAll(d.cfg.UserFilter, "%s", ldap.EscapeFilter(username))
+	result, err := conn.Search(ldap.NewSearchRequest(
+		d.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, d.cfg.Timeout, false,
+		filter, d.userAttributes(), nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, rbac.ErrDirectoryUserNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, errors.New("目录中存在多个同名用户")
+	}
+
+	entry := d.toEntry(result.Entries[0])
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, rbac.ErrDirectoryInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// 以服务账号重新绑定后查询分组，普通用户通常没有读取分组的权限
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("服务账号绑定失败: %w", err)
+		}
+	}
+	if err := d.loadGroups(conn, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListUsers 分页列出目录中的全部用户及其分组
+func (d *ldapDirectory) ListUsers(ctx context.Context) ([]*rbac.DirectoryEntry, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		d.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		d.cfg.UserListFilter, d.userAttributes(), nil,
+	), ldapPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*rbac.DirectoryEntry, 0, len(result.Entries))
+	for _, e := range result.Entries {
+		entry := d.toEntry(e)
+		if entry.Username == "" {
+			continue
+		}
+		if err := d.loadGroups(conn, entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// loadGroups 查询用户所属分组，同时记录分组名称和DN以便映射时任选其一
+func (d *ldapDirectory) loadGroups(conn *ldap.Conn, entry *rbac.DirectoryEntry) error {
+	if d.cfg.GroupBaseDN == "" {
+		return nil
+	}
+
+	filter := strings.ReplaceAll(d.cfg.GroupFilter, "%s", ldap.EscapeFilter(entry.DN))
+	result, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		d.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, d.cfg.Timeout, false,
+		filter, []string{d.cfg.GroupNameAttr}, nil,
+	), ldapPageSize)
+	if err != nil {
+		return fmt.Errorf("查询LDAP分组失败: %w", err)
+	}
+
+	for _, g := range result.Entries {
+		entry.Groups = append(entry.Groups, g.DN)
+		if name := g.GetAttributeValue(d.cfg.GroupNameAttr); name != "" {
+			entry.Groups = append(entry.Groups, name)
+		}
+	}
+	return nil
+}
+
+func (d *ldapDirectory) userAttributes() []string {
+	return []string{d.cfg.UsernameAttr, d.cfg.RealNameAttr, d.cfg.EmailAttr, d.cfg.PhoneAttr}
+}
+
+func (d *ldapDirectory) toEntry(e *ldap.Entry) *rbac.DirectoryEntry {
+	return &rbac.DirectoryEntry{
+		DN:       e.DN,
+		Username: e.GetAttributeValue(d.cfg.UsernameAttr),
+		RealName: e.GetAttributeValue(d.cfg.RealNameAttr),
+		Email:    e.GetAttributeValue(d.cfg.EmailAttr),
+		Phone:    e.GetAttributeValue(d.cfg.PhoneAttr),
+	}
+}
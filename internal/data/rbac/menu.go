@@ -81,8 +81,9 @@ func (r *menuRepo) GetByID(ctx context.Context, id uint) (*rbac.SysMenu, error)
 
 func (r *menuRepo) GetTree(ctx context.Context) ([]*rbac.SysMenu, error) {
 	var menus []*rbac.SysMenu
+	// 按钮不在侧边栏显示，但需要出现在菜单树中供角色授权
 	err := r.db.WithContext(ctx).
-		Where("visible = ?", 1).
+		Where("visible = ? OR type = ?", 1, 3).
 		Order("sort ASC").
 		Find(&menus).Error
 	if err != nil {
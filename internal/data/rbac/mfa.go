@@ -0,0 +1,226 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mfaRepo struct {
+	db            *gorm.DB
+	encryptionKey []byte
+}
+
+// NewMFARepo 创建双因素认证仓储
+func NewMFARepo(db *gorm.DB) rbac.MFARepo {
+	// AES-256要求密钥长度必须是32字节（256位）
+	return &mfaRepo{
+		db:            db,
+		encryptionKey: []byte("opshub-mfa-key-32-bytes-long!!!!"),
+	}
+}
+
+// GetByUserID 获取用户的双因素认证配置，返回的密钥已解密
+func (r *mfaRepo) GetByUserID(ctx context.Context, userID uint) (*rbac.SysUserMFA, error) {
+	var mfa rbac.SysUserMFA
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	secret, err := r.decrypt(mfa.Secret)
+	if err != nil {
+		return nil, err
+	}
+	mfa.Secret = secret
+	return &mfa, nil
+}
+
+// Save 保存双因素认证配置，每个用户只保留一条
+func (r *mfaRepo) Save(ctx context.Context, mfa *rbac.SysUserMFA) error {
+	secret, err := r.encrypt(mfa.Secret)
+	if err != nil {
+		return err
+	}
+	record := *mfa
+	record.Secret = secret
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"secret", "enabled", "confirmed_at", "last_used_step", "failed_count", "locked_until", "updated_at",
+		}),
+	}).Create(&record).Error
+}
+
+// Delete 删除用户的双因素认证配置及恢复码
+func (r *mfaRepo) Delete(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&rbac.SysUserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&rbac.SysUserMFA{}).Error
+	})
+}
+
+// MarkStepUsed 记录已使用的时间步并清除失败计数，时间步不大于上次记录时返回 false
+func (r *mfaRepo) MarkStepUsed(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&rbac.SysUserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]interface{}{
+			"last_used_step": step,
+			"failed_count":   0,
+			"locked_until":   nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordFailure 失败次数加一，lockUntil 非空时同时锁定
+func (r *mfaRepo) RecordFailure(ctx context.Context, userID uint, lockUntil *time.Time) error {
+	updates := map[string]interface{}{
+		"failed_count": gorm.Expr("failed_count + 1"),
+	}
+	if lockUntil != nil {
+		updates["failed_count"] = 0
+		updates["locked_until"] = lockUntil
+	}
+	return r.db.WithContext(ctx).Model(&rbac.SysUserMFA{}).
+		Where("user_id = ?", userID).
+		Updates(updates).Error
+}
+
+// ResetFailures 清除失败计数和锁定
+func (r *mfaRepo) ResetFailures(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUserMFA{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"failed_count": 0, "locked_until": nil}).Error
+}
+
+// ReplaceRecoveryCodes 替换用户的全部恢复码
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&rbac.SysUserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]*rbac.SysUserRecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, &rbac.SysUserRecoveryCode{UserID: userID, CodeHash: h})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode 核销恢复码，条件更新保证同一恢复码只能使用一次
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&rbac.SysUserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes 统计未使用的恢复码数量
+func (r *mfaRepo) CountRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&rbac.SysUserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// RoleRequiresMFA 检查用户是否拥有要求双因素认证的启用角色
+func (r *mfaRepo) RoleRequiresMFA(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("sys_user_role AS ur").
+		Joins("JOIN sys_role AS r ON r.id = ur.role_id").
+		Where("ur.user_id = ? AND r.require_mfa = ? AND r.status = 1 AND r.deleted_at IS NULL", userID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SetRoleRequireMFA 设置角色是否要求双因素认证
+func (r *mfaRepo) SetRoleRequireMFA(ctx context.Context, roleID uint, required bool) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysRole{}).
+		Where("id = ?", roleID).
+		Update("require_mfa", required).Error
+}
+
+// encrypt 加密
+func (r *mfaRepo) encrypt(plaintext string) (string, error) {
+	block, err := aes.NewCipher(r.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decrypt 解密
+func (r *mfaRepo) decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(r.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, cipherData := data[:nonceSize], data[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, cipherData, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
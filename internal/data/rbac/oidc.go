@@ -0,0 +1,213 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	"golang.org/x/oauth2"
+)
+
+// oidcHTTPTimeout 访问身份提供者的超时时间
+const oidcHTTPTimeout = 10 * time.Second
+
+// oidcProvider 单个身份提供者，发现文档在首次使用时加载，失败后下次请求重试
+type oidcProvider struct {
+	cfg conf.OIDCProviderConfig
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+	oauth2   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+type oidcConnector struct {
+	client    *http.Client
+	providers map[string]*oidcProvider
+}
+
+// NewOIDCConnector 创建 OIDC 协议客户端
+func NewOIDCConnector(configs []conf.OIDCProviderConfig) rbac.OIDCConnector {
+	c := &oidcConnector{
+		client:    &http.Client{Timeout: oidcHTTPTimeout},
+		providers: make(map[string]*oidcProvider, len(configs)),
+	}
+	for _, cfg := range configs {
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+		}
+		if cfg.UsernameClaim == "" {
+			cfg.UsernameClaim = "preferred_username"
+		}
+		if cfg.EmailClaim == "" {
+			cfg.EmailClaim = "email"
+		}
+		if cfg.RealNameClaim == "" {
+			cfg.RealNameClaim = "name"
+		}
+		if cfg.PhoneClaim == "" {
+			cfg.PhoneClaim = "phone_number"
+		}
+		c.providers[cfg.Name] = &oidcProvider{cfg: cfg}
+	}
+	return c
+}
+
+// load 加载发现文档和 JWKS 校验器
+func (c *oidcConnector) load(ctx context.Context, name string) (*oidcProvider, error) {
+	p, ok := c.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("未配置的身份提供者: %s", name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider != nil {
+		return p, nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, c.client), p.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("获取身份提供者配置失败: %w", err)
+	}
+	p.provider = provider
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientID})
+	return p, nil
+}
+
+// AuthCodeURL 生成授权地址
+func (c *oidcConnector) AuthCodeURL(ctx context.Context, name, state, nonce, verifier string) (string, error) {
+	p, err := c.load(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange 用授权码换取令牌，校验 ID Token 签名、受众、过期时间和 nonce，并解析声明
+func (c *oidcConnector) Exchange(ctx context.Context, name, code, nonce, verifier string) (*rbac.OIDCIdentity, error) {
+	p, err := c.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, c.client)
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("授权码交换失败: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("身份提供者未返回 id_token")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("ID Token 校验失败: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("ID Token nonce 不匹配")
+	}
+
+	claims := make(map[string]interface{})
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("解析 ID Token 声明失败: %w", err)
+	}
+
+	// ID Token 中缺少映射所需的声明时从 UserInfo 端点补充
+	if claimString(claims, p.cfg.UsernameClaim) == "" || (p.cfg.GroupsClaim != "" && claims[p.cfg.GroupsClaim] == nil) {
+		if userInfo, err := p.provider.UserInfo(ctx, oauth2.StaticTokenSource(token)); err == nil {
+			extra := make(map[string]interface{})
+			if err := userInfo.Claims(&extra); err == nil && extra["sub"] == idToken.Subject {
+				for k, v := range extra {
+					if _, exists := claims[k]; !exists {
+						claims[k] = v
+					}
+				}
+			}
+		}
+	}
+
+	return &rbac.OIDCIdentity{
+		Provider:      name,
+		Subject:       idToken.Subject,
+		Username:      claimString(claims, p.cfg.UsernameClaim),
+		Email:         claimString(claims, p.cfg.EmailClaim),
+		EmailVerified: claimBool(claims, "email_verified"),
+		RealName:      claimString(claims, p.cfg.RealNameClaim),
+		Phone:         claimString(claims, p.cfg.PhoneClaim),
+		Groups:        claimStrings(claims, p.cfg.GroupsClaim),
+	}, nil
+}
+
+// claimBool 读取布尔声明，部分提供者以字符串 "true" 返回
+func claimBool(claims map[string]interface{}, key string) bool {
+	switch v := claims[key].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// claimString 读取字符串声明
+func claimString(claims map[string]interface{}, key string) string {
+	if v, ok := claims[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// claimStrings 读取字符串或字符串数组声明
+func claimStrings(claims map[string]interface{}, key string) []string {
+	if key == "" {
+		return nil
+	}
+	switch v := claims[key].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+type passwordRepo struct {
+	db *gorm.DB
+}
+
+// NewPasswordRepo 创建密码历史和登录锁定仓储
+func NewPasswordRepo(db *gorm.DB) rbac.PasswordRepo {
+	return &passwordRepo{db: db}
+}
+
+// SetPassword 更新密码哈希、修改时间和强制改密标记
+func (r *passwordRepo) SetPassword(ctx context.Context, userID uint, hash string, changedAt time.Time, mustChange bool) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":             hash,
+			"password_changed_at":  changedAt,
+			"must_change_password": mustChange,
+		}).Error
+}
+
+// AddHistory 记录历史密码，只保留最近 keep 条
+func (r *passwordRepo) AddHistory(ctx context.Context, userID uint, hash string, keep int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rbac.SysUserPasswordHistory{UserID: userID, Password: hash}).Error; err != nil {
+			return err
+		}
+		var staleIDs []uint
+		if err := tx.Model(&rbac.SysUserPasswordHistory{}).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Offset(keep).
+			Pluck("id", &staleIDs).Error; err != nil {
+			return err
+		}
+		if len(staleIDs) == 0 {
+			return nil
+		}
+		return tx.Delete(&rbac.SysUserPasswordHistory{}, staleIDs).Error
+	})
+}
+
+// ListHistory 获取最近的历史密码哈希
+func (r *passwordRepo) ListHistory(ctx context.Context, userID uint, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.WithContext(ctx).Model(&rbac.SysUserPasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password", &hashes).Error
+	return hashes, err
+}
+
+// RecordLoginFailure 累加登录失败次数，上次失败早于窗口起点时重新计数，返回当前窗口内的失败次数
+func (r *passwordRepo) RecordLoginFailure(ctx context.Context, userID uint, windowStart, now time.Time) (int, error) {
+	// MySQL 按顺序执行赋值，先根据旧的 login_failed_at 计算次数，再更新窗口起点
+	err := r.db.WithContext(ctx).Exec(
+		"UPDATE sys_user SET "+
+			"login_failures = CASE WHEN login_failed_at IS NULL OR login_failed_at < ? THEN 1 ELSE login_failures + 1 END, "+
+			"login_failed_at = CASE WHEN login_failed_at IS NULL OR login_failed_at < ? THEN ? ELSE login_failed_at END "+
+			"WHERE id = ?",
+		windowStart, windowStart, now, userID,
+	).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var counts []int
+	if err := r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Pluck("login_failures", &counts).Error; err != nil || len(counts) == 0 {
+		return 0, err
+	}
+	return counts[0], nil
+}
+
+// Lock 锁定账号并清空失败计数
+func (r *passwordRepo) Lock(ctx context.Context, userID uint, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"login_failures":  0,
+			"login_failed_at": nil,
+			"locked_until":    until,
+		}).Error
+}
+
+// Unlock 解除锁定并清空失败计数
+func (r *passwordRepo) Unlock(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"login_failures":  0,
+			"login_failed_at": nil,
+			"locked_until":    nil,
+		}).Error
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+)
+
+type permissionRepo struct {
+	db *gorm.DB
+}
+
+// NewPermissionRepo 创建按钮权限仓储
+func NewPermissionRepo(db *gorm.DB) rbac.PermissionRepo {
+	return &permissionRepo{db: db}
+}
+
+// GetCodesByUserID 获取用户通过角色拥有的已启用按钮编码
+func (r *permissionRepo) GetCodesByUserID(ctx context.Context, userID uint) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).Model(&rbac.SysMenu{}).
+		Distinct("sys_menu.code").
+		Joins("JOIN sys_role_menu ON sys_role_menu.menu_id = sys_menu.id").
+		Joins("JOIN sys_user_role ON sys_user_role.role_id = sys_role_menu.role_id").
+		Joins("JOIN sys_role ON sys_role.id = sys_role_menu.role_id AND sys_role.status = 1 AND sys_role.deleted_at IS NULL").
+		Where("sys_user_role.user_id = ? AND sys_menu.type = 3 AND sys_menu.status = 1", userID).
+		Pluck("sys_menu.code", &codes).Error
+	return codes, err
+}
+
+// SyncButtons 确保每个权限都有对应的按钮菜单
+// 已存在（包括已删除）的按钮保持不变，新建的按钮授予已拥有父菜单的角色，保证升级后原有用户不受影响
+func (r *permissionRepo) SyncButtons(ctx context.Context, defs []rbac.PermissionDefinition) (int, error) {
+	created := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, def := range defs {
+			var count int64
+			if err := tx.Unscoped().Model(&rbac.SysMenu{}).Where("code = ?", def.Code).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			var parent rbac.SysMenu
+			err := tx.Where("code = ?", def.MenuCode).First(&parent).Error
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			button := &rbac.SysMenu{
+				Name:     def.Name,
+				Code:     def.Code,
+				Type:     3,
+				ParentID: parent.ID,
+				Sort:     i + 1,
+				Visible:  0,
+				Status:   1,
+			}
+			// Visible 为 0 时 Create 会被默认值覆盖，需要显式指定
+			if err := tx.Select("Name", "Code", "Type", "ParentID", "Sort", "Visible", "Status", "CreatedAt", "UpdatedAt").Create(button).Error; err != nil {
+				return err
+			}
+			if parent.ID != 0 {
+				if err := tx.Exec(
+					"INSERT INTO sys_role_menu (role_id, menu_id) SELECT role_id, ? FROM sys_role_menu WHERE menu_id = ?",
+					button.ID, parent.ID,
+				).Error; err != nil {
+					return err
+				}
+			}
+			created++
+		}
+		return nil
+	})
+	return created, err
+}
+
+// ListHolders 获取按钮菜单ID和持有角色
+func (r *permissionRepo) ListHolders(ctx context.Context, codes []string) (map[string]uint, map[string][]rbac.PermissionHolder, error) {
+	menuIDs := make(map[string]uint)
+	holders := make(map[string][]rbac.PermissionHolder)
+	if len(codes) == 0 {
+		return menuIDs, holders, nil
+	}
+
+	var menus []rbac.SysMenu
+	if err := r.db.WithContext(ctx).Where("code IN ? AND type = 3", codes).Find(&menus).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, menu := range menus {
+		menuIDs[menu.Code] = menu.ID
+	}
+
+	var rows []struct {
+		Code     string
+		RoleID   uint
+		RoleName string
+		RoleCode string
+	}
+	err := r.db.WithContext(ctx).Table("sys_role_menu").
+		Select("sys_menu.code AS code, sys_role.id AS role_id, sys_role.name AS role_name, sys_role.code AS role_code").
+		Joins("JOIN sys_menu ON sys_menu.id = sys_role_menu.menu_id AND sys_menu.deleted_at IS NULL").
+		Joins("JOIN sys_role ON sys_role.id = sys_role_menu.role_id AND sys_role.deleted_at IS NULL").
+		Where("sys_menu.code IN ? AND sys_menu.type = 3", codes).
+		Order("sys_role.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		holders[row.Code] = append(holders[row.Code], rbac.PermissionHolder{
+			RoleID:   row.RoleID,
+			RoleName: row.RoleName,
+			RoleCode: row.RoleCode,
+		})
+	}
+	return menuIDs, holders, nil
+}
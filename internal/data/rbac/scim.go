@@ -0,0 +1,165 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type scimRepo struct {
+	db *gorm.DB
+}
+
+// NewSCIMRepo 创建 SCIM 仓储
+func NewSCIMRepo(db *gorm.DB) rbac.SCIMRepo {
+	return &scimRepo{db: db}
+}
+
+// ListUsers 按条件分页获取除服务账号外的用户
+func (r *scimRepo) ListUsers(ctx context.Context, page *rbac.SCIMPage) ([]*rbac.SysUser, int64, error) {
+	query := r.db.WithContext(ctx).Model(&rbac.SysUser{}).
+		Where("source IS NULL OR source <> ?", rbac.UserSourceService)
+	if page.Where != nil {
+		query = query.Where(page.Where.SQL, page.Where.Args...)
+	}
+	// 计数和查询共用条件，开启新会话避免计数修改查询语句
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []*rbac.SysUser
+	err := query.
+		Preload("Department").
+		Preload("Roles").
+		Order("id ASC").
+		Offset(page.Offset).
+		Limit(page.Limit).
+		Find(&users).Error
+	return users, total, err
+}
+
+// SaveUser 只更新 SCIM 管理的字段，零值（如禁用状态、清空的部门）也会写入
+func (r *scimRepo) SaveUser(ctx context.Context, user *rbac.SysUser) error {
+	return r.db.WithContext(ctx).Model(&rbac.SysUser{}).Where("id = ?", user.ID).
+		Select("username", "real_name", "email", "phone", "department_id", "external_id", "status").
+		Updates(user).Error
+}
+
+// ListRoles 获取全部角色及其成员
+func (r *scimRepo) ListRoles(ctx context.Context) ([]*rbac.SysRole, error) {
+	var roles []*rbac.SysRole
+	err := r.db.WithContext(ctx).Preload("Users").Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// PageRoles 按条件分页获取角色及其成员
+func (r *scimRepo) PageRoles(ctx context.Context, page *rbac.SCIMPage) ([]*rbac.SysRole, int64, error) {
+	query := r.db.WithContext(ctx).Model(&rbac.SysRole{})
+	if page.Where != nil {
+		query = query.Where(page.Where.SQL, page.Where.Args...)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var roles []*rbac.SysRole
+	err := query.Preload("Users").Order("id ASC").Offset(page.Offset).Limit(page.Limit).Find(&roles).Error
+	return roles, total, err
+}
+
+// GetRole 获取角色及其成员
+func (r *scimRepo) GetRole(ctx context.Context, id uint) (*rbac.SysRole, error) {
+	var role rbac.SysRole
+	err := r.db.WithContext(ctx).Preload("Users").First(&role, id).Error
+	return &role, err
+}
+
+// AddRoleMembers 添加角色成员，已存在的成员忽略
+func (r *scimRepo) AddRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	userRoles := make([]rbac.SysUserRole, 0, len(userIDs))
+	for _, userID := range userIDs {
+		userRoles = append(userRoles, rbac.SysUserRole{UserID: userID, RoleID: roleID})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&userRoles).Error
+}
+
+// RemoveRoleMembers 移除角色成员
+func (r *scimRepo) RemoveRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("role_id = ? AND user_id IN ?", roleID, userIDs).Delete(&rbac.SysUserRole{}).Error
+}
+
+// SetRoleMembers 用给定的用户替换角色的全部成员
+func (r *scimRepo) SetRoleMembers(ctx context.Context, roleID uint, userIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&rbac.SysUserRole{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		seen := make(map[uint]bool, len(userIDs))
+		userRoles := make([]rbac.SysUserRole, 0, len(userIDs))
+		for _, userID := range userIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			userRoles = append(userRoles, rbac.SysUserRole{UserID: userID, RoleID: roleID})
+		}
+		return tx.Create(&userRoles).Error
+	})
+}
+
+// FindOrCreateDepartment 按名称或编码查找部门，不存在时以名称作为编码创建顶级部门
+func (r *scimRepo) FindOrCreateDepartment(ctx context.Context, name string) (uint, error) {
+	var dept rbac.SysDepartment
+	err := r.db.WithContext(ctx).Where("name = ? OR code = ?", name, name).Order("id ASC").First(&dept).Error
+	if err == nil {
+		return dept.ID, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+
+	dept = rbac.SysDepartment{
+		Name:     name,
+		Code:     name,
+		DeptType: 3,
+		Status:   1,
+	}
+	if err := r.db.WithContext(ctx).Create(&dept).Error; err != nil {
+		return 0, err
+	}
+	return dept.ID, nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+)
+
+const (
+	sessionKeyPrefix     = "session:"
+	userSessionKeyPrefix = "user_sessions:"
+)
+
+// rotateScript 刷新令牌摘要匹配时才替换，保证同一个刷新令牌只能使用一次
+var rotateScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'refresh_hash') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'refresh_hash', ARGV[2], 'prev_hash', ARGV[1], 'rotated_at', ARGV[3],
+	'last_active_at', ARGV[3], 'ip', ARGV[4], 'expires_at', ARGV[5])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+redis.call('PEXPIRE', KEYS[2], ARGV[6])
+return 1
+`)
+
+type sessionStore struct {
+	client *redis.Client
+}
+
+// NewSessionStore 创建基于 Redis 的会话存储，多副本部署共享
+func NewSessionStore(client *redis.Client) rbac.SessionStore {
+	return &sessionStore{client: client}
+}
+
+func sessionKey(id string) string {
+	return sessionKeyPrefix + id
+}
+
+func userSessionKey(userID uint) string {
+	return userSessionKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Save 保存会话并加入用户的会话集合
+func (s *sessionStore) Save(ctx context.Context, session *rbac.Session, ttl time.Duration) error {
+	key := sessionKey(session.ID)
+	userKey := userSessionKey(session.UserID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"user_id":        session.UserID,
+			"username":       session.Username,
+			"login_type":     session.LoginType,
+			"ip":             session.IP,
+			"user_agent":     session.UserAgent,
+			"device":         session.Device,
+			"created_at":     session.CreatedAt.Unix(),
+			"last_active_at": session.LastActiveAt.Unix(),
+			"expires_at":     session.ExpiresAt.Unix(),
+			"refresh_hash":   session.RefreshHash,
+		})
+		pipe.Expire(ctx, key, ttl)
+		pipe.SAdd(ctx, userKey, session.ID)
+		pipe.Expire(ctx, userKey, ttl)
+		return nil
+	})
+	return err
+}
+
+// Get 获取会话
+func (s *sessionStore) Get(ctx context.Context, id string) (*rbac.Session, error) {
+	values, err := s.client.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, rbac.ErrSessionNotFound
+	}
+	userID, _ := strconv.ParseUint(values["user_id"], 10, 64)
+	return &rbac.Session{
+		ID:           id,
+		UserID:       uint(userID),
+		Username:     values["username"],
+		LoginType:    values["login_type"],
+		IP:           values["ip"],
+		UserAgent:    values["user_agent"],
+		Device:       values["device"],
+		CreatedAt:    parseUnix(values["created_at"]),
+		LastActiveAt: parseUnix(values["last_active_at"]),
+		ExpiresAt:    parseUnix(values["expires_at"]),
+		RefreshHash:  values["refresh_hash"],
+		PrevHash:     values["prev_hash"],
+		RotatedAt:    parseUnix(values["rotated_at"]),
+	}, nil
+}
+
+// Touch 更新最近活动时间和IP，会话已删除时不会重新创建
+func (s *sessionStore) Touch(ctx context.Context, id, ip string, at time.Time) error {
+	key := sessionKey(id)
+	exists, err := s.client.Exists(ctx, key).Result()
+	if err != nil || exists == 0 {
+		return err
+	}
+	return s.client.HSet(ctx, key, "last_active_at", at.Unix(), "ip", ip).Err()
+}
+
+// Rotate 轮换刷新令牌
+func (s *sessionStore) Rotate(ctx context.Context, id, oldHash, newHash, ip string, at time.Time, ttl time.Duration) (bool, error) {
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	result, err := rotateScript.Run(ctx, s.client,
+		[]string{sessionKey(id), userSessionKey(session.UserID)},
+		oldHash, newHash, at.Unix(), ip, at.Add(ttl).Unix(), ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// Delete 删除会话
+func (s *sessionStore) Delete(ctx context.Context, userID uint, id string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(id))
+		pipe.SRem(ctx, userSessionKey(userID), id)
+		return nil
+	})
+	return err
+}
+
+// ListByUser 获取用户的会话，顺带清理集合中已过期的会话ID
+func (s *sessionStore) ListByUser(ctx context.Context, userID uint) ([]*rbac.Session, error) {
+	userKey := userSessionKey(userID)
+	ids, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*rbac.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := s.Get(ctx, id)
+		if err == rbac.ErrSessionNotFound {
+			s.client.SRem(ctx, userKey, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActiveAt.After(sessions[j].LastActiveAt)
+	})
+	return sessions, nil
+}
+
+func parseUnix(value string) time.Time {
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
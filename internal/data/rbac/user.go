@@ -75,15 +75,7 @@ func (r *userRepo) List(ctx context.Context, page, pageSize int, keyword string,
 	var users []*rbac.SysUser
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&rbac.SysUser{})
-	if keyword != "" {
-		query = query.Where("username LIKE ? OR real_name LIKE ? OR email LIKE ?",
-			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
-	}
-	if departmentID > 0 {
-		query = query.Where("department_id = ?", departmentID)
-	}
-
+	query := r.listQuery(ctx, keyword, departmentID)
 	err := query.Count(&total).Error
 	if err != nil {
 		return nil, 0, err
@@ -97,6 +89,30 @@ func (r *userRepo) List(ctx context.Context, page, pageSize int, keyword string,
 	return users, total, err
 }
 
+// ListAll 获取全部符合条件的用户（不分页），同样受数据权限限制
+func (r *userRepo) ListAll(ctx context.Context, keyword string, departmentID uint) ([]*rbac.SysUser, error) {
+	var users []*rbac.SysUser
+	err := r.listQuery(ctx, keyword, departmentID).
+		Preload("Department").Preload("Roles").Preload("Positions").
+		Order("id ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// listQuery 用户列表的查询条件
+func (r *userRepo) listQuery(ctx context.Context, keyword string, departmentID uint) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&rbac.SysUser{})
+	if keyword != "" {
+		query = query.Where("username LIKE ? OR real_name LIKE ? OR email LIKE ?",
+			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if departmentID > 0 {
+		query = query.Where("department_id = ?", departmentID)
+	}
+	// 数据权限：只返回可见部门内的用户
+	return query.Scopes(rbac.ScopeByDataPermission(ctx, "id"))
+}
+
 func (r *userRepo) AssignRoles(ctx context.Context, userID uint, roleIDs []uint) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除原有角色
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
 	"gorm.io/gorm"
 )
 
@@ -83,6 +84,72 @@ type MenuConfig struct {
 	Permission string `json:"permission"`
 }
 
+// PermissionProvider 可选接口，插件实现后按接口声明按钮权限
+type PermissionProvider interface {
+	// GetPermissions 返回插件接口的权限定义
+	GetPermissions() []PermissionConfig
+}
+
+// PermissionConfig 插件权限定义，对应插件菜单下的一个按钮
+type PermissionConfig struct {
+	// 权限编码，同时作为按钮菜单编码
+	Code string `json:"code"`
+
+	// 按钮名称
+	Name string `json:"name"`
+
+	// 所属菜单编码
+	MenuCode string `json:"menuCode"`
+
+	// 受控接口
+	Routes []PermissionRoute `json:"routes"`
+}
+
+// PermissionRoute 受权限控制的插件接口
+type PermissionRoute struct {
+	// HTTP 方法
+	Method string `json:"method"`
+
+	// 路由路径，相对于插件路由组 /api/v1/plugins
+	Path string `json:"path"`
+}
+
+// AuditProvider 可选接口，插件实现后为接口声明审计元数据
+type AuditProvider interface {
+	// GetAuditRoutes 返回插件接口的审计元数据，操作日志据此记录模块、操作和资源
+	GetAuditRoutes() []AuditRouteConfig
+}
+
+// AuditRouteConfig 插件接口的审计元数据
+type AuditRouteConfig struct {
+	// HTTP 方法
+	Method string `json:"method"`
+
+	// 路由路径，相对于插件路由组 /api/v1/plugins
+	Path string `json:"path"`
+
+	// 所属模块
+	Module string `json:"module"`
+
+	// 操作类型，如 创建、更新、删除、执行
+	Action string `json:"action"`
+
+	// 描述模板，可引用 {path.参数}、{query.参数}、{body.字段}、{resp.字段}
+	Description string `json:"description"`
+
+	// 资源类型
+	ResourceType string `json:"resourceType"`
+
+	// 资源ID取值表达式，如 path.id，多个用 | 分隔
+	ResourceID string `json:"resourceId"`
+}
+
+// TimelineProvider 可选接口，插件实现后向审计活动时间线提供记录
+type TimelineProvider interface {
+	// GetTimelineSources 返回插件的时间线来源
+	GetTimelineSources(db *gorm.DB) []audit.TimelineSource
+}
+
 // PluginState 插件状态数据模型
 type PluginState struct {
 	ID        uint      `gorm:"primarykey" json:"id"`
@@ -221,6 +288,42 @@ func (m *Manager) RegisterAllRoutes(router *gin.RouterGroup) {
 	}
 }
 
+// GetAllPermissions 获取所有已启用插件声明的权限
+func (m *Manager) GetAllPermissions() []PermissionConfig {
+	allPermissions := make([]PermissionConfig, 0)
+	for _, plugin := range m.plugins {
+		provider, ok := plugin.(PermissionProvider)
+		if ok && m.IsEnabled(plugin.Name()) {
+			allPermissions = append(allPermissions, provider.GetPermissions()...)
+		}
+	}
+	return allPermissions
+}
+
+// GetAllAuditRoutes 获取所有已启用插件声明的接口审计元数据
+func (m *Manager) GetAllAuditRoutes() []AuditRouteConfig {
+	allRoutes := make([]AuditRouteConfig, 0)
+	for _, plugin := range m.plugins {
+		provider, ok := plugin.(AuditProvider)
+		if ok && m.IsEnabled(plugin.Name()) {
+			allRoutes = append(allRoutes, provider.GetAuditRoutes()...)
+		}
+	}
+	return allRoutes
+}
+
+// GetAllTimelineSources 获取所有已启用插件提供的时间线来源
+func (m *Manager) GetAllTimelineSources() []audit.TimelineSource {
+	allSources := make([]audit.TimelineSource, 0)
+	for _, plugin := range m.plugins {
+		provider, ok := plugin.(TimelineProvider)
+		if ok && m.IsEnabled(plugin.Name()) {
+			allSources = append(allSources, provider.GetTimelineSources(m.db)...)
+		}
+	}
+	return allSources
+}
+
 // GetAllMenus Get all plugin menu configurations
 func (m *Manager) GetAllMenus() []MenuConfig {
 	allMenus := make([]MenuConfig, 0)
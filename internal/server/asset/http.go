@@ -31,26 +31,34 @@ import (
 )
 
 type HTTPServer struct {
-	assetGroupService    *assetService.AssetGroupService
-	hostService          *assetService.HostService
-	terminalManager      *TerminalManager
-	terminalAuditHandler *TerminalAuditHandler
-	authMiddleware       *rbacService.AuthMiddleware
+	assetGroupService     *assetService.AssetGroupService
+	hostService           *assetService.HostService
+	databaseService       *assetService.DatabaseService
+	terminalManager       *TerminalManager
+	portForwardManager    *PortForwardManager
+	terminalAuditHandler  *TerminalAuditHandler
+	terminalPolicyHandler *TerminalPolicyHandler
+	authMiddleware        *rbacService.AuthMiddleware
 }
 
 func NewHTTPServer(
 	assetGroupService *assetService.AssetGroupService,
 	hostService *assetService.HostService,
+	databaseService *assetService.DatabaseService,
 	terminalManager *TerminalManager,
+	portForwardManager *PortForwardManager,
 	db *gorm.DB,
 	authMiddleware *rbacService.AuthMiddleware,
 ) *HTTPServer {
 	return &HTTPServer{
-		assetGroupService:    assetGroupService,
-		hostService:          hostService,
-		terminalManager:      terminalManager,
-		terminalAuditHandler: NewTerminalAuditHandler(db),
-		authMiddleware:       authMiddleware,
+		assetGroupService:     assetGroupService,
+		hostService:           hostService,
+		databaseService:       databaseService,
+		terminalManager:       terminalManager,
+		portForwardManager:    portForwardManager,
+		terminalAuditHandler:  NewTerminalAuditHandler(db),
+		terminalPolicyHandler: NewTerminalPolicyHandler(db),
+		authMiddleware:        authMiddleware,
 	}
 }
 
@@ -114,6 +122,35 @@ func (s *HTTPServer) RegisterRoutes(r *gin.RouterGroup) {
 			s.hostService.DeleteHostFile)
 	}
 
+	// 数据库资产管理
+	databases := r.Group("/databases")
+	{
+		databases.GET("", s.databaseService.ListDatabases)
+		databases.POST("",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionEdit),
+			s.databaseService.CreateDatabase)
+		databases.GET("/:id",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionView),
+			s.databaseService.GetDatabase)
+		databases.PUT("/:id",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionEdit),
+			s.databaseService.UpdateDatabase)
+		databases.DELETE("/:id",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionDelete),
+			s.databaseService.DeleteDatabase)
+		databases.POST("/:id/test",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionView),
+			s.databaseService.TestDatabaseConnection)
+
+		// SQL控制台 - 终端权限，写入语句另需数据库写入权限
+		databases.POST("/:id/query",
+			s.authMiddleware.RequireDatabasePermission(rbacbiz.PermissionTerminal),
+			s.databaseService.ExecuteQuery)
+	}
+
+	// 数据库语句审计
+	r.GET("/database-query-logs", s.databaseService.ListQueryLogs)
+
 	// 凭证管理
 	credentials := r.Group("/credentials")
 	{
@@ -148,26 +185,55 @@ func (s *HTTPServer) RegisterRoutes(r *gin.RouterGroup) {
 		terminal.POST("/:id/resize", s.ResizeTerminal)
 	}
 
+	// 端口转发 - 端口转发权限
+	portForward := r.Group("/asset/port-forward")
+	{
+		portForward.GET("/:id/ws",
+			s.authMiddleware.RequireHostPermission(rbacbiz.PermissionPortForward),
+			s.HandlePortForwardWebSocket)
+		portForward.POST("/:id",
+			s.authMiddleware.RequireHostPermission(rbacbiz.PermissionPortForward),
+			s.CreatePortForwardListener)
+	}
+
+	// 端口转发审计
+	portForwards := r.Group("/port-forwards")
+	{
+		portForwards.GET("", s.ListPortForwards)
+		portForwards.DELETE("/:id", s.ClosePortForward)
+	}
+
 	// 终端审计
 	terminalSessions := r.Group("/terminal-sessions")
 	{
 		terminalSessions.GET("", s.terminalAuditHandler.ListTerminalSessions)
 		terminalSessions.GET("/:id/play", s.terminalAuditHandler.PlayTerminalSession)
-		terminalSessions.DELETE("/:id", s.terminalAuditHandler.DeleteTerminalSession)
+	}
+
+	// 终端会话策略
+	terminalPolicies := r.Group("/terminal-policies")
+	{
+		terminalPolicies.GET("", s.terminalPolicyHandler.ListPolicies)
+		terminalPolicies.POST("", s.terminalPolicyHandler.CreatePolicy)
+		terminalPolicies.PUT("/:id", s.terminalPolicyHandler.UpdatePolicy)
+		terminalPolicies.DELETE("/:id", s.terminalPolicyHandler.DeletePolicy)
 	}
 }
 
 // NewAssetServices 创建asset相关的服务
-func NewAssetServices(db *gorm.DB) (
+func NewAssetServices(db *gorm.DB, portForwardBind string) (
 	*assetService.AssetGroupService,
 	*assetService.HostService,
+	*assetService.DatabaseService,
 	*TerminalManager,
+	*PortForwardManager,
 ) {
 	// 初始化Repository
 	assetGroupRepo := assetdata.NewAssetGroupRepo(db)
 	hostRepo := assetdata.NewHostRepo(db)
 	credentialRepo := assetdata.NewCredentialRepo(db)
 	cloudAccountRepo := assetdata.NewCloudAccountRepo(db)
+	databaseRepo := assetdata.NewDatabaseRepo(db)
 	assetPermissionRepo := rbacdata.NewAssetPermissionRepo(db)
 
 	// 初始化UseCase
@@ -175,14 +241,19 @@ func NewAssetServices(db *gorm.DB) (
 	credentialUseCase := assetbiz.NewCredentialUseCase(credentialRepo, hostRepo)
 	cloudAccountUseCase := assetbiz.NewCloudAccountUseCase(cloudAccountRepo)
 	hostUseCase := assetbiz.NewHostUseCase(hostRepo, credentialRepo, assetGroupRepo, cloudAccountRepo)
+	databaseUseCase := assetbiz.NewDatabaseUseCase(databaseRepo, credentialRepo, assetGroupRepo)
 	assetPermissionUseCase := rbacbiz.NewAssetPermissionUseCase(assetPermissionRepo)
 
 	// 初始化Service
 	assetGroupService := assetService.NewAssetGroupService(assetGroupUseCase)
 	hostService := assetService.NewHostService(hostUseCase, credentialUseCase, cloudAccountUseCase, assetPermissionUseCase)
+	databaseService := assetService.NewDatabaseService(databaseUseCase, assetPermissionUseCase)
 
 	// 初始化TerminalManager
 	terminalManager := NewTerminalManager(hostUseCase, db)
 
-	return assetGroupService, hostService, terminalManager
+	// 初始化PortForwardManager
+	portForwardManager := NewPortForwardManager(hostUseCase, db, portForwardBind)
+
+	return assetGroupService, hostService, databaseService, terminalManager, portForwardManager
 }
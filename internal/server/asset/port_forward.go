@@ -0,0 +1,599 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	rbacService "github.com/ydcloud-dy/opshub/internal/service/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	sshclient "github.com/ydcloud-dy/opshub/pkg/ssh"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPortForwardMinutes = 60  // 默认有效期(分钟)
+	maxPortForwardMinutes     = 480 // 最长有效期(分钟)
+	// defaultPortForwardBind 未配置监听地址时只监听本机回环地址
+	defaultPortForwardBind = "127.0.0.1"
+)
+
+// PortForwardTunnel 活动中的端口转发隧道
+type PortForwardTunnel struct {
+	Record    *assetbiz.PortForwardSession
+	sshClient *sshclient.Client
+	listener  net.Listener
+
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+	connCount     atomic.Int32
+
+	mu      sync.Mutex
+	closers []io.Closer
+	timer   *time.Timer
+	done    chan struct{}
+	once    sync.Once
+}
+
+// track 登记需要在隧道关闭时一并关闭的连接
+func (t *PortForwardTunnel) track(c io.Closer) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.done:
+		return false
+	default:
+	}
+	t.closers = append(t.closers, c)
+	return true
+}
+
+// PortForwardManager 端口转发管理器
+type PortForwardManager struct {
+	tunnels     map[uint]*PortForwardTunnel
+	mu          sync.RWMutex
+	hostUseCase *assetbiz.HostUseCase
+	db          *gorm.DB
+	bindAddr    string
+}
+
+// NewPortForwardManager 创建端口转发管理器，bindAddr 为临时监听端口绑定的地址
+func NewPortForwardManager(hostUseCase *assetbiz.HostUseCase, db *gorm.DB, bindAddr string) *PortForwardManager {
+	// 进程重启后内存中的隧道已不存在，将遗留的活动记录标记为关闭
+	now := time.Now()
+	db.Model(&assetbiz.PortForwardSession{}).
+		Where("status = ?", assetbiz.PortForwardStatusActive).
+		Updates(map[string]interface{}{
+			"status":    assetbiz.PortForwardStatusClosed,
+			"reason":    "服务重启",
+			"closed_at": &now,
+		})
+
+	if bindAddr == "" {
+		bindAddr = defaultPortForwardBind
+	}
+	return &PortForwardManager{
+		tunnels:     make(map[uint]*PortForwardTunnel),
+		hostUseCase: hostUseCase,
+		db:          db,
+		bindAddr:    bindAddr,
+	}
+}
+
+// Open 建立到主机的SSH连接并登记端口转发记录
+func (pm *PortForwardManager) Open(ctx context.Context, hostID, userID uint, username, clientIP, mode string, req *assetbiz.PortForwardRequest) (*PortForwardTunnel, error) {
+	if req.TargetHost == "" {
+		req.TargetHost = "127.0.0.1"
+	}
+	if req.Duration <= 0 {
+		req.Duration = defaultPortForwardMinutes
+	}
+	if req.Duration > maxPortForwardMinutes {
+		req.Duration = maxPortForwardMinutes
+	}
+
+	host, client, err := pm.hostUseCase.OpenSSHClient(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &assetbiz.PortForwardSession{
+		HostID:     host.ID,
+		HostName:   host.Name,
+		HostIP:     host.IP,
+		UserID:     userID,
+		Username:   username,
+		Mode:       mode,
+		TargetHost: req.TargetHost,
+		TargetPort: req.TargetPort,
+		ClientIP:   clientIP,
+		Status:     assetbiz.PortForwardStatusActive,
+		ExpiresAt:  time.Now().Add(time.Duration(req.Duration) * time.Minute),
+	}
+	if err := pm.db.Create(record).Error; err != nil {
+		client.Close()
+		return nil, fmt.Errorf("保存端口转发记录失败: %w", err)
+	}
+
+	tunnel := &PortForwardTunnel{
+		Record:    record,
+		sshClient: client,
+		done:      make(chan struct{}),
+	}
+	tunnel.timer = time.AfterFunc(time.Until(record.ExpiresAt), func() {
+		pm.Close(record.ID, assetbiz.PortForwardStatusExpired, "已到期")
+	})
+
+	pm.mu.Lock()
+	pm.tunnels[record.ID] = tunnel
+	pm.mu.Unlock()
+
+	appLogger.Info("端口转发已建立",
+		zap.Uint("id", record.ID),
+		zap.String("mode", mode),
+		zap.Uint("hostID", host.ID),
+		zap.String("target", record.Target()),
+		zap.String("username", username))
+
+	return tunnel, nil
+}
+
+// LoopbackOnly 监听地址是否为本机回环地址，此时只有平台服务器本机能连接临时监听端口
+func (pm *PortForwardManager) LoopbackOnly() bool {
+	ip := net.ParseIP(pm.bindAddr)
+	if ip == nil {
+		return pm.bindAddr == "localhost"
+	}
+	return ip.IsLoopback()
+}
+
+// Listen 在平台侧开启临时监听端口，仅接受发起方IP的连接；
+// 监听回环地址时连接只可能来自本机（包括同机部署的反向代理），直接放行回环来源
+func (pm *PortForwardManager) Listen(tunnel *PortForwardTunnel) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort(pm.bindAddr, "0"))
+	if err != nil {
+		pm.Close(tunnel.Record.ID, assetbiz.PortForwardStatusFailed, err.Error())
+		return fmt.Errorf("开启监听端口失败: %w", err)
+	}
+	tunnel.listener = listener
+	tunnel.track(listener)
+
+	tunnel.Record.ListenAddr = listener.Addr().String()
+	pm.db.Model(tunnel.Record).Update("listen_addr", tunnel.Record.ListenAddr)
+
+	loopbackOnly := pm.LoopbackOnly()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if !allowedClient(conn.RemoteAddr(), tunnel.Record.ClientIP, loopbackOnly) {
+				appLogger.Warn("拒绝非发起方的端口转发连接",
+					zap.Uint("id", tunnel.Record.ID),
+					zap.String("remote", conn.RemoteAddr().String()))
+				conn.Close()
+				continue
+			}
+			go pm.forward(tunnel, conn)
+		}
+	}()
+
+	return nil
+}
+
+// forward 将一个本地连接通过SSH通道转发到目标地址
+func (pm *PortForwardManager) forward(tunnel *PortForwardTunnel, conn net.Conn) {
+	defer conn.Close()
+	if !tunnel.track(conn) {
+		return
+	}
+
+	remote, err := tunnel.sshClient.Dial("tcp", tunnel.Record.Target())
+	if err != nil {
+		appLogger.Error("端口转发连接目标失败", zap.Uint("id", tunnel.Record.ID), zap.Error(err))
+		return
+	}
+	defer remote.Close()
+	tunnel.track(remote)
+	tunnel.connCount.Add(1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		n, _ := io.Copy(remote, conn)
+		tunnel.bytesSent.Add(n)
+		remote.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		n, _ := io.Copy(conn, remote)
+		tunnel.bytesReceived.Add(n)
+		conn.Close()
+	}()
+	wg.Wait()
+}
+
+// ServeWebSocket 将WebSocket二进制消息与目标TCP连接双向转发
+func (pm *PortForwardManager) ServeWebSocket(tunnel *PortForwardTunnel, ws *websocket.Conn) {
+	defer pm.Close(tunnel.Record.ID, assetbiz.PortForwardStatusClosed, "连接断开")
+	if !tunnel.track(ws) {
+		return
+	}
+
+	remote, err := tunnel.sshClient.Dial("tcp", tunnel.Record.Target())
+	if err != nil {
+		ws.WriteMessage(websocket.TextMessage, []byte(err.Error()))
+		pm.Close(tunnel.Record.ID, assetbiz.PortForwardStatusFailed, err.Error())
+		return
+	}
+	defer remote.Close()
+	tunnel.track(remote)
+	tunnel.connCount.Add(1)
+
+	// 目标 -> WebSocket
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := remote.Read(buf)
+			if n > 0 {
+				if werr := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+					break
+				}
+				tunnel.bytesReceived.Add(int64(n))
+			}
+			if err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}()
+
+	// WebSocket -> 目标
+	for {
+		messageType, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+		if _, err := remote.Write(data); err != nil {
+			return
+		}
+		tunnel.bytesSent.Add(int64(len(data)))
+	}
+}
+
+// Close 关闭隧道并写入流量统计
+func (pm *PortForwardManager) Close(id uint, status, reason string) error {
+	pm.mu.Lock()
+	tunnel, ok := pm.tunnels[id]
+	delete(pm.tunnels, id)
+	pm.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("端口转发不存在或已关闭")
+	}
+
+	tunnel.once.Do(func() {
+		tunnel.timer.Stop()
+
+		tunnel.mu.Lock()
+		close(tunnel.done)
+		closers := tunnel.closers
+		tunnel.closers = nil
+		tunnel.mu.Unlock()
+
+		for _, c := range closers {
+			c.Close()
+		}
+		tunnel.sshClient.Close()
+
+		now := time.Now()
+		record := tunnel.Record
+		record.BytesSent = tunnel.bytesSent.Load()
+		record.BytesReceived = tunnel.bytesReceived.Load()
+		record.ConnCount = int(tunnel.connCount.Load())
+		record.Status = status
+		record.Reason = reason
+		record.ClosedAt = &now
+		record.Duration = int(now.Sub(record.CreatedAt).Seconds())
+
+		if err := pm.db.Model(record).Select(
+			"bytes_sent", "bytes_received", "conn_count", "status", "reason", "closed_at", "duration",
+		).Updates(record).Error; err != nil {
+			appLogger.Error("保存端口转发记录失败", zap.Uint("id", id), zap.Error(err))
+		}
+
+		appLogger.Info("端口转发已关闭",
+			zap.Uint("id", id),
+			zap.String("status", status),
+			zap.String("reason", reason),
+			zap.Int64("bytesSent", record.BytesSent),
+			zap.Int64("bytesReceived", record.BytesReceived))
+	})
+	return nil
+}
+
+// Get 获取活动隧道
+func (pm *PortForwardManager) Get(id uint) (*PortForwardTunnel, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	tunnel, ok := pm.tunnels[id]
+	return tunnel, ok
+}
+
+// Stats 获取活动隧道的实时流量
+func (t *PortForwardTunnel) Stats() (sent, received int64, conns int) {
+	return t.bytesSent.Load(), t.bytesReceived.Load(), int(t.connCount.Load())
+}
+
+// allowedClient 判断连接来源是否为隧道发起方，loopbackOnly 时放行本机回环来源
+func allowedClient(addr net.Addr, clientIP string, loopbackOnly bool) bool {
+	if clientIP == "" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	remote := net.ParseIP(host)
+	if loopbackOnly && remote != nil && remote.IsLoopback() {
+		return true
+	}
+	expected := net.ParseIP(clientIP)
+	if remote == nil || expected == nil {
+		return false
+	}
+	return remote.Equal(expected)
+}
+
+// HandlePortForwardWebSocket 建立WebSocket端口转发隧道
+// @Summary WebSocket端口转发
+// @Description 通过WebSocket二进制消息转发到主机可访问的目标端口，供命令行工具使用
+// @Tags 端口转发
+// @Security Bearer
+// @Param id path int true "主机ID"
+// @Param targetHost query string false "目标地址(相对主机)，默认127.0.0.1"
+// @Param targetPort query int true "目标端口"
+// @Param duration query int false "有效期(分钟)，默认60，最长480"
+// @Router /api/v1/asset/port-forward/{id}/ws [get]
+func (s *HTTPServer) HandlePortForwardWebSocket(c *gin.Context) {
+	hostID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的主机ID")
+		return
+	}
+
+	var req assetbiz.PortForwardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	tunnel, err := s.portForwardManager.Open(c.Request.Context(), uint(hostID),
+		rbacService.GetUserID(c), rbacService.GetUsername(c), c.ClientIP(),
+		assetbiz.PortForwardModeWebSocket, &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		appLogger.Error("WebSocket升级失败", zap.Error(err))
+		s.portForwardManager.Close(tunnel.Record.ID, assetbiz.PortForwardStatusFailed, "WebSocket升级失败")
+		return
+	}
+
+	s.portForwardManager.ServeWebSocket(tunnel, conn)
+}
+
+// CreatePortForwardListener 创建临时监听端口转发
+// @Summary 创建临时监听端口转发
+// @Description 在平台侧开启临时监听端口，转发到主机可访问的目标端口，仅允许发起方IP连接。
+// @Description 远程客户端需要将 server.port_forward_bind 配置为对外网卡地址；保持默认回环地址时只允许在平台服务器本机发起
+// @Tags 端口转发
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "主机ID"
+// @Param body body assetbiz.PortForwardRequest true "转发参数"
+// @Success 200 {object} response.Response "创建成功"
+// @Router /api/v1/asset/port-forward/{id} [post]
+func (s *HTTPServer) CreatePortForwardListener(c *gin.Context) {
+	hostID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的主机ID")
+		return
+	}
+
+	var req assetbiz.PortForwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 回环地址上的监听端口远程客户端无法访问，提前拒绝而不是创建一个连不上的隧道
+	clientIP := c.ClientIP()
+	if s.portForwardManager.LoopbackOnly() && !isLoopbackIP(clientIP) {
+		response.ErrorCode(c, http.StatusBadRequest, "端口转发监听地址为本机回环地址，远程客户端请使用WebSocket模式，或将 server.port_forward_bind 配置为对外网卡地址")
+		return
+	}
+
+	tunnel, err := s.portForwardManager.Open(c.Request.Context(), uint(hostID),
+		rbacService.GetUserID(c), rbacService.GetUsername(c), clientIP,
+		assetbiz.PortForwardModeListener, &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := s.portForwardManager.Listen(tunnel); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listenAddr := reachableListenAddr(tunnel.Record.ListenAddr, c.Request.Host)
+	_, port, _ := net.SplitHostPort(listenAddr)
+	response.Success(c, gin.H{
+		"session":    tunnel.Record,
+		"listenAddr": listenAddr,
+		"listenPort": port,
+		"expiresAt":  tunnel.Record.ExpiresAt,
+	})
+}
+
+// isLoopbackIP 判断IP是否为本机回环地址
+func isLoopbackIP(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.IsLoopback()
+}
+
+// reachableListenAddr 返回客户端可连接的监听地址，监听在通配地址时使用请求访问平台的主机名
+func reachableListenAddr(listenAddr, requestHost string) string {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return listenAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+		return listenAddr
+	}
+	if h, _, err := net.SplitHostPort(requestHost); err == nil {
+		requestHost = h
+	}
+	if requestHost == "" {
+		return listenAddr
+	}
+	return net.JoinHostPort(requestHost, port)
+}
+
+// ListPortForwards 获取端口转发记录
+// @Summary 获取端口转发记录
+// @Description 分页获取端口转发审计记录，按数据权限过滤，活动中的隧道返回实时流量
+// @Tags 端口转发
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键字"
+// @Param status query string false "状态"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/port-forwards [get]
+func (s *HTTPServer) ListPortForwards(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	keyword := c.Query("keyword")
+	status := c.Query("status")
+
+	query := s.portForwardManager.db.Model(&assetbiz.PortForwardSession{}).
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "user_id"))
+	if keyword != "" {
+		query = query.Where("host_name LIKE ? OR host_ip LIKE ? OR username LIKE ?",
+			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败")
+		return
+	}
+
+	var list []*assetbiz.PortForwardSession
+	if err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&list).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败")
+		return
+	}
+
+	// 活动中的隧道使用内存中的实时流量
+	for _, item := range list {
+		if tunnel, ok := s.portForwardManager.Get(item.ID); ok {
+			item.BytesSent, item.BytesReceived, item.ConnCount = tunnel.Stats()
+		}
+	}
+
+	response.Success(c, gin.H{
+		"total": total,
+		"list":  list,
+	})
+}
+
+// ClosePortForward 关闭端口转发
+// @Summary 关闭端口转发
+// @Description 关闭自己创建的活动端口转发隧道
+// @Tags 端口转发
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "端口转发ID"
+// @Success 200 {object} response.Response "关闭成功"
+// @Router /api/v1/port-forwards/{id} [delete]
+func (s *HTTPServer) ClosePortForward(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的端口转发ID")
+		return
+	}
+
+	tunnel, ok := s.portForwardManager.Get(uint(id))
+	if !ok {
+		response.ErrorCode(c, http.StatusNotFound, "端口转发不存在或已关闭")
+		return
+	}
+	if tunnel.Record.UserID != rbacService.GetUserID(c) {
+		response.ErrorCode(c, http.StatusForbidden, "只能关闭自己创建的端口转发")
+		return
+	}
+
+	if err := s.portForwardManager.Close(uint(id), assetbiz.PortForwardStatusClosed, "用户关闭"); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "关闭成功", nil)
+}
@@ -27,14 +27,16 @@ import (
 	"net/http"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
-	"golang.org/x/crypto/ssh"
 	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	auditbiz "github.com/ydcloud-dy/opshub/internal/biz/audit"
 	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
 	"gorm.io/gorm"
 )
 
@@ -66,36 +68,91 @@ type CredentialInfo struct {
 
 // TerminalSession 终端会话
 type TerminalSession struct {
-	ID          string
-	HostID      uint
-	HostName    string
-	HostIP      string
-	UserID      uint
-	Username    string
-	SSHClient   *ssh.Client
-	SSHSession  *ssh.Session
-	StdinPipe   io.WriteCloser
-	StdoutPipe  io.Reader
-	StderrPipe  io.Reader
-	Recorder    *AsciinemaRecorder // 录制器
-	CreatedAt   time.Time
+	ID         string
+	HostID     uint
+	HostName   string
+	HostIP     string
+	UserID     uint
+	Username   string
+	SSHClient  *ssh.Client
+	SSHSession *ssh.Session
+	StdinPipe  io.WriteCloser
+	StdoutPipe io.Reader
+	StderrPipe io.Reader
+	Recorder   *AsciinemaRecorder // 录制器
+	CreatedAt  time.Time
+	Policy     *assetbiz.EffectiveSessionPolicy // 生效的会话策略
+
+	lastActive  atomic.Int64 // 最后一次用户输入的时间（UnixNano）
+	closeMu     sync.Mutex
+	closeReason string
+}
+
+// Touch 记录用户输入活动
+func (ts *TerminalSession) Touch() {
+	ts.lastActive.Store(time.Now().UnixNano())
+}
+
+// IdleFor 返回会话已空闲的时长
+func (ts *TerminalSession) IdleFor(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, ts.lastActive.Load()))
+}
+
+// SetCloseReason 记录会话结束原因，仅第一次设置生效
+func (ts *TerminalSession) SetCloseReason(reason string) {
+	ts.closeMu.Lock()
+	defer ts.closeMu.Unlock()
+	if ts.closeReason == "" {
+		ts.closeReason = reason
+	}
+}
+
+// CloseReason 获取会话结束原因
+func (ts *TerminalSession) CloseReason() string {
+	ts.closeMu.Lock()
+	defer ts.closeMu.Unlock()
+	return ts.closeReason
 }
 
 // TerminalManager 终端管理器
 type TerminalManager struct {
-	sessions    map[string]*TerminalSession
-	mu          sync.RWMutex
-	hostUseCase *assetbiz.HostUseCase
-	db          *gorm.DB
+	sessions     map[string]*TerminalSession
+	userSessions map[uint]int // 每个用户占用的会话数（含建立中的会话）
+	mu           sync.RWMutex
+	hostUseCase  *assetbiz.HostUseCase
+	db           *gorm.DB
 }
 
 // NewTerminalManager 创建终端管理器
 func NewTerminalManager(hostUseCase *assetbiz.HostUseCase, db *gorm.DB) *TerminalManager {
 	return &TerminalManager{
-		sessions:    make(map[string]*TerminalSession),
-		hostUseCase: hostUseCase,
-		db:          db,
+		sessions:     make(map[string]*TerminalSession),
+		userSessions: make(map[uint]int),
+		hostUseCase:  hostUseCase,
+		db:           db,
+	}
+}
+
+// acquireSlot 为用户占用一个会话名额，limit 为 0 表示不限制
+func (tm *TerminalManager) acquireSlot(userID uint, limit int) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if limit > 0 && tm.userSessions[userID] >= limit {
+		return false
+	}
+	tm.userSessions[userID]++
+	return true
+}
+
+// releaseSlot 释放用户占用的会话名额
+func (tm *TerminalManager) releaseSlot(userID uint) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.userSessions[userID] <= 1 {
+		delete(tm.userSessions, userID)
+		return
 	}
+	tm.userSessions[userID]--
 }
 
 // CreateSession 创建SSH会话
@@ -162,7 +219,7 @@ func (tm *TerminalManager) CreateSession(ctx context.Context, hostID uint, userI
 
 	// 设置终端模式
 	modes := ssh.TerminalModes{
-		ssh.ECHO:   1, // 启用回显
+		ssh.ECHO:          1,     // 启用回显
 		ssh.TTY_OP_ISPEED: 14400, // 输入速度
 		ssh.TTY_OP_OSPEED: 14400, // 输出速度
 	}
@@ -226,6 +283,7 @@ func (tm *TerminalManager) CreateSession(ctx context.Context, hostID uint, userI
 		Recorder:   recorder,
 		CreatedAt:  time.Now(),
 	}
+	terminalSession.Touch()
 
 	// 保存会话
 	tm.mu.Lock()
@@ -261,57 +319,58 @@ func (tm *TerminalManager) CloseSession(sessionID string) error {
 		zap.Uint("userID", session.UserID),
 		zap.String("username", session.Username))
 
-	// 关闭录制器并保存会话信息
+	closeReason := session.CloseReason()
+	if closeReason == "" {
+		closeReason = assetbiz.SessionCloseClient
+	}
+
+	// 关闭录制器并获取录制信息
+	duration := int(time.Since(session.CreatedAt).Seconds())
+	var fileSize int64
+	var recordingPath string
 	if session.Recorder != nil {
-		// 关闭录制器
 		if err := session.Recorder.Close(); err != nil {
 			appLogger.Error("关闭录制器失败", zap.Error(err))
 		}
-
-		// 获取录制信息
-		duration := session.Recorder.GetDuration()
-		fileSize := session.Recorder.GetFileSize()
-		recordingPath := session.Recorder.GetRecordingPath()
+		duration = session.Recorder.GetDuration()
+		fileSize = session.Recorder.GetFileSize()
+		recordingPath = session.Recorder.GetRecordingPath()
 
 		appLogger.Info("录制信息",
 			zap.String("recordingPath", recordingPath),
 			zap.Int("duration", duration),
 			zap.Int64("fileSize", fileSize))
+	} else {
+		appLogger.Warn("会话没有录制器", zap.String("sessionID", sessionID))
+	}
 
-		// 保存会话记录到数据库
-		terminalSession := &assetbiz.TerminalSession{
-			HostID:        session.HostID,
-			HostName:      session.HostName,
-			HostIP:        session.HostIP,
-			UserID:        session.UserID,
-			Username:      session.Username,
-			RecordingPath: recordingPath,
-			Duration:      duration,
-			FileSize:      fileSize,
-			Status:        "completed",
-		}
+	// 保存会话记录到数据库
+	terminalSession := &assetbiz.TerminalSession{
+		HostID:        session.HostID,
+		HostName:      session.HostName,
+		HostIP:        session.HostIP,
+		UserID:        session.UserID,
+		Username:      session.Username,
+		RecordingPath: recordingPath,
+		Duration:      duration,
+		FileSize:      fileSize,
+		Status:        "completed",
+		CloseReason:   closeReason,
+	}
 
-		appLogger.Info("准备保存终端会话记录到数据库",
-			zap.Uint("hostID", terminalSession.HostID),
-			zap.String("hostName", terminalSession.HostName),
-			zap.Uint("userID", terminalSession.UserID),
-			zap.String("username", terminalSession.Username))
-
-		if err := tm.db.Create(terminalSession).Error; err != nil {
-			appLogger.Error("保存终端会话记录失败",
-				zap.Error(err),
-				zap.Uint("hostID", session.HostID),
-				zap.Uint("userID", session.UserID),
-				zap.String("recordingPath", recordingPath))
-		} else {
-			appLogger.Info("终端会话记录已成功保存到数据库",
-				zap.Uint("sessionID", terminalSession.ID),
-				zap.String("username", terminalSession.Username),
-				zap.String("hostName", terminalSession.HostName),
-				zap.Int("duration", duration))
-		}
+	if err := tm.db.Create(terminalSession).Error; err != nil {
+		appLogger.Error("保存终端会话记录失败",
+			zap.Error(err),
+			zap.Uint("hostID", session.HostID),
+			zap.Uint("userID", session.UserID),
+			zap.String("recordingPath", recordingPath))
 	} else {
-		appLogger.Warn("会话没有录制器", zap.String("sessionID", sessionID))
+		appLogger.Info("终端会话记录已成功保存到数据库",
+			zap.Uint("sessionID", terminalSession.ID),
+			zap.String("username", terminalSession.Username),
+			zap.String("hostName", terminalSession.HostName),
+			zap.Int("duration", duration),
+			zap.String("closeReason", closeReason))
 	}
 
 	// 关闭SSH连接
@@ -323,6 +382,11 @@ func (tm *TerminalManager) CloseSession(sessionID string) error {
 	}
 
 	delete(tm.sessions, sessionID)
+	if tm.userSessions[session.UserID] <= 1 {
+		delete(tm.userSessions, session.UserID)
+	} else {
+		tm.userSessions[session.UserID]--
+	}
 	appLogger.Info("终端会话已关闭", zap.String("sessionID", sessionID))
 	return nil
 }
@@ -395,13 +459,33 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 		return nil
 	})
 
+	// 会话策略：访问时段和并发会话数
+	policy, err := s.terminalManager.ResolvePolicy(c.Request.Context(), uid, uint(hostId))
+	if err != nil {
+		appLogger.Error("获取会话策略失败", zap.Error(err), zap.Int("hostId", hostId))
+		conn.WriteMessage(websocket.TextMessage, []byte("连接失败: 获取会话策略失败\r\n"))
+		return
+	}
+	if !policy.AllowedAt(time.Now()) {
+		conn.WriteMessage(websocket.TextMessage, []byte("连接被拒绝: 当前不在会话策略允许的访问时段内\r\n"))
+		s.terminalManager.RecordRejected(c.Request.Context(), uint(hostId), uid, uname, assetbiz.SessionCloseOutsideWindow)
+		return
+	}
+	if !s.terminalManager.acquireSlot(uid, policy.MaxConcurrent) {
+		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("连接被拒绝: 已达到最大并发会话数 %d\r\n", policy.MaxConcurrent)))
+		s.terminalManager.RecordRejected(c.Request.Context(), uint(hostId), uid, uname, assetbiz.SessionCloseConcurrent)
+		return
+	}
+
 	// 创建SSH会话
 	session, err := s.terminalManager.CreateSession(c.Request.Context(), uint(hostId), uid, uname, uint16(cols), uint16(rows))
 	if err != nil {
+		s.terminalManager.releaseSlot(uid)
 		appLogger.Error("SSH会话创建失败", zap.Error(err), zap.Int("hostId", hostId))
 		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("连接失败: %s\r\n", err.Error())))
 		return
 	}
+	session.Policy = policy
 
 	// 确保会话被关闭 - 使用显式调用而不是 defer
 	sessionClosed := false
@@ -415,11 +499,42 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 
 	appLogger.Info("SSH会话创建成功", zap.String("sessionID", session.ID), zap.Int("hostId", hostId))
 
+	// WebSocket 不支持并发写，输出和策略提示共用一把锁
+	var writeMu sync.Mutex
+	writeWS := func(data []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.WriteMessage(websocket.BinaryMessage, data)
+	}
+
+	// 终止会话：记录原因后关闭SSH和WebSocket，读循环随之退出
+	terminate := func(reason string) {
+		session.SetCloseReason(reason)
+		if session.SSHSession != nil {
+			session.SSHSession.Close()
+		}
+		if session.SSHClient != nil {
+			session.SSHClient.Close()
+		}
+		conn.Close()
+	}
+
+	// 按会话策略检查空闲、时长和访问时段
+	policyDone := make(chan struct{})
+	defer close(policyDone)
+	go s.terminalManager.enforcePolicy(session, func(msg string) {
+		banner := []byte("\r\n\x1b[1;33m[会话策略] " + msg + "\x1b[0m\r\n")
+		if session.Recorder != nil {
+			session.Recorder.RecordOutput(banner)
+		}
+		writeWS(banner)
+	}, terminate, policyDone)
+
 	// 启动goroutine从SSH读取输出并发送到WebSocket
 	var wg sync.WaitGroup
 	wg.Add(2)
 
-	// 读取stdout
+	// 读取stdout，远端 shell 退出后结束会话
 	go func() {
 		defer wg.Done()
 		buf := make([]byte, 1024)
@@ -431,9 +546,10 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 					session.Recorder.RecordOutput(buf[:n])
 				}
 				// 使用二进制消息以保留原始字节（包括CR/LF控制字符）
-				conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+				writeWS(buf[:n])
 			}
 			if err != nil {
+				terminate(assetbiz.SessionCloseShellExit)
 				return
 			}
 		}
@@ -451,7 +567,7 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 					session.Recorder.RecordOutput(buf[:n])
 				}
 				// 使用二进制消息以保留原始字节（包括CR/LF控制字符）
-				conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+				writeWS(buf[:n])
 			}
 			if err != nil {
 				return
@@ -459,6 +575,10 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 		}
 	}()
 
+	// 按回车切分输入，每条命令发布一个审计事件
+	var commands auditbiz.CommandBuffer
+	clientIP, userAgent := c.ClientIP(), c.Request.UserAgent()
+
 	// 处理来自WebSocket的消息并发送到SSH
 	for {
 		// 每次读取前更新超时时间
@@ -467,6 +587,7 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 		messageType, data, err := conn.ReadMessage()
 		if err != nil {
 			appLogger.Info("WebSocket连接关闭", zap.String("sessionID", session.ID), zap.Error(err))
+			session.SetCloseReason(assetbiz.SessionCloseClient)
 			// 立即关闭SSH连接，让所有阻塞的Read操作返回
 			if session.SSHSession != nil {
 				session.SSHSession.Close()
@@ -494,16 +615,20 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 				}
 			}
 			// 如果不是resize命令，当作普通输入发送到SSH
+			session.Touch()
 			// 录制输入
 			if session.Recorder != nil {
 				session.Recorder.RecordInput(data)
 			}
+			publishTerminalCommands(session, commands.Feed(data), clientIP, userAgent)
 			session.StdinPipe.Write(data)
 		} else if messageType == websocket.BinaryMessage {
+			session.Touch()
 			// 录制输入
 			if session.Recorder != nil {
 				session.Recorder.RecordInput(data)
 			}
+			publishTerminalCommands(session, commands.Feed(data), clientIP, userAgent)
 			session.StdinPipe.Write(data)
 		}
 	}
@@ -512,6 +637,27 @@ func (s *HTTPServer) HandleSSHConnection(c *gin.Context) {
 	appLogger.Info("终端会话结束", zap.String("sessionID", session.ID))
 }
 
+// publishTerminalCommands 发布终端命令审计事件
+func publishTerminalCommands(session *TerminalSession, commands []string, ip, userAgent string) {
+	for _, command := range commands {
+		auditbiz.PublishEvent(&auditbiz.AuditEvent{
+			Type:      auditbiz.EventTypeTerminalCommand,
+			UserID:    session.UserID,
+			Username:  session.Username,
+			IP:        ip,
+			UserAgent: userAgent,
+			Action:    "command",
+			Target:    session.HostName + "(" + session.HostIP + ")",
+			Detail: map[string]interface{}{
+				"hostId":    session.HostID,
+				"hostIp":    session.HostIP,
+				"sessionId": session.ID,
+				"command":   command,
+			},
+		})
+	}
+}
+
 // ResizeTerminal 调整终端大小
 func (s *HTTPServer) ResizeTerminal(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "终端大小调整功能待实现"})
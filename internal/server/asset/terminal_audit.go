@@ -27,6 +27,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"github.com/ydcloud-dy/opshub/pkg/response"
 	"gorm.io/gorm"
 )
@@ -69,8 +70,10 @@ func (h *TerminalAuditHandler) ListTerminalSessions(c *gin.Context) {
 		pageSize = 10
 	}
 
-	// 构建查询
-	query := h.db.Model(&assetbiz.TerminalSession{})
+	// 构建查询，按数据权限只返回可见部门内用户的会话
+	ctx := c.Request.Context()
+	query := h.db.WithContext(ctx).Model(&assetbiz.TerminalSession{}).
+		Scopes(rbacbiz.ScopeByDataPermission(ctx, "user_id"))
 
 	// 搜索关键词
 	if keyword != "" {
@@ -99,20 +102,22 @@ func (h *TerminalAuditHandler) ListTerminalSessions(c *gin.Context) {
 	list := make([]*assetbiz.TerminalSessionInfo, 0, len(sessions))
 	for _, session := range sessions {
 		info := &assetbiz.TerminalSessionInfo{
-			ID:            session.ID,
-			HostID:        session.HostID,
-			HostName:      session.HostName,
-			HostIP:        session.HostIP,
-			UserID:        session.UserID,
-			Username:      session.Username,
-			Duration:      session.Duration,
-			DurationText:  formatDuration(session.Duration),
-			FileSize:      session.FileSize,
-			FileSizeText:  formatFileSize(session.FileSize),
-			Status:        session.Status,
-			StatusText:    getStatusText(session.Status),
-			CreatedAt:     session.CreatedAt,
-			CreatedAtText: session.CreatedAt.Format("2006-01-02 15:04:05"),
+			ID:              session.ID,
+			HostID:          session.HostID,
+			HostName:        session.HostName,
+			HostIP:          session.HostIP,
+			UserID:          session.UserID,
+			Username:        session.Username,
+			Duration:        session.Duration,
+			DurationText:    formatDuration(session.Duration),
+			FileSize:        session.FileSize,
+			FileSizeText:    formatFileSize(session.FileSize),
+			Status:          session.Status,
+			StatusText:      getStatusText(session.Status),
+			CloseReason:     session.CloseReason,
+			CloseReasonText: getCloseReasonText(session.CloseReason),
+			CreatedAt:       session.CreatedAt,
+			CreatedAtText:   session.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 		list = append(list, info)
 	}
@@ -165,49 +170,6 @@ func (h *TerminalAuditHandler) PlayTerminalSession(c *gin.Context) {
 	c.String(http.StatusOK, string(content))
 }
 
-// DeleteTerminalSession 删除终端会话
-// @Summary 删除终端会话
-// @Description 删除指定的终端会话记录及其录制文件
-// @Tags 终端审计
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param id path int true "会话ID"
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 404 {object} response.Response "会话不存在"
-// @Router /api/v1/terminal-sessions/{id} [delete]
-func (h *TerminalAuditHandler) DeleteTerminalSession(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "无效的会话ID")
-		return
-	}
-
-	// 查询会话
-	var session assetbiz.TerminalSession
-	if err := h.db.First(&session, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			response.ErrorCode(c, http.StatusNotFound, "会话不存在")
-		} else {
-			response.ErrorCode(c, http.StatusInternalServerError, "查询失败")
-		}
-		return
-	}
-
-	// 删除录制文件
-	// 即使文件删除失败，仍然继续删除数据库记录
-	_ = os.Remove(session.RecordingPath)
-
-	// 删除数据库记录
-	if err := h.db.Delete(&session).Error; err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败")
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
-
 // formatDuration 格式化时长
 func formatDuration(seconds int) string {
 	if seconds < 60 {
@@ -240,12 +202,29 @@ func formatFileSize(size int64) string {
 // getStatusText 获取状态文本
 func getStatusText(status string) string {
 	statusMap := map[string]string{
-		"recording":  "录制中",
-		"completed":  "已完成",
-		"failed":     "失败",
+		"recording": "录制中",
+		"completed": "已完成",
+		"failed":    "失败",
+		"rejected":  "已拒绝",
 	}
 	if text, ok := statusMap[status]; ok {
 		return text
 	}
 	return status
 }
+
+// getCloseReasonText 获取会话结束原因文本
+func getCloseReasonText(reason string) string {
+	reasonMap := map[string]string{
+		assetbiz.SessionCloseClient:        "用户断开",
+		assetbiz.SessionCloseShellExit:     "远端退出",
+		assetbiz.SessionCloseIdleTimeout:   "空闲超时",
+		assetbiz.SessionCloseMaxDuration:   "超过最长时长",
+		assetbiz.SessionCloseOutsideWindow: "不在允许的访问时段",
+		assetbiz.SessionCloseConcurrent:    "超过并发会话数",
+	}
+	if text, ok := reasonMap[reason]; ok {
+		return text
+	}
+	return reason
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sessionPolicyCheckInterval 会话策略检查间隔
+const sessionPolicyCheckInterval = time.Second
+
+// sessionPolicyNotice 到达时长上限或访问时段结束前的提前提醒时间
+const sessionPolicyNotice = time.Minute
+
+// ResolvePolicy 获取用户连接指定主机时生效的会话策略（用户角色策略 + 主机所属分组策略）
+func (tm *TerminalManager) ResolvePolicy(ctx context.Context, userID, hostID uint) (*assetbiz.EffectiveSessionPolicy, error) {
+	db := tm.db.WithContext(ctx)
+
+	var groupID uint
+	if err := db.Table("hosts").Select("group_id").Where("id = ?", hostID).Scan(&groupID).Error; err != nil {
+		return nil, err
+	}
+
+	var roleIDs []uint
+	if err := db.Table("sys_user_role").Where("user_id = ?", userID).Pluck("role_id", &roleIDs).Error; err != nil {
+		return nil, err
+	}
+
+	query := db.Where("enabled = ?", true)
+	if len(roleIDs) > 0 {
+		query = query.Where("(scope_type = ? AND scope_id IN ?) OR (scope_type = ? AND scope_id = ?)",
+			assetbiz.SessionPolicyScopeRole, roleIDs, assetbiz.SessionPolicyScopeGroup, groupID)
+	} else {
+		query = query.Where("scope_type = ? AND scope_id = ?", assetbiz.SessionPolicyScopeGroup, groupID)
+	}
+
+	var policies []*assetbiz.TerminalSessionPolicy
+	if err := query.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return assetbiz.MergeSessionPolicies(policies), nil
+}
+
+// RecordRejected 记录被会话策略拒绝的连接
+func (tm *TerminalManager) RecordRejected(ctx context.Context, hostID, userID uint, username, reason string) {
+	record := &assetbiz.TerminalSession{
+		HostID:      hostID,
+		UserID:      userID,
+		Username:    username,
+		Status:      "rejected",
+		CloseReason: reason,
+	}
+	if hostVO, err := tm.hostUseCase.GetByID(ctx, hostID); err == nil {
+		record.HostName = hostVO.Name
+		record.HostIP = hostVO.IP
+	}
+	if err := tm.db.Create(record).Error; err != nil {
+		appLogger.Error("保存被拒绝的终端会话记录失败", zap.Error(err), zap.Uint("hostID", hostID), zap.Uint("userID", userID))
+	}
+}
+
+// enforcePolicy 按会话策略检查空闲超时、最长时长和访问时段，到达限制前发出提醒，到达后终止会话
+func (tm *TerminalManager) enforcePolicy(session *TerminalSession, notify func(msg string), terminate func(reason string), done <-chan struct{}) {
+	policy := session.Policy
+	if policy == nil || len(policy.Policies) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(sessionPolicyCheckInterval)
+	defer ticker.Stop()
+
+	var idleWarned, durationWarned, windowWarned bool
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if policy.IdleTimeout > 0 {
+				idle := session.IdleFor(now)
+				if idle >= policy.IdleTimeout {
+					notify(fmt.Sprintf("会话空闲超过 %s，连接已断开", policy.IdleTimeout))
+					terminate(assetbiz.SessionCloseIdleTimeout)
+					return
+				}
+				if policy.IdleWarning > 0 && idle >= policy.IdleTimeout-policy.IdleWarning {
+					if !idleWarned {
+						notify(fmt.Sprintf("会话已空闲，将在 %d 秒后断开，输入任意内容以保持连接", int((policy.IdleTimeout - idle).Seconds())))
+						idleWarned = true
+					}
+				} else {
+					idleWarned = false
+				}
+			}
+
+			if policy.MaxDuration > 0 {
+				elapsed := now.Sub(session.CreatedAt)
+				if elapsed >= policy.MaxDuration {
+					notify(fmt.Sprintf("会话已达到最长时长 %s，连接已断开", policy.MaxDuration))
+					terminate(assetbiz.SessionCloseMaxDuration)
+					return
+				}
+				if !durationWarned && elapsed >= policy.MaxDuration-sessionPolicyNotice {
+					notify("会话即将达到最长时长，将在 1 分钟内断开")
+					durationWarned = true
+				}
+			}
+
+			if !policy.AllowedAt(now) {
+				notify("已超出允许的访问时段，连接已断开")
+				terminate(assetbiz.SessionCloseOutsideWindow)
+				return
+			}
+			if !windowWarned && !policy.AllowedAt(now.Add(sessionPolicyNotice)) {
+				notify("即将超出允许的访问时段，将在 1 分钟内断开")
+				windowWarned = true
+			}
+		}
+	}
+}
+
+// TerminalPolicyHandler 终端会话策略处理器
+type TerminalPolicyHandler struct {
+	db *gorm.DB
+}
+
+// NewTerminalPolicyHandler 创建终端会话策略处理器
+func NewTerminalPolicyHandler(db *gorm.DB) *TerminalPolicyHandler {
+	return &TerminalPolicyHandler{db: db}
+}
+
+// ListPolicies 获取会话策略列表
+// @Summary 获取终端会话策略列表
+// @Description 分页获取终端会话策略
+// @Tags 终端审计
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param scopeType query string false "作用范围 role/group"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/terminal-policies [get]
+func (h *TerminalPolicyHandler) ListPolicies(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	query := h.db.Model(&assetbiz.TerminalSessionPolicy{})
+	if scopeType := c.Query("scopeType"); scopeType != "" {
+		query = query.Where("scope_type = ?", scopeType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败")
+		return
+	}
+
+	var policies []*assetbiz.TerminalSessionPolicy
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&policies).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"total": total,
+		"list":  policies,
+	})
+}
+
+// CreatePolicy 创建会话策略
+// @Summary 创建终端会话策略
+// @Description 为角色或资产分组创建会话策略：空闲超时、最长时长、并发会话数和允许的访问时段
+// @Tags 终端审计
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body asset.TerminalSessionPolicyRequest true "策略信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/terminal-policies [post]
+func (h *TerminalPolicyHandler) CreatePolicy(c *gin.Context) {
+	var req assetbiz.TerminalSessionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	policy, err := req.ToModel()
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.db.Create(policy).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "创建失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", policy)
+}
+
+// UpdatePolicy 更新会话策略
+// @Summary 更新终端会话策略
+// @Description 更新指定的终端会话策略，对新建立的会话生效
+// @Tags 终端审计
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "策略ID"
+// @Param body body asset.TerminalSessionPolicyRequest true "策略信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/terminal-policies/{id} [put]
+func (h *TerminalPolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的策略ID")
+		return
+	}
+
+	var existing assetbiz.TerminalSessionPolicy
+	if err := h.db.First(&existing, id).Error; err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "策略不存在")
+		return
+	}
+
+	var req assetbiz.TerminalSessionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	policy, err := req.ToModel()
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+
+	if err := h.db.Save(policy).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", policy)
+}
+
+// DeletePolicy 删除会话策略
+// @Summary 删除终端会话策略
+// @Description 删除指定的终端会话策略
+// @Tags 终端审计
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "策略ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/terminal-policies/{id} [delete]
+func (h *TerminalPolicyHandler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的策略ID")
+		return
+	}
+
+	if err := h.db.Delete(&assetbiz.TerminalSessionPolicy{}, id).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultCheckpointInterval 未配置时生成签名检查点的间隔
+const defaultCheckpointInterval = time.Hour
+
+// AuditChains 需要哈希链保护的审计表，插件的表按表名登记
+func AuditChains() []audit.AuditChain {
+	return []audit.AuditChain{
+		{Name: audit.ChainOperationLog, Table: "sys_operation_log", AddedFields: []string{"route", "resource_type", "resource_id"}},
+		// 登出时回写登出时间
+		{Name: audit.ChainLoginLog, Table: "sys_login_log", ExcludeFields: []string{"logout_time"}},
+		{Name: audit.ChainDataLog, Table: "sys_data_log"},
+		{Name: audit.ChainTerminalSession, Table: "ssh_terminal_sessions"},
+		{Name: audit.ChainK8sTerminalSession, Table: "k8s_terminal_sessions"},
+	}
+}
+
+// RegisterAuditChain 注册审计链回调，此后写入的审计记录都会入链
+func RegisterAuditChain(db *gorm.DB) error {
+	return auditdata.RegisterAuditChainCallbacks(db, AuditChains())
+}
+
+// NewAuditChainUseCase 创建审计链用例，服务和命令行校验共用
+func NewAuditChainUseCase(db *gorm.DB, signingKey string) *audit.AuditChainUseCase {
+	return audit.NewAuditChainUseCase(auditdata.NewAuditChainRepo(db), AuditChains(), signingKey)
+}
+
+// StartAuditCheckpoint 启动定时任务，为有新记录的审计链生成签名检查点
+func StartAuditCheckpoint(ctx context.Context, uc *audit.AuditChainUseCase, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				created, err := uc.Checkpoint(ctx)
+				if err != nil {
+					appLogger.Error("生成审计链检查点失败", zap.Error(err))
+					continue
+				}
+				if created > 0 {
+					appLogger.Info("已生成审计链检查点", zap.Int("count", created))
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	"gorm.io/gorm"
+)
+
+// DataChangeTables 记录数据变更的表，插件的表按表名登记，不需要引用插件的模型
+func DataChangeTables() []audit.DataChangeTable {
+	return []audit.DataChangeTable{
+		{
+			Table:      "sys_user",
+			MaskFields: []string{"password"},
+			// 登录时间和失败计数每次登录都会变化，不作为数据变更
+			IgnoreFields: []string{"updated_at", "last_login_at", "login_failures", "login_failed_at"},
+		},
+		{Table: "sys_role", IgnoreFields: []string{"updated_at"}},
+		{Table: "sys_department", IgnoreFields: []string{"updated_at"}},
+		{Table: "sys_menu", IgnoreFields: []string{"updated_at"}},
+		{Table: "sys_position", IgnoreFields: []string{"updated_at"}},
+		{
+			Table: "hosts",
+			// 采集任务定期写入的系统信息和监控指标
+			IgnoreFields: []string{
+				"updated_at", "status", "last_seen", "os", "kernel", "arch", "hostname", "uptime",
+				"cpu_info", "cpu_cores", "cpu_usage", "memory_total", "memory_used", "memory_usage",
+				"disk_total", "disk_used", "disk_usage",
+			},
+		},
+		{
+			Table:        "credentials",
+			MaskFields:   []string{"password", "private_key", "passphrase"},
+			IgnoreFields: []string{"updated_at"},
+		},
+		{
+			Table:        "cloud_accounts",
+			MaskFields:   []string{"access_key", "secret_key"},
+			IgnoreFields: []string{"updated_at"},
+		},
+		{
+			Table:        "asset_databases",
+			IgnoreFields: []string{"updated_at", "status", "last_seen"},
+		},
+		{
+			Table:        "k8s_clusters",
+			MaskFields:   []string{"kube_config"},
+			IgnoreFields: []string{"updated_at", "status", "version", "node_count", "pod_count", "status_synced_at"},
+		},
+		{
+			Table:        "ssl_certificates",
+			MaskFields:   []string{"private_key"},
+			IgnoreFields: []string{"updated_at"},
+		},
+		{Table: "alert_configs", IgnoreFields: []string{"updated_at"}},
+		{
+			Table:        "alert_channels",
+			MaskFields:   []string{"config"},
+			IgnoreFields: []string{"updated_at"},
+		},
+	}
+}
+
+// RegisterDataChangeAudit 为需要审计的表注册数据变更回调
+func RegisterDataChangeAudit(db *gorm.DB) error {
+	return auditdata.RegisterDataChangeCallbacks(db, DataChangeTables())
+}
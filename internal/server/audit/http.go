@@ -25,20 +25,32 @@ import (
 )
 
 type HTTPService struct {
-	operationLogService *audit.OperationLogService
-	loginLogService     *audit.LoginLogService
-	dataLogService      *audit.DataLogService
+	operationLogService  *audit.OperationLogService
+	loginLogService      *audit.LoginLogService
+	dataLogService       *audit.DataLogService
+	integrityService     *audit.IntegrityService
+	retentionService     *audit.RetentionService
+	securityEventService *audit.SecurityEventService
+	timelineService      *audit.TimelineService
 }
 
 func NewHTTPService(
 	operationLogService *audit.OperationLogService,
 	loginLogService *audit.LoginLogService,
 	dataLogService *audit.DataLogService,
+	integrityService *audit.IntegrityService,
+	retentionService *audit.RetentionService,
+	securityEventService *audit.SecurityEventService,
+	timelineService *audit.TimelineService,
 ) *HTTPService {
 	return &HTTPService{
-		operationLogService: operationLogService,
-		loginLogService:     loginLogService,
-		dataLogService:      dataLogService,
+		operationLogService:  operationLogService,
+		loginLogService:      loginLogService,
+		dataLogService:       dataLogService,
+		integrityService:     integrityService,
+		retentionService:     retentionService,
+		securityEventService: securityEventService,
+		timelineService:      timelineService,
 	}
 }
 
@@ -51,27 +63,62 @@ func (s *HTTPService) RegisterRoutes(r *gin.RouterGroup) {
 		operationLogs := audit.Group("/operation-logs")
 		{
 			operationLogs.GET("", s.operationLogService.ListOperationLogs)
+			operationLogs.GET("/export", s.operationLogService.ExportOperationLogs)
 			operationLogs.GET("/:id", s.operationLogService.GetOperationLog)
-			operationLogs.DELETE("/:id", s.operationLogService.DeleteOperationLog)
-			operationLogs.POST("/batch-delete", s.operationLogService.DeleteOperationLogsBatch)
 		}
 
 		// 登录日志路由
 		loginLogs := audit.Group("/login-logs")
 		{
 			loginLogs.GET("", s.loginLogService.ListLoginLogs)
+			loginLogs.GET("/export", s.loginLogService.ExportLoginLogs)
 			loginLogs.GET("/:id", s.loginLogService.GetLoginLog)
-			loginLogs.DELETE("/:id", s.loginLogService.DeleteLoginLog)
-			loginLogs.POST("/batch-delete", s.loginLogService.DeleteLoginLogsBatch)
 		}
 
 		// 数据日志路由
 		dataLogs := audit.Group("/data-logs")
 		{
 			dataLogs.GET("", s.dataLogService.ListDataLogs)
+			dataLogs.GET("/export", s.dataLogService.ExportDataLogs)
 			dataLogs.GET("/:id", s.dataLogService.GetDataLog)
-			dataLogs.DELETE("/:id", s.dataLogService.DeleteDataLog)
-			dataLogs.POST("/batch-delete", s.dataLogService.DeleteDataLogsBatch)
+		}
+
+		// 审计完整性校验
+		audit.GET("/integrity/verify", s.integrityService.VerifyIntegrity)
+
+		// 保留策略路由
+		retentionPolicies := audit.Group("/retention-policies")
+		{
+			retentionPolicies.GET("", s.retentionService.ListRetentionPolicies)
+			retentionPolicies.PUT("/:logType", s.retentionService.UpdateRetentionPolicy)
+			retentionPolicies.POST("/:logType/run", s.retentionService.RunRetentionPolicy)
+		}
+
+		// 归档路由
+		archives := audit.Group("/archives")
+		{
+			archives.GET("", s.retentionService.ListArchives)
+			archives.GET("/:id/download", s.retentionService.DownloadArchive)
+			archives.POST("/:id/import", s.retentionService.ImportArchive)
+			archives.DELETE("/:id/import", s.retentionService.UnloadArchive)
+			archives.GET("/:id/records", s.retentionService.ListArchiveRecords)
+		}
+
+		// 安全事件路由
+		securityEvents := audit.Group("/security-events")
+		{
+			securityEvents.GET("", s.securityEventService.ListSecurityEvents)
+			securityEvents.GET("/stats", s.securityEventService.GetSecurityEventStats)
+			securityEvents.GET("/:id", s.securityEventService.GetSecurityEvent)
+			securityEvents.PUT("/:id/handle", s.securityEventService.HandleSecurityEvent)
+		}
+
+		// 活动时间线路由
+		timeline := audit.Group("/timeline")
+		{
+			timeline.GET("", s.timelineService.GetTimeline)
+			timeline.GET("/sources", s.timelineService.ListTimelineSources)
+			timeline.GET("/export", s.timelineService.ExportTimeline)
 		}
 	}
 }
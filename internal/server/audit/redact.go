@@ -0,0 +1,44 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// NewRedactor 根据配置创建操作日志脱敏引擎，自定义规则有误时仅使用内置规则
+func NewRedactor(cfg conf.AuditRedactionConfig) *audit.Redactor {
+	rules := audit.RedactionRules{
+		Fields:     cfg.Fields,
+		SkipRoutes: cfg.SkipRoutes,
+	}
+	for _, p := range cfg.Patterns {
+		rules.Patterns = append(rules.Patterns, audit.RedactionPattern{Pattern: p.Pattern, Replacement: p.Replacement})
+	}
+	redactor, err := audit.NewRedactor(rules)
+	if err != nil {
+		appLogger.Error("操作日志脱敏配置无效，仅使用内置规则", zap.Error(err))
+		return audit.NewDefaultRedactor()
+	}
+	return redactor
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultRetentionInterval 未配置时执行保留策略的间隔
+const defaultRetentionInterval = 24 * time.Hour
+
+// NewAuditRetentionUseCase 创建审计日志保留用例并补齐默认策略
+// 归档存储配置有误时仍可查看策略和归档，执行时返回配置错误
+func NewAuditRetentionUseCase(db *gorm.DB, cfg conf.AuditArchiveConfig, signingKey string) *audit.AuditRetentionUseCase {
+	storage, err := auditdata.NewArchiveStorage(cfg)
+	if err != nil {
+		appLogger.Error("审计日志归档存储配置无效", zap.Error(err))
+		storage = auditdata.NewUnavailableArchiveStorage(cfg.Storage, err)
+	}
+	uc := audit.NewAuditRetentionUseCase(auditdata.NewAuditRetentionRepo(db), auditdata.NewAuditChainRepo(db), AuditChains(), storage, signingKey)
+	if err := uc.EnsureDefaultPolicies(context.Background()); err != nil {
+		appLogger.Error("初始化审计日志保留策略失败", zap.Error(err))
+	}
+	return uc
+}
+
+// StartAuditRetention 启动定时任务，按启用的保留策略归档并清理过期审计日志
+func StartAuditRetention(ctx context.Context, uc *audit.AuditRetentionUseCase, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				uc.RunAll(ctx)
+			}
+		}
+	}()
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/plugin"
+)
+
+// pluginRoutePrefix 插件路由组前缀
+const pluginRoutePrefix = "/api/v1/plugins"
+
+// 操作日志模块，与菜单结构对应
+const (
+	moduleSystem   = "系统管理"
+	moduleProfile  = "个人信息"
+	moduleAsset    = "资产管理"
+	moduleAudit    = "操作审计"
+	modulePlugin   = "插件管理"
+	moduleIdentity = "身份认证"
+)
+
+// resource 同一模块下同一类资源的接口
+type resource struct {
+	module string
+	typ    string
+}
+
+// route 构造接口审计元数据
+func (r resource) route(method, path, action, description, resourceID string) audit.RouteAudit {
+	return audit.RouteAudit{
+		Method:       method,
+		Path:         path,
+		Module:       r.module,
+		Action:       action,
+		Description:  description,
+		ResourceType: r.typ,
+		ResourceID:   resourceID,
+	}
+}
+
+// crud 标准的新增、修改、删除接口，新增时从响应中取资源ID
+func (r resource) crud(base, name, nameField string) []audit.RouteAudit {
+	return []audit.RouteAudit{
+		r.route("POST", base, audit.ActionCreate, "创建"+name+" {body."+nameField+"}", "resp.data.id"),
+		r.route("PUT", base+"/:id", audit.ActionUpdate, "更新"+name+" #{path.id} {body."+nameField+"}", "path.id"),
+		r.route("DELETE", base+"/:id", audit.ActionDelete, "删除"+name+" #{path.id}", "path.id"),
+	}
+}
+
+// PluginAuditRoutes 将插件声明的审计元数据转换为接口审计元数据，接口路径补全插件路由组前缀
+func PluginAuditRoutes(configs []plugin.AuditRouteConfig) []audit.RouteAudit {
+	routes := make([]audit.RouteAudit, 0, len(configs))
+	for _, cfg := range configs {
+		routes = append(routes, audit.RouteAudit{
+			Method:       cfg.Method,
+			Path:         pluginRoutePrefix + cfg.Path,
+			Module:       cfg.Module,
+			Action:       cfg.Action,
+			Description:  cfg.Description,
+			ResourceType: cfg.ResourceType,
+			ResourceID:   cfg.ResourceID,
+		})
+	}
+	return routes
+}
+
+// CoreAuditRoutes 系统内置接口的审计元数据
+// 查询类接口只登记导出、下载和终端等需要追溯的操作，其余按路径推断
+func CoreAuditRoutes() []audit.RouteAudit {
+	var routes []audit.RouteAudit
+	add := func(rs ...audit.RouteAudit) { routes = append(routes, rs...) }
+
+	// 登录认证
+	session := resource{moduleIdentity, "session"}
+	add(
+		session.route("POST", "/api/v1/public/login", audit.ActionLogin, "用户登录 {body.username}", "body.username"),
+		session.route("POST", "/api/v1/public/login/mfa", audit.ActionLogin, "二次验证登录", ""),
+		session.route("POST", "/api/v1/public/login/mfa/enroll", audit.ActionLogin, "登录时绑定二次验证", ""),
+		session.route("POST", "/api/v1/public/login/password", audit.ActionUpdate, "登录时修改过期密码", ""),
+		session.route("POST", "/api/v1/public/token/refresh", audit.ActionLogin, "刷新访问令牌", ""),
+		session.route("POST", "/api/v1/logout", audit.ActionLogout, "用户登出", ""),
+	)
+
+	// 个人信息
+	profile := resource{moduleProfile, "user"}
+	apiToken := resource{moduleProfile, "api_token"}
+	add(
+		profile.route("PUT", "/api/v1/profile/password", audit.ActionUpdate, "修改密码", ""),
+		profile.route("PUT", "/api/v1/profile/avatar", audit.ActionUpdate, "更新头像", ""),
+		profile.route("POST", "/api/v1/upload/avatar", audit.ActionUpload, "上传头像", ""),
+		profile.route("DELETE", "/api/v1/profile/sessions", audit.ActionRevoke, "注销其他登录会话", ""),
+		profile.route("DELETE", "/api/v1/profile/sessions/:id", audit.ActionRevoke, "注销登录会话 {path.id}", ""),
+		profile.route("POST", "/api/v1/profile/mfa/enroll", audit.ActionCreate, "开始绑定二次验证", ""),
+		profile.route("POST", "/api/v1/profile/mfa/confirm", audit.ActionCreate, "确认绑定二次验证", ""),
+		profile.route("POST", "/api/v1/profile/mfa/recovery-codes", audit.ActionUpdate, "重新生成恢复码", ""),
+		profile.route("DELETE", "/api/v1/profile/mfa", audit.ActionDelete, "关闭二次验证", ""),
+		apiToken.route("POST", "/api/v1/profile/api-tokens", audit.ActionCreate, "创建个人访问令牌 {body.name}", "resp.data.id"),
+		apiToken.route("DELETE", "/api/v1/profile/api-tokens/:id", audit.ActionRevoke, "吊销个人访问令牌 #{path.id}", "path.id"),
+	)
+
+	// 用户管理
+	user := resource{moduleSystem, "user"}
+	add(user.crud("/api/v1/users", "用户", "username")...)
+	add(
+		user.route("POST", "/api/v1/users/import", audit.ActionImport, "批量导入用户", ""),
+		user.route("GET", "/api/v1/users/export", audit.ActionExport, "导出用户", ""),
+		user.route("POST", "/api/v1/users/:id/roles", audit.ActionGrant, "分配用户角色 #{path.id} {body.roleIds}", "path.id"),
+		user.route("POST", "/api/v1/users/:id/positions", audit.ActionUpdate, "分配用户岗位 #{path.id} {body.positionIds}", "path.id"),
+		user.route("PUT", "/api/v1/users/:id/reset-password", audit.ActionUpdate, "重置用户密码 #{path.id}", "path.id"),
+		user.route("DELETE", "/api/v1/users/:id/mfa", audit.ActionUpdate, "重置用户二次验证 #{path.id}", "path.id"),
+		user.route("PUT", "/api/v1/users/:id/unlock", audit.ActionUpdate, "解锁用户 #{path.id}", "path.id"),
+		user.route("DELETE", "/api/v1/users/:id/sessions", audit.ActionRevoke, "注销用户全部会话 #{path.id}", "path.id"),
+		user.route("DELETE", "/api/v1/users/:id/sessions/:sessionId", audit.ActionRevoke, "注销用户会话 #{path.id} {path.sessionId}", "path.id"),
+		user.route("POST", "/api/v1/ldap/sync", audit.ActionSync, "同步 LDAP 用户", ""),
+	)
+
+	// 角色、菜单、部门、岗位
+	role := resource{moduleSystem, "role"}
+	menu := resource{moduleSystem, "menu"}
+	dept := resource{moduleSystem, "department"}
+	position := resource{moduleSystem, "position"}
+	add(role.crud("/api/v1/roles", "角色", "name")...)
+	add(menu.crud("/api/v1/menus", "菜单", "name")...)
+	add(dept.crud("/api/v1/departments", "部门", "name")...)
+	add(position.crud("/api/v1/positions", "岗位", "postName")...)
+	add(
+		role.route("POST", "/api/v1/roles/:id/menus", audit.ActionGrant, "分配角色权限 #{path.id}", "path.id"),
+		role.route("PUT", "/api/v1/roles/:id/mfa", audit.ActionUpdate, "设置角色二次验证要求 #{path.id}", "path.id"),
+		position.route("POST", "/api/v1/positions/:id/users", audit.ActionGrant, "岗位添加用户 #{path.id} {body.userIds}", "path.id"),
+		position.route("DELETE", "/api/v1/positions/:id/users/:userId", audit.ActionRevoke, "岗位移除用户 #{path.id} 用户 {path.userId}", "path.id"),
+	)
+
+	// 资产权限与访问申请
+	assetPermission := resource{moduleSystem, "asset_permission"}
+	assetGrant := resource{moduleSystem, "asset_grant"}
+	accessRequest := resource{moduleSystem, "access_request"}
+	add(
+		assetPermission.route("POST", "/api/v1/asset-permissions", audit.ActionGrant, "授予角色资产权限 角色 {body.roleId} 分组 {body.assetGroupId}", "body.roleId"),
+		assetPermission.route("PUT", "/api/v1/asset-permissions/:id", audit.ActionUpdate, "更新资产权限 #{path.id}", "path.id"),
+		assetPermission.route("DELETE", "/api/v1/asset-permissions/:id", audit.ActionRevoke, "删除资产权限 #{path.id}", "path.id"),
+		assetPermission.route("DELETE", "/api/v1/asset-permissions", audit.ActionRevoke, "删除角色资产权限 角色 {query.roleId} 分组 {query.assetGroupId}", "query.roleId"),
+		assetGrant.route("POST", "/api/v1/asset-grants", audit.ActionGrant, "创建资产授权", "resp.data.id"),
+		assetGrant.route("PUT", "/api/v1/asset-grants/:id", audit.ActionUpdate, "更新资产授权 #{path.id}", "path.id"),
+		assetGrant.route("DELETE", "/api/v1/asset-grants/:id", audit.ActionRevoke, "删除资产授权 #{path.id}", "path.id"),
+		accessRequest.route("POST", "/api/v1/access-requests", audit.ActionCreate, "提交访问申请 {body.resourceType} {body.reason}", "resp.data.id"),
+		accessRequest.route("POST", "/api/v1/access-requests/:id/approve", audit.ActionApprove, "批准访问申请 #{path.id}", "path.id"),
+		accessRequest.route("POST", "/api/v1/access-requests/:id/reject", audit.ActionApprove, "驳回访问申请 #{path.id}", "path.id"),
+		accessRequest.route("POST", "/api/v1/access-requests/:id/revoke", audit.ActionRevoke, "回收访问授权 #{path.id}", "path.id"),
+		accessRequest.route("POST", "/api/v1/access-requests/:id/cancel", audit.ActionUpdate, "撤回访问申请 #{path.id}", "path.id"),
+		accessRequest.route("PUT", "/api/v1/access-approvers", audit.ActionUpdate, "设置访问审批人", ""),
+	)
+
+	// 服务账号
+	serviceAccount := resource{moduleSystem, "service_account"}
+	add(
+		serviceAccount.route("POST", "/api/v1/service-accounts", audit.ActionCreate, "创建服务账号 {body.username}", "resp.data.id"),
+		serviceAccount.route("DELETE", "/api/v1/service-accounts/:id", audit.ActionDelete, "删除服务账号 #{path.id}", "path.id"),
+		serviceAccount.route("POST", "/api/v1/service-accounts/:id/tokens", audit.ActionCreate, "创建服务账号令牌 #{path.id} {body.name}", "path.id"),
+		serviceAccount.route("DELETE", "/api/v1/service-accounts/:id/tokens/:tokenId", audit.ActionRevoke, "吊销服务账号令牌 #{path.id} 令牌 {path.tokenId}", "path.id"),
+	)
+
+	// SCIM 同步
+	scimUser := resource{moduleIdentity, "user"}
+	scimGroup := resource{moduleIdentity, "department"}
+	add(
+		scimUser.route("POST", "/scim/v2/Users", audit.ActionCreate, "SCIM 创建用户 {body.userName}", "resp.id"),
+		scimUser.route("PUT", "/scim/v2/Users/:id", audit.ActionUpdate, "SCIM 更新用户 #{path.id} {body.userName}", "path.id"),
+		scimUser.route("PATCH", "/scim/v2/Users/:id", audit.ActionUpdate, "SCIM 更新用户 #{path.id}", "path.id"),
+		scimUser.route("DELETE", "/scim/v2/Users/:id", audit.ActionDelete, "SCIM 删除用户 #{path.id}", "path.id"),
+		scimGroup.route("POST", "/scim/v2/Groups", audit.ActionCreate, "SCIM 创建组 {body.displayName}", "resp.id"),
+		scimGroup.route("PUT", "/scim/v2/Groups/:id", audit.ActionUpdate, "SCIM 更新组 #{path.id} {body.displayName}", "path.id"),
+		scimGroup.route("PATCH", "/scim/v2/Groups/:id", audit.ActionUpdate, "SCIM 更新组 #{path.id}", "path.id"),
+		scimGroup.route("DELETE", "/scim/v2/Groups/:id", audit.ActionDelete, "SCIM 删除组 #{path.id}", "path.id"),
+	)
+
+	// 资产：分组、主机、数据库、凭证、云账号
+	assetGroup := resource{moduleAsset, "asset_group"}
+	host := resource{moduleAsset, "host"}
+	database := resource{moduleAsset, "database"}
+	credential := resource{moduleAsset, "credential"}
+	cloudAccount := resource{moduleAsset, "cloud_account"}
+	add(assetGroup.crud("/api/v1/asset-groups", "资产分组", "name")...)
+	add(host.crud("/api/v1/hosts", "主机", "name")...)
+	add(database.crud("/api/v1/databases", "数据库", "name")...)
+	add(credential.crud("/api/v1/credentials", "凭证", "name")...)
+	add(cloudAccount.crud("/api/v1/cloud-accounts", "云账号", "name")...)
+	add(
+		host.route("POST", "/api/v1/hosts/import", audit.ActionImport, "从 Excel 导入主机", ""),
+		host.route("POST", "/api/v1/hosts/batch-collect", audit.ActionExecute, "批量采集主机信息 {body.hostIds}", ""),
+		host.route("POST", "/api/v1/hosts/batch-delete", audit.ActionDelete, "批量删除主机 {body.hostIds}", ""),
+		host.route("POST", "/api/v1/hosts/:id/collect", audit.ActionExecute, "采集主机信息 #{path.id}", "path.id"),
+		host.route("POST", "/api/v1/hosts/:id/test", audit.ActionTest, "测试主机连接 #{path.id}", "path.id"),
+		host.route("GET", "/api/v1/hosts/:id/files/download", audit.ActionExport, "下载主机文件 #{path.id} {query.path}", "path.id"),
+		host.route("POST", "/api/v1/hosts/:id/files/upload", audit.ActionUpload, "上传文件到主机 #{path.id} {query.path}", "path.id"),
+		host.route("DELETE", "/api/v1/hosts/:id/files", audit.ActionDelete, "删除主机文件 #{path.id} {query.path}", "path.id"),
+		database.route("POST", "/api/v1/databases/:id/test", audit.ActionTest, "测试数据库连接 #{path.id}", "path.id"),
+		database.route("POST", "/api/v1/databases/:id/query", audit.ActionExecute, "执行 SQL #{path.id} {body.database}", "path.id"),
+		cloudAccount.route("POST", "/api/v1/cloud-accounts/import", audit.ActionImport, "从云账号导入主机 {body.accountId}", "body.accountId"),
+	)
+
+	// 终端、端口转发、会话策略
+	terminalPolicy := resource{moduleAsset, "terminal_policy"}
+	add(terminalPolicy.crud("/api/v1/terminal-policies", "会话策略", "name")...)
+	add(
+		host.route("GET", "/api/v1/asset/terminal/:id", audit.ActionConnect, "打开主机终端 #{path.id}", "path.id"),
+		host.route("POST", "/api/v1/asset/terminal/:id/resize", audit.ActionUpdate, "调整终端窗口 {path.id}", ""),
+		host.route("POST", "/api/v1/asset/port-forward/:id", audit.ActionConnect, "创建端口转发 #{path.id} {body.targetHost}:{body.targetPort}", "path.id"),
+		host.route("GET", "/api/v1/asset/port-forward/:id/ws", audit.ActionConnect, "连接端口转发 #{path.id} {query.targetHost}:{query.targetPort}", "path.id"),
+		resource{moduleAsset, "port_forward"}.route("DELETE", "/api/v1/port-forwards/:id", audit.ActionDelete, "关闭端口转发 {path.id}", "path.id"),
+		resource{moduleAsset, "terminal_session"}.route("GET", "/api/v1/terminal-sessions/:id/play", audit.ActionQuery, "回放终端会话 #{path.id}", "path.id"),
+	)
+
+	// 操作审计
+	auditLog := resource{moduleAudit, "audit_log"}
+	archive := resource{moduleAudit, "audit_archive"}
+	add(
+		auditLog.route("GET", "/api/v1/audit/operation-logs/export", audit.ActionExport, "导出操作日志 {query.format}", ""),
+		auditLog.route("GET", "/api/v1/audit/login-logs/export", audit.ActionExport, "导出登录日志 {query.format}", ""),
+		auditLog.route("GET", "/api/v1/audit/data-logs/export", audit.ActionExport, "导出数据日志 {query.format}", ""),
+		auditLog.route("GET", "/api/v1/audit/integrity/verify", audit.ActionQuery, "校验审计日志完整性 {query.chain}", ""),
+		resource{moduleAudit, "retention_policy"}.route("PUT", "/api/v1/audit/retention-policies/:logType", audit.ActionUpdate, "更新日志保留策略 {path.logType}", "path.logType"),
+		resource{moduleAudit, "retention_policy"}.route("POST", "/api/v1/audit/retention-policies/:logType/run", audit.ActionExecute, "执行日志保留策略 {path.logType}", "path.logType"),
+		archive.route("GET", "/api/v1/audit/archives/:id/download", audit.ActionExport, "下载审计归档 #{path.id}", "path.id"),
+		archive.route("POST", "/api/v1/audit/archives/:id/import", audit.ActionImport, "加载审计归档 #{path.id}", "path.id"),
+		archive.route("DELETE", "/api/v1/audit/archives/:id/import", audit.ActionDelete, "卸载审计归档 #{path.id}", "path.id"),
+		resource{moduleAudit, "security_event"}.route("PUT", "/api/v1/audit/security-events/:id/handle", audit.ActionUpdate, "处理安全事件 #{path.id} 为 {body.status}", "path.id"),
+		auditLog.route("GET", "/api/v1/audit/timeline", audit.ActionQuery, "查看活动时间线 用户 {query.username} 资源 {query.resourceType} {query.resourceId}", ""),
+		auditLog.route("GET", "/api/v1/audit/timeline/export", audit.ActionExport, "导出活动时间线 {query.format} 用户 {query.username} 资源 {query.resourceType} {query.resourceId}", ""),
+	)
+
+	// 插件管理
+	pluginRes := resource{modulePlugin, "plugin"}
+	add(
+		pluginRes.route("POST", "/api/v1/plugins/:name/enable", audit.ActionUpdate, "启用插件 {path.name}", "path.name"),
+		pluginRes.route("POST", "/api/v1/plugins/:name/disable", audit.ActionUpdate, "停用插件 {path.name}", "path.name"),
+		pluginRes.route("POST", "/api/v1/plugins/upload", audit.ActionUpload, "上传插件", ""),
+		pluginRes.route("DELETE", "/api/v1/plugins/:name/uninstall", audit.ActionDelete, "卸载插件 {path.name}", "path.name"),
+	)
+
+	return routes
+}
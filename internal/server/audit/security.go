@@ -0,0 +1,91 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	monitorservice "github.com/ydcloud-dy/opshub/plugins/monitor/service"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultGeoIPLanguage 未配置时 IP 库地名使用的语言
+const defaultGeoIPLanguage = "zh-CN"
+
+// StartLoginAnomalyDetection 按配置启动登录异常检测，未启用时不做任何处理
+// 登录日志写入时填充登录地点并异步检测，发现的安全事件通过监控中心的告警通道发送
+func StartLoginAnomalyDetection(ctx context.Context, db *gorm.DB, cfg conf.AuditSecurityConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	var locator audit.GeoLocator
+	if cfg.GeoIPDB != "" {
+		language := cfg.GeoIPLanguage
+		if language == "" {
+			language = defaultGeoIPLanguage
+		}
+		geoDB, err := auditdata.OpenGeoIPDatabase(cfg.GeoIPDB, language)
+		if err != nil {
+			appLogger.Error("加载离线IP库失败，登录地点和地理位置相关检测不可用", zap.String("path", cfg.GeoIPDB), zap.Error(err))
+		} else {
+			locator = geoDB
+		}
+	}
+
+	rules := audit.LoginAnomalyRules{
+		BruteForceWindow:        time.Duration(cfg.BruteForceWindow) * time.Minute,
+		BruteForceUserThreshold: cfg.BruteForceUserThreshold,
+		BruteForceIPThreshold:   cfg.BruteForceIPThreshold,
+		NewIP:                   cfg.NewIP,
+		NewCountry:              cfg.NewCountry,
+		HistoryWindow:           time.Duration(cfg.HistoryDays) * 24 * time.Hour,
+		TravelSpeedKmh:          float64(cfg.TravelSpeed),
+		NotifyLevel:             cfg.NotifyLevel,
+		NotifyUserIDs:           cfg.NotifyUserIDs,
+	}
+	if cfg.WorkHours.Enabled {
+		workHours, err := audit.NewWorkHours(cfg.WorkHours.Start, cfg.WorkHours.End, cfg.WorkHours.Weekdays, cfg.WorkHours.Timezone)
+		if err != nil {
+			appLogger.Error("工作时间配置无效，不检测非工作时间登录", zap.Error(err))
+		} else {
+			rules.WorkHours = workHours
+		}
+	}
+
+	detector := audit.NewLoginAnomalyDetector(
+		auditdata.NewLoginLogRepo(db),
+		auditdata.NewSecurityEventRepo(db),
+		locator,
+		monitorservice.NewNotifier(db),
+		rules,
+	)
+	if err := auditdata.RegisterLoginAnomalyCallbacks(db, detector); err != nil {
+		appLogger.Error("注册登录异常检测失败", zap.Error(err))
+		return
+	}
+	go detector.Run(ctx)
+}
@@ -27,25 +27,36 @@ import (
 )
 
 // NewAuditServices 创建审计模块的所有服务
-func NewAuditServices(db *gorm.DB) (
+func NewAuditServices(db *gorm.DB, chainUseCase *audit.AuditChainUseCase, retentionUseCase *audit.AuditRetentionUseCase) (
 	operationLogService *auditservice.OperationLogService,
 	loginLogService *auditservice.LoginLogService,
 	dataLogService *auditservice.DataLogService,
+	integrityService *auditservice.IntegrityService,
+	retentionService *auditservice.RetentionService,
+	securityEventService *auditservice.SecurityEventService,
+	timelineService *auditservice.TimelineService,
 ) {
 	// 初始化Repository
 	operationLogRepo := auditdata.NewOperationLogRepo(db)
 	loginLogRepo := auditdata.NewLoginLogRepo(db)
 	dataLogRepo := auditdata.NewDataLogRepo(db)
+	securityEventRepo := auditdata.NewSecurityEventRepo(db)
 
 	// 初始化UseCase
 	operationLogUseCase := audit.NewOperationLogUseCase(operationLogRepo)
 	loginLogUseCase := audit.NewLoginLogUseCase(loginLogRepo)
 	dataLogUseCase := audit.NewDataLogUseCase(dataLogRepo)
+	securityEventUseCase := audit.NewSecurityEventUseCase(securityEventRepo)
+	timelineUseCase := audit.NewTimelineUseCase(auditdata.NewTimelineSources(db)...)
 
 	// 初始化Service
 	operationLogService = auditservice.NewOperationLogService(operationLogUseCase)
 	loginLogService = auditservice.NewLoginLogService(loginLogUseCase)
 	dataLogService = auditservice.NewDataLogService(dataLogUseCase)
+	integrityService = auditservice.NewIntegrityService(chainUseCase)
+	retentionService = auditservice.NewRetentionService(retentionUseCase)
+	securityEventService = auditservice.NewSecurityEventService(securityEventUseCase)
+	timelineService = auditservice.NewTimelineService(timelineUseCase)
 
 	return
 }
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// StartAuditSinks 按配置启动审计事件转发，未启用任何转发目标时返回 nil
+// 停止服务时需调用 Close，未发送的事件写入重试队列
+func StartAuditSinks(db *gorm.DB, cfg conf.AuditConfig) *auditdata.AuditSinkPipeline {
+	pipeline, err := auditdata.NewAuditSinkPipeline(cfg.QueueDir, cfg.Sinks)
+	if err != nil {
+		appLogger.Error("审计事件转发配置无效", zap.Error(err))
+		return nil
+	}
+	if pipeline == nil {
+		return nil
+	}
+	if err := auditdata.RegisterAuditSinkCallbacks(db); err != nil {
+		appLogger.Error("注册审计事件转发失败", zap.Error(err))
+		pipeline.Close()
+		return nil
+	}
+	audit.SetEventPublisher(pipeline)
+	return pipeline
+}
@@ -26,9 +26,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	auditbiz "github.com/ydcloud-dy/opshub/internal/biz/audit"
 	"github.com/ydcloud-dy/opshub/internal/conf"
+	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
 	rbacdata "github.com/ydcloud-dy/opshub/internal/data/rbac"
 	"github.com/ydcloud-dy/opshub/internal/plugin"
 	assetserver "github.com/ydcloud-dy/opshub/internal/server/asset"
@@ -52,12 +55,16 @@ type HTTPServer struct {
 	conf      *conf.Config
 	svc       *service.Service
 	db        *gorm.DB
+	rdb       *redis.Client
 	pluginMgr *plugin.Manager
 	uploadSrv *UploadServer
+	auditSink *auditdata.AuditSinkPipeline
+	// auditRoutes 接口审计元数据，路由挂载时登记
+	auditRoutes *auditbiz.RouteAuditRegistry
 }
 
 // NewHTTPServer 创建HTTP服务器
-func NewHTTPServer(conf *conf.Config, svc *service.Service, db *gorm.DB) *HTTPServer {
+func NewHTTPServer(conf *conf.Config, svc *service.Service, db *gorm.DB, rdb *redis.Client) *HTTPServer {
 	// 设置Gin模式
 	gin.SetMode(conf.Server.Mode)
 
@@ -68,7 +75,8 @@ func NewHTTPServer(conf *conf.Config, svc *service.Service, db *gorm.DB) *HTTPSe
 	router.Use(middleware.Logger())
 	router.Use(middleware.Recovery())
 	router.Use(middleware.CORS())
-	router.Use(middleware.AuditLogOperation(db))
+	auditRoutes := auditbiz.NewRouteAuditRegistry()
+	router.Use(middleware.AuditLogOperation(db, auditserver.NewRedactor(conf.Audit.Redaction), auditRoutes))
 
 	// 创建插件管理器
 	pluginMgr := plugin.NewManager(db)
@@ -105,11 +113,13 @@ func NewHTTPServer(conf *conf.Config, svc *service.Service, db *gorm.DB) *HTTPSe
 
 	// 注册路由
 	s := &HTTPServer{
-		conf:      conf,
-		svc:       svc,
-		db:        db,
-		pluginMgr: pluginMgr,
-		uploadSrv: uploadSrv,
+		conf:        conf,
+		svc:         svc,
+		db:          db,
+		rdb:         rdb,
+		pluginMgr:   pluginMgr,
+		uploadSrv:   uploadSrv,
+		auditRoutes: auditRoutes,
 	}
 
 	// 先启用所有插件（在注册路由之前）
@@ -141,31 +151,59 @@ func (s *HTTPServer) registerRoutes(router *gin.Engine, jwtSecret string) {
 	router.Static("/uploads", "./web/public/uploads")
 
 	// 创建 RBAC 服务
-	userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, authMiddleware := rbac.NewRBACServices(s.db, jwtSecret)
+	userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, accessRequestService, ldapService, apiTokenService, permissionService, scimService, authMiddleware := rbac.NewRBACServices(s.db, s.rdb, jwtSecret, s.conf.Auth)
 
 	// RBAC 路由
-	rbacServer := rbac.NewHTTPServer(userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, authMiddleware)
+	rbacServer := rbac.NewHTTPServer(userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, accessRequestService, ldapService, apiTokenService, permissionService, scimService, authMiddleware)
 	rbacServer.RegisterRoutes(router)
 
 	// 创建 Audit 服务
-	operationLogService, loginLogService, dataLogService := auditserver.NewAuditServices(s.db)
+	auditChainUseCase := auditserver.NewAuditChainUseCase(s.db, s.conf.AuditSigningKey())
+	auditRetentionUseCase := auditserver.NewAuditRetentionUseCase(s.db, s.conf.Audit.Archive, s.conf.AuditSigningKey())
+	operationLogService, loginLogService, dataLogService, integrityService, retentionService, securityEventService, timelineService := auditserver.NewAuditServices(s.db, auditChainUseCase, auditRetentionUseCase)
+
+	// 数据变更审计：用户、角色、主机、凭证等表的增删改写入数据日志
+	if err := auditserver.RegisterDataChangeAudit(s.db); err != nil {
+		appLogger.Error("注册数据变更审计失败", zap.Error(err))
+	}
+
+	// 审计链：审计记录写入时追加哈希链，定期生成签名检查点
+	if err := auditserver.RegisterAuditChain(s.db); err != nil {
+		appLogger.Error("注册审计链失败", zap.Error(err))
+	}
+	auditserver.StartAuditCheckpoint(context.Background(), auditChainUseCase, time.Duration(s.conf.Audit.CheckpointInterval)*time.Minute)
+
+	// 审计日志保留：按策略归档过期记录后清理
+	auditserver.StartAuditRetention(context.Background(), auditRetentionUseCase, time.Duration(s.conf.Audit.RetentionInterval)*time.Minute)
+
+	// 审计事件转发：各类审计事件推送到 syslog、webhook 等外部系统
+	s.auditSink = auditserver.StartAuditSinks(s.db, s.conf.Audit)
+
+	// 登录异常检测：暴力破解、新IP/国家、不可能旅行和非工作时间登录
+	auditserver.StartLoginAnomalyDetection(context.Background(), s.db, s.conf.Audit.Security)
 
 	// 创建 Asset 服务
-	assetGroupService, hostService, terminalManager := assetserver.NewAssetServices(s.db)
+	assetGroupService, hostService, databaseService, terminalManager, portForwardManager := assetserver.NewAssetServices(s.db, s.conf.Server.PortForwardBind)
 
 	// 设置authMiddleware的assetPermissionRepo
 	assetPermissionRepo := rbacdata.NewAssetPermissionRepo(s.db)
 	authMiddleware.SetAssetPermissionRepo(assetPermissionRepo)
 
+	// 定期清理过期的资产权限和授权，回收到期的访问申请
+	rbac.StartAssetPermissionSweeper(context.Background(), assetPermissionRepo, accessRequestService)
+
+	// 定期同步 LDAP 用户
+	rbac.StartLDAPSync(context.Background(), ldapService, time.Duration(s.conf.Auth.LDAP.SyncInterval)*time.Minute)
+
 	// Asset 路由
-	assetServer := assetserver.NewHTTPServer(assetGroupService, hostService, terminalManager, s.db, authMiddleware)
+	assetServer := assetserver.NewHTTPServer(assetGroupService, hostService, databaseService, terminalManager, portForwardManager, s.db, authMiddleware)
 
 	// API v1 - 需要认证的接口
 	v1 := router.Group("/api/v1")
-	v1.Use(authMiddleware.AuthRequired())
+	v1.Use(authMiddleware.AuthRequired(), authMiddleware.RequirePermission())
 	{
 		// Audit 路由
-		auditHTTPServer := auditserver.NewHTTPService(operationLogService, loginLogService, dataLogService)
+		auditHTTPServer := auditserver.NewHTTPService(operationLogService, loginLogService, dataLogService, integrityService, retentionService, securityEventService, timelineService)
 		auditHTTPServer.RegisterRoutes(v1)
 
 		// 注册 Asset 路由
@@ -184,12 +222,19 @@ func (s *HTTPServer) registerRoutes(router *gin.Engine, jwtSecret string) {
 
 	// 插件路由
 	pluginsGroup := router.Group("/api/v1/plugins")
-	pluginsGroup.Use(authMiddleware.AuthRequired())
+	pluginsGroup.Use(authMiddleware.AuthRequired(), authMiddleware.RequirePermission())
 	s.pluginMgr.RegisterAllRoutes(pluginsGroup)
 
+	// 登记内置接口和插件接口的审计元数据
+	s.auditRoutes.Register(auditserver.CoreAuditRoutes()...)
+	s.auditRoutes.Register(auditserver.PluginAuditRoutes(s.pluginMgr.GetAllAuditRoutes())...)
+
+	// 登记插件提供的活动时间线来源
+	timelineService.UseCase().Register(s.pluginMgr.GetAllTimelineSources()...)
+
 	// 插件管理接口
 	pluginInfoGroup := router.Group("/api/v1/plugins")
-	pluginInfoGroup.Use(authMiddleware.AuthRequired())
+	pluginInfoGroup.Use(authMiddleware.AuthRequired(), authMiddleware.RequirePermission())
 	{
 		pluginInfoGroup.GET("", s.listPlugins)
 		pluginInfoGroup.GET("/:name", s.getPlugin)
@@ -200,6 +245,20 @@ func (s *HTTPServer) registerRoutes(router *gin.Engine, jwtSecret string) {
 		pluginInfoGroup.DELETE("/:name/uninstall", s.uploadSrv.UninstallPlugin)
 	}
 
+	// 登记插件接口权限，并将全部权限同步为菜单按钮
+	permissionUseCase := permissionService.UseCase()
+	permissionUseCase.Registry().Register(rbac.PluginPermissions(s.pluginMgr.GetAllPermissions())...)
+	if created, err := permissionUseCase.Sync(context.Background()); err != nil {
+		appLogger.Error("同步接口权限失败", zap.Error(err))
+	} else if created > 0 {
+		appLogger.Info("已创建接口权限按钮", zap.Int("count", created))
+	}
+
+	// 登记的接口必须都能对应到实际路由，路径写错时权限会静默失效
+	if err := permissionUseCase.Registry().Validate(rbac.GinRoutes(router.Routes())); err != nil {
+		appLogger.Fatal("接口权限登记校验失败", zap.Error(err))
+	}
+
 	// 前端静态文件服务（后面会用到）
 	// router.Static("/assets", "./web/dist/assets")
 	// router.NoRoute(func(c *gin.Context) {
@@ -411,6 +470,9 @@ func (s *HTTPServer) Stop(ctx context.Context) error {
 	if err := s.server.Shutdown(ctx); err != nil {
 		return fmt.Errorf("HTTP服务器停止失败: %w", err)
 	}
+	if s.auditSink != nil {
+		s.auditSink.Close()
+	}
 	appLogger.Info("HTTP服务器已停止")
 	return nil
 }
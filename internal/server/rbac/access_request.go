@@ -0,0 +1,55 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+
+	k8sservice "github.com/ydcloud-dy/opshub/plugins/kubernetes/service"
+)
+
+// clusterRoleBinder 通过 Kubernetes 插件的角色绑定服务授予/解除集群角色
+type clusterRoleBinder struct {
+	roleBindingService *k8sservice.RoleBindingService
+}
+
+// BindClusterRole 绑定集群角色
+func (b *clusterRoleBinder) BindClusterRole(ctx context.Context, clusterID, userID uint, roleName, roleNamespace, roleType string, boundBy uint) error {
+	return b.roleBindingService.BindUserRole(ctx, uint64(clusterID), uint64(userID), roleName, roleNamespace, roleType, uint64(boundBy))
+}
+
+// UnbindClusterRole 解除集群角色绑定，绑定已被手工移除时视为成功
+func (b *clusterRoleBinder) UnbindClusterRole(ctx context.Context, clusterID, userID uint, roleName, roleNamespace string) error {
+	bindings, err := b.roleBindingService.GetUserClusterRoles(ctx, uint64(clusterID), uint64(userID))
+	if err != nil {
+		return err
+	}
+	bound := false
+	for _, binding := range bindings {
+		if binding.RoleName == roleName && binding.RoleNamespace == roleNamespace {
+			bound = true
+			break
+		}
+	}
+	if !bound {
+		return nil
+	}
+	return b.roleBindingService.UnbindUserRole(ctx, uint64(clusterID), uint64(userID), roleName, roleNamespace)
+}
@@ -20,12 +20,18 @@
 package rbac
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	auditbiz "github.com/ydcloud-dy/opshub/internal/biz/audit"
 	auditdata "github.com/ydcloud-dy/opshub/internal/data/audit"
+	"github.com/ydcloud-dy/opshub/internal/conf"
 	rbacService "github.com/ydcloud-dy/opshub/internal/service/rbac"
 	rbacdata "github.com/ydcloud-dy/opshub/internal/data/rbac"
 	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	k8sservice "github.com/ydcloud-dy/opshub/plugins/kubernetes/service"
+	monitorservice "github.com/ydcloud-dy/opshub/plugins/monitor/service"
 	"gorm.io/gorm"
 )
 
@@ -37,6 +43,11 @@ type HTTPServer struct {
 	positionService        *rbacService.PositionService
 	captchaService         *rbacService.CaptchaService
 	assetPermissionService *rbacService.AssetPermissionService
+	accessRequestService   *rbacService.AccessRequestService
+	ldapService            *rbacService.LDAPService
+	apiTokenService        *rbacService.APITokenService
+	permissionService      *rbacService.PermissionService
+	scimService            *rbacService.SCIMService
 	authMiddleware         *rbacService.AuthMiddleware
 }
 
@@ -48,6 +59,11 @@ func NewHTTPServer(
 	positionService *rbacService.PositionService,
 	captchaService *rbacService.CaptchaService,
 	assetPermissionService *rbacService.AssetPermissionService,
+	accessRequestService *rbacService.AccessRequestService,
+	ldapService *rbacService.LDAPService,
+	apiTokenService *rbacService.APITokenService,
+	permissionService *rbacService.PermissionService,
+	scimService *rbacService.SCIMService,
 	authMiddleware *rbacService.AuthMiddleware,
 ) *HTTPServer {
 	return &HTTPServer{
@@ -58,6 +74,11 @@ func NewHTTPServer(
 		positionService:        positionService,
 		captchaService:         captchaService,
 		assetPermissionService: assetPermissionService,
+		accessRequestService:   accessRequestService,
+		ldapService:            ldapService,
+		apiTokenService:        apiTokenService,
+		permissionService:      permissionService,
+		scimService:            scimService,
 		authMiddleware:         authMiddleware,
 	}
 }
@@ -67,6 +88,16 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 	public := r.Group("/api/v1/public")
 	{
 		public.POST("/login", s.userService.Login)
+		public.POST("/login/mfa", s.userService.LoginMFA)
+		public.POST("/login/mfa/enroll", s.userService.LoginMFAEnroll)
+		public.POST("/login/password", s.userService.LoginChangePassword)
+		public.GET("/password-policy", s.userService.GetPasswordPolicy)
+		public.POST("/token/refresh", s.userService.RefreshToken)
+
+		// OIDC 单点登录
+		public.GET("/oidc/providers", s.userService.ListOIDCProviders)
+		public.GET("/oidc/:provider/login", s.userService.OIDCLogin)
+		public.GET("/oidc/:provider/callback", s.userService.OIDCCallback)
 	}
 
 	// 验证码路由（无需认证）
@@ -78,16 +109,42 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 
 	// 需要认证的路由
 	auth := r.Group("/api/v1")
-	auth.Use(s.authMiddleware.AuthRequired())
+	auth.Use(s.authMiddleware.AuthRequired(), s.authMiddleware.RequirePermission())
 	{
 		// 用户相关
 		auth.GET("/profile", s.userService.GetProfile)
 		auth.PUT("/profile/password", s.userService.ChangePassword)
+		auth.POST("/logout", s.userService.Logout)
+
+		// 登录会话
+		auth.GET("/profile/sessions", s.userService.ListMySessions)
+		auth.DELETE("/profile/sessions", s.userService.RevokeMySessions)
+		auth.DELETE("/profile/sessions/:id", s.userService.RevokeMySession)
+
+		// 双因素认证
+		auth.GET("/profile/mfa", s.userService.GetMFAStatus)
+		auth.POST("/profile/mfa/enroll", s.userService.BeginMFAEnroll)
+		auth.POST("/profile/mfa/confirm", s.userService.ConfirmMFAEnroll)
+		auth.POST("/profile/mfa/recovery-codes", s.userService.RegenerateRecoveryCodes)
+		auth.DELETE("/profile/mfa", s.userService.DisableMFA)
+
+		// 个人 API 令牌
+		auth.GET("/profile/api-tokens", s.apiTokenService.ListMyAPITokens)
+		auth.POST("/profile/api-tokens", s.apiTokenService.CreateMyAPIToken)
+		auth.DELETE("/profile/api-tokens/:id", s.apiTokenService.RevokeMyAPIToken)
+
+		// 接口权限
+		auth.GET("/profile/permissions", s.permissionService.GetMyPermissions)
+		auth.GET("/permissions", s.authMiddleware.RequireAdmin(), s.permissionService.ListPermissions)
 
 		// 用户管理
 		users := auth.Group("/users")
 		{
 			users.GET("", s.userService.ListUsers)
+			// 导入导出放在 /:id 之前
+			users.GET("/export", s.userService.ExportUsers)
+			users.GET("/import/template", s.userService.DownloadUserImportTemplate)
+			users.POST("/import", s.userService.ImportUsers)
 			users.GET("/:id", s.userService.GetUser)
 			users.POST("", s.userService.CreateUser)
 			users.PUT("/:id", s.userService.UpdateUser)
@@ -95,6 +152,11 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 			users.POST("/:id/roles", s.userService.AssignUserRoles)
 			users.POST("/:id/positions", s.userService.AssignUserPositions)
 			users.PUT("/:id/reset-password", s.userService.ResetPassword)
+			users.DELETE("/:id/mfa", s.userService.ResetUserMFA)
+			users.PUT("/:id/unlock", s.userService.UnlockUser)
+			users.GET("/:id/sessions", s.authMiddleware.RequireAdmin(), s.userService.ListUserSessions)
+			users.DELETE("/:id/sessions", s.authMiddleware.RequireAdmin(), s.userService.RevokeUserSessions)
+			users.DELETE("/:id/sessions/:sessionId", s.authMiddleware.RequireAdmin(), s.userService.RevokeUserSession)
 		}
 
 		// 角色管理
@@ -107,6 +169,7 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 			roles.PUT("/:id", s.roleService.UpdateRole)
 			roles.DELETE("/:id", s.roleService.DeleteRole)
 			roles.POST("/:id/menus", s.roleService.AssignRoleMenus)
+			roles.PUT("/:id/mfa", s.roleService.SetRoleMFA)
 		}
 
 		// 部门管理
@@ -153,6 +216,7 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 			assetPermissions.GET("/role/:roleId", s.assetPermissionService.GetAssetPermissionsByRole)
 			assetPermissions.GET("/group/:assetGroupId", s.assetPermissionService.GetAssetPermissionsByGroup)
 			assetPermissions.GET("/user/host", s.assetPermissionService.GetUserHostPermissions)
+			assetPermissions.GET("/explain", s.assetPermissionService.ExplainHostPermissions)
 			// 通用 /:id 路由必须放在最后
 			assetPermissions.GET("/:id", s.assetPermissionService.GetAssetPermissionDetail)
 			assetPermissions.PUT("/:id", s.assetPermissionService.UpdateAssetPermission)
@@ -160,11 +224,76 @@ func (s *HTTPServer) RegisterRoutes(r *gin.Engine) {
 			// 删除分组权限用空路径（没有 :id）
 			assetPermissions.DELETE("", s.assetPermissionService.DeleteAssetPermissionByRoleAndGroup)
 		}
+
+		// 资产授权（用户/部门/角色，支持时效和显式拒绝）
+		assetGrants := auth.Group("/asset-grants")
+		{
+			assetGrants.GET("", s.assetPermissionService.ListAssetGrants)
+			assetGrants.POST("", s.assetPermissionService.CreateAssetGrant)
+			assetGrants.GET("/:id", s.assetPermissionService.GetAssetGrant)
+			assetGrants.PUT("/:id", s.assetPermissionService.UpdateAssetGrant)
+			assetGrants.DELETE("/:id", s.assetPermissionService.DeleteAssetGrant)
+		}
+
+		// 访问申请与审批
+		accessRequests := auth.Group("/access-requests")
+		{
+			accessRequests.GET("", s.accessRequestService.ListAccessRequests)
+			accessRequests.POST("", s.accessRequestService.CreateAccessRequest)
+			accessRequests.GET("/:id", s.accessRequestService.GetAccessRequest)
+			accessRequests.POST("/:id/approve", s.accessRequestService.ApproveAccessRequest)
+			accessRequests.POST("/:id/reject", s.accessRequestService.RejectAccessRequest)
+			accessRequests.POST("/:id/revoke", s.accessRequestService.RevokeAccessRequest)
+			accessRequests.POST("/:id/cancel", s.accessRequestService.CancelAccessRequest)
+		}
+		auth.GET("/access-approvers", s.accessRequestService.ListAccessApprovers)
+		auth.PUT("/access-approvers", s.accessRequestService.SetAccessApprovers)
+
+		// LDAP/AD 目录同步
+		auth.GET("/ldap/status", s.ldapService.GetLDAPStatus)
+		auth.POST("/ldap/sync", s.ldapService.SyncLDAPUsers)
+
+		// 服务账号及其 API 令牌（仅管理员）
+		serviceAccounts := auth.Group("/service-accounts")
+		serviceAccounts.Use(s.authMiddleware.RequireAdmin())
+		{
+			serviceAccounts.GET("", s.apiTokenService.ListServiceAccounts)
+			serviceAccounts.POST("", s.apiTokenService.CreateServiceAccount)
+			serviceAccounts.DELETE("/:id", s.apiTokenService.DeleteServiceAccount)
+			serviceAccounts.GET("/:id/tokens", s.apiTokenService.ListServiceAccountTokens)
+			serviceAccounts.POST("/:id/tokens", s.apiTokenService.CreateServiceAccountToken)
+			serviceAccounts.DELETE("/:id/tokens/:tokenId", s.apiTokenService.RevokeServiceAccountToken)
+		}
+	}
+
+	// SCIM 2.0 用户下发（身份提供者使用独立令牌访问）
+	if s.scimService.Enabled() {
+		scim := r.Group("/scim/v2")
+		scim.Use(s.scimService.TokenRequired())
+		{
+			scim.GET("/ServiceProviderConfig", s.scimService.GetServiceProviderConfig)
+			scim.GET("/ResourceTypes", s.scimService.GetResourceTypes)
+			scim.GET("/Schemas", s.scimService.GetSchemas)
+
+			scim.GET("/Users", s.scimService.ListUsers)
+			scim.POST("/Users", s.scimService.CreateUser)
+			scim.GET("/Users/:id", s.scimService.GetUser)
+			scim.PUT("/Users/:id", s.scimService.ReplaceUser)
+			scim.PATCH("/Users/:id", s.scimService.PatchUser)
+			scim.DELETE("/Users/:id", s.scimService.DeleteUser)
+
+			scim.GET("/Groups", s.scimService.ListGroups)
+			scim.POST("/Groups", s.scimService.CreateGroup)
+			scim.GET("/Groups/:id", s.scimService.GetGroup)
+			scim.PUT("/Groups/:id", s.scimService.ReplaceGroup)
+			scim.PATCH("/Groups/:id", s.scimService.PatchGroup)
+			scim.DELETE("/Groups/:id", s.scimService.DeleteGroup)
+		}
 	}
 }
 
 // 依赖注入函数
-func NewRBACServices(db *gorm.DB, jwtSecret string) (
+func NewRBACServices(db *gorm.DB, rdb *redis.Client, jwtSecret string, authConf conf.AuthConfig) (
 	*rbacService.UserService,
 	*rbacService.RoleService,
 	*rbacService.DepartmentService,
@@ -172,6 +301,11 @@ func NewRBACServices(db *gorm.DB, jwtSecret string) (
 	*rbacService.PositionService,
 	*rbacService.CaptchaService,
 	*rbacService.AssetPermissionService,
+	*rbacService.AccessRequestService,
+	*rbacService.LDAPService,
+	*rbacService.APITokenService,
+	*rbacService.PermissionService,
+	*rbacService.SCIMService,
 	*rbacService.AuthMiddleware,
 ) {
 	// 初始化Repository
@@ -181,6 +315,8 @@ func NewRBACServices(db *gorm.DB, jwtSecret string) (
 	menuRepo := rbacdata.NewMenuRepo(db)
 	positionRepo := rbacdata.NewPositionRepo(db)
 	assetPermissionRepo := rbacdata.NewAssetPermissionRepo(db)
+	accessRequestRepo := rbacdata.NewAccessRequestRepo(db)
+	externalIdentityRepo := rbacdata.NewExternalIdentityRepo(db)
 
 	// 初始化Audit Repository
 	loginLogRepo := auditdata.NewLoginLogRepo(db)
@@ -192,12 +328,49 @@ func NewRBACServices(db *gorm.DB, jwtSecret string) (
 	menuUseCase := rbacbiz.NewMenuUseCase(menuRepo)
 	positionUseCase := rbacbiz.NewPositionUseCase(positionRepo)
 	assetPermissionUseCase := rbacbiz.NewAssetPermissionUseCase(assetPermissionRepo)
+	accessRequestUseCase := rbacbiz.NewAccessRequestUseCase(accessRequestRepo, assetPermissionRepo)
+	apiTokenUseCase := rbacbiz.NewAPITokenUseCase(rbacdata.NewAPITokenRepo(db), userRepo, externalIdentityRepo)
+	mfaUseCase := rbacbiz.NewMFAUseCase(rbacdata.NewMFARepo(db), assetPermissionRepo, authConf.MFA.Issuer, authConf.MFA.RequireForTerminal)
+	passwordUseCase := rbacbiz.NewPasswordUseCase(rbacdata.NewPasswordRepo(db), userRepo, passwordPolicy(authConf.Password))
+	userUseCase.SetPasswordUseCase(passwordUseCase)
+	permissionUseCase := rbacbiz.NewPermissionUseCase(rbacdata.NewPermissionRepo(db), roleRepo, rbacbiz.NewPermissionRegistry())
+	permissionUseCase.Registry().Register(CorePermissions()...)
+	permissionUseCase.Registry().Allow(CoreAllowedRoutes()...)
+	sessionUseCase := rbacbiz.NewSessionUseCase(rbacdata.NewSessionStore(rdb), userRepo, time.Duration(authConf.Session.RefreshTokenTTL)*time.Hour)
+
+	// 访问申请通过 Kubernetes 插件授予集群角色，通过监控中心的告警通道发送通知
+	accessRequestUseCase.SetClusterRoleBinder(&clusterRoleBinder{roleBindingService: k8sservice.NewRoleBindingService(db)})
+	accessRequestUseCase.SetNotifier(monitorservice.NewNotifier(db))
+
+	// 登录认证链：先校验本地账号，再交给 LDAP/AD
+	authChain := rbacbiz.NewAuthChain(rbacbiz.NewLocalAuthProvider(userRepo))
+	var ldapUseCase *rbacbiz.LDAPUseCase
+	if authConf.LDAP.Enabled {
+		ldapUseCase = rbacbiz.NewLDAPUseCase(rbacdata.NewLDAPDirectory(authConf.LDAP), userRepo, externalIdentityRepo, ldapSyncOptions(authConf.LDAP))
+		ldapUseCase.SetSessionUseCase(sessionUseCase)
+		authChain.Use(ldapUseCase)
+	}
+
+	// OIDC 单点登录
+	var oidcUseCase *rbacbiz.OIDCUseCase
+	if len(authConf.OIDC) > 0 {
+		oidcUseCase = rbacbiz.NewOIDCUseCase(rbacdata.NewOIDCConnector(authConf.OIDC), userRepo, externalIdentityRepo, oidcProviderOptions(authConf.OIDC))
+	}
+
+	// SCIM 用户下发
+	var scimUseCase *rbacbiz.SCIMUseCase
+	if authConf.SCIM.Enabled {
+		scimUseCase = rbacbiz.NewSCIMUseCase(rbacdata.NewSCIMRepo(db), userRepo, roleRepo, externalIdentityRepo, userUseCase, authConf.SCIM.DefaultRoles)
+		scimUseCase.SetSessionUseCase(sessionUseCase)
+		scimUseCase.SetPermissionUseCase(permissionUseCase)
+	}
 
 	// 初始化Audit UseCase
 	loginLogUseCase := auditbiz.NewLoginLogUseCase(loginLogRepo)
 
 	// 初始化Service
 	authService := rbacService.NewAuthService(jwtSecret, roleUseCase)
+	authService.SetAccessTokenTTL(time.Duration(authConf.Session.AccessTokenTTL) * time.Minute)
 	userService := rbacService.NewUserService(userUseCase, authService)
 	roleService := rbacService.NewRoleService(roleUseCase)
 	departmentService := rbacService.NewDepartmentService(deptUseCase)
@@ -205,7 +378,16 @@ func NewRBACServices(db *gorm.DB, jwtSecret string) (
 	positionService := rbacService.NewPositionService(positionUseCase)
 	captchaService := rbacService.NewCaptchaService()
 	assetPermissionService := rbacService.NewAssetPermissionService(assetPermissionUseCase)
+	accessRequestService := rbacService.NewAccessRequestService(accessRequestUseCase)
+	ldapService := rbacService.NewLDAPService(ldapUseCase)
+	apiTokenService := rbacService.NewAPITokenService(apiTokenUseCase)
+	permissionService := rbacService.NewPermissionService(permissionUseCase)
+	scimService := rbacService.NewSCIMService(scimUseCase, authConf.SCIM.Token)
 	authMiddleware := rbacService.NewAuthMiddleware(authService)
+	authMiddleware.SetAPITokenUseCase(apiTokenUseCase)
+	authMiddleware.SetSessionUseCase(sessionUseCase)
+	authMiddleware.SetPermissionUseCase(permissionUseCase)
+	authMiddleware.SetDataScopeUseCase(rbacbiz.NewDataScopeUseCase(roleRepo, userRepo, deptRepo))
 
 	// 设置验证码服务到用户服务
 	userService.SetCaptchaService(captchaService)
@@ -213,5 +395,46 @@ func NewRBACServices(db *gorm.DB, jwtSecret string) (
 	// 设置登录日志用例到用户服务
 	userService.SetLoginLogUseCase(loginLogUseCase)
 
-	return userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, authMiddleware
+	// 设置登录认证链到用户服务
+	userService.SetAuthChain(authChain)
+	userService.SetOIDCUseCase(oidcUseCase)
+
+	// 设置双因素认证用例
+	userService.SetMFAUseCase(mfaUseCase)
+	roleService.SetMFAUseCase(mfaUseCase)
+
+	// 设置会话用例：刷新令牌、会话列表和强制下线
+	userService.SetSessionUseCase(sessionUseCase)
+
+	// 设置密码策略：改密校验、强制改密和登录失败锁定
+	userService.SetPasswordUseCase(passwordUseCase)
+
+	// 设置按钮权限用例：角色授权和菜单变更后刷新权限缓存
+	userService.SetPermissionUseCase(permissionUseCase)
+
+	// 设置用户批量导入导出
+	userService.SetUserImportUseCase(rbacbiz.NewUserImportUseCase(userRepo, roleRepo, deptRepo, positionRepo, userUseCase))
+	roleService.SetPermissionUseCase(permissionUseCase)
+	menuService.SetPermissionUseCase(permissionUseCase)
+
+	return userService, roleService, departmentService, menuService, positionService, captchaService, assetPermissionService, accessRequestService, ldapService, apiTokenService, permissionService, scimService, authMiddleware
+}
+
+// passwordPolicy 将配置转换为密码策略
+func passwordPolicy(cfg conf.PasswordConfig) rbacbiz.PasswordPolicy {
+	return rbacbiz.PasswordPolicy{
+		MinLength:      cfg.MinLength,
+		RequireUpper:   cfg.RequireUpper,
+		RequireLower:   cfg.RequireLower,
+		RequireDigit:   cfg.RequireDigit,
+		RequireSymbol:  cfg.RequireSymbol,
+		Blocklist:      cfg.Blocklist,
+		HistoryCount:   cfg.HistoryCount,
+		MaxAgeDays:     cfg.MaxAgeDays,
+		WarnDays:       cfg.WarnDays,
+		ForceChange:    cfg.ForceChange,
+		MaxFailures:    cfg.MaxFailures,
+		FailureWindow:  cfg.FailureWindow,
+		LockoutMinutes: cfg.LockoutMinutes,
+	}
 }
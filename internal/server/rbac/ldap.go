@@ -0,0 +1,78 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+	rbacService "github.com/ydcloud-dy/opshub/internal/service/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// StartLDAPSync 启动 LDAP 用户定时同步任务，interval 为 0 或未启用 LDAP 时不启动
+func StartLDAPSync(ctx context.Context, ldapService *rbacService.LDAPService, interval time.Duration) {
+	if !ldapService.Enabled() || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := ldapService.Sync(ctx)
+				if err != nil {
+					appLogger.Error("LDAP用户同步失败", zap.Error(err))
+					continue
+				}
+				appLogger.Info("LDAP用户同步完成",
+					zap.Int("total", result.Total),
+					zap.Int("updated", result.Updated),
+					zap.Int("disabled", result.Disabled),
+					zap.Int("failed", result.Failed),
+				)
+			}
+		}
+	}()
+}
+
+// ldapSyncOptions 将配置中的分组映射转换为同步选项
+func ldapSyncOptions(cfg conf.LDAPConfig) rbacbiz.LDAPSyncOptions {
+	opts := rbacbiz.LDAPSyncOptions{
+		DefaultRoleCodes: cfg.DefaultRoles,
+		DisableMissing:   cfg.DisableMissing,
+	}
+	for _, m := range cfg.GroupMappings {
+		opts.GroupMappings = append(opts.GroupMappings, rbacbiz.DirectoryGroupMapping{
+			Group:          m.Group,
+			RoleCodes:      m.Roles,
+			DepartmentCode: m.Department,
+		})
+	}
+	return opts
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/internal/conf"
+)
+
+// oidcProviderOptions 将配置中的 OIDC 提供者转换为账号映射选项
+func oidcProviderOptions(configs []conf.OIDCProviderConfig) []rbacbiz.OIDCProviderOptions {
+	options := make([]rbacbiz.OIDCProviderOptions, 0, len(configs))
+	for _, cfg := range configs {
+		opts := rbacbiz.OIDCProviderOptions{
+			Name:              cfg.Name,
+			DisplayName:       cfg.DisplayName,
+			AutoCreate:        cfg.AutoCreate,
+			LinkBy:            cfg.LinkBy,
+			DefaultRoleCodes:  cfg.DefaultRoles,
+			PostLoginRedirect: cfg.PostLoginRedirect,
+		}
+		for _, m := range cfg.RoleMappings {
+			opts.RoleMappings = append(opts.RoleMappings, rbacbiz.DirectoryGroupMapping{
+				Group:          m.Group,
+				RoleCodes:      m.Roles,
+				DepartmentCode: m.Department,
+			})
+		}
+		options = append(options, opts)
+	}
+	return options
+}
@@ -0,0 +1,427 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"github.com/gin-gonic/gin"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/internal/plugin"
+)
+
+// pluginRoutePrefix 插件路由组前缀
+const pluginRoutePrefix = "/api/v1/plugins"
+
+// route 构造权限接口
+func route(method, path string) rbacbiz.PermissionRoute {
+	return rbacbiz.PermissionRoute{Method: method, Path: path}
+}
+
+// GinRoutes 将 gin 已注册的路由转换为权限接口，用于校验权限登记
+func GinRoutes(routes gin.RoutesInfo) []rbacbiz.PermissionRoute {
+	list := make([]rbacbiz.PermissionRoute, 0, len(routes))
+	for _, r := range routes {
+		list = append(list, route(r.Method, r.Path))
+	}
+	return list
+}
+
+// PluginPermissions 将插件声明的权限转换为权限定义，接口路径补全插件路由组前缀
+func PluginPermissions(configs []plugin.PermissionConfig) []rbacbiz.PermissionDefinition {
+	defs := make([]rbacbiz.PermissionDefinition, 0, len(configs))
+	for _, cfg := range configs {
+		routes := make([]rbacbiz.PermissionRoute, 0, len(cfg.Routes))
+		for _, r := range cfg.Routes {
+			routes = append(routes, route(r.Method, pluginRoutePrefix+r.Path))
+		}
+		defs = append(defs, rbacbiz.PermissionDefinition{
+			Code:     cfg.Code,
+			Name:     cfg.Name,
+			MenuCode: cfg.MenuCode,
+			Routes:   routes,
+		})
+	}
+	return defs
+}
+
+// CoreAllowedRoutes 只需登录即可访问的系统内置接口
+// 包括个人中心、多个页面复用的下拉选项，以及自带资产权限或归属校验的接口
+func CoreAllowedRoutes() []rbacbiz.PermissionRoute {
+	return []rbacbiz.PermissionRoute{
+		// 个人中心
+		route("GET", "/api/v1/profile"),
+		route("PUT", "/api/v1/profile/password"),
+		route("PUT", "/api/v1/profile/avatar"),
+		route("POST", "/api/v1/upload/avatar"),
+		route("POST", "/api/v1/logout"),
+		route("GET", "/api/v1/profile/sessions"),
+		route("DELETE", "/api/v1/profile/sessions"),
+		route("DELETE", "/api/v1/profile/sessions/:id"),
+		route("GET", "/api/v1/profile/mfa"),
+		route("POST", "/api/v1/profile/mfa/enroll"),
+		route("POST", "/api/v1/profile/mfa/confirm"),
+		route("POST", "/api/v1/profile/mfa/recovery-codes"),
+		route("DELETE", "/api/v1/profile/mfa"),
+		route("GET", "/api/v1/profile/api-tokens"),
+		route("POST", "/api/v1/profile/api-tokens"),
+		route("DELETE", "/api/v1/profile/api-tokens/:id"),
+		route("GET", "/api/v1/profile/permissions"),
+		route("GET", "/api/v1/menus/user"),
+
+		// 下拉选项，只返回名称和层级
+		route("GET", "/api/v1/roles/all"),
+		route("GET", "/api/v1/menus/tree"),
+		route("GET", "/api/v1/departments/tree"),
+		route("GET", "/api/v1/departments/parent-options"),
+		route("GET", "/api/v1/positions"),
+		route("GET", "/api/v1/asset-groups/tree"),
+		route("GET", "/api/v1/asset-groups/parent-options"),
+
+		// 仅管理员可访问，由 RequireAdmin 校验
+		route("GET", "/api/v1/permissions"),
+		route("GET", "/api/v1/users/:id/sessions"),
+		route("DELETE", "/api/v1/users/:id/sessions"),
+		route("DELETE", "/api/v1/users/:id/sessions/:sessionId"),
+		route("GET", "/api/v1/service-accounts"),
+		route("POST", "/api/v1/service-accounts"),
+		route("DELETE", "/api/v1/service-accounts/:id"),
+		route("GET", "/api/v1/service-accounts/:id/tokens"),
+		route("POST", "/api/v1/service-accounts/:id/tokens"),
+		route("DELETE", "/api/v1/service-accounts/:id/tokens/:tokenId"),
+
+		// 主机和数据库：列表按资产权限过滤，单个资产的操作由资产权限中间件校验
+		route("GET", "/api/v1/hosts"),
+		route("GET", "/api/v1/hosts/:id"),
+		route("POST", "/api/v1/hosts/:id/collect"),
+		route("GET", "/api/v1/hosts/:id/files"),
+		route("POST", "/api/v1/hosts/:id/files/upload"),
+		route("GET", "/api/v1/hosts/:id/files/download"),
+		route("DELETE", "/api/v1/hosts/:id/files"),
+		route("GET", "/api/v1/databases"),
+		route("GET", "/api/v1/databases/:id"),
+		route("POST", "/api/v1/databases/:id/test"),
+		route("POST", "/api/v1/databases/:id/query"),
+		route("GET", "/api/v1/asset/terminal/:id"),
+		route("POST", "/api/v1/asset/terminal/:id/resize"),
+		route("GET", "/api/v1/asset/port-forward/:id/ws"),
+		route("POST", "/api/v1/asset/port-forward/:id"),
+		route("GET", "/api/v1/asset-permissions/user/host"),
+
+		// 端口转发记录按数据权限过滤，只能关闭自己的隧道
+		route("GET", "/api/v1/port-forwards"),
+		route("DELETE", "/api/v1/port-forwards/:id"),
+
+		// 访问申请：申请人、审批人和管理员由用例校验
+		route("GET", "/api/v1/access-requests"),
+		route("POST", "/api/v1/access-requests"),
+		route("GET", "/api/v1/access-requests/:id"),
+		route("POST", "/api/v1/access-requests/:id/approve"),
+		route("POST", "/api/v1/access-requests/:id/reject"),
+		route("POST", "/api/v1/access-requests/:id/revoke"),
+		route("POST", "/api/v1/access-requests/:id/cancel"),
+		route("GET", "/api/v1/access-approvers"),
+
+		// 插件信息，前端加载插件菜单使用
+		route("GET", "/api/v1/plugins"),
+		route("GET", "/api/v1/plugins/:name"),
+		route("GET", "/api/v1/plugins/:name/menus"),
+	}
+}
+
+// CorePermissions 系统内置接口的权限映射
+// 列表和详情等查询接口对应菜单的查看权限，新增、修改、删除等接口对应操作按钮
+func CorePermissions() []rbacbiz.PermissionDefinition {
+	return []rbacbiz.PermissionDefinition{
+		// 用户管理
+		{Code: "system:user:view", Name: "查看用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/users"),
+			route("GET", "/api/v1/users/:id"),
+		}},
+		{Code: "system:user:create", Name: "新增用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/users"),
+		}},
+		{Code: "system:user:update", Name: "编辑用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/users/:id"),
+			route("POST", "/api/v1/users/:id/roles"),
+			route("POST", "/api/v1/users/:id/positions"),
+		}},
+		{Code: "system:user:delete", Name: "删除用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/users/:id"),
+		}},
+		{Code: "system:user:import", Name: "导入用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/users/import/template"),
+			route("POST", "/api/v1/users/import"),
+		}},
+		{Code: "system:user:export", Name: "导出用户", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/users/export"),
+		}},
+		{Code: "system:user:reset", Name: "重置密码", MenuCode: "users", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/users/:id/reset-password"),
+			route("DELETE", "/api/v1/users/:id/mfa"),
+			route("PUT", "/api/v1/users/:id/unlock"),
+		}},
+
+		// 角色管理
+		{Code: "system:role:view", Name: "查看角色", MenuCode: "roles", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/roles"),
+			route("GET", "/api/v1/roles/:id"),
+		}},
+		{Code: "system:role:create", Name: "新增角色", MenuCode: "roles", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/roles"),
+		}},
+		{Code: "system:role:update", Name: "编辑角色", MenuCode: "roles", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/roles/:id"),
+			route("PUT", "/api/v1/roles/:id/mfa"),
+		}},
+		{Code: "system:role:delete", Name: "删除角色", MenuCode: "roles", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/roles/:id"),
+		}},
+		{Code: "system:role:assign", Name: "分配权限", MenuCode: "roles", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/roles/:id/menus"),
+		}},
+
+		// 菜单管理
+		{Code: "system:menu:view", Name: "查看菜单", MenuCode: "menus", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/menus/:id"),
+		}},
+		{Code: "system:menu:create", Name: "新增菜单", MenuCode: "menus", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/menus"),
+		}},
+		{Code: "system:menu:update", Name: "编辑菜单", MenuCode: "menus", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/menus/:id"),
+		}},
+		{Code: "system:menu:delete", Name: "删除菜单", MenuCode: "menus", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/menus/:id"),
+		}},
+
+		// 部门信息
+		{Code: "system:dept:view", Name: "查看部门", MenuCode: "dept-info", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/departments/:id"),
+		}},
+		{Code: "system:dept:create", Name: "新增部门", MenuCode: "dept-info", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/departments"),
+		}},
+		{Code: "system:dept:update", Name: "编辑部门", MenuCode: "dept-info", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/departments/:id"),
+		}},
+		{Code: "system:dept:delete", Name: "删除部门", MenuCode: "dept-info", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/departments/:id"),
+		}},
+
+		// 岗位信息
+		{Code: "system:position:view", Name: "查看岗位", MenuCode: "position-info", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/positions/:id"),
+			route("GET", "/api/v1/positions/:id/users"),
+		}},
+		{Code: "system:position:create", Name: "新增岗位", MenuCode: "position-info", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/positions"),
+		}},
+		{Code: "system:position:update", Name: "编辑岗位", MenuCode: "position-info", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/positions/:id"),
+			route("POST", "/api/v1/positions/:id/users"),
+			route("DELETE", "/api/v1/positions/:id/users/:userId"),
+		}},
+		{Code: "system:position:delete", Name: "删除岗位", MenuCode: "position-info", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/positions/:id"),
+		}},
+
+		// 系统配置
+		{Code: "system:ldap:view", Name: "查看目录状态", MenuCode: "system-config", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/ldap/status"),
+		}},
+		{Code: "system:ldap:sync", Name: "同步目录", MenuCode: "system-config", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/ldap/sync"),
+		}},
+
+		// 主机管理
+		{Code: "asset:host:create", Name: "新增主机", MenuCode: "host-management", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/hosts"),
+			route("POST", "/api/v1/hosts/import"),
+			route("GET", "/api/v1/hosts/template/download"),
+		}},
+		{Code: "asset:host:update", Name: "编辑主机", MenuCode: "host-management", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/hosts/:id"),
+			route("POST", "/api/v1/hosts/:id/test"),
+			route("POST", "/api/v1/hosts/batch-collect"),
+		}},
+		{Code: "asset:host:delete", Name: "删除主机", MenuCode: "host-management", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/hosts/:id"),
+			route("POST", "/api/v1/hosts/batch-delete"),
+		}},
+
+		// 业务分组
+		{Code: "asset:group:view", Name: "查看分组", MenuCode: "business-group", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/asset-groups/:id"),
+		}},
+		{Code: "asset:group:create", Name: "新增分组", MenuCode: "business-group", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/asset-groups"),
+		}},
+		{Code: "asset:group:update", Name: "编辑分组", MenuCode: "business-group", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/asset-groups/:id"),
+		}},
+		{Code: "asset:group:delete", Name: "删除分组", MenuCode: "business-group", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/asset-groups/:id"),
+		}},
+
+		// 凭据管理
+		{Code: "asset:credential:view", Name: "查看凭据", MenuCode: "asset:credentials", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/credentials"),
+			route("GET", "/api/v1/credentials/all"),
+			route("GET", "/api/v1/credentials/:id"),
+		}},
+		{Code: "asset:credential:create", Name: "新增凭据", MenuCode: "asset:credentials", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/credentials"),
+		}},
+		{Code: "asset:credential:update", Name: "编辑凭据", MenuCode: "asset:credentials", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/credentials/:id"),
+		}},
+		{Code: "asset:credential:delete", Name: "删除凭据", MenuCode: "asset:credentials", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/credentials/:id"),
+		}},
+
+		// 云账号管理
+		{Code: "asset:cloud:view", Name: "查看云账号", MenuCode: "cloud-accounts", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/cloud-accounts"),
+			route("GET", "/api/v1/cloud-accounts/all"),
+			route("GET", "/api/v1/cloud-accounts/:id"),
+			route("GET", "/api/v1/cloud-accounts/:id/regions"),
+			route("GET", "/api/v1/cloud-accounts/:id/instances"),
+		}},
+		{Code: "asset:cloud:create", Name: "新增云账号", MenuCode: "cloud-accounts", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/cloud-accounts"),
+			route("POST", "/api/v1/cloud-accounts/import"),
+		}},
+		{Code: "asset:cloud:update", Name: "编辑云账号", MenuCode: "cloud-accounts", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/cloud-accounts/:id"),
+		}},
+		{Code: "asset:cloud:delete", Name: "删除云账号", MenuCode: "cloud-accounts", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/cloud-accounts/:id"),
+		}},
+
+		// 数据库资产
+		{Code: "asset:database:create", Name: "新增数据库", MenuCode: "asset-management", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/databases"),
+		}},
+		{Code: "asset:database:update", Name: "编辑数据库", MenuCode: "asset-management", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/databases/:id"),
+		}},
+		{Code: "asset:database:delete", Name: "删除数据库", MenuCode: "asset-management", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/databases/:id"),
+		}},
+		{Code: "asset:database:audit", Name: "查看语句审计", MenuCode: "asset-management", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/database-query-logs"),
+		}},
+
+		// 终端审计
+		{Code: "asset:terminal-audit:view", Name: "查看终端审计", MenuCode: "asset_terminal_audit", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/terminal-sessions"),
+			route("GET", "/api/v1/terminal-sessions/:id/play"),
+			route("GET", "/api/v1/terminal-policies"),
+		}},
+		{Code: "asset:terminal-policy:manage", Name: "管理命令策略", MenuCode: "asset_terminal_audit", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/terminal-policies"),
+			route("PUT", "/api/v1/terminal-policies/:id"),
+			route("DELETE", "/api/v1/terminal-policies/:id"),
+		}},
+
+		// 资产权限配置
+		{Code: "asset:permission:view", Name: "查看授权", MenuCode: "asset_permission", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/asset-permissions"),
+			route("GET", "/api/v1/asset-permissions/role/:roleId"),
+			route("GET", "/api/v1/asset-permissions/group/:assetGroupId"),
+			route("GET", "/api/v1/asset-permissions/explain"),
+			route("GET", "/api/v1/asset-permissions/:id"),
+			route("GET", "/api/v1/asset-grants"),
+			route("GET", "/api/v1/asset-grants/:id"),
+		}},
+		{Code: "asset:permission:create", Name: "新增授权", MenuCode: "asset_permission", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/asset-permissions"),
+			route("POST", "/api/v1/asset-grants"),
+		}},
+		{Code: "asset:permission:update", Name: "编辑授权", MenuCode: "asset_permission", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/asset-permissions/:id"),
+			route("PUT", "/api/v1/asset-grants/:id"),
+			route("PUT", "/api/v1/access-approvers"),
+		}},
+		{Code: "asset:permission:delete", Name: "删除授权", MenuCode: "asset_permission", Routes: []rbacbiz.PermissionRoute{
+			route("DELETE", "/api/v1/asset-permissions/:id"),
+			route("DELETE", "/api/v1/asset-permissions"),
+			route("DELETE", "/api/v1/asset-grants/:id"),
+		}},
+
+		// 操作审计：审计记录不允许通过接口删除，只能由保留策略清理
+		{Code: "audit:operation-log:view", Name: "查看操作日志", MenuCode: "operation-logs", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/operation-logs"),
+			route("GET", "/api/v1/audit/operation-logs/:id"),
+		}},
+		{Code: "audit:login-log:view", Name: "查看登录日志", MenuCode: "login-logs", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/login-logs"),
+			route("GET", "/api/v1/audit/login-logs/:id"),
+		}},
+		{Code: "audit:data-log:view", Name: "查看数据日志", MenuCode: "audit", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/data-logs"),
+			route("GET", "/api/v1/audit/data-logs/:id"),
+		}},
+		{Code: "audit:integrity:verify", Name: "校验审计完整性", MenuCode: "audit", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/integrity/verify"),
+		}},
+		{Code: "audit:log:export", Name: "导出审计日志", MenuCode: "audit", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/operation-logs/export"),
+			route("GET", "/api/v1/audit/login-logs/export"),
+			route("GET", "/api/v1/audit/data-logs/export"),
+			route("GET", "/api/v1/audit/timeline/export"),
+		}},
+		{Code: "audit:retention:view", Name: "查看保留策略和归档", MenuCode: "audit-retention", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/retention-policies"),
+			route("GET", "/api/v1/audit/archives"),
+			route("GET", "/api/v1/audit/archives/:id/records"),
+		}},
+		{Code: "audit:retention:manage", Name: "管理保留策略", MenuCode: "audit-retention", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/audit/retention-policies/:logType"),
+			route("POST", "/api/v1/audit/retention-policies/:logType/run"),
+		}},
+		{Code: "audit:archive:manage", Name: "下载和导入归档", MenuCode: "audit-retention", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/archives/:id/download"),
+			route("POST", "/api/v1/audit/archives/:id/import"),
+			route("DELETE", "/api/v1/audit/archives/:id/import"),
+		}},
+		{Code: "audit:security-event:view", Name: "查看安全事件", MenuCode: "security-events", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/security-events"),
+			route("GET", "/api/v1/audit/security-events/stats"),
+			route("GET", "/api/v1/audit/security-events/:id"),
+		}},
+		{Code: "audit:security-event:handle", Name: "处理安全事件", MenuCode: "security-events", Routes: []rbacbiz.PermissionRoute{
+			route("PUT", "/api/v1/audit/security-events/:id/handle"),
+		}},
+		{Code: "audit:timeline:view", Name: "查看活动时间线", MenuCode: "audit-timeline", Routes: []rbacbiz.PermissionRoute{
+			route("GET", "/api/v1/audit/timeline"),
+			route("GET", "/api/v1/audit/timeline/sources"),
+		}},
+
+		// 插件管理
+		{Code: "plugin:manage", Name: "启停插件", MenuCode: "plugin-list", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/plugins/:name/enable"),
+			route("POST", "/api/v1/plugins/:name/disable"),
+			route("DELETE", "/api/v1/plugins/:name/uninstall"),
+		}},
+		{Code: "plugin:install", Name: "安装插件", MenuCode: "plugin-install", Routes: []rbacbiz.PermissionRoute{
+			route("POST", "/api/v1/plugins/upload"),
+		}},
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// assetPermissionSweepInterval 过期权限清理间隔
+const assetPermissionSweepInterval = 5 * time.Minute
+
+// accessRequestExpirer 回收到期的访问申请授权
+type accessRequestExpirer interface {
+	ExpireDue(ctx context.Context) (int64, error)
+}
+
+// StartAssetPermissionSweeper 启动过期资产权限清理任务
+// 权限校验时已按时间窗口过滤，清理任务只负责把过期记录移出生效列表，并回收到期的访问申请授权
+func StartAssetPermissionSweeper(ctx context.Context, repo rbacbiz.AssetPermissionRepo, expirer accessRequestExpirer) {
+	go func() {
+		ticker := time.NewTicker(assetPermissionSweepInterval)
+		defer ticker.Stop()
+
+		sweep := func() {
+			// 先回收访问申请（集群角色需要主动解绑），再清理过期授权记录
+			if expired, err := expirer.ExpireDue(ctx); err != nil {
+				appLogger.Error("回收到期访问授权失败", zap.Error(err))
+			} else if expired > 0 {
+				appLogger.Info("已回收到期访问授权", zap.Int64("count", expired))
+			}
+
+			swept, err := repo.SweepExpired(ctx)
+			if err != nil {
+				appLogger.Error("清理过期资产权限失败", zap.Error(err))
+				return
+			}
+			if swept > 0 {
+				appLogger.Info("已清理过期资产权限", zap.Int64("count", swept))
+			}
+		}
+
+		sweep()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+}
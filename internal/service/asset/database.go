@@ -0,0 +1,316 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package asset
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/asset"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	rbacService "github.com/ydcloud-dy/opshub/internal/service/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+type DatabaseService struct {
+	databaseUseCase        *asset.DatabaseUseCase
+	assetPermissionUseCase *rbac.AssetPermissionUseCase
+}
+
+func NewDatabaseService(databaseUseCase *asset.DatabaseUseCase, assetPermissionUseCase *rbac.AssetPermissionUseCase) *DatabaseService {
+	return &DatabaseService{
+		databaseUseCase:        databaseUseCase,
+		assetPermissionUseCase: assetPermissionUseCase,
+	}
+}
+
+// CreateDatabase 创建数据库资产
+// @Summary 创建数据库资产
+// @Description 创建 MySQL/PostgreSQL/Redis 数据库资产，连接凭证引用凭证管理中的加密凭证
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body asset.DatabaseRequest true "数据库信息"
+// @Success 200 {object} response.Response{} "创建成功"
+// @Router /api/v1/databases [post]
+func (s *DatabaseService) CreateDatabase(c *gin.Context) {
+	var req asset.DatabaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	instance, err := s.databaseUseCase.Create(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "创建失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, instance)
+}
+
+// UpdateDatabase 更新数据库资产
+// @Summary 更新数据库资产
+// @Description 更新数据库资产信息
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "数据库ID"
+// @Param body body asset.DatabaseRequest true "数据库信息"
+// @Success 200 {object} response.Response{} "更新成功"
+// @Router /api/v1/databases/{id} [put]
+func (s *DatabaseService) UpdateDatabase(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+		return
+	}
+
+	var req asset.DatabaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+	req.ID = uint(id)
+
+	if err := s.databaseUseCase.Update(c.Request.Context(), &req); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", nil)
+}
+
+// DeleteDatabase 删除数据库资产
+// @Summary 删除数据库资产
+// @Description 删除指定的数据库资产
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "数据库ID"
+// @Success 200 {object} response.Response{} "删除成功"
+// @Router /api/v1/databases/{id} [delete]
+func (s *DatabaseService) DeleteDatabase(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+		return
+	}
+
+	if err := s.databaseUseCase.Delete(c.Request.Context(), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetDatabase 获取数据库资产详情
+// @Summary 获取数据库资产详情
+// @Description 获取数据库资产详情及当前用户对其拥有的操作权限
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "数据库ID"
+// @Success 200 {object} response.Response{data=asset.DatabaseInfoVO} "获取成功"
+// @Router /api/v1/databases/{id} [get]
+func (s *DatabaseService) GetDatabase(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+		return
+	}
+
+	instance, err := s.databaseUseCase.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "数据库不存在")
+		return
+	}
+
+	permissions, _ := s.assetPermissionUseCase.GetUserDatabasePermissions(c.Request.Context(), rbacService.GetUserID(c), uint(id))
+
+	response.Success(c, gin.H{
+		"database":    instance,
+		"permissions": permissions,
+		"readOnly":    permissions&rbac.PermissionDBWrite == 0,
+	})
+}
+
+// ListDatabases 数据库资产列表
+// @Summary 获取数据库资产列表
+// @Description 分页获取当前用户有权限访问的数据库资产
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键字"
+// @Param type query string false "数据库类型"
+// @Param groupId query int false "分组ID"
+// @Success 200 {object} response.Response{} "获取成功"
+// @Router /api/v1/databases [get]
+func (s *DatabaseService) ListDatabases(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	keyword := c.Query("keyword")
+	dbType := c.Query("type")
+
+	var groupID *uint
+	if groupIDStr := c.Query("groupId"); groupIDStr != "" {
+		if id, err := strconv.ParseUint(groupIDStr, 10, 32); err == nil {
+			gid := uint(id)
+			groupID = &gid
+		}
+	}
+
+	// 按资产权限过滤，出错时返回空列表以保证安全
+	var accessibleIDs []uint
+	if userID := rbacService.GetUserID(c); userID > 0 {
+		ids, err := s.assetPermissionUseCase.GetUserAccessibleDatabaseIDs(c.Request.Context(), userID)
+		if err == nil {
+			accessibleIDs = ids
+		}
+		if accessibleIDs == nil {
+			accessibleIDs = []uint{}
+		}
+	}
+
+	list, total, err := s.databaseUseCase.List(c.Request.Context(), page, pageSize, keyword, dbType, groupID, accessibleIDs)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":     list,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// TestDatabaseConnection 测试数据库连接
+// @Summary 测试数据库连接
+// @Description 使用配置的凭证测试数据库连通性并更新状态
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "数据库ID"
+// @Success 200 {object} response.Response{} "连接成功"
+// @Router /api/v1/databases/{id}/test [post]
+func (s *DatabaseService) TestDatabaseConnection(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+		return
+	}
+
+	if err := s.databaseUseCase.TestConnection(c.Request.Context(), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "连接成功", nil)
+}
+
+// ExecuteQuery 控制台执行语句
+// @Summary 执行数据库语句
+// @Description 在Web控制台执行单条语句。查询在只读事务中执行并受行数限制；未授权数据库写入的角色只能执行只读语句；危险语句会被拦截。所有语句都会记录审计日志
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "数据库ID"
+// @Param body body asset.DatabaseQueryRequest true "执行参数"
+// @Success 200 {object} response.Response{data=asset.DatabaseQueryResult} "执行成功"
+// @Failure 403 {object} response.Response "语句被拦截"
+// @Router /api/v1/databases/{id}/query [post]
+func (s *DatabaseService) ExecuteQuery(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+		return
+	}
+
+	var req asset.DatabaseQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := rbacService.GetUserID(c)
+	permissions, err := s.assetPermissionUseCase.GetUserDatabasePermissions(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "权限检查失败")
+		return
+	}
+
+	result, err := s.databaseUseCase.Execute(c.Request.Context(), uint(id), userID, rbacService.GetUsername(c),
+		c.ClientIP(), permissions&rbac.PermissionDBWrite > 0, &req)
+	if err != nil {
+		if errors.Is(err, asset.ErrStatementBlocked) {
+			response.ErrorCode(c, http.StatusForbidden, err.Error())
+			return
+		}
+		response.ErrorCode(c, http.StatusBadRequest, "执行失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ListQueryLogs 语句审计列表
+// @Summary 获取数据库语句审计记录
+// @Description 分页获取数据库控制台执行的语句记录，包括耗时、行数和拦截原因
+// @Tags 资产管理-数据库
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param databaseId query int false "数据库ID"
+// @Param username query string false "用户名"
+// @Param status query string false "状态 success/failed/blocked"
+// @Param keyword query string false "语句关键字"
+// @Success 200 {object} response.Response{} "获取成功"
+// @Router /api/v1/database-query-logs [get]
+func (s *DatabaseService) ListQueryLogs(c *gin.Context) {
+	var req asset.DatabaseQueryLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	list, total, err := s.databaseUseCase.ListQueryLogs(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Pagination(c, total, req.Page, req.PageSize, list)
+}
@@ -60,7 +60,7 @@ func toDataLogListResponse(log *audit.SysDataLog) DataLogListResponse {
 		UserID:     log.UserID,
 		Username:   log.Username,
 		RealName:   log.RealName,
-		TableName:  log.TableName,
+		TableName:  log.Table,
 		RecordID:   log.RecordID,
 		Action:     log.Action,
 		OldData:    log.OldData,
@@ -142,58 +142,3 @@ func (s *DataLogService) GetDataLog(c *gin.Context) {
 
 	response.Success(c, toDataLogListResponse(log))
 }
-
-// DeleteDataLog 删除数据日志
-// @Summary 删除数据日志
-// @Description 删除指定的数据变更日志记录
-// @Tags 审计管理-数据日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param id path int true "数据日志ID"
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/data-logs/{id} [delete]
-func (s *DataLogService) DeleteDataLog(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "无效的日志ID")
-		return
-	}
-
-	if err := s.useCase.Delete(c.Request.Context(), uint(id)); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
-
-// DeleteDataLogsBatch 批量删除数据日志
-// @Summary 批量删除数据日志
-// @Description 批量删除多条数据变更日志记录
-// @Tags 审计管理-数据日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param body body object true "日志ID列表" example({"ids": [1, 2, 3]})
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/data-logs/batch [delete]
-func (s *DataLogService) DeleteDataLogsBatch(c *gin.Context) {
-	var req struct {
-		IDs []uint `json:"ids" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
-		return
-	}
-
-	if err := s.useCase.DeleteBatch(c.Request.Context(), req.IDs); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
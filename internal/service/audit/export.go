@@ -0,0 +1,280 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+// excelCellLimit Excel 单元格最多容纳的字符数
+const excelCellLimit = 32767
+
+var operationLogExportColumns = []string{"ID", "用户名", "真实姓名", "模块", "操作", "描述", "资源类型", "资源ID", "请求方法", "请求路径", "请求参数", "状态码", "错误信息", "耗时(毫秒)", "IP地址", "用户代理", "操作时间"}
+
+var loginLogExportColumns = []string{"ID", "用户名", "真实姓名", "登录类型", "登录状态", "登录时间", "登出时间", "IP地址", "登录地点", "用户代理", "失败原因"}
+
+var dataLogExportColumns = []string{"ID", "用户名", "真实姓名", "表名", "记录ID", "操作类型", "原始数据", "新数据", "差异字段", "IP地址", "操作时间"}
+
+// ExportOperationLogs 导出操作日志
+// @Summary 导出操作日志
+// @Description 按列表筛选条件导出操作日志，受数据权限限制
+// @Tags 审计管理-操作日志
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param format query string false "导出格式 csv/xlsx/jsonl" default(xlsx)
+// @Param username query string false "用户名"
+// @Param module query string false "模块名"
+// @Param action query string false "操作"
+// @Param resourceType query string false "资源类型"
+// @Param resourceId query string false "资源ID"
+// @Param status query string false "状态"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {file} file "导出文件"
+// @Router /api/v1/audit/operation-logs/export [get]
+func (s *OperationLogService) ExportOperationLogs(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	logs, err := s.useCase.Export(c.Request.Context(), operationLogFilter(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "导出失败: "+err.Error())
+		return
+	}
+
+	records := make([][]string, 0, len(logs))
+	items := make([]interface{}, 0, len(logs))
+	for _, log := range logs {
+		records = append(records, []string{
+			strconv.FormatUint(uint64(log.ID), 10),
+			log.Username,
+			log.RealName,
+			log.Module,
+			log.Action,
+			log.Description,
+			log.ResourceType,
+			log.ResourceID,
+			log.Method,
+			log.Path,
+			log.Params,
+			strconv.Itoa(log.Status),
+			log.ErrorMsg,
+			strconv.FormatInt(log.CostTime, 10),
+			log.IP,
+			log.UserAgent,
+			log.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+		items = append(items, log)
+	}
+	writeExport(c, "operation_logs", format, operationLogExportColumns, records, items)
+}
+
+// ExportLoginLogs 导出登录日志
+// @Summary 导出登录日志
+// @Description 按列表筛选条件导出登录日志
+// @Tags 审计管理-登录日志
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param format query string false "导出格式 csv/xlsx/jsonl" default(xlsx)
+// @Param username query string false "用户名"
+// @Param loginType query string false "登录类型"
+// @Param loginStatus query string false "登录状态"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {file} file "导出文件"
+// @Router /api/v1/audit/login-logs/export [get]
+func (s *LoginLogService) ExportLoginLogs(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	logs, err := s.useCase.Export(c.Request.Context(), c.Query("username"), c.Query("loginType"), c.Query("loginStatus"), c.Query("startTime"), c.Query("endTime"))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "导出失败: "+err.Error())
+		return
+	}
+
+	records := make([][]string, 0, len(logs))
+	items := make([]interface{}, 0, len(logs))
+	for _, log := range logs {
+		resp := toLoginLogListResponse(log)
+		records = append(records, []string{
+			strconv.FormatUint(uint64(resp.ID), 10),
+			resp.Username,
+			resp.RealName,
+			resp.LoginType,
+			resp.LoginStatus,
+			resp.LoginTime,
+			resp.LogoutTime,
+			resp.IP,
+			resp.Location,
+			resp.UserAgent,
+			resp.FailReason,
+		})
+		items = append(items, log)
+	}
+	writeExport(c, "login_logs", format, loginLogExportColumns, records, items)
+}
+
+// ExportDataLogs 导出数据日志
+// @Summary 导出数据日志
+// @Description 按列表筛选条件导出数据变更日志
+// @Tags 审计管理-数据日志
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param format query string false "导出格式 csv/xlsx/jsonl" default(xlsx)
+// @Param username query string false "用户名"
+// @Param tableName query string false "表名"
+// @Param action query string false "操作类型"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {file} file "导出文件"
+// @Router /api/v1/audit/data-logs/export [get]
+func (s *DataLogService) ExportDataLogs(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	logs, err := s.useCase.Export(c.Request.Context(), c.Query("username"), c.Query("tableName"), c.Query("action"), c.Query("startTime"), c.Query("endTime"))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "导出失败: "+err.Error())
+		return
+	}
+
+	records := make([][]string, 0, len(logs))
+	items := make([]interface{}, 0, len(logs))
+	for _, log := range logs {
+		records = append(records, []string{
+			strconv.FormatUint(uint64(log.ID), 10),
+			log.Username,
+			log.RealName,
+			log.Table,
+			strconv.FormatUint(uint64(log.RecordID), 10),
+			log.Action,
+			log.OldData,
+			log.NewData,
+			log.DiffFields,
+			log.IP,
+			log.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+		items = append(items, log)
+	}
+	writeExport(c, "data_logs", format, dataLogExportColumns, records, items)
+}
+
+// exportFormat 读取并校验导出格式，不支持时直接返回错误响应
+func exportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", "xlsx"))
+	switch format {
+	case "csv", "xlsx", "jsonl":
+		return format, true
+	}
+	response.ErrorCode(c, http.StatusBadRequest, "仅支持 csv、xlsx 和 jsonl 格式")
+	return "", false
+}
+
+// writeExport 按格式输出导出文件，csv/xlsx 使用表头和行，jsonl 每行输出一条完整记录
+func writeExport(c *gin.Context, name, format string, columns []string, records [][]string, items []interface{}) {
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().Format("20060102150405"), format)
+	var buf bytes.Buffer
+
+	switch format {
+	case "jsonl":
+		enc := json.NewEncoder(&buf)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+				return
+			}
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Data(http.StatusOK, "application/x-ndjson; charset=utf-8", buf.Bytes())
+		return
+	case "csv":
+		// 写入 BOM，Excel 打开时正确识别 UTF-8
+		buf.WriteString("\xef\xbb\xbf")
+		w := csv.NewWriter(&buf)
+		w.Write(columns)
+		w.WriteAll(records)
+		if err := w.Error(); err != nil {
+			response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	sheetName := f.GetSheetName(0)
+	sw, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+		return
+	}
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E6E6FA"}, Pattern: 1},
+	})
+	sw.SetColWidth(1, len(columns), 18)
+	header := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		header = append(header, excelize.Cell{StyleID: headerStyle, Value: col})
+	}
+	sw.SetRow("A1", header)
+	for i, record := range records {
+		row := make([]interface{}, 0, len(record))
+		for _, val := range record {
+			// 超长内容截断，完整内容可导出 jsonl 查看
+			if r := []rune(val); len(r) > excelCellLimit {
+				val = string(r[:excelCellLimit])
+			}
+			row = append(row, val)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := sw.SetRow(cell, row); err != nil {
+			response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+			return
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+		return
+	}
+	if err := f.Write(&buf); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
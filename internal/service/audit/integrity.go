@@ -0,0 +1,81 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+type IntegrityService struct {
+	useCase *audit.AuditChainUseCase
+}
+
+func NewIntegrityService(useCase *audit.AuditChainUseCase) *IntegrityService {
+	return &IntegrityService{
+		useCase: useCase,
+	}
+}
+
+// VerifyIntegrity 校验审计链完整性
+// @Summary 校验审计链完整性
+// @Description 逐条校验操作日志、登录日志、数据日志和终端会话记录的哈希链与签名检查点，报告缺失、篡改和未入链的记录
+// @Tags 审计管理-完整性校验
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param chain query string false "审计链名称，为空校验全部"
+// @Success 200 {object} response.Response "校验完成"
+// @Failure 400 {object} response.Response "审计链不存在"
+// @Router /api/v1/audit/integrity/verify [get]
+func (s *IntegrityService) VerifyIntegrity(c *gin.Context) {
+	chain := c.Query("chain")
+	if chain != "" {
+		known := false
+		for _, ch := range s.useCase.Chains() {
+			if ch.Name == chain {
+				known = true
+				break
+			}
+		}
+		if !known {
+			response.ErrorCode(c, http.StatusBadRequest, "审计链不存在: "+chain)
+			return
+		}
+	}
+
+	reports, err := s.useCase.Verify(c.Request.Context(), chain)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "校验失败: "+err.Error())
+		return
+	}
+
+	valid := true
+	for _, r := range reports {
+		valid = valid && r.Valid
+	}
+	response.Success(c, gin.H{
+		"valid":   valid,
+		"reports": reports,
+	})
+}
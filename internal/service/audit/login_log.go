@@ -145,58 +145,3 @@ func (s *LoginLogService) GetLoginLog(c *gin.Context) {
 
 	response.Success(c, toLoginLogListResponse(log))
 }
-
-// DeleteLoginLog 删除登录日志
-// @Summary 删除登录日志
-// @Description 删除指定的登录日志记录
-// @Tags 审计管理-登录日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param id path int true "登录日志ID"
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/login-logs/{id} [delete]
-func (s *LoginLogService) DeleteLoginLog(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "无效的日志ID")
-		return
-	}
-
-	if err := s.useCase.Delete(c.Request.Context(), uint(id)); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
-
-// DeleteLoginLogsBatch 批量删除登录日志
-// @Summary 批量删除登录日志
-// @Description 批量删除多条登录日志记录
-// @Tags 审计管理-登录日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param body body object true "日志ID列表" example({"ids": [1, 2, 3]})
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/login-logs/batch [delete]
-func (s *LoginLogService) DeleteLoginLogsBatch(c *gin.Context) {
-	var req struct {
-		IDs []uint `json:"ids" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
-		return
-	}
-
-	if err := s.useCase.DeleteBatch(c.Request.Context(), req.IDs); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
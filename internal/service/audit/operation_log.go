@@ -40,44 +40,64 @@ func NewOperationLogService(useCase *audit.OperationLogUseCase) *OperationLogSer
 
 // OperationLogListResponse 操作日志列表响应
 type OperationLogListResponse struct {
-	ID          uint   `json:"id"`
-	UserID      uint   `json:"userId"`
-	Username    string `json:"username"`
-	RealName    string `json:"realName"`
-	Module      string `json:"module"`
-	Action      string `json:"action"`
-	Description string `json:"description"`
-	Method      string `json:"method"`
-	Path        string `json:"path"`
-	Status      int    `json:"status"`
-	ErrorMsg    string `json:"errorMsg"`
-	CostTime    int64  `json:"costTime"`
-	IP          string `json:"ip"`
-	CreatedAt   string `json:"createdAt"`
+	ID           uint   `json:"id"`
+	UserID       uint   `json:"userId"`
+	Username     string `json:"username"`
+	RealName     string `json:"realName"`
+	Module       string `json:"module"`
+	Action       string `json:"action"`
+	Description  string `json:"description"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Route        string `json:"route"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+	Status       int    `json:"status"`
+	ErrorMsg     string `json:"errorMsg"`
+	CostTime     int64  `json:"costTime"`
+	IP           string `json:"ip"`
+	CreatedAt    string `json:"createdAt"`
 }
 
 func toOperationLogListResponse(log *audit.SysOperationLog) OperationLogListResponse {
 	return OperationLogListResponse{
-		ID:          log.ID,
-		UserID:      log.UserID,
-		Username:    log.Username,
-		RealName:    log.RealName,
-		Module:      log.Module,
-		Action:      log.Action,
-		Description: log.Description,
-		Method:      log.Method,
-		Path:        log.Path,
-		Status:      log.Status,
-		ErrorMsg:    log.ErrorMsg,
-		CostTime:    log.CostTime,
-		IP:          log.IP,
-		CreatedAt:   log.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:           log.ID,
+		UserID:       log.UserID,
+		Username:     log.Username,
+		RealName:     log.RealName,
+		Module:       log.Module,
+		Action:       log.Action,
+		Description:  log.Description,
+		Method:       log.Method,
+		Path:         log.Path,
+		Route:        log.Route,
+		ResourceType: log.ResourceType,
+		ResourceID:   log.ResourceID,
+		Status:       log.Status,
+		ErrorMsg:     log.ErrorMsg,
+		CostTime:     log.CostTime,
+		IP:           log.IP,
+		CreatedAt:    log.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// operationLogFilter 从查询参数读取操作日志筛选条件
+func operationLogFilter(c *gin.Context) audit.OperationLogFilter {
+	return audit.OperationLogFilter{
+		Username:     c.Query("username"),
+		Module:       c.Query("module"),
+		Action:       c.Query("action"),
+		Status:       c.Query("status"),
+		ResourceType: c.Query("resourceType"),
+		ResourceID:   c.Query("resourceId"),
+		StartTime:    c.Query("startTime"),
+		EndTime:      c.Query("endTime"),
 	}
 }
 
 // ListOperationLogs 操作日志列表
 // @Summary 获取操作日志列表
-// @Description 分页获取系统操作日志，支持按用户、模块、操作、状态和时间范围筛选
+// @Description 分页获取系统操作日志，支持按用户、模块、操作、资源、状态和时间范围筛选
 // @Tags 审计管理-操作日志
 // @Accept json
 // @Produce json
@@ -87,6 +107,8 @@ func toOperationLogListResponse(log *audit.SysOperationLog) OperationLogListResp
 // @Param username query string false "用户名"
 // @Param module query string false "模块名"
 // @Param action query string false "操作"
+// @Param resourceType query string false "资源类型"
+// @Param resourceId query string false "资源ID"
 // @Param status query string false "状态"
 // @Param startTime query string false "开始时间"
 // @Param endTime query string false "结束时间"
@@ -95,14 +117,8 @@ func toOperationLogListResponse(log *audit.SysOperationLog) OperationLogListResp
 func (s *OperationLogService) ListOperationLogs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
-	username := c.Query("username")
-	module := c.Query("module")
-	action := c.Query("action")
-	status := c.Query("status")
-	startTime := c.Query("startTime")
-	endTime := c.Query("endTime")
 
-	logs, total, err := s.useCase.List(c.Request.Context(), page, pageSize, username, module, action, status, startTime, endTime)
+	logs, total, err := s.useCase.List(c.Request.Context(), page, pageSize, operationLogFilter(c))
 	if err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
 		return
@@ -147,58 +163,3 @@ func (s *OperationLogService) GetOperationLog(c *gin.Context) {
 
 	response.Success(c, toOperationLogListResponse(log))
 }
-
-// DeleteOperationLog 删除操作日志
-// @Summary 删除操作日志
-// @Description 删除指定的操作日志记录
-// @Tags 审计管理-操作日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param id path int true "操作日志ID"
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/operation-logs/{id} [delete]
-func (s *OperationLogService) DeleteOperationLog(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "无效的日志ID")
-		return
-	}
-
-	if err := s.useCase.Delete(c.Request.Context(), uint(id)); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
-
-// DeleteOperationLogsBatch 批量删除操作日志
-// @Summary 批量删除操作日志
-// @Description 批量删除多条操作日志记录
-// @Tags 审计管理-操作日志
-// @Accept json
-// @Produce json
-// @Security Bearer
-// @Param body body object true "日志ID列表" example({"ids": [1, 2, 3]})
-// @Success 200 {object} response.Response "删除成功"
-// @Failure 400 {object} response.Response "参数错误"
-// @Router /api/v1/audit/operation-logs/batch [delete]
-func (s *OperationLogService) DeleteOperationLogsBatch(c *gin.Context) {
-	var req struct {
-		IDs []uint `json:"ids" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
-		return
-	}
-
-	if err := s.useCase.DeleteBatch(c.Request.Context(), req.IDs); err != nil {
-		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
-		return
-	}
-
-	response.SuccessWithMessage(c, "删除成功", nil)
-}
@@ -0,0 +1,261 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"gorm.io/gorm"
+)
+
+type RetentionService struct {
+	useCase *audit.AuditRetentionUseCase
+}
+
+func NewRetentionService(useCase *audit.AuditRetentionUseCase) *RetentionService {
+	return &RetentionService{
+		useCase: useCase,
+	}
+}
+
+// UpdateRetentionPolicyRequest 修改保留策略请求
+type UpdateRetentionPolicyRequest struct {
+	RetentionDays int  `json:"retentionDays" binding:"required,min=1,max=3650"`
+	Enabled       bool `json:"enabled"`
+}
+
+// ListRetentionPolicies 保留策略列表
+// @Summary 获取审计日志保留策略
+// @Description 获取操作日志、登录日志和数据日志的保留天数、启用状态和最后执行结果
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/retention-policies [get]
+func (s *RetentionService) ListRetentionPolicies(c *gin.Context) {
+	policies, err := s.useCase.ListPolicies(c.Request.Context())
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, policies)
+}
+
+// UpdateRetentionPolicy 修改保留策略
+// @Summary 修改审计日志保留策略
+// @Description 修改日志类型的保留天数和启用状态，启用后定时归档并清理超过保留天数的记录
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param logType path string true "日志类型 operation_log/login_log/data_log"
+// @Param body body UpdateRetentionPolicyRequest true "保留策略"
+// @Success 200 {object} response.Response "修改成功"
+// @Router /api/v1/audit/retention-policies/{logType} [put]
+func (s *RetentionService) UpdateRetentionPolicy(c *gin.Context) {
+	var req UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+	if err := s.useCase.UpdatePolicy(c.Request.Context(), c.Param("logType"), req.RetentionDays, req.Enabled); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "修改成功", nil)
+}
+
+// RunRetentionPolicy 立即执行保留策略
+// @Summary 立即执行审计日志保留策略
+// @Description 在后台归档并清理超过保留天数的记录，执行结果记录在策略的最后执行状态中
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param logType path string true "日志类型 operation_log/login_log/data_log"
+// @Success 200 {object} response.Response "已开始执行"
+// @Failure 409 {object} response.Response "正在执行中"
+// @Router /api/v1/audit/retention-policies/{logType}/run [post]
+func (s *RetentionService) RunRetentionPolicy(c *gin.Context) {
+	if err := s.useCase.Run(c.Request.Context(), c.Param("logType")); err != nil {
+		response.ErrorCode(c, http.StatusConflict, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "已开始执行", nil)
+}
+
+// ListArchives 归档列表
+// @Summary 获取审计日志归档列表
+// @Description 分页获取保留策略生成的归档文件，包含记录数、链序号范围和文件摘要
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param logType query string false "日志类型"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/archives [get]
+func (s *RetentionService) ListArchives(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	archives, total, err := s.useCase.ListArchives(c.Request.Context(), page, pageSize, c.Query("logType"))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, gin.H{
+		"list":     archives,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	})
+}
+
+// DownloadArchive 下载归档文件
+// @Summary 下载审计日志归档文件
+// @Description 下载 gzip 压缩的 JSON Lines 归档文件
+// @Tags 审计管理-日志归档
+// @Produce application/gzip
+// @Security Bearer
+// @Param id path int true "归档ID"
+// @Success 200 {file} file "归档文件"
+// @Router /api/v1/audit/archives/{id}/download [get]
+func (s *RetentionService) DownloadArchive(c *gin.Context) {
+	id, ok := archiveID(c)
+	if !ok {
+		return
+	}
+	archive, r, err := s.useCase.OpenArchive(c.Request.Context(), id)
+	if err != nil {
+		archiveError(c, err)
+		return
+	}
+	defer r.Close()
+
+	c.DataFromReader(http.StatusOK, archive.FileSize, "application/gzip", r, map[string]string{
+		"Content-Disposition": "attachment; filename=" + path.Base(archive.ObjectKey),
+	})
+}
+
+// ImportArchive 导入归档
+// @Summary 导入审计日志归档
+// @Description 校验归档签名和文件摘要后，将归档记录导入独立的调查表，不影响在线审计日志
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "归档ID"
+// @Success 200 {object} response.Response "导入成功"
+// @Router /api/v1/audit/archives/{id}/import [post]
+func (s *RetentionService) ImportArchive(c *gin.Context) {
+	id, ok := archiveID(c)
+	if !ok {
+		return
+	}
+	count, err := s.useCase.ImportArchive(c.Request.Context(), id)
+	if err != nil {
+		archiveError(c, err)
+		return
+	}
+	response.SuccessWithMessage(c, "导入成功", gin.H{"count": count})
+}
+
+// UnloadArchive 卸载归档
+// @Summary 卸载已导入的审计日志归档
+// @Description 删除为调查导入的归档记录，归档文件保留
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "归档ID"
+// @Success 200 {object} response.Response "卸载成功"
+// @Router /api/v1/audit/archives/{id}/import [delete]
+func (s *RetentionService) UnloadArchive(c *gin.Context) {
+	id, ok := archiveID(c)
+	if !ok {
+		return
+	}
+	if err := s.useCase.UnloadArchive(c.Request.Context(), id); err != nil {
+		archiveError(c, err)
+		return
+	}
+	response.SuccessWithMessage(c, "卸载成功", nil)
+}
+
+// ListArchiveRecords 已导入的归档记录
+// @Summary 查询已导入的归档记录
+// @Description 分页查询导入调查表的归档记录，支持按用户名和内容关键字筛选
+// @Tags 审计管理-日志归档
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "归档ID"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param username query string false "用户名"
+// @Param keyword query string false "内容关键字"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/archives/{id}/records [get]
+func (s *RetentionService) ListArchiveRecords(c *gin.Context) {
+	id, ok := archiveID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	records, total, err := s.useCase.ListArchiveRecords(c.Request.Context(), id, page, pageSize, c.Query("username"), c.Query("keyword"))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, gin.H{
+		"list":     records,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	})
+}
+
+func archiveID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的归档ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func archiveError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.ErrorCode(c, http.StatusNotFound, "归档不存在")
+		return
+	}
+	response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	rbacService "github.com/ydcloud-dy/opshub/internal/service/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+type SecurityEventService struct {
+	useCase *audit.SecurityEventUseCase
+}
+
+func NewSecurityEventService(useCase *audit.SecurityEventUseCase) *SecurityEventService {
+	return &SecurityEventService{
+		useCase: useCase,
+	}
+}
+
+// SecurityEventResponse 安全事件响应
+type SecurityEventResponse struct {
+	ID          uint                   `json:"id"`
+	EventType   string                 `json:"eventType"`
+	EventName   string                 `json:"eventName"`
+	Level       string                 `json:"level"`
+	UserID      uint                   `json:"userId"`
+	Username    string                 `json:"username"`
+	IP          string                 `json:"ip"`
+	Location    string                 `json:"location"`
+	LoginLogID  uint                   `json:"loginLogId"`
+	Title       string                 `json:"title"`
+	Detail      map[string]interface{} `json:"detail"`
+	Status      string                 `json:"status"`
+	Notified    bool                   `json:"notified"`
+	NotifyError string                 `json:"notifyError"`
+	HandlerName string                 `json:"handlerName"`
+	HandledAt   string                 `json:"handledAt"`
+	Remark      string                 `json:"remark"`
+	CreatedAt   string                 `json:"createdAt"`
+}
+
+func toSecurityEventResponse(event *audit.SysSecurityEvent) SecurityEventResponse {
+	resp := SecurityEventResponse{
+		ID:          event.ID,
+		EventType:   event.EventType,
+		EventName:   audit.SecurityEventNames[event.EventType],
+		Level:       event.Level,
+		UserID:      event.UserID,
+		Username:    event.Username,
+		IP:          event.IP,
+		Location:    event.Location,
+		LoginLogID:  event.LoginLogID,
+		Title:       event.Title,
+		Status:      event.Status,
+		Notified:    event.Notified,
+		NotifyError: event.NotifyError,
+		HandlerName: event.HandlerName,
+		Remark:      event.Remark,
+		CreatedAt:   event.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	_ = json.Unmarshal([]byte(event.Detail), &resp.Detail)
+	if event.HandledAt != nil {
+		resp.HandledAt = event.HandledAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// HandleSecurityEventRequest 处理安全事件请求
+type HandleSecurityEventRequest struct {
+	Status string `json:"status" binding:"required,oneof=open resolved ignored"`
+	Remark string `json:"remark" binding:"max=500"`
+}
+
+// ListSecurityEvents 安全事件列表
+// @Summary 获取安全事件列表
+// @Description 分页获取登录异常检测发现的安全事件，支持按类型、等级、状态、用户名、IP和时间范围筛选
+// @Tags 审计管理-安全事件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param eventType query string false "事件类型"
+// @Param level query string false "等级 info/warning/critical"
+// @Param status query string false "处理状态 open/resolved/ignored"
+// @Param username query string false "用户名"
+// @Param ip query string false "IP地址"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/security-events [get]
+func (s *SecurityEventService) ListSecurityEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	filter := audit.SecurityEventFilter{
+		EventType: c.Query("eventType"),
+		Level:     c.Query("level"),
+		Status:    c.Query("status"),
+		Username:  c.Query("username"),
+		IP:        c.Query("ip"),
+		StartTime: c.Query("startTime"),
+		EndTime:   c.Query("endTime"),
+	}
+
+	events, total, err := s.useCase.List(c.Request.Context(), page, pageSize, filter)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	list := make([]SecurityEventResponse, 0, len(events))
+	for _, event := range events {
+		list = append(list, toSecurityEventResponse(event))
+	}
+
+	response.Success(c, gin.H{
+		"list":     list,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	})
+}
+
+// GetSecurityEventStats 安全事件统计
+// @Summary 获取安全事件统计
+// @Description 获取未处理事件数和最近7天各类事件数量
+// @Tags 审计管理-安全事件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/security-events/stats [get]
+func (s *SecurityEventService) GetSecurityEventStats(c *gin.Context) {
+	stats, err := s.useCase.Stats(c.Request.Context())
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+	response.Success(c, stats)
+}
+
+// GetSecurityEvent 获取安全事件详情
+// @Summary 获取安全事件详情
+// @Description 获取单个安全事件及其检测依据
+// @Tags 审计管理-安全事件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "安全事件ID"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 404 {object} response.Response "事件不存在"
+// @Router /api/v1/audit/security-events/{id} [get]
+func (s *SecurityEventService) GetSecurityEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的事件ID")
+		return
+	}
+
+	event, err := s.useCase.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "事件不存在")
+		return
+	}
+
+	response.Success(c, toSecurityEventResponse(event))
+}
+
+// HandleSecurityEvent 处理安全事件
+// @Summary 处理安全事件
+// @Description 将安全事件标记为已处理、已忽略或重新打开
+// @Tags 审计管理-安全事件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "安全事件ID"
+// @Param body body HandleSecurityEventRequest true "处理结果"
+// @Success 200 {object} response.Response "处理成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/audit/security-events/{id}/handle [put]
+func (s *SecurityEventService) HandleSecurityEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的事件ID")
+		return
+	}
+
+	var req HandleSecurityEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := s.useCase.Handle(c.Request.Context(), uint(id), req.Status, rbacService.GetUserID(c), rbacService.GetUsername(c), req.Remark); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "处理成功", nil)
+}
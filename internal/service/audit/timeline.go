@@ -0,0 +1,218 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+var timelineExportColumns = []string{"时间", "结束时间", "来源", "用户名", "操作", "摘要", "资源类型", "资源ID", "状态", "IP地址", "详情接口"}
+
+// defaultTimelineDays 未指定时间范围时查询最近的天数
+const defaultTimelineDays = 7
+
+type TimelineService struct {
+	useCase *audit.TimelineUseCase
+}
+
+func NewTimelineService(useCase *audit.TimelineUseCase) *TimelineService {
+	return &TimelineService{
+		useCase: useCase,
+	}
+}
+
+// UseCase 返回时间线用例，用于登记插件提供的来源
+func (s *TimelineService) UseCase() *audit.TimelineUseCase {
+	return s.useCase
+}
+
+// parseTimelineTime 解析时间参数，支持日期和日期时间，endOfDay 为 true 时日期取次日零点
+func parseTimelineTime(value string, endOfDay bool) (time.Time, bool) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
+// timelineQuery 从查询参数读取时间线条件，参数错误时直接返回错误响应
+func timelineQuery(c *gin.Context) (audit.TimelineQuery, []string, bool) {
+	query := audit.TimelineQuery{
+		Username:     strings.TrimSpace(c.Query("username")),
+		ResourceType: c.Query("resourceType"),
+		ResourceID:   c.Query("resourceId"),
+		Ascending:    c.Query("order") == "asc",
+	}
+	if userID := c.Query("userId"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+			return query, nil, false
+		}
+		query.UserID = uint(id)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, _ = strconv.Atoi(limit)
+	}
+
+	query.EndTime = time.Now()
+	if endTime := c.Query("endTime"); endTime != "" {
+		t, ok := parseTimelineTime(endTime, true)
+		if !ok {
+			response.ErrorCode(c, http.StatusBadRequest, "无效的结束时间")
+			return query, nil, false
+		}
+		query.EndTime = t
+	}
+	query.StartTime = query.EndTime.AddDate(0, 0, -defaultTimelineDays)
+	if startTime := c.Query("startTime"); startTime != "" {
+		t, ok := parseTimelineTime(startTime, false)
+		if !ok {
+			response.ErrorCode(c, http.StatusBadRequest, "无效的开始时间")
+			return query, nil, false
+		}
+		query.StartTime = t
+	}
+
+	var sources []string
+	if value := c.Query("sources"); value != "" {
+		sources = strings.Split(value, ",")
+	}
+	return query, sources, true
+}
+
+// GetTimeline 活动时间线
+// @Summary 获取活动时间线
+// @Description 合并操作日志、登录日志、安全事件、SSH 会话和插件记录（K8s 会话、任务执行），按用户或资源查看时间范围内的全部活动
+// @Tags 审计管理-活动时间线
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId query int false "用户ID"
+// @Param username query string false "用户名"
+// @Param resourceType query string false "资源类型，如 host、k8s_cluster、job_task"
+// @Param resourceId query string false "资源ID"
+// @Param startTime query string false "开始时间，默认结束时间前 7 天"
+// @Param endTime query string false "结束时间，默认当前时间"
+// @Param sources query string false "来源，多个用逗号分隔，默认全部"
+// @Param order query string false "排序 asc/desc" default(desc)
+// @Param limit query int false "最多返回条数" default(1000)
+// @Success 200 {object} response.Response{data=audit.TimelineResult} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/audit/timeline [get]
+func (s *TimelineService) GetTimeline(c *gin.Context) {
+	query, sources, ok := timelineQuery(c)
+	if !ok {
+		return
+	}
+	result, err := s.useCase.Query(c.Request.Context(), query, sources)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, result)
+}
+
+// ListTimelineSources 时间线来源列表
+// @Summary 获取时间线来源
+// @Description 获取内置和已启用插件提供的时间线来源
+// @Tags 审计管理-活动时间线
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/audit/timeline/sources [get]
+func (s *TimelineService) ListTimelineSources(c *gin.Context) {
+	response.Success(c, s.useCase.Sources())
+}
+
+// ExportTimeline 导出活动时间线
+// @Summary 导出活动时间线
+// @Description 按查询条件导出活动时间线，用于事件调查报告
+// @Tags 审计管理-活动时间线
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param format query string false "导出格式 csv/xlsx/jsonl" default(xlsx)
+// @Param userId query int false "用户ID"
+// @Param username query string false "用户名"
+// @Param resourceType query string false "资源类型"
+// @Param resourceId query string false "资源ID"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Param sources query string false "来源，多个用逗号分隔"
+// @Param order query string false "排序 asc/desc" default(desc)
+// @Success 200 {file} file "导出文件"
+// @Router /api/v1/audit/timeline/export [get]
+func (s *TimelineService) ExportTimeline(c *gin.Context) {
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	query, sources, ok := timelineQuery(c)
+	if !ok {
+		return
+	}
+	query.Limit = audit.MaxTimelineLimit
+	result, err := s.useCase.Query(c.Request.Context(), query, sources)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "导出失败: "+err.Error())
+		return
+	}
+	if result.Truncated {
+		response.ErrorCode(c, http.StatusBadRequest, "导出失败: 时间线超过 "+strconv.Itoa(audit.MaxTimelineLimit)+" 条，请缩小时间范围")
+		return
+	}
+
+	records := make([][]string, 0, len(result.Entries))
+	items := make([]interface{}, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		endTime := ""
+		if entry.EndTime != nil {
+			endTime = entry.EndTime.Format("2006-01-02 15:04:05")
+		}
+		records = append(records, []string{
+			entry.Time.Format("2006-01-02 15:04:05"),
+			endTime,
+			entry.SourceName,
+			entry.Username,
+			entry.Action,
+			entry.Title,
+			entry.ResourceType,
+			entry.ResourceID,
+			entry.Status,
+			entry.IP,
+			entry.DetailURL,
+		})
+		items = append(items, entry)
+	}
+	writeExport(c, "timeline", format, timelineExportColumns, records, items)
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+type AccessRequestService struct {
+	accessRequestUseCase *rbac.AccessRequestUseCase
+}
+
+func NewAccessRequestService(accessRequestUseCase *rbac.AccessRequestUseCase) *AccessRequestService {
+	return &AccessRequestService{
+		accessRequestUseCase: accessRequestUseCase,
+	}
+}
+
+// CreateAccessRequest 提交访问申请
+// @Summary 提交访问申请
+// @Description 申请主机的终端/文件权限或集群角色，审批通过后在申请时长内临时生效
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.AccessRequestCreateReq true "申请信息"
+// @Success 200 {object} response.Response{data=rbac.SysAccessRequest} "提交成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests [post]
+func (s *AccessRequestService) CreateAccessRequest(c *gin.Context) {
+	var req rbac.AccessRequestCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	ar, err := s.accessRequestUseCase.Submit(c.Request.Context(), GetUserID(c), GetUsername(c), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "提交失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "提交成功", ar)
+}
+
+// ListAccessRequests 访问申请列表
+// @Summary 获取访问申请列表
+// @Description scope=mine 查询我的申请，scope=todo 查询待我审批的申请，scope=all 查询全部（仅管理员）
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param scope query string false "范围 mine/todo/all" default(mine)
+// @Param status query string false "状态"
+// @Param resourceType query string false "资源类型 host/cluster"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/access-requests [get]
+func (s *AccessRequestService) ListAccessRequests(c *gin.Context) {
+	var req rbac.AccessRequestListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	list, total, err := s.accessRequestUseCase.List(c.Request.Context(), &req, GetUserID(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"total": total,
+		"list":  list,
+	})
+}
+
+// GetAccessRequest 获取访问申请详情
+// @Summary 获取访问申请详情
+// @Description 获取访问申请及其完整流转记录
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "申请ID"
+// @Success 200 {object} response.Response{data=rbac.AccessRequestDetailVO} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests/{id} [get]
+func (s *AccessRequestService) GetAccessRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的申请ID")
+		return
+	}
+
+	detail, err := s.accessRequestUseCase.GetDetail(c.Request.Context(), uint(id), GetUserID(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// ApproveAccessRequest 批准访问申请
+// @Summary 批准访问申请
+// @Description 批准后创建到期自动失效的临时授权
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "申请ID"
+// @Param body body rbac.AccessRequestReviewReq false "审批意见"
+// @Success 200 {object} response.Response "审批成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests/{id}/approve [post]
+func (s *AccessRequestService) ApproveAccessRequest(c *gin.Context) {
+	s.review(c, s.accessRequestUseCase.Approve, "审批成功")
+}
+
+// RejectAccessRequest 驳回访问申请
+// @Summary 驳回访问申请
+// @Description 驳回待审批的访问申请
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "申请ID"
+// @Param body body rbac.AccessRequestReviewReq false "驳回意见"
+// @Success 200 {object} response.Response "驳回成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests/{id}/reject [post]
+func (s *AccessRequestService) RejectAccessRequest(c *gin.Context) {
+	s.review(c, s.accessRequestUseCase.Reject, "驳回成功")
+}
+
+// RevokeAccessRequest 提前收回授权
+// @Summary 收回访问授权
+// @Description 在到期前收回已批准的临时授权
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "申请ID"
+// @Param body body rbac.AccessRequestReviewReq false "收回原因"
+// @Success 200 {object} response.Response "收回成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests/{id}/revoke [post]
+func (s *AccessRequestService) RevokeAccessRequest(c *gin.Context) {
+	s.review(c, s.accessRequestUseCase.Revoke, "收回成功")
+}
+
+// CancelAccessRequest 撤回访问申请
+// @Summary 撤回访问申请
+// @Description 申请人撤回待审批的申请
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "申请ID"
+// @Success 200 {object} response.Response "撤回成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-requests/{id}/cancel [post]
+func (s *AccessRequestService) CancelAccessRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的申请ID")
+		return
+	}
+
+	if err := s.accessRequestUseCase.Cancel(c.Request.Context(), uint(id), GetUserID(c), GetUsername(c)); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "撤回成功", nil)
+}
+
+// review 处理审批类操作
+func (s *AccessRequestService) review(c *gin.Context, action func(ctx context.Context, id, operatorID uint, operatorName, comment string) error, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的申请ID")
+		return
+	}
+
+	var req rbac.AccessRequestReviewReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+			return
+		}
+	}
+
+	if err := action(c.Request.Context(), uint(id), GetUserID(c), GetUsername(c), req.Comment); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, message, nil)
+}
+
+// ListAccessApprovers 获取审批人配置
+// @Summary 获取审批人配置
+// @Description 获取资产分组或部门的访问申请审批人
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param scopeType query string true "范围类型 asset_group/department"
+// @Param scopeId query int true "资产分组ID或部门ID"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/access-approvers [get]
+func (s *AccessRequestService) ListAccessApprovers(c *gin.Context) {
+	scopeType := c.Query("scopeType")
+	scopeID, err := strconv.ParseUint(c.Query("scopeId"), 10, 32)
+	if scopeType == "" || err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误")
+		return
+	}
+
+	approvers, err := s.accessRequestUseCase.ListApprovers(c.Request.Context(), scopeType, uint(scopeID))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, approvers)
+}
+
+// SetAccessApprovers 设置审批人
+// @Summary 设置审批人
+// @Description 设置资产分组或部门的访问申请审批人（覆盖原有配置）
+// @Tags 访问申请
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.AccessApproverReq true "审批人配置"
+// @Success 200 {object} response.Response "设置成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/access-approvers [put]
+func (s *AccessRequestService) SetAccessApprovers(c *gin.Context) {
+	var req rbac.AccessApproverReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := s.accessRequestUseCase.SetApprovers(c.Request.Context(), GetUserID(c), &req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "设置失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "设置成功", nil)
+}
+
+// ExpireDue 回收已到期的访问授权
+func (s *AccessRequestService) ExpireDue(ctx context.Context) (int64, error) {
+	return s.accessRequestUseCase.ExpireDue(ctx)
+}
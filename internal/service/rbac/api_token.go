@@ -0,0 +1,286 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+// APITokenService API 令牌和服务账号服务
+type APITokenService struct {
+	apiTokenUseCase *rbac.APITokenUseCase
+}
+
+// NewAPITokenService 创建 API 令牌服务
+func NewAPITokenService(apiTokenUseCase *rbac.APITokenUseCase) *APITokenService {
+	return &APITokenService{apiTokenUseCase: apiTokenUseCase}
+}
+
+// requireSession 令牌管理只允许登录会话操作，不能用 API 令牌签发新令牌
+func requireSession(c *gin.Context) bool {
+	if GetAPITokenID(c) != 0 {
+		response.ErrorCode(c, http.StatusForbidden, "请登录后管理令牌，不能使用 API 令牌操作")
+		return false
+	}
+	return true
+}
+
+// ListMyAPITokens 获取个人令牌
+// @Summary 获取个人 API 令牌
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]rbac.SysAPIToken} "获取成功"
+// @Router /api/v1/profile/api-tokens [get]
+func (s *APITokenService) ListMyAPITokens(c *gin.Context) {
+	tokens, err := s.apiTokenUseCase.ListByUser(c.Request.Context(), GetUserID(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取令牌失败: "+err.Error())
+		return
+	}
+	response.Success(c, tokens)
+}
+
+// CreateMyAPIToken 创建个人令牌
+// @Summary 创建个人 API 令牌
+// @Description 令牌以当前用户身份访问接口，权限不超过用户本身，明文只返回一次
+// @Tags API令牌
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.APITokenReq true "令牌信息"
+// @Success 200 {object} response.Response{data=rbac.APITokenCreatedVO} "创建成功"
+// @Router /api/v1/profile/api-tokens [post]
+func (s *APITokenService) CreateMyAPIToken(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	var req rbac.APITokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := GetUserID(c)
+	vo, err := s.apiTokenUseCase.Create(c.Request.Context(), userID, userID, &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, vo)
+}
+
+// RevokeMyAPIToken 吊销个人令牌
+// @Summary 吊销个人 API 令牌
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Param id path int true "令牌ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Router /api/v1/profile/api-tokens/{id} [delete]
+func (s *APITokenService) RevokeMyAPIToken(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的令牌ID")
+		return
+	}
+	if err := s.apiTokenUseCase.Revoke(c.Request.Context(), GetUserID(c), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusNotFound, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "吊销成功", nil)
+}
+
+// ListServiceAccounts 获取服务账号列表
+// @Summary 获取服务账号列表
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/service-accounts [get]
+func (s *APITokenService) ListServiceAccounts(c *gin.Context) {
+	accounts, err := s.apiTokenUseCase.ListServiceAccounts(c.Request.Context())
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取服务账号失败: "+err.Error())
+		return
+	}
+	for _, a := range accounts {
+		a.Password = ""
+	}
+	response.Success(c, gin.H{"total": len(accounts), "list": accounts})
+}
+
+// CreateServiceAccount 创建服务账号
+// @Summary 创建服务账号
+// @Description 服务账号不能登录，只能通过 API 令牌访问，权限由分配的角色决定
+// @Tags API令牌
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.ServiceAccountReq true "服务账号信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Router /api/v1/service-accounts [post]
+func (s *APITokenService) CreateServiceAccount(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	var req rbac.ServiceAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	account, err := s.apiTokenUseCase.CreateServiceAccount(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, account)
+}
+
+// DeleteServiceAccount 删除服务账号
+// @Summary 删除服务账号
+// @Description 删除服务账号并吊销其全部令牌
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务账号ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Router /api/v1/service-accounts/{id} [delete]
+func (s *APITokenService) DeleteServiceAccount(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的服务账号ID")
+		return
+	}
+	if err := s.apiTokenUseCase.DeleteServiceAccount(c.Request.Context(), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// ListServiceAccountTokens 获取服务账号的令牌
+// @Summary 获取服务账号令牌
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务账号ID"
+// @Success 200 {object} response.Response{data=[]rbac.SysAPIToken} "获取成功"
+// @Router /api/v1/service-accounts/{id}/tokens [get]
+func (s *APITokenService) ListServiceAccountTokens(c *gin.Context) {
+	account, ok := s.serviceAccount(c)
+	if !ok {
+		return
+	}
+	tokens, err := s.apiTokenUseCase.ListByUser(c.Request.Context(), account.ID)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取令牌失败: "+err.Error())
+		return
+	}
+	response.Success(c, tokens)
+}
+
+// CreateServiceAccountToken 为服务账号创建令牌
+// @Summary 创建服务账号令牌
+// @Tags API令牌
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务账号ID"
+// @Param body body rbac.APITokenReq true "令牌信息"
+// @Success 200 {object} response.Response{data=rbac.APITokenCreatedVO} "创建成功"
+// @Router /api/v1/service-accounts/{id}/tokens [post]
+func (s *APITokenService) CreateServiceAccountToken(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	account, ok := s.serviceAccount(c)
+	if !ok {
+		return
+	}
+	var req rbac.APITokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	vo, err := s.apiTokenUseCase.Create(c.Request.Context(), account.ID, GetUserID(c), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, vo)
+}
+
+// RevokeServiceAccountToken 吊销服务账号令牌
+// @Summary 吊销服务账号令牌
+// @Tags API令牌
+// @Produce json
+// @Security Bearer
+// @Param id path int true "服务账号ID"
+// @Param tokenId path int true "令牌ID"
+// @Success 200 {object} response.Response "吊销成功"
+// @Router /api/v1/service-accounts/{id}/tokens/{tokenId} [delete]
+func (s *APITokenService) RevokeServiceAccountToken(c *gin.Context) {
+	if !requireSession(c) {
+		return
+	}
+	account, ok := s.serviceAccount(c)
+	if !ok {
+		return
+	}
+	tokenID, err := strconv.ParseUint(c.Param("tokenId"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的令牌ID")
+		return
+	}
+	if err := s.apiTokenUseCase.Revoke(c.Request.Context(), account.ID, uint(tokenID)); err != nil {
+		response.ErrorCode(c, http.StatusNotFound, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "吊销成功", nil)
+}
+
+// serviceAccount 解析路径中的服务账号
+func (s *APITokenService) serviceAccount(c *gin.Context) (*rbac.SysUser, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的服务账号ID")
+		return nil, false
+	}
+	account, err := s.apiTokenUseCase.GetServiceAccount(c.Request.Context(), uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusNotFound, err.Error())
+		return nil, false
+	}
+	return account, true
+}
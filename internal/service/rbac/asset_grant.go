@@ -0,0 +1,177 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+// CreateAssetGrant 创建资产授权
+// @Summary 创建资产授权
+// @Description 将资产分组或主机的操作权限授予用户、部门或角色，支持生效/过期时间和显式拒绝
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.AssetGrantReq true "授权信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/asset-grants [post]
+func (s *AssetPermissionService) CreateAssetGrant(c *gin.Context) {
+	var req rbac.AssetGrantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	grant, err := s.assetPermissionUseCase.CreateGrant(c.Request.Context(), &req, c.GetUint("user_id"))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "创建失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", grant)
+}
+
+// UpdateAssetGrant 更新资产授权
+// @Summary 更新资产授权
+// @Description 更新指定的资产授权
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "授权ID"
+// @Param body body rbac.AssetGrantReq true "授权信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/asset-grants/{id} [put]
+func (s *AssetPermissionService) UpdateAssetGrant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的授权ID")
+		return
+	}
+
+	var req rbac.AssetGrantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := s.assetPermissionUseCase.UpdateGrant(c.Request.Context(), uint(id), &req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "更新失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", nil)
+}
+
+// DeleteAssetGrant 删除资产授权
+// @Summary 删除资产授权
+// @Description 删除指定的资产授权
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "授权ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/asset-grants/{id} [delete]
+func (s *AssetPermissionService) DeleteAssetGrant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的授权ID")
+		return
+	}
+
+	if err := s.assetPermissionUseCase.DeleteGrant(c.Request.Context(), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// GetAssetGrant 获取资产授权详情
+// @Summary 获取资产授权详情
+// @Description 获取指定资产授权的详细信息
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "授权ID"
+// @Success 200 {object} response.Response{data=rbac.AssetGrantInfo} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/asset-grants/{id} [get]
+func (s *AssetPermissionService) GetAssetGrant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的授权ID")
+		return
+	}
+
+	grant, err := s.assetPermissionUseCase.GetGrantByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "授权不存在")
+		return
+	}
+
+	response.Success(c, grant)
+}
+
+// ListAssetGrants 资产授权列表
+// @Summary 获取资产授权列表
+// @Description 分页获取资产授权列表
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param subjectType query string false "授权主体类型 user/department/role"
+// @Param subjectId query int false "授权主体ID"
+// @Param assetGroupId query int false "资产分组ID"
+// @Param effect query string false "授权效果 allow/deny"
+// @Param active query bool false "是否当前生效"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/asset-grants [get]
+func (s *AssetPermissionService) ListAssetGrants(c *gin.Context) {
+	var req rbac.AssetGrantListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	list, total, err := s.assetPermissionUseCase.ListGrants(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"total": total,
+		"list":  list,
+	})
+}
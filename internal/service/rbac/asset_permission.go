@@ -62,7 +62,7 @@ func (s *AssetPermissionService) CreateAssetPermission(c *gin.Context) {
 	}
 
 	// 批量创建权限（带操作权限）
-	if err := s.assetPermissionUseCase.CreateBatchWithPermissions(c.Request.Context(), req.RoleID, req.AssetGroupID, req.HostIDs, req.Permissions); err != nil {
+	if err := s.assetPermissionUseCase.CreateBatchWithPermissions(c.Request.Context(), req.RoleID, req.AssetGroupID, req.HostIDs, req.Permissions, req.StartAt, req.ExpiresAt); err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "创建失败: "+err.Error())
 		return
 	}
@@ -163,6 +163,8 @@ func (s *AssetPermissionService) UpdateAssetPermission(c *gin.Context) {
 		req.AssetGroupID,
 		req.HostIDs,
 		req.Permissions,
+		req.StartAt,
+		req.ExpiresAt,
 	); err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
 		return
@@ -352,3 +354,45 @@ func (s *AssetPermissionService) GetUserHostPermissions(c *gin.Context) {
 		"permissions": permissions,
 	})
 }
+
+// ExplainHostPermissions 解释用户对主机的有效权限
+// @Summary 解释用户主机权限
+// @Description 列出作用于用户和主机的全部权限来源（角色权限、用户/部门/角色授权、显式拒绝及其时效），并给出有效权限的判定依据
+// @Tags 资产权限管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param userId query int false "用户ID，默认为当前用户"
+// @Param hostId query int true "主机ID"
+// @Success 200 {object} response.Response{data=rbac.AssetPermissionExplainVO} "获取成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/asset-permissions/explain [get]
+func (s *AssetPermissionService) ExplainHostPermissions(c *gin.Context) {
+	hostID, err := strconv.ParseUint(c.Query("hostId"), 10, 32)
+	if err != nil || hostID == 0 {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的主机ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if userIDStr := c.Query("userId"); userIDStr != "" {
+		id, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+			return
+		}
+		userID = uint(id)
+	}
+	if userID == 0 {
+		response.ErrorCode(c, http.StatusUnauthorized, "未授权")
+		return
+	}
+
+	explain, err := s.assetPermissionUseCase.ExplainHostPermissions(c.Request.Context(), userID, uint(hostID))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "查询失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, explain)
+}
@@ -28,29 +28,46 @@ import (
 )
 
 type JwtClaims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type AuthService struct {
 	secretKey   string
 	roleUseCase *rbac.RoleUseCase
+	accessTTL   time.Duration
 }
 
 func NewAuthService(secretKey string, roleUseCase *rbac.RoleUseCase) *AuthService {
 	return &AuthService{
 		secretKey:   secretKey,
 		roleUseCase: roleUseCase,
+		accessTTL:   rbac.DefaultAccessTokenTTL,
 	}
 }
 
-func (s *AuthService) GenerateToken(userID uint, username string) (string, error) {
+// SetAccessTokenTTL 设置访问令牌有效期
+func (s *AuthService) SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.accessTTL = ttl
+	}
+}
+
+// AccessTokenTTL 访问令牌有效期
+func (s *AuthService) AccessTokenTTL() time.Duration {
+	return s.accessTTL
+}
+
+// GenerateToken 签发绑定会话的短期访问令牌
+func (s *AuthService) GenerateToken(userID uint, username, sessionID string) (string, error) {
 	claims := JwtClaims{
-		UserID:   userID,
-		Username: username,
+		UserID:    userID,
+		Username:  username,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+type LDAPService struct {
+	ldapUseCase *rbac.LDAPUseCase
+}
+
+// NewLDAPService 创建 LDAP 服务，ldapUseCase 为 nil 表示未启用 LDAP
+func NewLDAPService(ldapUseCase *rbac.LDAPUseCase) *LDAPService {
+	return &LDAPService{
+		ldapUseCase: ldapUseCase,
+	}
+}
+
+// Enabled 是否启用了 LDAP 认证
+func (s *LDAPService) Enabled() bool {
+	return s.ldapUseCase != nil
+}
+
+// Sync 执行一次目录同步（供定时任务调用）
+func (s *LDAPService) Sync(ctx context.Context) (*rbac.LDAPSyncResult, error) {
+	return s.ldapUseCase.Sync(ctx)
+}
+
+// SyncLDAPUsers 手动同步 LDAP 用户
+// @Summary 同步LDAP用户
+// @Description 从 LDAP/AD 同步已登录过的目录用户的资料、分组映射的角色和部门，并禁用目录中已删除的用户
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=rbac.LDAPSyncResult} "同步完成"
+// @Failure 400 {object} response.Response "未启用LDAP"
+// @Router /api/v1/ldap/sync [post]
+func (s *LDAPService) SyncLDAPUsers(c *gin.Context) {
+	if !s.Enabled() {
+		response.ErrorCode(c, http.StatusBadRequest, "未启用LDAP认证")
+		return
+	}
+
+	result, err := s.ldapUseCase.Sync(c.Request.Context())
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "同步失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "同步完成", result)
+}
+
+// GetLDAPStatus 获取 LDAP 状态
+// @Summary 获取LDAP状态
+// @Description 获取 LDAP 认证是否启用以及最近一次同步结果
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "获取成功"
+// @Router /api/v1/ldap/status [get]
+func (s *LDAPService) GetLDAPStatus(c *gin.Context) {
+	data := gin.H{"enabled": s.Enabled()}
+	if s.Enabled() {
+		data["lastSync"] = s.ldapUseCase.LastSync()
+	}
+	response.Success(c, data)
+}
@@ -29,8 +29,9 @@ import (
 )
 
 type MenuService struct {
-	menuUseCase *rbac.MenuUseCase
-	roleUseCase *rbac.RoleUseCase
+	menuUseCase       *rbac.MenuUseCase
+	roleUseCase       *rbac.RoleUseCase
+	permissionUseCase *rbac.PermissionUseCase
 }
 
 func NewMenuService(menuUseCase *rbac.MenuUseCase, roleUseCase *rbac.RoleUseCase) *MenuService {
@@ -40,6 +41,11 @@ func NewMenuService(menuUseCase *rbac.MenuUseCase, roleUseCase *rbac.RoleUseCase
 	}
 }
 
+// SetPermissionUseCase 设置按钮权限用例，菜单变更后刷新权限缓存
+func (s *MenuService) SetPermissionUseCase(permissionUseCase *rbac.PermissionUseCase) {
+	s.permissionUseCase = permissionUseCase
+}
+
 // CreateMenu 创建菜单
 // @Summary 创建菜单
 // @Description 管理员创建新菜单
@@ -127,6 +133,7 @@ func (s *MenuService) UpdateMenu(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.Success(c, req)
 }
@@ -154,6 +161,7 @@ func (s *MenuService) DeleteMenu(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
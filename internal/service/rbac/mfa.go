@@ -0,0 +1,339 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"go.uber.org/zap"
+)
+
+const (
+	// mfaTicketScope 双因素登录凭据的签名用途
+	mfaTicketScope = "mfa-login"
+	// mfaTicketTTL 密码校验通过后完成第二步的时限
+	mfaTicketTTL = 5 * time.Minute
+
+	mfaPurposeVerify = "verify" // 已启用，校验验证码
+	mfaPurposeSetup  = "setup"  // 策略要求但尚未绑定，先绑定认证器
+)
+
+// mfaTicket 密码校验通过后签发的一次登录凭据，只能用于完成第二步
+type mfaTicket struct {
+	UserID  uint   `json:"u"`
+	Purpose string `json:"p"`
+	Expires int64  `json:"e"`
+}
+
+// MFALoginRequest 双因素登录请求
+type MFALoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFATicketRequest 登录过程中绑定认证器请求
+type MFATicketRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+}
+
+// SetMFAUseCase 设置双因素认证用例（通过依赖注入）
+func (s *UserService) SetMFAUseCase(mfaUseCase *rbac.MFAUseCase) {
+	s.mfaUseCase = mfaUseCase
+}
+
+// issueMFATicket 用户已启用或被要求启用双因素认证时签发登录凭据，返回空字符串表示无需第二步
+func (s *UserService) issueMFATicket(ctx context.Context, user *rbac.SysUser) (string, bool, error) {
+	if s.mfaUseCase == nil {
+		return "", false, nil
+	}
+
+	purpose := mfaPurposeVerify
+	enabled, err := s.mfaUseCase.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return "", false, err
+	}
+	if !enabled {
+		required, err := s.mfaUseCase.IsRequired(ctx, user.ID)
+		if err != nil || !required {
+			return "", false, err
+		}
+		purpose = mfaPurposeSetup
+	}
+
+	ticket, err := s.signTicket(mfaTicketScope, &mfaTicket{
+		UserID:  user.ID,
+		Purpose: purpose,
+		Expires: time.Now().Add(mfaTicketTTL).Unix(),
+	})
+	return ticket, purpose == mfaPurposeSetup, err
+}
+
+// parseMFATicket 校验登录凭据并返回对应的用户
+func (s *UserService) parseMFATicket(ctx context.Context, value string) (*mfaTicket, *rbac.SysUser, error) {
+	var ticket mfaTicket
+	if err := s.verifyTicket(mfaTicketScope, value, &ticket); err != nil || time.Now().Unix() > ticket.Expires {
+		return nil, nil, errors.New("登录已失效，请重新登录")
+	}
+	user, err := s.userUseCase.GetByID(ctx, ticket.UserID)
+	if err != nil {
+		return nil, nil, errors.New("用户不存在")
+	}
+	if user.Status != 1 {
+		return nil, nil, errors.New("用户已被禁用")
+	}
+	return &ticket, user, nil
+}
+
+// LoginMFA 双因素登录第二步
+// @Summary 双因素登录
+// @Description 使用密码登录返回的 mfaToken 和认证器验证码（或恢复码）完成登录；首次绑定时同时确认绑定并返回恢复码
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param body body MFALoginRequest true "验证信息"
+// @Success 200 {object} response.Response{data=LoginResponse} "登录成功"
+// @Router /api/v1/public/login/mfa [post]
+func (s *UserService) LoginMFA(c *gin.Context) {
+	var req MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	clientIP := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	ticket, user, err := s.parseMFATicket(ctx, req.MFAToken)
+	if err != nil {
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+
+	loginType := "mfa"
+	var recoveryCodes []string
+	if ticket.Purpose == mfaPurposeSetup {
+		loginType = "mfa_enroll"
+		recoveryCodes, err = s.mfaUseCase.ConfirmEnroll(ctx, user.ID, req.Code)
+	} else {
+		var method string
+		method, err = s.mfaUseCase.Verify(ctx, user.ID, req.Code)
+		if method == rbac.MFAMethodRecovery {
+			loginType = "mfa_recovery"
+		}
+	}
+	if err != nil {
+		s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, err.Error(), user.ID)
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+
+	if s.completeLogin(c, user, loginType, recoveryCodes) {
+		appLogger.Info("双因素登录成功", zap.String("username", user.Username), zap.String("loginType", loginType))
+	}
+}
+
+// LoginMFAEnroll 登录过程中绑定认证器
+// @Summary 登录时绑定认证器
+// @Description 角色要求双因素认证但尚未绑定时，使用 mfaToken 生成认证器密钥和二维码
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param body body MFATicketRequest true "登录凭据"
+// @Success 200 {object} response.Response{data=rbac.MFAEnrollVO} "获取成功"
+// @Router /api/v1/public/login/mfa/enroll [post]
+func (s *UserService) LoginMFAEnroll(c *gin.Context) {
+	var req MFATicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	ticket, user, err := s.parseMFATicket(c.Request.Context(), req.MFAToken)
+	if err != nil {
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+	if ticket.Purpose != mfaPurposeSetup {
+		response.ErrorCode(c, http.StatusOK, "已启用双因素认证")
+		return
+	}
+
+	vo, err := s.mfaUseCase.BeginEnroll(c.Request.Context(), user.ID, user.Username)
+	if err != nil {
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+	response.Success(c, vo)
+}
+
+// GetMFAStatus 获取当前用户的双因素认证状态
+// @Summary 获取双因素认证状态
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=rbac.MFAStatusVO} "获取成功"
+// @Router /api/v1/profile/mfa [get]
+func (s *UserService) GetMFAStatus(c *gin.Context) {
+	vo, err := s.mfaUseCase.Status(c.Request.Context(), GetUserID(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取双因素认证状态失败: "+err.Error())
+		return
+	}
+	response.Success(c, vo)
+}
+
+// BeginMFAEnroll 生成认证器密钥
+// @Summary 绑定认证器
+// @Description 生成 TOTP 密钥和二维码，需调用确认接口后才会启用
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=rbac.MFAEnrollVO} "获取成功"
+// @Router /api/v1/profile/mfa/enroll [post]
+func (s *UserService) BeginMFAEnroll(c *gin.Context) {
+	vo, err := s.mfaUseCase.BeginEnroll(c.Request.Context(), GetUserID(c), GetUsername(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, vo)
+}
+
+// ConfirmMFAEnroll 确认绑定认证器
+// @Summary 确认绑定认证器
+// @Description 校验认证器验证码后启用双因素认证，返回只显示一次的恢复码
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.MFACodeReq true "验证码"
+// @Success 200 {object} response.Response "启用成功"
+// @Router /api/v1/profile/mfa/confirm [post]
+func (s *UserService) ConfirmMFAEnroll(c *gin.Context) {
+	var req rbac.MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := GetUserID(c)
+	codes, err := s.mfaUseCase.ConfirmEnroll(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		s.recordLoginLog(GetUsername(c), "mfa_enroll", "failed", c.ClientIP(), c.Request.UserAgent(), err.Error(), userID)
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.recordLoginLog(GetUsername(c), "mfa_enroll", "success", c.ClientIP(), c.Request.UserAgent(), "", userID)
+
+	response.SuccessWithMessage(c, "双因素认证已启用", gin.H{"recoveryCodes": codes})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 校验认证器验证码后重新生成恢复码，旧的恢复码全部失效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.MFACodeReq true "验证码"
+// @Success 200 {object} response.Response "生成成功"
+// @Router /api/v1/profile/mfa/recovery-codes [post]
+func (s *UserService) RegenerateRecoveryCodes(c *gin.Context) {
+	var req rbac.MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	codes, err := s.mfaUseCase.RegenerateRecoveryCodes(c.Request.Context(), GetUserID(c), req.Code)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"recoveryCodes": codes})
+}
+
+// DisableMFA 关闭双因素认证
+// @Summary 关闭双因素认证
+// @Description 校验验证码或恢复码后关闭；角色或策略要求启用时不允许关闭
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.MFACodeReq true "验证码"
+// @Success 200 {object} response.Response "关闭成功"
+// @Router /api/v1/profile/mfa [delete]
+func (s *UserService) DisableMFA(c *gin.Context) {
+	var req rbac.MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := GetUserID(c)
+	if err := s.mfaUseCase.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		s.recordLoginLog(GetUsername(c), "mfa_disable", "failed", c.ClientIP(), c.Request.UserAgent(), err.Error(), userID)
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.recordLoginLog(GetUsername(c), "mfa_disable", "success", c.ClientIP(), c.Request.UserAgent(), "", userID)
+
+	response.SuccessWithMessage(c, "双因素认证已关闭", nil)
+}
+
+// ResetUserMFA 重置用户的双因素认证
+// @Summary 重置用户双因素认证
+// @Description 管理员清除用户的认证器和恢复码（如认证器丢失），用户下次登录时按策略重新绑定
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response "重置成功"
+// @Router /api/v1/users/{id}/mfa [delete]
+func (s *UserService) ResetUserMFA(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+		return
+	}
+
+	user, err := s.userUseCase.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "用户不存在")
+		return
+	}
+
+	if err := s.mfaUseCase.Reset(c.Request.Context(), user.ID); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "重置双因素认证失败: "+err.Error())
+		return
+	}
+	s.recordLoginLog(user.Username, "mfa_reset", "success", c.ClientIP(), c.Request.UserAgent(), "由 "+GetUsername(c)+" 重置", user.ID)
+
+	response.SuccessWithMessage(c, "双因素认证已重置", nil)
+}
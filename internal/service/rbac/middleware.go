@@ -20,11 +20,13 @@
 package rbac
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
 	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"github.com/ydcloud-dy/opshub/pkg/response"
 )
@@ -32,8 +34,20 @@ import (
 const (
 	UserIdKey   = "user_id"
 	UsernameKey = "username"
+	// APITokenIDKey 使用 API 令牌访问时的令牌ID
+	APITokenIDKey = "api_token_id"
+	// SessionIDKey 访问令牌所属的会话ID
+	SessionIDKey = "session_id"
 )
 
+// apiTokenDeniedPaths 凭证管理接口只允许登录会话访问，避免泄露的 API 令牌被用来签发新令牌、修改密码或关闭双因素认证
+var apiTokenDeniedPaths = []string{
+	"/api/v1/profile/password",
+	"/api/v1/profile/sessions",
+	"/api/v1/profile/mfa",
+	"/api/v1/profile/api-tokens",
+}
+
 // GetUserID 从上下文获取用户ID
 func GetUserID(c *gin.Context) uint {
 	if userID, exists := c.Get(UserIdKey); exists {
@@ -54,10 +68,34 @@ func GetUsername(c *gin.Context) string {
 	return ""
 }
 
+// GetAPITokenID 从上下文获取 API 令牌ID，使用 JWT 登录时返回 0
+func GetAPITokenID(c *gin.Context) uint {
+	if tokenID, exists := c.Get(APITokenIDKey); exists {
+		if id, ok := tokenID.(uint); ok {
+			return id
+		}
+	}
+	return 0
+}
+
+// GetSessionID 从上下文获取当前会话ID，使用 API 令牌访问时返回空
+func GetSessionID(c *gin.Context) string {
+	if sessionID, exists := c.Get(SessionIDKey); exists {
+		if id, ok := sessionID.(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
 // AuthMiddleware JWT认证中间件
 type AuthMiddleware struct {
-	authService        *AuthService
+	authService         *AuthService
 	assetPermissionRepo rbac.AssetPermissionRepo
+	apiTokenUseCase     *rbac.APITokenUseCase
+	sessionUseCase      *rbac.SessionUseCase
+	permissionUseCase   *rbac.PermissionUseCase
+	dataScopeUseCase    *rbac.DataScopeUseCase
 }
 
 func NewAuthMiddleware(authService *AuthService) *AuthMiddleware {
@@ -71,6 +109,47 @@ func (m *AuthMiddleware) SetAssetPermissionRepo(repo rbac.AssetPermissionRepo) {
 	m.assetPermissionRepo = repo
 }
 
+// SetAPITokenUseCase 设置 API 令牌用例，设置后 AuthRequired 同时接受 API 令牌
+func (m *AuthMiddleware) SetAPITokenUseCase(apiTokenUseCase *rbac.APITokenUseCase) {
+	m.apiTokenUseCase = apiTokenUseCase
+}
+
+// SetSessionUseCase 设置会话用例，设置后访问令牌必须对应仍然有效的会话
+func (m *AuthMiddleware) SetSessionUseCase(sessionUseCase *rbac.SessionUseCase) {
+	m.sessionUseCase = sessionUseCase
+}
+
+// SetPermissionUseCase 设置按钮权限用例，设置后 RequirePermission 按权限映射表校验接口
+func (m *AuthMiddleware) SetPermissionUseCase(permissionUseCase *rbac.PermissionUseCase) {
+	m.permissionUseCase = permissionUseCase
+}
+
+// SetDataScopeUseCase 设置数据权限用例，设置后列表查询按角色数据范围过滤
+func (m *AuthMiddleware) SetDataScopeUseCase(dataScopeUseCase *rbac.DataScopeUseCase) {
+	m.dataScopeUseCase = dataScopeUseCase
+}
+
+// attachDataScope 在请求上下文中登记当前用户的数据范围，由仓储在列表查询时按需解析
+func (m *AuthMiddleware) attachDataScope(c *gin.Context, userID uint) {
+	if m.dataScopeUseCase == nil {
+		return
+	}
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(rbac.WithDataScope(ctx, userID, func() (*rbac.DataScope, error) {
+		return m.dataScopeUseCase.Resolve(ctx, userID)
+	}))
+}
+
+// attachAuditActor 在请求上下文中记录操作人，数据变更日志从中获取用户和客户端信息
+func attachAuditActor(c *gin.Context, userID uint, username string) {
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:    userID,
+		Username:  username,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+}
+
 // AuthRequired JWT认证
 func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -97,6 +176,34 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		// API 令牌：校验摘要、有效期和作用域
+		if m.apiTokenUseCase != nil && strings.HasPrefix(token, rbac.APITokenPrefix) {
+			apiToken, user, err := m.apiTokenUseCase.Authenticate(c.Request.Context(), token, c.Request.Method, c.Request.URL.Path, c.ClientIP())
+			if err != nil {
+				status := http.StatusUnauthorized
+				if errors.Is(err, rbac.ErrAPITokenScope) {
+					status = http.StatusForbidden
+				}
+				response.ErrorCode(c, status, err.Error())
+				c.Abort()
+				return
+			}
+			for _, path := range apiTokenDeniedPaths {
+				if c.Request.URL.Path == path || strings.HasPrefix(c.Request.URL.Path, path+"/") {
+					response.ErrorCode(c, http.StatusForbidden, "API 令牌不能管理登录凭证，请登录后操作")
+					c.Abort()
+					return
+				}
+			}
+			c.Set(UserIdKey, user.ID)
+			c.Set(UsernameKey, user.Username)
+			c.Set(APITokenIDKey, apiToken.ID)
+			m.attachDataScope(c, user.ID)
+			attachAuditActor(c, user.ID, user.Username)
+			c.Next()
+			return
+		}
+
 		claims, err := m.authService.ParseToken(token)
 		if err != nil {
 			response.ErrorCode(c, http.StatusUnauthorized, "token无效或已过期")
@@ -104,8 +211,34 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		// 会话校验：登出、被踢下线或用户禁用后令牌立即失效
+		if m.sessionUseCase != nil {
+			if claims.SessionID == "" {
+				response.ErrorCode(c, http.StatusUnauthorized, "token无效或已过期")
+				c.Abort()
+				return
+			}
+			session, err := m.sessionUseCase.Validate(c.Request.Context(), claims.SessionID, c.ClientIP())
+			if err == nil && session.UserID != claims.UserID {
+				err = rbac.ErrSessionRevoked
+			}
+			if err != nil {
+				status := http.StatusUnauthorized
+				if !errors.Is(err, rbac.ErrSessionRevoked) {
+					status = http.StatusInternalServerError
+					err = errors.New("会话校验失败")
+				}
+				response.ErrorCode(c, status, err.Error())
+				c.Abort()
+				return
+			}
+			c.Set(SessionIDKey, claims.SessionID)
+		}
+
 		c.Set(UserIdKey, claims.UserID)
 		c.Set(UsernameKey, claims.Username)
+		m.attachDataScope(c, claims.UserID)
+		attachAuditActor(c, claims.UserID, claims.Username)
 		c.Next()
 	}
 }
@@ -147,6 +280,32 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	}
 }
 
+// RequirePermission 按权限映射表校验系统内置接口和插件接口，需在 AuthRequired 之后使用
+// 未登记权限且不在登录即可访问列表中的接口一律拒绝，admin 角色拥有全部权限
+func (m *AuthMiddleware) RequirePermission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.permissionUseCase == nil {
+			c.Next()
+			return
+		}
+
+		code, err := m.permissionUseCase.Check(c.Request.Context(), GetUserID(c), c.Request.Method, c.FullPath())
+		if err != nil {
+			if errors.Is(err, rbac.ErrPermissionDenied) {
+				response.ErrorCode(c, http.StatusForbidden, "权限不足：缺少 "+code+" 权限")
+			} else if errors.Is(err, rbac.ErrPermissionUnregistered) {
+				response.ErrorCode(c, http.StatusForbidden, "权限不足：接口未登记权限")
+			} else {
+				response.ErrorCode(c, http.StatusInternalServerError, "权限校验失败")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // RequireHostPermission 检查主机操作权限的中间件
 func (m *AuthMiddleware) RequireHostPermission(operation uint) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -202,3 +361,54 @@ func (m *AuthMiddleware) RequireHostPermission(operation uint) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireDatabasePermission 检查数据库资产操作权限的中间件
+func (m *AuthMiddleware) RequireDatabasePermission(operation uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.assetPermissionRepo == nil {
+			response.ErrorCode(c, http.StatusInternalServerError, "权限检查未初始化")
+			c.Abort()
+			return
+		}
+
+		userID := GetUserID(c)
+		if userID == 0 {
+			response.ErrorCode(c, http.StatusUnauthorized, "未登录")
+			c.Abort()
+			return
+		}
+
+		databaseIDStr := c.Param("id")
+		if databaseIDStr == "" {
+			c.Next()
+			return
+		}
+
+		databaseID, err := strconv.ParseUint(databaseIDStr, 10, 32)
+		if err != nil {
+			response.ErrorCode(c, http.StatusBadRequest, "无效的数据库ID")
+			c.Abort()
+			return
+		}
+
+		hasPermission, err := m.assetPermissionRepo.CheckDatabaseOperationPermission(
+			c.Request.Context(),
+			userID,
+			uint(databaseID),
+			operation,
+		)
+		if err != nil {
+			response.ErrorCode(c, http.StatusInternalServerError, "权限检查失败")
+			c.Abort()
+			return
+		}
+
+		if !hasPermission {
+			response.ErrorCode(c, http.StatusForbidden, "权限不足")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
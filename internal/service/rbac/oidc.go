@@ -0,0 +1,250 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcStateCookie 保存授权请求状态的 Cookie，签名后存放在浏览器，多副本部署无需共享存储
+	oidcStateCookie = "opshub_oidc_state"
+	// oidcStateTTL 授权请求有效期
+	oidcStateTTL = 10 * time.Minute
+	// oidcCookiePath Cookie 只在回调路径下发送
+	oidcCookiePath = "/api/v1/public/oidc"
+)
+
+// oidcState 授权请求状态
+type oidcState struct {
+	Provider string `json:"p"`
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	Expires  int64  `json:"e"`
+}
+
+// SetOIDCUseCase 设置 OIDC 单点登录用例（通过依赖注入），未设置时不提供单点登录
+func (s *UserService) SetOIDCUseCase(oidcUseCase *rbac.OIDCUseCase) {
+	s.oidcUseCase = oidcUseCase
+}
+
+// ListOIDCProviders 获取单点登录提供者列表
+// @Summary 获取单点登录提供者
+// @Description 获取登录页可用的 OIDC 单点登录提供者
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response{data=[]rbac.OIDCProviderInfo} "获取成功"
+// @Router /api/v1/public/oidc/providers [get]
+func (s *UserService) ListOIDCProviders(c *gin.Context) {
+	if s.oidcUseCase == nil {
+		response.Success(c, []rbac.OIDCProviderInfo{})
+		return
+	}
+	response.Success(c, s.oidcUseCase.Providers())
+}
+
+// OIDCLogin 发起单点登录
+// @Summary 发起单点登录
+// @Description 生成 state、nonce 和 PKCE 校验码，跳转到身份提供者的授权页面
+// @Tags 认证管理
+// @Param provider path string true "提供者标识"
+// @Success 302 "跳转到身份提供者"
+// @Router /api/v1/public/oidc/{provider}/login [get]
+func (s *UserService) OIDCLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	if s.oidcUseCase == nil || !s.oidcUseCase.HasProvider(provider) {
+		response.ErrorCode(c, http.StatusNotFound, "未配置的身份提供者")
+		return
+	}
+
+	state := &oidcState{
+		Provider: provider,
+		State:    randomToken(),
+		Nonce:    randomToken(),
+		Verifier: oauth2.GenerateVerifier(),
+		Expires:  time.Now().Add(oidcStateTTL).Unix(),
+	}
+
+	authURL, err := s.oidcUseCase.AuthCodeURL(c.Request.Context(), provider, state.State, state.Nonce, state.Verifier)
+	if err != nil {
+		appLogger.Error("生成单点登录地址失败", zap.String("provider", provider), zap.Error(err))
+		s.redirectOIDCResult(c, provider, nil, "身份提供者暂不可用")
+		return
+	}
+
+	value, err := s.signOIDCState(state)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成登录状态失败")
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, int(oidcStateTTL.Seconds()), oidcCookiePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback 单点登录回调
+// @Summary 单点登录回调
+// @Description 校验 state，用授权码和 PKCE 校验码换取令牌并校验 ID Token，映射本地用户后签发 OpsHub 令牌，跳转回前端
+// @Tags 认证管理
+// @Param provider path string true "提供者标识"
+// @Param code query string false "授权码"
+// @Param state query string false "state"
+// @Success 302 "跳转到前端登录页，令牌放在 URL 片段中"
+// @Router /api/v1/public/oidc/{provider}/callback [get]
+func (s *UserService) OIDCCallback(c *gin.Context) {
+	provider := c.Param("provider")
+	clientIP := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+	loginType := "oidc"
+
+	if s.oidcUseCase == nil || !s.oidcUseCase.HasProvider(provider) {
+		response.ErrorCode(c, http.StatusNotFound, "未配置的身份提供者")
+		return
+	}
+
+	// state 只能使用一次
+	cookie, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, oidcCookiePath, "", c.Request.TLS != nil, true)
+
+	if errCode := c.Query("error"); errCode != "" {
+		reason := errCode
+		if desc := c.Query("error_description"); desc != "" {
+			reason += ": " + desc
+		}
+		s.recordLoginLog("", loginType, "failed", clientIP, userAgent, reason, 0)
+		s.redirectOIDCResult(c, provider, nil, "身份提供者拒绝了登录请求")
+		return
+	}
+
+	state, err := s.verifyOIDCState(cookie)
+	if err != nil || state.Provider != provider || !hmac.Equal([]byte(state.State), []byte(c.Query("state"))) {
+		s.recordLoginLog("", loginType, "failed", clientIP, userAgent, "state 校验失败", 0)
+		s.redirectOIDCResult(c, provider, nil, "登录请求已失效，请重新登录")
+		return
+	}
+
+	user, err := s.oidcUseCase.Login(c.Request.Context(), provider, c.Query("code"), state.Nonce, state.Verifier)
+	if err != nil {
+		appLogger.Error("单点登录失败", zap.String("provider", provider), zap.Error(err))
+		s.recordLoginLog("", loginType, "failed", clientIP, userAgent, err.Error(), 0)
+		s.redirectOIDCResult(c, provider, nil, err.Error())
+		return
+	}
+
+	if user.Status != 1 {
+		s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, "用户已被禁用", user.ID)
+		s.redirectOIDCResult(c, provider, nil, "用户已被禁用")
+		return
+	}
+
+	mfaToken, mfaSetup, err := s.issueMFATicket(c.Request.Context(), user)
+	if err != nil {
+		s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, "检查双因素认证失败", user.ID)
+		s.redirectOIDCResult(c, provider, nil, "检查双因素认证失败")
+		return
+	}
+	if mfaToken != "" {
+		fragment := url.Values{}
+		fragment.Set("mfa_token", mfaToken)
+		if mfaSetup {
+			fragment.Set("mfa_setup", "1")
+		}
+		s.redirectOIDCFragment(c, provider, fragment)
+		return
+	}
+
+	issued, err := s.issueSession(c, user, loginType)
+	if err != nil {
+		s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, "生成token失败", user.ID)
+		s.redirectOIDCResult(c, provider, nil, "生成token失败")
+		return
+	}
+
+	user.Password = ""
+	_ = s.userUseCase.Update(c.Request.Context(), user)
+
+	s.recordLoginLog(user.Username, loginType, "success", clientIP, userAgent, "", user.ID)
+	appLogger.Info("单点登录成功", zap.String("username", user.Username), zap.String("provider", provider))
+
+	s.redirectOIDCResult(c, provider, issued, "")
+}
+
+// redirectOIDCResult 跳转回前端，结果放在 URL 片段中，不会发送到服务端或出现在访问日志里
+func (s *UserService) redirectOIDCResult(c *gin.Context, provider string, issued *RefreshTokenResponse, errMsg string) {
+	fragment := url.Values{}
+	if issued != nil {
+		fragment.Set("oidc_token", issued.Token)
+		if issued.RefreshToken != "" {
+			fragment.Set("oidc_refresh_token", issued.RefreshToken)
+		}
+	} else {
+		fragment.Set("oidc_error", errMsg)
+	}
+	s.redirectOIDCFragment(c, provider, fragment)
+}
+
+// redirectOIDCFragment 跳转到登录完成地址并携带 URL 片段
+func (s *UserService) redirectOIDCFragment(c *gin.Context, provider string, fragment url.Values) {
+	target := "/login"
+	if s.oidcUseCase != nil {
+		target = s.oidcUseCase.PostLoginRedirect(provider)
+	}
+	c.Redirect(http.StatusFound, strings.SplitN(target, "#", 2)[0]+"#"+fragment.Encode())
+}
+
+// signOIDCState 对授权请求状态签名
+func (s *UserService) signOIDCState(state *oidcState) (string, error) {
+	return s.signTicket("oidc-state", state)
+}
+
+// verifyOIDCState 校验签名和有效期
+func (s *UserService) verifyOIDCState(value string) (*oidcState, error) {
+	var state oidcState
+	if err := s.verifyTicket("oidc-state", value, &state); err != nil {
+		return nil, err
+	}
+	if time.Now().Unix() > state.Expires {
+		return nil, errors.New("state expired")
+	}
+	return &state, nil
+}
+
+// randomToken 生成随机字符串
+func randomToken() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
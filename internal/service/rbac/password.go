@@ -0,0 +1,244 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"go.uber.org/zap"
+)
+
+const (
+	// passwordTicketScope 登录时修改密码凭据的签名用途
+	passwordTicketScope = "password-change"
+	// passwordTicketTTL 密码校验通过后完成改密的时限
+	passwordTicketTTL = 10 * time.Minute
+)
+
+// passwordTicket 需要修改密码时签发的登录凭据，绑定签发时的密码修改时间，改密后即失效
+type passwordTicket struct {
+	UserID    uint   `json:"u"`
+	LoginType string `json:"t"`
+	Changed   int64  `json:"c"`
+	Expires   int64  `json:"e"`
+}
+
+// LoginPasswordRequest 登录时修改密码请求
+type LoginPasswordRequest struct {
+	PasswordToken string `json:"passwordToken" binding:"required"`
+	NewPassword   string `json:"newPassword" binding:"required"`
+}
+
+// SetPasswordUseCase 设置密码策略用例（通过依赖注入）
+func (s *UserService) SetPasswordUseCase(passwordUseCase *rbac.PasswordUseCase) {
+	s.passwordUseCase = passwordUseCase
+}
+
+func passwordChangedUnix(user *rbac.SysUser) int64 {
+	if user.PasswordChangedAt == nil {
+		return 0
+	}
+	return user.PasswordChangedAt.Unix()
+}
+
+// checkLoginLocked 登录失败锁定期间不再校验密码，返回被检查的本地用户（不存在时为 nil）
+func (s *UserService) checkLoginLocked(ctx context.Context, username string) (*rbac.SysUser, error) {
+	if s.passwordUseCase == nil {
+		return nil, nil
+	}
+	user, err := s.userUseCase.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, nil
+	}
+	return user, s.passwordUseCase.CheckLocked(user)
+}
+
+// recordLoginFailure 记录密码错误，达到阈值时返回锁定提示
+func (s *UserService) recordLoginFailure(ctx context.Context, user *rbac.SysUser, authErr error) error {
+	if s.passwordUseCase == nil || user == nil || !errors.Is(authErr, rbac.ErrInvalidCredentials) {
+		return authErr
+	}
+	if err := s.passwordUseCase.RecordFailure(ctx, user); err != nil {
+		if errors.Is(err, rbac.ErrAccountLocked) {
+			return err
+		}
+		appLogger.Error("记录登录失败次数失败", zap.String("username", user.Username), zap.Error(err))
+	}
+	return authErr
+}
+
+// completeLogin 完成登录：本地账号需要修改密码时返回改密凭据，否则创建会话并签发令牌
+func (s *UserService) completeLogin(c *gin.Context, user *rbac.SysUser, loginType string, recoveryCodes []string) bool {
+	ctx := c.Request.Context()
+	clientIP := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	if s.passwordUseCase != nil {
+		if err := s.passwordUseCase.CheckLocked(user); err != nil {
+			s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, err.Error(), user.ID)
+			response.ErrorCode(c, http.StatusOK, err.Error())
+			return false
+		}
+		if err := s.passwordUseCase.ResetFailures(ctx, user); err != nil {
+			appLogger.Error("清除登录失败次数失败", zap.String("username", user.Username), zap.Error(err))
+		}
+
+		if reason := s.passwordUseCase.ChangeReason(user); reason != "" {
+			ticket, err := s.signTicket(passwordTicketScope, &passwordTicket{
+				UserID:    user.ID,
+				LoginType: loginType,
+				Changed:   passwordChangedUnix(user),
+				Expires:   time.Now().Add(passwordTicketTTL).Unix(),
+			})
+			if err != nil {
+				response.ErrorCode(c, http.StatusInternalServerError, "生成改密凭据失败")
+				return false
+			}
+			response.Success(c, LoginResponse{
+				PasswordChangeRequired: true,
+				PasswordChangeReason:   reason,
+				PasswordToken:          ticket,
+				RecoveryCodes:          recoveryCodes,
+			})
+			return false
+		}
+	}
+
+	issued, err := s.issueSession(c, user, loginType)
+	if err != nil {
+		s.recordLoginLog(user.Username, loginType, "failed", clientIP, userAgent, "生成token失败", user.ID)
+		response.ErrorCode(c, http.StatusInternalServerError, "生成token失败")
+		return false
+	}
+
+	// 清空密码字段，防止返回给前端
+	user.Password = ""
+
+	// 更新最后登录时间
+	_ = s.userUseCase.Update(ctx, user)
+
+	s.recordLoginLog(user.Username, loginType, "success", clientIP, userAgent, "", user.ID)
+
+	resp := LoginResponse{
+		Token:         issued.Token,
+		User:          user,
+		RefreshToken:  issued.RefreshToken,
+		ExpiresIn:     issued.ExpiresIn,
+		RecoveryCodes: recoveryCodes,
+	}
+	if s.passwordUseCase != nil {
+		resp.PasswordExpiresAt = s.passwordUseCase.ExpiryWarning(user)
+	}
+	response.Success(c, resp)
+	return true
+}
+
+// LoginChangePassword 登录时修改密码
+// @Summary 登录时修改密码
+// @Description 首次登录、管理员重置后或密码过期时，使用登录返回的 passwordToken 设置新密码并完成登录
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param body body LoginPasswordRequest true "新密码"
+// @Success 200 {object} response.Response{data=LoginResponse} "登录成功"
+// @Router /api/v1/public/login/password [post]
+func (s *UserService) LoginChangePassword(c *gin.Context) {
+	var req LoginPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+	if s.passwordUseCase == nil {
+		response.ErrorCode(c, http.StatusOK, "登录已失效，请重新登录")
+		return
+	}
+
+	var ticket passwordTicket
+	if err := s.verifyTicket(passwordTicketScope, req.PasswordToken, &ticket); err != nil || time.Now().Unix() > ticket.Expires {
+		response.ErrorCode(c, http.StatusOK, "登录已失效，请重新登录")
+		return
+	}
+	ctx := c.Request.Context()
+	user, err := s.userUseCase.GetByID(ctx, ticket.UserID)
+	if err != nil || user.Status != 1 || passwordChangedUnix(user) != ticket.Changed {
+		response.ErrorCode(c, http.StatusOK, "登录已失效，请重新登录")
+		return
+	}
+
+	if err := s.passwordUseCase.SetPassword(ctx, user, req.NewPassword, false); err != nil {
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+	s.revokeUserSessions(c, user.ID)
+
+	if s.completeLogin(c, user, ticket.LoginType, nil) {
+		appLogger.Info("修改密码并登录成功", zap.String("username", user.Username))
+	}
+}
+
+// GetPasswordPolicy 获取密码策略
+// @Summary 获取密码策略
+// @Description 获取本地账号的密码复杂度要求，用于前端提示
+// @Tags 认证管理
+// @Produce json
+// @Success 200 {object} response.Response{data=rbac.PasswordPolicy} "获取成功"
+// @Router /api/v1/public/password-policy [get]
+func (s *UserService) GetPasswordPolicy(c *gin.Context) {
+	if s.passwordUseCase == nil {
+		response.Success(c, rbac.PasswordPolicy{})
+		return
+	}
+	response.Success(c, s.passwordUseCase.Policy())
+}
+
+// UnlockUser 解锁用户
+// @Summary 解锁用户
+// @Description 管理员解除用户因登录失败次数过多导致的锁定
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response "解锁成功"
+// @Router /api/v1/users/{id}/unlock [put]
+func (s *UserService) UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+		return
+	}
+	if s.passwordUseCase == nil {
+		response.SuccessWithMessage(c, "解锁成功", nil)
+		return
+	}
+	if err := s.passwordUseCase.Unlock(c.Request.Context(), uint(id)); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "解锁失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "解锁成功", nil)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+// PermissionService 按钮权限服务
+type PermissionService struct {
+	permissionUseCase *rbac.PermissionUseCase
+}
+
+// NewPermissionService 创建按钮权限服务
+func NewPermissionService(permissionUseCase *rbac.PermissionUseCase) *PermissionService {
+	return &PermissionService{permissionUseCase: permissionUseCase}
+}
+
+// UseCase 返回按钮权限用例，用于注册插件权限和同步按钮菜单
+func (s *PermissionService) UseCase() *rbac.PermissionUseCase {
+	return s.permissionUseCase
+}
+
+// SetPermissionUseCase 设置按钮权限用例，分配角色后刷新权限缓存
+func (s *UserService) SetPermissionUseCase(permissionUseCase *rbac.PermissionUseCase) {
+	s.permissionUseCase = permissionUseCase
+}
+
+// invalidatePermissions 角色、菜单或授权变更后清空权限缓存
+func invalidatePermissions(permissionUseCase *rbac.PermissionUseCase) {
+	if permissionUseCase != nil {
+		permissionUseCase.Invalidate()
+	}
+}
+
+// ListPermissions 获取全部权限
+// @Summary 获取权限列表
+// @Description 列出所有接口权限、对应的接口以及持有该权限的角色，admin 角色默认拥有全部权限
+// @Tags 权限管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]rbac.PermissionInfo} "获取成功"
+// @Router /api/v1/permissions [get]
+func (s *PermissionService) ListPermissions(c *gin.Context) {
+	list, err := s.permissionUseCase.List(c.Request.Context())
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取权限失败: "+err.Error())
+		return
+	}
+	response.Success(c, list)
+}
+
+// GetMyPermissions 获取当前用户的权限编码
+// @Summary 获取当前用户权限
+// @Description 前端根据权限编码控制按钮显示
+// @Tags 权限管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]string} "获取成功"
+// @Router /api/v1/profile/permissions [get]
+func (s *PermissionService) GetMyPermissions(c *gin.Context) {
+	codes, err := s.permissionUseCase.GetUserCodes(c.Request.Context(), GetUserID(c))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取权限失败: "+err.Error())
+		return
+	}
+	response.Success(c, codes)
+}
@@ -29,7 +29,9 @@ import (
 )
 
 type RoleService struct {
-	roleUseCase *rbac.RoleUseCase
+	roleUseCase       *rbac.RoleUseCase
+	mfaUseCase        *rbac.MFAUseCase
+	permissionUseCase *rbac.PermissionUseCase
 }
 
 func NewRoleService(roleUseCase *rbac.RoleUseCase) *RoleService {
@@ -38,6 +40,53 @@ func NewRoleService(roleUseCase *rbac.RoleUseCase) *RoleService {
 	}
 }
 
+// SetMFAUseCase 设置双因素认证用例（通过依赖注入）
+func (s *RoleService) SetMFAUseCase(mfaUseCase *rbac.MFAUseCase) {
+	s.mfaUseCase = mfaUseCase
+}
+
+// SetPermissionUseCase 设置按钮权限用例，角色授权变更后刷新权限缓存
+func (s *RoleService) SetPermissionUseCase(permissionUseCase *rbac.PermissionUseCase) {
+	s.permissionUseCase = permissionUseCase
+}
+
+// SetRoleMFARequest 设置角色双因素认证要求
+type SetRoleMFARequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+// SetRoleMFA 设置角色是否要求双因素认证
+// @Summary 设置角色双因素认证要求
+// @Description 开启后拥有该角色的用户登录时必须完成双因素认证，未绑定的用户在下次登录时绑定
+// @Tags 角色管理
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Param body body SetRoleMFARequest true "是否要求"
+// @Success 200 {object} response.Response "设置成功"
+// @Router /api/v1/roles/{id}/mfa [put]
+func (s *RoleService) SetRoleMFA(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的角色ID")
+		return
+	}
+
+	var req SetRoleMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := s.mfaUseCase.SetRoleRequired(c.Request.Context(), uint(id), *req.Required); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "设置失败: "+err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "设置成功", nil)
+}
+
 // CreateRole 创建角色
 // @Summary 创建角色
 // @Description 管理员创建新角色
@@ -95,6 +144,7 @@ func (s *RoleService) UpdateRole(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.Success(c, req)
 }
@@ -122,6 +172,7 @@ func (s *RoleService) DeleteRole(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
@@ -239,6 +290,7 @@ func (s *RoleService) AssignRoleMenus(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "分配失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.Success(c, nil)
 }
@@ -0,0 +1,502 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+)
+
+// scimContentType SCIM 协议规定的响应类型
+const scimContentType = "application/scim+json"
+
+// SCIMService SCIM 2.0 下发接口，供身份提供者同步用户和用户组
+type SCIMService struct {
+	scimUseCase *rbac.SCIMUseCase
+	token       string
+}
+
+// NewSCIMService 创建 SCIM 服务，scimUseCase 为 nil 表示未启用 SCIM
+func NewSCIMService(scimUseCase *rbac.SCIMUseCase, token string) *SCIMService {
+	return &SCIMService{
+		scimUseCase: scimUseCase,
+		token:       token,
+	}
+}
+
+// Enabled 是否启用了 SCIM 下发
+func (s *SCIMService) Enabled() bool {
+	return s.scimUseCase != nil
+}
+
+// TokenRequired 校验身份提供者携带的 Bearer 令牌，未配置令牌时拒绝所有请求
+func (s *SCIMService) TokenRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := ""
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+			token = strings.TrimSpace(parts[1])
+		}
+		if s.token == "" || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeSCIMError(c, &rbac.SCIMError{Status: http.StatusUnauthorized, Detail: "SCIM 令牌无效"})
+			c.Abort()
+			return
+		}
+		// 身份提供者下发的变更在数据日志中记为 scim
+		attachAuditActor(c, 0, rbac.UserSourceSCIM)
+		c.Next()
+	}
+}
+
+// GetServiceProviderConfig 返回服务能力说明
+// @Summary SCIM 服务能力
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (s *SCIMService) GetServiceProviderConfig(c *gin.Context) {
+	writeSCIM(c, http.StatusOK, gin.H{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": 1000},
+		"changePassword": gin.H{"supported": true},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "使用配置文件 auth.scim.token 中的令牌认证",
+			"primary":     true,
+		}},
+		"meta": gin.H{"resourceType": "ServiceProviderConfig", "location": rbac.SCIMBasePath + "/ServiceProviderConfig"},
+	})
+}
+
+// GetResourceTypes 返回支持的资源类型
+// @Summary SCIM 资源类型
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Router /scim/v2/ResourceTypes [get]
+func (s *SCIMService) GetResourceTypes(c *gin.Context) {
+	resourceTypes := []gin.H{
+		{
+			"schemas":          []string{"urn:ietf:params:scim:schemas:core:2.0:ResourceType"},
+			"id":               "User",
+			"name":             "User",
+			"endpoint":         "/Users",
+			"schema":           rbac.SCIMSchemaUser,
+			"schemaExtensions": []gin.H{{"schema": rbac.SCIMSchemaEnterpriseUser, "required": false}},
+			"meta":             gin.H{"resourceType": "ResourceType", "location": rbac.SCIMBasePath + "/ResourceTypes/User"},
+		},
+		{
+			"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:ResourceType"},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   rbac.SCIMSchemaGroup,
+			"meta":     gin.H{"resourceType": "ResourceType", "location": rbac.SCIMBasePath + "/ResourceTypes/Group"},
+		},
+	}
+	writeSCIM(c, http.StatusOK, scimList(resourceTypes))
+}
+
+// GetSchemas 返回支持的属性定义
+// @Summary SCIM 属性定义
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Router /scim/v2/Schemas [get]
+func (s *SCIMService) GetSchemas(c *gin.Context) {
+	schemas := []gin.H{
+		scimSchema(rbac.SCIMSchemaUser, "User", []gin.H{
+			scimAttribute("userName", "string", false, true, "server"),
+			scimAttribute("externalId", "string", false, false, "none"),
+			scimAttribute("displayName", "string", false, false, "none"),
+			scimComplexAttribute("name", false, []gin.H{
+				scimAttribute("formatted", "string", false, false, "none"),
+				scimAttribute("givenName", "string", false, false, "none"),
+				scimAttribute("familyName", "string", false, false, "none"),
+			}),
+			scimComplexAttribute("emails", true, scimMultiValueAttributes()),
+			scimComplexAttribute("phoneNumbers", true, scimMultiValueAttributes()),
+			scimAttribute("active", "boolean", false, false, "none"),
+			scimWriteOnlyAttribute("password"),
+			scimComplexAttribute("groups", true, []gin.H{
+				scimAttribute("value", "string", false, false, "none"),
+				scimAttribute("display", "string", false, false, "none"),
+			}),
+		}),
+		scimSchema(rbac.SCIMSchemaEnterpriseUser, "EnterpriseUser", []gin.H{
+			scimAttribute("department", "string", false, false, "none"),
+		}),
+		scimSchema(rbac.SCIMSchemaGroup, "Group", []gin.H{
+			scimAttribute("displayName", "string", false, true, "server"),
+			scimComplexAttribute("members", true, []gin.H{
+				scimAttribute("value", "string", false, false, "none"),
+				scimAttribute("display", "string", false, false, "none"),
+			}),
+		}),
+	}
+	writeSCIM(c, http.StatusOK, scimList(schemas))
+}
+
+// ListUsers 查询用户
+// @Summary SCIM 查询用户
+// @Description 支持 filter、startIndex、count、attributes 和 excludedAttributes 参数
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Param filter query string false "过滤条件，如 userName eq \"alice\""
+// @Param startIndex query int false "起始序号，从1开始"
+// @Param count query int false "每页数量"
+// @Router /scim/v2/Users [get]
+func (s *SCIMService) ListUsers(c *gin.Context) {
+	list, err := s.scimUseCase.ListUsers(c.Request.Context(), scimListQuery(c))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetUser 查询单个用户
+// @Summary SCIM 查询用户详情
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Router /scim/v2/Users/{id} [get]
+func (s *SCIMService) GetUser(c *gin.Context) {
+	user, err := s.scimUseCase.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, user)
+}
+
+// CreateUser 创建用户
+// @Summary SCIM 创建用户
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.SCIMUser true "用户"
+// @Router /scim/v2/Users [post]
+func (s *SCIMService) CreateUser(c *gin.Context) {
+	var in rbac.SCIMUser
+	if !bindSCIM(c, &in) {
+		return
+	}
+	user, err := s.scimUseCase.CreateUser(c.Request.Context(), &in)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", user.Meta.Location)
+	writeSCIM(c, http.StatusCreated, user)
+}
+
+// ReplaceUser 整体替换用户
+// @Summary SCIM 替换用户
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Param body body rbac.SCIMUser true "用户"
+// @Router /scim/v2/Users/{id} [put]
+func (s *SCIMService) ReplaceUser(c *gin.Context) {
+	var in rbac.SCIMUser
+	if !bindSCIM(c, &in) {
+		return
+	}
+	user, err := s.scimUseCase.ReplaceUser(c.Request.Context(), c.Param("id"), &in)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, user)
+}
+
+// PatchUser 部分修改用户
+// @Summary SCIM 修改用户
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Param body body rbac.SCIMPatchRequest true "PATCH 操作"
+// @Router /scim/v2/Users/{id} [patch]
+func (s *SCIMService) PatchUser(c *gin.Context) {
+	var req rbac.SCIMPatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	user, err := s.scimUseCase.PatchUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, user)
+}
+
+// DeleteUser 删除用户
+// @Summary SCIM 删除用户
+// @Tags SCIM
+// @Security Bearer
+// @Param id path string true "用户ID"
+// @Router /scim/v2/Users/{id} [delete]
+func (s *SCIMService) DeleteUser(c *gin.Context) {
+	if err := s.scimUseCase.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups 查询用户组
+// @Summary SCIM 查询用户组
+// @Description 用户组对应系统角色
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Param filter query string false "过滤条件，如 displayName eq \"运维\""
+// @Param startIndex query int false "起始序号，从1开始"
+// @Param count query int false "每页数量"
+// @Router /scim/v2/Groups [get]
+func (s *SCIMService) ListGroups(c *gin.Context) {
+	list, err := s.scimUseCase.ListGroups(c.Request.Context(), scimListQuery(c))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetGroup 查询单个用户组
+// @Summary SCIM 查询用户组详情
+// @Tags SCIM
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户组ID"
+// @Router /scim/v2/Groups/{id} [get]
+func (s *SCIMService) GetGroup(c *gin.Context) {
+	group, err := s.scimUseCase.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, group)
+}
+
+// CreateGroup 创建用户组
+// @Summary SCIM 创建用户组
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body rbac.SCIMGroup true "用户组"
+// @Router /scim/v2/Groups [post]
+func (s *SCIMService) CreateGroup(c *gin.Context) {
+	var in rbac.SCIMGroup
+	if !bindSCIM(c, &in) {
+		return
+	}
+	group, err := s.scimUseCase.CreateGroup(c.Request.Context(), &in)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", group.Meta.Location)
+	writeSCIM(c, http.StatusCreated, group)
+}
+
+// ReplaceGroup 整体替换用户组
+// @Summary SCIM 替换用户组
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户组ID"
+// @Param body body rbac.SCIMGroup true "用户组"
+// @Router /scim/v2/Groups/{id} [put]
+func (s *SCIMService) ReplaceGroup(c *gin.Context) {
+	var in rbac.SCIMGroup
+	if !bindSCIM(c, &in) {
+		return
+	}
+	group, err := s.scimUseCase.ReplaceGroup(c.Request.Context(), c.Param("id"), &in)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, group)
+}
+
+// PatchGroup 部分修改用户组
+// @Summary SCIM 修改用户组
+// @Tags SCIM
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "用户组ID"
+// @Param body body rbac.SCIMPatchRequest true "PATCH 操作"
+// @Router /scim/v2/Groups/{id} [patch]
+func (s *SCIMService) PatchGroup(c *gin.Context) {
+	var req rbac.SCIMPatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	group, err := s.scimUseCase.PatchGroup(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, group)
+}
+
+// DeleteGroup 删除用户组
+// @Summary SCIM 删除用户组
+// @Tags SCIM
+// @Security Bearer
+// @Param id path string true "用户组ID"
+// @Router /scim/v2/Groups/{id} [delete]
+func (s *SCIMService) DeleteGroup(c *gin.Context) {
+	if err := s.scimUseCase.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// scimListQuery 解析列表查询参数
+func scimListQuery(c *gin.Context) *rbac.SCIMListQuery {
+	q := &rbac.SCIMListQuery{Filter: c.Query("filter")}
+	q.StartIndex, _ = strconv.Atoi(c.Query("startIndex"))
+	q.Count, _ = strconv.Atoi(c.Query("count"))
+	if attrs := c.Query("attributes"); attrs != "" {
+		q.Attributes = strings.Split(attrs, ",")
+	}
+	if attrs := c.Query("excludedAttributes"); attrs != "" {
+		q.ExcludedAttributes = strings.Split(attrs, ",")
+	}
+	return q
+}
+
+// bindSCIM 解析请求体，失败时返回 SCIM 错误
+func bindSCIM(c *gin.Context, v interface{}) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(v); err != nil {
+		writeSCIMError(c, &rbac.SCIMError{Status: http.StatusBadRequest, ScimType: "invalidSyntax", Detail: "请求体格式错误: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeSCIM(c *gin.Context, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Data(status, scimContentType, body)
+}
+
+// writeSCIMError 按 RFC 7644 3.12 返回错误，非 SCIM 错误按服务端错误处理
+func writeSCIMError(c *gin.Context, err error) {
+	var scimErr *rbac.SCIMError
+	if !errors.As(err, &scimErr) {
+		scimErr = &rbac.SCIMError{Status: http.StatusInternalServerError, Detail: err.Error()}
+	}
+	body := gin.H{
+		"schemas": []string{rbac.SCIMSchemaError},
+		"status":  strconv.Itoa(scimErr.Status),
+		"detail":  scimErr.Detail,
+	}
+	if scimErr.ScimType != "" {
+		body["scimType"] = scimErr.ScimType
+	}
+	raw, _ := json.Marshal(body)
+	c.Data(scimErr.Status, scimContentType, raw)
+}
+
+func scimList(resources []gin.H) gin.H {
+	return gin.H{
+		"schemas":      []string{rbac.SCIMSchemaListResponse},
+		"totalResults": len(resources),
+		"startIndex":   1,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	}
+}
+
+func scimSchema(id, name string, attributes []gin.H) gin.H {
+	return gin.H{
+		"schemas":    []string{"urn:ietf:params:scim:schemas:core:2.0:Schema"},
+		"id":         id,
+		"name":       name,
+		"attributes": attributes,
+		"meta":       gin.H{"resourceType": "Schema", "location": rbac.SCIMBasePath + "/Schemas/" + id},
+	}
+}
+
+func scimAttribute(name, attrType string, multiValued, required bool, uniqueness string) gin.H {
+	return gin.H{
+		"name":        name,
+		"type":        attrType,
+		"multiValued": multiValued,
+		"required":    required,
+		"caseExact":   false,
+		"mutability":  "readWrite",
+		"returned":    "default",
+		"uniqueness":  uniqueness,
+	}
+}
+
+// scimWriteOnlyAttribute 只写属性，如密码，不会在响应中返回
+func scimWriteOnlyAttribute(name string) gin.H {
+	attr := scimAttribute(name, "string", false, false, "none")
+	attr["mutability"] = "writeOnly"
+	attr["returned"] = "never"
+	return attr
+}
+
+func scimComplexAttribute(name string, multiValued bool, subAttributes []gin.H) gin.H {
+	attr := scimAttribute(name, "complex", multiValued, false, "none")
+	attr["subAttributes"] = subAttributes
+	return attr
+}
+
+func scimMultiValueAttributes() []gin.H {
+	return []gin.H{
+		scimAttribute("value", "string", false, false, "none"),
+		scimAttribute("type", "string", false, false, "none"),
+		scimAttribute("primary", "boolean", false, false, "none"),
+	}
+}
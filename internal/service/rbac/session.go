@@ -0,0 +1,289 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	appLogger "github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"go.uber.org/zap"
+)
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// RefreshTokenResponse 刷新令牌响应
+type RefreshTokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
+
+// SetSessionUseCase 设置会话用例（通过依赖注入）
+func (s *UserService) SetSessionUseCase(sessionUseCase *rbac.SessionUseCase) {
+	s.sessionUseCase = sessionUseCase
+}
+
+// issueSession 登录成功后创建会话并签发访问令牌，未启用会话管理时只签发访问令牌
+func (s *UserService) issueSession(c *gin.Context, user *rbac.SysUser, loginType string) (*RefreshTokenResponse, error) {
+	result := &RefreshTokenResponse{ExpiresIn: int64(s.authService.AccessTokenTTL().Seconds())}
+	sessionID := ""
+	if s.sessionUseCase != nil {
+		session, refreshToken, err := s.sessionUseCase.Create(c.Request.Context(), user, loginType, c.ClientIP(), c.Request.UserAgent())
+		if err != nil {
+			return nil, err
+		}
+		sessionID = session.ID
+		result.RefreshToken = refreshToken
+	}
+
+	token, err := s.authService.GenerateToken(user.ID, user.Username, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	result.Token = token
+	return result, nil
+}
+
+// revokeUserSessions 注销用户的全部会话，用于改密、禁用和删除用户
+func (s *UserService) revokeUserSessions(c *gin.Context, userID uint) {
+	if s.sessionUseCase == nil {
+		return
+	}
+	if _, err := s.sessionUseCase.RevokeAll(c.Request.Context(), userID, ""); err != nil {
+		appLogger.Error("注销用户会话失败", zap.Uint("userID", userID), zap.Error(err))
+	}
+}
+
+// RefreshToken 刷新访问令牌
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换，旧令牌失效
+// @Tags 认证管理
+// @Accept json
+// @Produce json
+// @Param body body RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} response.Response{data=RefreshTokenResponse} "刷新成功"
+// @Router /api/v1/public/token/refresh [post]
+func (s *UserService) RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+	if s.sessionUseCase == nil {
+		response.ErrorCode(c, http.StatusUnauthorized, rbac.ErrSessionRevoked.Error())
+		return
+	}
+
+	session, refreshToken, err := s.sessionUseCase.Refresh(c.Request.Context(), req.RefreshToken, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, rbac.ErrRefreshTokenRotated):
+			response.ErrorCode(c, http.StatusConflict, err.Error())
+		case errors.Is(err, rbac.ErrSessionRevoked):
+			response.ErrorCode(c, http.StatusUnauthorized, err.Error())
+		default:
+			response.ErrorCode(c, http.StatusInternalServerError, "刷新令牌失败")
+		}
+		return
+	}
+
+	token, err := s.authService.GenerateToken(session.UserID, session.Username, session.ID)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成token失败")
+		return
+	}
+
+	response.Success(c, RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.authService.AccessTokenTTL().Seconds()),
+	})
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 注销当前会话，访问令牌和刷新令牌立即失效
+// @Tags 认证管理
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response "退出成功"
+// @Router /api/v1/logout [post]
+func (s *UserService) Logout(c *gin.Context) {
+	sessionID := GetSessionID(c)
+	if s.sessionUseCase != nil && sessionID != "" {
+		if err := s.sessionUseCase.Revoke(c.Request.Context(), GetUserID(c), sessionID); err != nil && !errors.Is(err, rbac.ErrSessionNotFound) {
+			response.ErrorCode(c, http.StatusInternalServerError, "退出登录失败")
+			return
+		}
+	}
+	s.recordLoginLog(GetUsername(c), "logout", "success", c.ClientIP(), c.Request.UserAgent(), "", GetUserID(c))
+
+	response.SuccessWithMessage(c, "退出成功", nil)
+}
+
+// ListMySessions 获取当前用户的登录会话
+// @Summary 获取我的登录会话
+// @Description 获取当前用户所有有效的登录会话（设备、IP、最近活动时间）
+// @Tags 个人中心
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]rbac.Session} "获取成功"
+// @Router /api/v1/profile/sessions [get]
+func (s *UserService) ListMySessions(c *gin.Context) {
+	s.listSessions(c, GetUserID(c), GetSessionID(c))
+}
+
+// RevokeMySession 注销当前用户的指定会话
+// @Summary 注销我的登录会话
+// @Description 注销指定会话，对应设备需要重新登录
+// @Tags 个人中心
+// @Produce json
+// @Security Bearer
+// @Param id path string true "会话ID"
+// @Success 200 {object} response.Response "注销成功"
+// @Router /api/v1/profile/sessions/{id} [delete]
+func (s *UserService) RevokeMySession(c *gin.Context) {
+	s.revokeSession(c, GetUserID(c), c.Param("id"))
+}
+
+// RevokeMySessions 注销当前用户的全部会话
+// @Summary 注销我的全部会话
+// @Description 注销当前用户的全部会话，keepCurrent=true 时保留当前会话
+// @Tags 个人中心
+// @Produce json
+// @Security Bearer
+// @Param keepCurrent query bool false "是否保留当前会话"
+// @Success 200 {object} response.Response "注销成功"
+// @Router /api/v1/profile/sessions [delete]
+func (s *UserService) RevokeMySessions(c *gin.Context) {
+	exceptID := ""
+	if c.Query("keepCurrent") == "true" {
+		exceptID = GetSessionID(c)
+	}
+	s.revokeSessions(c, GetUserID(c), exceptID)
+}
+
+// ListUserSessions 获取指定用户的登录会话
+// @Summary 获取用户登录会话
+// @Description 管理员查看用户所有有效的登录会话
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response{data=[]rbac.Session} "获取成功"
+// @Router /api/v1/users/{id}/sessions [get]
+func (s *UserService) ListUserSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+		return
+	}
+	s.listSessions(c, uint(id), GetSessionID(c))
+}
+
+// RevokeUserSession 注销指定用户的某个会话
+// @Summary 注销用户登录会话
+// @Description 管理员强制下线用户的指定会话
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Param sessionId path string true "会话ID"
+// @Success 200 {object} response.Response "注销成功"
+// @Router /api/v1/users/{id}/sessions/{sessionId} [delete]
+func (s *UserService) RevokeUserSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+		return
+	}
+	s.revokeSession(c, uint(id), c.Param("sessionId"))
+}
+
+// RevokeUserSessions 注销指定用户的全部会话
+// @Summary 注销用户全部会话
+// @Description 管理员强制下线用户的全部会话
+// @Tags 用户管理
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} response.Response "注销成功"
+// @Router /api/v1/users/{id}/sessions [delete]
+func (s *UserService) RevokeUserSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的用户ID")
+		return
+	}
+	s.revokeSessions(c, uint(id), "")
+}
+
+func (s *UserService) listSessions(c *gin.Context, userID uint, currentID string) {
+	if s.sessionUseCase == nil {
+		response.Success(c, []*rbac.Session{})
+		return
+	}
+	sessions, err := s.sessionUseCase.List(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "获取会话失败: "+err.Error())
+		return
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	response.Success(c, sessions)
+}
+
+func (s *UserService) revokeSession(c *gin.Context, userID uint, sessionID string) {
+	if s.sessionUseCase == nil {
+		response.ErrorCode(c, http.StatusNotFound, rbac.ErrSessionNotFound.Error())
+		return
+	}
+	if err := s.sessionUseCase.Revoke(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, rbac.ErrSessionNotFound) {
+			response.ErrorCode(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.ErrorCode(c, http.StatusInternalServerError, "注销会话失败: "+err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "会话已注销", nil)
+}
+
+func (s *UserService) revokeSessions(c *gin.Context, userID uint, exceptID string) {
+	if s.sessionUseCase == nil {
+		response.SuccessWithMessage(c, "会话已注销", gin.H{"count": 0})
+		return
+	}
+	count, err := s.sessionUseCase.RevokeAll(c.Request.Context(), userID, exceptID)
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "注销会话失败: "+err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "会话已注销", gin.H{"count": count})
+}
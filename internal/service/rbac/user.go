@@ -38,6 +38,15 @@ type UserService struct {
 	authService     *AuthService
 	captchaService  *CaptchaService
 	loginLogUseCase *audit.LoginLogUseCase
+	authChain       *rbac.AuthChain
+	oidcUseCase     *rbac.OIDCUseCase
+	mfaUseCase      *rbac.MFAUseCase
+	sessionUseCase  *rbac.SessionUseCase
+	passwordUseCase *rbac.PasswordUseCase
+	// 按钮权限用例，分配角色后刷新权限缓存
+	permissionUseCase *rbac.PermissionUseCase
+	// 批量导入导出用户
+	userImportUseCase *rbac.UserImportUseCase
 }
 
 func NewUserService(userUseCase *rbac.UserUseCase, authService *AuthService) *UserService {
@@ -47,6 +56,11 @@ func NewUserService(userUseCase *rbac.UserUseCase, authService *AuthService) *Us
 	}
 }
 
+// SetAuthChain 设置登录认证链（通过依赖注入），未设置时只校验本地密码
+func (s *UserService) SetAuthChain(authChain *rbac.AuthChain) {
+	s.authChain = authChain
+}
+
 // SetCaptchaService 设置验证码服务（通过依赖注入）
 func (s *UserService) SetCaptchaService(captchaService *CaptchaService) {
 	s.captchaService = captchaService
@@ -68,8 +82,21 @@ type LoginRequest struct {
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string       `json:"token"`
+	Token string        `json:"token"`
 	User  *rbac.SysUser `json:"user"`
+	// 访问令牌有效期较短，过期前使用 refreshToken 调用 /public/token/refresh 换取新令牌
+	RefreshToken string `json:"refreshToken,omitempty"`
+	ExpiresIn    int64  `json:"expiresIn,omitempty"`
+	// 需要修改密码时不返回 token，前端使用 passwordToken 调用 /public/login/password 设置新密码后完成登录
+	PasswordChangeRequired bool       `json:"passwordChangeRequired,omitempty"`
+	PasswordChangeReason   string     `json:"passwordChangeReason,omitempty"`
+	PasswordToken          string     `json:"passwordToken,omitempty"`
+	PasswordExpiresAt      *time.Time `json:"passwordExpiresAt,omitempty"` // 密码即将过期时返回
+	// 需要双因素认证时不返回 token，前端使用 mfaToken 调用 /public/login/mfa 完成登录
+	MFARequired      bool     `json:"mfaRequired,omitempty"`
+	MFASetupRequired bool     `json:"mfaSetupRequired,omitempty"`
+	MFAToken         string   `json:"mfaToken,omitempty"`
+	RecoveryCodes    []string `json:"recoveryCodes,omitempty"`
 }
 
 // RegisterRequest 注册请求
@@ -133,8 +160,26 @@ func (s *UserService) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := s.userUseCase.ValidatePassword(c.Request.Context(), req.Username, req.Password)
+	// 登录失败次数过多时锁定账号
+	lockTarget, err := s.checkLoginLocked(c.Request.Context(), req.Username)
 	if err != nil {
+		s.recordLoginLog(req.Username, "web", "failed", clientIP, userAgent, err.Error(), lockTarget.ID)
+		response.ErrorCode(c, http.StatusOK, err.Error())
+		return
+	}
+
+	var user *rbac.SysUser
+	if s.authChain != nil {
+		var provider string
+		user, provider, err = s.authChain.Authenticate(c.Request.Context(), req.Username, req.Password)
+		if err == nil {
+			appLogger.Info("认证通过", zap.String("username", req.Username), zap.String("provider", provider))
+		}
+	} else {
+		user, err = s.userUseCase.ValidatePassword(c.Request.Context(), req.Username, req.Password)
+	}
+	if err != nil {
+		err = s.recordLoginFailure(c.Request.Context(), lockTarget, err)
 		appLogger.Error("登录失败", zap.String("username", req.Username), zap.Error(err))
 		// 记录登录日志 - 用户名或密码错误
 		s.recordLoginLog(req.Username, "web", "failed", clientIP, userAgent, err.Error(), 0)
@@ -149,29 +194,26 @@ func (s *UserService) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := s.authService.GenerateToken(user.ID, user.Username)
+	// 启用或被要求启用双因素认证时，先返回登录凭据，校验第二因素后再签发token
+	mfaToken, mfaSetup, err := s.issueMFATicket(c.Request.Context(), user)
 	if err != nil {
-		// 记录登录日志 - 生成token失败
-		s.recordLoginLog(req.Username, "web", "failed", clientIP, userAgent, "生成token失败", user.ID)
-		response.ErrorCode(c, http.StatusInternalServerError, "生成token失败")
+		s.recordLoginLog(req.Username, "web", "failed", clientIP, userAgent, "检查双因素认证失败", user.ID)
+		response.ErrorCode(c, http.StatusInternalServerError, "检查双因素认证失败")
+		return
+	}
+	if mfaToken != "" {
+		response.Success(c, LoginResponse{
+			MFARequired:      true,
+			MFASetupRequired: mfaSetup,
+			MFAToken:         mfaToken,
+		})
 		return
 	}
 
-	// 清空密码字段，防止返回给前端
-	user.Password = ""
-
-	// 更新最后登录时间
-	_ = s.userUseCase.Update(c.Request.Context(), user)
-
-	// 记录登录日志 - 登录成功
-	s.recordLoginLog(req.Username, "web", "success", clientIP, userAgent, "", user.ID)
-
-	appLogger.Info("用户登录成功", zap.String("username", req.Username))
-
-	response.Success(c, LoginResponse{
-		Token: token,
-		User:  user,
-	})
+	// 需要修改密码时返回改密凭据，否则签发token并记录登录日志
+	if s.completeLogin(c, user, "web", nil) {
+		appLogger.Info("用户登录成功", zap.String("username", req.Username))
+	}
 }
 
 // recordLoginLog 记录登录日志
@@ -309,6 +351,8 @@ func (s *UserService) ChangePassword(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// 密码修改后所有会话（包括当前会话）都需要重新登录
+	s.revokeUserSessions(c, userID)
 
 	response.SuccessWithMessage(c, "密码修改成功", nil)
 }
@@ -330,6 +374,9 @@ func (s *UserService) CreateUser(c *gin.Context) {
 		return
 	}
 
+	// 管理员设置的初始密码，按策略要求首次登录时修改
+	req.MustChangePassword = s.passwordUseCase != nil && s.passwordUseCase.ForceChangeOnReset()
+	req.LockedUntil = nil
 	if err := s.userUseCase.Create(c.Request.Context(), &req); err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "创建失败: "+err.Error())
 		return
@@ -367,6 +414,10 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 	}
 
 	req.ID = uint(id)
+	// 密码和锁定状态只能通过专门的接口修改
+	req.PasswordChangedAt = nil
+	req.MustChangePassword = false
+	req.LockedUntil = nil
 	if err := s.userUseCase.Update(c.Request.Context(), &req); err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "更新失败: "+err.Error())
 		return
@@ -379,6 +430,11 @@ func (s *UserService) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// 禁用用户时强制下线
+	if user.Status != 1 {
+		s.revokeUserSessions(c, user.ID)
+	}
+
 	// 清空密码字段，防止返回给前端
 	user.Password = ""
 
@@ -407,6 +463,7 @@ func (s *UserService) DeleteUser(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "删除失败: "+err.Error())
 		return
 	}
+	s.revokeUserSessions(c, uint(id))
 
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
@@ -513,6 +570,7 @@ func (s *UserService) AssignUserRoles(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "分配失败: "+err.Error())
 		return
 	}
+	invalidatePermissions(s.permissionUseCase)
 
 	response.Success(c, nil)
 }
@@ -589,6 +647,7 @@ func (s *UserService) ResetPassword(c *gin.Context) {
 		response.ErrorCode(c, http.StatusInternalServerError, "重置密码失败: "+err.Error())
 		return
 	}
+	s.revokeUserSessions(c, uint(id))
 
 	response.SuccessWithMessage(c, "密码重置成功", nil)
 }
@@ -0,0 +1,290 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package rbac
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+)
+
+// userImportMaxFileSize 导入文件大小上限
+const userImportMaxFileSize = 10 << 20
+
+// userExportColumns 用户导出的列，用于季度权限复核
+var userExportColumns = []string{"用户名", "真实姓名", "邮箱", "手机号", "部门", "部门编码", "角色", "角色编码", "岗位", "岗位编码", "状态", "来源", "最后登录时间", "创建时间"}
+
+// SetUserImportUseCase 设置批量导入导出用例
+func (s *UserService) SetUserImportUseCase(userImportUseCase *rbac.UserImportUseCase) {
+	s.userImportUseCase = userImportUseCase
+}
+
+// DownloadUserImportTemplate 下载用户导入模板
+// @Summary 下载用户导入模板
+// @Description 下载用户批量导入的 Excel 模板
+// @Tags 用户管理
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security Bearer
+// @Success 200 {file} file "Excel模板文件"
+// @Router /api/v1/users/import/template [get]
+func (s *UserService) DownloadUserImportTemplate(c *gin.Context) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheetName := f.GetSheetName(0)
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E6E6FA"}, Pattern: 1},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+	})
+	f.SetColWidth(sheetName, "A", "H", 20)
+	for i, header := range rbac.UserImportColumns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheetName, cell, header)
+		f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	}
+
+	examples := [][]string{
+		{"zhangsan", "张三", "zhangsan@example.com", "13800000000", "ops", "developer", "dev", ""},
+		{"lisi", "李四", "lisi@example.com", "", "ops", "developer,auditor", "", "Init@2026!"},
+	}
+	for i, example := range examples {
+		for j, val := range example {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+			f.SetCellValue(sheetName, cell, val)
+		}
+	}
+
+	// 说明放在单独的工作表，导入时只读取第一个工作表
+	noteSheet := "说明"
+	f.NewSheet(noteSheet)
+	f.SetColWidth(noteSheet, "A", "A", 90)
+	for i, note := range []string{
+		"1. 带*号的是必填项，导入前请删除示例数据",
+		"2. 部门编码、角色编码、岗位编码需要在系统中已存在，多个角色或岗位用逗号分隔",
+		"3. 初始密码需符合密码策略；不填写时可勾选自动生成，生成的密码首次登录必须修改",
+		"4. 也可以按相同列顺序上传 UTF-8 编码的 CSV 文件",
+	} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetCellValue(noteSheet, cell, note)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成模板文件失败")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=user_import_template.xlsx")
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// ImportUsers 批量导入用户
+// @Summary 批量导入用户
+// @Description 通过 Excel 或 CSV 批量创建用户并分配部门、角色和岗位。dryRun=true 时只校验不写入；generatePassword=true 时为未填写密码的用户生成初始密码，并要求首次登录修改
+// @Tags 用户管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "Excel(.xlsx)或CSV文件"
+// @Param dryRun formData bool false "只校验不导入"
+// @Param generatePassword formData bool false "自动生成初始密码"
+// @Success 200 {object} response.Response{data=rbac.UserImportResult} "导入结果"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /api/v1/users/import [post]
+func (s *UserService) ImportUsers(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "请选择要上传的文件")
+		return
+	}
+	if file.Size > userImportMaxFileSize {
+		response.ErrorCode(c, http.StatusBadRequest, "文件不能超过10MB")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "打开文件失败")
+		return
+	}
+	defer src.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(src); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "读取文件失败")
+		return
+	}
+
+	rows, err := rbac.ParseUserImportFile(file.Filename, buf.Bytes())
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.PostForm("dryRun"))
+	generatePassword, _ := strconv.ParseBool(c.PostForm("generatePassword"))
+	result, err := s.userImportUseCase.Import(c.Request.Context(), rows, rbac.UserImportOptions{
+		DryRun:           dryRun,
+		GeneratePassword: generatePassword,
+	})
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "导入失败: "+err.Error())
+		return
+	}
+	if !dryRun && result.SuccessCount > 0 {
+		invalidatePermissions(s.permissionUseCase)
+	}
+
+	response.Success(c, result)
+}
+
+// ExportUsers 导出用户
+// @Summary 导出用户
+// @Description 导出用户及其部门、角色和岗位，用于权限复核，受数据权限限制
+// @Tags 用户管理
+// @Produce application/octet-stream
+// @Security Bearer
+// @Param format query string false "导出格式 xlsx/csv" default(xlsx)
+// @Param keyword query string false "搜索关键字"
+// @Param departmentId query int false "部门ID"
+// @Success 200 {file} file "导出文件"
+// @Router /api/v1/users/export [get]
+func (s *UserService) ExportUsers(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", "xlsx"))
+	if format != "xlsx" && format != "csv" {
+		response.ErrorCode(c, http.StatusBadRequest, "仅支持 xlsx 和 csv 格式")
+		return
+	}
+	departmentID, _ := strconv.ParseUint(c.Query("departmentId"), 10, 32)
+
+	users, err := s.userImportUseCase.Export(c.Request.Context(), c.Query("keyword"), uint(departmentID))
+	if err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "查询失败: "+err.Error())
+		return
+	}
+
+	records := make([][]string, 0, len(users))
+	for _, user := range users {
+		records = append(records, userExportRecord(user))
+	}
+
+	filename := fmt.Sprintf("users_%s.%s", time.Now().Format("20060102150405"), format)
+	var buf bytes.Buffer
+	if format == "csv" {
+		// 写入 BOM，Excel 打开时正确识别 UTF-8
+		buf.WriteString("\xef\xbb\xbf")
+		w := csv.NewWriter(&buf)
+		w.Write(userExportColumns)
+		w.WriteAll(records)
+		if err := w.Error(); err != nil {
+			response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	sheetName := f.GetSheetName(0)
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E6E6FA"}, Pattern: 1},
+	})
+	f.SetColWidth(sheetName, "A", "N", 18)
+	for i, header := range userExportColumns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheetName, cell, header)
+		f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	}
+	for i, record := range records {
+		for j, val := range record {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+2)
+			f.SetCellValue(sheetName, cell, val)
+		}
+	}
+	if err := f.Write(&buf); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "生成导出文件失败")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// userExportRecord 按导出列顺序生成一行
+func userExportRecord(user *rbac.SysUser) []string {
+	var deptName, deptCode string
+	if user.Department != nil {
+		deptName, deptCode = user.Department.Name, user.Department.Code
+	}
+	roleNames := make([]string, 0, len(user.Roles))
+	roleCodes := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roleNames = append(roleNames, role.Name)
+		roleCodes = append(roleCodes, role.Code)
+	}
+	positionNames := make([]string, 0, len(user.Positions))
+	positionCodes := make([]string, 0, len(user.Positions))
+	for _, position := range user.Positions {
+		positionNames = append(positionNames, position.PostName)
+		positionCodes = append(positionCodes, position.PostCode)
+	}
+	status := "禁用"
+	if user.Status == 1 {
+		status = "启用"
+	}
+	source := user.Source
+	if source == "" {
+		source = rbac.UserSourceLocal
+	}
+	lastLogin := ""
+	if user.LastLoginAt != nil {
+		lastLogin = user.LastLoginAt.Format("2006-01-02 15:04:05")
+	}
+	return []string{
+		user.Username,
+		user.RealName,
+		user.Email,
+		user.Phone,
+		deptName,
+		deptCode,
+		strings.Join(roleNames, ","),
+		strings.Join(roleCodes, ","),
+		strings.Join(positionNames, ","),
+		strings.Join(positionCodes, ","),
+		status,
+		source,
+		lastLogin,
+		user.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 
 	"github.com/ydcloud-dy/opshub/cmd/root"
+	_ "github.com/ydcloud-dy/opshub/cmd/audit"   // 注册审计命令
 	_ "github.com/ydcloud-dy/opshub/cmd/config"  // 注册配置命令
 	_ "github.com/ydcloud-dy/opshub/cmd/server"  // 注册服务命令
 	_ "github.com/ydcloud-dy/opshub/cmd/version" // 注册版本命令
@@ -41,16 +41,99 @@ CREATE TABLE IF NOT EXISTS `sys_user` (
   `department_id` bigint unsigned DEFAULT 0 COMMENT '部门ID',
   `bio` text COMMENT '个人简介',
   `last_login_at` datetime COMMENT '最后登录时间',
+  `source` varchar(20) DEFAULT 'local' COMMENT '用户来源 local/ldap/oidc/service',
+  `external_id` varchar(255) COMMENT '外部目录中的用户标识',
+  `password_changed_at` datetime COMMENT '密码修改时间',
+  `must_change_password` tinyint(1) DEFAULT 0 COMMENT '下次登录必须修改密码',
+  `login_failures` int DEFAULT 0 COMMENT '登录失败次数',
+  `login_failed_at` datetime COMMENT '本轮首次登录失败时间',
+  `locked_until` datetime COMMENT '锁定截止时间',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `uk_username_deleted` (`username`, `deleted_at`),
+  KEY `idx_sys_user_external_id` (`external_id`),
   KEY `idx_department_id` (`department_id`),
   KEY `idx_status` (`status`),
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 用户外部身份绑定表
+CREATE TABLE IF NOT EXISTS `sys_user_identity` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `provider` varchar(50) NOT NULL COMMENT '身份提供者',
+  `subject` varchar(255) NOT NULL COMMENT '提供者中的用户标识(sub)',
+  `email` varchar(100) COMMENT '提供者返回的邮箱',
+  `last_login_at` datetime COMMENT '最后登录时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_identity_provider_subject` (`provider`, `subject`),
+  KEY `idx_sys_user_identity_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 用户双因素认证表
+CREATE TABLE IF NOT EXISTS `sys_user_mfa` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `secret` varchar(255) NOT NULL COMMENT 'TOTP密钥(加密)',
+  `enabled` tinyint(1) DEFAULT 0 COMMENT '是否已启用',
+  `confirmed_at` datetime COMMENT '绑定确认时间',
+  `last_used_step` bigint DEFAULT 0 COMMENT '最近一次使用的时间步，防止验证码重放',
+  `failed_count` int DEFAULT 0 COMMENT '连续验证失败次数',
+  `locked_until` datetime COMMENT '锁定截止时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_sys_user_mfa_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 双因素认证恢复码表
+CREATE TABLE IF NOT EXISTS `sys_user_recovery_code` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `code_hash` varchar(64) NOT NULL COMMENT '恢复码SHA-256摘要',
+  `used_at` datetime COMMENT '使用时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_user_recovery_code_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- API 令牌表
+CREATE TABLE IF NOT EXISTS `sys_api_token` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL COMMENT '令牌名称',
+  `user_id` bigint unsigned NOT NULL COMMENT '令牌身份（用户或服务账号）',
+  `created_by` bigint unsigned DEFAULT NULL COMMENT '创建人ID',
+  `prefix` varchar(20) COMMENT '令牌前缀，用于识别',
+  `token_hash` varchar(64) NOT NULL COMMENT '令牌SHA-256摘要',
+  `scopes` json COMMENT '作用域',
+  `expires_at` datetime COMMENT '过期时间',
+  `last_used_at` datetime COMMENT '最近使用时间',
+  `last_used_ip` varchar(64) COMMENT '最近使用IP',
+  `description` varchar(255) COMMENT '描述',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` datetime COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_token_hash` (`token_hash`),
+  KEY `idx_sys_api_token_user_id` (`user_id`),
+  KEY `idx_sys_api_token_expires_at` (`expires_at`),
+  KEY `idx_sys_api_token_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 用户历史密码表
+CREATE TABLE IF NOT EXISTS `sys_user_password_history` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `password` varchar(255) NOT NULL COMMENT '密码哈希',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_user_password_history_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- 角色表
 CREATE TABLE IF NOT EXISTS `sys_role` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
@@ -59,6 +142,8 @@ CREATE TABLE IF NOT EXISTS `sys_role` (
   `description` varchar(200) COMMENT '角色描述',
   `sort` int DEFAULT 0 COMMENT '排序',
   `status` tinyint DEFAULT 1 COMMENT '状态 1:启用 0:禁用',
+  `require_mfa` tinyint(1) DEFAULT 0 COMMENT '是否要求双因素认证',
+  `data_scope` tinyint DEFAULT 1 COMMENT '数据范围 1:全部 2:本部门及下级 3:本部门 4:仅本人',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
@@ -179,6 +264,9 @@ CREATE TABLE IF NOT EXISTS `sys_operation_log` (
   `status` int COMMENT '响应状态码',
   `error_msg` text COMMENT '错误信息',
   `cost_time` bigint COMMENT '耗时(毫秒)',
+  `route` varchar(200) COMMENT '路由模式',
+  `resource_type` varchar(50) COMMENT '资源类型',
+  `resource_id` varchar(200) COMMENT '资源ID',
   `ip` varchar(50) COMMENT '客户端IP',
   `user_agent` varchar(500) COMMENT '用户代理',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
@@ -188,6 +276,7 @@ CREATE TABLE IF NOT EXISTS `sys_operation_log` (
   KEY `idx_user_id` (`user_id`),
   KEY `idx_username` (`username`),
   KEY `idx_action` (`action`),
+  KEY `idx_resource` (`resource_type`, `resource_id`),
   KEY `idx_created_at` (`created_at`),
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -213,6 +302,7 @@ CREATE TABLE IF NOT EXISTS `sys_login_log` (
   KEY `idx_user_id` (`user_id`),
   KEY `idx_username` (`username`),
   KEY `idx_login_time` (`login_time`),
+  KEY `idx_ip` (`ip`),
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
@@ -241,6 +331,138 @@ CREATE TABLE IF NOT EXISTS `sys_data_log` (
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 审计链头表
+CREATE TABLE IF NOT EXISTS `sys_audit_chain` (
+  `name` varchar(50) NOT NULL COMMENT '链名称',
+  `table_name` varchar(100) COMMENT '审计表名',
+  `last_seq` bigint unsigned COMMENT '最后序号',
+  `last_hash` varchar(64) COMMENT '最后哈希',
+  `pruned_seq` bigint unsigned DEFAULT 0 COMMENT '已归档清理到的序号',
+  `pruned_hash` varchar(64) COMMENT '已归档清理的最后一条哈希',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 审计链记录表
+CREATE TABLE IF NOT EXISTS `sys_audit_chain_entry` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `chain` varchar(50) COMMENT '链名称',
+  `seq` bigint unsigned COMMENT '链内序号',
+  `record_id` bigint unsigned COMMENT '审计记录ID',
+  `digest` varchar(64) COMMENT '审计记录内容摘要',
+  `prev_hash` varchar(64) COMMENT '前一条哈希',
+  `hash` varchar(64) COMMENT '本条哈希',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_chain_seq` (`chain`, `seq`),
+  KEY `idx_sys_audit_chain_entry_record_id` (`record_id`),
+  KEY `idx_chain_created` (`chain`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 审计链签名检查点表
+CREATE TABLE IF NOT EXISTS `sys_audit_checkpoint` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `chain` varchar(50) COMMENT '链名称',
+  `seq` bigint unsigned COMMENT '链头序号',
+  `hash` varchar(64) COMMENT '链头哈希',
+  `signature` varchar(64) COMMENT 'HMAC-SHA256签名',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_audit_checkpoint_chain` (`chain`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 审计日志保留策略表
+CREATE TABLE IF NOT EXISTS `sys_audit_retention_policy` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `log_type` varchar(50) NOT NULL COMMENT '日志类型',
+  `retention_days` bigint NOT NULL DEFAULT 180 COMMENT '保留天数',
+  `enabled` tinyint(1) DEFAULT 0 COMMENT '是否启用',
+  `last_run_at` datetime COMMENT '最后执行时间',
+  `last_status` varchar(20) COMMENT '最后执行状态',
+  `last_message` varchar(500) COMMENT '最后执行信息',
+  `last_archived` bigint COMMENT '最后一次归档记录数',
+  `running_since` datetime COMMENT '执行开始时间',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_sys_audit_retention_policy_log_type` (`log_type`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 审计日志归档表
+CREATE TABLE IF NOT EXISTS `sys_audit_archive` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `log_type` varchar(50) COMMENT '日志类型',
+  `chain` varchar(50) COMMENT '审计链名称',
+  `storage` varchar(20) COMMENT '存储类型',
+  `object_key` varchar(500) COMMENT '归档文件路径',
+  `record_count` bigint COMMENT '记录数',
+  `missing_count` bigint COMMENT '链记录对应审计记录缺失数',
+  `tampered_count` bigint COMMENT '摘要不一致记录数',
+  `from_seq` bigint unsigned COMMENT '起始链序号',
+  `to_seq` bigint unsigned COMMENT '结束链序号',
+  `last_hash` varchar(64) COMMENT '结束链记录哈希',
+  `start_time` datetime COMMENT '最早记录时间',
+  `end_time` datetime COMMENT '最晚记录时间',
+  `cutoff` datetime COMMENT '保留截止时间',
+  `file_size` bigint COMMENT '文件大小',
+  `sha256` varchar(64) COMMENT '文件SHA256',
+  `signature` varchar(64) COMMENT 'HMAC-SHA256签名',
+  `imported_at` datetime COMMENT '导入调查时间',
+  `imported_count` bigint COMMENT '已导入记录数',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_audit_archive_log_type` (`log_type`),
+  KEY `idx_sys_audit_archive_chain` (`chain`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 审计日志归档导入记录表（调查用，与在线审计表隔离）
+CREATE TABLE IF NOT EXISTS `sys_audit_archive_record` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `archive_id` bigint unsigned COMMENT '归档ID',
+  `record_id` bigint unsigned COMMENT '原审计记录ID',
+  `username` varchar(50) COMMENT '用户名',
+  `record_time` datetime COMMENT '记录时间',
+  `data` longtext COMMENT '记录内容',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_audit_archive_record_archive_id` (`archive_id`),
+  KEY `idx_sys_audit_archive_record_username` (`username`),
+  KEY `idx_sys_audit_archive_record_record_time` (`record_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 登录安全事件表
+CREATE TABLE IF NOT EXISTS `sys_security_event` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `event_type` varchar(30) COMMENT '事件类型',
+  `level` varchar(20) COMMENT '等级',
+  `user_id` bigint unsigned COMMENT '用户ID',
+  `username` varchar(50) COMMENT '用户名',
+  `ip` varchar(50) COMMENT 'IP地址',
+  `location` varchar(100) COMMENT '登录地点',
+  `login_log_id` bigint unsigned COMMENT '触发的登录日志ID',
+  `title` varchar(200) COMMENT '事件摘要',
+  `detail` text COMMENT '检测依据',
+  `status` varchar(20) DEFAULT 'open' COMMENT '处理状态',
+  `notified` tinyint(1) DEFAULT 0 COMMENT '是否已发送告警',
+  `notify_error` varchar(500) COMMENT '告警发送失败原因',
+  `handled_by` bigint unsigned COMMENT '处理人ID',
+  `handler_name` varchar(50) COMMENT '处理人',
+  `handled_at` datetime COMMENT '处理时间',
+  `remark` varchar(500) COMMENT '处理备注',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_sys_security_event_event_type` (`event_type`),
+  KEY `idx_sys_security_event_level` (`level`),
+  KEY `idx_sys_security_event_user_id` (`user_id`),
+  KEY `idx_sys_security_event_username` (`username`),
+  KEY `idx_sys_security_event_ip` (`ip`),
+  KEY `idx_sys_security_event_login_log_id` (`login_log_id`),
+  KEY `idx_sys_security_event_status` (`status`),
+  KEY `idx_sys_security_event_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================
 -- 3. 资产管理表
 -- ============================================================
@@ -313,6 +535,7 @@ CREATE TABLE IF NOT EXISTS `hosts` (
   `disk_usage` float COMMENT '磁盘使用率',
   `uptime` varchar(100) COMMENT '运行时间',
   `hostname` varchar(100) COMMENT '主机名',
+  `created_by` bigint unsigned DEFAULT 0 COMMENT '创建人ID',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
@@ -320,6 +543,7 @@ CREATE TABLE IF NOT EXISTS `hosts` (
   KEY `idx_group_id` (`group_id`),
   KEY `idx_ip` (`ip`),
   KEY `idx_status` (`status`),
+  KEY `idx_created_by` (`created_by`),
   KEY `idx_deleted_at` (`deleted_at`),
   CONSTRAINT `fk_hosts_group` FOREIGN KEY (`group_id`) REFERENCES `asset_group` (`id`) ON DELETE SET NULL
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -349,12 +573,15 @@ CREATE TABLE IF NOT EXISTS `sys_role_asset_permission` (
   `asset_group_id` bigint unsigned NOT NULL COMMENT '资产组ID',
   `host_ids` json COMMENT '主机ID列表',
   `permissions` int unsigned DEFAULT 63 COMMENT '权限位',
+  `start_at` datetime DEFAULT NULL COMMENT '生效时间',
+  `expires_at` datetime DEFAULT NULL COMMENT '过期时间',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `uk_role_asset` (`role_id`, `asset_group_id`, `deleted_at`),
   KEY `idx_asset_group_id` (`asset_group_id`),
+  KEY `idx_expires_at` (`expires_at`),
   KEY `idx_deleted_at` (`deleted_at`),
   CONSTRAINT `fk_role_asset_perm_role` FOREIGN KEY (`role_id`) REFERENCES `sys_role` (`id`) ON DELETE CASCADE,
   CONSTRAINT `fk_role_asset_perm_group` FOREIGN KEY (`asset_group_id`) REFERENCES `asset_group` (`id`) ON DELETE CASCADE
@@ -371,7 +598,8 @@ CREATE TABLE IF NOT EXISTS `ssh_terminal_sessions` (
   `recording_path` varchar(500) COMMENT '录制文件路径',
   `duration` int COMMENT '会话时长(秒)',
   `file_size` bigint COMMENT '文件大小(字节)',
-  `status` varchar(20) DEFAULT 'recording' COMMENT '会话状态 recording/completed/failed',
+  `status` varchar(20) DEFAULT 'recording' COMMENT '会话状态 recording/completed/failed/rejected',
+  `close_reason` varchar(50) COMMENT '会话结束原因',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
@@ -383,6 +611,28 @@ CREATE TABLE IF NOT EXISTS `ssh_terminal_sessions` (
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 终端会话策略表
+CREATE TABLE IF NOT EXISTS `ssh_session_policies` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL COMMENT '策略名称',
+  `scope_type` varchar(20) NOT NULL COMMENT '作用范围 role/group',
+  `scope_id` bigint unsigned NOT NULL COMMENT '角色ID或资产分组ID',
+  `idle_timeout` int DEFAULT 0 COMMENT '空闲超时(秒)，0表示不限制',
+  `idle_warning` int DEFAULT 60 COMMENT '空闲断开前提前警告(秒)',
+  `max_duration` int DEFAULT 0 COMMENT '最长会话时长(秒)，0表示不限制',
+  `max_concurrent` int DEFAULT 0 COMMENT '每个用户最大并发会话数，0表示不限制',
+  `allowed_windows` varchar(255) COMMENT '允许的时间段，如 09:00-18:00,20:00-22:00',
+  `allowed_weekdays` varchar(20) COMMENT '允许的星期，1-7 表示周一到周日',
+  `enabled` tinyint(1) DEFAULT 1 COMMENT '是否启用',
+  `description` varchar(500) COMMENT '描述',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` datetime COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_session_policy_scope` (`scope_type`, `scope_id`),
+  KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================
 -- 4. 任务管理表 (Task Plugin)
 -- ============================================================
@@ -419,18 +669,21 @@ CREATE TABLE IF NOT EXISTS `job_tasks` (
   `name` varchar(255) NOT NULL COMMENT '任务名称',
   `template_id` bigint unsigned COMMENT '模板ID',
   `task_type` varchar(50) NOT NULL COMMENT '任务类型 manual/ansible/cron',
-  `status` varchar(50) DEFAULT 'pending' COMMENT '状态 pending/running/success/failed',
+  `status` varchar(50) DEFAULT 'pending' COMMENT '状态 pending/running/success/failed/cancelled/skipped',
   `target_hosts` text COMMENT '目标主机列表(JSON)',
   `parameters` json COMMENT '执行参数',
   `execute_time` datetime COMMENT '执行时间',
   `result` json COMMENT '执行结果',
   `error_message` text COMMENT '错误信息',
+  `output` longtext COMMENT '完整输出',
+  `schedule_id` bigint unsigned COMMENT '定时任务ID',
   `created_by` bigint unsigned NOT NULL COMMENT '创建者ID',
   `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
   `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` datetime COMMENT '删除时间',
   PRIMARY KEY (`id`),
   KEY `idx_template_id` (`template_id`),
+  KEY `idx_schedule_id` (`schedule_id`),
   KEY `idx_task_type` (`task_type`),
   KEY `idx_status` (`status`),
   KEY `idx_created_at` (`created_at`),
@@ -462,6 +715,37 @@ CREATE TABLE IF NOT EXISTS `ansible_tasks` (
   KEY `idx_deleted_at` (`deleted_at`)
 ) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 定时任务表
+CREATE TABLE IF NOT EXISTS `job_schedules` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL COMMENT '定时任务名称',
+  `description` text COMMENT '描述',
+  `cron_expr` varchar(100) NOT NULL COMMENT 'cron表达式',
+  `timezone` varchar(64) COMMENT '时区',
+  `template_id` bigint unsigned NOT NULL COMMENT '模板ID',
+  `script_type` varchar(20) NOT NULL DEFAULT 'Shell' COMMENT '脚本类型 Shell/Python',
+  `variables` text COMMENT '模板变量取值(JSON)',
+  `content` longtext COMMENT '保存时渲染的脚本快照',
+  `timeout` int DEFAULT 0 COMMENT '超时时间(秒)',
+  `target_type` varchar(20) NOT NULL DEFAULT 'hosts' COMMENT '目标类型 hosts/group',
+  `target_hosts` text COMMENT '目标主机列表(JSON)',
+  `group_id` bigint unsigned DEFAULT 0 COMMENT '目标分组ID',
+  `overlap_policy` varchar(20) NOT NULL DEFAULT 'skip' COMMENT '重叠策略 skip/queue/allow',
+  `enabled` tinyint(1) DEFAULT 0 COMMENT '是否启用',
+  `notify_on_failure` tinyint(1) DEFAULT 0 COMMENT '失败时通知',
+  `notify_user_ids` text COMMENT '通知用户ID列表(JSON)',
+  `last_run_time` datetime COMMENT '最后执行时间',
+  `last_run_status` varchar(50) COMMENT '最后执行状态',
+  `created_by` bigint unsigned NOT NULL COMMENT '创建者ID',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` datetime COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_template_id` (`template_id`),
+  KEY `idx_enabled` (`enabled`),
+  KEY `idx_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- ============================================================
 -- 5. Kubernetes 插件表
 -- ============================================================
@@ -832,6 +1116,9 @@ VALUES
   -- ========== 操作审计子菜单 (parent_id=23) ==========
   (24, '操作日志', 'operation-logs', 2, 23, '/audit/operation-logs', 'audit/OperationLogs', 'Document', 1, 1, 1, NOW(), NOW()),
   (25, '登录日志', 'login-logs', 2, 23, '/audit/login-logs', 'audit/LoginLogs', 'CircleCheck', 2, 1, 1, NOW(), NOW()),
+  (87, '日志归档', 'audit-retention', 2, 23, '/audit/retention', 'audit/Retention', 'Files', 4, 1, 1, NOW(), NOW()),
+  (88, '安全事件', 'security-events', 2, 23, '/audit/security-events', 'audit/SecurityEvents', 'Warning', 5, 1, 1, NOW(), NOW()),
+  (89, '活动时间线', 'audit-timeline', 2, 23, '/audit/timeline', 'audit/Timeline', 'Clock', 6, 1, 1, NOW(), NOW()),
 
   -- ========== 插件管理子菜单 (parent_id=30) ==========
   (32, '插件列表', 'plugin-list', 2, 30, '/plugin/list', 'plugin/PluginList', 'Grid', 1, 1, 1, NOW(), NOW()),
@@ -859,7 +1146,8 @@ VALUES
   -- ========== 任务中心子菜单 (parent_id=61) ==========
   (82, '任务模板', 'task_templates', 2, 61, '/task/templates', '', 'Document', 1, 1, 1, NOW(), NOW()),
   (83, '执行任务', 'task_execute', 2, 61, '/task/execute', '', 'Tools', 2, 1, 1, NOW(), NOW()),
-  (84, '文件分发', 'task_file_distribution', 2, 61, '/task/file-distribution', '', 'Files', 3, 1, 1, NOW(), NOW());
+  (84, '文件分发', 'task_file_distribution', 2, 61, '/task/file-distribution', '', 'Files', 3, 1, 1, NOW(), NOW()),
+  (90, '定时任务', 'task_schedules', 2, 61, '/task/schedules', '', 'Timer', 4, 1, 1, NOW(), NOW());
 
 -- 为管理员角色分配所有菜单权限
 INSERT INTO `sys_role_menu` (`role_id`, `menu_id`)
@@ -867,7 +1155,7 @@ VALUES
   (1, 1), (1, 2), (1, 3), (1, 5), (1, 10), (1, 11), (1, 12), (1, 13), (1, 15), (1, 16), (1, 17), (1, 19),
   (1, 23), (1, 24), (1, 25), (1, 27), (1, 29), (1, 30), (1, 32), (1, 33), (1, 34), (1, 36),
   (1, 42), (1, 61), (1, 65), (1, 69), (1, 70), (1, 71), (1, 72), (1, 73), (1, 74), (1, 75), (1, 76), (1, 77),
-  (1, 78), (1, 79), (1, 80), (1, 81), (1, 82), (1, 83), (1, 84), (1, 85), (1, 86);
+  (1, 78), (1, 79), (1, 80), (1, 81), (1, 82), (1, 83), (1, 84), (1, 85), (1, 86), (1, 87), (1, 88), (1, 89), (1, 90);
 
 -- 为普通用户角色分配基础菜单权限
 INSERT INTO `sys_role_menu` (`role_id`, `menu_id`)
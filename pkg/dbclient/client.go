@@ -0,0 +1,80 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package dbclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 数据库类型
+const (
+	TypeMySQL      = "mysql"
+	TypePostgreSQL = "postgresql"
+	TypeRedis      = "redis"
+)
+
+// Config 数据库连接配置
+type Config struct {
+	Type     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Database string // MySQL/PostgreSQL 为库名，Redis 为 DB 序号
+	Timeout  time.Duration
+}
+
+// Result 语句执行结果
+type Result struct {
+	Columns      []string        `json:"columns"`
+	Rows         [][]interface{} `json:"rows"`
+	RowsAffected int64           `json:"rowsAffected"`
+	Truncated    bool            `json:"truncated"` // 结果超过行数限制被截断
+}
+
+// Client 数据库客户端
+type Client interface {
+	// Ping 测试连接
+	Ping(ctx context.Context) error
+	// Query 以只读方式执行查询，最多返回 limit 行
+	Query(ctx context.Context, statement string, limit int) (*Result, error)
+	// Exec 执行写入语句
+	Exec(ctx context.Context, statement string) (*Result, error)
+	// Close 关闭连接
+	Close() error
+}
+
+// NewClient 创建数据库客户端
+func NewClient(cfg Config) (Client, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	switch cfg.Type {
+	case TypeMySQL, TypePostgreSQL:
+		return newSQLClient(cfg)
+	case TypeRedis:
+		return newRedisClient(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.Type)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient Redis 客户端
+type redisClient struct {
+	rdb *redis.Client
+}
+
+func newRedisClient(cfg Config) (*redisClient, error) {
+	dbIndex := 0
+	if cfg.Database != "" {
+		index, err := strconv.Atoi(cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("无效的Redis DB序号: %s", cfg.Database)
+		}
+		dbIndex = index
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           dbIndex,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+		PoolSize:     1,
+	})
+	return &redisClient{rdb: rdb}, nil
+}
+
+// Ping 测试连接
+func (c *redisClient) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+// Query 执行读命令
+func (c *redisClient) Query(ctx context.Context, statement string, limit int) (*Result, error) {
+	return c.do(ctx, statement, limit)
+}
+
+// Exec 执行写命令
+func (c *redisClient) Exec(ctx context.Context, statement string) (*Result, error) {
+	return c.do(ctx, statement, 0)
+}
+
+func (c *redisClient) do(ctx context.Context, statement string, limit int) (*Result, error) {
+	args, err := SplitCommand(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("命令不能为空")
+	}
+
+	cmdArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		cmdArgs[i] = arg
+	}
+
+	value, err := c.rdb.Do(ctx, cmdArgs...).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return redisResult(value, limit), nil
+}
+
+// redisResult 将Redis返回值转换为表格形式
+func redisResult(value interface{}, limit int) *Result {
+	result := &Result{Rows: make([][]interface{}, 0)}
+
+	switch v := value.(type) {
+	case []interface{}:
+		result.Columns = []string{"#", "value"}
+		for i, item := range v {
+			if limit > 0 && i >= limit {
+				result.Truncated = true
+				break
+			}
+			result.Rows = append(result.Rows, []interface{}{i + 1, formatRedisValue(item)})
+		}
+	case map[interface{}]interface{}:
+		result.Columns = []string{"field", "value"}
+		keys := make([]string, 0, len(v))
+		values := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			key := fmt.Sprint(k)
+			keys = append(keys, key)
+			values[key] = item
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if limit > 0 && i >= limit {
+				result.Truncated = true
+				break
+			}
+			result.Rows = append(result.Rows, []interface{}{key, formatRedisValue(values[key])})
+		}
+	default:
+		result.Columns = []string{"value"}
+		result.Rows = append(result.Rows, []interface{}{formatRedisValue(v)})
+	}
+
+	result.RowsAffected = int64(len(result.Rows))
+	return result
+}
+
+func formatRedisValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string, int64, float64, bool:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(formatRedisValue(item)))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Close 关闭连接
+func (c *redisClient) Close() error {
+	return c.rdb.Close()
+}
+
+// SplitCommand 按空白拆分Redis命令，支持单引号、双引号和反斜杠转义
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("引号未闭合")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package dbclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// sqlClient MySQL/PostgreSQL 客户端
+type sqlClient struct {
+	db *sql.DB
+}
+
+func newSQLClient(cfg Config) (*sqlClient, error) {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	var driverName, dsn string
+	if cfg.Type == TypeMySQL {
+		mc := mysql.NewConfig()
+		mc.User = cfg.Username
+		mc.Passwd = cfg.Password
+		mc.Net = "tcp"
+		mc.Addr = addr
+		mc.DBName = cfg.Database
+		mc.Timeout = cfg.Timeout
+		mc.ParseTime = true
+		mc.Loc = time.Local
+		driverName, dsn = "mysql", mc.FormatDSN()
+	} else {
+		u := &url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(cfg.Username, cfg.Password),
+			Host:   addr,
+			Path:   "/" + cfg.Database,
+		}
+		q := u.Query()
+		q.Set("connect_timeout", strconv.Itoa(int(cfg.Timeout.Seconds())))
+		q.Set("sslmode", "prefer")
+		u.RawQuery = q.Encode()
+		driverName, dsn = "pgx", u.String()
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库连接失败: %w", err)
+	}
+	// 控制台每次请求独立连接，无需连接池
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	return &sqlClient{db: db}, nil
+}
+
+// Ping 测试连接
+func (c *sqlClient) Ping(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Query 在只读事务中执行查询
+func (c *sqlClient) Query(ctx context.Context, statement string, limit int) (*Result, error) {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Columns: columns, Rows: make([][]interface{}, 0)}
+	for rows.Next() {
+		if limit > 0 && len(result.Rows) >= limit {
+			result.Truncated = true
+			break
+		}
+
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result.RowsAffected = int64(len(result.Rows))
+	return result, nil
+}
+
+// Exec 执行写入语句
+func (c *sqlClient) Exec(ctx context.Context, statement string) (*Result, error) {
+	res, err := c.db.ExecContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	affected, _ := res.RowsAffected()
+	return &Result{Columns: []string{}, Rows: [][]interface{}{}, RowsAffected: affected}, nil
+}
+
+// Close 关闭连接
+func (c *sqlClient) Close() error {
+	return c.db.Close()
+}
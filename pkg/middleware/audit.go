@@ -34,8 +34,30 @@ import (
 	"gorm.io/gorm"
 )
 
-// AuditLogOperation 操作审计日志中间件
-func AuditLogOperation(db *gorm.DB) gin.HandlerFunc {
+// skipRedactionKey 路由声明不对操作日志参数脱敏时设置的上下文键
+const skipRedactionKey = "audit_skip_redaction"
+
+// SkipAuditRedaction 路由级中间件，声明该路由的请求参数原样记录到操作日志
+// 仅用于确认不含敏感信息、且脱敏会影响排查的接口
+func SkipAuditRedaction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(skipRedactionKey, true)
+		c.Next()
+	}
+}
+
+// maxCapturedResponse 审计元数据引用响应字段时最多缓存的响应体大小
+const maxCapturedResponse = 64 * 1024
+
+// AuditLogOperation 操作审计日志中间件，请求参数和错误信息经 redactor 脱敏后保存
+// 模块、操作、描述和资源取自 routes 中登记的接口审计元数据，未登记的接口按路径推断
+func AuditLogOperation(db *gorm.DB, redactor *audit.Redactor, routes *audit.RouteAuditRegistry) gin.HandlerFunc {
+	if redactor == nil {
+		redactor = audit.NewDefaultRedactor()
+	}
+	if routes == nil {
+		routes = audit.NewRouteAuditRegistry()
+	}
 	return func(c *gin.Context) {
 		// 开始时间
 		start := time.Now()
@@ -53,6 +75,10 @@ func AuditLogOperation(db *gorm.DB) gin.HandlerFunc {
 			ResponseWriter: c.Writer,
 			status:         200,
 		}
+		meta, registered := routes.Lookup(c.Request.Method, c.FullPath())
+		if registered && meta.NeedsResponse() {
+			writer.body = &bytes.Buffer{}
+		}
 		c.Writer = writer
 
 		// 处理请求
@@ -70,32 +96,48 @@ func AuditLogOperation(db *gorm.DB) gin.HandlerFunc {
 		// 获取用户信息
 		userID, username, realName := getUserInfo(c)
 
-		// 获取模块和操作类型
-		module, action, description := getOperationInfo(path, c.Request.Method)
-
-		// 获取请求参数
-		params := getRequestParams(c, bodyBytes)
+		// 获取请求参数，未声明跳过时脱敏
+		redact := !c.GetBool(skipRedactionKey) && !redactor.SkipRoute(c.Request.Method, c.FullPath())
+		params := getRequestParams(c, bodyBytes, redactor, redact)
+
+		// 获取模块、操作类型和资源
+		var module, action, description, resourceID string
+		if registered {
+			module, action = meta.Module, meta.Action
+			description, resourceID = meta.Render(routeValues(c, params, writer.body))
+			if description == "" {
+				description = c.Request.Method + " " + path
+			}
+		} else {
+			module, action, description = getOperationInfo(path, c.Request.Method)
+		}
 
 		// 构建操作日志
 		log := &audit.SysOperationLog{
-			UserID:      userID,
-			Username:    username,
-			RealName:    realName,
-			Module:      module,
-			Action:      action,
-			Description: description,
-			Method:      c.Request.Method,
-			Path:        path,
-			Params:      params,
-			Status:      writer.status,
-			CostTime:    costTime,
-			IP:          c.ClientIP(),
-			UserAgent:   c.Request.UserAgent(),
+			UserID:       userID,
+			Username:     username,
+			RealName:     realName,
+			Module:       module,
+			Action:       action,
+			Description:  description,
+			Method:       c.Request.Method,
+			Path:         path,
+			Params:       params,
+			Route:        c.FullPath(),
+			ResourceType: meta.ResourceType,
+			ResourceID:   resourceID,
+			Status:       writer.status,
+			CostTime:     costTime,
+			IP:           c.ClientIP(),
+			UserAgent:    c.Request.UserAgent(),
 		}
 
 		// 如果有错误，记录错误信息
 		if len(c.Errors) > 0 {
 			log.ErrorMsg = c.Errors.String()
+			if redact {
+				log.ErrorMsg = redactor.RedactText(log.ErrorMsg)
+			}
 		}
 
 		// 异步保存日志
@@ -111,6 +153,32 @@ func AuditLogOperation(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
+// routeValues 审计元数据的取值来源，请求体使用脱敏后的参数
+func routeValues(c *gin.Context, params string, resp *bytes.Buffer) audit.RouteValues {
+	values := audit.RouteValues{
+		Path:  c.Param,
+		Query: c.Query,
+	}
+	if c.Request.Method != "GET" && params != "" {
+		values.Body = decodeJSON([]byte(params))
+	}
+	if resp != nil && resp.Len() < maxCapturedResponse {
+		values.Resp = decodeJSON(resp.Bytes())
+	}
+	return values
+}
+
+// decodeJSON 解析 JSON，数字保留原始文本，失败时返回 nil
+func decodeJSON(data []byte) interface{} {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+	return v
+}
+
 // shouldSkipLog 判断是否跳过记录日志
 func shouldSkipLog(path string) bool {
 	// 跳过健康检查、静态资源等
@@ -403,50 +471,38 @@ func getAssetOperationDescription(path string, method string) string {
 }
 
 // getRequestParams 获取请求参数
-func getRequestParams(c *gin.Context, bodyBytes []byte) string {
+func getRequestParams(c *gin.Context, bodyBytes []byte, redactor *audit.Redactor, redact bool) string {
 	// 对于GET请求，记录查询参数
 	if c.Request.Method == "GET" {
-		return c.Request.URL.RawQuery
+		if !redact {
+			return c.Request.URL.RawQuery
+		}
+		return redactor.RedactQuery(c.Request.URL.RawQuery)
 	}
 
-	// 对于POST/PUT/DELETE请求，记录请求体（但过滤敏感信息）
+	// 对于POST/PUT/DELETE请求，记录请求体
 	if len(bodyBytes) > 0 {
-		var params map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &params); err == nil {
-			// 过滤敏感字段
-			filterSensitiveFields(params)
-			if filtered, err := json.Marshal(params); err == nil {
-				return string(filtered)
-			}
+		if !redact {
+			return string(bodyBytes)
 		}
-		return string(bodyBytes)
+		return redactor.RedactBody(c.GetHeader("Content-Type"), bodyBytes)
 	}
 
 	return ""
 }
 
-// filterSensitiveFields 过滤敏感字段
-func filterSensitiveFields(params map[string]interface{}) {
-	sensitiveFields := []string{"password", "pwd", "secret", "token", "key"}
-
-	for _, field := range sensitiveFields {
-		if _, exists := params[field]; exists {
-			params[field] = "******"
-		}
-	}
-
-	// 递归处理嵌套对象
-	for _, v := range params {
-		if nested, ok := v.(map[string]interface{}); ok {
-			filterSensitiveFields(nested)
-		}
-	}
-}
-
 // responseWriter 响应写入器包装器，用于捕获状态码
+// body 非空时同时缓存响应体，超过 maxCapturedResponse 后不再缓存
 type responseWriter struct {
 	gin.ResponseWriter
 	status int
+	body   *bytes.Buffer
+}
+
+func (w *responseWriter) capture(data []byte) {
+	if w.body != nil && w.body.Len() < maxCapturedResponse {
+		w.body.Write(data)
+	}
 }
 
 func (w *responseWriter) WriteHeader(code int) {
@@ -456,10 +512,12 @@ func (w *responseWriter) WriteHeader(code int) {
 
 // Write implements the io.Writer interface
 func (w *responseWriter) Write(data []byte) (int, error) {
+	w.capture(data)
 	return w.ResponseWriter.Write(data)
 }
 
 // WriteString implements the gin.ResponseWriter interface
 func (w *responseWriter) WriteString(str string) (int, error) {
+	w.capture([]byte(str))
 	return w.ResponseWriter.WriteString(str)
 }
@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
@@ -152,6 +153,15 @@ func (c *Client) TestConnection() error {
 	return err
 }
 
+// Dial 通过SSH连接打开到远端地址的direct-tcpip通道（用于端口转发）
+func (c *Client) Dial(network, addr string) (net.Conn, error) {
+	conn, err := c.client.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("建立转发通道失败: %w", err)
+	}
+	return conn, nil
+}
+
 // NewSFTPClient 创建SFTP客户端
 func (c *Client) NewSFTPClient() (*sftp.Client, error) {
 	return sftp.NewClient(c.client)
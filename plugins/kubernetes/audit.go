@@ -0,0 +1,161 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kubernetes
+
+import "github.com/ydcloud-dy/opshub/internal/plugin"
+
+// auditModule 操作日志中的模块名称
+const auditModule = "容器管理"
+
+// auditResourceKind 通过 YAML 管理的 Kubernetes 资源
+type auditResourceKind struct {
+	kind       string // 路由中的资源名，如 configmaps
+	label      string // 描述中展示的资源名称
+	namespaced bool
+	create     string // 创建接口相对 namespace 的路径，为空表示无创建接口
+}
+
+// routes 生成资源创建、更新 YAML 和删除接口的审计元数据
+func (k auditResourceKind) routes() []plugin.AuditRouteConfig {
+	base := "/kubernetes/resources/" + k.kind
+	target := "{path.name}"
+	if k.namespaced {
+		base += "/:namespace"
+		target = "{path.namespace}/{path.name}"
+	}
+	resourceType := "k8s_" + k.kind
+	var routes []plugin.AuditRouteConfig
+	if k.create != "" {
+		routes = append(routes, plugin.AuditRouteConfig{
+			Method: "POST", Path: base + k.create, Module: auditModule, Action: "创建",
+			Description:  "创建 " + k.label + " " + target + " (集群 #{query.clusterId})",
+			ResourceType: resourceType, ResourceID: "path.name",
+		})
+	}
+	return append(routes,
+		plugin.AuditRouteConfig{
+			Method: "PUT", Path: base + "/:name/yaml", Module: auditModule, Action: "更新",
+			Description:  "更新 " + k.label + " " + target + " YAML (集群 #{query.clusterId})",
+			ResourceType: resourceType, ResourceID: "path.name",
+		},
+		plugin.AuditRouteConfig{
+			Method: "DELETE", Path: base + "/:name", Module: auditModule, Action: "删除",
+			Description:  "删除 " + k.label + " " + target + " (集群 #{query.clusterId})",
+			ResourceType: resourceType, ResourceID: "path.name",
+		},
+	)
+}
+
+// auditResourceKinds 通过通用 YAML 接口管理的资源
+var auditResourceKinds = []auditResourceKind{
+	{kind: "serviceaccounts", label: "ServiceAccount", namespaced: true, create: "/yaml"},
+	{kind: "roles", label: "Role", namespaced: true, create: "/yaml"},
+	{kind: "rolebindings", label: "RoleBinding", namespaced: true, create: "/yaml"},
+	{kind: "clusterroles", label: "ClusterRole", create: "/yaml"},
+	{kind: "clusterrolebindings", label: "ClusterRoleBinding", create: "/yaml"},
+	{kind: "workloads", label: "工作负载", namespaced: true},
+	{kind: "services", label: "Service", namespaced: true, create: "/:name"},
+	{kind: "ingresses", label: "Ingress", namespaced: true, create: "/:name"},
+	{kind: "endpoints", label: "Endpoints", namespaced: true, create: "/yaml"},
+	{kind: "networkpolicies", label: "NetworkPolicy", namespaced: true, create: "/:name"},
+	{kind: "configmaps", label: "ConfigMap", namespaced: true, create: "/yaml"},
+	{kind: "secrets", label: "Secret", namespaced: true, create: "/yaml"},
+	{kind: "persistentvolumeclaims", label: "PVC", namespaced: true, create: "/yaml"},
+	{kind: "persistentvolumes", label: "PV", create: "/yaml"},
+	{kind: "storageclasses", label: "StorageClass", create: "/yaml"},
+	{kind: "resourcequotas", label: "ResourceQuota", namespaced: true, create: "/yaml"},
+	{kind: "limitranges", label: "LimitRange", namespaced: true, create: "/yaml"},
+	{kind: "horizontalpodautoscalers", label: "HPA", namespaced: true, create: "/yaml"},
+	{kind: "poddisruptionbudgets", label: "PDB", namespaced: true, create: "/yaml"},
+}
+
+// GetAuditRoutes 获取插件接口的审计元数据
+func (p *Plugin) GetAuditRoutes() []plugin.AuditRouteConfig {
+	routes := []plugin.AuditRouteConfig{
+		// 集群
+		{Method: "POST", Path: "/kubernetes/clusters", Module: auditModule, Action: "创建", Description: "创建集群 {body.name}", ResourceType: "k8s_cluster", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/kubernetes/clusters/:id", Module: auditModule, Action: "更新", Description: "更新集群 #{path.id} {body.name}", ResourceType: "k8s_cluster", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/kubernetes/clusters/:id", Module: auditModule, Action: "删除", Description: "删除集群 #{path.id}", ResourceType: "k8s_cluster", ResourceID: "path.id"},
+		{Method: "POST", Path: "/kubernetes/clusters/:id/test", Module: auditModule, Action: "测试", Description: "测试集群 #{path.id} 连接", ResourceType: "k8s_cluster", ResourceID: "path.id"},
+		{Method: "POST", Path: "/kubernetes/clusters/:id/sync", Module: auditModule, Action: "同步", Description: "同步集群 #{path.id} 状态", ResourceType: "k8s_cluster", ResourceID: "path.id"},
+		{Method: "POST", Path: "/kubernetes/clusters/sync-all", Module: auditModule, Action: "同步", Description: "同步全部集群状态", ResourceType: "k8s_cluster"},
+		{Method: "POST", Path: "/kubernetes/clusters/:id/roles", Module: auditModule, Action: "创建", Description: "在集群 #{path.id} 创建角色 {body.namespace} {body.name}", ResourceType: "k8s_cluster", ResourceID: "path.id"},
+
+		// 凭据
+		{Method: "POST", Path: "/kubernetes/clusters/kubeconfig/sa", Module: auditModule, Action: "导出", Description: "获取集群 #{body.clusterId} ServiceAccount {body.serviceAccount} 的 kubeconfig", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+		{Method: "POST", Path: "/kubernetes/clusters/kubeconfig", Module: auditModule, Action: "授权", Description: "生成集群 #{body.clusterId} kubeconfig", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+		{Method: "DELETE", Path: "/kubernetes/clusters/kubeconfig", Module: auditModule, Action: "撤销", Description: "撤销集群 #{body.clusterId} ServiceAccount {body.serviceAccount} 的 kubeconfig", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+		{Method: "DELETE", Path: "/kubernetes/clusters/kubeconfig/revoke", Module: auditModule, Action: "撤销", Description: "彻底撤销集群 #{body.clusterId} 凭据", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+		{Method: "POST", Path: "/kubernetes/role-bindings/bind", Module: auditModule, Action: "授权", Description: "为用户 #{body.userId} 绑定集群 #{body.clusterId} 角色 {body.roleType} {body.roleNamespace} {body.roleName}", ResourceType: "user", ResourceID: "body.userId"},
+		{Method: "DELETE", Path: "/kubernetes/role-bindings/unbind", Module: auditModule, Action: "撤销", Description: "解除用户 #{body.userId} 的集群 #{body.clusterId} 角色 {body.roleNamespace} {body.roleName}", ResourceType: "user", ResourceID: "body.userId"},
+		{Method: "POST", Path: "/kubernetes/roles/create-defaults", Module: auditModule, Action: "创建", Description: "创建默认集群角色 (集群 #{query.clusterId}{body.clusterId})", ResourceType: "k8s_cluster", ResourceID: "query.clusterId|body.clusterId"},
+		{Method: "POST", Path: "/kubernetes/roles/create-defaults-namespace", Module: auditModule, Action: "创建", Description: "创建默认命名空间角色 {body.namespace} (集群 #{query.clusterId}{body.clusterId})", ResourceType: "k8s_cluster", ResourceID: "query.clusterId|body.clusterId"},
+		{Method: "DELETE", Path: "/kubernetes/roles/:namespace/:name", Module: auditModule, Action: "删除", Description: "删除角色 {path.namespace}/{path.name} (集群 #{query.clusterId})", ResourceType: "k8s_roles", ResourceID: "path.name"},
+
+		// 节点
+		{Method: "PUT", Path: "/kubernetes/resources/nodes/:nodeName/yaml", Module: auditModule, Action: "更新", Description: "更新节点 {path.nodeName} YAML (集群 #{query.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/drain", Module: auditModule, Action: "执行", Description: "驱逐节点 {path.nodeName} (集群 #{query.clusterId}{body.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/cordon", Module: auditModule, Action: "执行", Description: "禁止调度节点 {path.nodeName} (集群 #{query.clusterId}{body.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/uncordon", Module: auditModule, Action: "执行", Description: "恢复调度节点 {path.nodeName} (集群 #{query.clusterId}{body.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "DELETE", Path: "/kubernetes/resources/nodes/:nodeName", Module: auditModule, Action: "删除", Description: "删除节点 {path.nodeName} (集群 #{query.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/drain", Module: auditModule, Action: "执行", Description: "批量驱逐节点 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/cordon", Module: auditModule, Action: "执行", Description: "批量禁止调度节点 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/uncordon", Module: auditModule, Action: "执行", Description: "批量恢复调度节点 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/delete", Module: auditModule, Action: "删除", Description: "批量删除节点 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/labels", Module: auditModule, Action: "更新", Description: "批量{body.operation}节点标签 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+		{Method: "POST", Path: "/kubernetes/resources/nodes/batch/taints", Module: auditModule, Action: "更新", Description: "批量{body.operation}节点污点 {body.nodeNames} (集群 #{body.clusterId})", ResourceType: "k8s_node"},
+
+		// 命名空间
+		{Method: "POST", Path: "/kubernetes/resources/namespaces", Module: auditModule, Action: "创建", Description: "创建命名空间 {body.name} (集群 #{query.clusterId})", ResourceType: "k8s_namespaces", ResourceID: "body.name"},
+		{Method: "PUT", Path: "/kubernetes/resources/namespaces/:namespaceName/yaml", Module: auditModule, Action: "更新", Description: "更新命名空间 {path.namespaceName} YAML (集群 #{query.clusterId})", ResourceType: "k8s_namespaces", ResourceID: "path.namespaceName"},
+		{Method: "DELETE", Path: "/kubernetes/resources/namespaces/:namespaceName", Module: auditModule, Action: "删除", Description: "删除命名空间 {path.namespaceName} (集群 #{query.clusterId})", ResourceType: "k8s_namespaces", ResourceID: "path.namespaceName"},
+
+		// 工作负载
+		{Method: "POST", Path: "/kubernetes/workloads/update", Module: auditModule, Action: "更新", Description: "更新{query.type} {query.namespace}/{query.name} (集群 {query.cluster})", ResourceType: "k8s_workloads", ResourceID: "query.name"},
+		{Method: "POST", Path: "/kubernetes/workloads/pause", Module: auditModule, Action: "更新", Description: "暂停/恢复{body.type} {body.namespace}/{body.name} paused={body.paused} (集群 #{body.clusterId})", ResourceType: "k8s_workloads", ResourceID: "body.name"},
+		{Method: "POST", Path: "/kubernetes/workloads/rollback", Module: auditModule, Action: "部署", Description: "回滚{body.type} {body.namespace}/{body.name} 到版本 {body.revision} (集群 #{body.clusterId})", ResourceType: "k8s_workloads", ResourceID: "body.name"},
+		{Method: "POST", Path: "/kubernetes/resources/workloads/create", Module: auditModule, Action: "创建", Description: "通过 YAML 创建工作负载 (集群 #{body.clusterId})", ResourceType: "k8s_workloads"},
+		{Method: "POST", Path: "/kubernetes/resources/workloads/batch/delete", Module: auditModule, Action: "删除", Description: "批量删除工作负载 {body.workloads} (集群 #{body.clusterId})", ResourceType: "k8s_workloads"},
+		{Method: "POST", Path: "/kubernetes/resources/workloads/batch/restart", Module: auditModule, Action: "部署", Description: "批量重启工作负载 {body.workloads} (集群 #{body.clusterId})", ResourceType: "k8s_workloads"},
+		{Method: "POST", Path: "/kubernetes/resources/workloads/batch/pause", Module: auditModule, Action: "更新", Description: "批量暂停工作负载 {body.workloads} (集群 #{body.clusterId})", ResourceType: "k8s_workloads"},
+		{Method: "POST", Path: "/kubernetes/resources/workloads/batch/resume", Module: auditModule, Action: "更新", Description: "批量恢复工作负载 {body.workloads} (集群 #{body.clusterId})", ResourceType: "k8s_workloads"},
+
+		// 终端与文件
+		{Method: "GET", Path: "/kubernetes/shell/nodes/:nodeName", Module: auditModule, Action: "连接", Description: "打开节点 {path.nodeName} 终端 (集群 #{query.clusterId})", ResourceType: "k8s_node", ResourceID: "path.nodeName"},
+		{Method: "GET", Path: "/kubernetes/shell/pods", Module: auditModule, Action: "连接", Description: "打开 Pod {query.namespace}/{query.podName} 容器 {query.container} 终端 (集群 #{query.clusterId})", ResourceType: "k8s_pods", ResourceID: "query.podName"},
+		{Method: "POST", Path: "/kubernetes/pods/files/upload", Module: auditModule, Action: "上传", Description: "上传文件 {body.files} 到 Pod {body.form.namespace}/{body.form.podName}:{body.form.path}", ResourceType: "k8s_pods", ResourceID: "body.form.podName"},
+		{Method: "GET", Path: "/kubernetes/pods/files/download", Module: auditModule, Action: "导出", Description: "下载 Pod {query.namespace}/{query.podName} 文件 {query.path}", ResourceType: "k8s_pods", ResourceID: "query.podName"},
+		{Method: "POST", Path: "/kubernetes/cloudtty/deploy", Module: auditModule, Action: "部署", Description: "部署 CloudTTY (集群 #{body.clusterId})", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+		{Method: "POST", Path: "/kubernetes/cloudtty/service", Module: auditModule, Action: "创建", Description: "创建 CloudTTY 服务 (集群 #{body.clusterId})", ResourceType: "k8s_cluster", ResourceID: "body.clusterId"},
+
+		// Arthas 诊断
+		{Method: "POST", Path: "/kubernetes/arthas/install", Module: auditModule, Action: "部署", Description: "在 Pod {body.namespace}/{body.pod} 安装 Arthas (集群 #{body.clusterId})", ResourceType: "k8s_pods", ResourceID: "body.pod"},
+		{Method: "POST", Path: "/kubernetes/arthas/command", Module: auditModule, Action: "执行", Description: "在 Pod {body.namespace}/{body.pod} 执行 Arthas 命令 {body.command} (集群 #{body.clusterId})", ResourceType: "k8s_pods", ResourceID: "body.pod"},
+
+		// 集群巡检
+		{Method: "POST", Path: "/kubernetes/inspection/start", Module: auditModule, Action: "执行", Description: "发起集群巡检 {body.clusterIds}", ResourceType: "k8s_inspection", ResourceID: "resp.data.inspectionId"},
+		{Method: "DELETE", Path: "/kubernetes/inspection/:inspectionId", Module: auditModule, Action: "删除", Description: "删除巡检记录 #{path.inspectionId}", ResourceType: "k8s_inspection", ResourceID: "path.inspectionId"},
+		{Method: "GET", Path: "/kubernetes/inspection/export/:inspectionId", Module: auditModule, Action: "导出", Description: "导出巡检报告 #{path.inspectionId}", ResourceType: "k8s_inspection", ResourceID: "path.inspectionId"},
+	}
+	for _, kind := range auditResourceKinds {
+		routes = append(routes, kind.routes()...)
+	}
+	return routes
+}
@@ -0,0 +1,486 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"github.com/ydcloud-dy/opshub/internal/plugin"
+)
+
+// GetPermissions 获取插件接口权限，查询接口对应各菜单的查看权限
+func (p *Plugin) GetPermissions() []plugin.PermissionConfig {
+	return []plugin.PermissionConfig{
+		{
+			Code:     "kubernetes:cluster:view",
+			Name:     "查看集群",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/clusters"},
+				{Method: "GET", Path: "/kubernetes/clusters/:id"},
+				{Method: "GET", Path: "/kubernetes/resources/api-groups"},
+				{Method: "GET", Path: "/kubernetes/resources/api-resources"},
+				{Method: "GET", Path: "/kubernetes/resources/stats"},
+				{Method: "GET", Path: "/kubernetes/resources/components"},
+				{Method: "GET", Path: "/kubernetes/resources/events"},
+			},
+		},
+		{
+			Code:     "kubernetes:cluster:create",
+			Name:     "新增集群",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/clusters"},
+			},
+		},
+		{
+			Code:     "kubernetes:cluster:update",
+			Name:     "编辑集群",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/kubernetes/clusters/:id"},
+				{Method: "POST", Path: "/kubernetes/clusters/:id/test"},
+				{Method: "GET", Path: "/kubernetes/clusters/:id/config"},
+				{Method: "POST", Path: "/kubernetes/clusters/:id/sync"},
+				{Method: "POST", Path: "/kubernetes/clusters/sync-all"},
+			},
+		},
+		{
+			Code:     "kubernetes:cluster:delete",
+			Name:     "删除集群",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/clusters/:id"},
+			},
+		},
+		{
+			Code:     "kubernetes:kubeconfig:apply",
+			Name:     "申请KubeConfig",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/clusters/kubeconfig"},
+				{Method: "DELETE", Path: "/kubernetes/clusters/kubeconfig"},
+				{Method: "GET", Path: "/kubernetes/clusters/kubeconfig/existing"},
+			},
+		},
+		{
+			Code:     "kubernetes:cluster:authorize",
+			Name:     "集群授权",
+			MenuCode: "kubernetes_clusters",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/clusters/kubeconfig/sa"},
+				{Method: "DELETE", Path: "/kubernetes/clusters/kubeconfig/revoke"},
+				{Method: "GET", Path: "/kubernetes/roles/cluster"},
+				{Method: "POST", Path: "/kubernetes/roles/create-defaults"},
+				{Method: "POST", Path: "/kubernetes/roles/create-defaults-namespace"},
+				{Method: "GET", Path: "/kubernetes/roles/namespaces"},
+				{Method: "GET", Path: "/kubernetes/roles/namespace"},
+				{Method: "GET", Path: "/kubernetes/roles/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/roles/:namespace/:name"},
+				{Method: "POST", Path: "/kubernetes/clusters/:id/roles"},
+				{Method: "POST", Path: "/kubernetes/role-bindings/bind"},
+				{Method: "DELETE", Path: "/kubernetes/role-bindings/unbind"},
+				{Method: "GET", Path: "/kubernetes/role-bindings/users"},
+				{Method: "GET", Path: "/kubernetes/role-bindings/available-users"},
+				{Method: "GET", Path: "/kubernetes/role-bindings/user-roles"},
+				{Method: "GET", Path: "/kubernetes/role-bindings/user-bindings"},
+				{Method: "GET", Path: "/kubernetes/role-bindings/credential-users"},
+			},
+		},
+		{
+			Code:     "kubernetes:node:view",
+			Name:     "查看节点",
+			MenuCode: "kubernetes_nodes",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/nodes"},
+				{Method: "GET", Path: "/kubernetes/resources/nodes/:nodeName/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/nodes/:nodeName/metrics"},
+			},
+		},
+		{
+			Code:     "kubernetes:node:update",
+			Name:     "管理节点",
+			MenuCode: "kubernetes_nodes",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/kubernetes/resources/nodes/:nodeName/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/drain"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/cordon"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/:nodeName/uncordon"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/drain"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/cordon"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/uncordon"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/labels"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/taints"},
+			},
+		},
+		{
+			Code:     "kubernetes:node:delete",
+			Name:     "删除节点",
+			MenuCode: "kubernetes_nodes",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/nodes/:nodeName"},
+				{Method: "POST", Path: "/kubernetes/resources/nodes/batch/delete"},
+			},
+		},
+		{
+			Code:     "kubernetes:node:terminal",
+			Name:     "节点终端",
+			MenuCode: "kubernetes_nodes",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/shell/nodes/:nodeName"},
+			},
+		},
+		{
+			Code:     "kubernetes:namespace:view",
+			Name:     "查看命名空间",
+			MenuCode: "kubernetes_namespaces",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/namespaces"},
+				{Method: "GET", Path: "/kubernetes/resources/namespaces/:namespaceName/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/resourcequotas"},
+				{Method: "GET", Path: "/kubernetes/resources/resourcequotas/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/limitranges"},
+				{Method: "GET", Path: "/kubernetes/resources/limitranges/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:namespace:update",
+			Name:     "管理命名空间",
+			MenuCode: "kubernetes_namespaces",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/resources/namespaces"},
+				{Method: "PUT", Path: "/kubernetes/resources/namespaces/:namespaceName/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/resourcequotas/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/resourcequotas/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/limitranges/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/limitranges/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:namespace:delete",
+			Name:     "删除命名空间",
+			MenuCode: "kubernetes_namespaces",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/namespaces/:namespaceName"},
+				{Method: "DELETE", Path: "/kubernetes/resources/resourcequotas/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/limitranges/:namespace/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:workload:view",
+			Name:     "查看工作负载",
+			MenuCode: "kubernetes_workloads",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/pods"},
+				{Method: "GET", Path: "/kubernetes/resources/pods/:namespace/:name"},
+				{Method: "GET", Path: "/kubernetes/resources/pods/:namespace/:name/events"},
+				{Method: "GET", Path: "/kubernetes/resources/pods/metrics"},
+				{Method: "GET", Path: "/kubernetes/resources/pods/logs"},
+				{Method: "GET", Path: "/kubernetes/resources/deployments"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name/replicasets"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name/pods"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name/services"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name/ingresses"},
+				{Method: "GET", Path: "/kubernetes/resources/workloads/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/horizontalpodautoscalers"},
+				{Method: "GET", Path: "/kubernetes/resources/horizontalpodautoscalers/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/poddisruptionbudgets"},
+				{Method: "GET", Path: "/kubernetes/resources/poddisruptionbudgets/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:workload:update",
+			Name:     "管理工作负载",
+			MenuCode: "kubernetes_workloads",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/kubernetes/resources/workloads/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/workloads/update"},
+				{Method: "POST", Path: "/kubernetes/workloads/pause"},
+				{Method: "POST", Path: "/kubernetes/workloads/rollback"},
+				{Method: "POST", Path: "/kubernetes/resources/workloads/create"},
+				{Method: "POST", Path: "/kubernetes/resources/workloads/batch/restart"},
+				{Method: "POST", Path: "/kubernetes/resources/workloads/batch/pause"},
+				{Method: "POST", Path: "/kubernetes/resources/workloads/batch/resume"},
+				{Method: "POST", Path: "/kubernetes/resources/horizontalpodautoscalers/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/horizontalpodautoscalers/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/poddisruptionbudgets/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/poddisruptionbudgets/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:workload:delete",
+			Name:     "删除工作负载",
+			MenuCode: "kubernetes_workloads",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/workloads/:namespace/:name"},
+				{Method: "POST", Path: "/kubernetes/resources/workloads/batch/delete"},
+				{Method: "DELETE", Path: "/kubernetes/resources/horizontalpodautoscalers/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/poddisruptionbudgets/:namespace/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:pod:terminal",
+			Name:     "容器终端",
+			MenuCode: "kubernetes_workloads",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/shell/pods"},
+				{Method: "GET", Path: "/kubernetes/cloudtty/status"},
+				{Method: "POST", Path: "/kubernetes/cloudtty/deploy"},
+				{Method: "GET", Path: "/kubernetes/cloudtty/service"},
+				{Method: "POST", Path: "/kubernetes/cloudtty/service"},
+			},
+		},
+		{
+			Code:     "kubernetes:pod:file",
+			Name:     "容器文件",
+			MenuCode: "kubernetes_workloads",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/pods/files"},
+				{Method: "GET", Path: "/kubernetes/pods/files/download"},
+				{Method: "POST", Path: "/kubernetes/pods/files/upload"},
+			},
+		},
+		{
+			Code:     "kubernetes:network:view",
+			Name:     "查看网络",
+			MenuCode: "kubernetes_network",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/services"},
+				{Method: "GET", Path: "/kubernetes/resources/services/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/ingresses"},
+				{Method: "GET", Path: "/kubernetes/resources/ingresses/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/endpoints"},
+				{Method: "GET", Path: "/kubernetes/resources/endpoints/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/endpoints/:namespace/:name"},
+				{Method: "GET", Path: "/kubernetes/resources/networkpolicies"},
+				{Method: "GET", Path: "/kubernetes/resources/networkpolicies/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/network"},
+			},
+		},
+		{
+			Code:     "kubernetes:network:update",
+			Name:     "管理网络",
+			MenuCode: "kubernetes_network",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/kubernetes/resources/services/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/services/:namespace/:name"},
+				{Method: "PUT", Path: "/kubernetes/resources/ingresses/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/ingresses/:namespace/:name"},
+				{Method: "POST", Path: "/kubernetes/resources/endpoints/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/endpoints/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/networkpolicies/:namespace/:name"},
+				{Method: "PUT", Path: "/kubernetes/resources/networkpolicies/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:network:delete",
+			Name:     "删除网络",
+			MenuCode: "kubernetes_network",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/services/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/ingresses/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/endpoints/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/networkpolicies/:namespace/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:config:view",
+			Name:     "查看配置",
+			MenuCode: "kubernetes_config",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/configmaps"},
+				{Method: "GET", Path: "/kubernetes/resources/configmaps/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/secrets"},
+			},
+		},
+		{
+			Code:     "kubernetes:secret:view",
+			Name:     "查看Secret内容",
+			MenuCode: "kubernetes_config",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/secrets/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:config:update",
+			Name:     "管理配置",
+			MenuCode: "kubernetes_config",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/resources/configmaps/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/configmaps/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/secrets/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/secrets/:namespace/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:config:delete",
+			Name:     "删除配置",
+			MenuCode: "kubernetes_config",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/configmaps/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/secrets/:namespace/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:storage:view",
+			Name:     "查看存储",
+			MenuCode: "kubernetes_storage",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/persistentvolumeclaims"},
+				{Method: "GET", Path: "/kubernetes/resources/persistentvolumeclaims/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/persistentvolumes"},
+				{Method: "GET", Path: "/kubernetes/resources/persistentvolumes/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/storageclasses"},
+				{Method: "GET", Path: "/kubernetes/resources/storageclasses/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:storage:update",
+			Name:     "管理存储",
+			MenuCode: "kubernetes_storage",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/kubernetes/resources/persistentvolumeclaims/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/persistentvolumeclaims/:namespace/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/persistentvolumes/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/persistentvolumes/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/storageclasses/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/storageclasses/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:storage:delete",
+			Name:     "删除存储",
+			MenuCode: "kubernetes_storage",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/persistentvolumeclaims/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/persistentvolumes/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/storageclasses/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:access:view",
+			Name:     "查看访问控制",
+			MenuCode: "kubernetes_access",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/resources/serviceaccounts"},
+				{Method: "GET", Path: "/kubernetes/resources/serviceaccounts/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/roles/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/rolebindings/:namespace/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/clusterroles/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/clusterrolebindings/:name/yaml"},
+				{Method: "GET", Path: "/kubernetes/resources/roles"},
+				{Method: "GET", Path: "/kubernetes/resources/rolebindings"},
+				{Method: "GET", Path: "/kubernetes/resources/clusterroles"},
+				{Method: "GET", Path: "/kubernetes/resources/clusterrolebindings"},
+				{Method: "GET", Path: "/kubernetes/resources/podsecuritypolicies"},
+			},
+		},
+		{
+			Code:     "kubernetes:access:update",
+			Name:     "管理访问控制",
+			MenuCode: "kubernetes_access",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/resources/serviceaccounts/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/serviceaccounts/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/roles/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/roles/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/rolebindings/:namespace/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/rolebindings/:namespace/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/clusterroles/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/clusterroles/:name/yaml"},
+				{Method: "POST", Path: "/kubernetes/resources/clusterrolebindings/yaml"},
+				{Method: "PUT", Path: "/kubernetes/resources/clusterrolebindings/:name/yaml"},
+			},
+		},
+		{
+			Code:     "kubernetes:access:delete",
+			Name:     "删除访问控制",
+			MenuCode: "kubernetes_access",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/resources/serviceaccounts/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/roles/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/rolebindings/:namespace/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/clusterroles/:name"},
+				{Method: "DELETE", Path: "/kubernetes/resources/clusterrolebindings/:name"},
+			},
+		},
+		{
+			Code:     "kubernetes:audit:view",
+			Name:     "查看终端审计",
+			MenuCode: "kubernetes_audit",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/terminal/sessions"},
+				{Method: "GET", Path: "/kubernetes/terminal/sessions/:id/play"},
+			},
+		},
+		{
+			Code:     "kubernetes:diagnosis:use",
+			Name:     "应用诊断",
+			MenuCode: "kubernetes_application_diagnosis",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/arthas/java-processes"},
+				{Method: "GET", Path: "/kubernetes/arthas/check"},
+				{Method: "POST", Path: "/kubernetes/arthas/install"},
+				{Method: "POST", Path: "/kubernetes/arthas/command"},
+				{Method: "GET", Path: "/kubernetes/arthas/dashboard"},
+				{Method: "GET", Path: "/kubernetes/arthas/thread"},
+				{Method: "GET", Path: "/kubernetes/arthas/thread/stack"},
+				{Method: "GET", Path: "/kubernetes/arthas/jvm"},
+				{Method: "GET", Path: "/kubernetes/arthas/sysenv"},
+				{Method: "GET", Path: "/kubernetes/arthas/sysprop"},
+				{Method: "GET", Path: "/kubernetes/arthas/perfcounter"},
+				{Method: "GET", Path: "/kubernetes/arthas/memory"},
+				{Method: "GET", Path: "/kubernetes/arthas/jad"},
+				{Method: "GET", Path: "/kubernetes/arthas/getstatic"},
+				{Method: "GET", Path: "/kubernetes/arthas/sc"},
+				{Method: "GET", Path: "/kubernetes/arthas/sm"},
+				{Method: "GET", Path: "/kubernetes/arthas/profiler"},
+				{Method: "GET", Path: "/kubernetes/arthas/ws"},
+			},
+		},
+		{
+			Code:     "kubernetes:inspection:view",
+			Name:     "查看巡检",
+			MenuCode: "kubernetes_cluster_inspection",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/kubernetes/inspection/progress/:inspectionId"},
+				{Method: "GET", Path: "/kubernetes/inspection/result/:inspectionId"},
+				{Method: "GET", Path: "/kubernetes/inspection/history"},
+				{Method: "GET", Path: "/kubernetes/inspection/export/:inspectionId"},
+			},
+		},
+		{
+			Code:     "kubernetes:inspection:run",
+			Name:     "执行巡检",
+			MenuCode: "kubernetes_cluster_inspection",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/kubernetes/inspection/start"},
+			},
+		},
+		{
+			Code:     "kubernetes:inspection:delete",
+			Name:     "删除巡检",
+			MenuCode: "kubernetes_cluster_inspection",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/kubernetes/inspection/:inspectionId"},
+			},
+		},
+	}
+}
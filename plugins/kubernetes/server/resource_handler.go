@@ -57,6 +57,7 @@ import (
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	"sigs.k8s.io/yaml"
 
+	auditbiz "github.com/ydcloud-dy/opshub/internal/biz/audit"
 	"github.com/ydcloud-dy/opshub/plugins/kubernetes/data/models"
 	"github.com/ydcloud-dy/opshub/plugins/kubernetes/model"
 	"github.com/ydcloud-dy/opshub/plugins/kubernetes/service"
@@ -9063,6 +9064,31 @@ func (h *ResourceHandler) PodShellWebSocket(c *gin.Context) {
 		return
 	}
 
+	// 发布审计事件：打开终端以及其中执行的每条命令
+	clientIP, userAgent := c.ClientIP(), c.Request.UserAgent()
+	publishExecEvent := func(action, command string) {
+		detail := map[string]interface{}{
+			"clusterId": clusterID,
+			"namespace": namespace,
+			"pod":       podName,
+			"container": containerName,
+		}
+		if command != "" {
+			detail["command"] = command
+		}
+		auditbiz.PublishEvent(&auditbiz.AuditEvent{
+			Type:      auditbiz.EventTypeK8sExec,
+			UserID:    currentUserID.(uint),
+			Username:  username,
+			IP:        clientIP,
+			UserAgent: userAgent,
+			Action:    action,
+			Target:    fmt.Sprintf("%s/%s/%s", namespace, podName, containerName),
+			Detail:    detail,
+		})
+	}
+	publishExecEvent("open", "")
+
 	// 创建录制器（录制目录）
 	recordingDir := "./data/terminal-recordings"
 	recorder, err := NewAsciinemaRecorder(recordingDir, 120, 30)
@@ -9092,11 +9118,15 @@ func (h *ResourceHandler) PodShellWebSocket(c *gin.Context) {
 	go func() {
 		defer close(done)
 		defer cancel() // 当 goroutine 结束时取消 context
+		var commands auditbiz.CommandBuffer
 		for {
 			_, message, err := conn.ReadMessage()
 			if err != nil {
 				return
 			}
+			for _, command := range commands.Feed(message) {
+				publishExecEvent("command", command)
+			}
 			wsReader.data <- message
 		}
 	}()
@@ -12834,54 +12864,6 @@ func (h *ResourceHandler) PlayTerminalSession(c *gin.Context) {
 	c.Data(http.StatusOK, "application/json", data)
 }
 
-// DeleteTerminalSession 删除终端会话
-func (h *ResourceHandler) DeleteTerminalSession(c *gin.Context) {
-	sessionID := c.Param("id")
-	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "会话ID不能为空",
-		})
-		return
-	}
-
-	// 获取当前用户ID
-	currentUserID, ok := GetCurrentUserID(c)
-	if !ok {
-		return
-	}
-
-	var session model.TerminalSession
-	err := h.db.Where("id = ? AND user_id = ?", sessionID, currentUserID).First(&session).Error
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    404,
-			"message": "会话不存在",
-		})
-		return
-	}
-
-	// 删除录制文件
-	if session.RecordingPath != "" {
-		os.Remove(session.RecordingPath)
-	}
-
-	// 删除数据库记录
-	err = h.db.Delete(&session).Error
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    500,
-			"message": "删除会话失败: " + err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code":    0,
-		"message": "删除成功",
-	})
-}
-
 // ==================== 访问控制资源 ====================
 
 // ListServiceAccounts 获取ServiceAccount列表
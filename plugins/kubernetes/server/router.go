@@ -251,7 +251,6 @@ func RegisterRoutes(router *gin.RouterGroup, db *gorm.DB) {
 		// 终端审计
 		clusters.GET("/terminal/sessions", resourceHandler.ListTerminalSessions)
 		clusters.GET("/terminal/sessions/:id/play", resourceHandler.PlayTerminalSession)
-		clusters.DELETE("/terminal/sessions/:id", resourceHandler.DeleteTerminalSession)
 
 		// 统计信息
 		clusters.GET("/resources/stats", resourceHandler.GetClusterStats)
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/plugins/kubernetes/model"
+	"gorm.io/gorm"
+)
+
+// GetTimelineSources 获取插件的活动时间线来源
+func (p *Plugin) GetTimelineSources(db *gorm.DB) []audit.TimelineSource {
+	return []audit.TimelineSource{&terminalTimeline{db: db}}
+}
+
+// terminalTimeline Pod 终端会话，资源类型 k8s_cluster、k8s_namespaces、k8s_pods 分别按集群、命名空间和 Pod 匹配
+type terminalTimeline struct {
+	db *gorm.DB
+}
+
+func (s *terminalTimeline) Name() string  { return "k8s_session" }
+func (s *terminalTimeline) Label() string { return "K8s 会话" }
+
+func (s *terminalTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	db := s.db.WithContext(ctx).Model(&model.TerminalSession{})
+	if query.HasResource() {
+		switch query.ResourceType {
+		case "k8s_cluster":
+			db = db.Where("cluster_id = ?", query.ResourceID)
+		case "k8s_namespaces":
+			db = db.Where("namespace = ?", query.ResourceID)
+		case "k8s_pods":
+			db = db.Where("pod_name = ?", query.ResourceID)
+		case "k8s_terminal_session":
+			db = db.Where("id = ?", query.ResourceID)
+		default:
+			return nil, nil
+		}
+	}
+	var sessions []*model.TerminalSession
+	err := db.Scopes(
+		audit.TimelineScope(query, "created_at", "user_id", "username"),
+		rbacbiz.ScopeByDataPermission(ctx, "user_id"),
+	).Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*audit.TimelineEntry, 0, len(sessions))
+	for _, session := range sessions {
+		entry := &audit.TimelineEntry{
+			RecordID:     session.ID,
+			Time:         session.CreatedAt,
+			UserID:       session.UserID,
+			Username:     session.Username,
+			Action:       audit.ActionConnect,
+			Title:        fmt.Sprintf("Pod 终端 %s/%s 容器 %s (集群 %s)", session.Namespace, session.PodName, session.ContainerName, session.ClusterName),
+			ResourceType: "k8s_cluster",
+			ResourceID:   strconv.FormatUint(uint64(session.ClusterID), 10),
+			Status:       session.Status,
+			Link:         "/kubernetes/audit",
+		}
+		if session.Duration > 0 {
+			end := session.CreatedAt.Add(time.Duration(session.Duration) * time.Second)
+			entry.EndTime = &end
+		}
+		if session.RecordingPath != "" {
+			entry.DetailKind = audit.TimelineDetailRecording
+			entry.DetailURL = fmt.Sprintf("/api/v1/plugins/kubernetes/terminal/sessions/%d/play", session.ID)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package monitor
+
+import "github.com/ydcloud-dy/opshub/internal/plugin"
+
+// auditModule 操作日志中的模块名称
+const auditModule = "监控中心"
+
+// GetAuditRoutes 获取插件接口的审计元数据
+func (p *Plugin) GetAuditRoutes() []plugin.AuditRouteConfig {
+	return []plugin.AuditRouteConfig{
+		// 域名监控
+		{Method: "POST", Path: "/monitor/domains", Module: auditModule, Action: "创建", Description: "创建域名监控 {body.domain}", ResourceType: "domain_monitor", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/monitor/domains/:id", Module: auditModule, Action: "更新", Description: "更新域名监控 #{path.id} {body.domain}", ResourceType: "domain_monitor", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/monitor/domains/:id", Module: auditModule, Action: "删除", Description: "删除域名监控 #{path.id}", ResourceType: "domain_monitor", ResourceID: "path.id"},
+		{Method: "POST", Path: "/monitor/domains/:id/check", Module: auditModule, Action: "执行", Description: "立即检查域名监控 #{path.id}", ResourceType: "domain_monitor", ResourceID: "path.id"},
+
+		// 证书文件
+		{Method: "POST", Path: "/monitor/certificates/upload", Module: auditModule, Action: "上传", Description: "上传证书文件 {body.files}", ResourceType: "certificate"},
+		{Method: "POST", Path: "/monitor/certificates/validate", Module: auditModule, Action: "测试", Description: "验证证书内容", ResourceType: "certificate"},
+
+		// 告警通道
+		{Method: "POST", Path: "/monitor/alerts/channels", Module: auditModule, Action: "创建", Description: "创建告警通道 {body.name}", ResourceType: "alert_channel", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/monitor/alerts/channels/:id", Module: auditModule, Action: "更新", Description: "更新告警通道 #{path.id} {body.name}", ResourceType: "alert_channel", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/monitor/alerts/channels/:id", Module: auditModule, Action: "删除", Description: "删除告警通道 #{path.id}", ResourceType: "alert_channel", ResourceID: "path.id"},
+
+		// 告警接收人
+		{Method: "POST", Path: "/monitor/alerts/receivers", Module: auditModule, Action: "创建", Description: "创建告警接收人 {body.name}", ResourceType: "alert_receiver", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/monitor/alerts/receivers/:id", Module: auditModule, Action: "更新", Description: "更新告警接收人 #{path.id} {body.name}", ResourceType: "alert_receiver", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/monitor/alerts/receivers/:id", Module: auditModule, Action: "删除", Description: "删除告警接收人 #{path.id}", ResourceType: "alert_receiver", ResourceID: "path.id"},
+		{Method: "POST", Path: "/monitor/alerts/receiver-channels/:receiverId", Module: auditModule, Action: "授权", Description: "为告警接收人 #{path.receiverId} 添加通道 #{body.channelId}", ResourceType: "alert_receiver", ResourceID: "path.receiverId"},
+		{Method: "PUT", Path: "/monitor/alerts/receiver-channels/:receiverId/:channelId", Module: auditModule, Action: "更新", Description: "更新告警接收人 #{path.receiverId} 的通道 #{path.channelId} 配置", ResourceType: "alert_receiver", ResourceID: "path.receiverId"},
+		{Method: "DELETE", Path: "/monitor/alerts/receiver-channels/:receiverId/:channelId", Module: auditModule, Action: "撤销", Description: "移除告警接收人 #{path.receiverId} 的通道 #{path.channelId}", ResourceType: "alert_receiver", ResourceID: "path.receiverId"},
+	}
+}
@@ -152,3 +152,106 @@ func (p *Plugin) GetMenus() []plugin.MenuConfig {
 		},
 	}
 }
+
+// GetPermissions 获取插件接口权限
+func (p *Plugin) GetPermissions() []plugin.PermissionConfig {
+	return []plugin.PermissionConfig{
+		{
+			Code:     "monitor:domain:view",
+			Name:     "查看域名监控",
+			MenuCode: "monitor_domain",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/monitor/domains"},
+				{Method: "GET", Path: "/monitor/domains/stats"},
+				{Method: "GET", Path: "/monitor/domains/:id"},
+				{Method: "GET", Path: "/monitor/domains/:id/history"},
+			},
+		},
+		{
+			Code:     "monitor:domain:create",
+			Name:     "新增域名监控",
+			MenuCode: "monitor_domain",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/monitor/domains"},
+			},
+		},
+		{
+			Code:     "monitor:domain:update",
+			Name:     "编辑域名监控",
+			MenuCode: "monitor_domain",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/monitor/domains/:id"},
+				{Method: "POST", Path: "/monitor/domains/:id/check"},
+			},
+		},
+		{
+			Code:     "monitor:domain:delete",
+			Name:     "删除域名监控",
+			MenuCode: "monitor_domain",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/monitor/domains/:id"},
+			},
+		},
+		{
+			Code:     "monitor:certificate:upload",
+			Name:     "上传证书",
+			MenuCode: "monitor_domain",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/monitor/certificates/upload"},
+				{Method: "POST", Path: "/monitor/certificates/validate"},
+			},
+		},
+		{
+			Code:     "monitor:channel:view",
+			Name:     "查看告警通道",
+			MenuCode: "monitor_alert_channels",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/monitor/alerts/channels"},
+				{Method: "GET", Path: "/monitor/alerts/channels/:id"},
+			},
+		},
+		{
+			Code:     "monitor:channel:manage",
+			Name:     "管理告警通道",
+			MenuCode: "monitor_alert_channels",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/monitor/alerts/channels"},
+				{Method: "PUT", Path: "/monitor/alerts/channels/:id"},
+				{Method: "DELETE", Path: "/monitor/alerts/channels/:id"},
+			},
+		},
+		{
+			Code:     "monitor:receiver:view",
+			Name:     "查看告警接收人",
+			MenuCode: "monitor_alert_receivers",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/monitor/alerts/receivers"},
+				{Method: "GET", Path: "/monitor/alerts/receivers/:id"},
+				{Method: "GET", Path: "/monitor/alerts/receiver-channels/:receiverId"},
+			},
+		},
+		{
+			Code:     "monitor:receiver:manage",
+			Name:     "管理告警接收人",
+			MenuCode: "monitor_alert_receivers",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/monitor/alerts/receivers"},
+				{Method: "PUT", Path: "/monitor/alerts/receivers/:id"},
+				{Method: "DELETE", Path: "/monitor/alerts/receivers/:id"},
+				{Method: "POST", Path: "/monitor/alerts/receiver-channels/:receiverId"},
+				{Method: "PUT", Path: "/monitor/alerts/receiver-channels/:receiverId/:channelId"},
+				{Method: "DELETE", Path: "/monitor/alerts/receiver-channels/:receiverId/:channelId"},
+			},
+		},
+		{
+			Code:     "monitor:alert-log:view",
+			Name:     "查看告警日志",
+			MenuCode: "monitor_alert_logs",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/monitor/alerts/logs"},
+				{Method: "GET", Path: "/monitor/alerts/logs/stats"},
+				{Method: "GET", Path: "/monitor/alerts/stats"},
+			},
+		},
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/hex"
-	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -610,57 +609,9 @@ func (h *Handler) sendAlert(monitor *model.DomainMonitor, alert service.AlertMes
 	}
 
 	// 3. 构建告警通道配置
-	var channelConfig service.AlertChannelConfig
+	channelConfig := service.BuildChannelConfig(channels)
 	var emailReceivers []string
 
-	for _, channel := range channels {
-		var config map[string]interface{}
-		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
-			continue
-		}
-
-		switch channel.ChannelType {
-		case "email":
-			if smtpHost, ok := config["smtpHost"].(string); ok {
-				channelConfig.SMTPHost = smtpHost
-			}
-			if smtpPort, ok := config["smtpPort"].(float64); ok {
-				channelConfig.SMTPPort = int(smtpPort)
-			}
-			if smtpUser, ok := config["smtpUser"].(string); ok {
-				channelConfig.SMTPUser = smtpUser
-			}
-			if smtpPassword, ok := config["smtpPassword"].(string); ok {
-				channelConfig.SMTPPassword = smtpPassword
-			}
-			if fromEmail, ok := config["fromEmail"].(string); ok {
-				channelConfig.FromEmail = fromEmail
-			}
-			if fromName, ok := config["fromName"].(string); ok {
-				channelConfig.FromName = fromName
-			}
-		case "webhook":
-			if webhookURL, ok := config["webhookUrl"].(string); ok {
-				channelConfig.WebhookURL = webhookURL
-			}
-		case "wechat":
-			if wechatWebhook, ok := config["wechatWebhook"].(string); ok {
-				channelConfig.WeChatWebhook = wechatWebhook
-			}
-		case "dingtalk":
-			if dingtalkWebhook, ok := config["dingtalkWebhook"].(string); ok {
-				channelConfig.DingTalkWebhook = dingtalkWebhook
-			}
-			if dingtalkSecret, ok := config["dingtalkSecret"].(string); ok {
-				channelConfig.DingTalkSecret = dingtalkSecret
-			}
-		case "feishu":
-			if feishuWebhook, ok := config["feishuWebhook"].(string); ok {
-				channelConfig.FeishuWebhook = feishuWebhook
-			}
-		}
-	}
-
 	// 4. 获取接收人-通道关联关系（用于@提醒）
 	var receiverChannels []model.AlertReceiverChannel
 	if err := h.db.Find(&receiverChannels).Error; err != nil {
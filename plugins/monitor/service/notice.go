@@ -0,0 +1,243 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ydcloud-dy/opshub/plugins/monitor/model"
+	"gorm.io/gorm"
+)
+
+// BuildChannelConfig 根据启用的告警通道构建发送配置
+func BuildChannelConfig(channels []model.AlertChannel) AlertChannelConfig {
+	var channelConfig AlertChannelConfig
+	for _, channel := range channels {
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			continue
+		}
+
+		switch channel.ChannelType {
+		case "email":
+			if smtpHost, ok := config["smtpHost"].(string); ok {
+				channelConfig.SMTPHost = smtpHost
+			}
+			if smtpPort, ok := config["smtpPort"].(float64); ok {
+				channelConfig.SMTPPort = int(smtpPort)
+			}
+			if smtpUser, ok := config["smtpUser"].(string); ok {
+				channelConfig.SMTPUser = smtpUser
+			}
+			if smtpPassword, ok := config["smtpPassword"].(string); ok {
+				channelConfig.SMTPPassword = smtpPassword
+			}
+			if fromEmail, ok := config["fromEmail"].(string); ok {
+				channelConfig.FromEmail = fromEmail
+			}
+			if fromName, ok := config["fromName"].(string); ok {
+				channelConfig.FromName = fromName
+			}
+		case "webhook":
+			if webhookURL, ok := config["webhookUrl"].(string); ok {
+				channelConfig.WebhookURL = webhookURL
+			}
+		case "wechat":
+			if wechatWebhook, ok := config["wechatWebhook"].(string); ok {
+				channelConfig.WeChatWebhook = wechatWebhook
+			}
+		case "dingtalk":
+			if dingtalkWebhook, ok := config["dingtalkWebhook"].(string); ok {
+				channelConfig.DingTalkWebhook = dingtalkWebhook
+			}
+			if dingtalkSecret, ok := config["dingtalkSecret"].(string); ok {
+				channelConfig.DingTalkSecret = dingtalkSecret
+			}
+		case "feishu":
+			if feishuWebhook, ok := config["feishuWebhook"].(string); ok {
+				channelConfig.FeishuWebhook = feishuWebhook
+			}
+		}
+	}
+	return channelConfig
+}
+
+// Notifier 通用通知发送器，复用监控中心配置的告警通道和接收人
+// 用于访问申请审批等非域名告警的场景
+type Notifier struct {
+	db           *gorm.DB
+	alertService *AlertService
+}
+
+// NewNotifier 创建通知发送器
+func NewNotifier(db *gorm.DB) *Notifier {
+	return &Notifier{
+		db:           db,
+		alertService: NewAlertService(),
+	}
+}
+
+// Notify 向指定系统用户发送通知
+// 通过告警接收人的 userId 关联系统用户，未关联接收人的用户只能通过群机器人看到通知
+func (n *Notifier) Notify(ctx context.Context, title, content string, userIDs []uint) error {
+	db := n.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&model.AlertChannel{}) {
+		return fmt.Errorf("监控中心插件未启用，无可用通知通道")
+	}
+
+	var channels []model.AlertChannel
+	if err := db.Where("enabled = ?", true).Find(&channels).Error; err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return fmt.Errorf("未配置启用的告警通道")
+	}
+	config := BuildChannelConfig(channels)
+
+	var receivers []model.AlertReceiver
+	if len(userIDs) > 0 {
+		if err := db.Where("user_id IN ?", userIDs).Find(&receivers).Error; err != nil {
+			return err
+		}
+	}
+
+	return n.alertService.SendNotice(title, content, config, receivers)
+}
+
+// SendNotice 通过告警通道发送通用通知
+func (s *AlertService) SendNotice(title, content string, config AlertChannelConfig, receivers []model.AlertReceiver) error {
+	var errors []error
+	var successCount int
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+
+	var emails, wechatIDs, dingtalkIDs, feishuIDs, mobiles []string
+	for _, r := range receivers {
+		if r.EnableEmail && r.Email != "" {
+			emails = append(emails, r.Email)
+		}
+		if r.EnableWeChat && r.WeChatID != "" {
+			wechatIDs = append(wechatIDs, r.WeChatID)
+		}
+		if r.EnableDingTalk && r.DingTalkID != "" {
+			dingtalkIDs = append(dingtalkIDs, r.DingTalkID)
+		}
+		if r.EnableDingTalk && r.Phone != "" {
+			mobiles = append(mobiles, r.Phone)
+		}
+		if r.EnableFeishu && r.FeishuID != "" {
+			feishuIDs = append(feishuIDs, r.FeishuID)
+		}
+	}
+
+	send := func(name string, fn func() error) {
+		if err := fn(); err != nil {
+			errors = append(errors, fmt.Errorf("%s发送失败: %w", name, err))
+		} else {
+			successCount++
+		}
+	}
+
+	if config.SMTPHost != "" && len(emails) > 0 {
+		send("邮件", func() error {
+			auth := smtp.PlainAuth("", config.SMTPUser, config.SMTPPassword, config.SMTPHost)
+			msg := fmt.Sprintf("From: %s <%s>\r\nTo: %s\r\nSubject: [OpsHub] %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n\r\n%s",
+				config.FromName, config.FromEmail, strings.Join(emails, ", "), title, content, timestamp)
+			addr := fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort)
+			return smtp.SendMail(addr, auth, config.FromEmail, emails, []byte(msg))
+		})
+	}
+
+	if config.WeChatWebhook != "" {
+		send("企业微信", func() error {
+			text := fmt.Sprintf("**%s**\n\n%s\n**时间**: %s", title, content, timestamp)
+			for _, id := range wechatIDs {
+				text += " <@" + id + ">"
+			}
+			return s.sendWebhookRequest(config.WeChatWebhook, map[string]interface{}{
+				"msgtype": "text",
+				"text":    map[string]string{"content": text},
+			})
+		})
+	}
+
+	if config.DingTalkWebhook != "" {
+		send("钉钉", func() error {
+			text := fmt.Sprintf("## %s\n\n%s\n\n**时间**: %s", title, content, timestamp)
+			for _, id := range dingtalkIDs {
+				text += " @" + id
+			}
+			data := map[string]interface{}{
+				"msgtype":  "markdown",
+				"markdown": map[string]interface{}{"title": title, "text": text},
+			}
+			if len(mobiles) > 0 {
+				data["at"] = map[string]interface{}{"atMobiles": mobiles, "isAtAll": false}
+			}
+			return s.sendWebhookRequest(config.DingTalkWebhook, data)
+		})
+	}
+
+	if config.FeishuWebhook != "" {
+		send("飞书", func() error {
+			elements := []map[string]interface{}{
+				{"tag": "text", "text": content + "\n"},
+				{"tag": "text", "text": "时间: " + timestamp},
+			}
+			for _, id := range feishuIDs {
+				elements = append(elements, map[string]interface{}{"tag": "at", "user_id": id})
+			}
+			return s.sendWebhookRequest(config.FeishuWebhook, map[string]interface{}{
+				"msg_type": "post",
+				"content": map[string]interface{}{
+					"post": map[string]interface{}{
+						"zh_cn": map[string]interface{}{
+							"title":   title,
+							"content": [][]map[string]interface{}{elements},
+						},
+					},
+				},
+			})
+		})
+	}
+
+	if config.WebhookURL != "" {
+		send("Webhook", func() error {
+			return s.sendWebhookRequest(config.WebhookURL, map[string]interface{}{
+				"type":      "notice",
+				"title":     title,
+				"message":   content,
+				"timestamp": timestamp,
+			})
+		})
+	}
+
+	if successCount == 0 {
+		if len(errors) > 0 {
+			return fmt.Errorf("所有通知通道发送失败: %v", errors)
+		}
+		return fmt.Errorf("没有可用的通知通道")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sslcert
+
+import "github.com/ydcloud-dy/opshub/internal/plugin"
+
+// auditModule 操作日志中的模块名称
+const auditModule = "证书管理"
+
+// GetAuditRoutes 获取插件接口的审计元数据
+func (p *Plugin) GetAuditRoutes() []plugin.AuditRouteConfig {
+	return []plugin.AuditRouteConfig{
+		// 证书
+		{Method: "POST", Path: "/ssl-cert/certificates", Module: auditModule, Action: "创建", Description: "申请证书 {body.name} ({body.domain})", ResourceType: "ssl_certificate", ResourceID: "resp.data.id"},
+		{Method: "POST", Path: "/ssl-cert/certificates/import", Module: auditModule, Action: "导入", Description: "导入证书 {body.name}", ResourceType: "ssl_certificate", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/ssl-cert/certificates/:id", Module: auditModule, Action: "更新", Description: "更新证书 #{path.id} {body.name}", ResourceType: "ssl_certificate", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/ssl-cert/certificates/:id", Module: auditModule, Action: "删除", Description: "删除证书 #{path.id}", ResourceType: "ssl_certificate", ResourceID: "path.id"},
+		{Method: "POST", Path: "/ssl-cert/certificates/:id/renew", Module: auditModule, Action: "执行", Description: "续期证书 #{path.id}", ResourceType: "ssl_certificate", ResourceID: "path.id"},
+		{Method: "POST", Path: "/ssl-cert/certificates/:id/sync", Module: auditModule, Action: "同步", Description: "同步证书 #{path.id}", ResourceType: "ssl_certificate", ResourceID: "path.id"},
+		{Method: "GET", Path: "/ssl-cert/certificates/:id/download", Module: auditModule, Action: "导出", Description: "下载证书 #{path.id}", ResourceType: "ssl_certificate", ResourceID: "path.id"},
+
+		// DNS 服务商
+		{Method: "POST", Path: "/ssl-cert/dns-providers", Module: auditModule, Action: "创建", Description: "创建 DNS 服务商 {body.name}", ResourceType: "dns_provider", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/ssl-cert/dns-providers/:id", Module: auditModule, Action: "更新", Description: "更新 DNS 服务商 #{path.id} {body.name}", ResourceType: "dns_provider", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/ssl-cert/dns-providers/:id", Module: auditModule, Action: "删除", Description: "删除 DNS 服务商 #{path.id}", ResourceType: "dns_provider", ResourceID: "path.id"},
+		{Method: "POST", Path: "/ssl-cert/dns-providers/:id/test", Module: auditModule, Action: "测试", Description: "测试 DNS 服务商 #{path.id}", ResourceType: "dns_provider", ResourceID: "path.id"},
+
+		// 部署配置
+		{Method: "POST", Path: "/ssl-cert/deploy-configs", Module: auditModule, Action: "创建", Description: "创建部署配置 {body.name}", ResourceType: "deploy_config", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/ssl-cert/deploy-configs/:id", Module: auditModule, Action: "更新", Description: "更新部署配置 #{path.id} {body.name}", ResourceType: "deploy_config", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/ssl-cert/deploy-configs/:id", Module: auditModule, Action: "删除", Description: "删除部署配置 #{path.id}", ResourceType: "deploy_config", ResourceID: "path.id"},
+		{Method: "POST", Path: "/ssl-cert/deploy-configs/:id/deploy", Module: auditModule, Action: "部署", Description: "执行证书部署 #{path.id}", ResourceType: "deploy_config", ResourceID: "path.id"},
+		{Method: "POST", Path: "/ssl-cert/deploy-configs/:id/test", Module: auditModule, Action: "测试", Description: "测试部署配置 #{path.id}", ResourceType: "deploy_config", ResourceID: "path.id"},
+	}
+}
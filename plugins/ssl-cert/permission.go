@@ -0,0 +1,140 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sslcert
+
+import "github.com/ydcloud-dy/opshub/internal/plugin"
+
+// GetPermissions 获取插件接口权限，查询接口对应各菜单的查看权限
+func (p *Plugin) GetPermissions() []plugin.PermissionConfig {
+	return []plugin.PermissionConfig{
+		// 证书
+		{
+			Code:     "ssl-cert:certificate:view",
+			Name:     "查看证书",
+			MenuCode: "ssl_cert_certificates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/certificates"},
+				{Method: "GET", Path: "/ssl-cert/certificates/stats"},
+				{Method: "GET", Path: "/ssl-cert/certificates/cloud-accounts"},
+				{Method: "GET", Path: "/ssl-cert/certificates/:id"},
+			},
+		},
+		{
+			Code:     "ssl-cert:certificate:create",
+			Name:     "申请证书",
+			MenuCode: "ssl_cert_certificates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/ssl-cert/certificates"},
+				{Method: "POST", Path: "/ssl-cert/certificates/import"},
+			},
+		},
+		{
+			Code:     "ssl-cert:certificate:update",
+			Name:     "编辑证书",
+			MenuCode: "ssl_cert_certificates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/ssl-cert/certificates/:id"},
+				{Method: "POST", Path: "/ssl-cert/certificates/:id/renew"},
+				{Method: "POST", Path: "/ssl-cert/certificates/:id/sync"},
+			},
+		},
+		{
+			Code:     "ssl-cert:certificate:delete",
+			Name:     "删除证书",
+			MenuCode: "ssl_cert_certificates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/ssl-cert/certificates/:id"},
+			},
+		},
+		{
+			Code:     "ssl-cert:certificate:download",
+			Name:     "下载证书和私钥",
+			MenuCode: "ssl_cert_certificates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/certificates/:id/download"},
+			},
+		},
+
+		// DNS 服务商，完整配置包含密钥，只对管理权限开放
+		{
+			Code:     "ssl-cert:dns:view",
+			Name:     "查看DNS配置",
+			MenuCode: "ssl_cert_dns_providers",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/dns-providers"},
+				{Method: "GET", Path: "/ssl-cert/dns-providers/all"},
+				{Method: "GET", Path: "/ssl-cert/dns-providers/:id"},
+			},
+		},
+		{
+			Code:     "ssl-cert:dns:manage",
+			Name:     "管理DNS配置",
+			MenuCode: "ssl_cert_dns_providers",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/dns-providers/:id/detail"},
+				{Method: "POST", Path: "/ssl-cert/dns-providers"},
+				{Method: "PUT", Path: "/ssl-cert/dns-providers/:id"},
+				{Method: "DELETE", Path: "/ssl-cert/dns-providers/:id"},
+				{Method: "POST", Path: "/ssl-cert/dns-providers/:id/test"},
+			},
+		},
+
+		// 部署配置
+		{
+			Code:     "ssl-cert:deploy:view",
+			Name:     "查看部署配置",
+			MenuCode: "ssl_cert_deploy_configs",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/deploy-configs"},
+				{Method: "GET", Path: "/ssl-cert/deploy-configs/:id"},
+			},
+		},
+		{
+			Code:     "ssl-cert:deploy:manage",
+			Name:     "管理部署配置",
+			MenuCode: "ssl_cert_deploy_configs",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/ssl-cert/deploy-configs"},
+				{Method: "PUT", Path: "/ssl-cert/deploy-configs/:id"},
+				{Method: "DELETE", Path: "/ssl-cert/deploy-configs/:id"},
+				{Method: "POST", Path: "/ssl-cert/deploy-configs/:id/test"},
+			},
+		},
+		{
+			Code:     "ssl-cert:deploy:run",
+			Name:     "部署证书",
+			MenuCode: "ssl_cert_deploy_configs",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/ssl-cert/deploy-configs/:id/deploy"},
+			},
+		},
+
+		// 任务记录
+		{
+			Code:     "ssl-cert:task:view",
+			Name:     "查看任务记录",
+			MenuCode: "ssl_cert_tasks",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/ssl-cert/tasks"},
+				{Method: "GET", Path: "/ssl-cert/tasks/:id"},
+			},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package task
+
+import "github.com/ydcloud-dy/opshub/internal/plugin"
+
+// auditModule 操作日志中的模块名称
+const auditModule = "任务中心"
+
+// GetAuditRoutes 获取插件接口的审计元数据
+func (p *Plugin) GetAuditRoutes() []plugin.AuditRouteConfig {
+	return []plugin.AuditRouteConfig{
+		// 执行与分发
+		{Method: "POST", Path: "/task/execute", Module: auditModule, Action: "执行", Description: "执行脚本 {body.name} ({body.scriptType}) 主机 {body.hostIds}", ResourceType: "job_task", ResourceID: "resp.data.taskId"},
+		{Method: "POST", Path: "/task/distribute", Module: auditModule, Action: "上传", Description: "分发文件到 {body.form.targetPath} 主机 {body.form.hostIds}", ResourceType: "job_task"},
+
+		// 任务作业
+		{Method: "POST", Path: "/task/jobs", Module: auditModule, Action: "创建", Description: "创建任务 {body.name}", ResourceType: "job_task", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/task/jobs/:id", Module: auditModule, Action: "更新", Description: "更新任务 #{path.id} {body.name}", ResourceType: "job_task", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/task/jobs/:id", Module: auditModule, Action: "删除", Description: "删除任务 #{path.id}", ResourceType: "job_task", ResourceID: "path.id"},
+
+		// 任务模板
+		{Method: "POST", Path: "/task/templates", Module: auditModule, Action: "创建", Description: "创建任务模板 {body.name}", ResourceType: "job_template", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/task/templates/:id", Module: auditModule, Action: "更新", Description: "更新任务模板 #{path.id} {body.name}", ResourceType: "job_template", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/task/templates/:id", Module: auditModule, Action: "删除", Description: "删除任务模板 #{path.id}", ResourceType: "job_template", ResourceID: "path.id"},
+
+		// Ansible 任务
+		{Method: "POST", Path: "/task/ansible", Module: auditModule, Action: "创建", Description: "创建 Ansible 任务 {body.name}", ResourceType: "ansible_task", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/task/ansible/:id", Module: auditModule, Action: "更新", Description: "更新 Ansible 任务 #{path.id} {body.name}", ResourceType: "ansible_task", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/task/ansible/:id", Module: auditModule, Action: "删除", Description: "删除 Ansible 任务 #{path.id}", ResourceType: "ansible_task", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/ansible/:id/run", Module: auditModule, Action: "执行", Description: "执行 Ansible 任务 #{path.id}", ResourceType: "ansible_task", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/ansible/runs/:jobId/cancel", Module: auditModule, Action: "执行", Description: "取消 Ansible 执行 #{path.jobId}", ResourceType: "job_task", ResourceID: "path.jobId"},
+		{Method: "POST", Path: "/task/schedules", Module: auditModule, Action: "创建", Description: "创建定时任务 {body.name}", ResourceType: "job_schedule", ResourceID: "resp.data.id"},
+		{Method: "PUT", Path: "/task/schedules/:id", Module: auditModule, Action: "更新", Description: "更新定时任务 #{path.id} {body.name}", ResourceType: "job_schedule", ResourceID: "path.id"},
+		{Method: "DELETE", Path: "/task/schedules/:id", Module: auditModule, Action: "删除", Description: "删除定时任务 #{path.id}", ResourceType: "job_schedule", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/schedules/:id/enable", Module: auditModule, Action: "更新", Description: "启用定时任务 #{path.id}", ResourceType: "job_schedule", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/schedules/:id/pause", Module: auditModule, Action: "更新", Description: "暂停定时任务 #{path.id}", ResourceType: "job_schedule", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/schedules/:id/run", Module: auditModule, Action: "执行", Description: "立即执行定时任务 #{path.id}", ResourceType: "job_schedule", ResourceID: "path.id"},
+
+		// 执行历史
+		{Method: "DELETE", Path: "/task/execution-history/:id", Module: auditModule, Action: "删除", Description: "删除执行记录 #{path.id}", ResourceType: "job_task", ResourceID: "path.id"},
+		{Method: "POST", Path: "/task/execution-history/batch-delete", Module: auditModule, Action: "删除", Description: "批量删除执行记录 {body.ids}", ResourceType: "job_task"},
+		{Method: "POST", Path: "/task/execution-history/export", Module: auditModule, Action: "导出", Description: "导出执行记录", ResourceType: "job_task"},
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package model
+
+import (
+	"time"
+)
+
+// JobSchedule 定时任务，按 cron 表达式使用任务模板在目标主机上执行
+type JobSchedule struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Name            string     `json:"name" gorm:"size:255;not null" binding:"required"`
+	Description     string     `json:"description" gorm:"type:text"`
+	CronExpr        string     `json:"cronExpr" gorm:"size:100;not null" binding:"required"` // 5 位 cron 表达式或 @daily 等描述符
+	Timezone        string     `json:"timezone" gorm:"size:64"`                              // IANA 时区，为空时使用服务器时区
+	TemplateID      uint       `json:"templateId" gorm:"not null;index" binding:"required"`
+	ScriptType      string     `json:"scriptType" gorm:"size:20;not null;default:Shell"`   // Shell, Python
+	Variables       string     `json:"variables,omitempty" gorm:"type:text"`               // JSON对象，模板变量取值
+	Content         string     `json:"content,omitempty" gorm:"type:longtext"`             // 保存时渲染的脚本快照，执行时不再读取模板
+	Timeout         int        `json:"timeout"`                                            // 保存时模板的超时时间（秒）
+	TargetType      string     `json:"targetType" gorm:"size:20;not null;default:hosts"`   // hosts, group
+	TargetHosts     string     `json:"targetHosts,omitempty" gorm:"type:text"`             // JSON主机ID数组
+	GroupID         uint       `json:"groupId" gorm:"default:0"`                           // 每次执行时解析分组及子分组下的主机
+	OverlapPolicy   string     `json:"overlapPolicy" gorm:"size:20;not null;default:skip"` // skip, queue, allow
+	Enabled         bool       `json:"enabled" gorm:"index"`
+	NotifyOnFailure bool       `json:"notifyOnFailure"`
+	NotifyUserIDs   string     `json:"notifyUserIds,omitempty" gorm:"column:notify_user_ids;type:text"` // JSON用户ID数组，为空时通知创建人
+	LastRunTime     *time.Time `json:"lastRunTime,omitempty"`
+	LastRunStatus   string     `json:"lastRunStatus,omitempty" gorm:"size:50"`
+	CreatedBy       uint       `json:"createdBy" gorm:"not null"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+	DeletedAt       *time.Time `json:"deletedAt,omitempty" gorm:"index"`
+}
+
+func (JobSchedule) TableName() string {
+	return "job_schedules"
+}
@@ -28,13 +28,15 @@ type JobTask struct {
 	ID           uint       `json:"id" gorm:"primaryKey"`
 	Name         string     `json:"name" gorm:"size:255;not null" binding:"required"`
 	TemplateID   *uint      `json:"templateId,omitempty" gorm:"index"`
+	ScheduleID   *uint      `json:"scheduleId,omitempty" gorm:"index"` // 定时任务触发时记录所属定时任务
 	TaskType     string     `json:"taskType" gorm:"size:50;not null;index" binding:"required"` // manual, ansible, cron
-	Status       string     `json:"status" gorm:"size:50;not null;default:pending;index"` // pending, running, success, failed
+	Status       string     `json:"status" gorm:"size:50;not null;default:pending;index"` // pending, running, success, failed, cancelled, skipped
 	TargetHosts  string     `json:"targetHosts,omitempty" gorm:"type:text"` // JSON字符串
 	Parameters   string     `json:"parameters,omitempty" gorm:"type:text"` // JSON
 	ExecuteTime  *time.Time `json:"executeTime,omitempty"`
 	Result       string     `json:"result,omitempty" gorm:"type:text"` // JSON
 	ErrorMessage string     `json:"errorMessage,omitempty" gorm:"type:text"`
+	Output       string     `json:"output,omitempty" gorm:"type:longtext"` // 完整输出，Ansible 执行时记录
 	CreatedBy    uint       `json:"createdBy" gorm:"not null"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	UpdatedAt    time.Time  `json:"updatedAt"`
@@ -26,12 +26,14 @@ import (
 	"github.com/ydcloud-dy/opshub/internal/plugin"
 	"github.com/ydcloud-dy/opshub/plugins/task/model"
 	"github.com/ydcloud-dy/opshub/plugins/task/server"
+	"github.com/ydcloud-dy/opshub/plugins/task/service"
 )
 
 // Plugin 任务中心插件实现
 type Plugin struct {
-	db   *gorm.DB
-	name string
+	db        *gorm.DB
+	name      string
+	scheduler *service.JobScheduler
 }
 
 // New 创建插件实例
@@ -70,6 +72,7 @@ func (p *Plugin) Enable(db *gorm.DB) error {
 		&model.JobTask{},
 		&model.JobTemplate{},
 		&model.AnsibleTask{},
+		&model.JobSchedule{},
 	}
 
 	for _, m := range models {
@@ -80,18 +83,44 @@ func (p *Plugin) Enable(db *gorm.DB) error {
 		}
 	}
 
+	// 已有的执行记录表补充完整输出列
+	if !db.Migrator().HasColumn(&model.JobTask{}, "Output") {
+		if err := db.Migrator().AddColumn(&model.JobTask{}, "Output"); err != nil {
+			return err
+		}
+	}
+
+	// 执行记录关联定时任务
+	if !db.Migrator().HasColumn(&model.JobTask{}, "ScheduleID") {
+		if err := db.Migrator().AddColumn(&model.JobTask{}, "ScheduleID"); err != nil {
+			return err
+		}
+		if err := db.Migrator().CreateIndex(&model.JobTask{}, "ScheduleID"); err != nil {
+			return err
+		}
+	}
+
+	// 启动定时任务调度器
+	if p.scheduler == nil {
+		p.scheduler = service.NewJobScheduler(db, server.CheckCommandSafety)
+	}
+	p.scheduler.Start()
+
 	return nil
 }
 
 // Disable 禁用插件
 func (p *Plugin) Disable(db *gorm.DB) error {
-	// 清理插件资源（如果需要）
+	// 停止定时任务调度器
+	if p.scheduler != nil {
+		p.scheduler.Stop()
+	}
 	return nil
 }
 
 // RegisterRoutes 注册路由
 func (p *Plugin) RegisterRoutes(router *gin.RouterGroup, db *gorm.DB) {
-	server.RegisterRoutes(router, db)
+	server.RegisterRoutes(router, db, p.scheduler)
 }
 
 // GetMenus 获取插件菜单配置
@@ -115,5 +144,158 @@ func (p *Plugin) GetMenus() []plugin.MenuConfig {
 			Icon: "FolderOpened",
 			Sort: 52,
 		},
+		{
+			Name: "定时任务",
+			Path: "/task/schedules",
+			Icon: "Timer",
+			Sort: 53,
+		},
+	}
+}
+
+// GetPermissions 获取插件接口权限
+func (p *Plugin) GetPermissions() []plugin.PermissionConfig {
+	return []plugin.PermissionConfig{
+		{
+			Code:     "task:job:view",
+			Name:     "查看任务",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/task/jobs"},
+				{Method: "GET", Path: "/task/jobs/:id"},
+			},
+		},
+		{
+			Code:     "task:execute",
+			Name:     "执行任务",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/execute"},
+				{Method: "POST", Path: "/task/jobs"},
+				{Method: "PUT", Path: "/task/jobs/:id"},
+				{Method: "DELETE", Path: "/task/jobs/:id"},
+			},
+		},
+		{
+			Code:     "task:ansible:view",
+			Name:     "查看Ansible任务",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/task/ansible"},
+				{Method: "GET", Path: "/task/ansible/:id"},
+				{Method: "GET", Path: "/task/ansible/runs/:jobId/log"},
+			},
+		},
+		{
+			Code:     "task:ansible:manage",
+			Name:     "管理Ansible任务",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/ansible"},
+				{Method: "PUT", Path: "/task/ansible/:id"},
+				{Method: "DELETE", Path: "/task/ansible/:id"},
+			},
+		},
+		{
+			Code:     "task:ansible:run",
+			Name:     "执行Ansible任务",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/ansible/:id/run"},
+				{Method: "POST", Path: "/task/ansible/runs/:jobId/cancel"},
+			},
+		},
+		{
+			Code:     "task:history:view",
+			Name:     "查看执行记录",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/task/execution-history"},
+				{Method: "GET", Path: "/task/execution-history/:id"},
+				{Method: "POST", Path: "/task/execution-history/export"},
+			},
+		},
+		{
+			Code:     "task:history:delete",
+			Name:     "删除执行记录",
+			MenuCode: "task_execute",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/task/execution-history/:id"},
+				{Method: "POST", Path: "/task/execution-history/batch-delete"},
+			},
+		},
+		{
+			Code:     "task:distribute",
+			Name:     "分发文件",
+			MenuCode: "task_file_distribution",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/distribute"},
+			},
+		},
+		{
+			Code:     "task:template:view",
+			Name:     "查看模板",
+			MenuCode: "task_templates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/task/templates"},
+				{Method: "GET", Path: "/task/templates/all"},
+				{Method: "GET", Path: "/task/templates/:id"},
+			},
+		},
+		{
+			Code:     "task:template:create",
+			Name:     "新增模板",
+			MenuCode: "task_templates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/templates"},
+			},
+		},
+		{
+			Code:     "task:template:update",
+			Name:     "编辑模板",
+			MenuCode: "task_templates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "PUT", Path: "/task/templates/:id"},
+			},
+		},
+		{
+			Code:     "task:template:delete",
+			Name:     "删除模板",
+			MenuCode: "task_templates",
+			Routes: []plugin.PermissionRoute{
+				{Method: "DELETE", Path: "/task/templates/:id"},
+			},
+		},
+		{
+			Code:     "task:schedule:view",
+			Name:     "查看定时任务",
+			MenuCode: "task_schedules",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/task/schedules"},
+				{Method: "GET", Path: "/task/schedules/preview"},
+				{Method: "GET", Path: "/task/schedules/:id"},
+				{Method: "GET", Path: "/task/schedules/:id/runs"},
+			},
+		},
+		{
+			Code:     "task:schedule:manage",
+			Name:     "管理定时任务",
+			MenuCode: "task_schedules",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/schedules"},
+				{Method: "PUT", Path: "/task/schedules/:id"},
+				{Method: "DELETE", Path: "/task/schedules/:id"},
+				{Method: "POST", Path: "/task/schedules/:id/enable"},
+				{Method: "POST", Path: "/task/schedules/:id/pause"},
+			},
+		},
+		{
+			Code:     "task:schedule:run",
+			Name:     "立即执行定时任务",
+			MenuCode: "task_schedules",
+			Routes: []plugin.PermissionRoute{
+				{Method: "POST", Path: "/task/schedules/:id/run"},
+			},
+		},
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"github.com/ydcloud-dy/opshub/plugins/task/model"
 	"gorm.io/gorm"
 )
@@ -74,6 +75,9 @@ func (r *JobTaskRepository) List(ctx context.Context, page, pageSize int, keywor
 		query = query.Where("status = ?", status)
 	}
 
+	// 数据权限：只返回可见部门内用户创建的任务
+	query = query.Scopes(rbacbiz.ScopeByDataPermission(ctx, "created_by"))
+
 	err := query.Count(&total).Error
 	if err != nil {
 		return nil, 0, err
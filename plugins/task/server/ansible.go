@@ -0,0 +1,239 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"github.com/ydcloud-dy/opshub/plugins/task/service"
+)
+
+// RunAnsibleTaskRequest 执行Ansible任务请求，未填写的字段使用任务配置
+type RunAnsibleTaskRequest struct {
+	HostIDs   []uint                 `json:"hostIds"`
+	GroupIDs  []uint                 `json:"groupIds"`
+	Tags      string                 `json:"tags"`
+	Limit     string                 `json:"limit"`
+	ExtraVars map[string]interface{} `json:"extraVars"`
+	Check     bool                   `json:"check"`
+}
+
+// RunAnsibleTask 执行Ansible任务
+// @Summary 执行Ansible任务
+// @Description 在后台执行Ansible任务，返回执行记录ID，可通过日志接口实时获取输出
+// @Tags 任务管理-Ansible任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "任务ID"
+// @Param body body RunAnsibleTaskRequest false "执行参数"
+// @Success 200 {object} response.Response "已开始执行"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 403 {object} response.Response "无权在目标主机上执行，或非管理员执行内联 Playbook"
+// @Failure 404 {object} response.Response "任务不存在"
+// @Failure 409 {object} response.Response "任务正在执行"
+// @Router /task/ansible/{id}/run [post]
+func (h *Handler) RunAnsibleTask(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var task model.AnsibleTask
+	if err := h.db.Where("id = ? AND deleted_at IS NULL", id).First(&task).Error; err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "任务不存在")
+		return
+	}
+
+	var req RunAnsibleTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	var createdBy uint = 1
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uint); ok {
+			createdBy = uid
+		}
+	}
+
+	opts := service.AnsibleRunOptions{
+		Tags:      req.Tags,
+		Limit:     req.Limit,
+		ExtraVars: req.ExtraVars,
+		Check:     req.Check,
+		CreatedBy: createdBy,
+	}
+	if len(req.HostIDs) > 0 || len(req.GroupIDs) > 0 {
+		opts.Inventory = &service.AnsibleInventory{HostIDs: req.HostIDs, GroupIDs: req.GroupIDs}
+	}
+
+	job, _, err := h.ansible.Start(c.Request.Context(), &task, opts)
+	if err != nil {
+		if errors.Is(err, service.ErrAnsibleTaskRunning) {
+			response.ErrorCode(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrHostPermissionDenied) || errors.Is(err, service.ErrInlinePlaybookDenied) {
+			response.ErrorCode(c, http.StatusForbidden, err.Error())
+			return
+		}
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "已开始执行", gin.H{
+		"jobTaskId": job.ID,
+		"status":    job.Status,
+	})
+}
+
+// CancelAnsibleRun 取消Ansible执行
+// @Summary 取消Ansible执行
+// @Description 终止执行中的ansible-playbook进程
+// @Tags 任务管理-Ansible任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param jobId path int true "执行记录ID"
+// @Success 200 {object} response.Response "取消成功"
+// @Failure 404 {object} response.Response "执行不存在或已结束"
+// @Router /task/ansible/runs/{jobId}/cancel [post]
+func (h *Handler) CancelAnsibleRun(c *gin.Context) {
+	jobID, ok := h.visibleJobTaskID(c)
+	if !ok {
+		return
+	}
+	if err := h.ansible.Cancel(jobID); err != nil {
+		response.ErrorCode(c, http.StatusNotFound, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "已取消", nil)
+}
+
+// StreamAnsibleRunLog 获取Ansible执行输出
+// @Summary 获取Ansible执行输出
+// @Description 以分块文本流返回offset之后的输出，执行中持续推送直到结束；已结束的执行直接返回保存的输出
+// @Tags 任务管理-Ansible任务
+// @Produce plain
+// @Security Bearer
+// @Param jobId path int true "执行记录ID"
+// @Param offset query int false "起始字节偏移" default(0)
+// @Success 200 {string} string "执行输出"
+// @Failure 404 {object} response.Response "执行记录不存在"
+// @Router /task/ansible/runs/{jobId}/log [get]
+func (h *Handler) StreamAnsibleRunLog(c *gin.Context) {
+	jobID, ok := h.visibleJobTaskID(c)
+	if !ok {
+		return
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	run := h.ansible.Run(jobID)
+	if run == nil {
+		var output string
+		h.db.Model(&model.JobTask{}).Select("output").Where("id = ?", jobID).Scan(&output)
+		if offset < len(output) {
+			c.Writer.WriteString(output[offset:])
+		}
+		return
+	}
+
+	ctx := c.Request.Context()
+	for {
+		data, finished, changed := run.Read(offset)
+		if len(data) > 0 {
+			if _, err := c.Writer.Write(data); err != nil {
+				return
+			}
+			c.Writer.Flush()
+			offset += len(data)
+		}
+		if finished {
+			return
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// visibleJobTaskID 解析路径中的执行记录ID，并校验当前用户的数据权限
+func (h *Handler) visibleJobTaskID(c *gin.Context) (uint, bool) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "无效的执行记录ID")
+		return 0, false
+	}
+	var count int64
+	h.db.Model(&model.JobTask{}).Where("id = ? AND deleted_at IS NULL", jobID).
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by")).
+		Count(&count)
+	if count == 0 {
+		response.ErrorCode(c, http.StatusNotFound, "执行记录不存在")
+		return 0, false
+	}
+	return uint(jobID), true
+}
+
+// checkInlinePlaybook 非管理员不能保存内联 Playbook，返回 false 时已写入响应
+func (h *Handler) checkInlinePlaybook(c *gin.Context, task *model.AnsibleTask) bool {
+	err := h.ansible.CheckInlinePlaybook(c.Request.Context(), currentUserID(c), task)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, service.ErrInlinePlaybookDenied) {
+		response.ErrorCode(c, http.StatusForbidden, err.Error())
+	} else {
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+	}
+	return false
+}
+
+// normalizeAnsibleTask 校验目标主机和额外变量，空的额外变量保存为空对象
+func normalizeAnsibleTask(task *model.AnsibleTask) error {
+	if _, err := service.ParseAnsibleInventory(task.Inventory); err != nil {
+		return err
+	}
+	if strings.TrimSpace(task.ExtraVars) == "" {
+		task.ExtraVars = "{}"
+		return nil
+	}
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(task.ExtraVars), &vars); err != nil {
+		return errors.New("额外变量必须是 JSON 对象")
+	}
+	return nil
+}
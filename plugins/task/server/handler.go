@@ -37,25 +37,44 @@ import (
 	"github.com/pkg/sftp"
 	"golang.org/x/crypto/ssh"
 	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
 	"github.com/ydcloud-dy/opshub/pkg/response"
 	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"github.com/ydcloud-dy/opshub/plugins/task/service"
 	"gorm.io/gorm"
 )
 
 type Handler struct {
 	db            *gorm.DB
 	encryptionKey []byte
+	scripts       *service.ScriptExecutor
+	ansible       *service.AnsibleRunner
+	scheduler     *service.JobScheduler
 }
 
-func NewHandler(db *gorm.DB) *Handler {
+func NewHandler(db *gorm.DB, scheduler *service.JobScheduler) *Handler {
 	// 使用与凭证仓库相同的加密密钥
 	encryptionKey := []byte("opshub-enc-key-32-bytes-long!!!!")
 	return &Handler{
 		db:            db,
 		encryptionKey: encryptionKey,
+		scripts:       service.NewScriptExecutor(db),
+		ansible:       service.NewAnsibleRunner(db),
+		scheduler:     scheduler,
 	}
 }
 
+// currentUserID 从JWT中获取当前用户ID，作为新建数据的创建人
+func currentUserID(c *gin.Context) uint {
+	var createdBy uint = 1
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uint); ok {
+			createdBy = uid
+		}
+	}
+	return createdBy
+}
+
 // ==================== 任务作业 ====================
 
 // ListJobTasks 获取任务作业列表
@@ -82,7 +101,9 @@ func (h *Handler) ListJobTasks(c *gin.Context) {
 	var jobTasks []*model.JobTask
 	var total int64
 
-	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL")
+	// 数据权限：只返回可见部门内用户创建的任务
+	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL").
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by"))
 
 	if keyword != "" {
 		query = query.Where("name LIKE ?", "%"+keyword+"%")
@@ -145,7 +166,7 @@ func (h *Handler) CreateJobTask(c *gin.Context) {
 		return
 	}
 	jobTask.Status = "pending"
-	jobTask.CreatedBy = 1 // TODO: 从JWT获取
+	jobTask.CreatedBy = currentUserID(c)
 	if err := h.db.Create(&jobTask).Error; err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "创建失败")
 		return
@@ -172,10 +193,13 @@ func (h *Handler) UpdateJobTask(c *gin.Context) {
 		response.ErrorCode(c, http.StatusNotFound, "任务不存在")
 		return
 	}
+	// 创建人决定数据权限归属，不随编辑修改
+	createdBy := jobTask.CreatedBy
 	if err := c.ShouldBindJSON(&jobTask); err != nil {
 		response.ErrorCode(c, http.StatusBadRequest, "参数错误")
 		return
 	}
+	jobTask.CreatedBy = createdBy
 	h.db.Save(&jobTask)
 	response.Success(c, jobTask)
 }
@@ -300,7 +324,7 @@ func (h *Handler) CreateJobTemplate(c *gin.Context) {
 		return
 	}
 	template.Status = 1
-	template.CreatedBy = 1
+	template.CreatedBy = currentUserID(c)
 	// 处理空的 variables 字段，MySQL JSON 字段不能为空字符串
 	if template.Variables == "" {
 		template.Variables = "[]"
@@ -327,14 +351,19 @@ func (h *Handler) CreateJobTemplate(c *gin.Context) {
 func (h *Handler) UpdateJobTemplate(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
 	var template model.JobTemplate
-	if err := h.db.Where("id = ? AND deleted_at IS NULL", id).First(&template).Error; err != nil {
+	if err := h.db.Where("id = ? AND deleted_at IS NULL", id).
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by")).
+		First(&template).Error; err != nil {
 		response.ErrorCode(c, http.StatusNotFound, "模板不存在")
 		return
 	}
+	// 创建人决定数据权限归属，不随编辑修改
+	templateID, createdBy := template.ID, template.CreatedBy
 	if err := c.ShouldBindJSON(&template); err != nil {
 		response.ErrorCode(c, http.StatusBadRequest, "参数错误")
 		return
 	}
+	template.ID, template.CreatedBy = templateID, createdBy
 	h.db.Save(&template)
 	response.Success(c, template)
 }
@@ -430,6 +459,7 @@ func (h *Handler) GetAnsibleTask(c *gin.Context) {
 // @Param body body model.AnsibleTask true "任务信息"
 // @Success 200 {object} response.Response "创建成功"
 // @Failure 400 {object} response.Response "参数错误"
+// @Failure 403 {object} response.Response "只有管理员可以使用内联 Playbook"
 // @Router /task/ansible-tasks [post]
 func (h *Handler) CreateAnsibleTask(c *gin.Context) {
 	var ansibleTask model.AnsibleTask
@@ -447,8 +477,15 @@ func (h *Handler) CreateAnsibleTask(c *gin.Context) {
 	if ansibleTask.Verbose == "" {
 		ansibleTask.Verbose = "v"
 	}
-	ansibleTask.CreatedBy = 1
-	if err := h.db.Create(&ansibleTask).Error; err != nil {
+	if err := normalizeAnsibleTask(&ansibleTask); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ansibleTask.CreatedBy = currentUserID(c)
+	if !h.checkInlinePlaybook(c, &ansibleTask) {
+		return
+	}
+	if err := h.db.Omit("last_run_result").Create(&ansibleTask).Error; err != nil {
 		response.ErrorCode(c, http.StatusInternalServerError, "创建失败")
 		return
 	}
@@ -465,6 +502,7 @@ func (h *Handler) CreateAnsibleTask(c *gin.Context) {
 // @Param id path int true "任务ID"
 // @Param body body model.AnsibleTask true "任务信息"
 // @Success 200 {object} response.Response "更新成功"
+// @Failure 403 {object} response.Response "只有管理员可以使用内联 Playbook"
 // @Failure 404 {object} response.Response "任务不存在"
 // @Router /task/ansible-tasks/{id} [put]
 func (h *Handler) UpdateAnsibleTask(c *gin.Context) {
@@ -474,11 +512,21 @@ func (h *Handler) UpdateAnsibleTask(c *gin.Context) {
 		response.ErrorCode(c, http.StatusNotFound, "任务不存在")
 		return
 	}
+	taskID := ansibleTask.ID
 	if err := c.ShouldBindJSON(&ansibleTask); err != nil {
 		response.ErrorCode(c, http.StatusBadRequest, "参数错误")
 		return
 	}
-	h.db.Save(&ansibleTask)
+	ansibleTask.ID = taskID
+	if err := normalizeAnsibleTask(&ansibleTask); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !h.checkInlinePlaybook(c, &ansibleTask) {
+		return
+	}
+	// 执行状态由执行器维护，不随编辑覆盖
+	h.db.Omit("status", "last_run_time", "last_run_result", "created_by", "created_at").Save(&ansibleTask)
 	response.Success(c, ansibleTask)
 }
 
@@ -543,7 +591,7 @@ func (h *Handler) ExecuteTask(c *gin.Context) {
 	}
 
 	// 安全检查：检查命令是否包含危险操作
-	if err := h.checkCommandSafety(req.Content); err != nil {
+	if err := CheckCommandSafety(req.Content); err != nil {
 		response.ErrorCode(c, http.StatusForbidden, err.Error())
 		return
 	}
@@ -608,8 +656,8 @@ func (h *Handler) ExecuteTask(c *gin.Context) {
 	})
 }
 
-// checkCommandSafety 检查命令安全性
-func (h *Handler) checkCommandSafety(content string) error {
+// CheckCommandSafety 检查命令安全性
+func CheckCommandSafety(content string) error {
 	// 转换为小写以便不区分大小写检查
 	contentLower := strings.ToLower(content)
 
@@ -951,75 +999,7 @@ func (h *Handler) checkCommandSafety(content string) error {
 
 // executeOnHost 在单个主机上执行任务
 func (h *Handler) executeOnHost(ctx context.Context, hostID uint, scriptType, content string) HostExecutionResult {
-	result := HostExecutionResult{
-		HostID: hostID,
-		Status: "failed",
-	}
-
-	// 获取主机信息
-	var host assetbiz.Host
-	if err := h.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", hostID).First(&host).Error; err != nil {
-		result.Error = fmt.Sprintf("获取主机信息失败: %v", err)
-		return result
-	}
-
-	result.HostName = host.Name
-	result.HostIP = host.IP
-
-	// 获取凭证
-	if host.CredentialID == 0 {
-		result.Error = "主机未配置凭证"
-		return result
-	}
-
-	var credential assetbiz.Credential
-	if err := h.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", host.CredentialID).First(&credential).Error; err != nil {
-		result.Error = fmt.Sprintf("获取凭证失败: %v", err)
-		return result
-	}
-
-	// 解密凭证
-	if err := h.decryptCredential(&credential); err != nil {
-		result.Error = fmt.Sprintf("解密凭证失败: %v", err)
-		return result
-	}
-
-	// 建立SSH连接
-	sshClient, err := h.createSSHClient(&host, &credential)
-	if err != nil {
-		result.Error = fmt.Sprintf("SSH连接失败: %v", err)
-		return result
-	}
-	defer sshClient.Close()
-
-	// 创建SSH会话
-	session, err := sshClient.NewSession()
-	if err != nil {
-		result.Error = fmt.Sprintf("创建SSH会话失败: %v", err)
-		return result
-	}
-	defer session.Close()
-
-	// 根据脚本类型构造执行命令
-	var cmd string
-	if scriptType == "Python" {
-		cmd = fmt.Sprintf("python3 -c %s", shellescape(content))
-	} else {
-		// Shell 脚本
-		cmd = content
-	}
-
-	// 执行命令
-	output, err := session.CombinedOutput(cmd)
-	if err != nil {
-		result.Error = fmt.Sprintf("执行失败: %v", err)
-		result.Output = string(output)
-		return result
-	}
-
-	result.Status = "success"
-	result.Output = string(output)
-	return result
+	return HostExecutionResult(h.scripts.Execute(ctx, hostID, scriptType, content))
 }
 
 // createSSHClient 创建SSH客户端
@@ -1116,11 +1096,6 @@ func (h *Handler) decrypt(ciphertext string) (string, error) {
 	return string(plaintext), nil
 }
 
-// shellescape 转义shell命令
-func shellescape(s string) string {
-	return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
-}
-
 // ptrTime 返回时间指针
 func ptrTime(t time.Time) *time.Time {
 	return &t
@@ -1156,7 +1131,9 @@ func (h *Handler) ListExecutionHistory(c *gin.Context) {
 	var jobTasks []model.JobTask
 	var total int64
 
-	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL")
+	// 数据权限：只返回可见部门内用户创建的任务
+	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL").
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by"))
 
 	// 关键词搜索
 	if keyword != "" {
@@ -1180,7 +1157,7 @@ func (h *Handler) ListExecutionHistory(c *gin.Context) {
 
 	query.Count(&total)
 	offset := (page - 1) * pageSize
-	query.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&jobTasks)
+	query.Omit("output").Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&jobTasks)
 
 	// 获取用户信息
 	type UserInfo struct {
@@ -1407,12 +1384,14 @@ func (h *Handler) ExportExecutionHistory(c *gin.Context) {
 	c.ShouldBindJSON(&req)
 
 	var jobTasks []model.JobTask
-	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL")
+	// 数据权限：只返回可见部门内用户创建的任务
+	query := h.db.Model(&model.JobTask{}).Where("deleted_at IS NULL").
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by"))
 
 	if len(req.IDs) > 0 {
 		query = query.Where("id IN ?", req.IDs)
 	}
-	query.Order("created_at DESC").Find(&jobTasks)
+	query.Omit("output").Order("created_at DESC").Find(&jobTasks)
 
 	// 获取用户信息
 	type UserInfo struct {
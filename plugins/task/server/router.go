@@ -22,11 +22,12 @@ package server
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"github.com/ydcloud-dy/opshub/plugins/task/service"
 	"gorm.io/gorm"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, db *gorm.DB) {
-	handler := NewHandler(db)
+func RegisterRoutes(router *gin.RouterGroup, db *gorm.DB, scheduler *service.JobScheduler) {
+	handler := NewHandler(db, scheduler)
 
 	// 任务插件路由组 - 使用 /task 前缀
 	taskGroup := router.Group("/task")
@@ -66,6 +67,24 @@ func RegisterRoutes(router *gin.RouterGroup, db *gorm.DB) {
 			ansible.POST("", handler.CreateAnsibleTask)
 			ansible.PUT("/:id", handler.UpdateAnsibleTask)
 			ansible.DELETE("/:id", handler.DeleteAnsibleTask)
+			ansible.POST("/:id/run", handler.RunAnsibleTask)
+			ansible.POST("/runs/:jobId/cancel", handler.CancelAnsibleRun)
+			ansible.GET("/runs/:jobId/log", handler.StreamAnsibleRunLog)
+		}
+
+		// 定时任务
+		schedules := taskGroup.Group("/schedules")
+		{
+			schedules.GET("", handler.ListJobSchedules)
+			schedules.GET("/preview", handler.PreviewJobSchedule)
+			schedules.GET("/:id", handler.GetJobSchedule)
+			schedules.POST("", handler.CreateJobSchedule)
+			schedules.PUT("/:id", handler.UpdateJobSchedule)
+			schedules.DELETE("/:id", handler.DeleteJobSchedule)
+			schedules.POST("/:id/enable", handler.EnableJobSchedule)
+			schedules.POST("/:id/pause", handler.PauseJobSchedule)
+			schedules.POST("/:id/run", handler.RunJobSchedule)
+			schedules.GET("/:id/runs", handler.ListJobScheduleRuns)
 		}
 
 		// 执行记录
@@ -86,5 +105,6 @@ func AutoMigrate(db *gorm.DB) error {
 		&model.JobTask{},
 		&model.JobTemplate{},
 		&model.AnsibleTask{},
+		&model.JobSchedule{},
 	)
 }
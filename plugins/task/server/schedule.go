@@ -0,0 +1,510 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/response"
+	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"github.com/ydcloud-dy/opshub/plugins/task/service"
+)
+
+// JobScheduleItem 定时任务列表项
+type JobScheduleItem struct {
+	model.JobSchedule
+	TemplateName string     `json:"templateName"`
+	NextRunTime  *time.Time `json:"nextRunTime,omitempty"`
+}
+
+// ListJobSchedules 获取定时任务列表
+// @Summary 获取定时任务列表
+// @Description 分页获取定时任务列表，返回下次执行时间
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param keyword query string false "搜索关键词"
+// @Param enabled query bool false "是否启用"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /task/schedules [get]
+func (h *Handler) ListJobSchedules(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	keyword := c.Query("keyword")
+	enabled := c.Query("enabled")
+
+	var schedules []model.JobSchedule
+	var total int64
+
+	query := h.db.Model(&model.JobSchedule{}).Where("deleted_at IS NULL").
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by"))
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if enabled != "" {
+		query = query.Where("enabled = ?", enabled == "true" || enabled == "1")
+	}
+
+	query.Count(&total)
+	offset := (page - 1) * pageSize
+	query.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&schedules)
+
+	// 模板名称
+	templateIDs := make([]uint, 0, len(schedules))
+	for _, schedule := range schedules {
+		templateIDs = append(templateIDs, schedule.TemplateID)
+	}
+	templateNames := make(map[uint]string)
+	if len(templateIDs) > 0 {
+		var templates []model.JobTemplate
+		h.db.Select("id, name").Where("id IN ?", templateIDs).Find(&templates)
+		for _, template := range templates {
+			templateNames[template.ID] = template.Name
+		}
+	}
+
+	now := time.Now()
+	list := make([]JobScheduleItem, 0, len(schedules))
+	for _, schedule := range schedules {
+		item := JobScheduleItem{JobSchedule: schedule, TemplateName: templateNames[schedule.TemplateID]}
+		if schedule.Enabled {
+			if times, err := service.NextRunTimes(schedule.CronExpr, schedule.Timezone, now, 1); err == nil && len(times) > 0 {
+				item.NextRunTime = &times[0]
+			}
+		}
+		list = append(list, item)
+	}
+
+	response.Success(c, gin.H{
+		"list":     list,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetJobSchedule 获取定时任务详情
+// @Summary 获取定时任务详情
+// @Description 获取指定定时任务的详细信息
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Router /task/schedules/{id} [get]
+func (h *Handler) GetJobSchedule(c *gin.Context) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// CreateJobSchedule 创建定时任务
+// @Summary 创建定时任务
+// @Description 创建定时任务，启用时立即加入调度
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body model.JobSchedule true "定时任务信息"
+// @Success 200 {object} response.Response "创建成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Router /task/schedules [post]
+func (h *Handler) CreateJobSchedule(c *gin.Context) {
+	var schedule model.JobSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	schedule.ID = 0
+	schedule.LastRunTime = nil
+	schedule.LastRunStatus = ""
+	schedule.CreatedBy = 1
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uint); ok {
+			schedule.CreatedBy = uid
+		}
+	}
+	if err := h.normalizeJobSchedule(c.Request.Context(), &schedule); err != nil {
+		h.scheduleError(c, err)
+		return
+	}
+	if err := h.db.Create(&schedule).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "创建失败")
+		return
+	}
+	if err := h.scheduler.Reload(&schedule); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "加入调度失败: "+err.Error())
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// UpdateJobSchedule 更新定时任务
+// @Summary 更新定时任务
+// @Description 更新定时任务配置并重新调度
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Param body body model.JobSchedule true "定时任务信息"
+// @Success 200 {object} response.Response "更新成功"
+// @Failure 400 {object} response.Response "参数错误"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Router /task/schedules/{id} [put]
+func (h *Handler) UpdateJobSchedule(c *gin.Context) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	id, createdBy := schedule.ID, schedule.CreatedBy
+	if err := c.ShouldBindJSON(schedule); err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+	schedule.ID, schedule.CreatedBy = id, createdBy
+	if err := h.normalizeJobSchedule(c.Request.Context(), schedule); err != nil {
+		h.scheduleError(c, err)
+		return
+	}
+	// 定时任务仍以创建人的权限执行，编辑人也必须有目标主机的执行权限
+	if editor := currentUserID(c); editor != createdBy {
+		asEditor := *schedule
+		asEditor.CreatedBy = editor
+		if _, err := h.scheduler.AuthorizeHosts(c.Request.Context(), &asEditor); err != nil {
+			h.scheduleError(c, err)
+			return
+		}
+	}
+	// 执行状态由调度器维护，不随编辑覆盖
+	if err := h.db.Omit("last_run_time", "last_run_status", "created_by", "created_at").Save(schedule).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "更新失败")
+		return
+	}
+	if err := h.scheduler.Reload(schedule); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "重新调度失败: "+err.Error())
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// DeleteJobSchedule 删除定时任务
+// @Summary 删除定时任务
+// @Description 删除定时任务并停止调度，已有的执行记录保留
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Router /task/schedules/{id} [delete]
+func (h *Handler) DeleteJobSchedule(c *gin.Context) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	if err := h.db.Delete(&model.JobSchedule{}, schedule.ID).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "删除失败")
+		return
+	}
+	h.scheduler.Remove(schedule.ID)
+	response.Success(c, nil)
+}
+
+// EnableJobSchedule 启用定时任务
+// @Summary 启用定时任务
+// @Description 启用定时任务并加入调度
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Success 200 {object} response.Response "启用成功"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Router /task/schedules/{id}/enable [post]
+func (h *Handler) EnableJobSchedule(c *gin.Context) {
+	h.setJobScheduleEnabled(c, true)
+}
+
+// PauseJobSchedule 暂停定时任务
+// @Summary 暂停定时任务
+// @Description 暂停定时任务，执行中的任务继续运行直到结束
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Success 200 {object} response.Response "暂停成功"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Router /task/schedules/{id}/pause [post]
+func (h *Handler) PauseJobSchedule(c *gin.Context) {
+	h.setJobScheduleEnabled(c, false)
+}
+
+// setJobScheduleEnabled 启用或暂停定时任务
+func (h *Handler) setJobScheduleEnabled(c *gin.Context, enabled bool) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	if enabled {
+		if _, err := service.ParseSchedule(schedule.CronExpr, schedule.Timezone); err != nil {
+			response.ErrorCode(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := h.db.Model(&model.JobSchedule{}).Where("id = ?", schedule.ID).Update("enabled", enabled).Error; err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "操作失败")
+		return
+	}
+	schedule.Enabled = enabled
+	if err := h.scheduler.Reload(schedule); err != nil {
+		response.ErrorCode(c, http.StatusInternalServerError, "调度失败: "+err.Error())
+		return
+	}
+	if enabled {
+		response.SuccessWithMessage(c, "已启用", schedule)
+	} else {
+		response.SuccessWithMessage(c, "已暂停", schedule)
+	}
+}
+
+// RunJobSchedule 立即执行定时任务
+// @Summary 立即执行定时任务
+// @Description 立即触发一次执行，同样遵循重叠策略
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Success 200 {object} response.Response "已开始执行"
+// @Failure 403 {object} response.Response "创建人已被禁用"
+// @Failure 404 {object} response.Response "定时任务不存在"
+// @Failure 409 {object} response.Response "上一次执行尚未结束"
+// @Router /task/schedules/{id}/run [post]
+func (h *Handler) RunJobSchedule(c *gin.Context) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	var userID uint
+	if value, exists := c.Get("user_id"); exists {
+		if uid, ok := value.(uint); ok {
+			userID = uid
+		}
+	}
+
+	job, queued, err := h.scheduler.RunNow(schedule.ID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrScheduleRunning) {
+			response.ErrorCode(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrScheduleCreatorDisabled) {
+			response.ErrorCode(c, http.StatusForbidden, err.Error())
+			return
+		}
+		response.ErrorCode(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if queued {
+		response.SuccessWithMessage(c, "上一次执行尚未结束，已排队等待执行", gin.H{"queued": true})
+		return
+	}
+	response.SuccessWithMessage(c, "已开始执行", gin.H{"jobTaskId": job.ID, "queued": false})
+}
+
+// ListJobScheduleRuns 获取定时任务的执行记录
+// @Summary 获取定时任务的执行记录
+// @Description 分页获取定时任务每次触发产生的执行记录
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "定时任务ID"
+// @Param page query int false "页码" default(1)
+// @Param pageSize query int false "每页数量" default(10)
+// @Param status query string false "执行状态"
+// @Success 200 {object} response.Response "获取成功"
+// @Router /task/schedules/{id}/runs [get]
+func (h *Handler) ListJobScheduleRuns(c *gin.Context) {
+	schedule, ok := h.findJobSchedule(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	status := c.Query("status")
+
+	var runs []model.JobTask
+	var total int64
+	query := h.db.Model(&model.JobTask{}).Where("schedule_id = ? AND deleted_at IS NULL", schedule.ID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Count(&total)
+	offset := (page - 1) * pageSize
+	query.Omit("output").Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&runs)
+
+	response.Success(c, gin.H{
+		"list":     runs,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// PreviewJobSchedule 预览执行时间
+// @Summary 预览执行时间
+// @Description 根据cron表达式和时区计算之后的执行时间
+// @Tags 任务管理-定时任务
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param cronExpr query string true "cron表达式"
+// @Param timezone query string false "时区"
+// @Param count query int false "数量" default(5)
+// @Success 200 {object} response.Response "获取成功"
+// @Failure 400 {object} response.Response "表达式无效"
+// @Router /task/schedules/preview [get]
+func (h *Handler) PreviewJobSchedule(c *gin.Context) {
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "5"))
+	if count <= 0 || count > 50 {
+		count = 5
+	}
+	times, err := service.NextRunTimes(c.Query("cronExpr"), c.Query("timezone"), time.Now(), count)
+	if err != nil {
+		response.ErrorCode(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Success(c, times)
+}
+
+// findJobSchedule 按路径中的ID获取当前用户可见的定时任务
+func (h *Handler) findJobSchedule(c *gin.Context) (*model.JobSchedule, bool) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var schedule model.JobSchedule
+	if err := h.db.Where("id = ? AND deleted_at IS NULL", id).
+		Scopes(rbacbiz.ScopeByDataPermission(c.Request.Context(), "created_by")).
+		First(&schedule).Error; err != nil {
+		response.ErrorCode(c, http.StatusNotFound, "定时任务不存在")
+		return nil, false
+	}
+	return &schedule, true
+}
+
+// normalizeJobSchedule 校验定时任务配置并填充默认值，目标主机按创建人的权限校验
+func (h *Handler) normalizeJobSchedule(ctx context.Context, schedule *model.JobSchedule) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	schedule.CronExpr = strings.TrimSpace(schedule.CronExpr)
+	schedule.Timezone = strings.TrimSpace(schedule.Timezone)
+	if _, err := service.ParseSchedule(schedule.CronExpr, schedule.Timezone); err != nil {
+		return err
+	}
+
+	if schedule.OverlapPolicy == "" {
+		schedule.OverlapPolicy = service.OverlapSkip
+	}
+	switch schedule.OverlapPolicy {
+	case service.OverlapSkip, service.OverlapQueue, service.OverlapAllow:
+	default:
+		return errors.New("重叠策略只能是 skip、queue 或 allow")
+	}
+
+	if schedule.ScriptType == "" {
+		schedule.ScriptType = "Shell"
+	}
+	if schedule.ScriptType != "Shell" && schedule.ScriptType != "Python" {
+		return errors.New("脚本类型只能是 Shell 或 Python")
+	}
+
+	if schedule.TargetType == "" {
+		schedule.TargetType = "hosts"
+	}
+	switch schedule.TargetType {
+	case "hosts":
+		var hostIDs []uint
+		if err := json.Unmarshal([]byte(schedule.TargetHosts), &hostIDs); err != nil || len(hostIDs) == 0 {
+			return errors.New("请选择目标主机")
+		}
+		schedule.GroupID = 0
+	case "group":
+		if schedule.GroupID == 0 {
+			return errors.New("请选择目标分组")
+		}
+		schedule.TargetHosts = ""
+	default:
+		return errors.New("目标类型只能是 hosts 或 group")
+	}
+
+	if strings.TrimSpace(schedule.NotifyUserIDs) != "" {
+		var userIDs []uint
+		if err := json.Unmarshal([]byte(schedule.NotifyUserIDs), &userIDs); err != nil {
+			return errors.New("通知用户格式错误，应为用户ID数组")
+		}
+	}
+
+	// 按当前模板渲染并保存脚本快照，之后修改模板需要重新保存定时任务才会生效
+	var template model.JobTemplate
+	if err := h.db.Where("id = ? AND deleted_at IS NULL", schedule.TemplateID).First(&template).Error; err != nil {
+		return errors.New("任务模板不存在")
+	}
+	values, err := service.ParseScheduleVariables(schedule.Variables)
+	if err != nil {
+		return err
+	}
+	content, err := service.RenderTemplate(&template, values)
+	if err != nil {
+		return err
+	}
+	if err := CheckCommandSafety(content); err != nil {
+		return err
+	}
+	schedule.Content = content
+	schedule.Timeout = template.Timeout
+	_, err = h.scheduler.AuthorizeHosts(ctx, schedule)
+	return err
+}
+
+// scheduleError 返回定时任务校验错误，无目标主机权限时返回 403
+func (h *Handler) scheduleError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrHostPermissionDenied) {
+		response.ErrorCode(c, http.StatusForbidden, err.Error())
+		return
+	}
+	response.ErrorCode(c, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"golang.org/x/crypto/ssh"
+)
+
+// AnsibleInventory Ansible 任务的目标配置，对应 AnsibleTask.Inventory 字段
+type AnsibleInventory struct {
+	HostIDs  []uint `json:"hostIds"`
+	GroupIDs []uint `json:"groupIds"`
+}
+
+// ParseAnsibleInventory 解析任务中保存的目标配置
+func ParseAnsibleInventory(raw string) (AnsibleInventory, error) {
+	var inventory AnsibleInventory
+	if strings.TrimSpace(raw) == "" {
+		return inventory, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &inventory); err != nil {
+		return inventory, fmt.Errorf("目标主机配置格式错误，应为 {\"hostIds\":[],\"groupIds\":[]}: %w", err)
+	}
+	return inventory, nil
+}
+
+// Empty 是否未指定任何主机或分组
+func (i AnsibleInventory) Empty() bool {
+	return len(i.HostIDs) == 0 && len(i.GroupIDs) == 0
+}
+
+// ansibleTarget 解析后的执行目标
+type ansibleTarget struct {
+	hosts  []*ansibleHost
+	groups []*ansibleGroup
+}
+
+// ansibleHost inventory 中的一台主机
+type ansibleHost struct {
+	alias string
+	host  *assetbiz.Host
+}
+
+// ansibleGroup inventory 中的一个分组，包含其所有子孙分组下的主机
+type ansibleGroup struct {
+	name    string
+	aliases []string
+}
+
+// ansibleInventoryFile 写入磁盘的 inventory 及其附带的敏感信息
+type ansibleInventoryFile struct {
+	path    string
+	env     []string
+	secrets []string
+}
+
+var invalidAnsibleName = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ansibleName 将名称转换为合法的 Ansible 主机别名或分组名
+func ansibleName(name string) string {
+	name = strings.Trim(invalidAnsibleName.ReplaceAllString(name, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return ""
+	}
+	return name
+}
+
+// resolveTarget 根据主机和分组解析出本次执行的主机列表
+func (r *AnsibleRunner) resolveTarget(ctx context.Context, inventory AnsibleInventory) (*ansibleTarget, error) {
+	if inventory.Empty() {
+		return nil, errors.New("未指定目标主机或分组")
+	}
+
+	// 分组展开到所有子孙分组
+	subtrees := make(map[uint][]uint, len(inventory.GroupIDs))
+	var groupIDs []uint
+	for _, groupID := range inventory.GroupIDs {
+		descendants, err := r.groupRepo.GetDescendantIDs(ctx, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("获取分组失败: %w", err)
+		}
+		subtrees[groupID] = append([]uint{groupID}, descendants...)
+		groupIDs = append(groupIDs, subtrees[groupID]...)
+	}
+
+	// 只保留执行者数据范围内的主机
+	var hosts []*assetbiz.Host
+	query := r.db.WithContext(ctx).Model(&assetbiz.Host{}).
+		Scopes(rbacbiz.ScopeByDataPermission(ctx, "created_by"))
+	switch {
+	case len(inventory.HostIDs) > 0 && len(groupIDs) > 0:
+		query = query.Where("id IN ? OR group_id IN ?", inventory.HostIDs, groupIDs)
+	case len(inventory.HostIDs) > 0:
+		query = query.Where("id IN ?", inventory.HostIDs)
+	default:
+		query = query.Where("group_id IN ?", groupIDs)
+	}
+	if err := query.Order("id ASC").Find(&hosts).Error; err != nil {
+		return nil, fmt.Errorf("获取主机失败: %w", err)
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New("目标主机或分组下没有可用的主机")
+	}
+
+	target := &ansibleTarget{}
+	used := make(map[string]bool, len(hosts))
+	aliasesByGroup := make(map[uint][]string)
+	for _, host := range hosts {
+		alias := ansibleName(host.Name)
+		if alias == "" {
+			alias = fmt.Sprintf("host_%d", host.ID)
+		} else if used[alias] {
+			alias = fmt.Sprintf("%s_%d", alias, host.ID)
+		}
+		used[alias] = true
+		target.hosts = append(target.hosts, &ansibleHost{alias: alias, host: host})
+		aliasesByGroup[host.GroupID] = append(aliasesByGroup[host.GroupID], alias)
+	}
+
+	if len(inventory.GroupIDs) > 0 {
+		var groups []*assetbiz.AssetGroup
+		if err := r.db.WithContext(ctx).Where("id IN ?", inventory.GroupIDs).Find(&groups).Error; err != nil {
+			return nil, fmt.Errorf("获取分组失败: %w", err)
+		}
+		for _, group := range groups {
+			name := ansibleName(group.Code)
+			if name == "" || used[name] {
+				name = fmt.Sprintf("group_%d", group.ID)
+			}
+			used[name] = true
+			item := &ansibleGroup{name: name}
+			for _, id := range subtrees[group.ID] {
+				item.aliases = append(item.aliases, aliasesByGroup[id]...)
+			}
+			target.groups = append(target.groups, item)
+		}
+	}
+	return target, nil
+}
+
+// hostIDs 返回目标主机ID列表
+func (t *ansibleTarget) hostIDs() []uint {
+	ids := make([]uint, 0, len(t.hosts))
+	for _, item := range t.hosts {
+		ids = append(ids, item.host.ID)
+	}
+	return ids
+}
+
+// writeInventory 在工作目录中生成 inventory 文件
+// 密码不落盘，通过环境变量传给 ansible-playbook；私钥以 0600 权限写入工作目录，执行结束后随目录一起删除
+func (r *AnsibleRunner) writeInventory(ctx context.Context, workDir string, target *ansibleTarget) (*ansibleInventoryFile, error) {
+	file := &ansibleInventoryFile{path: filepath.Join(workDir, "inventory.json")}
+	keyDir := filepath.Join(workDir, "keys")
+	if err := os.Mkdir(keyDir, 0o700); err != nil {
+		return nil, err
+	}
+
+	credentials := make(map[uint]*assetbiz.Credential)
+	prepared := make(map[uint]string)
+	hosts := make(map[string]interface{}, len(target.hosts))
+	for _, item := range target.hosts {
+		host := item.host
+		port := host.Port
+		if port == 0 {
+			port = 22
+		}
+		vars := map[string]interface{}{
+			"ansible_host": host.IP,
+			"ansible_port": port,
+		}
+		user := host.SSHUser
+
+		if host.CredentialID > 0 {
+			credential, ok := credentials[host.CredentialID]
+			if !ok {
+				var err error
+				credential, err = r.credentialRepo.GetByIDDecrypted(ctx, host.CredentialID)
+				if err != nil {
+					return nil, fmt.Errorf("获取主机 %s 的凭证失败: %w", host.Name, err)
+				}
+				credentials[host.CredentialID] = credential
+			}
+			if credential.Username != "" {
+				user = credential.Username
+			}
+
+			switch credential.Type {
+			case "password":
+				env := fmt.Sprintf("OPSHUB_ANSIBLE_PASSWORD_%d", credential.ID)
+				if _, ok := prepared[credential.ID]; !ok {
+					file.env = append(file.env, env+"="+credential.Password)
+					file.secrets = append(file.secrets, credential.Password)
+					prepared[credential.ID] = ""
+				}
+				vars["ansible_password"] = fmt.Sprintf("{{ lookup('env', '%s') }}", env)
+			case "key", "private_key":
+				keyFile, ok := prepared[credential.ID]
+				if !ok {
+					keyFile = filepath.Join(keyDir, fmt.Sprintf("credential_%d", credential.ID))
+					if err := writePrivateKey(keyFile, credential); err != nil {
+						return nil, fmt.Errorf("写入主机 %s 的私钥失败: %w", host.Name, err)
+					}
+					if credential.Passphrase != "" {
+						file.secrets = append(file.secrets, credential.Passphrase)
+					}
+					prepared[credential.ID] = keyFile
+				}
+				vars["ansible_ssh_private_key_file"] = keyFile
+			default:
+				return nil, fmt.Errorf("主机 %s 的凭证类型 %s 不支持", host.Name, credential.Type)
+			}
+		}
+		if user != "" {
+			vars["ansible_user"] = user
+		}
+		hosts[item.alias] = vars
+	}
+
+	children := make(map[string]interface{}, len(target.groups))
+	for _, group := range target.groups {
+		members := make(map[string]interface{}, len(group.aliases))
+		for _, alias := range group.aliases {
+			members[alias] = map[string]interface{}{}
+		}
+		children[group.name] = map[string]interface{}{"hosts": members}
+	}
+
+	all := map[string]interface{}{"hosts": hosts}
+	if len(children) > 0 {
+		all["children"] = children
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{"all": all}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file.path, data, 0o600); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// writePrivateKey 写入私钥文件，带密码的私钥先解密，避免 ssh 交互式询问密码
+func writePrivateKey(path string, credential *assetbiz.Credential) error {
+	data := []byte(credential.PrivateKey)
+	if credential.Passphrase != "" {
+		key, err := ssh.ParseRawPrivateKeyWithPassphrase(data, []byte(credential.Passphrase))
+		if err != nil {
+			return fmt.Errorf("解析私钥失败: %w", err)
+		}
+		block, err := ssh.MarshalPrivateKey(key, "")
+		if err != nil {
+			return fmt.Errorf("转换私钥失败: %w", err)
+		}
+		data = pem.EncodeToMemory(block)
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AnsibleHostStats PLAY RECAP 中单台主机的统计
+type AnsibleHostStats struct {
+	Ok          int `json:"ok"`
+	Changed     int `json:"changed"`
+	Unreachable int `json:"unreachable"`
+	Failed      int `json:"failed"`
+	Skipped     int `json:"skipped"`
+	Rescued     int `json:"rescued"`
+	Ignored     int `json:"ignored"`
+}
+
+// add 累加统计
+func (s *AnsibleHostStats) add(other *AnsibleHostStats) {
+	s.Ok += other.Ok
+	s.Changed += other.Changed
+	s.Unreachable += other.Unreachable
+	s.Failed += other.Failed
+	s.Skipped += other.Skipped
+	s.Rescued += other.Rescued
+	s.Ignored += other.Ignored
+}
+
+var (
+	// web1 : ok=2 changed=1 unreachable=0 failed=0 skipped=0 rescued=0 ignored=0
+	ansibleRecapLine = regexp.MustCompile(`^(\S+)\s*:\s*ok=(\d+)\s+changed=(\d+)\s+unreachable=(\d+)\s+failed=(\d+)(?:\s+skipped=(\d+))?(?:\s+rescued=(\d+))?(?:\s+ignored=(\d+))?`)
+	// TASK [name] ****、PLAY [name] ****、RUNNING HANDLER [name] ****
+	ansibleHeaderLine = regexp.MustCompile(`^(?:PLAY|TASK|RUNNING HANDLER) \[.*\]`)
+	// ok: [web1]、changed: [web1] => (item=x)、fatal: [web1 -> localhost]: FAILED! => {...}
+	ansibleHostLine = regexp.MustCompile(`^[a-zA-Z]+: \[([^\]]+)\]`)
+	// 详细模式下的连接日志 <web1> ESTABLISH SSH CONNECTION ...
+	ansibleVerboseLine = regexp.MustCompile(`^<([^>]+)>`)
+)
+
+// parseAnsibleRecap 解析 PLAY RECAP 中每台主机的统计，没有 RECAP 时返回 nil
+func parseAnsibleRecap(output string) map[string]*AnsibleHostStats {
+	var stats map[string]*AnsibleHostStats
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), maxAnsibleOutput)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "PLAY RECAP") {
+			stats = make(map[string]*AnsibleHostStats)
+			continue
+		}
+		if stats == nil {
+			continue
+		}
+		match := ansibleRecapLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		values := make([]int, 7)
+		for i := range values {
+			values[i], _ = strconv.Atoi(match[i+2])
+		}
+		stats[match[1]] = &AnsibleHostStats{
+			Ok:          values[0],
+			Changed:     values[1],
+			Unreachable: values[2],
+			Failed:      values[3],
+			Skipped:     values[4],
+			Rescued:     values[5],
+			Ignored:     values[6],
+		}
+	}
+	return stats
+}
+
+// splitAnsibleOutput 按主机拆分输出，每台主机的输出带上所属的 TASK 标题
+func splitAnsibleOutput(output string, aliases map[string]bool) map[string]string {
+	builders := make(map[string]*strings.Builder)
+	lastHeader := make(map[string]string)
+	write := func(alias, header, line string) {
+		builder, ok := builders[alias]
+		if !ok {
+			builder = &strings.Builder{}
+			builders[alias] = builder
+		}
+		if header != "" && lastHeader[alias] != header {
+			builder.WriteString(header)
+			builder.WriteByte('\n')
+			lastHeader[alias] = header
+		}
+		builder.WriteString(line)
+		builder.WriteByte('\n')
+	}
+	hostOf := func(name string) string {
+		if i := strings.Index(name, " -> "); i >= 0 {
+			name = name[:i]
+		}
+		if aliases[name] {
+			return name
+		}
+		return ""
+	}
+
+	var header, current string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), maxAnsibleOutput)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "PLAY RECAP"):
+			current = ""
+			header = ""
+		case ansibleHeaderLine.MatchString(trimmed):
+			header = strings.TrimRight(trimmed, "* ")
+			current = ""
+		case trimmed == "":
+			current = ""
+		default:
+			if match := ansibleHostLine.FindStringSubmatch(trimmed); match != nil {
+				current = hostOf(match[1])
+				if current != "" {
+					write(current, header, line)
+				}
+			} else if match := ansibleVerboseLine.FindStringSubmatch(trimmed); match != nil {
+				if alias := hostOf(match[1]); alias != "" {
+					write(alias, header, line)
+				}
+			} else if current != "" {
+				write(current, header, line)
+			}
+		}
+	}
+
+	outputs := make(map[string]string, len(builders))
+	for alias, builder := range builders {
+		outputs[alias] = builder.String()
+	}
+	return outputs
+}
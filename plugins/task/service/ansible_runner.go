@@ -0,0 +1,842 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	assetdata "github.com/ydcloud-dy/opshub/internal/data/asset"
+	rbacdata "github.com/ydcloud-dy/opshub/internal/data/rbac"
+	"github.com/ydcloud-dy/opshub/pkg/logger"
+	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultAnsibleTimeout 任务未配置超时时间时的默认值（秒）
+	defaultAnsibleTimeout = 600
+	// maxAnsibleOutput 单次执行保留的最大输出
+	maxAnsibleOutput = 8 << 20
+	// ansibleRunRetention 执行结束后在内存中保留输出的时间，供日志流读取剩余内容
+	ansibleRunRetention = time.Minute
+	// ansibleWaitDelay 超时或取消后等待子进程退出的时间
+	ansibleWaitDelay = 10 * time.Second
+)
+
+// ErrAnsibleTaskRunning 任务正在执行中
+var ErrAnsibleTaskRunning = errors.New("该 Ansible 任务正在执行中，请等待执行结束")
+
+// ErrAnsibleRunNotFound 执行不存在或已结束
+var ErrAnsibleRunNotFound = errors.New("执行不存在或已结束")
+
+// ErrInlinePlaybookDenied 内联 Playbook 在服务端进程中执行，可以访问控制节点上的文件和命令，仅限管理员使用
+var ErrInlinePlaybookDenied = errors.New("只有管理员可以使用内联 Playbook，请改用 Playbook 目录中的文件")
+
+// AnsibleRunOptions 单次执行参数，未设置的字段使用任务自身的配置
+type AnsibleRunOptions struct {
+	Name      string
+	TaskType  string
+	Inventory *AnsibleInventory
+	Tags      string
+	Limit     string
+	ExtraVars map[string]interface{}
+	Check     bool
+	CreatedBy uint
+}
+
+// AnsibleHostResult 单台主机的执行结果，字段与脚本执行的主机结果保持一致
+type AnsibleHostResult struct {
+	HostID   uint              `json:"hostId"`
+	HostName string            `json:"hostName"`
+	HostIP   string            `json:"hostIp"`
+	Alias    string            `json:"alias"`
+	Status   string            `json:"status"` // success, failed, skipped
+	Output   string            `json:"output,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Stats    *AnsibleHostStats `json:"stats,omitempty"`
+}
+
+// AnsibleRunResult 一次执行的结果，保存到 AnsibleTask.LastRunResult
+type AnsibleRunResult struct {
+	JobTaskID  uint                 `json:"jobTaskId"`
+	Status     string               `json:"status"` // success, failed, cancelled
+	ReturnCode int                  `json:"returnCode"`
+	Error      string               `json:"error,omitempty"`
+	StartedAt  time.Time            `json:"startedAt"`
+	FinishedAt time.Time            `json:"finishedAt"`
+	Duration   int64                `json:"duration"` // 毫秒
+	Hosts      []*AnsibleHostResult `json:"hosts"`
+	Totals     AnsibleHostStats     `json:"totals"`
+}
+
+// AnsibleRunner 调用 ansible-playbook 执行 Ansible 任务
+// 每次执行使用独立的临时工作目录，结束后删除；执行中的输出保存在内存中供实时读取
+type AnsibleRunner struct {
+	db             *gorm.DB
+	credentialRepo assetbiz.CredentialRepo
+	groupRepo      assetbiz.AssetGroupRepo
+	hosts          *HostAuthorizer
+	roleRepo       rbacbiz.RoleRepo
+	binary         string
+	workRoot       string
+	playbookDir    string
+	knownHosts     string
+
+	mu      sync.Mutex
+	runs    map[uint]*AnsibleRun // 执行记录ID -> 执行
+	running map[uint]uint        // Ansible 任务ID -> 执行记录ID
+}
+
+// NewAnsibleRunner 创建执行器，可通过环境变量调整：
+// OPSHUB_ANSIBLE_PLAYBOOK ansible-playbook 可执行文件，默认从 PATH 查找；
+// OPSHUB_ANSIBLE_WORK_DIR 临时工作目录的根目录，默认系统临时目录下的 opshub-ansible；
+// OPSHUB_ANSIBLE_PLAYBOOK_DIR 任务中 playbookPath 的根目录，默认 data/ansible/playbooks；
+// OPSHUB_ANSIBLE_KNOWN_HOSTS 目标主机公钥的 known_hosts 文件，默认 data/ansible/known_hosts
+func NewAnsibleRunner(db *gorm.DB) *AnsibleRunner {
+	binary := os.Getenv("OPSHUB_ANSIBLE_PLAYBOOK")
+	if binary == "" {
+		binary = "ansible-playbook"
+	}
+	workRoot := os.Getenv("OPSHUB_ANSIBLE_WORK_DIR")
+	if workRoot == "" {
+		workRoot = filepath.Join(os.TempDir(), "opshub-ansible")
+	}
+	playbookDir := os.Getenv("OPSHUB_ANSIBLE_PLAYBOOK_DIR")
+	if playbookDir == "" {
+		playbookDir = filepath.Join("data", "ansible", "playbooks")
+	}
+	if abs, err := filepath.Abs(playbookDir); err == nil {
+		playbookDir = abs
+	}
+	knownHosts := os.Getenv("OPSHUB_ANSIBLE_KNOWN_HOSTS")
+	if knownHosts == "" {
+		knownHosts = filepath.Join("data", "ansible", "known_hosts")
+	}
+
+	r := &AnsibleRunner{
+		db:             db,
+		credentialRepo: assetdata.NewCredentialRepo(db),
+		groupRepo:      assetdata.NewAssetGroupRepo(db),
+		hosts:          NewHostAuthorizer(db),
+		roleRepo:       rbacdata.NewRoleRepo(db),
+		binary:         binary,
+		workRoot:       workRoot,
+		playbookDir:    playbookDir,
+		knownHosts:     knownHosts,
+		runs:           make(map[uint]*AnsibleRun),
+		running:        make(map[uint]uint),
+	}
+	r.recoverInterrupted()
+	return r
+}
+
+// recoverInterrupted 服务重启前未结束的执行已无法继续，标记为失败
+func (r *AnsibleRunner) recoverInterrupted() {
+	const message = "服务重启，执行已中断"
+	if err := r.db.Model(&model.JobTask{}).
+		Where("task_type = ? AND status = ?", "ansible", "running").
+		Updates(map[string]interface{}{"status": "failed", "error_message": message}).Error; err != nil {
+		logger.Warn("恢复中断的 Ansible 执行记录失败", zap.Error(err))
+	}
+	if err := r.db.Model(&model.AnsibleTask{}).
+		Where("status = ?", "running").
+		Update("status", "failed").Error; err != nil {
+		logger.Warn("恢复中断的 Ansible 任务状态失败", zap.Error(err))
+	}
+}
+
+// Start 创建执行记录并在后台执行任务，返回的执行可用于读取实时输出
+func (r *AnsibleRunner) Start(ctx context.Context, task *model.AnsibleTask, opts AnsibleRunOptions) (*model.JobTask, *AnsibleRun, error) {
+	inventory := opts.Inventory
+	if inventory == nil || inventory.Empty() {
+		parsed, err := ParseAnsibleInventory(task.Inventory)
+		if err != nil {
+			return nil, nil, err
+		}
+		inventory = &parsed
+	}
+
+	admin, err := r.isAdmin(ctx, opts.CreatedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !admin && HasInlinePlaybook(task) {
+		return nil, nil, ErrInlinePlaybookDenied
+	}
+	if strings.HasPrefix(strings.TrimSpace(opts.Limit), "@") {
+		return nil, nil, errors.New("执行范围不支持从文件读取主机列表")
+	}
+
+	playbook := ""
+	if !HasInlinePlaybook(task) {
+		if task.PlaybookPath == "" {
+			return nil, nil, errors.New("任务未配置 Playbook")
+		}
+		var err error
+		if playbook, err = r.playbookFile(task.PlaybookPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	vars, err := mergeExtraVars(task.ExtraVars, opts.ExtraVars)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 非管理员传入的变量值标记为 !unsafe，避免通过 {{ lookup('pipe', ...) }} 等模板在控制节点上执行命令
+	extraVars := encodeExtraVars(vars, !admin)
+
+	target, err := r.resolveTarget(ctx, *inventory)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.hosts.Authorize(ctx, opts.CreatedBy, target.hostIDs()); err != nil {
+		return nil, nil, err
+	}
+
+	// 同一任务同时只允许一个执行
+	r.mu.Lock()
+	if _, ok := r.running[task.ID]; ok {
+		r.mu.Unlock()
+		return nil, nil, ErrAnsibleTaskRunning
+	}
+	r.running[task.ID] = 0
+	r.mu.Unlock()
+
+	tags := opts.Tags
+	if tags == "" {
+		tags = task.Tags
+	}
+	name := opts.Name
+	if name == "" {
+		name = fmt.Sprintf("Ansible: %s", task.Name)
+	}
+	taskType := opts.TaskType
+	if taskType == "" {
+		taskType = "ansible"
+	}
+
+	hostIDs, _ := json.Marshal(target.hostIDs())
+	parameters, _ := json.Marshal(map[string]interface{}{
+		"ansibleTaskId": task.ID,
+		"hostIds":       inventory.HostIDs,
+		"groupIds":      inventory.GroupIDs,
+		"tags":          tags,
+		"limit":         opts.Limit,
+		"check":         opts.Check,
+	})
+	now := time.Now()
+	job := &model.JobTask{
+		Name:        name,
+		TaskType:    taskType,
+		Status:      "running",
+		TargetHosts: string(hostIDs),
+		Parameters:  string(parameters),
+		Result:      "[]",
+		ExecuteTime: &now,
+		CreatedBy:   opts.CreatedBy,
+	}
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		r.release(task.ID, 0)
+		return nil, nil, fmt.Errorf("创建执行记录失败: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Model(&model.AnsibleTask{}).Where("id = ?", task.ID).
+		Updates(map[string]interface{}{"status": "running", "last_run_time": now}).Error; err != nil {
+		logger.Warn("更新 Ansible 任务状态失败", zap.Uint("taskId", task.ID), zap.Error(err))
+	}
+
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = defaultAnsibleTimeout
+	}
+	runCtx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	run := newAnsibleRun(job.ID, task.ID, cancel)
+
+	r.mu.Lock()
+	r.running[task.ID] = job.ID
+	r.runs[job.ID] = run
+	r.mu.Unlock()
+
+	args := r.playbookArgs(task, tags, opts)
+	go r.execute(runCtx, run, task, target, playbook, extraVars, args, timeout)
+	return job, run, nil
+}
+
+// Run 获取执行中或刚结束的执行，不存在时返回 nil
+func (r *AnsibleRunner) Run(jobTaskID uint) *AnsibleRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runs[jobTaskID]
+}
+
+// Cancel 取消执行
+func (r *AnsibleRunner) Cancel(jobTaskID uint) error {
+	run := r.Run(jobTaskID)
+	if run == nil || !run.cancelRun() {
+		return ErrAnsibleRunNotFound
+	}
+	return nil
+}
+
+// release 释放任务的执行占用，执行记录在保留期后从内存移除
+func (r *AnsibleRunner) release(taskID, jobTaskID uint) {
+	r.mu.Lock()
+	delete(r.running, taskID)
+	r.mu.Unlock()
+	if jobTaskID == 0 {
+		return
+	}
+	time.AfterFunc(ansibleRunRetention, func() {
+		r.mu.Lock()
+		delete(r.runs, jobTaskID)
+		r.mu.Unlock()
+	})
+}
+
+// execute 在临时工作目录中执行 ansible-playbook 并保存结果
+func (r *AnsibleRunner) execute(ctx context.Context, run *AnsibleRun, task *model.AnsibleTask, target *ansibleTarget, playbook string, extraVars []byte, args []string, timeout int) {
+	defer run.cancel()
+	defer r.release(task.ID, run.JobTaskID)
+
+	result := &AnsibleRunResult{JobTaskID: run.JobTaskID, StartedAt: time.Now(), ReturnCode: -1}
+	runErr := r.runPlaybook(ctx, run, task, target, playbook, extraVars, args)
+	run.flush()
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt).Milliseconds()
+
+	var exitErr *exec.ExitError
+	switch {
+	case runErr == nil:
+		result.Status = "success"
+		result.ReturnCode = 0
+	case run.isCancelled():
+		result.Status = "cancelled"
+		result.Error = "执行已取消"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("执行超时（超过 %d 秒）", timeout)
+	case errors.As(runErr, &exitErr):
+		result.Status = "failed"
+		result.ReturnCode = exitErr.ExitCode()
+		result.Error = ansibleExitMessage(result.ReturnCode)
+	default:
+		result.Status = "failed"
+		result.Error = runErr.Error()
+	}
+
+	if result.Error != "" {
+		fmt.Fprintf(run, "\n%s\n", result.Error)
+	}
+
+	output := run.Output()
+	r.collectHosts(result, target, output)
+	r.save(task.ID, result, output)
+	run.finish(result)
+}
+
+// runPlaybook 准备工作目录并执行 ansible-playbook
+func (r *AnsibleRunner) runPlaybook(ctx context.Context, run *AnsibleRun, task *model.AnsibleTask, target *ansibleTarget, playbook string, extraVars []byte, args []string) error {
+	if err := os.MkdirAll(r.workRoot, 0o700); err != nil {
+		return fmt.Errorf("创建工作目录失败: %w", err)
+	}
+	workDir, err := os.MkdirTemp(r.workRoot, fmt.Sprintf("job-%d-", run.JobTaskID))
+	if err != nil {
+		return fmt.Errorf("创建工作目录失败: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	knownHosts, err := r.writeKnownHosts(workDir)
+	if err != nil {
+		return err
+	}
+	inventory, err := r.writeInventory(ctx, workDir, target)
+	if err != nil {
+		return err
+	}
+	run.setSecrets(inventory.secrets)
+
+	if playbook == "" {
+		playbook = filepath.Join(workDir, "playbook.yml")
+		if err := os.WriteFile(playbook, []byte(task.PlaybookContent), 0o600); err != nil {
+			return fmt.Errorf("写入 Playbook 失败: %w", err)
+		}
+	}
+
+	args = append([]string{"-i", inventory.path}, args...)
+	if len(extraVars) > 0 {
+		varsFile := filepath.Join(workDir, "extra_vars.yml")
+		if err := os.WriteFile(varsFile, extraVars, 0o600); err != nil {
+			return fmt.Errorf("写入额外变量失败: %w", err)
+		}
+		args = append(args, "--extra-vars", "@"+varsFile)
+	}
+	args = append(args, playbook)
+
+	cmd := exec.CommandContext(ctx, r.binary, args...)
+	cmd.Dir = workDir
+	cmd.Env = r.environ(workDir, knownHosts, inventory.env)
+	cmd.Stdout = run
+	cmd.Stderr = run
+	cmd.WaitDelay = ansibleWaitDelay
+	fmt.Fprintf(run, "$ %s %s\n\n", r.binary, strings.Join(args, " "))
+	return cmd.Run()
+}
+
+// playbookArgs 根据任务配置生成 ansible-playbook 参数
+func (r *AnsibleRunner) playbookArgs(task *model.AnsibleTask, tags string, opts AnsibleRunOptions) []string {
+	var args []string
+	if task.Fork > 0 {
+		args = append(args, "--forks", strconv.Itoa(task.Fork))
+	}
+	switch task.Verbose {
+	case "v", "vv", "vvv", "vvvv":
+		args = append(args, "-"+task.Verbose)
+	}
+	if tags = strings.Join(splitTags(tags), ","); tags != "" {
+		args = append(args, "--tags", tags)
+	}
+	if opts.Limit != "" {
+		args = append(args, "--limit", opts.Limit)
+	}
+	if opts.Check {
+		args = append(args, "--check", "--diff")
+	}
+	return args
+}
+
+// environ 构造子进程环境变量，只继承必要的变量，不继承服务端的 ANSIBLE_* 配置，密码仅通过环境变量传递
+func (r *AnsibleRunner) environ(workDir, knownHosts string, secrets []string) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		switch key {
+		case "PATH", "HOME", "USER", "LANG", "LC_ALL", "SSH_AUTH_SOCK":
+			env = append(env, kv)
+		}
+	}
+	env = append(env,
+		"ANSIBLE_HOST_KEY_CHECKING=True",
+		"ANSIBLE_SSH_ARGS=-C -o ControlMaster=auto -o ControlPersist=60s -o StrictHostKeyChecking=yes -o UserKnownHostsFile="+knownHosts,
+		"ANSIBLE_NOCOLOR=True",
+		"ANSIBLE_FORCE_COLOR=False",
+		"ANSIBLE_RETRY_FILES_ENABLED=False",
+		"ANSIBLE_STDOUT_CALLBACK=default",
+		"ANSIBLE_LOCAL_TEMP="+filepath.Join(workDir, ".ansible", "tmp"),
+		"ANSIBLE_SSH_CONTROL_PATH_DIR="+filepath.Join(workDir, ".ansible", "cp"),
+		"PYTHONUNBUFFERED=1",
+	)
+	return append(env, secrets...)
+}
+
+// writeKnownHosts 将配置的 known_hosts 复制到工作目录，只信任其中登记过公钥的主机；
+// 文件不存在时写入空文件，此时所有主机都会因主机密钥校验失败而不可达
+func (r *AnsibleRunner) writeKnownHosts(workDir string) (string, error) {
+	data, err := os.ReadFile(r.knownHosts)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("读取 known_hosts 失败: %w", err)
+	}
+	path := filepath.Join(workDir, "known_hosts")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("写入 known_hosts 失败: %w", err)
+	}
+	return path, nil
+}
+
+// playbookFile 解析 playbookPath，限制在 Playbook 根目录内
+func (r *AnsibleRunner) playbookFile(path string) (string, error) {
+	full := filepath.Join(r.playbookDir, filepath.Clean("/"+path))
+	info, err := os.Stat(full)
+	if err != nil || info.IsDir() {
+		return "", fmt.Errorf("Playbook 文件 %s 不存在", path)
+	}
+	return full, nil
+}
+
+// collectHosts 根据 PLAY RECAP 和输出生成每台主机的结果
+func (r *AnsibleRunner) collectHosts(result *AnsibleRunResult, target *ansibleTarget, output string) {
+	aliases := make(map[string]bool, len(target.hosts))
+	for _, item := range target.hosts {
+		aliases[item.alias] = true
+	}
+	recap := parseAnsibleRecap(output)
+	outputs := splitAnsibleOutput(output, aliases)
+
+	for _, item := range target.hosts {
+		host := &AnsibleHostResult{
+			HostID:   item.host.ID,
+			HostName: item.host.Name,
+			HostIP:   item.host.IP,
+			Alias:    item.alias,
+			Output:   outputs[item.alias],
+		}
+		stats, ok := recap[item.alias]
+		switch {
+		case ok && stats.Unreachable > 0:
+			host.Status = "failed"
+			host.Error = "主机不可达"
+		case ok && stats.Failed > 0:
+			host.Status = "failed"
+			host.Error = "任务执行失败"
+		case ok:
+			host.Status = "success"
+		case recap != nil:
+			host.Status = "skipped"
+			host.Error = "主机未匹配 Playbook 中的 hosts"
+		default:
+			host.Status = "failed"
+			host.Error = result.Error
+			if host.Error == "" {
+				host.Error = "未获取到执行结果"
+			}
+		}
+		if ok {
+			host.Stats = stats
+			result.Totals.add(stats)
+		}
+		result.Hosts = append(result.Hosts, host)
+	}
+}
+
+// save 保存执行记录和任务的最近执行结果
+func (r *AnsibleRunner) save(taskID uint, result *AnsibleRunResult, output string) {
+	hosts, _ := json.Marshal(result.Hosts)
+	if err := r.db.Model(&model.JobTask{}).Where("id = ?", result.JobTaskID).Updates(map[string]interface{}{
+		"status":        result.Status,
+		"result":        string(hosts),
+		"output":        output,
+		"error_message": result.Error,
+	}).Error; err != nil {
+		logger.Error("保存 Ansible 执行记录失败", zap.Uint("jobTaskId", result.JobTaskID), zap.Error(err))
+	}
+
+	// 最近执行结果只保留统计，完整输出在执行记录中
+	summary := *result
+	summary.Hosts = make([]*AnsibleHostResult, 0, len(result.Hosts))
+	for _, host := range result.Hosts {
+		item := *host
+		item.Output = ""
+		summary.Hosts = append(summary.Hosts, &item)
+	}
+	lastResult, _ := json.Marshal(summary)
+	if err := r.db.Model(&model.AnsibleTask{}).Where("id = ?", taskID).Updates(map[string]interface{}{
+		"status":          result.Status,
+		"last_run_result": string(lastResult),
+	}).Error; err != nil {
+		logger.Error("保存 Ansible 任务执行结果失败", zap.Uint("taskId", taskID), zap.Error(err))
+	}
+}
+
+// HasInlinePlaybook 任务是否使用内联 Playbook 内容，而不是 Playbook 目录中的文件
+func HasInlinePlaybook(task *model.AnsibleTask) bool {
+	return strings.TrimSpace(task.PlaybookContent) != ""
+}
+
+// CheckInlinePlaybook 校验用户能否保存任务中的内联 Playbook
+func (r *AnsibleRunner) CheckInlinePlaybook(ctx context.Context, userID uint, task *model.AnsibleTask) error {
+	if !HasInlinePlaybook(task) {
+		return nil
+	}
+	admin, err := r.isAdmin(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return ErrInlinePlaybookDenied
+	}
+	return nil
+}
+
+// isAdmin 用户是否拥有 admin 角色
+func (r *AnsibleRunner) isAdmin(ctx context.Context, userID uint) (bool, error) {
+	roles, err := r.roleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("获取用户角色失败: %w", err)
+	}
+	for _, role := range roles {
+		if role.Code == rbacbiz.RoleCodeAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// encodeExtraVars 将额外变量编码为 YAML 流式格式，没有变量时返回空
+// unsafe 为 true 时所有字符串值标记为 !unsafe，Ansible 不会对其做模板渲染
+func encodeExtraVars(vars map[string]interface{}, unsafe bool) []byte {
+	if len(vars) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	writeExtraVar(&buf, vars, unsafe)
+	return buf.Bytes()
+}
+
+// writeExtraVar 写入单个变量值，JSON 是 YAML 的子集，字符串以 JSON 转义后的双引号形式写入
+func writeExtraVar(buf *bytes.Buffer, value interface{}, unsafe bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			data, _ := json.Marshal(key)
+			buf.Write(data)
+			buf.WriteString(": ")
+			writeExtraVar(buf, v[key], unsafe)
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeExtraVar(buf, item, unsafe)
+		}
+		buf.WriteByte(']')
+	case string:
+		if unsafe {
+			buf.WriteString("!unsafe ")
+		}
+		data, _ := json.Marshal(v)
+		buf.Write(data)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte("null")
+		}
+		buf.Write(data)
+	}
+}
+
+// mergeExtraVars 合并任务配置的额外变量和本次执行传入的变量
+func mergeExtraVars(raw string, overrides map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	if strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &vars); err != nil {
+			return nil, fmt.Errorf("额外变量必须是 JSON 对象: %w", err)
+		}
+	}
+	for key, value := range overrides {
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// splitTags 拆分逗号分隔的标签
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// ansibleExitMessage ansible-playbook 退出码说明
+func ansibleExitMessage(code int) string {
+	switch code {
+	case 1:
+		return "ansible-playbook 执行出错（退出码 1）"
+	case 2:
+		return "部分主机执行失败（退出码 2）"
+	case 3:
+		return "部分主机不可达（退出码 3）"
+	case 4:
+		return "Playbook 或 inventory 解析失败（退出码 4）"
+	case 5:
+		return "ansible-playbook 参数错误（退出码 5）"
+	default:
+		return fmt.Sprintf("ansible-playbook 异常退出（退出码 %d）", code)
+	}
+}
+
+// AnsibleRun 一次执行的实时输出
+type AnsibleRun struct {
+	JobTaskID     uint
+	AnsibleTaskID uint
+
+	mu        sync.Mutex
+	output    []byte
+	pending   []byte
+	secrets   []string
+	truncated bool
+	finished  bool
+	cancelled bool
+	result    *AnsibleRunResult
+	changed   chan struct{}
+	done      chan struct{}
+	cancel    context.CancelFunc
+}
+
+func newAnsibleRun(jobTaskID, ansibleTaskID uint, cancel context.CancelFunc) *AnsibleRun {
+	return &AnsibleRun{
+		JobTaskID:     jobTaskID,
+		AnsibleTaskID: ansibleTaskID,
+		changed:       make(chan struct{}),
+		done:          make(chan struct{}),
+		cancel:        cancel,
+	}
+}
+
+// Write 按行写入输出，写入前脱敏
+func (r *AnsibleRun) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, p...)
+	appended := false
+	for {
+		i := bytes.IndexByte(r.pending, '\n')
+		if i < 0 {
+			break
+		}
+		r.appendLine(r.pending[:i+1])
+		r.pending = r.pending[i+1:]
+		appended = true
+	}
+	// 超长的单行不再等待换行
+	if len(r.pending) > 64*1024 {
+		r.appendLine(r.pending)
+		r.pending = nil
+		appended = true
+	}
+	if appended {
+		r.notify()
+	}
+	return len(p), nil
+}
+
+// Read 读取 offset 之后的输出，返回是否已结束以及下次有新输出时关闭的通道
+func (r *AnsibleRun) Read(offset int) ([]byte, bool, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if offset < 0 || offset > len(r.output) {
+		offset = len(r.output)
+	}
+	data := append([]byte(nil), r.output[offset:]...)
+	return data, r.finished, r.changed
+}
+
+// Output 当前的完整输出
+func (r *AnsibleRun) Output() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return string(r.output)
+}
+
+// Done 执行结束时关闭
+func (r *AnsibleRun) Done() <-chan struct{} {
+	return r.done
+}
+
+// Result 执行结果，执行结束前返回 nil
+func (r *AnsibleRun) Result() *AnsibleRunResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.result
+}
+
+func (r *AnsibleRun) setSecrets(secrets []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, secret := range secrets {
+		if len(secret) >= 4 {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+}
+
+func (r *AnsibleRun) appendLine(line []byte) {
+	for _, secret := range r.secrets {
+		line = bytes.ReplaceAll(line, []byte(secret), []byte("******"))
+	}
+	if r.truncated {
+		return
+	}
+	if len(r.output)+len(line) > maxAnsibleOutput {
+		r.output = append(r.output, "\n...... 输出超过上限，后续内容已省略 ......\n"...)
+		r.truncated = true
+		return
+	}
+	r.output = append(r.output, line...)
+}
+
+func (r *AnsibleRun) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// flush 写入未以换行结尾的剩余输出
+func (r *AnsibleRun) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) > 0 {
+		r.appendLine(append(r.pending, '\n'))
+		r.pending = nil
+		r.notify()
+	}
+}
+
+func (r *AnsibleRun) finish(result *AnsibleRunResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.result = result
+	r.finished = true
+	r.notify()
+	close(r.done)
+}
+
+func (r *AnsibleRun) cancelRun() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return false
+	}
+	r.cancelled = true
+	r.cancel()
+	return true
+}
+
+func (r *AnsibleRun) isCancelled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cancelled
+}
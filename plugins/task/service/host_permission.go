@@ -0,0 +1,93 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	rbacdata "github.com/ydcloud-dy/opshub/internal/data/rbac"
+	"gorm.io/gorm"
+)
+
+// ErrHostPermissionDenied 用户无权在目标主机上执行命令
+var ErrHostPermissionDenied = errors.New("无权在目标主机上执行命令")
+
+// hostExecutePermission 在主机上执行脚本或 Playbook 所需的资产权限，与 Web 终端共用同一权限位
+const hostExecutePermission = rbacbiz.PermissionTerminal
+
+// HostAuthorizer 校验用户对目标主机的执行权限
+type HostAuthorizer struct {
+	db        *gorm.DB
+	assetPerm rbacbiz.AssetPermissionRepo
+	dataScope *rbacbiz.DataScopeUseCase
+}
+
+// NewHostAuthorizer 创建主机执行权限校验器
+func NewHostAuthorizer(db *gorm.DB) *HostAuthorizer {
+	return &HostAuthorizer{
+		db:        db,
+		assetPerm: rbacdata.NewAssetPermissionRepo(db),
+		dataScope: rbacbiz.NewDataScopeUseCase(rbacdata.NewRoleRepo(db), rbacdata.NewUserRepo(db), rbacdata.NewDepartmentRepo(db)),
+	}
+}
+
+// WithUser 在上下文中登记指定用户的数据范围，供后台执行时按创建人而不是请求者过滤主机
+func (a *HostAuthorizer) WithUser(ctx context.Context, userID uint) context.Context {
+	return rbacbiz.WithDataScope(ctx, userID, func() (*rbacbiz.DataScope, error) {
+		return a.dataScope.Resolve(ctx, userID)
+	})
+}
+
+// Authorize 校验用户能否在所有目标主机上执行命令：主机需在上下文的数据范围内，且用户拥有该主机的执行权限
+func (a *HostAuthorizer) Authorize(ctx context.Context, userID uint, hostIDs []uint) error {
+	if len(hostIDs) == 0 {
+		return nil
+	}
+
+	var visible []uint
+	if err := a.db.WithContext(ctx).Model(&assetbiz.Host{}).
+		Scopes(rbacbiz.ScopeByDataPermission(ctx, "created_by")).
+		Where("id IN ?", hostIDs).
+		Pluck("id", &visible).Error; err != nil {
+		return fmt.Errorf("获取主机失败: %w", err)
+	}
+	inScope := make(map[uint]bool, len(visible))
+	for _, id := range visible {
+		inScope[id] = true
+	}
+
+	for _, hostID := range hostIDs {
+		if !inScope[hostID] {
+			return fmt.Errorf("%w: 主机 %d 不存在或不在数据权限范围内", ErrHostPermissionDenied, hostID)
+		}
+		ok, err := a.assetPerm.CheckHostOperationPermission(ctx, userID, hostID, hostExecutePermission)
+		if err != nil {
+			return fmt.Errorf("检查主机权限失败: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: 主机 %d", ErrHostPermissionDenied, hostID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,576 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	assetdata "github.com/ydcloud-dy/opshub/internal/data/asset"
+	"github.com/ydcloud-dy/opshub/pkg/logger"
+	monitorservice "github.com/ydcloud-dy/opshub/plugins/monitor/service"
+	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 重叠策略：上一次执行未结束时再次触发的处理方式
+const (
+	OverlapSkip  = "skip"  // 跳过本次执行
+	OverlapQueue = "queue" // 等上一次结束后执行，最多排队一次
+	OverlapAllow = "allow" // 允许并行执行
+)
+
+// 触发方式
+const (
+	TriggerCron   = "cron"
+	TriggerManual = "manual"
+	TriggerQueue  = "queue"
+)
+
+const (
+	// defaultScheduleTimeout 模板未配置超时时间时的默认值（秒）
+	defaultScheduleTimeout = 300
+	// scheduleHostConcurrency 单次执行同时连接的主机数
+	scheduleHostConcurrency = 10
+	// maxNotifyHosts 失败通知中列出的最大主机数
+	maxNotifyHosts = 10
+)
+
+// ErrScheduleRunning 上一次执行尚未结束
+var ErrScheduleRunning = errors.New("上一次执行尚未结束，按重叠策略不能再次执行")
+
+// ErrScheduleCreatorDisabled 创建人已被禁用或删除
+var ErrScheduleCreatorDisabled = errors.New("定时任务的创建人已被禁用或删除，不能执行")
+
+// scheduleParser 支持标准 5 位 cron 表达式和 @daily、@every 1h 等描述符
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseSchedule 解析 cron 表达式，timezone 为空时使用服务器时区
+func ParseSchedule(expr, timezone string) (cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, errors.New("请通过时区字段设置时区，cron 表达式中不能包含 TZ")
+	}
+	location := time.Local
+	if timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("无效的时区 %s", timezone)
+		}
+	}
+	schedule, err := scheduleParser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 cron 表达式: %w", err)
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok {
+		spec.Location = location
+	}
+	return schedule, nil
+}
+
+// NextRunTimes 预览 from 之后的 n 次执行时间
+func NextRunTimes(expr, timezone string, from time.Time, n int) ([]time.Time, error) {
+	schedule, err := ParseSchedule(expr, timezone)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, n)
+	for next := from; len(times) < n; {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		times = append(times, next)
+	}
+	return times, nil
+}
+
+// TemplateVariable 任务模板中定义的参数
+type TemplateVariable struct {
+	Name         string      `json:"name"`
+	VarName      string      `json:"varName"`
+	DefaultValue interface{} `json:"defaultValue"`
+	Required     bool        `json:"required"`
+}
+
+// ParseScheduleVariables 解析定时任务保存的模板变量取值
+func ParseScheduleVariables(raw string) (map[string]string, error) {
+	values := make(map[string]string)
+	if strings.TrimSpace(raw) == "" {
+		return values, nil
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, errors.New("模板变量必须是 JSON 对象")
+	}
+	for key, value := range parsed {
+		if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
+
+// RenderTemplate 用变量值替换模板中的 {{变量名}} 占位符，未提供的变量使用默认值
+func RenderTemplate(template *model.JobTemplate, values map[string]string) (string, error) {
+	var variables []TemplateVariable
+	if raw := strings.TrimSpace(template.Variables); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &variables); err != nil {
+			return "", fmt.Errorf("模板 %s 的参数定义格式错误", template.Name)
+		}
+	}
+
+	content := template.Content
+	for _, variable := range variables {
+		if variable.VarName == "" {
+			continue
+		}
+		value, ok := values[variable.VarName]
+		if !ok && variable.DefaultValue != nil {
+			value = fmt.Sprint(variable.DefaultValue)
+		}
+		if value == "" && variable.Required {
+			return "", fmt.Errorf("模板参数 %s 未填写", variable.Name)
+		}
+		content = strings.ReplaceAll(content, "{{"+variable.VarName+"}}", value)
+	}
+	// 模板未定义但定时任务中填写的变量同样替换
+	for name, value := range values {
+		content = strings.ReplaceAll(content, "{{"+name+"}}", value)
+	}
+	return content, nil
+}
+
+// JobScheduler 定时任务调度器
+// 执行状态保存在内存中，重叠策略只在当前实例内生效
+type JobScheduler struct {
+	db           *gorm.DB
+	scripts      *ScriptExecutor
+	groupRepo    assetbiz.AssetGroupRepo
+	hosts        *HostAuthorizer
+	notifier     *monitorservice.Notifier
+	checkCommand func(content string) error
+
+	mu      sync.Mutex
+	cron    *cron.Cron
+	entries map[uint]cron.EntryID
+	states  map[uint]*scheduleState
+}
+
+// scheduleState 定时任务的执行状态
+type scheduleState struct {
+	running int
+	queued  bool
+}
+
+// NewJobScheduler 创建调度器，checkCommand 用于每次执行前复查渲染后的脚本
+func NewJobScheduler(db *gorm.DB, checkCommand func(content string) error) *JobScheduler {
+	return &JobScheduler{
+		db:           db,
+		scripts:      NewScriptExecutor(db),
+		groupRepo:    assetdata.NewAssetGroupRepo(db),
+		hosts:        NewHostAuthorizer(db),
+		notifier:     monitorservice.NewNotifier(db),
+		checkCommand: checkCommand,
+		entries:      make(map[uint]cron.EntryID),
+		states:       make(map[uint]*scheduleState),
+	}
+}
+
+// Start 启动调度器并加载所有启用的定时任务
+func (s *JobScheduler) Start() {
+	s.mu.Lock()
+	if s.cron != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.cron = cron.New()
+	s.mu.Unlock()
+
+	s.recoverInterrupted()
+
+	var schedules []*model.JobSchedule
+	if err := s.db.Where("enabled = ? AND deleted_at IS NULL", true).Find(&schedules).Error; err != nil {
+		logger.Error("加载定时任务失败", zap.Error(err))
+	}
+	for _, schedule := range schedules {
+		if err := s.Reload(schedule); err != nil {
+			logger.Warn("定时任务无法调度", zap.Uint("scheduleId", schedule.ID), zap.String("cron", schedule.CronExpr), zap.Error(err))
+		}
+	}
+
+	s.mu.Lock()
+	s.cron.Start()
+	s.mu.Unlock()
+	logger.Info("定时任务调度器已启动", zap.Int("schedules", len(schedules)))
+}
+
+// Stop 停止调度器，执行中的任务继续运行直到结束
+func (s *JobScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cron == nil {
+		return
+	}
+	s.cron.Stop()
+	s.cron = nil
+	s.entries = make(map[uint]cron.EntryID)
+	logger.Info("定时任务调度器已停止")
+}
+
+// recoverInterrupted 服务重启前未结束的执行已无法继续，标记为失败
+func (s *JobScheduler) recoverInterrupted() {
+	if err := s.db.Model(&model.JobTask{}).
+		Where("schedule_id IS NOT NULL AND status = ?", "running").
+		Updates(map[string]interface{}{"status": "failed", "error_message": "服务重启，执行已中断"}).Error; err != nil {
+		logger.Warn("恢复中断的定时任务执行记录失败", zap.Error(err))
+	}
+}
+
+// Reload 按最新配置重新调度定时任务，已暂停或删除的任务会被移除
+func (s *JobScheduler) Reload(schedule *model.JobSchedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entryID, ok := s.entries[schedule.ID]; ok {
+		s.cron.Remove(entryID)
+		delete(s.entries, schedule.ID)
+	}
+	if s.cron == nil || !schedule.Enabled || schedule.DeletedAt != nil {
+		return nil
+	}
+
+	spec, err := ParseSchedule(schedule.CronExpr, schedule.Timezone)
+	if err != nil {
+		return err
+	}
+	id := schedule.ID
+	s.entries[id] = s.cron.Schedule(spec, cron.FuncJob(func() {
+		if _, _, err := s.trigger(id, TriggerCron, 0); err != nil {
+			logger.Error("触发定时任务失败", zap.Uint("scheduleId", id), zap.Error(err))
+		}
+	}))
+	return nil
+}
+
+// Remove 移除定时任务的调度
+func (s *JobScheduler) Remove(scheduleID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entryID, ok := s.entries[scheduleID]; ok {
+		s.cron.Remove(entryID)
+		delete(s.entries, scheduleID)
+	}
+}
+
+// RunNow 立即执行一次，同样遵循重叠策略；排队时返回 queued 为 true
+func (s *JobScheduler) RunNow(scheduleID, userID uint) (*model.JobTask, bool, error) {
+	return s.trigger(scheduleID, TriggerManual, userID)
+}
+
+// trigger 按重叠策略触发一次执行
+func (s *JobScheduler) trigger(scheduleID uint, trigger string, userID uint) (*model.JobTask, bool, error) {
+	var schedule model.JobSchedule
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", scheduleID).First(&schedule).Error; err != nil {
+		return nil, false, fmt.Errorf("定时任务不存在: %w", err)
+	}
+	if trigger != TriggerManual && !schedule.Enabled {
+		return nil, false, nil
+	}
+
+	// 定时任务以创建人的权限执行，创建人不可用时不再执行
+	active, err := s.creatorActive(&schedule)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取创建人失败: %w", err)
+	}
+	if !active {
+		if trigger == TriggerManual {
+			return nil, false, ErrScheduleCreatorDisabled
+		}
+		job, err := s.createJob(&schedule, trigger, userID, "skipped", "创建人已被禁用或删除，跳过本次执行")
+		return job, false, err
+	}
+
+	s.mu.Lock()
+	state, ok := s.states[scheduleID]
+	if !ok {
+		state = &scheduleState{}
+		s.states[scheduleID] = state
+	}
+	if state.running > 0 {
+		switch schedule.OverlapPolicy {
+		case OverlapAllow:
+		case OverlapQueue:
+			state.queued = true
+			s.mu.Unlock()
+			return nil, true, nil
+		default:
+			s.mu.Unlock()
+			if trigger == TriggerManual {
+				return nil, false, ErrScheduleRunning
+			}
+			job, err := s.createJob(&schedule, trigger, userID, "skipped", "上一次执行尚未结束，按重叠策略跳过本次执行")
+			return job, false, err
+		}
+	}
+	state.running++
+	s.mu.Unlock()
+
+	job, err := s.createJob(&schedule, trigger, userID, "running", "")
+	if err != nil {
+		s.finish(scheduleID)
+		return nil, false, err
+	}
+	go s.execute(&schedule, job)
+	return job, false, nil
+}
+
+// creatorActive 检查定时任务的创建人是否存在且处于启用状态
+func (s *JobScheduler) creatorActive(schedule *model.JobSchedule) (bool, error) {
+	var count int64
+	err := s.db.Model(&rbacbiz.SysUser{}).
+		Where("id = ? AND status = ?", schedule.CreatedBy, 1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// finish 结束一次执行，有排队的触发时开始下一次执行
+func (s *JobScheduler) finish(scheduleID uint) {
+	s.mu.Lock()
+	state := s.states[scheduleID]
+	state.running--
+	if state.running > 0 {
+		s.mu.Unlock()
+		return
+	}
+	queued := state.queued
+	delete(s.states, scheduleID)
+	s.mu.Unlock()
+
+	if queued {
+		if _, _, err := s.trigger(scheduleID, TriggerQueue, 0); err != nil {
+			logger.Error("执行排队的定时任务失败", zap.Uint("scheduleId", scheduleID), zap.Error(err))
+		}
+	}
+}
+
+// createJob 创建执行记录
+func (s *JobScheduler) createJob(schedule *model.JobSchedule, trigger string, userID uint, status, message string) (*model.JobTask, error) {
+	if userID == 0 {
+		userID = schedule.CreatedBy
+	}
+	parameters, _ := json.Marshal(map[string]interface{}{
+		"scheduleId": schedule.ID,
+		"trigger":    trigger,
+		"cronExpr":   schedule.CronExpr,
+		"timezone":   schedule.Timezone,
+	})
+	now := time.Now()
+	templateID := schedule.TemplateID
+	scheduleID := schedule.ID
+	job := &model.JobTask{
+		Name:         fmt.Sprintf("定时任务: %s", schedule.Name),
+		TemplateID:   &templateID,
+		ScheduleID:   &scheduleID,
+		TaskType:     "cron",
+		Status:       status,
+		TargetHosts:  "[]",
+		Parameters:   string(parameters),
+		Result:       "[]",
+		ErrorMessage: message,
+		ExecuteTime:  &now,
+		CreatedBy:    userID,
+	}
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("创建执行记录失败: %w", err)
+	}
+	if status == "running" {
+		s.db.Model(&model.JobSchedule{}).Where("id = ?", schedule.ID).
+			Updates(map[string]interface{}{"last_run_time": now, "last_run_status": status})
+	}
+	return job, nil
+}
+
+// execute 执行一次定时任务并保存结果
+func (s *JobScheduler) execute(schedule *model.JobSchedule, job *model.JobTask) {
+	defer s.finish(schedule.ID)
+
+	results, err := s.run(schedule, job)
+	status := "success"
+	message := ""
+	if err != nil {
+		status = "failed"
+		message = err.Error()
+	}
+	for _, result := range results {
+		if result.Status != "success" {
+			status = "failed"
+		}
+	}
+
+	resultJSON, _ := json.Marshal(results)
+	if err := s.db.Model(&model.JobTask{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":        status,
+		"result":        string(resultJSON),
+		"error_message": message,
+	}).Error; err != nil {
+		logger.Error("保存定时任务执行记录失败", zap.Uint("jobTaskId", job.ID), zap.Error(err))
+	}
+	s.db.Model(&model.JobSchedule{}).Where("id = ?", schedule.ID).Update("last_run_status", status)
+
+	if status == "failed" && schedule.NotifyOnFailure {
+		s.notifyFailure(schedule, job, message, results)
+	}
+}
+
+// run 在目标主机上执行保存定时任务时渲染的脚本快照
+// 模板之后的修改不会影响已保存的定时任务，避免他人修改模板后以创建人的权限执行
+func (s *JobScheduler) run(schedule *model.JobSchedule, job *model.JobTask) ([]HostScriptResult, error) {
+	content := schedule.Content
+	if content == "" {
+		return nil, errors.New("定时任务缺少脚本快照，请重新保存后再执行")
+	}
+	// 命令拦截规则可能在保存之后调整，每次执行前重新检查
+	if err := s.checkCommand(content); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	hostIDs, err := s.AuthorizeHosts(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	targetHosts, _ := json.Marshal(hostIDs)
+	s.db.Model(&model.JobTask{}).Where("id = ?", job.ID).Update("target_hosts", string(targetHosts))
+
+	timeout := schedule.Timeout
+	if timeout <= 0 {
+		timeout = defaultScheduleTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	results := make([]HostScriptResult, len(hostIDs))
+	sem := make(chan struct{}, scheduleHostConcurrency)
+	var wg sync.WaitGroup
+	for i, hostID := range hostIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, hostID uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.scripts.Execute(ctx, hostID, schedule.ScriptType, content)
+		}(i, hostID)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// AuthorizeHosts 按创建人的数据范围解析目标主机，并校验创建人对每台主机的执行权限
+func (s *JobScheduler) AuthorizeHosts(ctx context.Context, schedule *model.JobSchedule) ([]uint, error) {
+	ctx = s.hosts.WithUser(ctx, schedule.CreatedBy)
+	hostIDs, err := s.ResolveHosts(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.hosts.Authorize(ctx, schedule.CreatedBy, hostIDs); err != nil {
+		return nil, err
+	}
+	return hostIDs, nil
+}
+
+// ResolveHosts 解析定时任务的目标主机，分组目标在每次执行时按当前分组成员解析，只包含上下文数据范围内的主机
+func (s *JobScheduler) ResolveHosts(ctx context.Context, schedule *model.JobSchedule) ([]uint, error) {
+	var hostIDs []uint
+	if schedule.TargetType == "group" {
+		if schedule.GroupID == 0 {
+			return nil, errors.New("未指定目标分组")
+		}
+		descendants, err := s.groupRepo.GetDescendantIDs(ctx, schedule.GroupID)
+		if err != nil {
+			return nil, fmt.Errorf("获取分组失败: %w", err)
+		}
+		groupIDs := append([]uint{schedule.GroupID}, descendants...)
+		if err := s.db.WithContext(ctx).Model(&assetbiz.Host{}).Where("group_id IN ?", groupIDs).
+			Scopes(rbacbiz.ScopeByDataPermission(ctx, "created_by")).
+			Order("id ASC").Pluck("id", &hostIDs).Error; err != nil {
+			return nil, fmt.Errorf("获取分组主机失败: %w", err)
+		}
+		if len(hostIDs) == 0 {
+			return nil, errors.New("目标分组下没有主机")
+		}
+		return hostIDs, nil
+	}
+
+	if strings.TrimSpace(schedule.TargetHosts) != "" {
+		if err := json.Unmarshal([]byte(schedule.TargetHosts), &hostIDs); err != nil {
+			return nil, errors.New("目标主机格式错误，应为主机ID数组")
+		}
+	}
+	if len(hostIDs) == 0 {
+		return nil, errors.New("未指定目标主机")
+	}
+	return hostIDs, nil
+}
+
+// notifyFailure 通过监控中心的告警通道发送失败通知
+func (s *JobScheduler) notifyFailure(schedule *model.JobSchedule, job *model.JobTask, message string, results []HostScriptResult) {
+	var userIDs []uint
+	if strings.TrimSpace(schedule.NotifyUserIDs) != "" {
+		_ = json.Unmarshal([]byte(schedule.NotifyUserIDs), &userIDs)
+	}
+	if len(userIDs) == 0 {
+		userIDs = []uint{schedule.CreatedBy}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "定时任务: %s\n", schedule.Name)
+	fmt.Fprintf(&b, "执行记录: #%d\n", job.ID)
+	fmt.Fprintf(&b, "执行时间: %s\n", job.ExecuteTime.Format("2006-01-02 15:04:05"))
+	if message != "" {
+		fmt.Fprintf(&b, "错误: %s\n", message)
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Status == "success" {
+			continue
+		}
+		failed++
+		if failed <= maxNotifyHosts {
+			fmt.Fprintf(&b, "失败主机: %s(%s) %s\n", result.HostName, result.HostIP, result.Error)
+		}
+	}
+	if failed > maxNotifyHosts {
+		fmt.Fprintf(&b, "等 %d 台主机执行失败\n", failed)
+	}
+	fmt.Fprintf(&b, "详情请在 任务中心-执行记录 中查看")
+
+	if err := s.notifier.Notify(context.Background(), "定时任务执行失败: "+schedule.Name, b.String(), userIDs); err != nil {
+		logger.Warn("发送定时任务失败通知失败", zap.Uint("scheduleId", schedule.ID), zap.Error(err))
+	}
+}
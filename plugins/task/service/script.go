@@ -0,0 +1,171 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	assetbiz "github.com/ydcloud-dy/opshub/internal/biz/asset"
+	assetdata "github.com/ydcloud-dy/opshub/internal/data/asset"
+	"golang.org/x/crypto/ssh"
+	"gorm.io/gorm"
+)
+
+// HostScriptResult 单台主机的脚本执行结果，与执行记录中的主机结果格式一致
+type HostScriptResult struct {
+	HostID   uint   `json:"hostId"`
+	HostName string `json:"hostName"`
+	HostIP   string `json:"hostIp"`
+	Status   string `json:"status"` // success, failed
+	Output   string `json:"output"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ScriptExecutor 通过 SSH 在主机上执行脚本
+type ScriptExecutor struct {
+	db             *gorm.DB
+	credentialRepo assetbiz.CredentialRepo
+}
+
+// NewScriptExecutor 创建脚本执行器
+func NewScriptExecutor(db *gorm.DB) *ScriptExecutor {
+	return &ScriptExecutor{
+		db:             db,
+		credentialRepo: assetdata.NewCredentialRepo(db),
+	}
+}
+
+// Execute 在单台主机上执行脚本，ctx 取消或超时时关闭会话终止远程命令
+func (e *ScriptExecutor) Execute(ctx context.Context, hostID uint, scriptType, content string) HostScriptResult {
+	result := HostScriptResult{
+		HostID: hostID,
+		Status: "failed",
+	}
+
+	// 获取主机信息
+	var host assetbiz.Host
+	if err := e.db.WithContext(ctx).Where("id = ?", hostID).First(&host).Error; err != nil {
+		result.Error = fmt.Sprintf("获取主机信息失败: %v", err)
+		return result
+	}
+	result.HostName = host.Name
+	result.HostIP = host.IP
+
+	if host.CredentialID == 0 {
+		result.Error = "主机未配置凭证"
+		return result
+	}
+	credential, err := e.credentialRepo.GetByIDDecrypted(ctx, host.CredentialID)
+	if err != nil {
+		result.Error = fmt.Sprintf("获取凭证失败: %v", err)
+		return result
+	}
+
+	client, err := dialHost(&host, credential)
+	if err != nil {
+		result.Error = fmt.Sprintf("SSH连接失败: %v", err)
+		return result
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		result.Error = fmt.Sprintf("创建SSH会话失败: %v", err)
+		return result
+	}
+	defer session.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Close()
+		case <-done:
+		}
+	}()
+
+	// 根据脚本类型构造执行命令
+	cmd := content
+	if scriptType == "Python" {
+		cmd = fmt.Sprintf("python3 -c %s", shellescape(content))
+	}
+
+	output, err := session.CombinedOutput(cmd)
+	result.Output = string(output)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Error = "执行超时"
+		} else {
+			result.Error = fmt.Sprintf("执行失败: %v", err)
+		}
+		return result
+	}
+	result.Status = "success"
+	return result
+}
+
+// dialHost 使用主机凭证建立SSH连接
+func dialHost(host *assetbiz.Host, credential *assetbiz.Credential) (*ssh.Client, error) {
+	var auth ssh.AuthMethod
+	switch credential.Type {
+	case "password":
+		auth = ssh.Password(credential.Password)
+	case "key", "private_key":
+		var signer ssh.Signer
+		var err error
+		if credential.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(credential.PrivateKey), []byte(credential.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(credential.PrivateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析私钥失败: %w", err)
+		}
+		auth = ssh.PublicKeys(signer)
+	default:
+		return nil, fmt.Errorf("不支持的凭证类型: %s", credential.Type)
+	}
+
+	user := credential.Username
+	if user == "" {
+		user = host.SSHUser
+	}
+	port := host.Port
+	if port == 0 {
+		port = 22
+	}
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	}
+	return ssh.Dial("tcp", fmt.Sprintf("%s:%d", host.IP, port), config)
+}
+
+// shellescape 转义shell命令
+func shellescape(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
+}
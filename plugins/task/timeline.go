@@ -0,0 +1,126 @@
+// Copyright (c) 2026 DYCloud J.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ydcloud-dy/opshub/internal/biz/audit"
+	rbacbiz "github.com/ydcloud-dy/opshub/internal/biz/rbac"
+	"github.com/ydcloud-dy/opshub/plugins/task/model"
+	"gorm.io/gorm"
+)
+
+// GetTimelineSources 获取插件的活动时间线来源
+func (p *Plugin) GetTimelineSources(db *gorm.DB) []audit.TimelineSource {
+	return []audit.TimelineSource{&jobTaskTimeline{db: db}}
+}
+
+// jobTaskTimeline 任务执行记录，资源类型 job_task 按任务匹配，job_template 按模板匹配，ansible_task 按 Ansible 任务匹配，job_schedule 按定时任务匹配，host 按目标主机匹配
+type jobTaskTimeline struct {
+	db *gorm.DB
+}
+
+func (s *jobTaskTimeline) Name() string  { return "job_task" }
+func (s *jobTaskTimeline) Label() string { return "任务执行" }
+
+func (s *jobTaskTimeline) Timeline(ctx context.Context, query audit.TimelineQuery) ([]*audit.TimelineEntry, error) {
+	db := s.db.WithContext(ctx).Model(&model.JobTask{}).Where("deleted_at IS NULL")
+	if query.HasResource() {
+		switch query.ResourceType {
+		case "job_task":
+			db = db.Where("id = ?", query.ResourceID)
+		case "job_template":
+			db = db.Where("template_id = ?", query.ResourceID)
+		case "ansible_task":
+			taskID, err := strconv.ParseUint(query.ResourceID, 10, 64)
+			if err != nil {
+				return nil, nil
+			}
+			db = db.Where("JSON_EXTRACT(parameters, '$.ansibleTaskId') = ?", taskID)
+		case "job_schedule":
+			db = db.Where("schedule_id = ?", query.ResourceID)
+		case "host":
+			hostID, err := strconv.ParseUint(query.ResourceID, 10, 64)
+			if err != nil {
+				return nil, nil
+			}
+			db = db.Where("JSON_CONTAINS(target_hosts, ?)", strconv.FormatUint(hostID, 10))
+		default:
+			return nil, nil
+		}
+	}
+	var tasks []*model.JobTask
+	err := db.Scopes(
+		audit.TimelineScope(query, "created_at", "created_by", ""),
+		rbacbiz.ScopeByDataPermission(ctx, "created_by"),
+	).Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	usernames, err := s.usernames(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*audit.TimelineEntry, 0, len(tasks))
+	for _, task := range tasks {
+		entries = append(entries, &audit.TimelineEntry{
+			RecordID:     task.ID,
+			Time:         task.CreatedAt,
+			UserID:       task.CreatedBy,
+			Username:     usernames[task.CreatedBy],
+			Action:       audit.ActionExecute,
+			Title:        fmt.Sprintf("%s [%s]", task.Name, task.TaskType),
+			ResourceType: "job_task",
+			ResourceID:   strconv.FormatUint(uint64(task.ID), 10),
+			Status:       task.Status,
+			DetailKind:   audit.TimelineDetailJSON,
+			DetailURL:    fmt.Sprintf("/api/v1/plugins/task/execution-history/%d", task.ID),
+			Link:         "/task/execution-history",
+		})
+	}
+	return entries, nil
+}
+
+// usernames 查询任务创建人的用户名
+func (s *jobTaskTimeline) usernames(ctx context.Context, tasks []*model.JobTask) (map[uint]string, error) {
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.CreatedBy)
+	}
+	usernames := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return usernames, nil
+	}
+	var users []struct {
+		ID       uint
+		Username string
+	}
+	if err := s.db.WithContext(ctx).Table("sys_user").Select("id, username").Where("id IN ?", ids).Scan(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+	return usernames, nil
+}
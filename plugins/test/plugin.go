@@ -102,3 +102,18 @@ func (p *TestPlugin) GetMenus() []plugin.MenuConfig {
 		},
 	}
 }
+
+// GetPermissions 获取插件接口权限
+func (p *TestPlugin) GetPermissions() []plugin.PermissionConfig {
+	return []plugin.PermissionConfig{
+		{
+			Code:     "test:view",
+			Name:     "查看测试插件",
+			MenuCode: "test_home",
+			Routes: []plugin.PermissionRoute{
+				{Method: "GET", Path: "/test/hello"},
+				{Method: "GET", Path: "/test/info"},
+			},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+import request from '@/utils/request'
+
+export interface AccessRequestData {
+  resourceType: 'host' | 'cluster'
+  assetGroupId?: number
+  hostIds?: number[]
+  permissions?: number
+  clusterId?: number
+  roleName?: string
+  roleNamespace?: string
+  roleType?: 'ClusterRole' | 'Role'
+  reason: string
+  durationMinutes: number
+}
+
+// 获取访问申请列表（scope: mine 我的申请 / todo 待我审批 / all 全部）
+export const getAccessRequests = (params: {
+  page: number
+  pageSize: number
+  scope?: 'mine' | 'todo' | 'all'
+  status?: string
+  resourceType?: string
+}) => {
+  return request.get('/api/v1/access-requests', { params })
+}
+
+// 提交访问申请
+export const createAccessRequest = (data: AccessRequestData) => {
+  return request.post('/api/v1/access-requests', data)
+}
+
+// 获取访问申请详情（含流转记录）
+export const getAccessRequest = (id: number) => {
+  return request.get(`/api/v1/access-requests/${id}`)
+}
+
+// 批准访问申请
+export const approveAccessRequest = (id: number, comment?: string) => {
+  return request.post(`/api/v1/access-requests/${id}/approve`, { comment })
+}
+
+// 驳回访问申请
+export const rejectAccessRequest = (id: number, comment?: string) => {
+  return request.post(`/api/v1/access-requests/${id}/reject`, { comment })
+}
+
+// 收回已批准的授权
+export const revokeAccessRequest = (id: number, comment?: string) => {
+  return request.post(`/api/v1/access-requests/${id}/revoke`, { comment })
+}
+
+// 撤回待审批的申请
+export const cancelAccessRequest = (id: number) => {
+  return request.post(`/api/v1/access-requests/${id}/cancel`)
+}
+
+// 获取审批人配置
+export const getAccessApprovers = (scopeType: 'asset_group' | 'department', scopeId: number) => {
+  return request.get('/api/v1/access-approvers', { params: { scopeType, scopeId } })
+}
+
+// 设置审批人
+export const setAccessApprovers = (data: {
+  scopeType: 'asset_group' | 'department'
+  scopeId: number
+  userIds: number[]
+}) => {
+  return request.put('/api/v1/access-approvers', data)
+}
@@ -0,0 +1,79 @@
+import request from '@/utils/request'
+
+// API 令牌与服务账号API
+
+export interface ApiTokenScope {
+  plugin?: string
+  methods?: string[]
+  paths?: string[]
+}
+
+export interface ApiTokenData {
+  name: string
+  scopes: ApiTokenScope[]
+  expiresAt: string
+  description?: string
+}
+
+/**
+ * 获取个人 API 令牌
+ */
+export const getMyApiTokens = () => {
+  return request.get('/api/v1/profile/api-tokens')
+}
+
+/**
+ * 创建个人 API 令牌，明文令牌只返回一次
+ */
+export const createMyApiToken = (data: ApiTokenData) => {
+  return request.post('/api/v1/profile/api-tokens', data)
+}
+
+/**
+ * 吊销个人 API 令牌
+ */
+export const revokeMyApiToken = (id: number) => {
+  return request.delete(`/api/v1/profile/api-tokens/${id}`)
+}
+
+/**
+ * 获取服务账号列表
+ */
+export const getServiceAccounts = () => {
+  return request.get('/api/v1/service-accounts')
+}
+
+/**
+ * 创建服务账号
+ */
+export const createServiceAccount = (data: { username: string; realName?: string; roleIds?: number[] }) => {
+  return request.post('/api/v1/service-accounts', data)
+}
+
+/**
+ * 删除服务账号及其全部令牌
+ */
+export const deleteServiceAccount = (id: number) => {
+  return request.delete(`/api/v1/service-accounts/${id}`)
+}
+
+/**
+ * 获取服务账号的令牌
+ */
+export const getServiceAccountTokens = (id: number) => {
+  return request.get(`/api/v1/service-accounts/${id}/tokens`)
+}
+
+/**
+ * 为服务账号创建令牌
+ */
+export const createServiceAccountToken = (id: number, data: ApiTokenData) => {
+  return request.post(`/api/v1/service-accounts/${id}/tokens`, data)
+}
+
+/**
+ * 吊销服务账号令牌
+ */
+export const revokeServiceAccountToken = (id: number, tokenId: number) => {
+  return request.delete(`/api/v1/service-accounts/${id}/tokens/${tokenId}`)
+}
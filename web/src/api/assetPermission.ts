@@ -16,6 +16,8 @@ export const createAssetPermission = (data: {
   assetGroupId: number
   hostIds: number[]
   permissions?: number
+  startAt?: string | null
+  expiresAt?: string | null
 }) => {
   return request.post('/api/v1/asset-permissions', data)
 }
@@ -36,6 +38,8 @@ export const updateAssetPermission = (id: number, data: {
   assetGroupId: number
   hostIds: number[]
   permissions?: number
+  startAt?: string | null
+  expiresAt?: string | null
 }) => {
   return request.put(`/api/v1/asset-permissions/${id}`, data)
 }
@@ -56,3 +60,50 @@ export const getUserHostPermissions = (hostId: number) => {
     params: { hostId }
   })
 }
+
+// 解释用户对指定主机的有效权限及来源
+export const explainHostPermissions = (hostId: number, userId?: number) => {
+  return request.get('/api/v1/asset-permissions/explain', {
+    params: { hostId, userId }
+  })
+}
+
+export interface AssetGrantData {
+  subjectType: 'user' | 'department' | 'role'
+  subjectId: number
+  assetGroupId: number
+  hostIds: number[]
+  permissions: number
+  effect?: 'allow' | 'deny'
+  startAt?: string | null
+  expiresAt?: string | null
+  reason?: string
+}
+
+// 获取资产授权列表
+export const getAssetGrants = (params: {
+  page: number
+  pageSize: number
+  subjectType?: string
+  subjectId?: number
+  assetGroupId?: number
+  effect?: string
+  active?: boolean
+}) => {
+  return request.get('/api/v1/asset-grants', { params })
+}
+
+// 创建资产授权
+export const createAssetGrant = (data: AssetGrantData) => {
+  return request.post('/api/v1/asset-grants', data)
+}
+
+// 更新资产授权
+export const updateAssetGrant = (id: number, data: AssetGrantData) => {
+  return request.put(`/api/v1/asset-grants/${id}`, data)
+}
+
+// 删除资产授权
+export const deleteAssetGrant = (id: number) => {
+  return request.delete(`/api/v1/asset-grants/${id}`)
+}
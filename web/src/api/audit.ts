@@ -7,6 +7,9 @@ export const getOperationLogList = (params: {
   username?: string
   module?: string
   action?: string
+  status?: string
+  resourceType?: string
+  resourceId?: string
   startTime?: string
   endTime?: string
 }) => {
@@ -17,14 +20,6 @@ export const getOperationLogDetail = (id: number) => {
   return request.get(`/api/v1/audit/operation-logs/${id}`)
 }
 
-export const deleteOperationLog = (id: number) => {
-  return request.delete(`/api/v1/audit/operation-logs/${id}`)
-}
-
-export const deleteOperationLogsBatch = (ids: number[]) => {
-  return request.post('/api/v1/audit/operation-logs/batch-delete', { ids })
-}
-
 // 登录日志相关接口
 export const getLoginLogList = (params: {
   page?: number
@@ -42,14 +37,6 @@ export const getLoginLogDetail = (id: number) => {
   return request.get(`/api/v1/audit/login-logs/${id}`)
 }
 
-export const deleteLoginLog = (id: number) => {
-  return request.delete(`/api/v1/audit/login-logs/${id}`)
-}
-
-export const deleteLoginLogsBatch = (ids: number[]) => {
-  return request.post('/api/v1/audit/login-logs/batch-delete', { ids })
-}
-
 // 数据日志相关接口
 export const getDataLogList = (params: {
   page?: number
@@ -67,10 +54,118 @@ export const getDataLogDetail = (id: number) => {
   return request.get(`/api/v1/audit/data-logs/${id}`)
 }
 
-export const deleteDataLog = (id: number) => {
-  return request.delete(`/api/v1/audit/data-logs/${id}`)
+// 审计完整性校验
+export const verifyAuditIntegrity = (chain?: string) => {
+  return request.get('/api/v1/audit/integrity/verify', { params: { chain } })
+}
+
+// 审计日志导出，返回文件内容
+export type AuditExportFormat = 'xlsx' | 'csv' | 'jsonl'
+
+export const exportOperationLogs = (params: Record<string, any>) => {
+  return request.get('/api/v1/audit/operation-logs/export', { params, responseType: 'blob' })
+}
+
+export const exportLoginLogs = (params: Record<string, any>) => {
+  return request.get('/api/v1/audit/login-logs/export', { params, responseType: 'blob' })
+}
+
+export const exportDataLogs = (params: Record<string, any>) => {
+  return request.get('/api/v1/audit/data-logs/export', { params, responseType: 'blob' })
+}
+
+// 保留策略相关接口
+export const getRetentionPolicies = () => {
+  return request.get('/api/v1/audit/retention-policies')
+}
+
+export const updateRetentionPolicy = (logType: string, data: { retentionDays: number; enabled: boolean }) => {
+  return request.put(`/api/v1/audit/retention-policies/${logType}`, data)
+}
+
+export const runRetentionPolicy = (logType: string) => {
+  return request.post(`/api/v1/audit/retention-policies/${logType}/run`)
+}
+
+// 归档相关接口
+export const getAuditArchives = (params: { page?: number; pageSize?: number; logType?: string }) => {
+  return request.get('/api/v1/audit/archives', { params })
+}
+
+export const downloadAuditArchive = (id: number) => {
+  return request.get(`/api/v1/audit/archives/${id}/download`, { responseType: 'blob' })
+}
+
+export const importAuditArchive = (id: number) => {
+  return request.post(`/api/v1/audit/archives/${id}/import`)
+}
+
+export const unloadAuditArchive = (id: number) => {
+  return request.delete(`/api/v1/audit/archives/${id}/import`)
+}
+
+export const getAuditArchiveRecords = (id: number, params: { page?: number; pageSize?: number; username?: string; keyword?: string }) => {
+  return request.get(`/api/v1/audit/archives/${id}/records`, { params })
+}
+
+// 安全事件相关接口
+export interface SecurityEventParams {
+  page?: number
+  pageSize?: number
+  eventType?: string
+  level?: string
+  status?: string
+  username?: string
+  ip?: string
+  startTime?: string
+  endTime?: string
+}
+
+export const getSecurityEvents = (params: SecurityEventParams) => {
+  return request.get('/api/v1/audit/security-events', { params })
+}
+
+export const getSecurityEventStats = () => {
+  return request.get('/api/v1/audit/security-events/stats')
+}
+
+export const getSecurityEventDetail = (id: number) => {
+  return request.get(`/api/v1/audit/security-events/${id}`)
+}
+
+export const handleSecurityEvent = (id: number, data: { status: string; remark?: string }) => {
+  return request.put(`/api/v1/audit/security-events/${id}/handle`, data)
+}
+
+// 活动时间线相关接口
+export interface TimelineParams {
+  userId?: number
+  username?: string
+  resourceType?: string
+  resourceId?: string
+  startTime?: string
+  endTime?: string
+  sources?: string
+  order?: 'asc' | 'desc'
+  limit?: number
+}
+
+export const getTimeline = (params: TimelineParams) => {
+  return request.get('/api/v1/audit/timeline', { params })
+}
+
+export const getTimelineSources = () => {
+  return request.get('/api/v1/audit/timeline/sources')
+}
+
+export const exportTimeline = (params: TimelineParams & { format: AuditExportFormat }) => {
+  return request.get('/api/v1/audit/timeline/export', { params, responseType: 'blob' })
 }
 
-export const deleteDataLogsBatch = (ids: number[]) => {
-  return request.post('/api/v1/audit/data-logs/batch-delete', { ids })
+// 时间线详情，录制文件按文本返回
+export const getTimelineDetail = (url: string, kind: string) => {
+  if (kind === 'recording') {
+    return request.get(url, { responseType: 'text' })
+  }
+  return request.get(url)
 }
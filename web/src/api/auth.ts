@@ -18,6 +18,36 @@ export interface RegisterParams {
 export interface LoginResponse {
   token: string
   user: any
+  // 访问令牌有效期较短，过期后使用 refreshToken 换取新令牌
+  refreshToken?: string
+  expiresIn?: number
+  // 需要双因素认证时返回，使用 mfaToken 完成第二步登录
+  mfaRequired?: boolean
+  mfaSetupRequired?: boolean
+  mfaToken?: string
+  recoveryCodes?: string[]
+  // 首次登录、管理员重置后或密码过期时返回，使用 passwordToken 设置新密码后完成登录
+  passwordChangeRequired?: boolean
+  passwordChangeReason?: 'initial' | 'expired'
+  passwordToken?: string
+  // 密码即将过期时返回过期时间
+  passwordExpiresAt?: string
+}
+
+export interface PasswordPolicy {
+  minLength: number
+  requireUpper: boolean
+  requireLower: boolean
+  requireDigit: boolean
+  requireSymbol: boolean
+  historyCount: number
+  maxAgeDays: number
+}
+
+export interface MfaEnrollInfo {
+  secret: string
+  uri: string
+  qrCode: string
 }
 
 // 登录
@@ -25,6 +55,31 @@ export const login = (params: LoginParams) => {
   return request.post<any, LoginResponse>('/api/v1/public/login', params)
 }
 
+// 双因素登录：使用认证器验证码或恢复码完成登录
+export const loginMfa = (mfaToken: string, code: string) => {
+  return request.post<any, LoginResponse>('/api/v1/public/login/mfa', { mfaToken, code })
+}
+
+// 登录时绑定认证器（角色要求双因素认证但尚未绑定）
+export const loginMfaEnroll = (mfaToken: string) => {
+  return request.post<any, MfaEnrollInfo>('/api/v1/public/login/mfa/enroll', { mfaToken })
+}
+
+// 登录时修改密码（首次登录、管理员重置后或密码过期）
+export const loginChangePassword = (passwordToken: string, newPassword: string) => {
+  return request.post<any, LoginResponse>('/api/v1/public/login/password', { passwordToken, newPassword })
+}
+
+// 获取密码策略
+export const getPasswordPolicy = () => {
+  return request.get<any, PasswordPolicy>('/api/v1/public/password-policy')
+}
+
+// 退出登录，注销当前会话
+export const logout = () => {
+  return request.post('/api/v1/logout')
+}
+
 // 注册
 export const register = (params: RegisterParams) => {
   return request.post('/api/v1/public/register', params)
@@ -34,3 +89,13 @@ export const register = (params: RegisterParams) => {
 export const getProfile = () => {
   return request.get('/api/v1/profile')
 }
+
+export interface OidcProvider {
+  name: string
+  displayName: string
+}
+
+// 获取单点登录提供者
+export const getOidcProviders = () => {
+  return request.get<any, OidcProvider[]>('/api/v1/public/oidc/providers')
+}
@@ -0,0 +1,37 @@
+import request from '@/utils/request'
+
+// 接口权限API
+
+export interface PermissionRoute {
+  method: string
+  path: string
+}
+
+export interface PermissionHolder {
+  roleId: number
+  roleName: string
+  roleCode: string
+}
+
+export interface PermissionInfo {
+  code: string
+  name: string
+  menuCode: string
+  menuId: number
+  routes: PermissionRoute[]
+  roles: PermissionHolder[]
+}
+
+/**
+ * 获取全部接口权限及持有角色（仅管理员）
+ */
+export const getPermissions = () => {
+  return request.get('/api/v1/permissions')
+}
+
+/**
+ * 获取当前用户拥有的权限编码
+ */
+export const getMyPermissions = () => {
+  return request.get('/api/v1/profile/permissions')
+}
@@ -39,3 +39,8 @@ export const getRoleMenus = (id: number) => {
 export const assignRoleMenus = (id: number, menuIds: number[]) => {
   return request.post(`/api/v1/roles/${id}/menus`, { menuIds })
 }
+
+// 设置角色是否要求双因素认证
+export const setRoleMfa = (id: number, required: boolean) => {
+  return request.put(`/api/v1/roles/${id}/mfa`, { required })
+}
@@ -173,6 +173,48 @@ export const deleteAnsibleTask = (id: number) => {
   return request.delete<any, any>(`/api/v1/plugins/task/ansible/${id}`)
 }
 
+export interface RunAnsibleTaskRequest {
+  hostIds?: number[]
+  groupIds?: number[]
+  tags?: string
+  limit?: string
+  extraVars?: Record<string, any>
+  check?: boolean
+}
+
+export const runAnsibleTask = (id: number, data: RunAnsibleTaskRequest = {}) => {
+  return request.post<any, { jobTaskId: number; status: string }>(`/api/v1/plugins/task/ansible/${id}/run`, data)
+}
+
+export const cancelAnsibleRun = (jobId: number) => {
+  return request.post<any, any>(`/api/v1/plugins/task/ansible/runs/${jobId}/cancel`)
+}
+
+// 实时读取执行输出，执行结束后 Promise 完成；通过 signal 中止读取
+export const streamAnsibleRunLog = async (
+  jobId: number,
+  onChunk: (text: string) => void,
+  signal?: AbortSignal,
+  offset = 0
+) => {
+  const token = localStorage.getItem('token')
+  const res = await fetch(`/api/v1/plugins/task/ansible/runs/${jobId}/log?offset=${offset}`, {
+    headers: token ? { Authorization: `Bearer ${token}` } : {},
+    signal
+  })
+  if (!res.ok || !res.body) {
+    throw new Error('获取执行输出失败')
+  }
+  const reader = res.body.getReader()
+  const decoder = new TextDecoder()
+  while (true) {
+    const { done, value } = await reader.read()
+    if (done) break
+    onChunk(decoder.decode(value, { stream: true }))
+  }
+  onChunk(decoder.decode())
+}
+
 // ==================== 执行记录 ====================
 
 export interface ExecutionHistory {
@@ -184,6 +226,7 @@ export interface ExecutionHistory {
   parameters?: string
   result?: string
   errorMessage?: string
+  output?: string
   createdBy: number
   createdByName?: string
   createdAt: string
@@ -220,6 +263,82 @@ export const exportExecutionHistory = (ids?: number[]) => {
   return request.post<any, any>('/api/v1/plugins/task/execution-history/export', { ids })
 }
 
+// ==================== 定时任务 ====================
+
+export interface JobSchedule {
+  id: number
+  name: string
+  description?: string
+  cronExpr: string
+  timezone?: string
+  templateId: number
+  templateName?: string
+  scriptType: string // Shell, Python
+  variables?: string // JSON对象，模板变量取值
+  targetType: string // hosts, group
+  targetHosts?: string // JSON主机ID数组
+  groupId?: number
+  overlapPolicy: string // skip, queue, allow
+  enabled: boolean
+  notifyOnFailure: boolean
+  notifyUserIds?: string // JSON用户ID数组
+  lastRunTime?: string
+  lastRunStatus?: string
+  nextRunTime?: string
+  createdBy: number
+  createdAt: string
+  updatedAt: string
+}
+
+export interface JobScheduleListParams {
+  page?: number
+  pageSize?: number
+  keyword?: string
+  enabled?: boolean
+}
+
+export const getJobScheduleList = (params: JobScheduleListParams) => {
+  return request.get<any, any>('/api/v1/plugins/task/schedules', { params })
+}
+
+export const getJobScheduleDetail = (id: number) => {
+  return request.get<any, JobSchedule>(`/api/v1/plugins/task/schedules/${id}`)
+}
+
+export const createJobSchedule = (data: any) => {
+  return request.post<any, any>('/api/v1/plugins/task/schedules', data)
+}
+
+export const updateJobSchedule = (id: number, data: any) => {
+  return request.put<any, any>(`/api/v1/plugins/task/schedules/${id}`, data)
+}
+
+export const deleteJobSchedule = (id: number) => {
+  return request.delete<any, any>(`/api/v1/plugins/task/schedules/${id}`)
+}
+
+export const enableJobSchedule = (id: number) => {
+  return request.post<any, any>(`/api/v1/plugins/task/schedules/${id}/enable`)
+}
+
+export const pauseJobSchedule = (id: number) => {
+  return request.post<any, any>(`/api/v1/plugins/task/schedules/${id}/pause`)
+}
+
+export const runJobSchedule = (id: number) => {
+  return request.post<any, any>(`/api/v1/plugins/task/schedules/${id}/run`)
+}
+
+export const getJobScheduleRuns = (id: number, params: { page?: number; pageSize?: number; status?: string }) => {
+  return request.get<any, any>(`/api/v1/plugins/task/schedules/${id}/runs`, { params })
+}
+
+export const previewJobSchedule = (cronExpr: string, timezone?: string, count = 5) => {
+  return request.get<any, string[]>('/api/v1/plugins/task/schedules/preview', {
+    params: { cronExpr, timezone, count }
+  })
+}
+
 // ==================== 文件分发 ====================
 
 export const distributeFiles = (formData: FormData) => {
@@ -22,9 +22,50 @@ export const playTerminalSession = (id: number) => {
   })
 }
 
+// 终端会话策略API
+
+export interface TerminalSessionPolicyData {
+  name: string
+  scopeType: 'role' | 'group'
+  scopeId: number
+  idleTimeout?: number
+  idleWarning?: number
+  maxDuration?: number
+  maxConcurrent?: number
+  allowedWindows?: string
+  allowedWeekdays?: string
+  enabled?: boolean
+  description?: string
+}
+
+/**
+ * 获取终端会话策略列表
+ */
+export const getTerminalPolicies = (params: {
+  page: number
+  pageSize: number
+  scopeType?: string
+}) => {
+  return request.get('/api/v1/terminal-policies', { params })
+}
+
+/**
+ * 创建终端会话策略
+ */
+export const createTerminalPolicy = (data: TerminalSessionPolicyData) => {
+  return request.post('/api/v1/terminal-policies', data)
+}
+
+/**
+ * 更新终端会话策略
+ */
+export const updateTerminalPolicy = (id: number, data: TerminalSessionPolicyData) => {
+  return request.put(`/api/v1/terminal-policies/${id}`, data)
+}
+
 /**
- * 删除终端会话
+ * 删除终端会话策略
  */
-export const deleteTerminalSession = (id: number) => {
-  return request.delete(`/api/v1/terminal-sessions/${id}`)
+export const deleteTerminalPolicy = (id: number) => {
+  return request.delete(`/api/v1/terminal-policies/${id}`)
 }
@@ -44,3 +44,94 @@ export const resetUserPassword = (id: number, password: string) => {
 export const changePassword = (oldPassword: string, newPassword: string) => {
   return request.put('/api/v1/profile/password', { oldPassword, newPassword })
 }
+
+// 重置用户的双因素认证（认证器丢失时由管理员操作）
+export const resetUserMfa = (id: number) => {
+  return request.delete(`/api/v1/users/${id}/mfa`)
+}
+
+// 解除登录失败锁定
+export const unlockUser = (id: number) => {
+  return request.put(`/api/v1/users/${id}/unlock`)
+}
+
+// 获取当前用户的双因素认证状态
+export const getMfaStatus = () => {
+  return request.get('/api/v1/profile/mfa')
+}
+
+// 生成认证器密钥和二维码
+export const beginMfaEnroll = () => {
+  return request.post('/api/v1/profile/mfa/enroll')
+}
+
+// 确认绑定认证器，返回恢复码
+export const confirmMfaEnroll = (code: string) => {
+  return request.post('/api/v1/profile/mfa/confirm', { code })
+}
+
+// 重新生成恢复码
+export const regenerateRecoveryCodes = (code: string) => {
+  return request.post('/api/v1/profile/mfa/recovery-codes', { code })
+}
+
+// 关闭双因素认证
+export const disableMfa = (code: string) => {
+  return request.delete('/api/v1/profile/mfa', { data: { code } })
+}
+
+// 获取 LDAP 状态和最近一次同步结果
+export const getLdapStatus = () => {
+  return request.get('/api/v1/ldap/status')
+}
+
+// 手动同步 LDAP 用户
+export const syncLdapUsers = () => {
+  return request.post('/api/v1/ldap/sync')
+}
+
+// 获取当前用户的登录会话
+export const getMySessions = () => {
+  return request.get('/api/v1/profile/sessions')
+}
+
+// 注销当前用户的指定会话
+export const revokeMySession = (id: string) => {
+  return request.delete(`/api/v1/profile/sessions/${id}`)
+}
+
+// 注销当前用户的全部会话，keepCurrent 为 true 时保留当前会话
+export const revokeMySessions = (keepCurrent = true) => {
+  return request.delete('/api/v1/profile/sessions', { params: { keepCurrent } })
+}
+
+// 获取用户的登录会话（管理员）
+export const getUserSessions = (id: number) => {
+  return request.get(`/api/v1/users/${id}/sessions`)
+}
+
+// 强制下线用户的全部会话（管理员）
+export const revokeUserSessions = (id: number) => {
+  return request.delete(`/api/v1/users/${id}/sessions`)
+}
+
+// 下载用户导入模板
+export const downloadUserImportTemplate = () => {
+  return request.get('/api/v1/users/import/template', { responseType: 'blob' })
+}
+
+// 批量导入用户，dryRun 只校验不写入，generatePassword 为未填写密码的用户生成初始密码
+export const importUsers = (file: File, options: { dryRun?: boolean; generatePassword?: boolean } = {}) => {
+  const formData = new FormData()
+  formData.append('file', file)
+  formData.append('dryRun', String(!!options.dryRun))
+  formData.append('generatePassword', String(!!options.generatePassword))
+  return request.post('/api/v1/users/import', formData, {
+    headers: { 'Content-Type': 'multipart/form-data' }
+  })
+}
+
+// 导出用户及其部门、角色和岗位
+export const exportUsers = (params: { format?: 'xlsx' | 'csv'; keyword?: string; departmentId?: number } = {}) => {
+  return request.get('/api/v1/users/export', { params, responseType: 'blob' })
+}
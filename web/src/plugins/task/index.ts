@@ -70,6 +70,14 @@ class TaskPlugin implements Plugin {
         hidden: false,
         parentPath: parentPath,
       },
+      {
+        name: '定时任务',
+        path: '/task/schedules',
+        icon: 'Timer',
+        sort: 5,
+        hidden: false,
+        parentPath: parentPath,
+      },
     ]
   }
 
@@ -108,6 +116,12 @@ class TaskPlugin implements Plugin {
             component: () => import('@/views/task/ExecutionHistory.vue'),
             meta: { title: '执行记录' },
           },
+          {
+            path: 'schedules',
+            name: 'TaskSchedules',
+            component: () => import('@/views/task/Schedules.vue'),
+            meta: { title: '定时任务' },
+          },
         ],
       },
     ]
@@ -77,6 +77,24 @@ const router = createRouter({
           component: () => import('@/views/audit/DataLogs.vue'),
           meta: { title: '数据日志' }
         },
+        {
+          path: 'audit/retention',
+          name: 'AuditRetention',
+          component: () => import('@/views/audit/Retention.vue'),
+          meta: { title: '日志归档' }
+        },
+        {
+          path: 'audit/security-events',
+          name: 'SecurityEvents',
+          component: () => import('@/views/audit/SecurityEvents.vue'),
+          meta: { title: '安全事件' }
+        },
+        {
+          path: 'audit/timeline',
+          name: 'AuditTimeline',
+          component: () => import('@/views/audit/Timeline.vue'),
+          meta: { title: '活动时间线' }
+        },
         {
           path: 'asset/hosts',
           name: 'AssetHosts',
@@ -1,5 +1,5 @@
 import { defineStore } from 'pinia'
-import { login, register, getProfile } from '@/api/auth'
+import { login, loginMfa, loginChangePassword, logout, register, getProfile } from '@/api/auth'
 import type { LoginParams, RegisterParams } from '@/api/auth'
 
 interface UserState {
@@ -23,12 +23,45 @@ export const useUserStore = defineStore('user', {
     // 登录
     async login(params: LoginParams) {
       const res = await login(params)
-      this.token = res.token
+      // 需要双因素认证或修改密码时由登录页继续下一步
+      if (res.mfaRequired || res.passwordChangeRequired) {
+        return res
+      }
+      this.setTokens(res.token, res.refreshToken)
       this.userInfo = res.user
-      localStorage.setItem('token', res.token)
       return res
     },
 
+    // 双因素登录第二步
+    async loginMfa(mfaToken: string, code: string) {
+      const res = await loginMfa(mfaToken, code)
+      if (res.passwordChangeRequired) {
+        return res
+      }
+      this.setTokens(res.token, res.refreshToken)
+      this.userInfo = res.user
+      return res
+    },
+
+    // 登录时修改密码
+    async loginChangePassword(passwordToken: string, newPassword: string) {
+      const res = await loginChangePassword(passwordToken, newPassword)
+      this.setTokens(res.token, res.refreshToken)
+      this.userInfo = res.user
+      return res
+    },
+
+    // 保存访问令牌和刷新令牌
+    setTokens(token: string, refreshToken?: string) {
+      this.token = token
+      localStorage.setItem('token', token)
+      if (refreshToken) {
+        localStorage.setItem('refreshToken', refreshToken)
+      } else {
+        localStorage.removeItem('refreshToken')
+      }
+    },
+
     // 注册
     async register(params: RegisterParams) {
       const res = await register(params)
@@ -44,11 +77,24 @@ export const useUserStore = defineStore('user', {
       return res
     },
 
-    // 退出登录
+    // 清除本地登录状态
     logout() {
       this.token = ''
       this.userInfo = null
       localStorage.removeItem('token')
+      localStorage.removeItem('refreshToken')
+    },
+
+    // 退出登录：先注销服务端会话，失败不影响本地退出
+    async signOut() {
+      if (this.token) {
+        try {
+          await logout()
+        } catch (e) {
+          // 会话可能已失效，忽略
+        }
+      }
+      this.logout()
     },
 
     // 更新头像
@@ -6,7 +6,9 @@ export const PERMISSION = {
   TERMINAL: 1 << 3, // 8 - 终端
   FILE: 1 << 4,     // 16 - 文件管理
   COLLECT: 1 << 5,  // 32 - 采集信息
-  ALL: 0x3F,        // 63 - 所有权限
+  PORT_FORWARD: 1 << 6, // 64 - 端口转发
+  DB_WRITE: 1 << 7, // 128 - 数据库写入
+  ALL: 0xFF,        // 255 - 所有权限
 } as const
 
 /**
@@ -38,6 +40,10 @@ export function getPermissionName(permission: number): string {
       return '文件管理'
     case PERMISSION.COLLECT:
       return '采集信息'
+    case PERMISSION.PORT_FORWARD:
+      return '端口转发'
+    case PERMISSION.DB_WRITE:
+      return '数据库写入'
     default:
       return '未知'
   }
@@ -56,6 +62,8 @@ export function getPermissionNames(permissions: number): string[] {
   if ((permissions & PERMISSION.TERMINAL) > 0) names.push('终端')
   if ((permissions & PERMISSION.FILE) > 0) names.push('文件管理')
   if ((permissions & PERMISSION.COLLECT) > 0) names.push('采集信息')
+  if ((permissions & PERMISSION.PORT_FORWARD) > 0) names.push('端口转发')
+  if ((permissions & PERMISSION.DB_WRITE) > 0) names.push('数据库写入')
   return names
 }
 
@@ -86,6 +94,12 @@ export function permissionNamesToMask(permissionNames: string[]): number {
       case '采集信息':
         mask |= PERMISSION.COLLECT
         break
+      case '端口转发':
+        mask |= PERMISSION.PORT_FORWARD
+        break
+      case '数据库写入':
+        mask |= PERMISSION.DB_WRITE
+        break
     }
   }
   return mask
@@ -121,4 +135,6 @@ export const PERMISSION_OPTIONS = [
   { label: '连接终端', value: PERMISSION.TERMINAL, description: 'SSH连接到主机' },
   { label: '文件管理', value: PERMISSION.FILE, description: '文件上传、下载、删除' },
   { label: '采集信息', value: PERMISSION.COLLECT, description: '采集主机系统信息' },
+  { label: '端口转发', value: PERMISSION.PORT_FORWARD, description: '通过平台建立到主机的端口转发隧道' },
+  { label: '数据库写入', value: PERMISSION.DB_WRITE, description: '在数据库控制台执行写入语句，未授权时为只读' },
 ]
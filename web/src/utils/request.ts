@@ -9,6 +9,52 @@ const request = axios.create({
 // Token过期跳转标志，防止重复跳转
 let isRedirecting = false
 
+// 正在进行的令牌刷新，并发请求共用同一次刷新
+let refreshing: Promise<string> | null = null
+
+// 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+const refreshAccessToken = (): Promise<string> => {
+  if (refreshing) return refreshing
+  const refreshToken = localStorage.getItem('refreshToken')
+  refreshing = (async () => {
+    if (!refreshToken) throw new Error('no refresh token')
+    const { data: res } = await axios.post('/api/v1/public/token/refresh', { refreshToken })
+    if (res.code === 0 || res.code === 200) {
+      localStorage.setItem('token', res.data.token)
+      localStorage.setItem('refreshToken', res.data.refreshToken)
+      return res.data.token as string
+    }
+    // 其他标签页刚完成刷新，直接使用它保存的新令牌
+    if (res.code === 409 && localStorage.getItem('refreshToken') !== refreshToken) {
+      return localStorage.getItem('token') as string
+    }
+    throw new Error(res.message || 'refresh failed')
+  })().finally(() => {
+    refreshing = null
+  })
+  return refreshing
+}
+
+// 登录失效，清除令牌并跳转到登录页
+const redirectToLogin = (response: any, res: any) => {
+  // 避免重复跳转
+  if (!isRedirecting) {
+    isRedirecting = true
+    ElMessage.error('登录已过期，请重新登录')
+    localStorage.removeItem('token')
+    localStorage.removeItem('refreshToken')
+    // 延迟跳转，让用户看到提示
+    setTimeout(() => {
+      window.location.href = '/login'
+    }, 1000)
+  }
+  return Promise.reject({
+    code: res.code,
+    message: res.message || '请求失败',
+    response: response
+  })
+}
+
 // 请求拦截器
 request.interceptors.request.use(
   (config) => {
@@ -50,21 +96,19 @@ request.interceptors.response.use(
         res.message.includes('未登录') ||
         res.message.includes('登录已过期')
       )) {
-        // 避免重复跳转
-        if (!isRedirecting) {
-          isRedirecting = true
-          ElMessage.error('登录已过期，请重新登录')
-          localStorage.removeItem('token')
-          // 延迟跳转，让用户看到提示
-          setTimeout(() => {
-            window.location.href = '/login'
-          }, 1000)
+        // 访问令牌过期时先尝试刷新，成功后重放原请求
+        const config: any = response.config
+        if (res.code === 401 && !config._retried && !url.includes('/public/')) {
+          config._retried = true
+          return refreshAccessToken().then(
+            (token) => {
+              config.headers.Authorization = `Bearer ${token}`
+              return request(config)
+            },
+            () => redirectToLogin(response, res)
+          )
         }
-        return Promise.reject({
-          code: res.code,
-          message: res.message || '请求失败',
-          response: response
-        })
+        return redirectToLogin(response, res)
       }
 
       // 只在非登录接口的情况下自动显示错误消息
@@ -93,6 +137,7 @@ request.interceptors.response.use(
         isRedirecting = true
         ElMessage.error('登录已过期，请重新登录')
         localStorage.removeItem('token')
+        localStorage.removeItem('refreshToken')
         setTimeout(() => {
           window.location.href = '/login'
         }, 1000)